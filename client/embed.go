@@ -2,14 +2,78 @@
 package client
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"io/fs"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 //go:embed src/*.js
 var assets embed.FS
 
+//go:embed types/golivekit.d.ts
+var typings []byte
+
+// Prefix is the path Handler is conventionally mounted under:
+//
+//	r.Handle(client.Prefix, http.StripPrefix(client.Prefix, client.Handler()))
+const Prefix = "/_live/"
+
+// clientScript is the main client file, stamped with its version when served.
+// It is a stable alias redirecting to the versioned ScriptName.
+const clientScript = "golivekit.js"
+
+// versionPlaceholder is replaced by Version in the served client script.
+const versionPlaceholder = "__GOLIVEKIT_VERSION__"
+
+var (
+	versionOnce sync.Once
+	version     string
+	stamped     []byte
+)
+
+// Version returns the build hash of the embedded client script. The client
+// sends it on join so the router can detect version skew after a deploy.
+func Version() string {
+	versionOnce.Do(func() {
+		data, err := assets.ReadFile("src/" + clientScript)
+		if err != nil {
+			panic(err)
+		}
+		sum := sha256.Sum256(data)
+		version = hex.EncodeToString(sum[:6])
+		stamped = bytes.Replace(data, []byte(versionPlaceholder), []byte(version), 1)
+	})
+	return version
+}
+
+// ScriptName returns the versioned file name of the client script,
+// golivekit-<Version>.js. Handler serves it with a far-future cache lifetime.
+func ScriptName() string {
+	return "golivekit-" + Version() + ".js"
+}
+
+// ScriptPath returns the URL of the versioned client script under Prefix.
+func ScriptPath() string {
+	return Prefix + ScriptName()
+}
+
+// ScriptTag returns a script element loading the versioned client script.
+func ScriptTag() string {
+	return `<script src="` + ScriptPath() + `"></script>`
+}
+
+// Types returns the TypeScript definitions of the client API
+// (golivekit.d.ts), for editor support in apps writing hooks.
+func Types() []byte {
+	return bytes.Clone(typings)
+}
+
 // Assets returns the embedded filesystem containing JavaScript files.
 func Assets() fs.FS {
 	fsys, err := fs.Sub(assets, "src")
@@ -19,23 +83,62 @@ func Assets() fs.FS {
 	return fsys
 }
 
-// Handler returns an HTTP handler that serves the embedded assets.
+// Handler returns an HTTP handler that serves the embedded assets. The
+// client script is stamped with Version and served under ScriptName as an
+// immutable asset. golivekit.js, and the versioned names of other builds,
+// redirect to the current ScriptName, so pages rendered before a deploy pick
+// up the new client.
 func Handler() http.Handler {
-	return http.FileServer(http.FS(Assets()))
+	files := http.FileServer(http.FS(Assets()))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		switch {
+		case name == ScriptName():
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			w.Header().Set("ETag", `"`+Version()+`"`)
+			http.ServeContent(w, r, clientScript, time.Time{}, bytes.NewReader(stamped))
+		case name == clientScript || isScriptVersion(name):
+			// Relative to the request, so it works under any mount prefix
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Location", ScriptName())
+			w.WriteHeader(http.StatusFound)
+		default:
+			files.ServeHTTP(w, r)
+		}
+	})
+}
+
+// isScriptVersion reports whether name is a versioned client script name.
+func isScriptVersion(name string) bool {
+	hash, ok := strings.CutPrefix(name, "golivekit-")
+	if !ok {
+		return false
+	}
+	hash, ok = strings.CutSuffix(hash, ".js")
+	if !ok || hash == "" {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
 }
 
 // MustGetFile returns the contents of an embedded file.
 // Panics if the file doesn't exist.
 func MustGetFile(name string) []byte {
-	data, err := assets.ReadFile("src/" + name)
+	data, err := GetFile(name)
 	if err != nil {
 		panic(err)
 	}
 	return data
 }
 
-// GetFile returns the contents of an embedded file.
+// GetFile returns the contents of an embedded file. The client script is
+// returned stamped with Version.
 func GetFile(name string) ([]byte, error) {
+	if name == clientScript {
+		Version()
+		return bytes.Clone(stamped), nil
+	}
 	return assets.ReadFile("src/" + name)
 }
 
@@ -0,0 +1,119 @@
+/**
+ * GoliveKit Bridge - mount Live routes in React and Vue apps
+ *
+ * Wraps <golive-view> (golivekit.js, which must be loaded first) so a SPA
+ * can migrate one page at a time to server-driven UI. Props go to the
+ * server as "lv:props" events; events the server pushes come back through
+ * onEvent.
+ *
+ *   const GoliveView = GoliveBridge.react(React);
+ *   <GoliveView route="/live/invoice" props={{ id }} onEvent={(event, payload) => ...} />
+ */
+
+(function () {
+    // Mount a Live route into el. Returns a handle to update props, push
+    // events and unmount.
+    function mount(el, options = {}) {
+        const view = document.createElement('golive-view');
+        if (options.route) view.setAttribute('route', options.route);
+        if (options.socket) view.setAttribute('socket', options.socket);
+        // lazy: connect once scrolled into view
+        if (!options.lazy) view.setAttribute('eager', '');
+        view.props = options.props || null;
+
+        const onEvent = (e) => {
+            if (options.onEvent) options.onEvent(e.detail.event, e.detail.payload, e.detail.binary);
+        };
+        view.addEventListener('golive:event', onEvent);
+        el.appendChild(view);
+
+        return {
+            element: view,
+            setProps(props) {
+                view.props = props;
+                return view.liveView ? view.liveView.setProps(props) : Promise.resolve();
+            },
+            pushEvent(event, payload = {}) {
+                return view.liveView ? view.liveView.pushEvent(event, payload) : Promise.resolve();
+            },
+            destroy() {
+                view.removeEventListener('golive:event', onEvent);
+                view.remove();
+            }
+        };
+    }
+
+    // React component: <GoliveView route socket props onEvent lazy className style />.
+    // Changing route or socket remounts the view; props changes are sent.
+    function react(React) {
+        return function GoliveView({ route, socket, props, onEvent, lazy, className, style }) {
+            const ref = React.useRef(null);
+            const handle = React.useRef(null);
+            const latest = React.useRef({});
+            latest.current = { props, onEvent };
+
+            React.useEffect(() => {
+                handle.current = mount(ref.current, {
+                    route, socket, lazy,
+                    props: latest.current.props,
+                    onEvent: (...args) => latest.current.onEvent && latest.current.onEvent(...args)
+                });
+                return () => {
+                    handle.current.destroy();
+                    handle.current = null;
+                };
+            }, [route, socket]);
+
+            const propsKey = JSON.stringify(props || null);
+            const sent = React.useRef(propsKey);
+            React.useEffect(() => {
+                if (!handle.current || sent.current === propsKey) return;
+                sent.current = propsKey;
+                handle.current.setProps(props);
+            }, [propsKey]);
+
+            return React.createElement('div', { ref, className, style });
+        };
+    }
+
+    // Vue 3 component: <GoliveView route socket :props @event lazy />.
+    // Exposes pushEvent(event, payload) through a template ref.
+    function vue(Vue) {
+        return Vue.defineComponent({
+            name: 'GoliveView',
+            props: { route: String, socket: String, props: Object, lazy: Boolean },
+            emits: ['event'],
+            setup(p, { emit, expose }) {
+                const el = Vue.ref(null);
+                let handle = null;
+
+                const start = () => {
+                    handle = mount(el.value, {
+                        route: p.route, socket: p.socket, lazy: p.lazy, props: p.props,
+                        onEvent: (event, payload, binary) => emit('event', event, payload, binary)
+                    });
+                };
+                const stop = () => {
+                    if (handle) handle.destroy();
+                    handle = null;
+                };
+
+                Vue.onMounted(start);
+                Vue.onBeforeUnmount(stop);
+                Vue.watch(() => [p.route, p.socket], () => { stop(); start(); });
+                Vue.watch(() => p.props, (props) => handle && handle.setProps(props), { deep: true });
+
+                expose({ pushEvent: (event, payload) => handle ? handle.pushEvent(event, payload) : Promise.resolve() });
+                return () => Vue.h('div', { ref: el });
+            }
+        });
+    }
+
+    const GoliveBridge = { mount, react, vue };
+    window.GoliveBridge = GoliveBridge;
+
+    // Export for module systems
+    if (typeof module !== 'undefined' && module.exports) {
+        module.exports = GoliveBridge;
+    }
+})();
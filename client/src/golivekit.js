@@ -3,6 +3,11 @@
  * Handles WebSocket connections, DOM updates, and event handling.
  */
 
+// Client build version, stamped by the server when it serves this file
+// (client.Version). Sent on join so a deploy can be detected.
+const GOLIVEKIT_VERSION = '__GOLIVEKIT_VERSION__';
+const GOLIVEKIT_SCRIPT = document.currentScript ? document.currentScript.src : null;
+
 class GoliveKit {
     constructor(options = {}) {
         this.options = {
@@ -13,10 +18,22 @@ class GoliveKit {
             reconnectMaxDelay: 30000,
             optimisticUpdates: true,
             eventDebounce: 16, // ~1 frame, prevents double-clicks but allows fast navigation
+            root: null, // element the view renders into (<golive-view>), null for the page
+            props: null, // host framework props, sent on join and by setProps (lv:props)
+            transport: 'auto', // 'websocket', 'sse', or 'auto': SSE when WebSockets are blocked
+            sseFallbackTimeout: 5000, // ms to wait for a WebSocket before falling back
+            batchEvents: true, // events fired within one animation frame share one message (lv:batch)
+            tabSync: false, // relay lv:shared values to the other tabs over a BroadcastChannel
             ...options
         };
 
+        // DOM scope: lookups and event delegation stay inside the root
+        this.root = this.options.root || document;
+        // The socket URL follows the page URL across push_patch
+        this._urlFromLocation = !options.url;
+
         this.socket = null;
+        this._sse = this.options.transport === 'sse';
         this.connected = false;
         this.joined = false;
         this.connecting = false;
@@ -26,15 +43,54 @@ class GoliveKit {
         this.lastV = 0; // Version for diff ordering
         this.pendingReplies = new Map();
         this.hooks = new Map();
+        this._hooked = new Map(); // hooked element -> its hook instance
+        this._hooksDown = false; // hooks were told of a lost connection
         this.eventListeners = new Map();
         this.heartbeatTimer = null;
         this.reconnectTimer = null;
         this.topic = null;
+        this._sessionToken = null; // Sent on rejoin to recover the server state
+
+        // Per-frame input batching (game loops)
+        this._inputQueue = [];
+        this._inputFrame = 0;
+        this._inputScheduled = false;
+
+        // User events waiting for the next frame (lv:batch)
+        this._eventBatch = [];
+        this._batchScheduled = false;
+
+        // Active sensor bindings (lv-geolocation, lv-visibility, lv-resize)
+        this._sensors = new Map();
+        this._receipts = { delivered: new Set(), read: new Set(), queue: { delivered: [], read: [] }, timer: null, observer: null };
 
         // Optimistic UI state
         this.pendingOptimistic = new Map();
         this._lastEvents = new Map();
 
+        // Streamed slots being reassembled, and the diff version each
+        // slot was last updated at (so stale streams are dropped)
+        this._streams = new Map();
+        this._slotVersions = new Map();
+
+        // Statics and dynamics of the slots the server sends as trees
+        this._trees = new Map();
+
+        // Last full render, the base of the deltas of the next one
+        this._fullBase = null;
+
+        // Text of the slots the server may send as splices
+        this._slotTexts = new Map();
+
+        // Key of the current history entry, under which its UI state is kept
+        this._entryKey = null;
+
+        // State shared across tabs (lv:shared), and the channel relaying it
+        this._shared = new Map();
+        this._tabs = null;
+        this._tabGroup = null;
+        if (this.options.tabSync) this._openTabChannel();
+
         this._onOpen = this._onOpen.bind(this);
         this._onClose = this._onClose.bind(this);
         this._onError = this._onError.bind(this);
@@ -64,6 +120,13 @@ class GoliveKit {
                 opacity: 0.7;
             }
 
+            /* Grabación en curso (lv-record) */
+            [lv-record].lv-recording {
+                color: #dc2626;
+                animation: lv-pulse 1s ease-in-out infinite;
+            }
+            @keyframes lv-pulse { 50% { opacity: 0.6; } }
+
             /* Transición suave en slots */
             [data-slot] {
                 transition: opacity 0.12s ease;
@@ -165,6 +228,22 @@ class GoliveKit {
         return `${protocol}//${location.host}${location.pathname}${location.search}`;
     }
 
+    // Whether el belongs to this view: elements inside a <golive-view> are
+    // handled by that element's own instance.
+    _owns(el) {
+        return el.closest('golive-view') === this.options.root;
+    }
+
+    // Elements under the root that belong to this view.
+    _select(selector) {
+        return Array.from(this.root.querySelectorAll(selector)).filter(el => this._owns(el));
+    }
+
+    // Element full renders replace the children of.
+    _container() {
+        return this.options.root || document.querySelector('[data-live-view]');
+    }
+
     connect() {
         if (this.socket && this.connected) return Promise.resolve();
         if (this.connecting) return Promise.resolve();
@@ -173,11 +252,28 @@ class GoliveKit {
 
         return new Promise((resolve) => {
             try {
-                this.socket = new WebSocket(this.options.url);
-                this.socket.onopen = () => { this._onOpen(); resolve(); };
-                this.socket.onclose = this._onClose;
-                this.socket.onerror = this._onError;
-                this.socket.onmessage = this._onMessage;
+                const socket = this._sse ? this._openSSE() : new WebSocket(this.options.url);
+                let opened = false;
+                this.socket = socket;
+                socket.binaryType = 'arraybuffer';
+                socket.onopen = () => { opened = true; this._onOpen(); resolve(); };
+                socket.onclose = (event) => {
+                    // A WebSocket that never opened is likely blocked by a
+                    // proxy: connect again over SSE
+                    if (!opened && this.socket === socket && this._fallbackToSSE()) {
+                        this.connecting = false;
+                        this.connect().then(resolve);
+                        return;
+                    }
+                    this._onClose(event);
+                };
+                socket.onerror = this._onError;
+                socket.onmessage = this._onMessage;
+                // Some proxies hold the upgrade request instead of refusing it
+                if (!this._sse && this.options.transport === 'auto') {
+                    setTimeout(() => { if (!opened && this.socket === socket) socket.close(); },
+                        this.options.sseFallbackTimeout);
+                }
             } catch (e) {
                 this.connecting = false;
                 resolve();
@@ -185,9 +281,87 @@ class GoliveKit {
         });
     }
 
+    _fallbackToSSE() {
+        if (this._sse || this.options.transport !== 'auto' || typeof EventSource === 'undefined') return false;
+        this._sse = true;
+        return true;
+    }
+
+    // SSE transport: server messages arrive on an EventSource, client
+    // messages are POSTed to the same path with the stream's client_id.
+    // Returns an object with the WebSocket members connect() and _send use.
+    _openSSE() {
+        const url = new URL(this.options.url, location.href);
+        url.protocol = url.protocol === 'wss:' ? 'https:' : url.protocol === 'ws:' ? 'http:' : url.protocol;
+        const source = new EventSource(url.href, { withCredentials: true });
+        const postURL = new URL(url.href);
+        let queue = [];
+        let posting = false;
+        let closed = false;
+
+        const socket = {
+            onopen: null, onclose: null, onerror: null, onmessage: null,
+            send(data) {
+                queue.push(data);
+                flush();
+            },
+            close(code = 1000) {
+                if (closed) return;
+                closed = true;
+                source.close();
+                setTimeout(() => socket.onclose && socket.onclose({ code }), 0);
+            }
+        };
+        // One POST at a time keeps messages in order; queued ones go together
+        const flush = () => {
+            if (posting || closed || !queue.length || !postURL.searchParams.has('lv_sse')) return;
+            posting = true;
+            const body = queue.join('\n') + '\n';
+            queue = [];
+            fetch(postURL.href, {
+                method: 'POST', body, credentials: 'include',
+                headers: { 'Content-Type': 'text/plain' }
+            }).then((res) => {
+                posting = false;
+                if (res.ok) flush(); else socket.close(4000);
+            }).catch(() => {
+                posting = false;
+                socket.close(4000);
+            });
+        };
+
+        source.addEventListener('connected', (e) => {
+            postURL.searchParams.set('lv_sse', JSON.parse(e.data).payload.client_id);
+            if (socket.onopen) socket.onopen();
+            flush();
+        });
+        source.onmessage = (e) => socket.onmessage && socket.onmessage(e);
+        // The server session ends with the stream: reconnect as a new one
+        source.onerror = () => socket.close(1006);
+        return socket;
+    }
+
+    // Disconnect and remove the DOM listeners added by bindEvents.
+    destroy() {
+        this.disconnect();
+        if (this._listeners) this._listeners.abort();
+        this._listeners = null;
+        for (const stop of this._sensors.values()) stop();
+        this._sensors.clear();
+        this._callHooks('destroyed');
+        this._hooked.clear();
+        clearInterval(this._relativeTimer);
+        this._relativeTimer = null;
+        if (this._tabs) this._tabs.close();
+        this._tabs = null;
+    }
+
     disconnect() {
         this._clearTimers();
         if (this.socket) {
+            // Its close must not cancel a reconnect scheduled after this
+            this.socket.onclose = null;
+            this.socket.onmessage = null;
             try { this.socket.close(1000); } catch (e) {}
             this.socket = null;
         }
@@ -213,16 +387,39 @@ class GoliveKit {
         this.pendingReplies.set(ref, (payload) => {
             if (payload && payload.status === 'ok') {
                 this.joined = true;
-                this._callHooks('mounted');
+                const session = payload.response && payload.response.session;
+                if (session) this._sessionToken = session;
+                const rendered = payload.response && payload.response.rendered;
+                this._fullBase = rendered && rendered.s ? rendered.s[0] : null;
+                if (this._hooksDown) this._callHooks('reconnected');
+                this._hooksDown = false;
+                this._scanHooks();
+                if (document.hidden) this._reportVisibility();
+                this._scanSensors();
+                this._localizeTimes();
+                this._refreshRelativeTimes();
             }
         });
 
+        const payload = { join_ref: ref, timezone: this._timezone(), features: this._features(), client: this._clientInfo() };
+        if (this.options.props) payload.props = this.options.props;
+        if (this._tabGroup) payload.tab_group = this._tabGroup;
+        if (!GOLIVEKIT_VERSION.startsWith('__')) payload.vsn = GOLIVEKIT_VERSION;
+        // State of the connection this page lost, kept by the server for a
+        // while (LiveViewSessionManager.Park)
+        if (this._sessionToken) payload.session = this._sessionToken;
+        // Session carried over from a draining server (lv:reconnect)
+        if (this._recoveryToken) {
+            payload.recovery = this._recoveryToken;
+            this._recoveryToken = null;
+        }
+
         this._send({
             ref,
             join_ref: ref,
             topic: this.topic,
             event: 'phx_join',
-            payload: { join_ref: ref }
+            payload
         });
     }
 
@@ -230,11 +427,12 @@ class GoliveKit {
         this.connected = false;
         this.joined = false;
         this.connecting = false;
+        this._eventBatch = [];
         this._clearTimers();
         if (event.code !== 1000 && !this.reconnecting) {
             this._scheduleReconnect();
         }
-        this._callHooks('disconnected');
+        this._disconnectHooks();
     }
 
     _onError() {
@@ -243,11 +441,54 @@ class GoliveKit {
 
     _onMessage(event) {
         try {
-            const msg = JSON.parse(event.data);
+            const msg = event.data instanceof ArrayBuffer
+                ? this._decodeBinary(event.data)
+                : JSON.parse(event.data);
+            // SSE carries attachments base64-encoded
+            if (typeof msg.bin === 'string') {
+                msg.binary = this._fromBase64(msg.bin);
+                delete msg.bin;
+            }
             this._handleMessage(msg);
         } catch (e) {}
     }
 
+    // Binary frame: [uint32 header length][JSON header][attachment bytes]
+    _encodeBinary(msg, data) {
+        const header = new TextEncoder().encode(JSON.stringify(msg));
+        const bytes = data instanceof ArrayBuffer ? new Uint8Array(data)
+            : new Uint8Array(data.buffer, data.byteOffset, data.byteLength);
+        const frame = new Uint8Array(4 + header.length + bytes.length);
+        new DataView(frame.buffer).setUint32(0, header.length);
+        frame.set(header, 4);
+        frame.set(bytes, 4 + header.length);
+        return frame.buffer;
+    }
+
+    _toBase64(data) {
+        const bytes = data instanceof ArrayBuffer ? new Uint8Array(data)
+            : new Uint8Array(data.buffer, data.byteOffset, data.byteLength);
+        let s = '';
+        for (let i = 0; i < bytes.length; i += 0x8000) {
+            s += String.fromCharCode.apply(null, bytes.subarray(i, i + 0x8000));
+        }
+        return btoa(s);
+    }
+
+    _fromBase64(s) {
+        const bin = atob(s);
+        const bytes = new Uint8Array(bin.length);
+        for (let i = 0; i < bin.length; i++) bytes[i] = bin.charCodeAt(i);
+        return bytes.buffer;
+    }
+
+    _decodeBinary(buf) {
+        const len = new DataView(buf).getUint32(0);
+        const msg = JSON.parse(new TextDecoder().decode(new Uint8Array(buf, 4, len)));
+        msg.binary = buf.slice(4 + len);
+        return msg;
+    }
+
     _scheduleReconnect() {
         if (this.reconnectAttempts >= this.options.reconnectMaxAttempts) return;
 
@@ -264,6 +505,77 @@ class GoliveKit {
         }, delay);
     }
 
+    // Rolling deploy: the server is draining. Reconnect after a random delay
+    // in [min, max] ms so clients spread over the new instances, and rejoin
+    // with the recovery token to restore the session.
+    _reconnectForDeploy(p) {
+        const min = Math.max(0, p.min || 0);
+        const max = Math.max(min, p.max || 0);
+        this.disconnect();
+        this._recoveryToken = p.token || null;
+        this.reconnectAttempts = 0;
+        this.reconnectTimer = setTimeout(() => {
+            this.reconnectTimer = null;
+            this.connect();
+        }, min + Math.random() * (max - min));
+        this._disconnectHooks();
+    }
+
+    // A newer tab of a single-session route took the session over
+    // (router.WithSingleSession). Show the page's [lv-takeover] notice, or a
+    // default one; its [lv-takeover-reclaim] button moves the session back.
+    _takenOver(p) {
+        this.disconnect();
+        this._disconnectHooks();
+        this._emit('lv:takeover', p);
+        if (p.reason !== 'tab') return;
+
+        let notices = this._select('[lv-takeover]');
+        if (!notices.length) {
+            const el = document.createElement('div');
+            el.setAttribute('lv-takeover', '');
+            el.setAttribute('role', 'alert');
+            el.className = 'lv-takeover';
+            el.textContent = 'This page is open in another tab. ';
+            const button = document.createElement('button');
+            button.type = 'button';
+            button.setAttribute('lv-takeover-reclaim', '');
+            button.textContent = 'Use here';
+            el.appendChild(button);
+            (this.options.root || document.body).appendChild(el);
+            notices = [el];
+        }
+        for (const el of notices) {
+            el.hidden = false;
+            for (const button of el.querySelectorAll('[lv-takeover-reclaim]')) {
+                button.onclick = () => {
+                    notices.forEach(n => { n.hidden = true; });
+                    this.connect();
+                };
+            }
+        }
+    }
+
+    // Version skew: the server runs a different client build. Refetch the
+    // script past the HTTP cache and reload, at most once per server version
+    // so a stale CDN copy cannot cause a reload loop.
+    _reloadForVersion(p) {
+        this.disconnect();
+        try {
+            if (sessionStorage.getItem('lv:reloaded-for') === p.version) {
+                console.warn('GoliveKit: client version mismatch persists after reload');
+                return;
+            }
+            sessionStorage.setItem('lv:reloaded-for', p.version);
+        } catch (e) {}
+        const reload = () => window.location.reload();
+        if (GOLIVEKIT_SCRIPT && window.fetch) {
+            fetch(GOLIVEKIT_SCRIPT, { cache: 'reload' }).then(reload, reload);
+        } else {
+            reload();
+        }
+    }
+
     _startHeartbeat() {
         this._stopHeartbeat();
         this.heartbeatTimer = setInterval(() => {
@@ -292,6 +604,8 @@ class GoliveKit {
         if (msg.ref && this.pendingReplies.has(msg.ref)) {
             const cb = this.pendingReplies.get(msg.ref);
             this.pendingReplies.delete(msg.ref);
+            const r = msg.payload && msg.payload.status === 'error' && msg.payload.response;
+            if (r && r.error) this._showUserError(r.error);
             try { cb(msg.payload); } catch (e) {}
             return;
         }
@@ -314,6 +628,237 @@ class GoliveKit {
                     this._revertOptimistic();
                 }
                 break;
+            case 'diff_chunk':
+                this._applyChunk(msg.payload || {});
+                break;
+            case 'lv:head':
+                // Embedded views leave the host page's head alone
+                if (!this.options.root) this._applyHead(msg.payload || {});
+                break;
+            case 'lv:badge':
+                if (!this.options.root) this._applyBadge(msg.payload || {});
+                break;
+            case 'lv:redirect':
+                // Full page navigation (e.g. admitted from a waiting room).
+                // Only same-origin paths are followed.
+                if (msg.payload && /^\/(?![\/\\])/.test(msg.payload.to || '')) {
+                    this.disconnect();
+                    if (msg.payload.replace) window.location.replace(msg.payload.to);
+                    else window.location.assign(msg.payload.to);
+                }
+                break;
+            case 'lv:navigate':
+                // push_navigate: move to another route, keeping this entry's UI state
+                if (!this.options.root && msg.payload) this._navigate(msg.payload.to);
+                break;
+            case 'lv:reload':
+                this._reloadForVersion(msg.payload || {});
+                break;
+            case 'lv:patch':
+                // Embedded views leave the host page's URL alone
+                if (!this.options.root) this._patchURL(msg.payload || {});
+                break;
+            case 'lv:reconnect':
+                this._reconnectForDeploy(msg.payload || {});
+                break;
+            case 'lv:takeover':
+                // Another connection took the session over (possibly on
+                // another node): reconnecting would take it back
+                this._takenOver(msg.payload || {});
+                break;
+            case 'lv:upload_presign':
+                this._uploadExternal(msg.payload || {});
+                break;
+            case 'lv:session':
+                // A socket cannot set cookies: post the session it wrote to
+                // the store's flush path (security.CookieSessionStore)
+                if (msg.payload && /^\/(?![\/\\])/.test(msg.payload.url || '')) {
+                    fetch(msg.payload.url, { method: 'POST', body: msg.payload.token, credentials: 'same-origin' }).catch(() => {});
+                }
+                break;
+            case 'lv:error':
+                // A user error of HandleInfo, which has no event to reply to
+                this._showUserError(msg.payload || {});
+                break;
+            case 'lv:push_to':
+                this._pushToHooks(msg.payload || {});
+                break;
+            case 'lv:shared':
+                this._applyShared(msg.payload || {}, true);
+                break;
+            case 'lv:consent':
+                // Persist the privacy consent decision (privacy.HandleConsentEvent)
+                if (msg.payload && msg.payload.cookie) document.cookie = msg.payload.cookie;
+                break;
+            default:
+                this._emit(msg.event, msg.payload || {}, msg.binary);
+                // Embedded views dispatch golive:event on their element instead
+                if (!this.options.root) {
+                    window.dispatchEvent(new CustomEvent('golive:' + msg.event, { detail: msg.payload || {} }));
+                }
+        }
+    }
+
+    // Socket.PushEventTo: deliver an event to the hooks of the elements
+    // matching a selector.
+    _pushToHooks(p) {
+        if (!p.to || !p.event) return;
+        for (const [el, hook] of this._hooked) {
+            let match = false;
+            try { match = el.matches(p.to); } catch (e) { return; }
+            if (match) hook._lvDispatch(p.event, p.payload || {});
+        }
+    }
+
+    // Show a user error (core.UserError): the message goes to the
+    // [lv-error-toast] element, field messages to [lv-error-for="name"]
+    // with the field marked invalid. Messages of the previous error are
+    // cleared. Listeners of "lv:error" get the error too.
+    _showUserError(err) {
+        const fields = err.fields || {};
+        for (const el of this._select('[lv-error-for]')) {
+            el.textContent = fields[el.getAttribute('lv-error-for')] || '';
+        }
+        for (const el of this._select('[aria-invalid="true"][name]')) {
+            if (!fields[el.name]) el.removeAttribute('aria-invalid');
+        }
+        for (const name of Object.keys(fields)) {
+            this._select(`[name="${CSS.escape(name)}"]`).forEach(el => el.setAttribute('aria-invalid', 'true'));
+        }
+        for (const el of this._select('[lv-error-toast]')) {
+            el.textContent = err.message || '';
+            el.hidden = !err.message;
+            clearTimeout(el._lvToastTimer);
+            const ms = parseInt(el.getAttribute('lv-error-toast'), 10);
+            if (err.message && ms > 0) el._lvToastTimer = setTimeout(() => { el.hidden = true; }, ms);
+        }
+        this._emit('lv:error', err);
+    }
+
+    // Apply a server-managed head update (Socket.UpdateHead / HeadProvider).
+    // Meta tags are matched by name or property, links by rel (+ hreflang);
+    // missing elements are created.
+    _applyHead(head) {
+        if (head.title) document.title = head.title;
+
+        for (const m of head.meta || []) {
+            const attr = m.name ? 'name' : 'property';
+            const key = m.name || m.property;
+            if (!key) continue;
+            let el = document.head.querySelector(`meta[${attr}="${CSS.escape(key)}"]`);
+            if (!el) {
+                el = document.createElement('meta');
+                el.setAttribute(attr, key);
+                el.setAttribute('data-lv-head', '');
+                document.head.appendChild(el);
+            }
+            el.setAttribute('content', m.content || '');
+        }
+
+        for (const l of head.links || []) {
+            if (!l.rel) continue;
+            let sel = `link[rel="${CSS.escape(l.rel)}"]`;
+            if (l.hreflang) sel += `[hreflang="${CSS.escape(l.hreflang)}"]`;
+            let el = document.head.querySelector(sel);
+            if (!el) {
+                el = document.createElement('link');
+                el.setAttribute('rel', l.rel);
+                el.setAttribute('data-lv-head', '');
+                document.head.appendChild(el);
+            }
+            el.setAttribute('href', l.href || '');
+            for (const attr of ['type', 'sizes', 'hreflang']) {
+                if (l[attr]) el.setAttribute(attr, l[attr]);
+            }
+        }
+    }
+
+    // Favicon/title badge (Socket.PushBadge / core.Badge). The badge is
+    // cleared when the page regains focus and the server is told via
+    // "lv:focus" so it can reset its unread count.
+    _applyBadge(p) {
+        if (!this._badge) {
+            const icon = document.querySelector('link[rel~="icon"]');
+            this._badge = { title: document.title, icon: icon ? icon.href : null, count: 0 };
+            const onFocus = () => {
+                if (this._badge.count > 0) this._clearBadge(true);
+            };
+            window.addEventListener('focus', onFocus);
+            document.addEventListener('visibilitychange', () => {
+                if (document.visibilityState === 'visible' && document.hasFocus()) onFocus();
+            });
+        }
+
+        const count = p.count || 0;
+        if (count <= 0) {
+            if (p.title) this._badge.title = p.title;
+            this._clearBadge(false);
+            return;
+        }
+        if (document.visibilityState === 'visible' && document.hasFocus()) {
+            // Already looking at the page: nothing to notify
+            this._badge.count = count;
+            this._clearBadge(true);
+            return;
+        }
+
+        if (this._badge.count === 0) this._badge.title = document.title;
+        this._badge.count = count;
+        document.title = p.title || `(${count > 99 ? '99+' : count}) ${this._badge.title}`;
+        this._drawFaviconBadge(count);
+    }
+
+    _clearBadge(report) {
+        const b = this._badge;
+        const had = b.count > 0;
+        b.count = 0;
+        document.title = b.title;
+        const icon = document.querySelector('link[rel~="icon"]');
+        if (icon && b.icon) icon.href = b.icon;
+        if (report && had) this.pushEvent('lv:focus', { focused: true });
+    }
+
+    _drawFaviconBadge(count) {
+        const size = 32;
+        const canvas = document.createElement('canvas');
+        canvas.width = canvas.height = size;
+        const ctx = canvas.getContext('2d');
+        if (!ctx) return;
+
+        const apply = () => {
+            if (this._badge.count !== count) return; // superseded
+            const label = count > 99 ? '99+' : String(count);
+            ctx.fillStyle = '#EF4444';
+            ctx.beginPath();
+            ctx.arc(size - 10, 10, 10, 0, 2 * Math.PI);
+            ctx.fill();
+            ctx.fillStyle = '#FFFFFF';
+            ctx.font = `bold ${label.length > 2 ? 9 : 13}px sans-serif`;
+            ctx.textAlign = 'center';
+            ctx.textBaseline = 'middle';
+            ctx.fillText(label, size - 10, 11);
+
+            let icon = document.querySelector('link[rel~="icon"]');
+            if (!icon) {
+                icon = document.createElement('link');
+                icon.rel = 'icon';
+                document.head.appendChild(icon);
+            }
+            try { icon.href = canvas.toDataURL('image/png'); } catch (e) {}
+        };
+
+        if (!this._badge.icon) { apply(); return; }
+        const img = new Image();
+        img.onload = () => { ctx.drawImage(img, 0, 0, size, size); apply(); };
+        img.onerror = apply;
+        img.src = this._badge.icon;
+    }
+
+    // Listeners get (payload, binary, event); '*' listeners get every event.
+    _emit(event, payload, binary) {
+        for (const name of [event, '*']) {
+            const listeners = this.eventListeners.get(name);
+            if (listeners) listeners.forEach(cb => { try { cb(payload, binary, event); } catch (e) {} });
         }
     }
 
@@ -322,35 +867,84 @@ class GoliveKit {
         if (diff.v && diff.v <= this.lastV) return;
         if (diff.v) this.lastV = diff.v;
 
+        // Full render sent as a delta of the previous one
+        if (diff.fd) {
+            const full = this._applyFullDelta(diff.fd);
+            if (full === null) {
+                console.warn('GoliveKit: full render delta does not match, reloading');
+                window.location.reload();
+                return;
+            }
+            diff = Object.assign({}, diff, { f: full });
+        }
+
+        this._callHooks('beforeUpdate');
+
         // Full render (fallback)
         if (diff.f) {
-            const container = document.querySelector('[data-live-view]');
-            if (container) {
-                const temp = document.createElement('div');
-                temp.innerHTML = diff.f;
-                while (container.firstChild) container.removeChild(container.firstChild);
-                while (temp.firstChild) container.appendChild(temp.firstChild);
-            }
-            this._callHooks('updated');
+            this._fullBase = diff.f;
+            this._trees.clear();
+            this._slotTexts.clear();
+            const container = this._container();
+            if (container) this._morph(container, diff.f);
+            this._scanSensors();
+            this._localizeTimes();
+            this._refreshRelativeTimes();
+            this._scanHooks('updated');
+            if (diff.j) this.execJS(diff.j);
             return;
         }
 
         // Text slots (fast path - textContent only)
         if (diff.s) {
             for (const [slotId, content] of Object.entries(diff.s)) {
-                const slot = document.querySelector(`[data-slot="${slotId}"]`);
+                const slot = this._select(`[data-slot="${slotId}"]`)[0];
                 if (slot) slot.textContent = content;
+                this._trees.delete(slotId);
+                this._slotTexts.set(slotId, content);
             }
         }
 
-        // HTML slots (innerHTML with focus protection)
+        // Splices of large text slots, against the text last sent
+        if (diff.w) {
+            for (const [slotId, splices] of Object.entries(diff.w)) {
+                let text = this._slotTexts.get(slotId);
+                if (text === undefined) {
+                    console.warn('GoliveKit: text splices for an unknown slot, reloading');
+                    window.location.reload();
+                    return;
+                }
+                for (let i = splices.length - 1; i >= 0; i--) {
+                    const sp = splices[i];
+                    text = text.slice(0, sp.p) + (sp.t || '') + text.slice(sp.p + (sp.d || 0));
+                }
+                this._slotTexts.set(slotId, text);
+                const slot = this._select(`[data-slot="${slotId}"]`)[0];
+                if (slot) slot.textContent = text;
+            }
+        }
+
+        // HTML slots (patched in place, see _morph)
         if (diff.h) {
-            const active = document.activeElement;
             for (const [slotId, content] of Object.entries(diff.h)) {
-                const slot = document.querySelector(`[data-slot="${slotId}"]`);
-                if (slot && !slot.contains(active)) {
-                    slot.innerHTML = content;
-                }
+                const slot = this._select(`[data-slot="${slotId}"]`)[0];
+                if (slot) this._morph(slot, content);
+                this._trees.delete(slotId);
+                this._slotTexts.delete(slotId);
+                if (diff.v) this._slotVersions.set(slotId, diff.v);
+            }
+        }
+
+        // Slot trees: the dynamics that changed since the last render
+        if (diff.t) {
+            for (const [slotId, change] of Object.entries(diff.t)) {
+                const tree = this._mergeTree(this._trees.get(slotId), change);
+                if (!tree) continue;
+                this._trees.set(slotId, tree);
+                this._slotTexts.delete(slotId);
+                const slot = this._select(`[data-slot="${slotId}"]`)[0];
+                if (slot) this._morph(slot, this._treeHTML(tree));
+                if (diff.v) this._slotVersions.set(slotId, diff.v);
             }
         }
 
@@ -361,13 +955,312 @@ class GoliveKit {
             }
         }
 
-        this._callHooks('updated');
+        this._scanSensors();
+        this._localizeTimes();
+        this._refreshRelativeTimes();
+        this._scanHooks('updated');
+
+        // Commands sent by the handler (js.Exec), on the patched DOM
+        if (diff.j) this.execJS(diff.j);
+    }
+
+    // Patch the children of el to html instead of replacing them, like
+    // morphdom: elements that stay keep their state, so the focused input
+    // keeps its value and selection, scrolled lists keep their position,
+    // and elements marked lv-ignore are left as they are.
+    _morph(el, html) {
+        if (el.hasAttribute('lv-ignore')) return;
+        const template = document.createElement('template');
+        template.innerHTML = html;
+        this._morphChildren(el, template.content);
+    }
+
+    // Key identifying an element across renders: its id or data-key.
+    _nodeKey(node) {
+        return node.nodeType === 1 ? (node.id || node.getAttribute('data-key')) : null;
+    }
+
+    _morphChildren(from, to) {
+        const keyed = new Map();
+        for (const child of from.childNodes) {
+            const key = this._nodeKey(child);
+            if (key) keyed.set(key, child);
+        }
+
+        let cursor = from.firstChild;
+        for (const next of Array.from(to.childNodes)) {
+            const key = this._nodeKey(next);
+            let match = key ? keyed.get(key) : null;
+            if (!match && !key && cursor && !this._nodeKey(cursor)) match = cursor;
+            if (match && (match.nodeType !== next.nodeType || match.nodeName !== next.nodeName)) match = null;
+            if (key) keyed.delete(key);
+
+            if (!match) {
+                from.insertBefore(next, cursor);
+                continue;
+            }
+            if (match === cursor) {
+                cursor = cursor.nextSibling;
+            } else {
+                from.insertBefore(match, cursor);
+            }
+            this._morphNode(match, next);
+        }
+
+        // Whatever was not matched is gone from the render
+        while (cursor) {
+            const next = cursor.nextSibling;
+            cursor.remove();
+            cursor = next;
+        }
+    }
+
+    _morphNode(from, to) {
+        if (from.nodeType !== 1) {
+            if (from.nodeValue !== to.nodeValue) from.nodeValue = to.nodeValue;
+            return;
+        }
+        if (from.hasAttribute('lv-ignore')) return;
+        if (from.nodeName !== to.nodeName) {
+            from.replaceWith(to);
+            return;
+        }
+
+        const focused = from === document.activeElement;
+        for (const attr of Array.from(from.attributes)) {
+            if (!to.hasAttribute(attr.name)) from.removeAttribute(attr.name);
+        }
+        for (const attr of Array.from(to.attributes)) {
+            if (from.getAttribute(attr.name) !== attr.value) from.setAttribute(attr.name, attr.value);
+        }
+        if (from._lvJS) this._reapplyJS(from);
+
+        // Form state lives in properties; the focused field keeps what
+        // the user is typing
+        switch (from.nodeName) {
+            case 'INPUT':
+                if (!focused && from.type !== 'file') {
+                    from.value = to.value;
+                    from.checked = to.checked;
+                }
+                return;
+            case 'TEXTAREA':
+                if (!focused) from.value = to.value;
+                return;
+            case 'SELECT': {
+                if (focused) return;
+                const value = to.value;
+                this._morphChildren(from, to);
+                from.value = value;
+                return;
+            }
+            case 'OPTION':
+                from.selected = to.selected;
+                break;
+        }
+        if (focused && from.isContentEditable) return;
+        this._morphChildren(from, to);
+    }
+
+    // Rebuild a full render from its delta: [start, length] pairs copy the
+    // previous full render, strings are inserted. Returns null when the
+    // delta was computed against another base.
+    _applyFullDelta(fd) {
+        const base = this._fullBase;
+        if (base === null || base.length !== fd.b) return null;
+        let html = '';
+        for (let i = 0; i < fd.o.length; i++) {
+            const op = fd.o[i];
+            if (typeof op === 'string') {
+                html += op;
+            } else {
+                html += base.slice(op, op + fd.o[++i]);
+            }
+        }
+        return html;
+    }
+
+    // Merge a tree change into the tree held: a change with statics ("s")
+    // replaces it, otherwise its numbered dynamics do, and the rows ("d")
+    // of a comprehension replace all of its rows.
+    _mergeTree(tree, change) {
+        if (typeof change === 'string' || (change && change.s)) return change;
+        if (!tree || typeof tree !== 'object') return null;
+        const merged = Object.assign({}, tree);
+        for (const [key, value] of Object.entries(change)) {
+            merged[key] = key === 'd' ? value : this._mergeTree(tree[key], value);
+        }
+        return merged;
+    }
+
+    // HTML of a tree: its statics interleaved with its dynamics.
+    _treeHTML(tree) {
+        if (typeof tree === 'string') return tree;
+        if (tree.d) return tree.d.map(row => this._partsHTML(tree.s, row)).join('');
+        return this._partsHTML(tree.s, tree.s.slice(1).map((_, i) => tree[i]));
+    }
+
+    _partsHTML(statics, dynamics) {
+        let html = statics[0];
+        for (let i = 1; i < statics.length; i++) {
+            html += this._treeHTML(dynamics[i - 1]) + statics[i];
+        }
+        return html;
+    }
+
+    // Reassemble an HTML slot the server streamed in chunks (slots over the
+    // router's streaming threshold). Chunks of an older stream for the same
+    // slot are discarded, and a completed stream is dropped if a newer diff
+    // already updated the slot.
+    _applyChunk(p) {
+        if (!p.id || !p.n) return;
+        let s = this._streams.get(p.id);
+        if (s && s.v > p.v) return;
+        if (!s || s.v !== p.v) {
+            s = { v: p.v, parts: new Array(p.n), got: 0 };
+            this._streams.set(p.id, s);
+        }
+        if (s.parts[p.i] === undefined) {
+            s.parts[p.i] = p.c || '';
+            s.got++;
+        }
+        if (s.got < p.n) return;
+
+        this._streams.delete(p.id);
+        if ((this._slotVersions.get(p.id) || 0) > p.v) return;
+        this._applyDiff({ h: { [p.id]: s.parts.join('') } });
+        this._slotVersions.set(p.id, p.v);
+    }
+
+    // Run JS commands (package js): [name, args] pairs. Commands without a
+    // target act on el, the element that ran them.
+    execJS(ops, el = null) {
+        for (const [name, args = {}] of ops) {
+            const els = args.to ? this._select(args.to) : (el ? [el] : []);
+            const time = args.time === undefined ? 200 : args.time;
+            switch (name) {
+                case 'show':
+                    els.forEach(e => this._jsShow(e, args.display || 'block', args.transition, time));
+                    break;
+                case 'hide':
+                    els.forEach(e => this._jsHide(e, args.transition, time));
+                    break;
+                case 'toggle':
+                    els.forEach(e => e.style.display === 'none' || getComputedStyle(e).display === 'none'
+                        ? this._jsShow(e, args.display || 'block', args.transition, time)
+                        : this._jsHide(e, args.out, time));
+                    break;
+                case 'add_class':
+                case 'remove_class':
+                case 'toggle_class': {
+                    const names = (args.names || '').split(/\s+/).filter(Boolean);
+                    for (const e of els) {
+                        for (const n of names) {
+                            const add = name === 'add_class' || (name === 'toggle_class' && !e.classList.contains(n));
+                            this._jsState(e).classes.set(n, add);
+                            e.classList.toggle(n, add);
+                        }
+                        if (args.transition) this._jsTransition(e, args.transition, time);
+                    }
+                    break;
+                }
+                case 'set_attr':
+                    els.forEach(e => {
+                        this._jsState(e).attrs.set(args.attr, args.value);
+                        e.setAttribute(args.attr, args.value);
+                    });
+                    break;
+                case 'remove_attr':
+                    els.forEach(e => {
+                        this._jsState(e).attrs.set(args.attr, null);
+                        e.removeAttribute(args.attr);
+                    });
+                    break;
+                case 'dispatch':
+                    els.forEach(e => e.dispatchEvent(new CustomEvent(args.event, { bubbles: true, detail: args.detail || {} })));
+                    break;
+                case 'focus':
+                    if (els[0]) els[0].focus();
+                    break;
+                case 'focus_first': {
+                    const first = els[0] && els[0].querySelector('a[href], button:not([disabled]), input:not([disabled]):not([type="hidden"]), select:not([disabled]), textarea:not([disabled]), [tabindex]:not([tabindex="-1"])');
+                    if (first) first.focus();
+                    break;
+                }
+                case 'push': {
+                    const payload = { ...(el ? this._getPayload(el) : {}), ...(args.value || {}) };
+                    if (args.target) payload._target = args.target;
+                    this.pushEvent(args.event, payload);
+                    break;
+                }
+                case 'navigate':
+                    this._navigate(args.href, !!args.replace);
+                    break;
+                case 'patch':
+                    this._patchURL({ to: args.href, replace: !!args.replace });
+                    break;
+            }
+        }
+    }
+
+    _jsShow(el, display, transition, time) {
+        this._jsState(el).display = display;
+        el.style.display = display;
+        if (transition) this._jsTransition(el, transition, time);
+    }
+
+    _jsHide(el, transition, time) {
+        const state = this._jsState(el);
+        state.display = 'none';
+        if (!transition) {
+            el.style.display = 'none';
+            return;
+        }
+        this._jsTransition(el, transition, time, () => {
+            if (state.display === 'none') el.style.display = 'none';
+        });
+    }
+
+    // Apply a transition class for time ms, then run done.
+    _jsTransition(el, cls, time, done) {
+        const names = cls.split(/\s+/).filter(Boolean);
+        clearTimeout(el._lvTransition);
+        el.classList.add(...names);
+        el._lvTransition = setTimeout(() => {
+            el.classList.remove(...names);
+            if (done) done();
+        }, time);
+    }
+
+    // What JS commands changed on an element, reapplied when a render
+    // patches it (_morphNode) so a shown modal stays open.
+    _jsState(el) {
+        if (!el._lvJS) el._lvJS = { display: null, classes: new Map(), attrs: new Map() };
+        return el._lvJS;
+    }
+
+    _reapplyJS(el) {
+        const state = el._lvJS;
+        for (const [name, value] of state.attrs) {
+            if (value === null) el.removeAttribute(name);
+            else el.setAttribute(name, value);
+        }
+        for (const [name, add] of state.classes) el.classList.toggle(name, add);
+        if (state.display !== null) el.style.display = state.display;
     }
 
     _applyListOps(listId, ops) {
-        const container = document.querySelector(`[data-list="${listId}"]`);
+        const container = this._select(`[data-list="${listId}"]`)[0];
         if (!container) return;
 
+        // Lists backing non-HTML client state (map markers, chart series)
+        // are handed to the container's hook instead of patching the DOM.
+        const hook = this._hooked.get(container);
+        if (hook && hook.listOps) {
+            try { hook.listOps(ops); } catch (e) {}
+            return;
+        }
+
         for (const op of ops) {
             switch (op.o) {
                 case 'i': { // Insert
@@ -396,20 +1289,34 @@ class GoliveKit {
                 }
                 case 'u': { // Update
                     const el = container.querySelector(`[data-key="${op.k}"]`);
-                    if (el) el.outerHTML = op.c;
+                    const template = document.createElement('template');
+                    template.innerHTML = op.c;
+                    const node = template.content.firstElementChild;
+                    if (el && node) {
+                        node.dataset.key = op.k;
+                        this._morphNode(el, node);
+                    }
                     break;
                 }
             }
         }
     }
 
-    _send(msg) {
+    _send(msg, binary) {
         if (!this.socket || !this.connected) return;
         try {
-            this.socket.send(JSON.stringify(msg));
+            let data = JSON.stringify(msg);
+            if (binary) {
+                data = this._sse ? JSON.stringify({ ...msg, bin: this._toBase64(binary) })
+                    : this._encodeBinary(msg, binary);
+            }
+            this.socket.send(data);
         } catch (e) {}
     }
 
+    // Resolves with the value the handler set with core.Reply, once the
+    // event's diff has been applied. Without a reply (none set, an error, an
+    // event waiting for the connection) it resolves with undefined.
     pushEvent(event, payload = {}) {
         // Lazy connect on first interaction
         if (!this.connected && !this.connecting) {
@@ -418,17 +1325,129 @@ class GoliveKit {
             });
             return Promise.resolve();
         }
-        return this._pushEvent(event, payload);
+        return this._pushEvent(event, payload).then(this._replyValue);
+    }
+
+    _replyValue(reply) {
+        return reply && reply.status === 'ok' && reply.response ? reply.response.reply : undefined;
+    }
+
+    // Replace the host framework props (React/Vue bridge). They are sent
+    // as an "lv:props" event, and on join after a reconnect.
+    setProps(props) {
+        this.options.props = props;
+        if (this.joined) return this._pushEvent('lv:props', props || {});
+        return Promise.resolve();
+    }
+
+    // Set values of the state shared across tabs (Socket.PushShared) from
+    // the page, such as a theme the user picked: they are shown here and
+    // relayed to the other tabs.
+    share(values) {
+        this._applyShared(values || {}, true);
     }
 
-    _pushEvent(event, payload) {
+    // The last value of a shared key, from this tab or another one.
+    shared(key) {
+        return this._shared.get(key);
+    }
+
+    // tabSync: the tabs of this origin share a BroadcastChannel and a
+    // random group ID, kept in localStorage and sent on join, so the server
+    // pushes each shared value to one tab of the browser. A new tab asks
+    // the others for the values they have.
+    _openTabChannel() {
+        if (typeof BroadcastChannel === 'undefined') return;
+        try {
+            this._tabGroup = localStorage.getItem('lv:tab-group');
+            if (!this._tabGroup) {
+                const bytes = crypto.getRandomValues(new Uint8Array(16));
+                this._tabGroup = Array.from(bytes, b => b.toString(16).padStart(2, '0')).join('');
+                localStorage.setItem('lv:tab-group', this._tabGroup);
+            }
+        } catch (e) {
+            return; // storage blocked: tabs cannot find each other
+        }
+        this._tabs = new BroadcastChannel('golivekit:tabs');
+        this._tabs.onmessage = (e) => {
+            const m = e.data || {};
+            if (m.type === 'shared') this._applyShared(m.values || {}, false);
+            if (m.type === 'hello' && this._shared.size) {
+                this._tabs.postMessage({ type: 'shared', values: Object.fromEntries(this._shared) });
+            }
+        };
+        this._tabs.postMessage({ type: 'hello' });
+    }
+
+    // Show shared values in their [lv-shared="key"] elements and hand them
+    // to "lv:shared" listeners; relay them to the other tabs unless they
+    // came from one.
+    _applyShared(values, relay) {
+        const keys = Object.keys(values);
+        if (!keys.length) return;
+        for (const key of keys) {
+            const v = values[key];
+            this._shared.set(key, v);
+            this._select(`[lv-shared="${CSS.escape(key)}"]`).forEach(el => {
+                el.textContent = v == null ? '' : String(v);
+            });
+        }
+        if (relay && this._tabs) this._tabs.postMessage({ type: 'shared', values });
+        this._emit('lv:shared', values);
+    }
+
+    // Push an event with a binary attachment (ArrayBuffer or typed array).
+    // The server receives it as []byte under payload["binary"].
+    pushBinary(event, payload = {}, data) {
+        return this._pushEvent(event, payload, data).then(this._replyValue);
+    }
+
+    // Queue an input to be sent with all other inputs captured in the same
+    // animation frame, as a single "input_batch" event.
+    queueInput(type, data = {}) {
+        this._inputQueue.push({ t: type, d: data, f: this._inputFrame });
+        if (this._inputScheduled) return;
+        this._inputScheduled = true;
+        const flush = () => {
+            this._inputScheduled = false;
+            this._inputFrame++;
+            const inputs = this._inputQueue;
+            this._inputQueue = [];
+            if (inputs.length) this.pushEvent('input_batch', { inputs });
+        };
+        typeof requestAnimationFrame === 'function' ? requestAnimationFrame(flush) : setTimeout(flush, 16);
+    }
+
+    // Apply a binary board diff (gameloop.EncodeDiff) to a board
+    // { width, height, cells: Uint8Array }. Returns the updated board.
+    static applyBoardDiff(board, buf) {
+        const view = new DataView(buf);
+        const width = view.getUint16(1), height = view.getUint16(3);
+        if (view.getUint8(0) === 0) {
+            return { width, height, cells: new Uint8Array(buf.slice(5, 5 + width * height)) };
+        }
+        const count = view.getUint32(5);
+        for (let i = 0, off = 9; i < count; i++, off += 5) {
+            board.cells[view.getUint32(off)] = view.getUint8(off + 4);
+        }
+        return board;
+    }
+
+    _pushEvent(event, payload, binary) {
         if (!this.connected || !this.joined) return Promise.resolve();
 
         const ref = String(++this.msgRef);
 
         return new Promise((resolve) => {
             this.pendingReplies.set(ref, resolve);
-            this._send({ ref, topic: this.topic, event, payload });
+            const msg = { ref, topic: this.topic, event, payload };
+            if (binary || !this.options.batchEvents || event === 'lv:params' || event === 'lv:visibility') {
+                // Sent on its own, after the events queued before it
+                this._flushEvents();
+                this._send(msg, binary);
+            } else {
+                this._batchEvent(msg);
+            }
             setTimeout(() => {
                 if (this.pendingReplies.has(ref)) {
                     this.pendingReplies.delete(ref);
@@ -438,19 +1457,70 @@ class GoliveKit {
         });
     }
 
+    // Queue an event to be sent with the others fired in the same animation
+    // frame. The server handles a batch in order and answers with one diff.
+    // Hidden pages get no frames, so they flush on a timer.
+    _batchEvent(msg) {
+        this._eventBatch.push(msg);
+        if (this._batchScheduled) return;
+        this._batchScheduled = true;
+        const flush = () => this._flushEvents();
+        typeof requestAnimationFrame === 'function' && !document.hidden
+            ? requestAnimationFrame(flush) : setTimeout(flush, 0);
+    }
+
+    _flushEvents() {
+        this._batchScheduled = false;
+        const events = this._eventBatch;
+        if (!events.length) return;
+        this._eventBatch = [];
+        if (events.length === 1) {
+            this._send(events[0]);
+            return;
+        }
+        this._send({
+            ref: String(++this.msgRef),
+            topic: events[0].topic,
+            event: 'lv:batch',
+            payload: { events: events.map(({ ref, event, payload }) => ({ ref, event, payload })) }
+        });
+    }
+
     _getLiveViewId() {
-        const el = document.querySelector('[data-live-view]');
+        const el = this._select('[data-live-view]')[0];
         return el ? el.dataset.liveView : 'main';
     }
 
     bindEvents() {
-        document.addEventListener('click', (e) => {
+        // Listeners are removed by destroy()
+        this._listeners = new AbortController();
+        const opts = { signal: this._listeners.signal };
+        const root = this.root;
+
+        this._initHistory(opts);
+
+        // Hidden pages get no renders from server pushes until shown again
+        // (core.VisibilityEvent)
+        document.addEventListener('visibilitychange', () => this._reportVisibility(), opts);
+
+        root.addEventListener('click', (e) => {
             const target = e.target.closest('[lv-click]');
-            if (!target) return;
+            if (!target || !this._owns(target)) return;
 
             e.preventDefault();
 
+            // lv-confirm="Delete 3 items?" asks before sending
+            const confirmMsg = target.getAttribute('lv-confirm');
+            if (confirmMsg && !window.confirm(confirmMsg)) return;
+
             const event = target.getAttribute('lv-click');
+
+            // lv-click holding JS commands (package js) runs them instead
+            if (event.startsWith('[')) {
+                try { this.execJS(JSON.parse(event), target); } catch (err) { console.warn('GoliveKit: invalid JS commands', err); }
+                return;
+            }
+
             const payload = this._getPayload(target);
 
             // Debounce rapid clicks
@@ -477,19 +1547,59 @@ class GoliveKit {
                     target.classList.remove('lv-pending');
                     this._revertOptimistic();
                 });
-        });
+        }, opts);
+
+        root.addEventListener('click', (e) => {
+            const target = e.target.closest('[lv-record]');
+            if (!target || !this._owns(target)) return;
+            e.preventDefault();
+            target._lvRecorder ? this._stopRecording(target) : this._startRecording(target);
+        }, opts);
+
+        // <input type="file" lv-upload="avatar">: stream the chosen files to
+        // the server (uploads.Uploader) while the form is being filled in.
+        root.addEventListener('change', (e) => {
+            const input = e.target.closest('input[lv-upload]');
+            if (input && this._owns(input)) this._upload(input);
+        }, opts);
+
+        // <a href="/demos" lv-navigate>: open another route on this socket
+        // (live_redirect) instead of loading the page. Modified clicks keep
+        // their browser behavior (new tab, download).
+        root.addEventListener('click', (e) => {
+            const link = e.target.closest('a[lv-navigate]');
+            if (!link || !this._owns(link) || this.options.root) return;
+            if (e.defaultPrevented || e.button !== 0 || e.metaKey || e.ctrlKey || e.shiftKey || e.altKey) return;
+            const url = new URL(link.href, location.href);
+            if (url.origin !== location.origin) return;
+            e.preventDefault();
+            this._navigate(url.pathname + url.search);
+        }, opts);
+
+        // lv-back="/users": close a stacked route (modal, drawer). Goes back
+        // when the previous history entry belongs to this page, else
+        // replaces the URL with the fallback (deep links).
+        root.addEventListener('click', (e) => {
+            const target = e.target.closest('[lv-back]');
+            if (!target || !this._owns(target) || this.options.root) return;
+            e.preventDefault();
+            if (history.state && history.state.lvPrev) history.back();
+            else this._patchURL({ to: target.getAttribute('lv-back'), replace: true });
+        }, opts);
 
-        document.addEventListener('submit', (e) => {
+        root.addEventListener('submit', (e) => {
             const form = e.target.closest('[lv-submit]');
-            if (form) {
+            if (form && this._owns(form)) {
                 e.preventDefault();
-                this.pushEvent(form.getAttribute('lv-submit'), Object.fromEntries(new FormData(form)));
+                // Files travel through lv-upload; only text fields are submitted.
+                const fields = [...new FormData(form)].filter(([, v]) => typeof v === 'string');
+                this.pushEvent(form.getAttribute('lv-submit'), { ...Object.fromEntries(fields), ...this._target(form) });
             }
-        });
+        }, opts);
 
-        document.addEventListener('change', (e) => {
+        root.addEventListener('change', (e) => {
             const target = e.target.closest('[lv-change]');
-            if (target) {
+            if (target && this._owns(target)) {
                 const debounce = parseInt(target.getAttribute('lv-debounce') || '0');
                 const payload = { value: target.value, ...this._getPayload(target) };
                 if (debounce > 0) {
@@ -499,37 +1609,709 @@ class GoliveKit {
                     this.pushEvent(target.getAttribute('lv-change'), payload);
                 }
             }
-        });
+        }, opts);
 
-        document.addEventListener('input', (e) => {
+        root.addEventListener('input', (e) => {
             const target = e.target.closest('[lv-input]');
-            if (target) {
+            if (target && this._owns(target)) {
                 const debounce = parseInt(target.getAttribute('lv-debounce') || '300');
                 clearTimeout(target._dt);
                 target._dt = setTimeout(() => {
                     this.pushEvent(target.getAttribute('lv-input'), { value: target.value, ...this._getPayload(target) });
                 }, debounce);
             }
+        }, opts);
+
+        // lv-search="<listbox id>": arrow keys move through the results,
+        // Enter opens the active one, Escape clears the query.
+        root.addEventListener('keydown', (e) => {
+            const input = e.target.closest && e.target.closest('[lv-search]');
+            if (input && this._owns(input)) this._searchKeydown(input, e);
+        }, opts);
+    }
+
+    _searchKeydown(input, e) {
+        const list = document.getElementById(input.getAttribute('lv-search'));
+        const options = list ? Array.from(list.querySelectorAll('[role="option"]')) : [];
+        const current = options.findIndex(o => o.classList.contains('lv-search-active'));
+
+        const activate = (i) => {
+            options.forEach((o, j) => {
+                o.classList.toggle('lv-search-active', i === j);
+                o.setAttribute('aria-selected', i === j ? 'true' : 'false');
+            });
+            if (options[i]) {
+                input.setAttribute('aria-activedescendant', options[i].id);
+                options[i].scrollIntoView({ block: 'nearest' });
+            }
+        };
+
+        switch (e.key) {
+            case 'ArrowDown':
+                if (!options.length) return;
+                e.preventDefault();
+                activate((current + 1) % options.length);
+                break;
+            case 'ArrowUp':
+                if (!options.length) return;
+                e.preventDefault();
+                activate(current <= 0 ? options.length - 1 : current - 1);
+                break;
+            case 'Enter': {
+                const hit = options[current < 0 ? 0 : current]?.querySelector('a, button');
+                if (!hit) return;
+                e.preventDefault();
+                hit.click();
+                break;
+            }
+            case 'Escape':
+                if (!input.value) return;
+                e.preventDefault();
+                input.value = '';
+                input.removeAttribute('aria-activedescendant');
+                input.dispatchEvent(new Event('input', { bubbles: true }));
+                break;
+        }
+    }
+
+    // History entries carry a key (history.state.lvKey) under which the page's
+    // UI state is kept in sessionStorage: window scroll, and the scroll
+    // offsets, <details> open state and aria-expanded / aria-selected /
+    // hidden attributes of elements marked lv-ui-state (they need an id).
+    // Leaving an entry saves it; coming back with back/forward restores it.
+    _initHistory(opts) {
+        if (this.options.root || !window.history || !window.sessionStorage) return;
+        history.scrollRestoration = 'manual';
+        this._entryKey = this._ensureEntryKey();
+        window.addEventListener('popstate', () => this._onPopState(), opts);
+        window.addEventListener('pagehide', () => this._saveUIState(), opts);
+        // Back to this entry after a full page load (or a reload)
+        this._restoreUIState();
+    }
+
+    _ensureEntryKey() {
+        const state = history.state || {};
+        if (state.lvKey) return state.lvKey;
+        const key = Date.now().toString(36) + Math.random().toString(36).slice(2, 8);
+        history.replaceState({ ...state, lvKey: key }, '');
+        return key;
+    }
+
+    // Back/forward between entries of this document (push_patch): the server
+    // re-renders for the URL, then the entry's UI state is restored.
+    _onPopState() {
+        this._saveUIState();
+        this._entryKey = this._ensureEntryKey();
+        this._urlChanged('pop').then(() => this._restoreUIState());
+    }
+
+    // Tell the server the URL changed (lv:params), so the view's
+    // HandleParams runs. kind is "patch", "pop" (back/forward) or "navigate".
+    // A URL of another route answers with that route's document.
+    _urlChanged(kind) {
+        if (this._urlFromLocation) this.options.url = this._defaultURL();
+        return this._pushEvent('lv:params', { url: location.pathname + location.search, kind }).then(reply => {
+            const doc = reply && reply.response && reply.response.document;
+            if (doc) this._swapDocument(doc);
+            return reply;
+        });
+    }
+
+    // Live navigation (lv-navigate, push_navigate): add a history entry and
+    // let the server mount the route on this socket. Without a joined
+    // socket the page is loaded; routes the server cannot mount this way
+    // answer with lv:redirect.
+    _navigate(to, replace = false) {
+        if (!/^\/(?![\/\\])/.test(to || '')) return;
+        if (!this.joined || !this._entryKey) {
+            this._saveUIState();
+            this.disconnect();
+            if (replace) window.location.replace(to);
+            else window.location.assign(to);
+            return;
+        }
+        this._saveUIState();
+        if (replace) history.replaceState({}, '', to);
+        else history.pushState({}, '', to);
+        this._entryKey = this._ensureEntryKey();
+        window.scrollTo(0, 0);
+        this._urlChanged('navigate');
+    }
+
+    // Swap in the document of a route mounted by live navigation: the
+    // head's title, meta tags and stylesheets, the <html> language and the
+    // body. Its scripts do not run (the client is already loaded); pages
+    // behave through lv- attributes and hooks.
+    _swapDocument(html) {
+        const doc = new DOMParser().parseFromString(html, 'text/html');
+        const swapped = 'title, meta, style:not(#lv-styles), link[rel="stylesheet"], link[rel="icon"], link[rel="canonical"]';
+        document.head.querySelectorAll(swapped).forEach(el => el.remove());
+        doc.head.querySelectorAll(swapped).forEach(el => document.head.appendChild(document.importNode(el, true)));
+        for (const name of ['lang', 'dir']) {
+            const value = doc.documentElement.getAttribute(name);
+            if (value) document.documentElement.setAttribute(name, value);
+        }
+        document.body.replaceChildren(...Array.from(doc.body.childNodes, n => document.importNode(n, true)));
+
+        this.topic = 'lv:' + this._getLiveViewId();
+        this._streams.clear();
+        this._slotVersions.clear();
+        this._trees.clear();
+        this._fullBase = null;
+        this._slotTexts.clear();
+        this.pendingOptimistic.clear();
+        this._scanSensors();
+        this._localizeTimes();
+        this._refreshRelativeTimes();
+        this._scanHooks();
+    }
+
+    // Server push_patch (lv:patch): change the URL without a page load.
+    // Only same-origin paths are followed. A pushed entry starts at the top.
+    _patchURL(p) {
+        if (!/^\/(?![\/\\])/.test(p.to || '') || !this._entryKey) return;
+        this._saveUIState();
+        if (p.replace) {
+            history.replaceState({ ...(history.state || {}), lvKey: this._entryKey }, '', p.to);
+        } else {
+            history.pushState({ lvPrev: true }, '', p.to);
+            this._entryKey = this._ensureEntryKey();
+            window.scrollTo(0, 0);
+        }
+        this._urlChanged('patch');
+    }
+
+    _saveUIState() {
+        if (!this._entryKey) return;
+        const state = { x: window.scrollX, y: window.scrollY, els: {} };
+        for (const el of this._select('[lv-ui-state][id]')) {
+            const s = { t: el.scrollTop, l: el.scrollLeft, a: {} };
+            if (el.tagName === 'DETAILS') s.open = el.open;
+            for (const name of ['aria-expanded', 'aria-selected', 'hidden']) {
+                s.a[name] = el.getAttribute(name);
+            }
+            state.els[el.id] = s;
+        }
+        try {
+            sessionStorage.setItem('lv:ui:' + this._entryKey, JSON.stringify(state));
+        } catch (e) {} // storage full or disabled
+    }
+
+    _restoreUIState() {
+        let state = null;
+        try {
+            state = JSON.parse(sessionStorage.getItem('lv:ui:' + this._entryKey));
+        } catch (e) {}
+        if (!state) return;
+        for (const [id, s] of Object.entries(state.els || {})) {
+            const el = document.getElementById(id);
+            if (!el || !this._owns(el)) continue;
+            if ('open' in s) el.open = s.open;
+            for (const [name, value] of Object.entries(s.a || {})) {
+                if (value === null) el.removeAttribute(name);
+                else el.setAttribute(name, value);
+            }
+            el.scrollTop = s.t;
+            el.scrollLeft = s.l;
+        }
+        window.scrollTo(state.x, state.y);
+    }
+
+    _timezone() {
+        try { return Intl.DateTimeFormat().resolvedOptions().timeZone || ''; } catch (e) { return ''; }
+    }
+
+    _reportVisibility() {
+        if (this.joined) this._pushEvent('lv:visibility', { hidden: document.hidden });
+    }
+
+    // Browser features reported on join (core.ConnectInfo.Features).
+    _features() {
+        const features = {
+            touch: navigator.maxTouchPoints > 0,
+            share: typeof navigator.share === 'function',
+            notifications: typeof Notification !== 'undefined',
+            webtransport: typeof WebTransport !== 'undefined',
+            tabsync: this._tabs !== null
+        };
+        return Object.keys(features).filter(name => features[name]);
+    }
+
+    // Viewport and preferences reported on join (core.ConnectInfo), so the
+    // server can adapt the first render.
+    _clientInfo() {
+        const media = q => typeof matchMedia === 'function' && matchMedia(q).matches;
+        const conn = navigator.connection;
+        return {
+            viewport_width: window.innerWidth,
+            viewport_height: window.innerHeight,
+            color_scheme: media('(prefers-color-scheme: dark)') ? 'dark' : 'light',
+            reduced_motion: media('(prefers-reduced-motion: reduce)'),
+            locale: navigator.language || '',
+            connection: (conn && conn.effectiveType) || ''
+        };
+    }
+
+    // Format <time lv-localtime="time|date|datetime"> elements (core.LocalTime)
+    // in the browser's timezone and locale.
+    _localizeTimes(root = this.root) {
+        const styles = {
+            time: { hour: '2-digit', minute: '2-digit', second: '2-digit' },
+            date: { dateStyle: 'medium' },
+            datetime: { dateStyle: 'medium', timeStyle: 'short' }
+        };
+        root.querySelectorAll('time[lv-localtime]').forEach(el => {
+            const d = new Date(el.getAttribute('datetime'));
+            if (isNaN(d)) return;
+            const opts = styles[el.getAttribute('lv-localtime')] || styles.datetime;
+            try {
+                el.textContent = new Intl.DateTimeFormat(document.documentElement.lang || undefined, opts).format(d);
+                el.title = d.toString();
+            } catch (e) {}
+        });
+    }
+
+    // Keep <time lv-relative> elements (i18n RelativeTimeHTML) fresh
+    // client-side. Refreshes every 15s while such elements exist.
+    _refreshRelativeTimes() {
+        const els = this._select('time[lv-relative]');
+        if (!els.length || typeof Intl === 'undefined' || !Intl.RelativeTimeFormat) {
+            clearInterval(this._relativeTimer);
+            this._relativeTimer = null;
+            return;
+        }
+        const units = [
+            ['year', 31536000], ['month', 2592000], ['week', 604800],
+            ['day', 86400], ['hour', 3600], ['minute', 60], ['second', 1]
+        ];
+        const now = Date.now();
+        els.forEach(el => {
+            const t = Date.parse(el.getAttribute('datetime'));
+            if (isNaN(t)) return;
+            const secs = Math.round((t - now) / 1000);
+            const abs = Math.abs(secs);
+            try {
+                const rtf = new Intl.RelativeTimeFormat(el.lang || document.documentElement.lang || undefined, { numeric: 'auto' });
+                if (abs < 10) {
+                    el.textContent = rtf.format(0, 'second');
+                } else {
+                    const [unit, size] = units.find(([, size]) => abs >= size);
+                    el.textContent = rtf.format(Math.trunc(secs / size), unit);
+                }
+                if (!el.title) el.title = new Date(t).toLocaleString();
+            } catch (e) {}
+        });
+        if (!this._relativeTimer) {
+            this._relativeTimer = setInterval(() => this._refreshRelativeTimes(), 15000);
+        }
+    }
+
+    // Sensor bindings. Each element is bound once; bindings of elements
+    // removed from the DOM are torn down on the next scan.
+    //   lv-geolocation="moved"  lv-geolocation-high-accuracy  lv-throttle="1000"
+    //   lv-visibility="seen"    lv-resize="resized"           lv-throttle="250"
+    _scanSensors() {
+        this._scanReceipts();
+        for (const [el, stop] of this._sensors) {
+            if (!el.isConnected) { stop(); this._sensors.delete(el); }
+        }
+        this._select('[lv-geolocation],[lv-visibility],[lv-resize]').forEach(el => {
+            if (this._sensors.has(el)) return;
+            const stops = [];
+            if (el.hasAttribute('lv-geolocation')) stops.push(this._bindGeolocation(el));
+            if (el.hasAttribute('lv-visibility')) stops.push(this._bindVisibility(el));
+            if (el.hasAttribute('lv-resize')) stops.push(this._bindResize(el));
+            this._sensors.set(el, () => stops.forEach(s => s && s()));
+        });
+    }
+
+    // Leading + trailing throttle: the latest call always gets through.
+    // Read receipts: elements with lv-receipt="<message id>" are acknowledged
+    // as delivered once rendered and as read once visible while the page has
+    // focus. Acks are batched into a single "lv:ack" event per status.
+    _scanReceipts() {
+        const r = this._receipts;
+        if (!r.observer && 'IntersectionObserver' in window) {
+            r.observer = new IntersectionObserver(entries => {
+                for (const entry of entries) {
+                    if (entry.isIntersecting) this._ackReceipt(entry.target, 'read');
+                }
+            }, { threshold: 0.5 });
+            window.addEventListener('focus', () => {
+                this._select('[lv-receipt]').forEach(el => {
+                    const rect = el.getBoundingClientRect();
+                    if (rect.bottom > 0 && rect.top < window.innerHeight) this._ackReceipt(el, 'read');
+                });
+            });
+        }
+        this._select('[lv-receipt]').forEach(el => {
+            const id = el.getAttribute('lv-receipt');
+            if (!id || r.read.has(id)) return;
+            this._ackReceipt(el, 'delivered');
+            if (r.observer) r.observer.observe(el);
+        });
+    }
+
+    _ackReceipt(el, status) {
+        const r = this._receipts;
+        const id = el.getAttribute('lv-receipt');
+        if (!id || r[status].has(id)) return;
+        if (status === 'read') {
+            if (document.visibilityState !== 'visible' || !document.hasFocus()) return;
+            if (r.observer) r.observer.unobserve(el);
+        }
+        r[status].add(id);
+        r.queue[status].push(id);
+        if (!r.timer) {
+            r.timer = setTimeout(() => {
+                r.timer = null;
+                for (const st of ['delivered', 'read']) {
+                    if (r.queue[st].length) this.pushEvent('lv:ack', { ids: r.queue[st], status: st });
+                    r.queue[st] = [];
+                }
+            }, 250);
+        }
+    }
+
+    _throttle(fn, ms) {
+        let last = 0, timer = null, args = null;
+        return (...a) => {
+            args = a;
+            const wait = ms - (Date.now() - last);
+            if (wait <= 0) {
+                last = Date.now();
+                fn(...args);
+            } else if (!timer) {
+                timer = setTimeout(() => { timer = null; last = Date.now(); fn(...args); }, wait);
+            }
+        };
+    }
+
+    _bindGeolocation(el) {
+        const event = el.getAttribute('lv-geolocation');
+        const extra = this._getPayload(el);
+        if (!navigator.geolocation) {
+            this.pushEvent(event, { permission: 'unsupported', ...extra });
+            return null;
+        }
+
+        let status = null;
+        if (navigator.permissions) {
+            navigator.permissions.query({ name: 'geolocation' }).then(s => {
+                status = s;
+                this.pushEvent(event, { permission: s.state, ...extra });
+                s.onchange = () => this.pushEvent(event, { permission: s.state, ...extra });
+            }).catch(() => {});
+        }
+
+        const send = this._throttle(pos => {
+            const c = pos.coords;
+            this.pushEvent(event, {
+                permission: 'granted', lat: c.latitude, lng: c.longitude, accuracy: c.accuracy,
+                altitude: c.altitude, heading: c.heading, speed: c.speed, timestamp: pos.timestamp, ...extra
+            });
+        }, parseInt(el.getAttribute('lv-throttle') || '1000'));
+
+        const id = navigator.geolocation.watchPosition(send, err => {
+            this.pushEvent(event, {
+                permission: err.code === 1 ? 'denied' : 'granted',
+                error: err.code === 1 ? 'permission_denied' : err.code === 2 ? 'unavailable' : 'timeout',
+                message: err.message, ...extra
+            });
+        }, { enableHighAccuracy: el.hasAttribute('lv-geolocation-high-accuracy') });
+
+        return () => {
+            navigator.geolocation.clearWatch(id);
+            if (status) status.onchange = null;
+        };
+    }
+
+    _bindVisibility(el) {
+        const event = el.getAttribute('lv-visibility');
+        const extra = this._getPayload(el);
+        let inView = false, ratio = 0;
+        const send = this._throttle(() => this.pushEvent(event, {
+            visible: inView && !document.hidden, in_viewport: inView, page_visible: !document.hidden,
+            ratio: Math.round(ratio * 100) / 100, ...extra
+        }), parseInt(el.getAttribute('lv-throttle') || '250'));
+
+        let observer = null;
+        if (typeof IntersectionObserver !== 'undefined') {
+            observer = new IntersectionObserver(entries => {
+                const e = entries[entries.length - 1];
+                if (e.isIntersecting === inView && Math.abs(e.intersectionRatio - ratio) < 0.25) return;
+                inView = e.isIntersecting;
+                ratio = e.intersectionRatio;
+                send();
+            }, { threshold: [0, 0.25, 0.5, 0.75, 1] });
+            observer.observe(el);
+        }
+        const onPage = () => send();
+        document.addEventListener('visibilitychange', onPage);
+
+        return () => {
+            if (observer) observer.disconnect();
+            document.removeEventListener('visibilitychange', onPage);
+        };
+    }
+
+    _bindResize(el) {
+        const event = el.getAttribute('lv-resize');
+        const extra = this._getPayload(el);
+        const send = this._throttle(() => {
+            const r = el.getBoundingClientRect();
+            this.pushEvent(event, {
+                width: Math.round(r.width), height: Math.round(r.height),
+                viewport_width: window.innerWidth, viewport_height: window.innerHeight,
+                dpr: window.devicePixelRatio || 1,
+                orientation: window.innerWidth >= window.innerHeight ? 'landscape' : 'portrait', ...extra
+            });
+        }, parseInt(el.getAttribute('lv-throttle') || '250'));
+
+        let observer = null;
+        if (typeof ResizeObserver !== 'undefined') {
+            observer = new ResizeObserver(send);
+            observer.observe(el);
+        } else {
+            send();
+        }
+        window.addEventListener('resize', send);
+
+        return () => {
+            if (observer) observer.disconnect();
+            window.removeEventListener('resize', send);
+        };
+    }
+
+    // lv-record: capture audio with MediaRecorder and stream chunks to the
+    // server (uploads.Recorder) as binary events while recording.
+    //   lv-record="voice_note"  lv-record-timeslice="250"  lv-record-max="60"
+    async _startRecording(el) {
+        if (!navigator.mediaDevices || typeof MediaRecorder === 'undefined') return;
+        const name = el.getAttribute('lv-record');
+        const timeslice = parseInt(el.getAttribute('lv-record-timeslice') || '250');
+        const max = parseInt(el.getAttribute('lv-record-max') || '0');
+
+        let stream;
+        try {
+            stream = await navigator.mediaDevices.getUserMedia({ audio: true });
+        } catch (e) {
+            return;
+        }
+
+        const preferred = el.getAttribute('lv-record-mime') || 'audio/webm;codecs=opus';
+        const mime = MediaRecorder.isTypeSupported(preferred) ? preferred : '';
+        const recorder = new MediaRecorder(stream, mime ? { mimeType: mime } : undefined);
+        const id = `${name}-${Date.now().toString(36)}`;
+        const started = Date.now();
+        let seq = 0;
+        let chain = this.pushEvent('record_start', { id, name, mime: recorder.mimeType, ...this._getPayload(el) });
+
+        // Chunks are chained so they reach the server in order.
+        recorder.ondataavailable = (e) => {
+            if (!e.data || !e.data.size) return;
+            const n = seq++;
+            chain = chain.then(() => e.data.arrayBuffer()).then(buf =>
+                this.pushBinary('record_chunk', { id, seq: n, duration: Date.now() - started }, buf));
+        };
+        recorder.onstop = () => {
+            stream.getTracks().forEach(t => t.stop());
+            const event = el._lvRecordCancel ? 'record_cancel' : 'record_stop';
+            chain.then(() => this.pushEvent(event, { id, duration: Date.now() - started }));
+            el._lvRecordCancel = false;
+        };
+
+        el._lvRecorder = recorder;
+        el.classList.add('lv-recording');
+        recorder.start(timeslice);
+        if (max > 0) el._lvRecordTimer = setTimeout(() => this._stopRecording(el), max * 1000);
+    }
+
+    _stopRecording(el, cancel = false) {
+        const recorder = el._lvRecorder;
+        if (!recorder) return;
+        clearTimeout(el._lvRecordTimer);
+        el._lvRecorder = null;
+        el._lvRecordCancel = cancel;
+        el.classList.remove('lv-recording');
+        if (recorder.state !== 'inactive') recorder.stop();
+    }
+
+    // lv-upload: announce the chosen files ("upload_start"), then send the
+    // ones passing the input's constraints as "upload_chunk" binary events,
+    // in order. Chunks are not acknowledged: sending pauses while the socket
+    // buffer is full. Progress is emitted locally as 'upload:progress'.
+    // Inputs with data-external upload to presigned URLs instead.
+    //   lv-upload="avatar"  accept=".jpg,image/*"  data-max-size="5242880"
+    //   data-max-entries="1"  data-chunk-size="262144"
+    async _upload(input) {
+        const name = input.getAttribute('lv-upload');
+        const maxSize = parseInt(input.dataset.maxSize || '0');
+        const maxEntries = parseInt(input.dataset.maxEntries || '1');
+        const chunkSize = parseInt(input.dataset.chunkSize || '262144');
+        const accept = (input.getAttribute('accept') || '').split(',')
+            .map(a => a.trim().toLowerCase()).filter(Boolean);
+        const target = this._target(input);
+        const id = Date.now().toString(36);
+        const files = Array.from(input.files || []).map((file, i) => ({ ref: `${id}-${i}`, file }));
+        input.value = '';
+        if (!files.length || !this.joined) return;
+
+        this._pushEvent('upload_start', {
+            name, ...target,
+            files: files.map(({ ref, file }) => ({ ref, name: file.name, size: file.size, type: file.type })),
         });
+
+        // External uploads wait for the presigned URLs of the accepted files
+        if (input.hasAttribute('data-external')) {
+            this._externalFiles = this._externalFiles || new Map();
+            files.forEach(({ ref, file }) => this._externalFiles.set(ref, { name, file, target }));
+            return;
+        }
+
+        const valid = files.filter(({ file }, i) =>
+            i < maxEntries && (!maxSize || file.size <= maxSize) && this._accepts(accept, file));
+        for (const { ref, file } of valid) {
+            for (let offset = 0; offset < file.size; offset += chunkSize) {
+                while (this.socket && this.socket.bufferedAmount > chunkSize * 4) {
+                    await new Promise(r => setTimeout(r, 20));
+                }
+                if (!this.joined) return;
+                const buf = await file.slice(offset, offset + chunkSize).arrayBuffer();
+                this._pushEvent('upload_chunk', { name, ref, offset, ...target }, buf);
+                this._emit('upload:progress', {
+                    name, ref, file: file.name, loaded: Math.min(offset + chunkSize, file.size), total: file.size,
+                });
+            }
+        }
+    }
+
+    // lv:upload_presign: PUT each accepted file straight to the storage and
+    // report progress (in 10% steps), completion or failure to the server.
+    _uploadExternal({ name, entries }) {
+        for (const { ref, url, method, headers } of entries || []) {
+            const pending = this._externalFiles && this._externalFiles.get(ref);
+            if (!pending) continue;
+            this._externalFiles.delete(ref);
+            const { file, target } = pending;
+            const xhr = new XMLHttpRequest();
+            let reported = 0;
+            xhr.open(method || 'PUT', url);
+            for (const [k, v] of Object.entries(headers || {})) xhr.setRequestHeader(k, v);
+            xhr.upload.onprogress = (e) => {
+                if (!e.lengthComputable) return;
+                this._emit('upload:progress', { name, ref, file: file.name, loaded: e.loaded, total: e.total });
+                const progress = Math.floor(e.loaded * 100 / e.total);
+                if (progress - reported >= 10) {
+                    reported = progress;
+                    this._pushEvent('upload_progress', { name, ref, progress, ...target });
+                }
+            };
+            xhr.onload = () => this._pushEvent(xhr.status < 300 ? 'upload_done' : 'upload_error', { name, ref, ...target });
+            xhr.onerror = () => this._pushEvent('upload_error', { name, ref, ...target });
+            xhr.send(file);
+        }
+    }
+
+    // Mirrors the accept attribute: extensions, MIME types and "image/*".
+    _accepts(accept, file) {
+        if (!accept.length) return true;
+        const name = file.name.toLowerCase(), type = (file.type || '').toLowerCase();
+        return accept.some(a => a.startsWith('.') ? name.endsWith(a)
+            : a === '*/*' || a === type || (a.endsWith('/*') && type.startsWith(a.slice(0, -1))));
     }
 
     _getPayload(el) {
-        const p = {};
+        const p = this._target(el);
         for (const attr of el.attributes) {
             if (attr.name.startsWith('lv-value-')) p[attr.name.slice(9)] = attr.value;
         }
         return p;
     }
 
+    // Events from inside a LiveComponent go to it: the closest lv-target
+    // names it (lv-target="" sends to the view).
+    _target(el) {
+        const t = el.closest('[lv-target]');
+        const id = t && t.getAttribute('lv-target');
+        return id ? { _target: id } : {};
+    }
+
     registerHook(name, callbacks) { this.hooks.set(name, callbacks); }
 
+    // Hooks registered on this client, then on GoliveKit.hooks.
+    _hookDef(name) {
+        return this.hooks.get(name) || GoliveKit.hooks[name];
+    }
+
+    // Each hooked element gets its own instance of the hook: callbacks run
+    // with it as this, so state set on this stays with the element.
+    _newHook(el, def) {
+        const hook = Object.create(def);
+        const handlers = [];
+        hook.el = el;
+        hook.liveView = this;
+        hook.pushEvent = (event, payload = {}) => this.pushEvent(event, { ...payload, ...this._target(el) });
+        // handleEvent returns a handle for removeHandleEvent
+        hook.handleEvent = (event, cb) => {
+            const handle = [event, cb];
+            this.on(event, cb);
+            handlers.push(handle);
+            return handle;
+        };
+        hook.removeHandleEvent = (handle) => {
+            const i = handlers.indexOf(handle);
+            if (i < 0) return;
+            handlers.splice(i, 1);
+            this.off(handle[0], handle[1]);
+        };
+        // Events pushed to this hook only (Socket.PushEventTo)
+        hook._lvDispatch = (event, payload) => {
+            for (const [name, cb] of handlers) {
+                if (name === event) try { cb(payload, undefined, event); } catch (e) {}
+            }
+        };
+        hook._lvRemoveHandlers = () => handlers.splice(0).forEach(([event, cb]) => this.off(event, cb));
+        return hook;
+    }
+
+    // Mount hooks on elements that gained lv-hook and destroy those of
+    // elements that were removed or changed hook. event, if set, is called
+    // on the hooks that stay.
+    _scanHooks(event) {
+        for (const [el, hook] of this._hooked) {
+            if (el.isConnected && this._hookDef(el.getAttribute('lv-hook')) === Object.getPrototypeOf(hook)) continue;
+            this._hooked.delete(el);
+            this._runHook(hook, 'destroyed');
+            hook._lvRemoveHandlers();
+        }
+        if (event) this._callHooks(event);
+        this._select('[lv-hook]').forEach(el => {
+            if (this._hooked.has(el)) return;
+            const def = this._hookDef(el.getAttribute('lv-hook'));
+            if (!def) return;
+            const hook = this._newHook(el, def);
+            this._hooked.set(el, hook);
+            this._runHook(hook, 'mounted');
+        });
+    }
+
+    _disconnectHooks() {
+        this._hooksDown = true;
+        this._callHooks('disconnected');
+    }
+
     _callHooks(event) {
-        try {
-            document.querySelectorAll('[lv-hook]').forEach(el => {
-                const hook = this.hooks.get(el.getAttribute('lv-hook'));
-                if (hook && hook[event]) try { hook[event].call(el); } catch (e) {}
-            });
-        } catch (e) {}
+        for (const hook of this._hooked.values()) this._runHook(hook, event);
+        if (event === 'destroyed') {
+            for (const hook of this._hooked.values()) hook._lvRemoveHandlers();
+        }
+    }
+
+    _runHook(hook, event) {
+        if (typeof hook[event] === 'function') {
+            try { hook[event](); } catch (e) { console.error(`GoliveKit: hook ${event} failed`, e); }
+        }
     }
 
     on(event, cb) {
@@ -546,9 +2328,65 @@ class GoliveKit {
     }
 }
 
+// Hooks shared by every client, by lv-hook name. registerHook adds hooks
+// to one client only.
+GoliveKit.hooks = {};
+
+// <golive-view route="/widgets/chat"> renders a Live route inside any page.
+// It connects once scrolled into view (or at once with the eager attribute)
+// to the route's socket, or to the socket attribute's URL when the socket is
+// proxied or served from another origin. The props property, if set before
+// it connects, is sent on join.
+class GoliveView extends HTMLElement {
+    connectedCallback() {
+        if (this.liveView || this._observer) return;
+        if (this.hasAttribute('eager') || typeof IntersectionObserver === 'undefined') {
+            this._start();
+            return;
+        }
+        this._observer = new IntersectionObserver(entries => {
+            if (!entries.some(e => e.isIntersecting)) return;
+            this._observer.disconnect();
+            this._observer = null;
+            this._start();
+        }, { rootMargin: '200px' });
+        this._observer.observe(this);
+    }
+
+    disconnectedCallback() {
+        if (this._observer) this._observer.disconnect();
+        this._observer = null;
+        if (this.liveView) this.liveView.destroy();
+        this.liveView = null;
+    }
+
+    _start() {
+        this.liveView = new GoliveKit({ root: this, url: this._socketURL(), props: this.props || null });
+        // Server events bubble as DOM events, for hosts that are not
+        // GoliveKit pages (see bridge.js)
+        this.liveView.on('*', (payload, binary, event) => {
+            this.dispatchEvent(new CustomEvent('golive:event', { bubbles: true, detail: { event, payload, binary } }));
+        });
+        this.liveView.bindEvents();
+        this.liveView.connect();
+    }
+
+    _socketURL() {
+        const url = new URL(this.getAttribute('socket') || this.getAttribute('route') || '/', location.href);
+        url.protocol = url.protocol === 'https:' ? 'wss:' : url.protocol === 'http:' ? 'ws:' : url.protocol;
+        return url.href;
+    }
+}
+
+if (typeof customElements !== 'undefined' && !customElements.get('golive-view')) {
+    customElements.define('golive-view', GoliveView);
+}
+
 // Create instance and bind events only
 window.liveView = new GoliveKit();
 document.addEventListener('DOMContentLoaded', () => {
+    window.liveView._localizeTimes();
+    window.liveView._refreshRelativeTimes();
     if (document.querySelector('[data-live-view]')) {
         window.liveView.bindEvents();
     }
@@ -0,0 +1,116 @@
+/**
+ * GoliveKit LiveMap - map hook for pkg/livemap
+ *
+ * Renders server-managed markers with Leaflet (window.L) or MapLibre
+ * (window.maplibregl), whichever is loaded. Marker changes arrive as list
+ * operations whose content is the marker JSON.
+ */
+
+(function () {
+    const adapters = {
+        leaflet: {
+            create(el, lat, lng, zoom) {
+                const map = window.L.map(el).setView([lat, lng], zoom);
+                window.L.tileLayer('https://{s}.tile.openstreetmap.org/{z}/{x}/{y}.png', {
+                    attribution: '&copy; OpenStreetMap contributors'
+                }).addTo(map);
+                return map;
+            },
+            add(map, m) {
+                const marker = window.L.marker([m.lat, m.lng], { title: m.label || '' }).addTo(map);
+                if (m.popup) marker.bindPopup(document.createTextNode(m.popup));
+                return marker;
+            },
+            move(marker, m) {
+                marker.setLatLng([m.lat, m.lng]);
+                if (m.popup) marker.bindPopup(document.createTextNode(m.popup));
+            },
+            remove(map, marker) { map.removeLayer(marker); },
+            destroy(map) { map.remove(); }
+        },
+
+        maplibre: {
+            create(el, lat, lng, zoom) {
+                return new window.maplibregl.Map({
+                    container: el,
+                    style: el.dataset.style || 'https://demotiles.maplibre.org/style.json',
+                    center: [lng, lat],
+                    zoom
+                });
+            },
+            add(map, m) {
+                const marker = new window.maplibregl.Marker({ color: m.color || undefined })
+                    .setLngLat([m.lng, m.lat])
+                    .addTo(map);
+                if (m.popup) marker.setPopup(new window.maplibregl.Popup().setText(m.popup));
+                return marker;
+            },
+            move(marker, m) {
+                marker.setLngLat([m.lng, m.lat]);
+                if (m.popup) marker.setPopup(new window.maplibregl.Popup().setText(m.popup));
+            },
+            remove(map, marker) { marker.remove(); },
+            destroy(map) { map.remove(); }
+        }
+    };
+
+    function adapter() {
+        if (window.L && window.L.map) return adapters.leaflet;
+        if (window.maplibregl) return adapters.maplibre;
+        return null;
+    }
+
+    function upsert(hook, m) {
+        const existing = hook.markers.get(m.id);
+        if (existing) {
+            hook.adapter.move(existing, m);
+        } else {
+            hook.markers.set(m.id, hook.adapter.add(hook.map, m));
+        }
+    }
+
+    const LiveMap = {
+        mounted() {
+            const a = adapter();
+            if (!a) return;
+            const el = this.el;
+            this.adapter = a;
+            this.markers = new Map();
+            this.map = a.create(el,
+                parseFloat(el.dataset.lat), parseFloat(el.dataset.lng), parseInt(el.dataset.zoom || '13'));
+            try {
+                JSON.parse(el.dataset.markers || '[]').forEach(m => upsert(this, m));
+            } catch (e) {}
+        },
+
+        listOps(ops) {
+            if (!this.map) return;
+            for (const op of ops) {
+                switch (op.o) {
+                    case 'i':
+                    case 'u':
+                        try { upsert(this, JSON.parse(op.c)); } catch (e) {}
+                        break;
+                    case 'd': {
+                        const marker = this.markers.get(op.k);
+                        if (marker) {
+                            this.adapter.remove(this.map, marker);
+                            this.markers.delete(op.k);
+                        }
+                        break;
+                    }
+                    // 'm' (reorder) has no meaning for map markers.
+                }
+            }
+        },
+
+        destroyed() {
+            if (!this.map) return;
+            this.adapter.destroy(this.map);
+            this.map = null;
+        }
+    };
+
+    if (window.liveView) window.liveView.registerHook('LiveMap', LiveMap);
+    window.GoliveKitLiveMap = LiveMap;
+})();
@@ -0,0 +1,49 @@
+/**
+ * GoliveKit Bridge type definitions (/_live/bridge.js, loaded after
+ * golivekit.js). React and Vue are passed in rather than imported, so their
+ * components are typed loosely.
+ */
+
+/** Options for GoliveBridge.mount. */
+export interface MountOptions {
+    /** Live route to render, e.g. "/live/invoice". */
+    route: string;
+    /** Socket URL when the route is proxied or on another origin. */
+    socket?: string;
+    /** Props sent on join and received by the view as the lv:props event. */
+    props?: Record<string, unknown> | null;
+    /** Called for each event the server pushes to the view. */
+    onEvent?: (event: string, payload: Record<string, unknown>, binary?: ArrayBuffer) => void;
+    /** Connect once scrolled into view instead of at once. */
+    lazy?: boolean;
+}
+
+/** A mounted view. */
+export interface MountHandle {
+    readonly element: HTMLElement;
+    /** Sends new props to the view. */
+    setProps(props: Record<string, unknown>): Promise<unknown>;
+    /** Sends an event to the view. */
+    pushEvent(event: string, payload?: Record<string, unknown>): Promise<unknown>;
+    /** Disconnects and removes the view. */
+    destroy(): void;
+}
+
+export interface GoliveBridge {
+    /** Mounts a Live route into el. */
+    mount(el: HTMLElement, options: MountOptions): MountHandle;
+    /** Returns a React component: <GoliveView route props onEvent lazy className style />. */
+    react(React: any): (props: MountOptions & { className?: string; style?: unknown }) => any;
+    /** Returns a Vue 3 component: <GoliveView route :props @event lazy />. */
+    vue(Vue: any): any;
+}
+
+declare const GoliveBridge: GoliveBridge;
+
+declare global {
+    interface Window {
+        GoliveBridge: GoliveBridge;
+    }
+}
+
+export default GoliveBridge;
@@ -0,0 +1,10 @@
+// Compile-time check that golivekit.d.ts only declares members the client
+// implements, so the definitions cannot drift from src/golivekit.js (npm run
+// typecheck). A failure names the members missing from the implementation.
+import type { GoliveKit } from "./golivekit";
+import Implementation from "../src/golivekit.js";
+
+type NoneMissing<Missing extends never> = Missing;
+
+export type InstanceMembers = NoneMissing<Exclude<keyof GoliveKit, keyof InstanceType<typeof Implementation>>>;
+export type StaticMembers = NoneMissing<Exclude<keyof typeof GoliveKit, keyof typeof Implementation>>;
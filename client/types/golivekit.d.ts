@@ -0,0 +1,197 @@
+/**
+ * GoliveKit - JavaScript Client type definitions.
+ *
+ * The client is loaded as a plain script (/_live/golivekit.js) and exposes a
+ * single instance as window.liveView. These definitions describe its public
+ * API for editors and for apps writing hooks in TypeScript.
+ */
+
+/** Options accepted by the GoliveKit constructor. */
+export interface GoliveKitOptions {
+    /** WebSocket URL. Default: the current page on ws(s)://. */
+    url?: string;
+    /** Heartbeat interval in milliseconds. Default: 30000. */
+    heartbeatInterval?: number;
+    /** Reconnect attempts before giving up. Default: 5. */
+    reconnectMaxAttempts?: number;
+    /** First reconnect delay in milliseconds, doubled per attempt. Default: 1000. */
+    reconnectBaseDelay?: number;
+    /** Maximum reconnect delay in milliseconds. Default: 30000. */
+    reconnectMaxDelay?: number;
+    /** Apply optimistic UI updates before the server replies. Default: true. */
+    optimisticUpdates?: boolean;
+    /** Minimum milliseconds between two identical events. Default: 16. */
+    eventDebounce?: number;
+    /** Element the view renders into, as <golive-view> does. Default: the page. */
+    root?: HTMLElement | null;
+    /** Props sent with the join, received by the view as the lv:props event. */
+    props?: Record<string, unknown> | null;
+    /** Connection transport; 'auto' falls back to SSE when WebSockets are blocked. Default: 'auto'. */
+    transport?: 'auto' | 'websocket' | 'sse';
+    /** Milliseconds to wait for a WebSocket before falling back to SSE. Default: 5000. */
+    sseFallbackTimeout?: number;
+    /** Send the events fired within one animation frame in one message. Default: true. */
+    batchEvents?: boolean;
+    /** Relay lv:shared values to the other tabs of the origin over a BroadcastChannel. Default: false. */
+    tabSync?: boolean;
+}
+
+/**
+ * The this of hook callbacks: one instance per hooked element, created from
+ * the hook, so state set on it stays with the element.
+ */
+export interface HookContext {
+    /** The hooked element. */
+    readonly el: HTMLElement;
+    /** The client the element belongs to. */
+    readonly liveView: GoliveKit;
+    /** Sends an event to the view, or to the LiveComponent the element is in. Resolves like GoliveKit.pushEvent. */
+    pushEvent(event: string, payload?: Record<string, unknown>): Promise<unknown>;
+    /**
+     * Listens to an event pushed by the server (Socket.PushEvent, or
+     * Socket.PushEventTo an element of this hook) until the hook is destroyed.
+     */
+    handleEvent(event: string, listener: ServerEventListener): HandleEventRef;
+    /** Stops listening with a handle returned by handleEvent. */
+    removeHandleEvent(ref: HandleEventRef): void;
+    [key: string]: unknown;
+}
+
+/** Handle returned by HookContext.handleEvent. */
+export type HandleEventRef = unknown;
+
+/** Lifecycle callback of a hook. */
+export type HookCallback = (this: HookContext) => void;
+
+/** A hook attached to elements with lv-hook="Name". */
+export interface Hook {
+    /** Called when the element is added, or when the view joined. */
+    mounted?: HookCallback;
+    /** Called before a diff is applied. */
+    beforeUpdate?: HookCallback;
+    /** Called after a diff was applied. */
+    updated?: HookCallback;
+    /** Called when the element was removed, or the client destroyed. */
+    destroyed?: HookCallback;
+    /** Called when the connection is lost. */
+    disconnected?: HookCallback;
+    /** Called when the view joined again after a lost connection. */
+    reconnected?: HookCallback;
+    /** Receives the operations of the data-list the element holds, instead of the DOM. */
+    listOps?: (this: HookContext, ops: Array<Record<string, unknown>>) => void;
+}
+
+/**
+ * Listener for an event pushed by the server (Socket.Push). Events the client
+ * handles itself (diffs, lv:head, lv:redirect...) are not delivered to
+ * listeners. binary is set for binary frames; event is the event name, useful
+ * for "*" listeners.
+ */
+export type ServerEventListener = (payload: Record<string, unknown>, binary: ArrayBuffer | undefined, event: string) => void;
+
+/**
+ * A user error returned by a handler (core.UserError), delivered to "lv:error"
+ * listeners after the client showed it in [lv-error-toast] and
+ * [lv-error-for="field"] elements.
+ */
+export interface UserErrorPayload {
+    code: string;
+    message: string;
+    fields?: Record<string, string>;
+}
+
+/**
+ * Delivered to "lv:takeover" listeners when another connection took the
+ * session over: "tab" for a newer tab of a single-session route
+ * (router.WithSingleSession), "reconnect" for the same page rejoining.
+ */
+export interface TakeoverPayload {
+    reason: 'tab' | 'reconnect';
+}
+
+/** A board as used by GoliveKit.applyBoardDiff. */
+export interface Board {
+    width: number;
+    height: number;
+    cells: Uint8Array;
+}
+
+/** The GoliveKit client. */
+export declare class GoliveKit {
+    constructor(options?: GoliveKitOptions);
+
+    readonly options: Required<GoliveKitOptions>;
+    readonly connected: boolean;
+    readonly joined: boolean;
+
+    /** Opens the socket and joins the view. */
+    connect(): Promise<void>;
+    /** Closes the socket without reconnecting. */
+    disconnect(): void;
+    /** Disconnects and removes the listeners added by bindEvents. */
+    destroy(): void;
+    /** Binds lv-* attributes in the document. */
+    bindEvents(): void;
+
+    /** Sends an event to the view. Resolves with the handler's core.Reply value, or undefined. */
+    pushEvent(event: string, payload?: Record<string, unknown>): Promise<unknown>;
+    /** Sends an event with a binary attachment, received as payload["binary"]. */
+    pushBinary(event: string, payload: Record<string, unknown>, data: ArrayBuffer | ArrayBufferView): Promise<unknown>;
+    /** Runs JS commands (package js), acting on el when they have no target. */
+    execJS(ops: Array<[string, Record<string, unknown>]>, el?: Element | null): void;
+    /** Sets shared state (Socket.PushShared) from the page and relays it to the other tabs. */
+    share(values: Record<string, unknown>): void;
+    /** Returns the last value of a shared key, from this tab or another one. */
+    shared(key: string): unknown;
+    /** Sends new props to the view as the lv:props event. */
+    setProps(props: Record<string, unknown>): Promise<unknown>;
+    /** Queues an input sent with the others of the same animation frame as "input_batch". */
+    queueInput(type: string, data?: Record<string, unknown>): void;
+
+    /** Hooks shared by every client, by lv-hook name. */
+    static hooks: Record<string, Hook>;
+
+    /** Registers a hook for elements with lv-hook="name" of this client. */
+    registerHook(name: string, hook: Hook): void;
+
+    /** Listens to an event pushed by the server, or to all of them with "*". */
+    on(event: string, listener: ServerEventListener): void;
+    /** Removes a listener added with on. */
+    off(event: string, listener: ServerEventListener): void;
+
+    /** Applies a binary board diff (gameloop.EncodeDiff) and returns the board. */
+    static applyBoardDiff(board: Board, buf: ArrayBuffer): Board;
+}
+
+/**
+ * <golive-view route="/widgets/chat">: renders a Live route inside any page.
+ * Attributes: route, socket (socket URL when proxied or on another origin),
+ * eager (connect at once instead of when scrolled into view).
+ */
+export interface GoliveView extends HTMLElement {
+    /** The view's client, once connected. */
+    readonly liveView: GoliveKit | null;
+    /** Props sent when the view joins. */
+    props: Record<string, unknown> | null;
+}
+
+/** Detail of the bubbling "golive:event" DOM event a <golive-view> dispatches. */
+export interface GoliveEventDetail {
+    event: string;
+    payload: Record<string, unknown>;
+    binary?: ArrayBuffer;
+}
+
+declare global {
+    interface Window {
+        liveView: GoliveKit;
+    }
+    interface HTMLElementTagNameMap {
+        "golive-view": GoliveView;
+    }
+    interface HTMLElementEventMap {
+        "golive:event": CustomEvent<GoliveEventDetail>;
+    }
+}
+
+export default GoliveKit;
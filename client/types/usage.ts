@@ -0,0 +1,37 @@
+// Compile-time checks for golivekit.d.ts (npm run typecheck).
+import GoliveKit, { Hook } from "./golivekit";
+import "./bridge";
+
+const Chart: Hook = {
+    mounted() {
+        this.el.dataset.ready = "true";
+        this.handleEvent("points", (p) => this.pushEvent("drawn", { n: p["n"] }));
+    },
+    updated() {
+        this.el.classList.add("updated");
+    },
+};
+
+window.liveView.registerHook("Chart", Chart);
+GoliveKit.hooks.Chart = Chart;
+window.liveView.on("score", (p) => console.log(p["value"]));
+window.liveView.pushEvent("inc", { by: 1 }).then(() => undefined);
+
+const board = GoliveKit.applyBoardDiff({ width: 1, height: 1, cells: new Uint8Array(1) }, new ArrayBuffer(6));
+board.cells[0] = 1;
+
+const widget = document.createElement("golive-view");
+widget.setAttribute("route", "/widgets/chat");
+widget.liveView?.pushEvent("hello");
+
+widget.props = { id: 7 };
+widget.addEventListener("golive:event", (e) => console.log(e.detail.event));
+window.liveView.on("*", (payload, _binary, event) => console.log(event, payload));
+window.liveView.setProps({ id: 8 });
+
+const handle = window.GoliveBridge.mount(document.body, {
+    route: "/live/invoice",
+    props: { id: 7 },
+    onEvent: (event, payload) => console.log(event, payload),
+});
+handle.setProps({ id: 8 }).then(() => handle.destroy());
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// adminModel is a model found by "golive generate admin": an exported
+// struct with a changeset function.
+type adminModel struct {
+	typeName  string
+	changeset string
+	fields    []adminField
+}
+
+// adminField is an editable struct field.
+type adminField struct {
+	name     string // form name
+	label    string
+	ctor     string // forms constructor, "TextField"
+	required bool
+}
+
+// runGenerateAdmin implements "golive generate admin [flags] [models dir]".
+// Models are the exported structs of the package that have a
+//
+//	func <Type>Changeset(data, params map[string]any) *forms.Changeset
+//
+// Fields are named by their form or json tag (default: snake_case) and typed
+// from their Go type; the admin tag tunes them:
+//
+//	Body  string `admin:"textarea,required"`
+//	Token string `admin:"-"`
+func runGenerateAdmin(args []string) error {
+	flags := flag.NewFlagSet("generate admin", flag.ContinueOnError)
+	out := flags.String("out", filepath.Join("internal", "adminsite"), "output directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	dir := filepath.Join("internal", "models")
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	pkgName, models, err := parseAdminModels(dir)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		return fmt.Errorf("no models with a <Type>Changeset function in %s", dir)
+	}
+
+	modulePath, err := readModulePath("go.mod")
+	if err != nil {
+		return err
+	}
+	importPath := modulePath + "/" + filepath.ToSlash(filepath.Clean(dir))
+
+	src, err := generateAdmin(filepath.Base(*out), importPath, pkgName, models)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*out, "site.go")
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		return err
+	}
+
+	for _, m := range models {
+		fmt.Printf("  %s (%d fields)\n", m.typeName, len(m.fields))
+	}
+	fmt.Printf("✅ Created %s\n", path)
+	return nil
+}
+
+// parseAdminModels finds the models of the package in dir.
+func parseAdminModels(dir string) (string, []adminModel, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgName := ""
+	structs := make(map[string]*ast.StructType)
+	changesets := make(map[string]string)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkgName = file.Name.Name
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || !ts.Name.IsExported() {
+						continue
+					}
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						structs[ts.Name.Name] = st
+					}
+				}
+			case *ast.FuncDecl:
+				name := d.Name.Name
+				if d.Recv == nil && strings.HasSuffix(name, "Changeset") && isChangesetFunc(d.Type) {
+					changesets[strings.TrimSuffix(name, "Changeset")] = name
+				}
+			}
+		}
+	}
+
+	var models []adminModel
+	for typeName, st := range structs {
+		changeset, ok := changesets[typeName]
+		if !ok {
+			continue
+		}
+		models = append(models, adminModel{
+			typeName:  typeName,
+			changeset: changeset,
+			fields:    adminFields(st),
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].typeName < models[j].typeName })
+	return pkgName, models, nil
+}
+
+// isChangesetFunc reports whether ft is
+// func(data, params map[string]any) *forms.Changeset.
+func isChangesetFunc(ft *ast.FuncType) bool {
+	if ft.Params.NumFields() != 2 || ft.Results.NumFields() != 1 {
+		return false
+	}
+	for _, p := range ft.Params.List {
+		if _, ok := p.Type.(*ast.MapType); !ok {
+			return false
+		}
+	}
+	star, ok := ft.Results.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Changeset"
+}
+
+// adminFields returns the editable fields of a model struct.
+func adminFields(st *ast.StructType) []adminField {
+	var fields []adminField
+	for _, f := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if f.Tag != nil {
+			if s, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tag = reflect.StructTag(s)
+			}
+		}
+		opts := strings.Split(tag.Get("admin"), ",")
+		if opts[0] == "-" {
+			continue
+		}
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			name := tagName(tag, ident.Name)
+			if name == "-" || name == "id" {
+				continue
+			}
+			ctor := fieldConstructor(f.Type, name)
+			if ctor == "" {
+				continue
+			}
+			field := adminField{name: name, label: humanize(ident.Name), ctor: ctor}
+			for _, opt := range opts {
+				switch opt {
+				case "required":
+					field.required = true
+				case "textarea":
+					field.ctor = "TextareaField"
+				}
+			}
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// tagName returns the field's name from its form or json tag.
+func tagName(tag reflect.StructTag, goName string) string {
+	for _, key := range []string{"form", "json"} {
+		if name, _, _ := strings.Cut(tag.Get(key), ","); name != "" {
+			return name
+		}
+	}
+	return snakeName(goName)
+}
+
+// snakeName is humanize's words joined by underscores: "AuthorID" is
+// "author_id".
+func snakeName(goName string) string {
+	return strings.ToLower(strings.ReplaceAll(humanize(goName), " ", "_"))
+}
+
+// fieldConstructor maps a Go type to a forms constructor, or "" for types
+// the admin does not edit.
+func fieldConstructor(expr ast.Expr, name string) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			switch {
+			case strings.Contains(name, "email"):
+				return "EmailField"
+			case strings.HasSuffix(name, "url"):
+				return "URLField"
+			case strings.Contains(name, "password"):
+				return "" // never shown, not even hashed
+			}
+			return "TextField"
+		case "bool":
+			return "CheckboxField"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+			return "NumberField"
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok && x.Name == "time" && t.Sel.Name == "Time" {
+			return "DateField"
+		}
+	}
+	return ""
+}
+
+// humanize turns a Go name into a label: "PublishedAt" is "Published at",
+// "AuthorID" is "Author ID".
+func humanize(s string) string {
+	var words []string
+	start := 0
+	runes := []rune(s)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	for i := 1; i < len(words); i++ {
+		if strings.ToUpper(words[i]) != words[i] || len(words[i]) == 1 {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// readModulePath returns the module path declared in a go.mod file.
+func readModulePath(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("run from the module root: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no module directive", path)
+}
+
+// generateAdmin emits the site: a Repos struct with a repository per model
+// and NewSite registering them.
+func generateAdmin(pkg, importPath, modelsPkg string, models []adminModel) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by golive generate admin. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	alias := ""
+	if path.Base(importPath) != modelsPkg {
+		alias = modelsPkg + " "
+	}
+	fmt.Fprintf(&b, "import (\n\t\"github.com/gabrielmiguelok/golivekit/pkg/admin\"\n\t\"github.com/gabrielmiguelok/golivekit/pkg/forms\"\n\n\t%s%q\n)\n\n", alias, importPath)
+
+	b.WriteString("// Repos holds the repository of each model.\ntype Repos struct {\n")
+	for _, m := range models {
+		fmt.Fprintf(&b, "\t%s admin.Repo\n", m.typeName)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// NewSite returns the admin site for the models. Set Authorize before\n// mounting it: the site denies everyone until then.\n")
+	b.WriteString("func NewSite(repos Repos) *admin.Site {\n\tsite := admin.NewSite(\"Admin\")\n")
+	for _, m := range models {
+		fmt.Fprintf(&b, "\tsite.Register(admin.Model{\n\t\tName: %q,\n\t\tFields: []forms.Field{\n", snakeName(m.typeName))
+		for _, f := range m.fields {
+			opt := ""
+			if f.required {
+				opt = ", forms.WithRequired()"
+			}
+			fmt.Fprintf(&b, "\t\t\tforms.%s(%q, %q%s),\n", f.ctor, f.name, f.label, opt)
+		}
+		fmt.Fprintf(&b, "\t\t},\n\t\tRepo: repos.%s,\n\t\tChangeset: %s.%s,\n\t})\n", m.typeName, modelsPkg, m.changeset)
+	}
+	b.WriteString("\treturn site\n}\n")
+
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/i18n"
+)
+
+// errMissingTranslations is returned by --check when keys are untranslated.
+var errMissingTranslations = errors.New("missing translations")
+
+// templateKeyPattern matches {{t "key"}} and {{tn "key" .Count}} in templates.
+var templateKeyPattern = regexp.MustCompile(`\{\{-?\s*(t|tn)\s+"([^"]+)"`)
+
+// extractedKey is a translation key found in source.
+type extractedKey struct {
+	plural bool
+	files  map[string]bool
+}
+
+func runI18n(args []string) error {
+	if len(args) < 1 || args[0] != "extract" {
+		return fmt.Errorf("usage: golive i18n extract [flags]")
+	}
+
+	flags := flag.NewFlagSet("i18n extract", flag.ContinueOnError)
+	src := flags.String("src", ".", "directory to scan for T(...) calls")
+	dir := flags.String("dir", "locales", "directory holding locale files")
+	format := flags.String("format", "json", "locale file format: json or po")
+	langs := flags.String("locales", "", "comma-separated locales (default: existing files)")
+	check := flags.Bool("check", false, "do not write files; fail if translations are missing")
+	prune := flags.Bool("prune", false, "remove unused keys from locale files")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "po" {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	keys, err := extractKeys(*src)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🔎 Found %d translation keys in %s\n", len(keys), *src)
+
+	locales := splitList(*langs)
+	if len(locales) == 0 {
+		locales = existingLocales(*dir, *format)
+	}
+	if len(locales) == 0 {
+		return fmt.Errorf("no locales found in %s (use --locales en,es)", *dir)
+	}
+
+	missingTotal := 0
+	for _, locale := range locales {
+		path := filepath.Join(*dir, locale+"."+*format)
+		existing, err := readLocaleFile(path, *format)
+		if err != nil {
+			return err
+		}
+
+		expected := expandKeys(keys, locale)
+		merged := make(map[string]string, len(expected))
+		var missing, unused []string
+
+		for key := range expected {
+			value := existing[key]
+			if value == "" {
+				missing = append(missing, key)
+			}
+			merged[key] = value
+		}
+		for key, value := range existing {
+			if _, ok := expected[key]; !ok {
+				unused = append(unused, key)
+				if !*prune {
+					merged[key] = value
+				}
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(unused)
+		missingTotal += len(missing)
+
+		fmt.Printf("\n🌐 %s: %d keys, %d missing, %d unused\n", locale, len(expected), len(missing), len(unused))
+		for _, key := range missing {
+			fmt.Printf("   - missing: %s\n", key)
+		}
+		for _, key := range unused {
+			fmt.Printf("   - unused:  %s\n", key)
+		}
+
+		if *check {
+			continue
+		}
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			return err
+		}
+		if err := writeLocaleFile(path, *format, merged); err != nil {
+			return err
+		}
+	}
+
+	if *check && missingTotal > 0 {
+		return fmt.Errorf("%w: %d keys", errMissingTranslations, missingTotal)
+	}
+	if !*check {
+		fmt.Printf("\n✅ Locale files updated in %s\n", *dir)
+	}
+	return nil
+}
+
+// extractKeys scans Go files and templates under root for translation keys.
+func extractKeys(root string) (map[string]*extractedKey, error) {
+	keys := make(map[string]*extractedKey)
+	add := func(key, file string, plural bool) {
+		k, ok := keys[key]
+		if !ok {
+			k = &extractedKey{files: make(map[string]bool)}
+			keys[key] = k
+		}
+		k.plural = k.plural || plural
+		k.files[file] = true
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "dist") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".go":
+			if strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			return extractGoKeys(path, add)
+		case ".html", ".tmpl", ".gohtml":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, m := range templateKeyPattern.FindAllStringSubmatch(string(data), -1) {
+				add(m[2], path, m[1] == "tn")
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// extractGoKeys finds T/TPlural/TLocale calls whose key is a string literal.
+// Package-level helpers take a context first (i18n.T(ctx, "key")); methods
+// take the key first (t.T("key")); TLocale takes the locale first.
+func extractGoKeys(path string, add func(key, file string, plural bool)) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil // not our job to report syntax errors
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		var name string
+		switch fn := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			name = fn.Sel.Name
+		case *ast.Ident:
+			name = fn.Name
+		}
+		if name != "T" && name != "TPlural" && name != "TLocale" {
+			return true
+		}
+
+		// The key is the first string literal among the first two arguments.
+		for i := 0; i < len(call.Args) && i < 2; i++ {
+			lit, ok := call.Args[i].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			if name == "TLocale" && i == 0 {
+				continue
+			}
+			if key, err := strconv.Unquote(lit.Value); err == nil && key != "" {
+				add(key, path, name == "TPlural")
+			}
+			break
+		}
+		return true
+	})
+	return nil
+}
+
+// expandKeys returns the keys expected in a locale file. Plural keys expand
+// to one entry per CLDR category used by the locale ("items.one", "items.other").
+func expandKeys(keys map[string]*extractedKey, locale string) map[string]bool {
+	forms := pluralCategories(locale)
+	out := make(map[string]bool, len(keys))
+	for key, k := range keys {
+		if !k.plural {
+			out[key] = true
+			continue
+		}
+		for _, form := range forms {
+			out[key+"."+form] = true
+		}
+	}
+	return out
+}
+
+// pluralCategories lists the plural categories a locale uses for integers.
+func pluralCategories(locale string) []string {
+	seen := make(map[string]bool)
+	var forms []string
+	for n := 0; n <= 200; n++ {
+		if f := i18n.PluralForm(locale, n); !seen[f] {
+			seen[f] = true
+			forms = append(forms, f)
+		}
+	}
+	sort.Strings(forms)
+	return forms
+}
+
+func existingLocales(dir, format string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*."+format))
+	locales := make([]string, 0, len(matches))
+	for _, m := range matches {
+		locales = append(locales, strings.TrimSuffix(filepath.Base(m), "."+format))
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func readLocaleFile(path, format string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "po" {
+		return parsePO(string(data)), nil
+	}
+
+	out := make(map[string]string)
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return out, nil
+}
+
+func writeLocaleFile(path, format string, entries map[string]string) error {
+	if format == "po" {
+		return os.WriteFile(path, []byte(formatPO(entries)), 0644)
+	}
+
+	// encoding/json sorts map keys, keeping diffs stable.
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// parsePO reads msgid/msgstr pairs from a gettext PO file. Only single-line
+// and continued-string entries are supported; comments are ignored.
+func parsePO(data string) map[string]string {
+	out := make(map[string]string)
+	var id, str *string
+	var msgid, msgstr string
+	flush := func() {
+		if msgid != "" {
+			out[msgid] = msgstr
+		}
+		msgid, msgstr = "", ""
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "msgid "):
+			flush()
+			msgid = unquotePO(strings.TrimPrefix(line, "msgid "))
+			id, str = &msgid, nil
+		case strings.HasPrefix(line, "msgstr "):
+			msgstr = unquotePO(strings.TrimPrefix(line, "msgstr "))
+			id, str = nil, &msgstr
+		case strings.HasPrefix(line, `"`):
+			if str != nil {
+				*str += unquotePO(line)
+			} else if id != nil {
+				*id += unquotePO(line)
+			}
+		}
+	}
+	flush()
+	return out
+}
+
+func unquotePO(s string) string {
+	v, err := strconv.Unquote(strings.TrimSpace(s))
+	if err != nil {
+		return ""
+	}
+	return v
+}
+
+func formatPO(entries map[string]string) string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "\nmsgid %s\nmsgstr %s\n", strconv.Quote(k), strconv.Quote(entries[k]))
+	}
+	return sb.String()
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeSources lays out a small app using every supported call form.
+func writeSources(t *testing.T, root string) {
+	t.Helper()
+	writeFile(t, filepath.Join(root, "app.go"), `package app
+
+import "github.com/gabrielmiguelok/golivekit/pkg/i18n"
+
+func render(ctx context.Context, t *i18n.Translator, n int) {
+	i18n.T(ctx, "home.title")
+	t.T("home.subtitle")
+	t.TPlural("cart.items", n)
+	i18n.TLocale("es", "home.footer")
+	t.T(dynamicKey)
+}
+`)
+	writeFile(t, filepath.Join(root, "views", "index.html"),
+		`<h1>{{t "nav.home"}}</h1><p>{{- tn "inbox.unread" .Count}}</p>`)
+	writeFile(t, filepath.Join(root, "app_test.go"), `package app
+func x() { T("test.only") }
+`)
+	writeFile(t, filepath.Join(root, "vendor", "dep", "dep.go"), `package dep
+func x() { T("vendor.only") }
+`)
+	writeFile(t, filepath.Join(root, "broken.go"), `package app
+func {`)
+}
+
+func TestExtractKeys(t *testing.T) {
+	root := t.TempDir()
+	writeSources(t, root)
+
+	keys, err := extractKeys(root)
+	if err != nil {
+		t.Fatalf("extractKeys: %v", err)
+	}
+
+	want := map[string]bool{
+		"home.title":    false,
+		"home.subtitle": false,
+		"cart.items":    true,
+		"home.footer":   false,
+		"nav.home":      false,
+		"inbox.unread":  true,
+	}
+	if len(keys) != len(want) {
+		t.Errorf("found %d keys, want %d: %v", len(keys), len(want), sortedKeys(keys))
+	}
+	for key, plural := range want {
+		k, ok := keys[key]
+		if !ok {
+			t.Errorf("key %q not found", key)
+			continue
+		}
+		if k.plural != plural {
+			t.Errorf("key %q plural = %v, want %v", key, k.plural, plural)
+		}
+	}
+}
+
+func TestExpandKeys(t *testing.T) {
+	keys := map[string]*extractedKey{
+		"title": {},
+		"items": {plural: true},
+	}
+
+	got := expandKeys(keys, "en")
+	want := map[string]bool{"title": true, "items.one": true, "items.other": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandKeys(en) = %v, want %v", got, want)
+	}
+
+	if got := expandKeys(keys, "ru"); len(got) <= len(want) {
+		t.Errorf("expandKeys(ru) = %v, want more plural forms than en", got)
+	}
+}
+
+func TestRunI18n_MergesIntoExistingCatalog(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	dir := filepath.Join(root, "locales")
+	writeSources(t, src)
+	writeFile(t, filepath.Join(dir, "es.json"), `{
+  "home.title": "Inicio",
+  "cart.items.one": "1 artículo",
+  "old.banner": "Oferta"
+}`)
+
+	if err := runI18n([]string{"extract", "--src", src, "--dir", dir}); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+
+	got, err := readLocaleFile(filepath.Join(dir, "es.json"), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"home.title":         "Inicio",
+		"home.subtitle":      "",
+		"home.footer":        "",
+		"nav.home":           "",
+		"cart.items.one":     "1 artículo",
+		"cart.items.other":   "",
+		"inbox.unread.one":   "",
+		"inbox.unread.other": "",
+		"old.banner":         "Oferta",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged catalog = %v, want %v", got, want)
+	}
+
+	// --prune drops the stale key and keeps existing translations.
+	if err := runI18n([]string{"extract", "--src", src, "--dir", dir, "--prune"}); err != nil {
+		t.Fatalf("extract --prune: %v", err)
+	}
+	got, err = readLocaleFile(filepath.Join(dir, "es.json"), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["old.banner"]; ok {
+		t.Error("stale key kept with --prune")
+	}
+	if got["home.title"] != "Inicio" {
+		t.Errorf("home.title = %q after prune, want %q", got["home.title"], "Inicio")
+	}
+}
+
+func TestRunI18n_Check(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	dir := filepath.Join(root, "locales")
+	writeFile(t, filepath.Join(src, "app.go"), `package app
+func x() { T("greeting") }
+`)
+	path := filepath.Join(dir, "en.json")
+	writeFile(t, path, `{"stale": "x"}`)
+
+	err := runI18n([]string{"extract", "--src", src, "--dir", dir, "--check"})
+	if !errors.Is(err, errMissingTranslations) {
+		t.Fatalf("check error = %v, want errMissingTranslations", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != `{"stale": "x"}` {
+		t.Errorf("--check rewrote the catalog: %s", data)
+	}
+
+	writeFile(t, path, `{"greeting": "Hello", "stale": "x"}`)
+	if err := runI18n([]string{"extract", "--src", src, "--dir", dir, "--check"}); err != nil {
+		t.Errorf("check with complete catalog: %v", err)
+	}
+}
+
+func TestRunI18n_NewLocalePO(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	dir := filepath.Join(root, "locales")
+	writeFile(t, filepath.Join(src, "app.go"), `package app
+func x() { T("greeting") }
+`)
+
+	if err := runI18n([]string{"extract", "--src", src, "--dir", dir}); err == nil {
+		t.Error("extract with no locales succeeded, want error")
+	}
+
+	if err := runI18n([]string{"extract", "--src", src, "--dir", dir, "--format", "po", "--locales", "fr"}); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	got, err := readLocaleFile(filepath.Join(dir, "fr.po"), "po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"greeting": ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fr.po = %v, want %v", got, want)
+	}
+}
+
+func TestPORoundTrip(t *testing.T) {
+	entries := map[string]string{
+		"greeting":  "Bonjour",
+		"quote":     `Il a dit "oui"`,
+		"multiline": "ligne 1\nligne 2",
+		"empty":     "",
+	}
+	if got := parsePO(formatPO(entries)); !reflect.DeepEqual(got, entries) {
+		t.Errorf("parsePO(formatPO(x)) = %v, want %v", got, entries)
+	}
+
+	continued := `# comment
+msgid ""
+msgstr "Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "long"
+msgstr ""
+"first "
+"second"
+`
+	if got := parsePO(continued); got["long"] != "first second" || len(got) != 1 {
+		t.Errorf("parsePO(continued) = %v", got)
+	}
+}
+
+func sortedKeys(keys map[string]*extractedKey) []string {
+	out := make([]string, 0, len(keys))
+	for k := range keys {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"syscall"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/client"
 )
 
 var version = "0.1.0"
@@ -50,7 +52,7 @@ func main() {
 		if len(os.Args) < 3 {
 			fmt.Println("Error: generator type required")
 			fmt.Println("Usage: golive generate <type> <name>")
-			fmt.Println("Types: component, live, scaffold")
+			fmt.Println("Types: component, live, scaffold, render, types, admin")
 			os.Exit(1)
 		}
 		if err := runGenerate(os.Args[2:]); err != nil {
@@ -58,6 +60,24 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "migrate":
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "seed":
+		if err := runSeed(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "i18n":
+		if err := runI18n(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
 	case "version", "-v", "--version":
 		fmt.Printf("GoliveKit CLI v%s\n", version)
 
@@ -80,7 +100,10 @@ Commands:
   new <name>           Create a new GoliveKit project
   dev                  Start development server with hot reload
   build                Build for production
-  generate <type>      Generate code (component, live, scaffold)
+  generate <type>      Generate code (component, live, scaffold, render, types, admin)
+  migrate <command>    Manage SQL migrations (new, up, down, status)
+  seed [names]         Load development data from cmd/seed
+  i18n extract         Extract translation keys into locale files
   version              Show version
   help                 Show this help
 
@@ -90,6 +113,13 @@ Examples:
   golive build
   golive generate component Counter
   golive generate live ChatRoom
+  golive generate render ./components
+  golive generate types web/static
+  golive generate admin internal/models
+  golive migrate new create_users
+  golive migrate up -env production
+  golive seed users posts
+  golive i18n extract --locales en,es --check
 
 For more information, visit: https://github.com/gabrielmiguelok/golivekit
 `, version)
@@ -370,6 +400,7 @@ func runDev() error {
 	}
 
 	fmt.Printf("📁 Main file: %s\n", mainFile)
+	checkMigrations()
 	fmt.Println("👀 Watching for file changes...")
 	fmt.Println("🌐 Server will start at http://localhost:3000")
 	fmt.Println("Press Ctrl+C to stop")
@@ -545,6 +576,15 @@ func runBuild() error {
 }
 
 func runGenerate(args []string) error {
+	if len(args) > 0 && args[0] == "render" {
+		return runGenerateRender(args[1:])
+	}
+	if len(args) > 0 && args[0] == "types" {
+		return generateTypes(args[1:])
+	}
+	if len(args) > 0 && args[0] == "admin" {
+		return runGenerateAdmin(args[1:])
+	}
 	if len(args) < 2 {
 		return fmt.Errorf("name required")
 	}
@@ -564,6 +604,24 @@ func runGenerate(args []string) error {
 	}
 }
 
+// generateTypes writes the client's TypeScript definitions into dir
+// (default: web/static), for editor support when writing hooks.
+func generateTypes(args []string) error {
+	dir := filepath.Join("web", "static")
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "golivekit.d.ts")
+	if err := os.WriteFile(path, client.Types(), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Created %s\n", path)
+	return nil
+}
+
 func generateComponent(name string) error {
 	fmt.Printf("Generating component: %s\n", name)
 
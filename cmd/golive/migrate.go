@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+// migrateCommand is the application's migration binary, which registers
+// its database drivers and embeds the migrations.
+const migrateCommand = "./cmd/migrate"
+
+// runMigrate implements "golive migrate new|up|down|status".
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: golive migrate new <name> | up | down [n] | status [-check]")
+	}
+	switch args[0] {
+	case "new":
+		return newMigration(args[1:])
+	case "up", "down", "status":
+		return runMigrateCommand(args)
+	default:
+		// Flags such as -env come first: pass everything through
+		if strings.HasPrefix(args[0], "-") {
+			return runMigrateCommand(args)
+		}
+		return fmt.Errorf("unknown migrate command: %s", args[0])
+	}
+}
+
+// runMigrateCommand runs cmd/migrate with args, which uses migrate.Main.
+func runMigrateCommand(args []string) error {
+	if _, err := os.Stat(migrateCommand); err != nil {
+		return fmt.Errorf("%s not found: create it with \"golive migrate new <name>\"", migrateCommand)
+	}
+	cmd := exec.Command("go", append([]string{"run", migrateCommand}, args...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// newMigration writes an empty up/down pair, creating golive.toml, the
+// migrations package and cmd/migrate on first use.
+func newMigration(args []string) error {
+	flags := flag.NewFlagSet("migrate new", flag.ContinueOnError)
+	dbName := flags.String("db", "main", "database in golive.toml")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: golive migrate new [-db name] <name>")
+	}
+	name := strings.ToLower(strings.ReplaceAll(flags.Arg(0), "-", "_"))
+	if !migrationName.MatchString(name) {
+		return fmt.Errorf("invalid migration name %q: use letters, digits and underscores", flags.Arg(0))
+	}
+
+	if _, err := os.Stat(migrate.ConfigFile); os.IsNotExist(err) {
+		if err := os.WriteFile(migrate.ConfigFile, []byte(defaultGoliveTOML), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Created %s (SQLite; set the driver and connection strings)\n", migrate.ConfigFile)
+	}
+	cfg, err := migrate.LoadConfig(migrate.ConfigFile, migrate.DefaultEnv())
+	if err != nil {
+		return err
+	}
+	db, ok := cfg.Database(*dbName)
+	if !ok {
+		return fmt.Errorf("%s has no [databases.%s]", migrate.ConfigFile, *dbName)
+	}
+
+	if err := os.MkdirAll(db.Dir, 0755); err != nil {
+		return err
+	}
+	embedFile := filepath.Join(db.Dir, "embed.go")
+	if _, err := os.Stat(embedFile); os.IsNotExist(err) {
+		pkg := filepath.Base(db.Dir)
+		src := fmt.Sprintf("// Package %s embeds the SQL migrations of the %s database.\npackage %s\n\nimport \"embed\"\n\n// FS holds the migrations, applied by cmd/migrate.\n//\n//go:embed *.sql\nvar FS embed.FS\n", pkg, db.Name, pkg)
+		if err := os.WriteFile(embedFile, []byte(src), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Created %s\n", embedFile)
+	}
+
+	version := time.Now().UTC().Format("20060102150405")
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(db.Dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		body := fmt.Sprintf("-- %s: %s\n", name, direction)
+		if err := os.WriteFile(file, []byte(body), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Created %s\n", file)
+	}
+
+	if _, err := os.Stat(migrateCommand); os.IsNotExist(err) {
+		return scaffoldMigrateCommand(db)
+	}
+	return nil
+}
+
+// scaffoldMigrateCommand writes cmd/migrate for the first database.
+func scaffoldMigrateCommand(db migrate.Database) error {
+	modulePath, err := readModulePath("go.mod")
+	if err != nil {
+		return err
+	}
+	importPath := modulePath + "/" + filepath.ToSlash(filepath.Clean(db.Dir))
+
+	src := fmt.Sprintf(`// Command migrate applies the SQL migrations of the databases in
+// golive.toml. Run it with "golive migrate up|down|status".
+package main
+
+import (
+	"io/fs"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+
+	// Import the database/sql driver named in golive.toml, e.g.
+	// _ "github.com/jackc/pgx/v5/stdlib"
+	// _ "github.com/go-sql-driver/mysql"
+	// _ "modernc.org/sqlite"
+
+	%q
+)
+
+func main() {
+	migrate.Main(map[string]fs.FS{
+		%q: %s.FS,
+	})
+}
+`, importPath, db.Name, path.Base(importPath))
+
+	dir := filepath.FromSlash(migrateCommand)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Created %s (import your database driver there)\n", file)
+	return nil
+}
+
+// checkMigrations runs at golive dev startup: it applies pending migrations
+// when golive.toml sets [migrate] auto, and otherwise warns about them.
+func checkMigrations() {
+	if _, err := os.Stat(migrateCommand); err != nil {
+		return
+	}
+	cfg, err := migrate.LoadConfig(migrate.ConfigFile, migrate.DefaultEnv())
+	if err != nil {
+		fmt.Printf("⚠️  Migrations not checked: %v\n", err)
+		return
+	}
+
+	if cfg.Auto {
+		fmt.Println("🗄️  Applying migrations...")
+		if err := runMigrateCommand([]string{"up"}); err != nil {
+			fmt.Printf("⚠️  Migrations failed: %v\n", err)
+		}
+		return
+	}
+
+	cmd := exec.Command("go", "run", migrateCommand, "status", "-check")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		fmt.Printf("⚠️  Pending migrations (run \"golive migrate up\", or set auto = true under [migrate] in %s):\n%s", migrate.ConfigFile, out)
+	}
+}
+
+const defaultGoliveTOML = `# GoliveKit project configuration
+
+[migrate]
+auto = false # apply pending migrations when golive dev starts
+
+[databases.main]
+driver = "sqlite" # database/sql driver name
+dir = "migrations"
+
+[environments.development]
+main = "file:dev.db"
+
+[environments.production]
+main = "${DATABASE_URL}"
+`
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// renderTemplateExt is the extension of templates compiled by
+// "golive generate render".
+const renderTemplateExt = ".golive.html"
+
+// renderAnnotation names the component a template renders:
+// <!-- golive:render Counter -->
+var renderAnnotation = regexp.MustCompile(`^\s*<!--\s*golive:render\s+([A-Za-z_]\w*)\s*-->[ \t]*\r?\n?`)
+
+// renderExprPattern matches { .Field }, { .Field.Sub }, { .Method() } and the
+// unescaped form {! .Field }.
+var renderExprPattern = regexp.MustCompile(`\{(!?)\s*\.(\w+(?:\.\w+)*)(\(\))?\s*\}`)
+
+// dynamicMark stands in for a dynamic value while slots are located.
+const dynamicMark = "\x00"
+
+// renderTemplate is a parsed template: len(statics) == len(dynamics)+1.
+type renderTemplate struct {
+	typeName string
+	statics  []string
+	dynamics []string // Go expressions producing the value
+	slots    []renderSlot
+}
+
+// renderSlot is a top-level data-slot element. Its content runs from
+// statics[first][start:] to statics[last][:end], with every dynamic in
+// between.
+type renderSlot struct {
+	id          string
+	first, last int
+	start, end  int
+}
+
+// runGenerateRender implements "golive generate render [flags] [paths]".
+func runGenerateRender(args []string) error {
+	flags := flag.NewFlagSet("generate render", flag.ContinueOnError)
+	pkg := flags.String("package", "", "package name (default: from the Go files next to each template)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && path != p && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && strings.HasSuffix(path, renderTemplateExt) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no %s templates found", renderTemplateExt)
+	}
+
+	for _, file := range files {
+		out, err := generateRenderFile(file, *pkg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		fmt.Printf("  ✓ %s\n", out)
+	}
+	fmt.Printf("✅ Generated %d render functions\n", len(files))
+	return nil
+}
+
+// generateRenderFile compiles one template and writes <name>_render.go next
+// to it.
+func generateRenderFile(file, pkg string) (string, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := parseRenderTemplate(string(src))
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Dir(file)
+	if pkg == "" {
+		pkg = packageName(dir)
+	}
+	base := strings.TrimSuffix(filepath.Base(file), renderTemplateExt)
+	code, err := tmpl.generate(pkg, filepath.Base(file))
+	if err != nil {
+		return "", err
+	}
+
+	out := filepath.Join(dir, toSnakeCase(base)+"_render.go")
+	return out, os.WriteFile(out, code, 0644)
+}
+
+// packageName returns the package declared by the Go files in dir, falling
+// back to the directory name.
+func packageName(dir string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, m := range matches {
+		if strings.HasSuffix(m, "_test.go") || strings.HasSuffix(m, "_render.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), m, nil, parser.PackageClauseOnly)
+		if err == nil {
+			return f.Name.Name
+		}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "main"
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, filepath.Base(abs))
+}
+
+// parseRenderTemplate splits a template into statics and dynamics and
+// locates its top-level slots.
+func parseRenderTemplate(src string) (*renderTemplate, error) {
+	m := renderAnnotation.FindStringSubmatch(src)
+	if m == nil {
+		return nil, fmt.Errorf("missing <!-- golive:render TypeName --> annotation on the first line")
+	}
+	src = strings.TrimSpace(src[len(m[0]):])
+	if strings.Contains(src, dynamicMark) {
+		return nil, fmt.Errorf("template contains a NUL byte")
+	}
+
+	tmpl := &renderTemplate{typeName: m[1]}
+	var skeleton strings.Builder
+	last := 0
+	for _, loc := range renderExprPattern.FindAllStringSubmatchIndex(src, -1) {
+		tmpl.statics = append(tmpl.statics, src[last:loc[0]])
+		skeleton.WriteString(src[last:loc[0]])
+		skeleton.WriteString(dynamicMark)
+
+		expr := "c." + src[loc[4]:loc[5]]
+		if loc[6] != -1 {
+			expr += "()"
+		}
+		if loc[3] > loc[2] {
+			tmpl.dynamics = append(tmpl.dynamics, "core.RawValue("+expr+")")
+		} else {
+			tmpl.dynamics = append(tmpl.dynamics, "core.EscapeValue("+expr+")")
+		}
+		last = loc[1]
+	}
+	tmpl.statics = append(tmpl.statics, src[last:])
+	skeleton.WriteString(src[last:])
+
+	slots, err := findSlots(skeleton.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range slots {
+		tmpl.slots = append(tmpl.slots, tmpl.locate(skeleton.String(), s))
+	}
+	return tmpl, nil
+}
+
+// skeletonSlot is a slot's content range in the skeleton.
+type skeletonSlot struct {
+	id         string
+	start, end int
+}
+
+// findSlots returns the top-level data-slot elements of skeleton, matching
+// the router's runtime extraction.
+func findSlots(skeleton string) ([]skeletonSlot, error) {
+	const marker = `data-slot="`
+	var slots []skeletonSlot
+	pos := 0
+	for {
+		idx := strings.Index(skeleton[pos:], marker)
+		if idx == -1 {
+			return slots, nil
+		}
+		idStart := pos + idx + len(marker)
+		idLen := strings.IndexByte(skeleton[idStart:], '"')
+		if idLen == -1 {
+			return nil, fmt.Errorf("unterminated data-slot attribute")
+		}
+		id := skeleton[idStart : idStart+idLen]
+		if strings.Contains(id, dynamicMark) {
+			return nil, fmt.Errorf("slot ids must be static")
+		}
+
+		tagStart := strings.LastIndexByte(skeleton[:pos+idx], '<')
+		if tagStart == -1 {
+			return nil, fmt.Errorf("data-slot %q outside a tag", id)
+		}
+		tagEnd := tagStart + 1
+		for tagEnd < len(skeleton) && !strings.ContainsRune(" \t\n/>", rune(skeleton[tagEnd])) {
+			tagEnd++
+		}
+		tag := skeleton[tagStart+1 : tagEnd]
+
+		gt := strings.IndexByte(skeleton[idStart+idLen:], '>')
+		if gt == -1 {
+			return nil, fmt.Errorf("unterminated tag for slot %q", id)
+		}
+		start := idStart + idLen + gt + 1
+
+		end, next := matchClose(skeleton, start, tag)
+		if end == -1 {
+			return nil, fmt.Errorf("missing </%s> for slot %q", tag, id)
+		}
+		slots = append(slots, skeletonSlot{id: id, start: start, end: end})
+		pos = next
+	}
+}
+
+// matchClose finds the close tag matching an element whose content starts
+// at pos. It returns the content end and the position after the close tag.
+func matchClose(s string, pos int, tag string) (end, next int) {
+	open, closing := "<"+tag, "</"+tag
+	depth := 1
+	for pos < len(s) {
+		nextClose := strings.Index(s[pos:], closing)
+		if nextClose == -1 {
+			return -1, -1
+		}
+		nextClose += pos
+		nextOpen := strings.Index(s[pos:nextClose], open)
+		if nextOpen != -1 {
+			nextOpen += pos
+			after := nextOpen + len(open)
+			if after < len(s) && strings.ContainsRune(" \t\n/>", rune(s[after])) {
+				depth++
+			}
+			pos = after
+			continue
+		}
+		depth--
+		if depth == 0 {
+			return nextClose, nextClose + len(closing)
+		}
+		pos = nextClose + len(closing)
+	}
+	return -1, -1
+}
+
+// locate converts a skeleton range to static/dynamic coordinates.
+func (t *renderTemplate) locate(skeleton string, s skeletonSlot) renderSlot {
+	at := func(p int) (index, offset int) {
+		index = strings.Count(skeleton[:p], dynamicMark)
+		return index, p - strings.LastIndex(skeleton[:p], dynamicMark) - 1
+	}
+	slot := renderSlot{id: s.id}
+	slot.first, slot.start = at(s.start)
+	slot.last, slot.end = at(s.end)
+	return slot
+}
+
+// slotExpr returns the Go expression for a slot's trimmed content.
+func (t *renderTemplate) slotExpr(s renderSlot) string {
+	if s.first == s.last {
+		return strconv.Quote(strings.TrimSpace(t.statics[s.first][s.start:s.end]))
+	}
+	var parts []string
+	add := func(static string) {
+		if static != "" {
+			parts = append(parts, strconv.Quote(static))
+		}
+	}
+	add(t.statics[s.first][s.start:])
+	for i := s.first; i < s.last; i++ {
+		parts = append(parts, "d"+strconv.Itoa(i))
+		if i+1 < s.last {
+			add(t.statics[i+1])
+		}
+	}
+	add(t.statics[s.last][:s.end])
+	return "strings.TrimSpace(" + strings.Join(parts, " + ") + ")"
+}
+
+// generate emits the Go source for the template.
+func (t *renderTemplate) generate(pkg, source string) ([]byte, error) {
+	statics := lowerFirst(t.typeName) + "Statics"
+	size := 0
+	for _, s := range t.statics {
+		size += len(s)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by golive generate render from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\t\"context\"\n\t\"io\"\n\t\"strings\"\n\n\t\"github.com/gabrielmiguelok/golivekit/pkg/core\"\n)\n\n")
+	fmt.Fprintf(&b, "var _ core.SlotRenderer = (*%s)(nil)\n\n", t.typeName)
+
+	fmt.Fprintf(&b, "// %s holds the static parts of %s.\n", statics, source)
+	fmt.Fprintf(&b, "var %s = [...]string{\n", statics)
+	for _, s := range t.statics {
+		fmt.Fprintf(&b, "\t%s,\n", strconv.Quote(s))
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// RenderSlots renders %s and returns the content of its slots.\n", t.typeName)
+	fmt.Fprintf(&b, "func (c *%s) RenderSlots(ctx context.Context) (string, map[string]string) {\n", t.typeName)
+	for i, d := range t.dynamics {
+		fmt.Fprintf(&b, "\td%d := %s\n", i, d)
+	}
+	if len(t.dynamics) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\tvar b strings.Builder\n\tb.Grow(%d)\n", size)
+	for i := range t.statics {
+		if t.statics[i] != "" {
+			fmt.Fprintf(&b, "\tb.WriteString(%s[%d])\n", statics, i)
+		}
+		if i < len(t.dynamics) {
+			fmt.Fprintf(&b, "\tb.WriteString(d%d)\n", i)
+		}
+	}
+	b.WriteString("\n\treturn b.String(), map[string]string{\n")
+	for _, s := range t.slots {
+		fmt.Fprintf(&b, "\t\t%s: %s,\n", strconv.Quote(s.id), t.slotExpr(s))
+	}
+	b.WriteString("\t}\n}\n\n")
+
+	b.WriteString("// Render implements core.Component.\n")
+	fmt.Fprintf(&b, "func (c *%s) Render(ctx context.Context) core.Renderer {\n", t.typeName)
+	b.WriteString("\treturn core.RendererFunc(func(ctx context.Context, w io.Writer) error {\n")
+	b.WriteString("\t\thtml, _ := c.RenderSlots(ctx)\n\t\t_, err := io.WriteString(w, html)\n\t\treturn err\n\t})\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// lowerFirst lowercases the first letter of s.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
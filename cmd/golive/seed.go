@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// seedCommand is the application's seed binary, which registers its seeds.
+const seedCommand = "./cmd/seed"
+
+// runSeed implements "golive seed [-list] [-force] [names...]": it runs
+// cmd/seed, which uses seed.Main, creating it on first use.
+func runSeed(args []string) error {
+	if _, err := os.Stat(seedCommand); os.IsNotExist(err) {
+		return scaffoldSeedCommand()
+	}
+	cmd := exec.Command("go", append([]string{"run", seedCommand}, args...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// scaffoldSeedCommand writes cmd/seed with an empty seed.
+func scaffoldSeedCommand() error {
+	src := `// Command seed loads development data. Run it with "golive seed [names]";
+// without names every seed runs, in registration order.
+package main
+
+import (
+	"context"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/seed"
+)
+
+func main() {
+	seed.Register("example", func(ctx context.Context) error {
+		// Insert data through the application's repositories, or load
+		// fixture files with seed.RegisterFixtures.
+		return nil
+	})
+	seed.Main()
+}
+`
+	dir := filepath.FromSlash(seedCommand)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Created %s (register your seeds there, then run golive seed)\n", file)
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/client"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// sessionsTopic is published when logins start or end, for the admin page.
+const sessionsTopic = "auth:sessions"
+
+// kickTopic is published when a login session is revoked. Its Live views
+// subscribe to it and send the browser to the login page.
+func kickTopic(sessionID string) string {
+	return "auth:kick:" + sessionID
+}
+
+// Mailer sends the emails of the app.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// logMailer prints emails to the server log instead of sending them.
+type logMailer struct{}
+
+func (logMailer) Send(to, subject, body string) error {
+	log.Printf("📧 To: %s\nSubject: %s\n\n%s", to, subject, body)
+	return nil
+}
+
+// Login is an active login session, shown on the admin page. Ref names it
+// without revealing the session ID, which is a credential.
+type Login struct {
+	Ref      string
+	UserID   string
+	Username string
+	Started  time.Time
+
+	sessionID string
+}
+
+// loginRef derives the public name of a session.
+func loginRef(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:6])
+}
+
+// App wires the accounts, the login sessions and the routes together.
+type App struct {
+	Users    *Users
+	Mailer   Mailer
+	BaseURL  string
+	PubSub   pubsub.PubSub
+	sessions *security.SessionManager
+	store    *security.MemorySessionStore
+	csrf     *security.CSRFProtection
+
+	mu     sync.Mutex
+	logins map[string]Login
+}
+
+// NewApp creates the app. Verification links start with baseURL.
+func NewApp(baseURL string, mailer Mailer) *App {
+	store := security.NewMemorySessionStore()
+	return &App{
+		Users:   NewUsers(),
+		Mailer:  mailer,
+		BaseURL: baseURL,
+		PubSub:  pubsub.NewMemoryPubSub(),
+		store:   store,
+		sessions: security.NewSessionManager(security.SessionManagerConfig{
+			Store:      store,
+			SessionTTL: 12 * time.Hour,
+		}),
+		csrf:   security.NewCSRFProtection(security.CSRFConfig{}),
+		logins: make(map[string]Login),
+	}
+}
+
+// Handler returns the app's routes. Every request goes through the session
+// middleware, which loads the user into the context, and CSRF protection.
+func (a *App) Handler() http.Handler {
+	r := router.New()
+	r.SetPubSub(a.PubSub)
+	r.Use(a.sessions.Middleware())
+	r.Use(a.csrf.Middleware())
+
+	r.Handle("/_live/", http.StripPrefix("/_live/", client.Handler()))
+	r.HandleFunc("/register", a.handleRegister)
+	r.HandleFunc("/verify", a.handleVerify)
+	r.HandleFunc("/login", a.handleLogin)
+	r.HandleFunc("POST /logout", a.handleLogout)
+
+	r.Group("", func(g *router.RouteGroup) {
+		g.OnMount(requireUser)
+		g.Live("/", a.newDashboard)
+
+		g.Group("/admin", func(g *router.RouteGroup) {
+			g.OnMount(requireRole("admin"))
+			g.Live("", a.newAdmin)
+		})
+	})
+
+	return r
+}
+
+// requireUser sends visitors without a valid session to the login page.
+// The session middleware fills the session from the cookie on the page
+// load and again when the socket connects, so revoked sessions cannot
+// rejoin.
+func requireUser(ctx context.Context, params core.Params, session core.Session) error {
+	if session.GetString("user_id") == "" {
+		return router.HaltRedirect("/login")
+	}
+	return nil
+}
+
+// requireRole sends users without role back to their dashboard.
+func requireRole(role string) router.OnMountHook {
+	return func(ctx context.Context, params core.Params, session core.Session) error {
+		roles, _ := session["roles"].([]string)
+		if !slices.Contains(roles, role) {
+			return router.HaltRedirect("/")
+		}
+		return nil
+	}
+}
+
+// login starts a session for user and sets its cookie.
+func (a *App) login(w http.ResponseWriter, user *User) error {
+	auth := &security.AuthContext{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Roles:    user.Roles,
+	}
+	sessionID, err := a.sessions.Login(w, auth)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.logins[sessionID] = Login{
+		Ref:       loginRef(sessionID),
+		UserID:    user.ID,
+		Username:  user.Username,
+		Started:   time.Now(),
+		sessionID: sessionID,
+	}
+	a.mu.Unlock()
+	a.PubSub.Publish(sessionsTopic, nil)
+	return nil
+}
+
+// ErrUnknownLogin is returned by Kick for refs of no active session.
+var ErrUnknownLogin = errors.New("unknown login session")
+
+// Kick revokes the login session named ref: its cookie stops working and its
+// open Live views are sent to the login page.
+func (a *App) Kick(ref string) error {
+	sessionID := ""
+	a.mu.Lock()
+	for id, l := range a.logins {
+		if l.Ref == ref {
+			sessionID = id
+		}
+	}
+	a.mu.Unlock()
+	if sessionID == "" {
+		return ErrUnknownLogin
+	}
+
+	if err := a.store.Delete(sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	a.endLogin(sessionID)
+	return a.PubSub.Publish(kickTopic(sessionID), nil)
+}
+
+// endLogin forgets a login session.
+func (a *App) endLogin(sessionID string) {
+	a.mu.Lock()
+	delete(a.logins, sessionID)
+	a.mu.Unlock()
+	a.PubSub.Publish(sessionsTopic, nil)
+}
+
+// Logins returns the active login sessions, oldest first.
+func (a *App) Logins() []Login {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	logins := make([]Login, 0, len(a.logins))
+	for _, l := range a.logins {
+		if _, err := a.store.Get(l.sessionID); err == nil {
+			logins = append(logins, l)
+		}
+	}
+	slices.SortFunc(logins, func(x, y Login) int {
+		return x.Started.Compare(y.Started)
+	})
+	return logins
+}
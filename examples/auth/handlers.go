@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// registerFields are the form fields of the registration page.
+var registerFields = []string{"username", "email", "password", "password_confirmation"}
+
+// emailPattern is a loose email check; the verification link is the real one.
+const emailPattern = `^[^@\s]+@[^@\s]+\.[^@\s]+$`
+
+// pageData is what the HTTP pages render.
+type pageData struct {
+	Title     string
+	CSRF      template.HTML
+	Notice    string
+	Error     string
+	Changeset *forms.Changeset
+}
+
+// handleRegister shows the registration form and creates accounts. The new
+// account gets a verification email and cannot log in before using it.
+func (a *App) handleRegister(w http.ResponseWriter, r *http.Request) {
+	data := pageData{Title: "Create an account", CSRF: template.HTML(a.csrf.Hidden(r))}
+	if r.Method != http.MethodPost {
+		data.Changeset = forms.NewChangeset(nil)
+		render(w, registerPage, data)
+		return
+	}
+
+	params := make(map[string]any)
+	for _, field := range registerFields {
+		params[field] = r.PostFormValue(field)
+	}
+	cs := forms.Cast(nil, params, registerFields).
+		ValidateRequired("username", "email", "password").
+		ValidateLength("username", forms.LengthOpts{Min: 2, Max: 40}).
+		ValidateFormat("email", emailPattern).
+		ValidateLength("password", forms.LengthOpts{Min: 8, Max: 200}).
+		ValidateConfirmation("password")
+	data.Changeset = cs
+	if !cs.Valid {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, registerPage, data)
+		return
+	}
+
+	user, token, err := a.Users.Register(cs.GetString("username"), cs.GetString("email"), cs.GetString("password"))
+	if errors.Is(err, ErrEmailTaken) {
+		cs.AddError("email", "is already registered")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, registerPage, data)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Registration failed", http.StatusInternalServerError)
+		return
+	}
+
+	link := a.BaseURL + "/verify?token=" + url.QueryEscape(token)
+	if err := a.Mailer.Send(user.Email, "Verify your email", "Open this link to verify your account:\n"+link); err != nil {
+		log.Printf("verification email to %s failed: %v", user.Email, err)
+	}
+
+	http.Redirect(w, r, "/login?registered=1", http.StatusSeeOther)
+}
+
+// handleVerify marks the account of a verification link as verified.
+func (a *App) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if _, err := a.Users.Verify(r.URL.Query().Get("token")); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render(w, messagePage, pageData{Title: "Verification failed", Error: err.Error()})
+		return
+	}
+	http.Redirect(w, r, "/login?verified=1", http.StatusSeeOther)
+}
+
+// handleLogin shows the login form and starts sessions.
+func (a *App) handleLogin(w http.ResponseWriter, r *http.Request) {
+	data := pageData{Title: "Log in", CSRF: template.HTML(a.csrf.Hidden(r))}
+	if r.Method != http.MethodPost {
+		switch {
+		case r.URL.Query().Has("registered"):
+			data.Notice = "Check your email for the verification link."
+		case r.URL.Query().Has("verified"):
+			data.Notice = "Email verified, you can log in."
+		}
+		render(w, loginPage, data)
+		return
+	}
+
+	user, err := a.Users.Authenticate(r.PostFormValue("email"), r.PostFormValue("password"))
+	if err != nil {
+		data.Error = err.Error()
+		w.WriteHeader(http.StatusUnauthorized)
+		render(w, loginPage, data)
+		return
+	}
+	if err := a.login(w, user); err != nil {
+		http.Error(w, "Login failed", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// handleLogout ends the current session.
+func (a *App) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if auth := security.AuthFromContext(r.Context()); auth != nil {
+		a.endLogin(auth.SessionID)
+	}
+	a.sessions.Logout(w, r)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func render(w http.ResponseWriter, page *template.Template, data pageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, data); err != nil {
+		log.Printf("render %s: %v", page.Name(), err)
+	}
+}
+
+// layout wraps the HTTP pages and the Live views.
+const layout = `<!DOCTYPE html>
+<html>
+<head>
+    <title>{{.Title}} · GoliveKit Auth</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 3rem auto; padding: 0 1rem; color: #1f2937; }
+        form.card, .card { background: #fff; border: 1px solid #e5e7eb; border-radius: 8px; padding: 1.5rem; }
+        label { display: block; margin: 0.75rem 0 0.25rem; font-weight: 600; }
+        input { width: 100%; padding: 0.5rem; border: 1px solid #d1d5db; border-radius: 4px; box-sizing: border-box; }
+        button { margin-top: 1rem; padding: 0.5rem 1rem; background: #4f46e5; color: #fff; border: none; border-radius: 4px; cursor: pointer; }
+        button.danger { background: #dc2626; margin: 0; }
+        .error { color: #dc2626; font-size: 0.9rem; }
+        .notice { background: #ecfdf5; color: #065f46; padding: 0.75rem; border-radius: 4px; }
+        table { width: 100%; border-collapse: collapse; }
+        th, td { text-align: left; padding: 0.5rem; border-bottom: 1px solid #e5e7eb; }
+        nav { display: flex; gap: 1rem; align-items: center; margin-bottom: 1.5rem; }
+        nav form { margin-left: auto; }
+        nav button { margin: 0; }
+    </style>
+</head>
+<body>
+{{template "content" .}}
+</body>
+</html>`
+
+func page(name, content string) *template.Template {
+	t := template.Must(template.New(name).Parse(layout))
+	return template.Must(t.New("content").Parse(content))
+}
+
+var registerPage = page("register", `
+<h1>Create an account</h1>
+<form class="card" method="post" action="/register">
+    {{.CSRF}}
+    {{$cs := .Changeset}}
+    <label for="username">Username</label>
+    <input id="username" name="username" value="{{$cs.GetString "username"}}" required>
+    {{range index $cs.Errors "username"}}<div class="error">Username {{.}}</div>{{end}}
+    <label for="email">Email</label>
+    <input id="email" name="email" type="email" value="{{$cs.GetString "email"}}" required>
+    {{range index $cs.Errors "email"}}<div class="error">Email {{.}}</div>{{end}}
+    <label for="password">Password</label>
+    <input id="password" name="password" type="password" required>
+    {{range index $cs.Errors "password"}}<div class="error">Password {{.}}</div>{{end}}
+    <label for="password_confirmation">Confirm password</label>
+    <input id="password_confirmation" name="password_confirmation" type="password" required>
+    {{range index $cs.Errors "password_confirmation"}}<div class="error">Confirmation {{.}}</div>{{end}}
+    <button type="submit">Register</button>
+</form>
+<p>Already registered? <a href="/login">Log in</a></p>`)
+
+var loginPage = page("login", `
+<h1>Log in</h1>
+{{if .Notice}}<p class="notice">{{.Notice}}</p>{{end}}
+<form class="card" method="post" action="/login">
+    {{.CSRF}}
+    {{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+    <label for="email">Email</label>
+    <input id="email" name="email" type="email" required>
+    <label for="password">Password</label>
+    <input id="password" name="password" type="password" required>
+    <button type="submit">Log in</button>
+</form>
+<p>No account? <a href="/register">Register</a></p>`)
+
+var messagePage = page("message", `
+<h1>{{.Title}}</h1>
+<p class="error">{{.Error}}</p>
+<p><a href="/register">Register again</a> or <a href="/login">log in</a>.</p>`)
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+)
+
+// authView is embedded by the Live views behind the login. It follows the
+// login session and sends the browser to the login page when an admin
+// revokes it.
+type authView struct {
+	core.BaseComponent
+	app       *App
+	user      *User
+	sessionID string
+	csrf      string
+}
+
+// mount loads the current user and subscribes to the session's kick topic.
+func (v *authView) mount(session core.Session) error {
+	user, ok := v.app.Users.Get(session.GetString("user_id"))
+	if !ok {
+		return router.HaltRedirect("/login")
+	}
+	v.user = user
+	v.sessionID = session.GetString("session_id")
+	v.csrf = session.GetString("cookie:_csrf")
+
+	if socket := v.Socket(); socket != nil {
+		if err := socket.Subscribe(kickTopic(v.sessionID)); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+	return nil
+}
+
+// kicked reports whether msg revokes the view's session, and then sends
+// the browser to the login page.
+func (v *authView) kicked(msg any) bool {
+	b, ok := msg.(core.Broadcast)
+	if !ok || b.Topic != kickTopic(v.sessionID) {
+		return false
+	}
+	if socket := v.Socket(); socket != nil {
+		socket.Push(router.RedirectEvent, map[string]any{"to": "/login"})
+	}
+	return true
+}
+
+// viewData is what the Live views render.
+type viewData struct {
+	Title  string
+	CSRF   string
+	User   *User
+	Users  []*User
+	Logins []Login
+	Self   string
+	Notice string
+}
+
+// Dashboard is the page of a logged-in user.
+type Dashboard struct {
+	authView
+}
+
+func (a *App) newDashboard() core.Component {
+	return &Dashboard{authView{app: a}}
+}
+
+// Name returns the component name.
+func (c *Dashboard) Name() string {
+	return "dashboard"
+}
+
+// Mount loads the user.
+func (c *Dashboard) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	return c.mount(session)
+}
+
+// HandleEvent has nothing to handle: logging out is a form post.
+func (c *Dashboard) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	return nil
+}
+
+// HandleInfo follows session revocations.
+func (c *Dashboard) HandleInfo(ctx context.Context, msg any) error {
+	c.kicked(msg)
+	return nil
+}
+
+// Render returns the dashboard HTML.
+func (c *Dashboard) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		return dashboardPage.Execute(w, viewData{Title: "Dashboard", CSRF: c.csrf, User: c.user})
+	})
+}
+
+// Admin lists the accounts and the active logins, and revokes logins.
+// Only users with the admin role can mount it.
+type Admin struct {
+	authView
+	notice string
+}
+
+func (a *App) newAdmin() core.Component {
+	return &Admin{authView: authView{app: a}}
+}
+
+// Name returns the component name.
+func (c *Admin) Name() string {
+	return "admin"
+}
+
+// Mount loads the user and follows logins starting and ending.
+func (c *Admin) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	if err := c.mount(session); err != nil {
+		return err
+	}
+	if socket := c.Socket(); socket != nil {
+		if err := socket.Subscribe(sessionsTopic); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+	return nil
+}
+
+// HandleEvent revokes logins.
+func (c *Admin) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if event != "kick" {
+		return nil
+	}
+	ref, _ := payload["ref"].(string)
+	err := c.app.Kick(ref)
+	switch {
+	case errors.Is(err, ErrUnknownLogin):
+		c.notice = "That session has already ended."
+	case err != nil:
+		return err
+	default:
+		c.notice = "Session revoked."
+	}
+	return nil
+}
+
+// HandleInfo re-renders when logins change, and follows revocations of the
+// admin's own session.
+func (c *Admin) HandleInfo(ctx context.Context, msg any) error {
+	c.kicked(msg)
+	return nil
+}
+
+// Render returns the admin page HTML.
+func (c *Admin) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		return adminPage.Execute(w, viewData{
+			Title:  "Admin",
+			CSRF:   c.csrf,
+			User:   c.user,
+			Users:  c.app.Users.All(),
+			Logins: c.app.Logins(),
+			Self:   loginRef(c.sessionID),
+			Notice: c.notice,
+		})
+	})
+}
+
+// liveNav is the navigation bar of the Live views; logging out is a plain
+// form post carrying the CSRF token.
+const liveNav = `
+<nav>
+    <strong>{{.User.Username}}</strong>
+    <a href="/">Dashboard</a>
+    {{if .User.IsAdmin}}<a href="/admin">Admin</a>{{end}}
+    <form method="post" action="/logout">
+        <input type="hidden" name="_csrf" value="{{.CSRF}}">
+        <button type="submit">Log out</button>
+    </form>
+</nav>`
+
+var dashboardPage = livePage("dashboard", liveNav+`
+<div class="card">
+    <h1>Welcome, {{.User.Username}}</h1>
+    <p>Email: {{.User.Email}}</p>
+    <p>Roles: {{range $i, $r := .User.Roles}}{{if $i}}, {{end}}{{$r}}{{end}}</p>
+</div>`)
+
+var adminPage = livePage("admin", liveNav+`
+{{if .Notice}}<p class="notice">{{.Notice}}</p>{{end}}
+<h2>Active sessions</h2>
+<table>
+    <tr><th>User</th><th>Since</th><th></th></tr>
+    {{range .Logins}}
+    <tr>
+        <td>{{.Username}}</td>
+        <td>{{.Started.Format "15:04:05"}}</td>
+        <td>{{if eq .Ref $.Self}}this session{{else}}<button class="danger" lv-click="kick" lv-value-ref="{{.Ref}}">Kick</button>{{end}}</td>
+    </tr>
+    {{end}}
+</table>
+<h2>Accounts</h2>
+<table>
+    <tr><th>User</th><th>Email</th><th>Roles</th><th>Verified</th></tr>
+    {{range .Users}}
+    <tr>
+        <td>{{.Username}}</td>
+        <td>{{.Email}}</td>
+        <td>{{range $i, $r := .Roles}}{{if $i}}, {{end}}{{$r}}{{end}}</td>
+        <td>{{if .Verified}}yes{{else}}pending{{end}}</td>
+    </tr>
+    {{end}}
+</table>`)
+
+// livePage wraps content in the layout, inside the Live view's root.
+func livePage(name, content string) *template.Template {
+	return page(name, `<div data-live-view="`+name+`">`+content+`</div>
+<script src="/_live/golivekit.js"></script>`)
+}
@@ -0,0 +1,27 @@
+// Package main is a reference application for authentication and
+// authorization with GoliveKit: registration with email verification,
+// password login with server-side sessions and CSRF protection, a Live
+// dashboard, and an admin Live route gated by role where admins revoke
+// sessions, kicking their open Live views.
+//
+// The first account registered becomes the admin. Verification emails are
+// printed to the log.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":3000", "listen address")
+	baseURL := flag.String("url", "http://localhost:3000", "public URL, for verification links")
+	flag.Parse()
+
+	app := NewApp(*baseURL, logMailer{})
+
+	log.Printf("🔐 Auth example starting at %s", *baseURL)
+	log.Println("Register at /register; the first account becomes the admin")
+	log.Fatal(http.ListenAndServe(*addr, app.Handler()))
+}
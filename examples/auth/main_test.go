@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+)
+
+// outbox records the emails the app sends.
+type outbox struct {
+	mu    sync.Mutex
+	mails map[string]string
+}
+
+func (o *outbox) Send(to, subject, body string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.mails[to] = body
+	return nil
+}
+
+var linkPattern = regexp.MustCompile(`http\S+/verify\?token=\S+`)
+
+func (o *outbox) link(t *testing.T, to string) string {
+	t.Helper()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	link := linkPattern.FindString(o.mails[to])
+	if link == "" {
+		t.Fatalf("no verification link sent to %s", to)
+	}
+	return link
+}
+
+// browser is an HTTP client with its own cookies.
+type browser struct {
+	t      *testing.T
+	base   string
+	client *http.Client
+}
+
+func newBrowser(t *testing.T, base string) *browser {
+	jar, _ := cookiejar.New(nil)
+	return &browser{t: t, base: base, client: &http.Client{Jar: jar}}
+}
+
+// get fetches path, following redirects, and returns the final path and body.
+func (b *browser) get(path string) (string, string) {
+	b.t.Helper()
+	resp, err := b.client.Get(b.base + path)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return b.read(resp)
+}
+
+// post submits a form on path with the CSRF token of the page it is on.
+func (b *browser) post(path string, form url.Values) (int, string) {
+	b.t.Helper()
+	_, page := b.get(path)
+	token := regexp.MustCompile(`name="_csrf" value="([^"]+)"`).FindStringSubmatch(page)
+	if token == nil {
+		b.t.Fatalf("no CSRF token on %s", path)
+	}
+	form.Set("_csrf", html.UnescapeString(token[1]))
+
+	resp, err := b.client.PostForm(b.base+path, form)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	_, body := b.read(resp)
+	return resp.StatusCode, body
+}
+
+func (b *browser) read(resp *http.Response) (string, string) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return resp.Request.URL.Path, string(body)
+}
+
+// join connects a Live view of path.
+func (b *browser) join(ctx context.Context, path string) *websocket.Conn {
+	b.t.Helper()
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(b.base, "http")+path, &websocket.DialOptions{HTTPClient: b.client})
+	if err != nil {
+		b.t.Fatalf("dial %s: %v", path, err)
+	}
+	b.t.Cleanup(func() { ws.CloseNow() })
+
+	join := map[string]any{"ref": "1", "join_ref": "1", "topic": "lv:" + path, "event": "phx_join", "payload": map[string]any{}}
+	if err := wsjson.Write(ctx, ws, join); err != nil {
+		b.t.Fatal(err)
+	}
+	waitFor(b.t, ctx, ws, "phx_reply")
+	return ws
+}
+
+// waitFor reads messages until one has event.
+func waitFor(t *testing.T, ctx context.Context, ws *websocket.Conn, event string) map[string]any {
+	t.Helper()
+	for {
+		var msg map[string]any
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("waiting for %s: %v", event, err)
+		}
+		if msg["event"] == event {
+			return msg
+		}
+	}
+}
+
+// signUp registers, verifies and logs in an account.
+func signUp(t *testing.T, b *browser, mail *outbox, username, email string) {
+	t.Helper()
+	form := url.Values{
+		"username":              {username},
+		"email":                 {email},
+		"password":              {"correct horse"},
+		"password_confirmation": {"correct horse"},
+	}
+	if status, body := b.post("/register", form); status != http.StatusOK || !strings.Contains(body, "Check your email") {
+		t.Fatalf("register: status %d\n%s", status, body)
+	}
+
+	login := url.Values{"email": {email}, "password": {"correct horse"}}
+	if status, body := b.post("/login", login); status != http.StatusUnauthorized || !strings.Contains(body, ErrNotVerified.Error()) {
+		t.Fatalf("login before verifying: status %d", status)
+	}
+
+	link, _ := url.Parse(mail.link(t, email))
+	if path, _ := b.get(link.RequestURI()); path != "/login" {
+		t.Fatalf("verify landed on %s", path)
+	}
+	if status, body := b.post("/login", login); status != http.StatusOK || !strings.Contains(body, "Welcome, "+username) {
+		t.Fatalf("login: status %d\n%s", status, body)
+	}
+}
+
+func TestAuthFlow(t *testing.T) {
+	mail := &outbox{mails: make(map[string]string)}
+	ts := httptest.NewUnstartedServer(nil)
+	app := NewApp("http://"+ts.Listener.Addr().String(), mail)
+	ts.Config.Handler = app.Handler()
+	ts.Start()
+	defer ts.Close()
+
+	alice := newBrowser(t, ts.URL)
+	bob := newBrowser(t, ts.URL)
+
+	// Anonymous visitors go to the login page
+	if path, _ := bob.get("/"); path != "/login" {
+		t.Fatalf("anonymous dashboard landed on %s", path)
+	}
+
+	signUp(t, alice, mail, "alice", "alice@example.com")
+	signUp(t, bob, mail, "bob", "bob@example.com")
+
+	// Forms without the token are refused
+	resp, err := bob.client.PostForm(ts.URL+"/logout", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("logout without CSRF token: status %d, want 403", resp.StatusCode)
+	}
+
+	// The first account is the admin; others cannot open the admin route
+	if path, _ := bob.get("/admin"); path != "/" {
+		t.Errorf("bob opening /admin landed on %s", path)
+	}
+	_, page := alice.get("/admin")
+	if !strings.Contains(page, "bob@example.com") {
+		t.Fatalf("admin page does not list bob:\n%s", page)
+	}
+
+	// Kicking bob sends his open dashboard to the login page
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	bobWS := bob.join(ctx, "/")
+	adminWS := alice.join(ctx, "/admin")
+
+	var ref string
+	for _, l := range app.Logins() {
+		if l.Username == "bob" {
+			ref = l.Ref
+		}
+	}
+	kick := map[string]any{"ref": "2", "topic": "lv:/admin", "event": "kick", "payload": map[string]any{"ref": ref}}
+	if err := wsjson.Write(ctx, adminWS, kick); err != nil {
+		t.Fatal(err)
+	}
+
+	redirect := waitFor(t, ctx, bobWS, router.RedirectEvent)
+	if to := redirect["payload"].(map[string]any)["to"]; to != "/login" {
+		t.Errorf("kicked view redirected to %v", to)
+	}
+	if path, _ := bob.get("/"); path != "/login" {
+		t.Errorf("kicked session still opens the dashboard (%s)", path)
+	}
+	if len(app.Logins()) != 1 {
+		t.Errorf("logins = %v, want alice's only", app.Logins())
+	}
+
+	// Logging out ends alice's session
+	if status, _ := alice.post("/logout", url.Values{}); status != http.StatusOK {
+		t.Errorf("logout: status %d", status)
+	}
+	if path, _ := alice.get("/admin"); path != "/login" {
+		t.Errorf("after logout /admin landed on %s", path)
+	}
+}
+
+func TestUsers_RejectsBadCredentials(t *testing.T) {
+	users := NewUsers()
+	if _, _, err := users.Register("carol", "Carol@Example.com", "secret password"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := users.Register("carol2", "carol@example.com", "x"); err != ErrEmailTaken {
+		t.Errorf("duplicate email: err = %v", err)
+	}
+	if _, err := users.Authenticate("carol@example.com", "wrong password"); err != ErrInvalidCredentials {
+		t.Errorf("wrong password: err = %v", err)
+	}
+	if _, err := users.Authenticate("nobody@example.com", "secret password"); err != ErrInvalidCredentials {
+		t.Errorf("unknown email: err = %v", err)
+	}
+	if _, err := users.Verify("forged"); err != ErrInvalidVerifyToken {
+		t.Errorf("forged token: err = %v", err)
+	}
+}
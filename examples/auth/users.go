@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Account errors.
+var (
+	ErrEmailTaken         = errors.New("email already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrNotVerified        = errors.New("email not verified")
+	ErrInvalidVerifyToken = errors.New("invalid or expired verification link")
+)
+
+// verifyTTL is how long an email verification link stays valid.
+const verifyTTL = 24 * time.Hour
+
+// passwordIterations is the PBKDF2 cost of password hashes.
+const passwordIterations = 100_000
+
+// User is a registered account.
+type User struct {
+	ID        string
+	Username  string
+	Email     string
+	Roles     []string
+	Verified  bool
+	CreatedAt time.Time
+
+	salt []byte
+	hash []byte
+}
+
+// IsAdmin reports whether the user has the admin role.
+func (u *User) IsAdmin() bool {
+	return slices.Contains(u.Roles, "admin")
+}
+
+// verification is a pending email verification.
+type verification struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// Users is the in-memory account store. The first account registered
+// becomes the admin.
+type Users struct {
+	mu      sync.RWMutex
+	byID    map[string]*User
+	byEmail map[string]*User
+	pending map[string]verification
+	nextID  int
+}
+
+// NewUsers creates an empty account store.
+func NewUsers() *Users {
+	return &Users{
+		byID:    make(map[string]*User),
+		byEmail: make(map[string]*User),
+		pending: make(map[string]verification),
+	}
+}
+
+// Register creates an unverified account and returns it with the token of
+// its verification link.
+func (s *Users) Register(username, email, password string) (*User, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, "", err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.byEmail[email]; ok {
+		return nil, "", ErrEmailTaken
+	}
+
+	s.nextID++
+	user := &User{
+		ID:        fmt.Sprintf("u%d", s.nextID),
+		Username:  username,
+		Email:     email,
+		Roles:     []string{"user"},
+		CreatedAt: time.Now(),
+		salt:      salt,
+		hash:      hashPassword(password, salt),
+	}
+	if len(s.byID) == 0 {
+		user.Roles = append(user.Roles, "admin")
+	}
+	s.byID[user.ID] = user
+	s.byEmail[email] = user
+	s.pending[token] = verification{userID: user.ID, expiresAt: time.Now().Add(verifyTTL)}
+
+	return user, token, nil
+}
+
+// Verify marks the account of a verification token as verified. Tokens
+// work once.
+func (s *Users) Verify(token string) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.pending[token]
+	if !ok {
+		return nil, ErrInvalidVerifyToken
+	}
+	delete(s.pending, token)
+	if time.Now().After(v.expiresAt) {
+		return nil, ErrInvalidVerifyToken
+	}
+
+	user := s.byID[v.userID]
+	user.Verified = true
+	return user, nil
+}
+
+// Authenticate checks an email and password. Unverified accounts cannot
+// log in.
+func (s *Users) Authenticate(email, password string) (*User, error) {
+	s.mu.RLock()
+	user, ok := s.byEmail[strings.ToLower(strings.TrimSpace(email))]
+	s.mu.RUnlock()
+
+	if !ok {
+		// Hash anyway, so unknown emails take as long as wrong passwords
+		hashPassword(password, make([]byte, 16))
+		return nil, ErrInvalidCredentials
+	}
+	if subtle.ConstantTimeCompare(hashPassword(password, user.salt), user.hash) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	if !user.Verified {
+		return nil, ErrNotVerified
+	}
+	return user, nil
+}
+
+// Get returns the account with id.
+func (s *Users) Get(id string) (*User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.byID[id]
+	return user, ok
+}
+
+// All returns the accounts in registration order.
+func (s *Users) All() []*User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*User, 0, len(s.byID))
+	for _, user := range s.byID {
+		users = append(users, user)
+	}
+	slices.SortFunc(users, func(a, b *User) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return users
+}
+
+// hashPassword derives a password hash with PBKDF2-HMAC-SHA256.
+func hashPassword(password string, salt []byte) []byte {
+	prf := hmac.New(sha256.New, []byte(password))
+	prf.Write(salt)
+	prf.Write(binary.BigEndian.AppendUint32(nil, 1))
+	u := prf.Sum(nil)
+
+	key := slices.Clone(u)
+	for i := 1; i < passwordIterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+	return key
+}
+
+// randomToken returns an unguessable URL-safe token.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
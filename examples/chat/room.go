@@ -0,0 +1,625 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/examples/internal/store"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/presence"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// rooms are the chat rooms, the first one being the default.
+var rooms = []string{"lobby", "golang", "random"}
+
+// typingTimeout is how long someone shows as typing after their last
+// keystroke.
+const typingTimeout = 3 * time.Second
+
+// messagesTopic carries the new messages of a room, JSON-encoded.
+func messagesTopic(room string) string {
+	return "chat:" + room + ":messages"
+}
+
+// presenceTopic tracks who is in a room.
+func presenceTopic(room string) string {
+	return "chat:" + room
+}
+
+// typingStopped is sent to the component when its typing timer fires.
+type typingStopped struct{}
+
+// ChatRoom is the chat LiveView component. It shows one room and keeps
+// count of the messages arriving in the others.
+type ChatRoom struct {
+	core.BaseComponent
+	Username    string
+	room        string
+	messages    []store.Message
+	unread      map[string]int
+	tz          *time.Location
+	unwatch     []func()
+	unreceipts  func()
+	typing      bool
+	typingUntil time.Time
+	typingTimer *time.Timer
+}
+
+// NewChatRoom creates a new chat room component.
+func NewChatRoom() core.Component {
+	return &ChatRoom{unread: make(map[string]int)}
+}
+
+// Name returns the component name.
+func (c *ChatRoom) Name() string {
+	return "chat"
+}
+
+// Mount opens the room of the ?room= parameter.
+func (c *ChatRoom) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	// Generate a random username
+	c.Username = fmt.Sprintf("User%d", time.Now().UnixNano()%10000)
+	c.tz = session.Location()
+
+	// Allow custom username from query params
+	if username := params.Get("username"); username != "" {
+		c.Username = username
+	}
+	c.Assigns().Set("username", c.Username)
+
+	if socket := c.Socket(); socket != nil {
+		// Messages of every room arrive in HandleInfo: the open room shows
+		// them, the others count them as unread
+		for _, room := range rooms {
+			if err := socket.Subscribe(messagesTopic(room)); err != nil {
+				return fmt.Errorf("failed to subscribe: %w", err)
+			}
+			c.unwatch = append(c.unwatch, presences.GetOrCreate(presenceTopic(room)).Watch(socket))
+		}
+
+		// Get notified when others receive or read our messages
+		c.unreceipts = chatReceipts.Watch(c.Username, socket)
+	}
+
+	return c.enter(ctx, roomParam(params))
+}
+
+// roomParam returns the room named by params, or the default room.
+func roomParam(params core.Params) string {
+	if room := params.Get("room"); slices.Contains(rooms, room) {
+		return room
+	}
+	return rooms[0]
+}
+
+// enter moves the user to room: presence follows them and the room's
+// history is loaded.
+func (c *ChatRoom) enter(ctx context.Context, room string) error {
+	if room == c.room {
+		return nil
+	}
+	if socket := c.Socket(); socket != nil {
+		if c.room != "" {
+			presences.Untrack(presenceTopic(c.room), socket)
+		}
+		c.typing = false
+		if err := c.track(room); err != nil {
+			return fmt.Errorf("failed to track presence: %w", err)
+		}
+	}
+	c.room = room
+	delete(c.unread, room)
+	c.Assigns().Set("room", room)
+	return c.loadMessages(ctx)
+}
+
+// track adds the socket to room's presence under the current name.
+func (c *ChatRoom) track(room string) error {
+	return presences.Track(presenceTopic(room), c.Socket(), presence.PresenceInfo{
+		UserID:   c.Username,
+		Username: c.Username,
+		Metas:    map[string]any{"typing": c.typing},
+	})
+}
+
+// HandleParams follows the room in the URL, on room changes and on
+// back/forward.
+func (c *ChatRoom) HandleParams(ctx context.Context, params core.Params, nav core.Navigation) error {
+	return c.enter(ctx, roomParam(params))
+}
+
+// HandleEvent handles user interactions.
+func (c *ChatRoom) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if handled, err := chatReceipts.HandleEvent(c.Username, event, payload); handled {
+		return err
+	}
+
+	switch event {
+	case "send_message":
+		content, _ := payload["message"].(string)
+		if content == "" {
+			return nil
+		}
+
+		// Create message
+		msg := store.Message{
+			ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
+			Room:      c.room,
+			Username:  c.Username,
+			Content:   content,
+			Timestamp: time.Now(),
+		}
+
+		// Store message
+		if err := messages.Add(ctx, msg); err != nil {
+			return err
+		}
+		chatReceipts.Sent(msg.ID, msg.Username)
+		c.setTyping(false)
+
+		// Broadcast to the sessions of every node, this one included
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return ps.Publish(messagesTopic(c.room), data)
+
+	case "typing":
+		value, _ := payload["value"].(string)
+		if value == "" {
+			c.setTyping(false)
+			return nil
+		}
+		c.typingUntil = time.Now().Add(typingTimeout)
+		if c.typingTimer == nil {
+			socket := c.Socket()
+			c.typingTimer = time.AfterFunc(typingTimeout, func() { socket.SendInfo(typingStopped{}) })
+		} else {
+			c.typingTimer.Reset(typingTimeout)
+		}
+		c.setTyping(true)
+
+	case "join_room":
+		room, _ := payload["room"].(string)
+		if !slices.Contains(rooms, room) {
+			return nil
+		}
+		if err := c.enter(ctx, room); err != nil {
+			return err
+		}
+		return c.Socket().PushPatch("/?room=" + room)
+
+	case "set_username":
+		username, _ := payload["username"].(string)
+		if username != "" && username != c.Username {
+			c.Username = username
+			c.Assigns().Set("username", c.Username)
+			if c.unreceipts != nil {
+				c.unreceipts()
+				c.unreceipts = chatReceipts.Watch(username, c.Socket())
+				return c.track(c.room)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setTyping publishes whether the user is typing in the room.
+func (c *ChatRoom) setTyping(typing bool) {
+	if typing == c.typing || c.Socket() == nil {
+		return
+	}
+	c.typing = typing
+	presences.GetOrCreate(presenceTopic(c.room)).Update(c.Socket(), map[string]any{"typing": typing})
+}
+
+// Head keeps the browser tab title in sync with the unread count.
+func (c *ChatRoom) Head() core.Head {
+	unread := 0
+	for _, n := range c.unread {
+		unread += n
+	}
+	title := "#" + c.room + " · GoliveKit Chat"
+	if unread > 0 {
+		title = fmt.Sprintf("(%d) %s", unread, title)
+	}
+	return core.Head{Title: title}
+}
+
+// HandleInfo receives new messages, presence diffs (who joins, leaves or
+// types), typing timeouts and receipts for our messages
+// (receipts.Receipt); the router re-renders after it returns.
+func (c *ChatRoom) HandleInfo(ctx context.Context, msg any) error {
+	switch msg := msg.(type) {
+	case core.Broadcast:
+		var m store.Message
+		if err := json.Unmarshal(msg.Data, &m); err != nil || msg.Topic != messagesTopic(m.Room) {
+			return nil
+		}
+		if m.Room != c.room {
+			if m.Username != c.Username {
+				c.unread[m.Room]++
+			}
+			return nil
+		}
+		c.messages = append(c.messages, m)
+		if len(c.messages) > maxMessages {
+			c.messages = c.messages[len(c.messages)-maxMessages:]
+		}
+		c.Assigns().Set("messages", c.messages)
+
+	case typingStopped:
+		if time.Now().After(c.typingUntil) {
+			c.setTyping(false)
+		}
+	}
+	return nil
+}
+
+// loadMessages reads the recent messages of the room from the store.
+func (c *ChatRoom) loadMessages(ctx context.Context) error {
+	recent, err := messages.Recent(ctx, c.room, maxMessages)
+	if err != nil {
+		return fmt.Errorf("failed to load messages: %w", err)
+	}
+	c.messages = recent
+	c.Assigns().Set("messages", recent)
+	return nil
+}
+
+// Terminate cleans up resources. Closing the socket also removes it from
+// the room's presence, on every node.
+func (c *ChatRoom) Terminate(ctx context.Context, reason core.TerminateReason) error {
+	if c.typingTimer != nil {
+		c.typingTimer.Stop()
+	}
+	for _, unwatch := range c.unwatch {
+		unwatch()
+	}
+	if c.unreceipts != nil {
+		c.unreceipts()
+	}
+	return nil
+}
+
+// online returns the distinct people in room and who of them is typing,
+// leaving out the current user.
+func (c *ChatRoom) online(room string) (people, typing []string) {
+	seen := make(map[string]bool)
+	for _, info := range presences.List(presenceTopic(room)) {
+		if info.Username == c.Username && room == c.room {
+			continue
+		}
+		if info.Metas["typing"] == true && !slices.Contains(typing, info.Username) {
+			typing = append(typing, info.Username)
+		}
+		if !seen[info.Username] {
+			seen[info.Username] = true
+			people = append(people, info.Username)
+		}
+	}
+	slices.Sort(people)
+	slices.Sort(typing)
+	return people, typing
+}
+
+// typingLine describes who is typing.
+func typingLine(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0] + " is typing…"
+	case 2:
+		return names[0] + " and " + names[1] + " are typing…"
+	default:
+		return fmt.Sprintf("%s and %d others are typing…", names[0], len(names)-1)
+	}
+}
+
+// Render returns the HTML representation.
+func (c *ChatRoom) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		// Room list with who is in each and the unread counts
+		var roomsHTML strings.Builder
+		for _, room := range rooms {
+			people, _ := c.online(room)
+			count := len(people)
+			if room == c.room {
+				count++
+			}
+			class, badge := "room", ""
+			if room == c.room {
+				class += " active"
+			}
+			if n := c.unread[room]; n > 0 {
+				badge = fmt.Sprintf(`<span class="unread">%d</span>`, n)
+			}
+			fmt.Fprintf(&roomsHTML, `
+                    <button class="%s" lv-click="join_room" lv-value-room="%s">
+                        <span># %s</span>%s<span class="online">%d online</span>
+                    </button>`,
+				class, room, room, badge, count)
+		}
+
+		people, typing := c.online(c.room)
+		whoHTML := "Only you are here"
+		if len(people) > 0 {
+			escaped := make([]string, len(people))
+			for i, name := range people {
+				escaped[i] = html.EscapeString(name)
+			}
+			whoHTML = "You, " + strings.Join(escaped, ", ")
+		}
+
+		// Build messages HTML
+		var messagesHTML strings.Builder
+		if len(c.messages) == 0 {
+			messagesHTML.WriteString(`<p class="no-messages">No messages yet. Be the first to say hello!</p>`)
+		}
+		for _, msg := range c.messages {
+			isOwn := msg.Username == c.Username
+			class := "message"
+			status, receipt := "", fmt.Sprintf(` lv-receipt="%s"`, html.EscapeString(msg.ID))
+			if isOwn {
+				class += " own"
+				receipt = ""
+				if r, ok := chatReceipts.Receipt(msg.ID); ok {
+					status = fmt.Sprintf(`<div class="receipt">%s</div>`, html.EscapeString(r.Summary()))
+				}
+			}
+			fmt.Fprintf(&messagesHTML, `
+                    <div class="%s"%s data-key="%s">
+                        <div class="message-header">
+                            <span class="username">%s</span>
+                            <span class="time">%s</span>
+                        </div>
+                        <div class="content">%s</div>%s
+                    </div>`,
+				class,
+				receipt,
+				html.EscapeString(msg.ID),
+				html.EscapeString(msg.Username),
+				core.LocalTimeAs(msg.Timestamp, core.TimeStyleTime, c.tz),
+				security.RenderMarkdown(msg.Content),
+				status,
+			)
+		}
+
+		tmpl := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+    <title>%s</title>
+    <style>
+        * { box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            margin: 0;
+            padding: 1rem;
+            background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%);
+            min-height: 100vh;
+        }
+        .container {
+            max-width: 960px;
+            margin: 0 auto;
+        }
+        h1 {
+            color: white;
+            text-align: center;
+            margin-bottom: 1rem;
+            text-shadow: 0 2px 4px rgba(0,0,0,0.2);
+        }
+        .chat-box {
+            display: flex;
+            background: white;
+            border-radius: 1rem;
+            box-shadow: 0 10px 40px rgba(0,0,0,0.2);
+            overflow: hidden;
+        }
+        .rooms {
+            width: 200px;
+            padding: 0.75rem;
+            background: #1f2937;
+            display: flex;
+            flex-direction: column;
+            gap: 0.25rem;
+        }
+        .room {
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            gap: 0.25rem 0.5rem;
+            padding: 0.5rem 0.75rem;
+            border: none;
+            border-radius: 0.5rem;
+            background: transparent;
+            color: #d1d5db;
+            font-size: 0.9rem;
+            text-align: start;
+            cursor: pointer;
+        }
+        .room:hover { background: #374151; }
+        .room.active { background: #4f46e5; color: white; }
+        .room .online {
+            width: 100%%;
+            font-size: 0.7rem;
+            color: #9ca3af;
+        }
+        .room.active .online { color: #c7d2fe; }
+        .unread {
+            margin-inline-start: auto;
+            padding: 0 0.4rem;
+            border-radius: 999px;
+            background: #ef4444;
+            color: white;
+            font-size: 0.7rem;
+            font-weight: 600;
+        }
+        .room-main {
+            flex: 1;
+            min-width: 0;
+        }
+        .user-info {
+            padding: 1rem;
+            background: #f3f4f6;
+            border-bottom: 1px solid #e5e7eb;
+            display: flex;
+            align-items: center;
+            gap: 0.5rem;
+        }
+        .user-info label {
+            font-weight: 500;
+            color: #374151;
+        }
+        .user-info input {
+            flex: 1;
+            padding: 0.5rem;
+            border: 1px solid #d1d5db;
+            border-radius: 0.375rem;
+            font-size: 0.875rem;
+        }
+        .who {
+            font-size: 0.8rem;
+            color: #6b7280;
+        }
+        .messages {
+            height: 400px;
+            overflow-y: auto;
+            padding: 1rem;
+            background: #fafafa;
+        }
+        .no-messages {
+            text-align: center;
+            color: #9ca3af;
+            padding: 2rem;
+        }
+        .message {
+            margin-bottom: 0.75rem;
+            padding: 0.75rem;
+            background: white;
+            border-radius: 0.5rem;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.1);
+            max-width: 80%%;
+        }
+        .message.own {
+            background: #dbeafe;
+            margin-left: auto;
+        }
+        .message-header {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 0.25rem;
+            font-size: 0.75rem;
+        }
+        .username {
+            font-weight: 600;
+            color: #4f46e5;
+        }
+        .message.own .username {
+            color: #1e40af;
+        }
+        .time {
+            color: #9ca3af;
+        }
+        .content {
+            color: #374151;
+            word-wrap: break-word;
+        }
+        .receipt {
+            margin-top: 0.25rem;
+            font-size: 0.7rem;
+            color: #6b7280;
+            text-align: end;
+        }
+        .typing {
+            height: 1.5rem;
+            padding: 0.25rem 1rem;
+            font-size: 0.8rem;
+            font-style: italic;
+            color: #6b7280;
+        }
+        .input-area {
+            padding: 1rem;
+            border-top: 1px solid #e5e7eb;
+            display: flex;
+            gap: 0.5rem;
+        }
+        .input-area input {
+            flex: 1;
+            padding: 0.75rem;
+            border: 1px solid #d1d5db;
+            border-radius: 0.5rem;
+            font-size: 1rem;
+        }
+        .input-area input:focus {
+            outline: none;
+            border-color: #4f46e5;
+            box-shadow: 0 0 0 3px rgba(79, 70, 229, 0.1);
+        }
+        .input-area button {
+            padding: 0.75rem 1.5rem;
+            background: #4f46e5;
+            color: white;
+            border: none;
+            border-radius: 0.5rem;
+            font-size: 1rem;
+            cursor: pointer;
+            transition: background 0.15s;
+        }
+        .input-area button:hover {
+            background: #4338ca;
+        }
+    </style>
+</head>
+<body>
+    <div data-live-view="chat">
+        <div class="container">
+            <h1>💬 GoliveKit Chat</h1>
+            <div class="chat-box">
+                <nav class="rooms" data-slot="rooms">%s
+                </nav>
+                <div class="room-main">
+                    <div class="user-info">
+                        <strong># %s</strong>
+                        <span class="who" data-slot="who">%s</span>
+                    </div>
+                    <div class="user-info">
+                        <label>Your name:</label>
+                        <input type="text" value="%s" lv-change="set_username" lv-debounce="500" name="username" />
+                    </div>
+                    <div class="messages" data-slot="messages">%s
+                    </div>
+                    <div class="typing" data-slot="typing" aria-live="polite">%s</div>
+                    <form class="input-area" lv-submit="send_message">
+                        <input type="text" name="message" lv-input="typing" lv-debounce="150" placeholder="Message #%s (**bold**, *italic*, [link](https://...))" autocomplete="off" />
+                        <button type="submit">Send</button>
+                    </form>
+                </div>
+            </div>
+        </div>
+    </div>
+    <script src="/_live/golivekit.js"></script>
+</body>
+</html>`,
+			html.EscapeString(c.Head().Title),
+			roomsHTML.String(),
+			c.room,
+			whoHTML,
+			html.EscapeString(c.Username),
+			messagesHTML.String(),
+			html.EscapeString(typingLine(typing)),
+			c.room,
+		)
+
+		_, err := w.Write([]byte(tmpl))
+		return err
+	})
+}
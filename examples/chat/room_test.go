@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/examples/internal/store"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+// join mounts a chat room for username in room, subscribed to the
+// in-memory backend the way the router would.
+func join(t *testing.T, username, room string) *ChatRoom {
+	t.Helper()
+	socket := core.NewSocket(username, nil)
+	socket.SetSubscriber(func(topic string, deliver func([]byte)) (func(), error) {
+		sub, err := ps.Subscribe(topic, deliver)
+		if err != nil {
+			return nil, err
+		}
+		return func() { sub.Unsubscribe() }, nil
+	})
+	t.Cleanup(func() { socket.Close() })
+
+	c := NewChatRoom().(*ChatRoom)
+	c.SetSocket(socket)
+	params := core.Params{"room": room, "username": username}
+	if err := c.Mount(context.Background(), params, core.Session{}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// await feeds the component its info messages until cond holds.
+func await(t *testing.T, c *ChatRoom, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for !cond() {
+		select {
+		case msg := <-c.Socket().Info():
+			c.HandleInfo(context.Background(), msg)
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestChatRoom_RoomsUnreadAndTyping(t *testing.T) {
+	backend := pubsub.NewMemoryPubSub()
+	defer backend.Close()
+	if err := connect(backend); err != nil {
+		t.Fatal(err)
+	}
+	messages = store.NewMemoryMessages(maxMessages)
+	ctx := context.Background()
+
+	alice := join(t, "alice", "lobby")
+	bob := join(t, "bob", "golang")
+
+	// A message in #golang shows there and counts as unread in the lobby
+	if err := bob.HandleEvent(ctx, "send_message", map[string]any{"message": "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	await(t, bob, "bob's message", func() bool { return len(bob.messages) == 1 })
+	await(t, alice, "the unread count", func() bool { return alice.unread["golang"] == 1 })
+	if len(alice.messages) != 0 {
+		t.Errorf("lobby shows %+v", alice.messages)
+	}
+
+	// Typing shows in the room's presence
+	alice.HandleEvent(ctx, "typing", map[string]any{"value": "h"})
+	if _, typing := bob.online("lobby"); !slices.Equal(typing, []string{"alice"}) {
+		t.Errorf("typing in the lobby = %v, want alice", typing)
+	}
+
+	// Entering #golang loads its history, clears the count and moves
+	// alice's presence
+	if err := alice.HandleParams(ctx, core.Params{"room": "golang"}, core.Navigation{Kind: core.NavigationPatch}); err != nil {
+		t.Fatal(err)
+	}
+	if len(alice.messages) != 1 || alice.unread["golang"] != 0 {
+		t.Errorf("after entering #golang: messages %+v, unread %v", alice.messages, alice.unread)
+	}
+	if people, typing := bob.online("golang"); !slices.Equal(people, []string{"alice"}) || len(typing) != 0 {
+		t.Errorf("#golang people = %v, typing = %v", people, typing)
+	}
+	if people, _ := bob.online("lobby"); len(people) != 0 {
+		t.Errorf("lobby still lists %v", people)
+	}
+}
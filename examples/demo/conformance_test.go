@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/gabrielmiguelok/golivekit/client"
+	"github.com/gabrielmiguelok/golivekit/examples/demo/demos"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// conformanceClient speaks the client side of the protocol: it sends events
+// and keeps the slots, keyed lists and pushed events the way the JavaScript
+// client would apply them.
+type conformanceClient struct {
+	t      *testing.T
+	ws     *websocket.Conn
+	topic  string
+	ref    int
+	slots  map[string]string
+	lists  map[string][]string
+	chunks map[string][]string
+	// streamed holds the slots that arrived as diff_chunk messages
+	streamed map[string]bool
+	pushed   map[string][]map[string]any
+}
+
+// dialConformance joins the kitchen sink at path and returns the client
+// with the joined page.
+func dialConformance(t *testing.T, serverURL, path string) (*conformanceClient, string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(serverURL, "http")+path, nil)
+	if err != nil {
+		t.Fatalf("WebSocket dial failed: %v", err)
+	}
+	ws.SetReadLimit(1 << 20)
+	t.Cleanup(func() { ws.Close(websocket.StatusNormalClosure, "test done") })
+
+	c := &conformanceClient{
+		t:        t,
+		ws:       ws,
+		topic:    "lv:kitchen-sink",
+		slots:    make(map[string]string),
+		lists:    make(map[string][]string),
+		chunks:   make(map[string][]string),
+		streamed: make(map[string]bool),
+		pushed:   make(map[string][]map[string]any),
+	}
+	c.send("phx_join", map[string]any{"join_ref": "1"})
+	reply := c.read(ctx)
+	if reply.Event != "phx_reply" {
+		t.Fatalf("join answered with %q", reply.Event)
+	}
+	if reply.Topic != "" {
+		c.topic = reply.Topic
+	}
+	response, _ := reply.Payload["response"].(map[string]any)
+	rendered, _ := response["rendered"].(map[string]any)
+	statics, _ := rendered["s"].([]any)
+	if len(statics) != 1 {
+		t.Fatalf("join reply without a render: %v", reply.Payload)
+	}
+	page, _ := statics[0].(string)
+	return c, page
+}
+
+// send sends event to the view.
+func (c *conformanceClient) send(event string, payload map[string]any) {
+	c.t.Helper()
+	c.ref++
+	data, err := json.Marshal(transport.Message{Ref: fmt.Sprint(c.ref), Topic: c.topic, Event: event, Payload: payload})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.write(websocket.MessageText, data)
+}
+
+// sendBinary sends event with a binary attachment, as upload chunks travel.
+func (c *conformanceClient) sendBinary(event string, payload map[string]any, data []byte) {
+	c.t.Helper()
+	c.ref++
+	frame, err := transport.EncodeBinary(transport.Message{Ref: fmt.Sprint(c.ref), Topic: c.topic, Event: event, Payload: payload, Binary: data})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.write(websocket.MessageBinary, frame)
+}
+
+func (c *conformanceClient) write(typ websocket.MessageType, data []byte) {
+	c.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.ws.Write(ctx, typ, data); err != nil {
+		c.t.Fatalf("write failed: %v", err)
+	}
+}
+
+func (c *conformanceClient) read(ctx context.Context) transport.Message {
+	c.t.Helper()
+	_, data, err := c.ws.Read(ctx)
+	if err != nil {
+		c.t.Fatalf("read failed: %v", err)
+	}
+	var msg transport.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.t.Fatalf("undecodable message %q: %v", data, err)
+	}
+	return msg
+}
+
+// await applies incoming messages until cond holds.
+func (c *conformanceClient) await(what string, cond func() bool) {
+	c.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for !cond() {
+		if ctx.Err() != nil {
+			c.t.Fatalf("timed out waiting for %s (slots %v, lists %v)", what, c.textSlots(), c.lists)
+		}
+		c.apply(c.read(ctx))
+	}
+}
+
+// awaitSlot waits until the slot id holds want.
+func (c *conformanceClient) awaitSlot(id, want string) {
+	c.t.Helper()
+	c.await(fmt.Sprintf("%s = %q", id, want), func() bool { return c.slots[id] == want })
+}
+
+// textSlots returns the short slots, for failure messages.
+func (c *conformanceClient) textSlots() map[string]string {
+	short := make(map[string]string)
+	for id, content := range c.slots {
+		if len(content) < 200 {
+			short[id] = content
+		}
+	}
+	return short
+}
+
+func (c *conformanceClient) apply(msg transport.Message) {
+	c.t.Helper()
+	switch msg.Event {
+	case "phx_reply":
+		if msg.Payload["status"] == "error" {
+			c.t.Fatalf("event %s failed: %v", msg.Ref, msg.Payload["response"])
+		}
+
+	case "diff":
+		if full, _ := msg.Payload["f"].(string); full != "" {
+			c.t.Fatalf("got a full render instead of a slot diff")
+		}
+		for _, key := range []string{"s", "h"} {
+			slots, _ := msg.Payload[key].(map[string]any)
+			for id, content := range slots {
+				c.slots[id], _ = content.(string)
+			}
+		}
+		lists, _ := msg.Payload["l"].(map[string]any)
+		for id, ops := range lists {
+			ops, _ := ops.([]any)
+			for _, op := range ops {
+				op, _ := op.(map[string]any)
+				c.applyListOp(id, op)
+			}
+		}
+
+	case router.ChunkEvent:
+		id, _ := msg.Payload["id"].(string)
+		i, _ := msg.Payload["i"].(float64)
+		n, _ := msg.Payload["n"].(float64)
+		if c.chunks[id] == nil {
+			c.chunks[id] = make([]string, int(n))
+		}
+		c.chunks[id][int(i)], _ = msg.Payload["c"].(string)
+		if int(i) == int(n)-1 {
+			c.slots[id] = strings.Join(c.chunks[id], "")
+			c.streamed[id] = true
+			delete(c.chunks, id)
+		}
+
+	default:
+		c.pushed[msg.Event] = append(c.pushed[msg.Event], msg.Payload)
+	}
+}
+
+// applyListOp applies a keyed list operation to the list's keys.
+func (c *conformanceClient) applyListOp(id string, op map[string]any) {
+	key, _ := op["k"].(string)
+	index, _ := op["i"].(float64)
+	keys := c.lists[id]
+	remove := func() {
+		for i, k := range keys {
+			if k == key {
+				keys = append(keys[:i], keys[i+1:]...)
+				return
+			}
+		}
+	}
+	switch op["o"] {
+	case "i":
+		at := min(int(index), len(keys))
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+	case "d":
+		remove()
+	case "m":
+		remove()
+		at := min(int(index), len(keys))
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+	}
+	c.lists[id] = keys
+}
+
+func TestKitchenSink_Conformance(t *testing.T) {
+	r := router.New()
+	r.Handle("/_live/", http.StripPrefix("/_live/", client.Handler()))
+	r.Live(demos.KitchenSinkPath, demos.NewKitchenSink)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// The HTTP render carries every control and result
+	resp, err := http.Get(ts.URL + demos.KitchenSinkPath)
+	if err != nil {
+		t.Fatalf("HTTP GET failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	for _, id := range []string{
+		"clicks", "click-inc", "click-dec", "click-add", "click-count",
+		"forms", "form-echo", "echo-value", "form", "form-name", "form-submit", "form-status", "form-saved",
+		"uploads", "upload-input", "upload-count", "upload-files",
+		"hooks", "hook-ping", "hook-state", "hook-pings", "hook-pong",
+		"streams", "stream-append", "stream-count", "stream-items", "bulk-load", "bulk-clear", "bulk",
+		"patches", "patch-overview", "patch-details", "patch-settings", "patch-tab",
+	} {
+		if !strings.Contains(string(body), fmt.Sprintf(`data-testid="%s"`, id)) {
+			t.Errorf("page is missing data-testid=%q", id)
+		}
+	}
+
+	c, page := dialConformance(t, ts.URL, demos.KitchenSinkPath+"?tab=details")
+	if !strings.Contains(page, `data-testid="patch-tab">details<`) {
+		t.Error("join render did not open the tab of the URL")
+	}
+
+	t.Run("clicks", func(t *testing.T) {
+		c.t = t
+		c.send("inc", nil)
+		c.send("inc", nil)
+		c.send("dec", nil)
+		c.awaitSlot("click-count", "1")
+		c.send("add", map[string]any{"by": "5"})
+		c.awaitSlot("click-count", "6")
+	})
+
+	t.Run("forms", func(t *testing.T) {
+		c.t = t
+		c.send("echo", map[string]any{"value": "<b>hi</b>"})
+		c.awaitSlot("echo-value", "&lt;b&gt;hi&lt;/b&gt;")
+		c.send("validate", map[string]any{"value": "Ada"})
+		c.awaitSlot("form-status", "valid")
+		c.send("validate", map[string]any{"value": "A"})
+		c.awaitSlot("form-status", "invalid")
+		c.send("save", map[string]any{"name": "  Ada  "})
+		c.awaitSlot("form-saved", "Ada")
+		c.awaitSlot("form-status", "valid")
+	})
+
+	t.Run("uploads", func(t *testing.T) {
+		c.t = t
+		content := []byte("hello world")
+		c.send("upload_start", map[string]any{
+			"name":  "files",
+			"files": []any{map[string]any{"ref": "0", "name": "hello.txt", "size": len(content), "type": "text/plain"}},
+		})
+		c.sendBinary("upload_chunk", map[string]any{"name": "files", "ref": "0", "offset": 0}, content)
+		c.awaitSlot("upload-count", "1")
+		c.awaitSlot("upload-files", "hello.txt (11 bytes)")
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		c.t = t
+		c.send("hook_mounted", nil)
+		c.awaitSlot("hook-state", "mounted")
+		c.send("hook_ping", nil)
+		c.awaitSlot("hook-pings", "1")
+		c.await("the pong", func() bool { return len(c.pushed[demos.PongEvent]) == 1 })
+		if pings := c.pushed[demos.PongEvent][0]["pings"]; pings != float64(1) {
+			t.Errorf("pong carried pings = %v, want 1", pings)
+		}
+	})
+
+	t.Run("streams", func(t *testing.T) {
+		c.t = t
+		c.send("stream_append", nil)
+		c.send("stream_append", nil)
+		c.send("stream_append", nil)
+		c.awaitSlot("stream-count", "3")
+		c.await("three items", func() bool { return len(c.lists["stream-items"]) == 3 })
+		c.send("stream_remove", map[string]any{"key": "item-2"})
+		c.awaitSlot("stream-count", "2")
+		c.await("item-2 removed", func() bool {
+			return strings.Join(c.lists["stream-items"], ",") == "item-1,item-3"
+		})
+
+		// A slot over the streaming threshold arrives in chunks
+		c.send("bulk_load", nil)
+		c.await("the streamed rows", func() bool { return c.streamed["bulk"] })
+		bulk := c.slots["bulk"]
+		if len(bulk) <= router.DefaultSlotStreamThreshold {
+			t.Errorf("bulk slot is %d bytes, under the streaming threshold", len(bulk))
+		}
+		if !strings.HasSuffix(bulk, `Row 2000 of the streamed slot</div>`) {
+			t.Error("streamed rows were not reassembled in order")
+		}
+		c.send("bulk_clear", nil)
+		c.awaitSlot("bulk", `<p class="ks-result">Nothing loaded</p>`)
+	})
+
+	t.Run("patches", func(t *testing.T) {
+		c.t = t
+		c.send("tab", map[string]any{"tab": "settings"})
+		c.await("the patch", func() bool { return len(c.pushed[core.PatchEvent]) == 1 })
+		to, _ := c.pushed[core.PatchEvent][0]["to"].(string)
+		if to != demos.KitchenSinkPath+"?tab=settings" {
+			t.Fatalf("patched to %q", to)
+		}
+		c.awaitSlot("patch-tab", "settings")
+
+		// The client reports the URL it patched, then the back button
+		c.send(core.ParamsEvent, map[string]any{"url": to, "kind": string(core.NavigationPatch)})
+		c.send(core.ParamsEvent, map[string]any{"url": demos.KitchenSinkPath + "?tab=details", "kind": string(core.NavigationPopState)})
+		c.awaitSlot("patch-tab", "details")
+	})
+}
@@ -175,6 +175,11 @@ func (d *LiveDashboard) HandleEvent(ctx context.Context, event string, payload m
 	return nil
 }
 
+// Styles returns the component's co-located CSS.
+func (d *LiveDashboard) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{dashboardStyles}
+}
+
 // Render returns the HTML representation.
 func (d *LiveDashboard) Render(ctx context.Context) core.Renderer {
 	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
@@ -197,13 +202,12 @@ func (d *LiveDashboard) renderDashboard() string {
 	}
 
 	body := d.renderDashboardBody()
-	return website.RenderDocument(cfg, renderDashboardStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderDashboardStyles returns custom CSS
-func renderDashboardStyles() string {
-	return `
-<style>
+// dashboardStyles is the dashboard demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var dashboardStyles = core.NewStylesheet("dashboard", `
 .dashboard-container {
 	max-width: 1200px;
 	margin: 0 auto;
@@ -561,9 +565,7 @@ func renderDashboardStyles() string {
 	padding: 0 0.5rem;
 	margin-top: 0.25rem;
 }
-</style>
-`
-}
+`)
 
 // renderDashboardBody generates the main content
 func (d *LiveDashboard) renderDashboardBody() string {
@@ -607,7 +609,7 @@ func (d *LiveDashboard) renderDashboardBody() string {
 	memSys := formatBytes(int64(m.Sys))
 
 	content := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + dashboardStyles.Scope() + `">
 <div class="dashboard-container" data-live-view="live-dashboard">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -327,6 +327,11 @@ func (e *CollabEditor) HandleEvent(ctx context.Context, event string, payload ma
 	return nil
 }
 
+// Styles returns the component's co-located CSS.
+func (e *CollabEditor) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{editorStyles}
+}
+
 // Render returns the HTML representation.
 func (e *CollabEditor) Render(ctx context.Context) core.Renderer {
 	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
@@ -349,13 +354,12 @@ func (e *CollabEditor) renderEditor() string {
 	}
 
 	body := e.renderEditorBody()
-	return website.RenderDocument(cfg, renderEditorStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderEditorStyles returns custom CSS
-func renderEditorStyles() string {
-	return `
-<style>
+// editorStyles is the editor demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var editorStyles = core.NewStylesheet("editor", `
 .editor-container {
 	max-width: 1000px;
 	margin: 0 auto;
@@ -764,9 +768,7 @@ func renderEditorStyles() string {
 	outline: none;
 	border-color: var(--color-primary);
 }
-</style>
-`
-}
+`)
 
 // renderEditorBody generates the main content
 func (e *CollabEditor) renderEditorBody() string {
@@ -815,7 +817,7 @@ func (e *CollabEditor) renderEditorBody() string {
 	}
 
 	mainContent := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + editorStyles.Scope() + `">
 <div class="editor-container" data-live-view="collab-editor">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -114,6 +114,57 @@ func (f *FormsWizard) Terminate(ctx context.Context, reason core.TerminateReason
 	return nil
 }
 
+// Snapshot keeps the wizard's progress when the connection drops, so the
+// reconnecting page continues where it was. Passwords are left out and
+// typed again.
+func (f *FormsWizard) Snapshot() map[string]any {
+	return map[string]any{
+		"step":      int(f.CurrentStep),
+		"email":     f.Email,
+		"full_name": f.FullName,
+		"username":  f.Username,
+		"bio":       f.Bio,
+		"theme":     f.Theme,
+		"language":  f.Language,
+		"notify": []bool{
+			f.Notifications.Email, f.Notifications.Push,
+			f.Notifications.SMS, f.Notifications.Weekly,
+		},
+	}
+}
+
+// Restore applies a snapshot after Mount. The first step is incomplete
+// until the password is typed again.
+func (f *FormsWizard) Restore(state map[string]any) error {
+	str := func(key string) string {
+		s, _ := state[key].(string)
+		return s
+	}
+	f.Email = str("email")
+	f.FullName = str("full_name")
+	f.Username = str("username")
+	f.Bio = str("bio")
+	if theme := str("theme"); theme != "" {
+		f.Theme = theme
+	}
+	if lang := str("language"); lang != "" {
+		f.Language = lang
+	}
+	if notify, ok := state["notify"].([]any); ok && len(notify) == 4 {
+		f.Notifications.Email, _ = notify[0].(bool)
+		f.Notifications.Push, _ = notify[1].(bool)
+		f.Notifications.SMS, _ = notify[2].(bool)
+		f.Notifications.Weekly, _ = notify[3].(bool)
+	}
+	if step, ok := state["step"].(float64); ok && step >= float64(StepBasics) && step <= float64(StepReview) {
+		f.CurrentStep = WizardStep(step)
+	}
+	for step := StepProfile; step < f.CurrentStep; step++ {
+		f.StepComplete[step] = true
+	}
+	return nil
+}
+
 // HandleEvent handles user interactions.
 func (f *FormsWizard) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
 	switch event {
@@ -469,6 +520,11 @@ func isValidUsername(username string) bool {
 	return usernameRegex.MatchString(username)
 }
 
+// Styles returns the component's co-located CSS.
+func (f *FormsWizard) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{formsStyles}
+}
+
 // Render returns the HTML representation.
 func (f *FormsWizard) Render(ctx context.Context) core.Renderer {
 	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
@@ -491,13 +547,12 @@ func (f *FormsWizard) renderWizard() string {
 	}
 
 	body := f.renderWizardBody()
-	return website.RenderDocument(cfg, renderFormsStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderFormsStyles returns custom CSS
-func renderFormsStyles() string {
-	return `
-<style>
+// formsStyles is the forms demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var formsStyles = core.NewStylesheet("forms", `
 .wizard-container {
 	max-width: 700px;
 	margin: 0 auto;
@@ -950,9 +1005,7 @@ func renderFormsStyles() string {
 	text-align: center;
 	margin-top: 1rem;
 }
-</style>
-`
-}
+`)
 
 // renderWizardBody generates the main content
 func (f *FormsWizard) renderWizardBody() string {
@@ -975,7 +1028,7 @@ func (f *FormsWizard) renderWizardBody() string {
 	}
 
 	content := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + formsStyles.Scope() + `">
 <div class="wizard-container" data-live-view="forms-wizard">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -1486,7 +1539,7 @@ func (f *FormsWizard) renderStepReview() string {
 // renderSuccessScreen renders the success message
 func (f *FormsWizard) renderSuccessScreen() string {
 	return fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + formsStyles.Scope() + `">
 <div class="wizard-container" data-live-view="forms-wizard">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -13,6 +13,7 @@ import (
 	"github.com/gabrielmiguelok/golivekit/internal/website"
 	"github.com/gabrielmiguelok/golivekit/internal/website/components"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/gameloop"
 )
 
 // Direction constants for snake movement
@@ -347,12 +348,22 @@ func (g *SnakeGame) handleKey(key string) {
 
 // HandleInfo handles internal messages (for game tick via PubSub or timer)
 func (g *SnakeGame) HandleInfo(ctx context.Context, msg any) error {
-	if msg == "tick" {
+	switch msg.(type) {
+	case gameloop.Tick:
 		g.tick()
+	case string:
+		if msg == "tick" {
+			g.tick()
+		}
 	}
 	return nil
 }
 
+// Styles returns the component's co-located CSS.
+func (g *SnakeGame) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{gameStyles}
+}
+
 // Render returns the HTML representation.
 func (g *SnakeGame) Render(ctx context.Context) core.Renderer {
 	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
@@ -375,13 +386,12 @@ func (g *SnakeGame) renderGame() string {
 	}
 
 	body := g.renderGameBody()
-	return website.RenderDocument(cfg, renderGameStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderGameStyles returns custom CSS for the game
-func renderGameStyles() string {
-	return `
-<style>
+// gameStyles is the game demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var gameStyles = core.NewStylesheet("game", `
 .game-container {
 	max-width: 900px;
 	margin: 0 auto;
@@ -675,9 +685,7 @@ func renderGameStyles() string {
 	0%, 100% { opacity: 1; }
 	50% { opacity: 0.5; }
 }
-</style>
-`
-}
+`)
 
 // renderGameBody generates the main game content
 func (g *SnakeGame) renderGameBody() string {
@@ -697,7 +705,7 @@ func (g *SnakeGame) renderGameBody() string {
 	players := gamePlayers.Load()
 
 	content := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + gameStyles.Scope() + `">
 <div class="game-container" data-live-view="snake-game" lv-keydown="keydown" tabindex="0">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
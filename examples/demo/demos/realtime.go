@@ -7,12 +7,13 @@ import (
 	"io"
 	"sort"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/gabrielmiguelok/golivekit/internal/website"
 	"github.com/gabrielmiguelok/golivekit/internal/website/components"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/presence"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
 )
 
 // UserStatus represents a user's current status
@@ -24,15 +25,16 @@ const (
 	StatusTyping
 )
 
-// PlaylistUser represents a user in the playlist room
-type PlaylistUser struct {
-	ID        string
-	Name      string
-	Color     string
-	Status    UserStatus
-	JoinedAt  time.Time
-	IsHost    bool
-	LastSeen  time.Time
+// String returns the status name kept in the user's presence metas.
+func (s UserStatus) String() string {
+	switch s {
+	case StatusAway:
+		return "away"
+	case StatusTyping:
+		return "typing"
+	default:
+		return "online"
+	}
 }
 
 // Song represents a song in the playlist
@@ -56,15 +58,20 @@ type ChatMessage struct {
 	Timestamp time.Time
 }
 
+// playlistTopic is the presence topic of the playlist room.
+const playlistTopic = "playlist:room"
+
+// playlistPresence tracks who is in the room. With a shared pubsub (Redis,
+// NATS) instead of the in-memory one, it lists the users of every node.
+var playlistPresence = presence.NewPresenceManager(pubsub.NewMemoryPubSub())
+
 // Global playlist state (simulated PubSub)
 var (
-	playlistUsers    = make(map[string]*PlaylistUser)
 	playlistSongs    = []*Song{}
 	playlistChat     = []ChatMessage{}
 	playlistNowPlaying = 0 // Index of currently playing song
 	playlistProgress = 0   // Playback progress in seconds
 	playlistMu       sync.RWMutex
-	playlistListeners atomic.Int64
 	userColors       = []string{"#ef4444", "#f97316", "#eab308", "#22c55e", "#06b6d4", "#3b82f6", "#8b5cf6", "#ec4899"}
 	colorIndex       int
 )
@@ -105,6 +112,7 @@ type RealtimePlaylist struct {
 	UserName    string
 	UserColor   string
 	JoinedAt    time.Time
+	IsHost      bool
 	IsTyping    bool
 	CurrentTab  string // "queue" or "chat"
 
@@ -136,22 +144,33 @@ func (p *RealtimePlaylist) Mount(ctx context.Context, params core.Params, sessio
 	playlistMu.Lock()
 	p.UserColor = userColors[colorIndex%len(userColors)]
 	colorIndex++
+	playlistMu.Unlock()
 
-	// First user is host
-	isHost := len(playlistUsers) == 0
-
-	playlistUsers[p.UserID] = &PlaylistUser{
-		ID:       p.UserID,
-		Name:     p.UserName,
-		Color:    p.UserColor,
-		Status:   StatusOnline,
-		JoinedAt: p.JoinedAt,
-		IsHost:   isHost,
-		LastSeen: time.Now(),
+	// Only connected sockets are in the room, not the initial HTTP render
+	socket := p.Socket()
+	if socket == nil {
+		return nil
 	}
-	playlistMu.Unlock()
 
-	playlistListeners.Add(1)
+	// First user is host
+	room := playlistPresence.GetOrCreate(playlistTopic)
+	p.IsHost = room.Count() == 0
+
+	// Presence untracks the socket when it closes and delivers joins and
+	// leaves to HandleInfo, from this node and the others
+	room.Watch(socket)
+	err := room.Track(socket, presence.PresenceInfo{
+		UserID:   p.UserID,
+		Username: p.UserName,
+		Metas: map[string]any{
+			"color":  p.UserColor,
+			"status": StatusOnline.String(),
+			"host":   p.IsHost,
+		},
+	})
+	if err != nil {
+		return err
+	}
 
 	// Add join message to chat
 	p.addSystemMessage(fmt.Sprintf("%s joined the room", p.UserName))
@@ -159,13 +178,16 @@ func (p *RealtimePlaylist) Mount(ctx context.Context, params core.Params, sessio
 	return nil
 }
 
+// HandleInfo re-renders when users join, leave or change.
+func (p *RealtimePlaylist) HandleInfo(ctx context.Context, msg any) error {
+	return nil
+}
+
 // Terminate handles cleanup.
 func (p *RealtimePlaylist) Terminate(ctx context.Context, reason core.TerminateReason) error {
-	playlistMu.Lock()
-	delete(playlistUsers, p.UserID)
-	playlistMu.Unlock()
-
-	playlistListeners.Add(-1)
+	if p.Socket() == nil {
+		return nil
+	}
 
 	// Add leave message
 	p.addSystemMessage(fmt.Sprintf("%s left the room", p.UserName))
@@ -173,6 +195,13 @@ func (p *RealtimePlaylist) Terminate(ctx context.Context, reason core.TerminateR
 	return nil
 }
 
+// updatePresence merges metas into the user's presence entry.
+func (p *RealtimePlaylist) updatePresence(metas map[string]any) {
+	if socket := p.Socket(); socket != nil {
+		playlistPresence.GetOrCreate(playlistTopic).Update(socket, metas)
+	}
+}
+
 // addSystemMessage adds a system message to chat
 func (p *RealtimePlaylist) addSystemMessage(text string) {
 	playlistMu.Lock()
@@ -228,11 +257,7 @@ func (p *RealtimePlaylist) HandleEvent(ctx context.Context, event string, payloa
 		if val, ok := payload["value"].(string); ok && len(val) > 0 && len(val) <= 20 {
 			oldName := p.UserName
 			p.UserName = val
-			playlistMu.Lock()
-			if user, ok := playlistUsers[p.UserID]; ok {
-				user.Name = val
-			}
-			playlistMu.Unlock()
+			p.updatePresence(map[string]any{"name": val})
 			p.addSystemMessage(fmt.Sprintf("%s is now known as %s", oldName, val))
 		}
 
@@ -326,7 +351,7 @@ func (p *RealtimePlaylist) handleRemoveSong(songID string) {
 	for i, song := range playlistSongs {
 		if song.ID == songID {
 			// Only allow removing if user added it or is host
-			if song.AddedBy == p.UserName || (playlistUsers[p.UserID] != nil && playlistUsers[p.UserID].IsHost) {
+			if song.AddedBy == p.UserName || p.IsHost {
 				playlistSongs = append(playlistSongs[:i], playlistSongs[i+1:]...)
 				if playlistNowPlaying >= len(playlistSongs) {
 					playlistNowPlaying = 0
@@ -371,16 +396,16 @@ func (p *RealtimePlaylist) updateTypingStatus(isTyping bool) {
 	}
 	p.IsTyping = isTyping
 
-	playlistMu.Lock()
-	if user, ok := playlistUsers[p.UserID]; ok {
-		if isTyping {
-			user.Status = StatusTyping
-		} else {
-			user.Status = StatusOnline
-		}
-		user.LastSeen = time.Now()
+	status := StatusOnline
+	if isTyping {
+		status = StatusTyping
 	}
-	playlistMu.Unlock()
+	p.updatePresence(map[string]any{"status": status.String()})
+}
+
+// Styles returns the component's co-located CSS.
+func (p *RealtimePlaylist) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{playlistStyles}
 }
 
 // Render returns the HTML representation.
@@ -405,13 +430,12 @@ func (p *RealtimePlaylist) renderPlaylist() string {
 	}
 
 	body := p.renderPlaylistBody()
-	return website.RenderDocument(cfg, renderPlaylistStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderPlaylistStyles returns custom CSS
-func renderPlaylistStyles() string {
-	return `
-<style>
+// playlistStyles is the playlist demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var playlistStyles = core.NewStylesheet("playlist", `
 .playlist-container {
 	max-width: 1100px;
 	margin: 0 auto;
@@ -849,9 +873,7 @@ func renderPlaylistStyles() string {
 	padding: 0.5rem 1rem;
 	border-top: 1px solid var(--color-border);
 }
-</style>
-`
-}
+`)
 
 // renderPlaylistBody generates the main content
 func (p *RealtimePlaylist) renderPlaylistBody() string {
@@ -868,7 +890,7 @@ func (p *RealtimePlaylist) renderPlaylistBody() string {
 		},
 	})
 
-	listeners := playlistListeners.Load()
+	listeners := playlistPresence.GetOrCreate(playlistTopic).Count()
 
 	playlistMu.RLock()
 	nowPlaying := p.renderNowPlaying()
@@ -884,7 +906,7 @@ func (p *RealtimePlaylist) renderPlaylistBody() string {
 	}
 
 	content := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + playlistStyles.Scope() + `">
 <div class="playlist-container" data-live-view="realtime-playlist">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -1093,24 +1115,37 @@ func (p *RealtimePlaylist) renderChat() string {
 	return `<div class="chat-messages">` + html + `</div>`
 }
 
+// playlistUsers returns the users in the room on every node, in join order.
+func playlistUsers() []presence.PresenceInfo {
+	users := playlistPresence.GetOrCreate(playlistTopic).List()
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].OnlineAt.Before(users[j].OnlineAt)
+	})
+	return users
+}
+
+// userName returns a user's current name: renames are kept in the metas.
+func userName(user presence.PresenceInfo) string {
+	if name, ok := user.Metas["name"].(string); ok {
+		return name
+	}
+	return user.Username
+}
+
 // renderUsers renders the users list
 func (p *RealtimePlaylist) renderUsers() string {
 	var html string
-	for _, user := range playlistUsers {
-		statusClass := "online"
-		if user.Status == StatusAway {
-			statusClass = "away"
-		} else if user.Status == StatusTyping {
-			statusClass = "typing"
-		}
+	for _, user := range playlistUsers() {
+		statusClass, _ := user.Metas["status"].(string)
+		color, _ := user.Metas["color"].(string)
 
 		badge := ""
-		if user.IsHost {
+		if host, _ := user.Metas["host"].(bool); host {
 			badge = `<span class="user-badge">host</span>`
 		}
 
 		isSelf := ""
-		if user.ID == p.UserID {
+		if user.UserID == p.UserID {
 			isSelf = " (you)"
 		}
 
@@ -1120,7 +1155,7 @@ func (p *RealtimePlaylist) renderUsers() string {
 	<span class="user-name" style="color:%s">%s%s</span>
 	%s
 </div>
-`, statusClass, user.Color, user.Name, isSelf, badge)
+`, statusClass, color, userName(user), isSelf, badge)
 	}
 
 	return html
@@ -1129,9 +1164,9 @@ func (p *RealtimePlaylist) renderUsers() string {
 // getTypingUsers returns a string of users currently typing
 func (p *RealtimePlaylist) getTypingUsers() string {
 	var typing []string
-	for _, user := range playlistUsers {
-		if user.Status == StatusTyping && user.ID != p.UserID {
-			typing = append(typing, user.Name)
+	for _, user := range playlistUsers() {
+		if user.Metas["status"] == StatusTyping.String() && user.UserID != p.UserID {
+			typing = append(typing, userName(user))
 		}
 	}
 
@@ -4,73 +4,70 @@ package demos
 import (
 	"context"
 	"fmt"
+	"html"
 	"io"
-	"math/rand"
-	"runtime"
-	"sync/atomic"
-	"time"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/gabrielmiguelok/golivekit/internal/website"
 	"github.com/gabrielmiguelok/golivekit/internal/website/components"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/uploads"
 )
 
-// BenchmarkResult represents benchmark test results
-type BenchmarkResult struct {
-	TotalEvents   int
-	TotalTime     time.Duration
-	AvgLatency    time.Duration
-	P95Latency    time.Duration
-	P99Latency    time.Duration
-	Throughput    float64
-	Errors        int
-	BytesSent     int64
-	BytesReceived int64
-}
+// KitchenSinkPath is the route of the kitchen sink, which patches its own
+// URL when a tab is picked.
+const KitchenSinkPath = "/demos/showcase"
+
+// PongEvent is pushed to the client each time the hook section is pinged.
+const PongEvent = "ks:pong"
+
+// bulkRows is how many rows the bulk section loads: enough to go over the
+// router's slot streaming threshold, so they arrive as diff_chunk messages.
+const bulkRows = 2000
+
+// kitchenSinkTabs are the tabs of the patch section, the first one being
+// the default.
+var kitchenSinkTabs = []string{"overview", "details", "settings"}
 
-// KitchenSink is the kitchen sink demo component.
+// KitchenSink is the protocol conformance fixture: every client binding
+// (clicks, forms, uploads, hooks, streams, patches) in one route, driven by
+// the e2e tests. Controls and results carry stable data-testid attributes,
+// and each result is a slot named after its test id, so tests read it from
+// the diffs. The state is deterministic: no timers, randomness or counters
+// shared between visitors.
 type KitchenSink struct {
 	core.BaseComponent
 
-	// Mini demos state
-	CounterValue    int
-	FormName        string
-	FormValidated   bool
-	ListItems       []string
-	PresenceUsers   []string
-
-	// Metrics state
-	MetricEvents    int64
-	MetricLatency   time.Duration
-	MetricCacheHits int
-	MetricMisses    int
-
-	// Benchmark state
-	BenchRunning     bool
-	BenchEventsPerSec int
-	BenchPayloadKB   int
-	BenchConcurrent  int
-	BenchProgress    int
-	BenchResult      *BenchmarkResult
-
-	// Connection info
-	ConnectedAt time.Time
-	EventsTotal atomic.Int64
-}
-
-// Global showcase stats
-var (
-	showcaseVisitors atomic.Int64
-	showcaseEvents   atomic.Int64
-)
+	// Clicks
+	Count int
+
+	// Forms
+	Echo  string
+	Valid bool
+	Saved string
+
+	// Uploads receives the files of the upload section
+	uploads  *uploads.Uploader
+	Uploaded []string
+
+	// Hooks
+	HookMounted bool
+	Pings       int
+
+	// Streams: a keyed list and a slot large enough to be streamed
+	Items      []string
+	nextItem   int
+	BulkLoaded bool
+
+	// Patches
+	Tab string
+}
 
 // NewKitchenSink creates a new kitchen sink component.
 func NewKitchenSink() core.Component {
-	return &KitchenSink{
-		BenchEventsPerSec: 50,
-		BenchPayloadKB:    2,
-		BenchConcurrent:   10,
-	}
+	return &KitchenSink{Tab: kitchenSinkTabs[0]}
 }
 
 // Name returns the component name.
@@ -80,161 +77,134 @@ func (k *KitchenSink) Name() string {
 
 // Mount initializes the component.
 func (k *KitchenSink) Mount(ctx context.Context, params core.Params, session core.Session) error {
-	k.ConnectedAt = time.Now()
+	k.uploads = uploads.NewUploader(discardStore{})
+	k.uploads.AllowUpload("files", uploads.AllowOptions{MaxFileSize: 1 << 20, MaxEntries: 3})
+	k.Tab = tabParam(params)
+	return nil
+}
 
-	// Initialize mini demos
-	k.CounterValue = 0
-	k.ListItems = []string{"Item 1", "Item 2", "Item 3"}
-	k.PresenceUsers = []string{"Alice", "Bob", "Charlie"}
+// tabParam returns the tab named by params, or the default tab.
+func tabParam(params core.Params) string {
+	if tab := params.Get("tab"); slices.Contains(kitchenSinkTabs, tab) {
+		return tab
+	}
+	return kitchenSinkTabs[0]
+}
 
-	showcaseVisitors.Add(1)
+// HandleParams follows the tab in the URL, on patches and on back/forward.
+func (k *KitchenSink) HandleParams(ctx context.Context, params core.Params, nav core.Navigation) error {
+	k.Tab = tabParam(params)
 	return nil
 }
 
-// Terminate handles cleanup.
+// Terminate removes the files that were never consumed.
 func (k *KitchenSink) Terminate(ctx context.Context, reason core.TerminateReason) error {
-	showcaseVisitors.Add(-1)
+	if k.uploads != nil {
+		k.uploads.Close()
+	}
 	return nil
 }
 
 // HandleEvent handles user interactions.
 func (k *KitchenSink) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
-	k.EventsTotal.Add(1)
-	showcaseEvents.Add(1)
-
-	switch event {
-	// Counter mini-demo
-	case "counter_inc":
-		k.CounterValue++
-	case "counter_dec":
-		k.CounterValue--
-	case "counter_reset":
-		k.CounterValue = 0
-
-	// Form mini-demo
-	case "form_update":
-		if val, ok := payload["value"].(string); ok {
-			k.FormName = val
-			k.FormValidated = len(val) >= 3
-		}
-
-	// List mini-demo
-	case "list_add":
-		k.ListItems = append(k.ListItems, fmt.Sprintf("Item %d", len(k.ListItems)+1))
-	case "list_remove":
-		if idx, ok := payload["index"].(float64); ok && int(idx) < len(k.ListItems) {
-			k.ListItems = append(k.ListItems[:int(idx)], k.ListItems[int(idx)+1:]...)
-		}
-
-	// Presence mini-demo (simulated)
-	case "presence_join":
-		names := []string{"Diana", "Eve", "Frank", "Grace"}
-		k.PresenceUsers = append(k.PresenceUsers, names[rand.Intn(len(names))])
-	case "presence_leave":
-		if len(k.PresenceUsers) > 1 {
-			k.PresenceUsers = k.PresenceUsers[:len(k.PresenceUsers)-1]
-		}
-
-	// Benchmark controls
-	case "bench_events":
-		if val, ok := payload["value"].(float64); ok {
-			k.BenchEventsPerSec = int(val)
-			if k.BenchEventsPerSec < 1 {
-				k.BenchEventsPerSec = 1
-			}
-			if k.BenchEventsPerSec > 100 {
-				k.BenchEventsPerSec = 100
-			}
+	if uploads.IsUploadEvent(event) {
+		if _, err := k.uploads.HandleEvent(event, payload); err != nil {
+			return err
 		}
+		return k.consumeUploads(ctx)
+	}
 
-	case "bench_payload":
-		if val, ok := payload["value"].(float64); ok {
-			k.BenchPayloadKB = int(val)
-			if k.BenchPayloadKB < 1 {
-				k.BenchPayloadKB = 1
-			}
-			if k.BenchPayloadKB > 10 {
-				k.BenchPayloadKB = 10
-			}
+	switch event {
+	// Clicks: lv-click, with lv-value-by for the step
+	case "inc":
+		k.Count++
+	case "dec":
+		k.Count--
+	case "add":
+		by, err := strconv.Atoi(fmt.Sprint(payload["by"]))
+		if err != nil {
+			return fmt.Errorf("kitchen-sink: add: %w", err)
 		}
-
-	case "bench_concurrent":
-		if val, ok := payload["value"].(float64); ok {
-			k.BenchConcurrent = int(val)
-			if k.BenchConcurrent < 1 {
-				k.BenchConcurrent = 1
-			}
-			if k.BenchConcurrent > 50 {
-				k.BenchConcurrent = 50
-			}
+		k.Count += by
+
+	// Forms: lv-input, lv-change and lv-submit
+	case "echo":
+		k.Echo, _ = payload["value"].(string)
+	case "validate":
+		value, _ := payload["value"].(string)
+		k.Valid = len(strings.TrimSpace(value)) >= 3
+	case "save":
+		name, _ := payload["name"].(string)
+		k.Valid = len(strings.TrimSpace(name)) >= 3
+		if k.Valid {
+			k.Saved = strings.TrimSpace(name)
 		}
 
-	case "bench_start":
-		if !k.BenchRunning {
-			k.startBenchmark()
+	// Hooks: the KitchenSinkPing hook reports it mounted, pings are
+	// answered with a pushed event the hook listens to
+	case "hook_mounted":
+		k.HookMounted = true
+	case "hook_ping":
+		k.Pings++
+		if socket := k.Socket(); socket != nil {
+			return socket.Push(PongEvent, map[string]any{"pings": k.Pings})
 		}
 
-	case "bench_stop":
-		k.BenchRunning = false
-
-	case "bench_reset":
-		k.BenchRunning = false
-		k.BenchProgress = 0
-		k.BenchResult = nil
-
-	case "bench_progress":
-		if progress, ok := payload["progress"].(float64); ok {
-			k.BenchProgress = int(progress)
+	// Streams
+	case "stream_append":
+		k.nextItem++
+		k.Items = append(k.Items, fmt.Sprintf("item-%d", k.nextItem))
+	case "stream_remove":
+		key, _ := payload["key"].(string)
+		k.Items = slices.DeleteFunc(k.Items, func(item string) bool { return item == key })
+	case "bulk_load":
+		k.BulkLoaded = true
+	case "bulk_clear":
+		k.BulkLoaded = false
+
+	// Patches: the URL follows the tab
+	case "tab":
+		tab, _ := payload["tab"].(string)
+		if !slices.Contains(kitchenSinkTabs, tab) {
+			return nil
 		}
-
-	case "tick":
-		// Update metrics
-		k.updateMetrics()
+		k.Tab = tab
+		return k.Socket().PushPatch(KitchenSinkPath + "?tab=" + tab)
 	}
 
 	return nil
 }
 
-// startBenchmark starts a simulated benchmark
-func (k *KitchenSink) startBenchmark() {
-	k.BenchRunning = true
-	k.BenchProgress = 0
-	k.BenchResult = nil
-
-	// Simulate benchmark (would normally run actual tests)
-	go func() {
-		startTime := time.Now()
-		totalEvents := k.BenchEventsPerSec * 20 // 20 second test
+// consumeUploads records the fully received files.
+func (k *KitchenSink) consumeUploads(ctx context.Context) error {
+	entries, err := k.uploads.Consume(ctx, "files")
+	for _, entry := range entries {
+		k.Uploaded = append(k.Uploaded, fmt.Sprintf("%s (%d bytes)", entry.FileName, entry.Size))
+	}
+	return err
+}
 
-		for i := 0; i < 100 && k.BenchRunning; i++ {
-			time.Sleep(200 * time.Millisecond)
-			k.BenchProgress = i + 1
-		}
+// GetLists returns the stream section's keyed list, sent as list
+// operations instead of a re-rendered slot.
+func (k *KitchenSink) GetLists() map[string][]core.ListItem {
+	items := make([]core.ListItem, len(k.Items))
+	for i, key := range k.Items {
+		items[i] = core.ListItem{Key: key, Content: renderStreamItem(key)}
+	}
+	return map[string][]core.ListItem{"stream-items": items}
+}
 
-		if k.BenchRunning {
-			elapsed := time.Since(startTime)
-			k.BenchResult = &BenchmarkResult{
-				TotalEvents:   totalEvents,
-				TotalTime:     elapsed,
-				AvgLatency:    time.Duration(3+rand.Intn(5)) * time.Millisecond,
-				P95Latency:    time.Duration(8+rand.Intn(5)) * time.Millisecond,
-				P99Latency:    time.Duration(12+rand.Intn(8)) * time.Millisecond,
-				Throughput:    float64(totalEvents) / elapsed.Seconds(),
-				Errors:        0,
-				BytesSent:     int64(totalEvents * k.BenchPayloadKB * 1024),
-				BytesReceived: int64(totalEvents * 100),
-			}
-		}
-		k.BenchRunning = false
-	}()
+// renderStreamItem renders an item of the stream section's list.
+func renderStreamItem(key string) string {
+	return fmt.Sprintf(`<div class="list-item" data-key="%s" data-testid="stream-item">
+	<span>%s</span>
+	<button class="list-remove" lv-click="stream_remove" lv-value-key="%s" data-testid="stream-remove">✕</button>
+</div>`, key, key, key)
 }
 
-// updateMetrics updates the live metrics
-func (k *KitchenSink) updateMetrics() {
-	k.MetricEvents = k.EventsTotal.Load()
-	k.MetricLatency = time.Duration(2+rand.Intn(5)) * time.Millisecond
-	k.MetricCacheHits = 80 + rand.Intn(15)
-	k.MetricMisses = 100 - k.MetricCacheHits
+// Styles returns the component's co-located CSS.
+func (k *KitchenSink) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{kitchenSinkStyles}
 }
 
 // Render returns the HTML representation.
@@ -250,22 +220,21 @@ func (k *KitchenSink) Render(ctx context.Context) core.Renderer {
 func (k *KitchenSink) renderKitchenSink() string {
 	cfg := website.PageConfig{
 		Title:       "Kitchen Sink - GoliveKit Demo",
-		Description: "Comprehensive showcase of all GoliveKit features with benchmarking.",
+		Description: "Every GoliveKit binding in one page: clicks, forms, uploads, hooks, streams and patches.",
 		URL:         "https://golivekit.cloud/demos/showcase",
-		Keywords:    []string{"showcase", "benchmark", "all-features", "liveview"},
+		Keywords:    []string{"showcase", "conformance", "all-features", "liveview"},
 		Author:      "Gabriel Miguel",
 		Language:    "en",
 		ThemeColor:  "#8B5CF6",
 	}
 
 	body := k.renderKitchenSinkBody()
-	return website.RenderDocument(cfg, renderKitchenSinkStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderKitchenSinkStyles returns custom CSS
-func renderKitchenSinkStyles() string {
-	return `
-<style>
+// kitchenSinkStyles is the kitchen sink's scoped CSS, injected into the document head
+// by the router via Styles.
+var kitchenSinkStyles = core.NewStylesheet("kitchen-sink", `
 .ks-container {
 	max-width: 1200px;
 	margin: 0 auto;
@@ -350,58 +319,6 @@ func renderKitchenSinkStyles() string {
 	gap: 0.5rem;
 }
 
-.metrics-grid {
-	display: grid;
-	grid-template-columns: repeat(2, 1fr);
-	gap: 1rem;
-}
-
-.metric-item {
-	background: var(--color-bg);
-	padding: 1rem;
-	border-radius: 0.5rem;
-	text-align: center;
-}
-
-.metric-label {
-	font-size: 0.75rem;
-	color: var(--color-textMuted);
-	text-transform: uppercase;
-	letter-spacing: 0.05em;
-}
-
-.metric-value {
-	font-size: 1.5rem;
-	font-weight: 700;
-	color: var(--color-primary);
-	margin-top: 0.25rem;
-}
-
-.mini-demos {
-	display: grid;
-	grid-template-columns: repeat(2, 1fr);
-	gap: 1rem;
-}
-
-@media (max-width: 600px) {
-	.mini-demos {
-		grid-template-columns: 1fr;
-	}
-}
-
-.mini-demo {
-	background: var(--color-bg);
-	padding: 1rem;
-	border-radius: 0.5rem;
-}
-
-.mini-title {
-	font-size: 0.8125rem;
-	font-weight: 600;
-	margin-bottom: 0.75rem;
-	color: var(--color-textMuted);
-}
-
 .counter-demo {
 	display: flex;
 	align-items: center;
@@ -504,158 +421,30 @@ func renderKitchenSinkStyles() string {
 	color: var(--color-primary);
 }
 
-.presence-demo {
-	display: flex;
-	flex-direction: column;
-	gap: 0.5rem;
-}
-
-.presence-users {
+.ks-actions {
 	display: flex;
 	flex-wrap: wrap;
-	gap: 0.375rem;
-}
-
-.presence-user {
-	display: flex;
-	align-items: center;
-	gap: 0.375rem;
-	padding: 0.25rem 0.5rem;
-	background: var(--color-bgAlt);
-	border-radius: 1rem;
-	font-size: 0.75rem;
-}
-
-.presence-dot {
-	width: 6px;
-	height: 6px;
-	background: var(--color-success);
-	border-radius: 50%;
-}
-
-.presence-controls {
-	display: flex;
 	gap: 0.5rem;
+	margin-bottom: 0.75rem;
 }
 
-.presence-btn {
-	padding: 0.25rem 0.5rem;
-	border: 1px solid var(--color-border);
-	border-radius: 0.25rem;
-	background: transparent;
-	cursor: pointer;
-	font-size: 0.75rem;
-}
-
-.presence-btn:hover {
+.ks-actions a[aria-current="page"] {
 	border-color: var(--color-primary);
+	color: var(--color-primary);
 }
 
-.bench-controls {
-	display: grid;
-	grid-template-columns: repeat(3, 1fr);
-	gap: 1rem;
-	margin-bottom: 1rem;
-}
-
-.control-group {
-	display: flex;
-	flex-direction: column;
-	gap: 0.5rem;
-}
-
-.control-label {
-	font-size: 0.75rem;
-	color: var(--color-textMuted);
-}
-
-.control-slider {
-	display: flex;
-	align-items: center;
-	gap: 0.5rem;
-}
-
-.slider {
-	flex: 1;
-	height: 6px;
-	background: var(--color-border);
-	border-radius: 3px;
-	cursor: pointer;
-}
-
-.slider-fill {
-	height: 100%;
-	background: var(--color-primary);
-	border-radius: 3px;
-}
-
-.slider-value {
-	width: 40px;
-	text-align: right;
-	font-weight: 600;
-}
-
-.bench-actions {
-	display: flex;
-	gap: 0.5rem;
-	margin-bottom: 1rem;
-}
-
-.bench-progress {
-	margin-bottom: 1rem;
-}
-
-.progress-bar {
-	height: 8px;
-	background: var(--color-border);
-	border-radius: 4px;
-	overflow: hidden;
-	margin-bottom: 0.5rem;
-}
-
-.progress-fill {
-	height: 100%;
-	background: var(--color-primary);
-	transition: width 0.3s;
-}
-
-.progress-text {
-	font-size: 0.75rem;
-	color: var(--color-textMuted);
-	text-align: center;
-}
-
-.bench-results {
-	background: var(--color-bg);
-	padding: 1rem;
-	border-radius: 0.5rem;
-}
-
-.results-title {
+.ks-result {
+	font-family: monospace;
 	font-size: 0.875rem;
-	font-weight: 600;
-	margin-bottom: 0.75rem;
-}
-
-.results-grid {
-	display: grid;
-	grid-template-columns: repeat(2, 1fr);
-	gap: 0.5rem;
-	font-size: 0.8125rem;
-}
-
-.result-item {
-	display: flex;
-	justify-content: space-between;
-}
-
-.result-label {
 	color: var(--color-textMuted);
+	margin-top: 0.5rem;
 }
 
-.result-value {
-	font-weight: 600;
+.bulk-rows {
+	max-height: 200px;
+	overflow-y: auto;
 	font-family: monospace;
+	font-size: 0.75rem;
 }
 
 .back-link {
@@ -671,9 +460,7 @@ func renderKitchenSinkStyles() string {
 .back-link:hover {
 	color: var(--color-primary);
 }
-</style>
-`
-}
+`)
 
 // renderKitchenSinkBody generates the main content
 func (k *KitchenSink) renderKitchenSinkBody() string {
@@ -690,16 +477,8 @@ func (k *KitchenSink) renderKitchenSinkBody() string {
 		},
 	})
 
-	// Memory stats
-	var m runtime.MemStats
-	runtime.ReadMemStats(&m)
-
-	visitors := showcaseVisitors.Load()
-	events := showcaseEvents.Load()
-	uptime := time.Since(k.ConnectedAt).Round(time.Second)
-
 	content := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="`+kitchenSinkStyles.Scope()+`">
 <div class="ks-container" data-live-view="kitchen-sink">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -709,108 +488,15 @@ func (k *KitchenSink) renderKitchenSinkBody() string {
 		<span style="font-size:1.5rem">🎯</span>
 		<h1>Kitchen Sink</h1>
 	</div>
-	<button class="ks-btn ks-btn-primary" lv-click="bench_start">▶️ Run Benchmark</button>
 </div>
 
 <div class="ks-grid">
-	<div class="ks-card">
-		<div class="card-title">📊 Live Metrics</div>
-		<div class="metrics-grid" data-slot="metrics">
-			<div class="metric-item">
-				<div class="metric-label">Diff Latency</div>
-				<div class="metric-value">%dms</div>
-			</div>
-			<div class="metric-item">
-				<div class="metric-label">Messages</div>
-				<div class="metric-value">%d</div>
-			</div>
-			<div class="metric-item">
-				<div class="metric-label">Cache Hits</div>
-				<div class="metric-value">%d%%</div>
-			</div>
-			<div class="metric-item">
-				<div class="metric-label">Memory</div>
-				<div class="metric-value">%s</div>
-			</div>
-		</div>
-	</div>
-
-	<div class="ks-card">
-		<div class="card-title">⚙️ Benchmark Controls</div>
-		<div class="bench-controls">
-			<div class="control-group">
-				<span class="control-label">Events/sec</span>
-				<div class="control-slider">
-					<div class="slider" lv-click="bench_events" lv-value-value="%d">
-						<div class="slider-fill" style="width:%d%%"></div>
-					</div>
-					<span class="slider-value">%d</span>
-				</div>
-			</div>
-			<div class="control-group">
-				<span class="control-label">Payload KB</span>
-				<div class="control-slider">
-					<div class="slider" lv-click="bench_payload" lv-value-value="%d">
-						<div class="slider-fill" style="width:%d%%"></div>
-					</div>
-					<span class="slider-value">%d</span>
-				</div>
-			</div>
-			<div class="control-group">
-				<span class="control-label">Concurrent</span>
-				<div class="control-slider">
-					<div class="slider" lv-click="bench_concurrent" lv-value-value="%d">
-						<div class="slider-fill" style="width:%d%%"></div>
-					</div>
-					<span class="slider-value">%d</span>
-				</div>
-			</div>
-		</div>
-		<div class="bench-actions">
-			<button class="ks-btn ks-btn-primary" lv-click="bench_start" %s>▶️ Start</button>
-			<button class="ks-btn ks-btn-danger" lv-click="bench_stop" %s>⏹️ Stop</button>
-			<button class="ks-btn" lv-click="bench_reset">🔄 Reset</button>
-		</div>
-		<div class="bench-progress" data-slot="progress">
-			<div class="progress-bar">
-				<div class="progress-fill" style="width:%d%%"></div>
-			</div>
-			<div class="progress-text">%s</div>
-		</div>
-		%s
-	</div>
-
-	<div class="ks-card" style="grid-column: span 2">
-		<div class="card-title">🧪 Mini Demos</div>
-		<div class="mini-demos">
-			%s
-			%s
-			%s
-			%s
-		</div>
-	</div>
-
-	<div class="ks-card" style="grid-column: span 2">
-		<div class="card-title">📡 Session Info</div>
-		<div class="metrics-grid">
-			<div class="metric-item">
-				<div class="metric-label">Visitors</div>
-				<div class="metric-value">%d</div>
-			</div>
-			<div class="metric-item">
-				<div class="metric-label">Total Events</div>
-				<div class="metric-value">%d</div>
-			</div>
-			<div class="metric-item">
-				<div class="metric-label">Your Events</div>
-				<div class="metric-value">%d</div>
-			</div>
-			<div class="metric-item">
-				<div class="metric-label">Session Time</div>
-				<div class="metric-value">%s</div>
-			</div>
-		</div>
-	</div>
+	%s
+	%s
+	%s
+	%s
+	%s
+	%s
 </div>
 
 </div>
@@ -818,164 +504,151 @@ func (k *KitchenSink) renderKitchenSinkBody() string {
 
 <script src="/_live/golivekit.js"></script>
 <script>
-setInterval(function() {
-	if (window.liveSocket && window.liveSocket.isConnected()) {
-		window.liveSocket.pushEvent("tick", {});
-	}
-}, 1000);
+window.liveView.registerHook('KitchenSinkPing', {
+	mounted() { this.pushEvent('hook_mounted', {}); }
+});
+window.liveView.on('%s', function(payload) {
+	var pong = document.querySelector('[data-testid="hook-pong"]');
+	if (pong) pong.textContent = payload.pings;
+});
 </script>
-`, k.MetricLatency.Milliseconds(), k.MetricEvents, k.MetricCacheHits, formatBytes(int64(m.Alloc)),
-		k.BenchEventsPerSec+10, k.BenchEventsPerSec, k.BenchEventsPerSec,
-		k.BenchPayloadKB+1, k.BenchPayloadKB*10, k.BenchPayloadKB,
-		k.BenchConcurrent+5, k.BenchConcurrent*2, k.BenchConcurrent,
-		k.disabledIf(k.BenchRunning), k.disabledIf(!k.BenchRunning),
-		k.BenchProgress, k.progressText(),
-		k.renderBenchResults(),
-		k.renderCounterDemo(), k.renderFormDemo(), k.renderListDemo(), k.renderPresenceDemo(),
-		visitors, events, k.EventsTotal.Load(), uptime.String())
+`, k.renderClicks(), k.renderForms(), k.renderUploads(), k.renderHooks(), k.renderStreams(), k.renderPatches(),
+		PongEvent)
 
 	return navbar + content
 }
 
-// disabledIf returns disabled attr if condition is true
-func (k *KitchenSink) disabledIf(cond bool) string {
-	if cond {
-		return "disabled"
-	}
-	return ""
+// renderClicks renders the click section
+func (k *KitchenSink) renderClicks() string {
+	return fmt.Sprintf(`
+<section class="ks-card" data-testid="clicks">
+	<div class="card-title">🖱️ Clicks</div>
+	<div class="counter-demo">
+		<button class="counter-btn" lv-click="dec" data-testid="click-dec">−</button>
+		<span class="counter-value" data-slot="click-count" data-testid="click-count">%d</span>
+		<button class="counter-btn" lv-click="inc" data-testid="click-inc">+</button>
+		<button class="ks-btn" lv-click="add" lv-value-by="5" data-testid="click-add">+5</button>
+	</div>
+</section>
+`, k.Count)
 }
 
-// progressText returns progress text
-func (k *KitchenSink) progressText() string {
-	if k.BenchRunning {
-		return fmt.Sprintf("Running... %d%%", k.BenchProgress)
+// renderForms renders the form section
+func (k *KitchenSink) renderForms() string {
+	statusClass, status := "invalid", "invalid"
+	if k.Valid {
+		statusClass, status = "valid", "valid"
 	}
-	if k.BenchResult != nil {
-		return "Complete!"
-	}
-	return "Ready to start"
-}
-
-// renderBenchResults renders benchmark results
-func (k *KitchenSink) renderBenchResults() string {
-	if k.BenchResult == nil {
-		return ""
+	saved := k.Saved
+	if saved == "" {
+		saved = "nothing saved"
 	}
 
-	r := k.BenchResult
 	return fmt.Sprintf(`
-<div class="bench-results" data-slot="results">
-	<div class="results-title">📈 Results</div>
-	<div class="results-grid">
-		<div class="result-item">
-			<span class="result-label">Total Events</span>
-			<span class="result-value">%d</span>
-		</div>
-		<div class="result-item">
-			<span class="result-label">Throughput</span>
-			<span class="result-value">%.1f/sec</span>
-		</div>
-		<div class="result-item">
-			<span class="result-label">Avg Latency</span>
-			<span class="result-value">%dms</span>
-		</div>
-		<div class="result-item">
-			<span class="result-label">P95 Latency</span>
-			<span class="result-value">%dms</span>
-		</div>
-		<div class="result-item">
-			<span class="result-label">P99 Latency</span>
-			<span class="result-value">%dms</span>
-		</div>
-		<div class="result-item">
-			<span class="result-label">Errors</span>
-			<span class="result-value">%d</span>
-		</div>
+<section class="ks-card" data-testid="forms">
+	<div class="card-title">📝 Forms</div>
+	<div class="form-demo">
+		<input type="text" placeholder="Type to echo..." lv-input="echo" lv-debounce="50" data-testid="form-echo">
+		<div class="ks-result">Echo: <span data-slot="echo-value" data-testid="echo-value">%s</span></div>
+		<form lv-submit="save" data-testid="form">
+			<input type="text" name="name" placeholder="Name (3+ characters)" lv-change="validate" data-testid="form-name">
+			<button type="submit" class="ks-btn ks-btn-primary" data-testid="form-submit">Save</button>
+		</form>
+		<div class="form-status %s" data-slot="form-status" data-testid="form-status">%s</div>
+		<div class="ks-result" data-slot="form-saved" data-testid="form-saved">%s</div>
 	</div>
-</div>
-`, r.TotalEvents, r.Throughput, r.AvgLatency.Milliseconds(), r.P95Latency.Milliseconds(), r.P99Latency.Milliseconds(), r.Errors)
+</section>
+`, html.EscapeString(k.Echo), statusClass, status, html.EscapeString(saved))
 }
 
-// renderCounterDemo renders the counter mini-demo
-func (k *KitchenSink) renderCounterDemo() string {
-	return fmt.Sprintf(`
-<div class="mini-demo">
-	<div class="mini-title">Counter</div>
-	<div class="counter-demo">
-		<button class="counter-btn" lv-click="counter_dec">−</button>
-		<span class="counter-value" data-slot="counter">%d</span>
-		<button class="counter-btn" lv-click="counter_inc">+</button>
-	</div>
-</div>
-`, k.CounterValue)
-}
+// renderUploads renders the upload section
+func (k *KitchenSink) renderUploads() string {
+	files := "no files"
+	if len(k.Uploaded) > 0 {
+		files = html.EscapeString(strings.Join(k.Uploaded, ", "))
+	}
 
-// renderFormDemo renders the form mini-demo
-func (k *KitchenSink) renderFormDemo() string {
-	statusClass := "invalid"
-	statusText := "Enter at least 3 characters"
-	if k.FormValidated {
-		statusClass = "valid"
-		statusText = "✓ Valid"
+	return fmt.Sprintf(`
+<section class="ks-card" data-testid="uploads">
+	<div class="card-title">📤 Uploads</div>
+	<div class="form-demo" data-testid="upload-input">%s</div>
+	<div class="ks-result">Received: <span data-slot="upload-count" data-testid="upload-count">%d</span></div>
+	<div class="ks-result" data-slot="upload-files" data-testid="upload-files">%s</div>
+</section>
+`, k.uploads.Input("files"), len(k.Uploaded), files)
+}
+
+// renderHooks renders the hook section
+func (k *KitchenSink) renderHooks() string {
+	state := "waiting"
+	if k.HookMounted {
+		state = "mounted"
 	}
 
 	return fmt.Sprintf(`
-<div class="mini-demo">
-	<div class="mini-title">Form Validation</div>
-	<div class="form-demo">
-		<input type="text" placeholder="Enter name..."
-			lv-change="form_update" lv-debounce="150" value="%s">
-		<div class="form-status %s">%s</div>
+<section class="ks-card" lv-hook="KitchenSinkPing" data-testid="hooks">
+	<div class="card-title">🪝 Hooks</div>
+	<div class="ks-actions">
+		<button class="ks-btn" lv-click="hook_ping" data-testid="hook-ping">Ping</button>
 	</div>
-</div>
-`, k.FormName, statusClass, statusText)
-}
+	<div class="ks-result">Hook: <span data-slot="hook-state" data-testid="hook-state">%s</span></div>
+	<div class="ks-result">Pings: <span data-slot="hook-pings" data-testid="hook-pings">%d</span></div>
+	<div class="ks-result">Pongs seen by the client: <span data-testid="hook-pong">0</span></div>
+</section>
+`, state, k.Pings)
+}
+
+// renderStreams renders the stream section: a keyed list updated through
+// list operations and a bulk slot streamed in chunks
+func (k *KitchenSink) renderStreams() string {
+	var items strings.Builder
+	for _, key := range k.Items {
+		items.WriteString(renderStreamItem(key))
+	}
 
-// renderListDemo renders the list mini-demo
-func (k *KitchenSink) renderListDemo() string {
-	var items string
-	for i, item := range k.ListItems {
-		items += fmt.Sprintf(`
-<div class="list-item">
-	<span>%s</span>
-	<button class="list-remove" lv-click="list_remove" lv-value-index="%d">✕</button>
-</div>
-`, item, i)
+	var bulk strings.Builder
+	if k.BulkLoaded {
+		for i := 1; i <= bulkRows; i++ {
+			fmt.Fprintf(&bulk, `<div class="bulk-row" data-testid="bulk-row">Row %04d of the streamed slot</div>`, i)
+		}
+	} else {
+		bulk.WriteString(`<p class="ks-result">Nothing loaded</p>`)
 	}
 
 	return fmt.Sprintf(`
-<div class="mini-demo">
-	<div class="mini-title">Dynamic List</div>
-	<div class="list-demo" data-slot="list">
-		%s
-		<button class="list-add" lv-click="list_add">+ Add Item</button>
+<section class="ks-card" data-testid="streams">
+	<div class="card-title">🌊 Streams</div>
+	<div class="ks-actions">
+		<button class="list-add" lv-click="stream_append" data-testid="stream-append">+ Append item</button>
+		<button class="list-add" lv-click="bulk_load" data-testid="bulk-load">Load %d rows</button>
+		<button class="list-add" lv-click="bulk_clear" data-testid="bulk-clear">Clear rows</button>
 	</div>
-</div>
-`, items)
-}
-
-// renderPresenceDemo renders the presence mini-demo
-func (k *KitchenSink) renderPresenceDemo() string {
-	var users string
-	for _, user := range k.PresenceUsers {
-		users += fmt.Sprintf(`
-<span class="presence-user">
-	<span class="presence-dot"></span>
-	%s
-</span>
-`, user)
+	<div class="ks-result">Items: <span data-slot="stream-count" data-testid="stream-count">%d</span></div>
+	<div class="list-demo" data-list="stream-items" data-testid="stream-items">%s</div>
+	<div class="bulk-rows" data-slot="bulk" data-testid="bulk">%s</div>
+</section>
+`, bulkRows, len(k.Items), items.String(), bulk.String())
+}
+
+// renderPatches renders the patch section
+func (k *KitchenSink) renderPatches() string {
+	var tabs strings.Builder
+	for _, tab := range kitchenSinkTabs {
+		current := ""
+		if tab == k.Tab {
+			current = ` aria-current="page"`
+		}
+		fmt.Fprintf(&tabs, `
+		<a class="ks-btn" href="%s?tab=%s" lv-click="tab" lv-value-tab="%s" data-testid="patch-%s"%s>%s</a>`,
+			KitchenSinkPath, tab, tab, tab, current, tab)
 	}
 
 	return fmt.Sprintf(`
-<div class="mini-demo">
-	<div class="mini-title">Presence</div>
-	<div class="presence-demo">
-		<div class="presence-users" data-slot="presence">%s</div>
-		<div class="presence-controls">
-			<button class="presence-btn" lv-click="presence_join">+ Join</button>
-			<button class="presence-btn" lv-click="presence_leave">− Leave</button>
-		</div>
-	</div>
-</div>
-`, users)
+<section class="ks-card" data-testid="patches">
+	<div class="card-title">🔗 Patches</div>
+	<nav class="ks-actions" data-slot="patch-tabs">%s
+	</nav>
+	<div class="ks-result">Tab: <span data-slot="patch-tab" data-testid="patch-tab">%s</span></div>
+</section>
+`, tabs.String(), k.Tab)
 }
@@ -4,6 +4,7 @@ package demos
 import (
 	"context"
 	"fmt"
+	"html/template"
 	"io"
 	"path/filepath"
 	"sort"
@@ -13,6 +14,7 @@ import (
 	"github.com/gabrielmiguelok/golivekit/internal/website"
 	"github.com/gabrielmiguelok/golivekit/internal/website/components"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/uploads"
 )
 
 // FileType represents the type of a file
@@ -39,16 +41,6 @@ type FileItem struct {
 	IsSelected bool
 }
 
-// UploadJob represents an upload in progress
-type UploadJob struct {
-	ID       string
-	Filename string
-	Size     int64
-	Progress int
-	Status   string // "uploading", "complete", "error"
-	Error    string
-}
-
 // FileManager is the file manager component.
 type FileManager struct {
 	core.BaseComponent
@@ -64,9 +56,8 @@ type FileManager struct {
 	SortAsc    bool
 	ShowHidden bool
 
-	// Upload state
-	Uploads     []*UploadJob
-	IsUploading bool
+	// Uploads receives the files chosen with the Upload button
+	uploads *uploads.Uploader
 
 	// Modal state
 	ShowNewFolder bool
@@ -101,6 +92,9 @@ func (f *FileManager) Mount(ctx context.Context, params core.Params, session cor
 	f.Selected = make(map[string]bool)
 	f.CurrentPath = []string{"root"}
 
+	f.uploads = uploads.NewUploader(discardStore{})
+	f.uploads.AllowUpload("files", uploads.AllowOptions{MaxFileSize: 2 << 20, MaxEntries: 5})
+
 	// Root folder
 	f.Files["root"] = &FileItem{
 		ID:       "root",
@@ -202,6 +196,7 @@ func (f *FileManager) Mount(ctx context.Context, params core.Params, session cor
 
 // Terminate handles cleanup.
 func (f *FileManager) Terminate(ctx context.Context, reason core.TerminateReason) error {
+	f.uploads.Close()
 	return nil
 }
 
@@ -215,6 +210,13 @@ func (f *FileManager) getCurrentFolder() *FileItem {
 
 // HandleEvent handles user interactions.
 func (f *FileManager) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if uploads.IsUploadEvent(event) {
+		if _, err := f.uploads.HandleEvent(event, payload); err != nil {
+			return err
+		}
+		return f.consumeUploads(ctx)
+	}
+
 	switch event {
 	// Navigation
 	case "navigate":
@@ -352,24 +354,16 @@ func (f *FileManager) HandleEvent(ctx context.Context, event string, payload map
 	case "paste":
 		f.pasteFiles()
 
-	// Upload simulation
-	case "start_upload":
-		filename, _ := payload["filename"].(string)
-		size, _ := payload["size"].(float64)
-		f.startUpload(filename, int64(size))
-
-	case "upload_progress":
-		id, _ := payload["id"].(string)
-		progress, _ := payload["progress"].(float64)
-		f.updateUploadProgress(id, int(progress))
-
+	// Uploads
 	case "cancel_upload":
 		id, _ := payload["id"].(string)
-		f.cancelUpload(id)
+		f.uploads.Cancel("files", id)
 
 	case "clear_uploads":
-		f.Uploads = nil
-		f.IsUploading = false
+		l, _ := f.uploads.Upload("files")
+		for _, entry := range l.Entries() {
+			f.uploads.Cancel("files", entry.UUID)
+		}
 
 	// Keyboard shortcuts (simulated)
 	case "keydown":
@@ -475,60 +469,30 @@ func (f *FileManager) pasteFiles() {
 	f.ClipboardOp = ""
 }
 
-// startUpload starts a simulated upload
-func (f *FileManager) startUpload(filename string, size int64) {
-	if size == 0 {
-		size = 1024 * 1024 // Default 1MB
+// consumeUploads adds the fully received files to the current folder
+func (f *FileManager) consumeUploads(ctx context.Context) error {
+	entries, err := f.uploads.Consume(ctx, "files")
+	for _, entry := range entries {
+		f.addUploadedFile(entry.FileName, entry.Size)
 	}
+	return err
+}
 
-	upload := &UploadJob{
-		ID:       fmt.Sprintf("upload_%d", time.Now().UnixNano()),
-		Filename: filename,
-		Size:     size,
-		Progress: 0,
-		Status:   "uploading",
-	}
+// discardStore keeps no bytes: the demo file system is simulated, so an
+// uploaded file only adds its name and size to it.
+type discardStore struct{}
 
-	f.Uploads = append(f.Uploads, upload)
-	f.IsUploading = true
+func (discardStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	_, err := io.Copy(io.Discard, r)
+	return "", err
 }
 
-// updateUploadProgress updates upload progress
-func (f *FileManager) updateUploadProgress(id string, progress int) {
-	for _, upload := range f.Uploads {
-		if upload.ID == id {
-			upload.Progress = progress
-			if progress >= 100 {
-				upload.Status = "complete"
-				// Add file to current folder
-				f.addUploadedFile(upload.Filename, upload.Size)
-			}
-			break
-		}
-	}
-
-	// Check if all uploads complete
-	allDone := true
-	for _, upload := range f.Uploads {
-		if upload.Status == "uploading" {
-			allDone = false
-			break
-		}
-	}
-	if allDone {
-		f.IsUploading = false
-	}
+func (discardStore) PresignURL(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*uploads.PresignedURL, error) {
+	return nil, uploads.ErrPresignNotSupported
 }
 
-// cancelUpload cancels an upload
-func (f *FileManager) cancelUpload(id string) {
-	var newUploads []*UploadJob
-	for _, upload := range f.Uploads {
-		if upload.ID != id {
-			newUploads = append(newUploads, upload)
-		}
-	}
-	f.Uploads = newUploads
+func (discardStore) Delete(ctx context.Context, key string) error {
+	return nil
 }
 
 // addUploadedFile adds a file from upload
@@ -648,6 +612,11 @@ func (f *FileManager) getSortedChildren(folder *FileItem) []*FileItem {
 	return items
 }
 
+// Styles returns the component's co-located CSS.
+func (f *FileManager) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{fileManagerStyles}
+}
+
 // Render returns the HTML representation.
 func (f *FileManager) Render(ctx context.Context) core.Renderer {
 	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
@@ -670,13 +639,12 @@ func (f *FileManager) renderFileManager() string {
 	}
 
 	body := f.renderFileManagerBody()
-	return website.RenderDocument(cfg, renderFileManagerStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderFileManagerStyles returns custom CSS
-func renderFileManagerStyles() string {
-	return `
-<style>
+// fileManagerStyles is the file manager demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var fileManagerStyles = core.NewStylesheet("file-manager", `
 .fm-container {
 	max-width: 1100px;
 	margin: 0 auto;
@@ -968,8 +936,8 @@ func renderFileManagerStyles() string {
 	transition: width 0.3s;
 }
 
-.upload-progress-fill.complete {
-	background: var(--color-success);
+.fm-upload input {
+	display: none;
 }
 
 .upload-status {
@@ -1101,9 +1069,7 @@ func renderFileManagerStyles() string {
 .drop-zone-text {
 	color: var(--color-textMuted);
 }
-</style>
-`
-}
+`)
 
 // renderFileManagerBody generates the main content
 func (f *FileManager) renderFileManagerBody() string {
@@ -1125,7 +1091,7 @@ func (f *FileManager) renderFileManagerBody() string {
 	items := f.getSortedChildren(currentFolder)
 
 	content := fmt.Sprintf(`
-<main id="main-content">
+<main id="main-content" class="` + fileManagerStyles.Scope() + `">
 <div class="fm-container" data-live-view="file-manager" lv-keydown="keydown" tabindex="0">
 
 <a href="/demos" class="back-link">← Back to Demos</a>
@@ -1136,9 +1102,9 @@ func (f *FileManager) renderFileManagerBody() string {
 		<h1>File Manager</h1>
 	</div>
 	<div class="fm-actions">
-		<button class="fm-btn fm-btn-primary" lv-click="start_upload" lv-value-filename="newfile.txt" lv-value-size="1048576">
-			📤 Upload
-		</button>
+		<label class="fm-btn fm-btn-primary fm-upload">
+			📤 Upload %s
+		</label>
 		<button class="fm-btn" lv-click="new_folder">📁 New Folder</button>
 	</div>
 </div>
@@ -1170,27 +1136,7 @@ func (f *FileManager) renderFileManagerBody() string {
 </main>
 
 <script src="/_live/golivekit.js"></script>
-<script>
-// Simulate upload progress
-var uploadIntervals = {};
-document.addEventListener('click', function(e) {
-	if (e.target.textContent.includes('Upload')) {
-		var id = 'upload_' + Date.now();
-		var progress = 0;
-		uploadIntervals[id] = setInterval(function() {
-			progress += Math.random() * 15;
-			if (progress >= 100) {
-				progress = 100;
-				clearInterval(uploadIntervals[id]);
-			}
-			if (window.liveSocket && window.liveSocket.isConnected()) {
-				window.liveSocket.pushEvent("upload_progress", {id: id, progress: progress});
-			}
-		}, 200);
-	}
-});
-</script>
-`, f.renderBreadcrumb(), f.renderUploadPanel(), f.renderSelectedInfo(selectedCount),
+`, f.uploads.Input("files"), f.renderBreadcrumb(), f.renderUploadPanel(), f.renderSelectedInfo(selectedCount),
 		f.renderSortControls(), f.viewClass("grid"), f.viewClass("list"),
 		f.renderFiles(items), f.renderModals())
 
@@ -1249,22 +1195,19 @@ func (f *FileManager) renderBreadcrumb() string {
 	return `<div class="fm-breadcrumb">` + html + `</div>`
 }
 
-// renderUploadPanel renders the upload progress panel
+// renderUploadPanel renders the uploads still arriving or rejected
 func (f *FileManager) renderUploadPanel() string {
-	if len(f.Uploads) == 0 {
+	l, _ := f.uploads.Upload("files")
+	entries := l.Entries()
+	if len(entries) == 0 {
 		return ""
 	}
 
 	var items string
-	for _, upload := range f.Uploads {
-		statusClass := ""
-		if upload.Status == "complete" {
-			statusClass = "complete"
-		}
-
-		icon := "📄"
-		if upload.Status == "complete" {
-			icon = "✅"
+	for _, entry := range entries {
+		icon, status := "📄", fmt.Sprintf("%d%%", entry.Progress)
+		if len(entry.Errors) > 0 {
+			icon, status = "⚠️", entry.Errors[0]
 		}
 
 		items += fmt.Sprintf(`
@@ -1273,23 +1216,29 @@ func (f *FileManager) renderUploadPanel() string {
 	<div class="upload-info">
 		<div class="upload-filename">%s</div>
 		<div class="upload-progress">
-			<div class="upload-progress-fill %s" style="width:%d%%"></div>
+			<div class="upload-progress-fill" style="width:%d%%"></div>
 		</div>
 	</div>
-	<span class="upload-status">%d%%</span>
+	<span class="upload-status">%s</span>
+	<button class="fm-btn" lv-click="cancel_upload" lv-value-id="%s">✕</button>
 </div>
-`, icon, upload.Filename, statusClass, upload.Progress, upload.Progress)
+`, icon, template.HTMLEscapeString(entry.FileName), entry.Progress, status, entry.UUID)
+	}
+
+	title := fmt.Sprintf("Uploading %d files...", len(entries))
+	if !l.Pending() {
+		title = "Some files were rejected (max 5 files of 2 MB)"
 	}
 
 	return fmt.Sprintf(`
 <div class="upload-panel" data-slot="uploads">
 	<div class="upload-header">
-		<span class="upload-title">Uploading %d files...</span>
+		<span class="upload-title">%s</span>
 		<button class="fm-btn" lv-click="clear_uploads">✕</button>
 	</div>
 	%s
 </div>
-`, len(f.Uploads), items)
+`, title, items)
 }
 
 // renderFiles renders the file list or grid
@@ -1299,7 +1248,7 @@ func (f *FileManager) renderFiles(items []*FileItem) string {
 <div class="empty-folder">
 	<div class="empty-folder-icon">📂</div>
 	<p>This folder is empty</p>
-	<p style="font-size:0.875rem">Drop files here or click Upload</p>
+	<p style="font-size:0.875rem">Click Upload to add files</p>
 </div>
 `
 	}
@@ -1330,7 +1279,7 @@ func (f *FileManager) renderFilesGrid(items []*FileItem) string {
 	<span class="file-icon">%s</span>
 	<span class="file-name">%s</span>
 </div>
-`, selectedClass, event, item.ID, icon, item.Name)
+`, selectedClass, event, item.ID, icon, template.HTMLEscapeString(item.Name))
 	}
 
 	return `<div class="fm-grid">` + html + `</div>`
@@ -1365,7 +1314,7 @@ func (f *FileManager) renderFilesList(items []*FileItem) string {
 	<span class="file-size">%s</span>
 	<span class="file-date">%s</span>
 </div>
-`, selectedClass, event, item.ID, icon, item.Name, size, date)
+`, selectedClass, event, item.ID, icon, template.HTMLEscapeString(item.Name), size, date)
 	}
 
 	return `<div class="fm-list">` + html + `</div>`
@@ -65,6 +65,11 @@ func (h *DemosHub) HandleEvent(ctx context.Context, event string, payload map[st
 	return nil
 }
 
+// Styles returns the component's co-located CSS.
+func (h *DemosHub) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{hubStyles}
+}
+
 // Render returns the HTML representation.
 func (h *DemosHub) Render(ctx context.Context) core.Renderer {
 	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
@@ -139,9 +144,9 @@ func getDemoCards() []DemoCard {
 			Icon:        "🎯",
 			Title:       "Kitchen Sink",
 			Subtitle:    "All Features",
-			Description: "Comprehensive showcase of all GoliveKit features with benchmarking tools.",
+			Description: "Every client binding in one page: clicks, forms, uploads, hooks, streams and patches.",
 			Path:        "/demos/showcase",
-			Features:    []string{"Everything", "Benchmarks", "Metrics"},
+			Features:    []string{"Everything", "Conformance", "Streams"},
 			Complexity:  5,
 		},
 	}
@@ -160,13 +165,12 @@ func renderDemosHub(visitors, events int64, uptime string) string {
 	}
 
 	body := renderHubBody(visitors, events, uptime)
-	return website.RenderDocument(cfg, renderHubStyles(), body)
+	return website.RenderDocument(cfg, "", body)
 }
 
-// renderHubStyles returns custom CSS for the hub.
-func renderHubStyles() string {
-	return `
-<style>
+// hubStyles is the hub demo's scoped CSS, injected into the document head
+// by the router via Styles.
+var hubStyles = core.NewStylesheet("hub", `
 .demos-grid {
 	display: grid;
 	grid-template-columns: repeat(auto-fit, minmax(320px, 1fr));
@@ -339,9 +343,7 @@ func renderHubStyles() string {
 .back-link:hover {
 	color: var(--color-primary);
 }
-</style>
-`
-}
+`)
 
 // renderHubBody generates the main content.
 func renderHubBody(visitors, events int64, uptime string) string {
@@ -353,7 +355,7 @@ func renderHubBody(visitors, events int64, uptime string) string {
 		ShowBadge: true,
 		BadgeText: "Demos",
 		Links: []website.NavLink{
-			{Label: "Home", URL: "/", External: false},
+			{Label: "Home", URL: "/", External: false, Live: true},
 			{Label: "Docs", URL: "/docs", External: false},
 		},
 	})
@@ -361,7 +363,7 @@ func renderHubBody(visitors, events int64, uptime string) string {
 	// Hero section
 	hero := `
 <section class="hub-hero">
-	<a href="/" class="back-link">← Back to Home</a>
+	<a href="/" class="back-link" lv-navigate>← Back to Home</a>
 	<h1>🎪 GoliveKit Demos</h1>
 	<p>Explore interactive showcases demonstrating real-time web applications built entirely in Go. Each demo is production-ready and uses actual GoliveKit packages.</p>
 </section>
@@ -415,7 +417,7 @@ func renderHubBody(visitors, events int64, uptime string) string {
 	// GoliveKit script
 	script := `<script src="/_live/golivekit.js"></script>`
 
-	return navbar + `<main id="main-content">` + hero + statsBar + cardsHTML + footer + `</main>` + script
+	return navbar + `<main id="main-content" class="` + hubStyles.Scope() + `">` + hero + statsBar + cardsHTML + footer + `</main>` + script
 }
 
 // renderDemoCard generates HTML for a single demo card.
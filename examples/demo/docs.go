@@ -11,6 +11,7 @@ import (
 	"github.com/gabrielmiguelok/golivekit/internal/website"
 	"github.com/gabrielmiguelok/golivekit/internal/website/components"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/search"
 )
 
 // contentCache holds pre-rendered content for each section (initialized once)
@@ -30,10 +31,26 @@ var contentCache = sync.OnceValue(func() map[string]string {
 	}
 })
 
+// docsIndex is the full-text index over all sections (built once)
+var docsIndex = sync.OnceValue(func() *search.MemoryIndex {
+	idx := search.NewMemoryIndex()
+	cache := contentCache()
+	for _, section := range docsSections {
+		idx.Index(search.Document{
+			ID:    section.ID,
+			Title: section.Title,
+			Body:  search.TextFromHTML(cache[section.ID]),
+			URL:   "/docs?section=" + section.ID,
+		})
+	}
+	return idx
+})
+
 // DocsComponent is the documentation page component.
 type DocsComponent struct {
 	core.BaseComponent
 	CurrentSection string
+	search         *search.SearchBox
 }
 
 // NewDocs creates a new documentation component.
@@ -52,6 +69,9 @@ func (d *DocsComponent) Mount(ctx context.Context, params core.Params, session c
 	if section, ok := params["section"]; ok && section != "" {
 		d.CurrentSection = section
 	}
+	d.search = search.NewSearchBox("docs", docsIndex())
+	d.search.Placeholder = "Search docs…"
+	d.search.SelectEvent = search.EventSelect
 	return nil
 }
 
@@ -62,6 +82,14 @@ func (d *DocsComponent) Terminate(ctx context.Context, reason core.TerminateReas
 
 // HandleEvent handles user interactions.
 func (d *DocsComponent) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if handled, err := d.search.HandleEvent(ctx, event, payload); handled {
+		return err
+	}
+	if doc, ok := d.search.Selected(event, payload); ok {
+		d.CurrentSection = doc.ID
+		d.search.Clear()
+		return nil
+	}
 	if event == "nav" {
 		if section, ok := payload["section"].(string); ok {
 			d.CurrentSection = section
@@ -109,6 +137,8 @@ func (d *DocsComponent) renderDocsPage() string {
 		Author:      "Gabriel Miguel",
 		Language:    "en",
 		ThemeColor:  "#8B5CF6",
+		// Cards, stats and animations are not used above the fold
+		StylesheetURL: "/_website/styles.css",
 	}
 
 	var body strings.Builder
@@ -163,13 +193,29 @@ func (d *DocsComponent) getSectionTitle() string {
 
 func (d *DocsComponent) renderSidebar() string {
 	var sb strings.Builder
-	sb.WriteString(`<aside class="docs-sidebar" data-slot="sidebar"><nav class="docs-nav">`)
+	sb.WriteString(`<aside class="docs-sidebar">`)
+
+	// Search input stays outside the slots so typing is never interrupted
+	sb.WriteString(`<div class="docs-search lv-search" role="search">`)
+	sb.WriteString(d.search.InputHTML())
+	sb.WriteString(d.search.ResultsHTML())
+	sb.WriteString(`</div>`)
+
+	sb.WriteString(docsNav.Render(d.CurrentSection))
+	sb.WriteString(`</aside>`)
+	return sb.String()
+}
+
+// docsNav renders the section list once per active section.
+var docsNav = core.Memo(func(current string) string {
+	var sb strings.Builder
+	sb.WriteString(`<nav class="docs-nav" data-slot="sidebar">`)
 	sb.WriteString(`<h2 class="docs-nav-title">Documentation</h2>`)
 	sb.WriteString(`<ul class="docs-nav-list">`)
 
 	for _, section := range docsSections {
 		activeClass := ""
-		if section.ID == d.CurrentSection {
+		if section.ID == current {
 			activeClass = " docs-nav-item-active"
 		}
 		// Use lv-click for WebSocket-powered navigation (no page reload)
@@ -177,9 +223,9 @@ func (d *DocsComponent) renderSidebar() string {
 			section.ID, activeClass, section.Icon, section.Title))
 	}
 
-	sb.WriteString(`</ul></nav></aside>`)
+	sb.WriteString(`</ul></nav>`)
 	return sb.String()
-}
+})
 
 func (d *DocsComponent) renderContent() string {
 	// Use cached content (O(1) lookup instead of function call)
@@ -581,7 +627,7 @@ r.Use(router.CORS(router.CORSConfig{
 }))
 
 <span class="token-comment">// Rate limiting</span>
-r.Use(router.RateLimit(<span class="token-number">100</span>)) <span class="token-comment">// 100 requests per minute</span>
+r.Use(router.RateLimit(<span class="token-number">100</span>)) <span class="token-comment">// 100 requests per second per IP</span>
 
 <span class="token-comment">// Secure headers (CSP, X-Frame-Options, etc.)</span>
 r.Use(router.SecureHeaders())`) + `
@@ -887,9 +933,17 @@ pubsub.Broadcast(<span class="token-string">"chat:room:123"</span>, ChatMessage{
 <h2>Presence Tracking</h2>
 ` + codeBlock("presence.go", `<span class="token-keyword">import</span> <span class="token-string">"github.com/gabrielmiguelok/golivekit/pkg/presence"</span>
 
-presence.Track(<span class="token-string">"room:123"</span>, user.ID, <span class="token-type">map[string]any</span>{<span class="token-string">"name"</span>: user.Name})
-users := presence.List(<span class="token-string">"room:123"</span>)
-presence.Untrack(<span class="token-string">"room:123"</span>, user.ID)`) + `
+pm := presence.NewPresenceManager(ps) <span class="token-comment">// shared pubsub: every node sees every user</span>
+
+<span class="token-comment">// Mount: untracked automatically when the socket closes</span>
+pm.Track(<span class="token-string">"room:123"</span>, c.Socket(), presence.PresenceInfo{UserID: user.ID, Username: user.Name})
+pm.GetOrCreate(<span class="token-string">"room:123"</span>).Watch(c.Socket())
+users := pm.List(<span class="token-string">"room:123"</span>)
+
+<span class="token-comment">// HandleInfo: joins and leaves arrive as presence.PresenceDiff</span>
+<span class="token-keyword">if</span> diff, ok := msg.(presence.PresenceDiff); ok {
+    c.users = pm.List(diff.Topic)
+}`) + `
 </section>
 </article>`
 }
@@ -1378,11 +1432,12 @@ func contentPackages() string {
 }
 
 func renderDocsCSS() string {
-	return `
+	return website.CodeStyles() + search.CSS + `
+.docs-search{margin-bottom:1.25rem}
 .docs-layout{display:grid;grid-template-columns:1fr;gap:2rem;padding:2rem 0}
 @media(min-width:768px){.docs-layout{grid-template-columns:260px 1fr}}
 .docs-sidebar{position:sticky;top:5rem;height:fit-content;max-height:calc(100vh - 6rem);overflow-y:auto}
-.docs-nav-title{font-size:0.8rem;font-weight:700;text-transform:uppercase;letter-spacing:0.05em;color:var(--color-textMuted);margin-bottom:1rem;padding-left:0.75rem}
+.docs-nav-title{font-size:0.8rem;font-weight:700;text-transform:uppercase;letter-spacing:0.05em;color:var(--color-textMuted);margin-bottom:1rem;padding-inline-start:0.75rem}
 .docs-nav-list{display:flex;flex-direction:column;gap:0.25rem;list-style:none;padding:0;margin:0}
 .docs-nav-item{display:flex;align-items:center;gap:0.5rem;width:100%;padding:0.6rem 0.75rem;border-radius:0.5rem;font-size:0.875rem;color:var(--color-textMuted);background:transparent;text-decoration:none;transition:all 0.15s ease;border:none;cursor:pointer;font-family:inherit;text-align:left}
 .docs-nav-item:hover{color:var(--color-text);background:var(--color-bgAlt)}
@@ -1394,7 +1449,7 @@ func renderDocsCSS() string {
 .docs-lead{font-size:1.1rem;color:var(--color-textMuted);margin-bottom:1.5rem}
 .docs-section{margin-bottom:1.5rem}
 .docs-section p{margin-bottom:0.75rem;line-height:1.6}
-.docs-list{padding-left:1.25rem;margin-bottom:0.75rem;list-style:disc}
+.docs-list{padding-inline-start:1.25rem;margin-bottom:0.75rem;list-style:disc}
 .docs-list li{margin-bottom:0.4rem;color:var(--color-textMuted)}
 .docs-list-numbered{list-style:decimal}
 .docs-link{color:var(--color-primary);text-decoration:underline;text-underline-offset:2px}
@@ -42,6 +42,9 @@ func main() {
 	// Serve GoliveKit client JS
 	r.Handle("/_live/", http.StripPrefix("/_live/", client.Handler()))
 
+	// Non-critical website CSS, loaded asynchronously by the landing page
+	r.Handle("/_website/styles.css", website.StylesheetHandler())
+
 	// Register LiveView routes
 	r.Live("/", NewDemo)
 	r.Live("/docs", NewDocs)
@@ -54,7 +57,7 @@ func main() {
 	r.Live("/demos/dashboard", demos.NewLiveDashboard)
 	r.Live("/demos/game", demos.NewSnakeGame)
 	r.Live("/demos/editor", demos.NewCollabEditor)
-	r.Live("/demos/showcase", demos.NewKitchenSink)
+	r.Live(demos.KitchenSinkPath, demos.NewKitchenSink)
 
 	// Health check for cloud platforms
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -210,6 +213,8 @@ func (d *Demo) Render(ctx context.Context) core.Renderer {
 			Author:      "Gabriel Miguel",
 			Language:    "en",
 			ThemeColor:  "#8B5CF6",
+			// Inline only above-the-fold CSS to speed up LCP
+			StylesheetURL: "/_website/styles.css",
 		}
 
 		// Landing page options
@@ -246,7 +251,7 @@ func renderLandingWithLiveData(cfg website.PageConfig, opts landing.Options, cou
 		BadgeText: "100% Go",
 		Links: []website.NavLink{
 			{Label: "Docs", URL: "/docs", External: false},
-			{Label: "Demos", URL: "/demos", External: false},
+			{Label: "Demos", URL: "/demos", External: false, Live: true},
 		},
 	}))
 
@@ -472,10 +477,9 @@ func renderDemosSection() string {
 </div>
 
 <div class="text-center" style="margin-top:2rem">
-<a href="/demos" class="btn btn-secondary">View All Demos →</a>
+<a href="/demos" class="btn btn-secondary" lv-navigate>View All Demos →</a>
 </div>
 </div>
 </section>
 `
 }
-
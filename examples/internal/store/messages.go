@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Message is a chat message.
+type Message struct {
+	ID        string
+	Room      string
+	Username  string
+	Content   string
+	Timestamp time.Time
+}
+
+// MessageRepo stores chat messages.
+type MessageRepo interface {
+	Add(ctx context.Context, msg Message) error
+	// Recent returns the last limit messages of room, oldest first.
+	Recent(ctx context.Context, room string, limit int) ([]Message, error)
+}
+
+// MemoryMessages keeps the last messages of each room in memory.
+type MemoryMessages struct {
+	rooms map[string][]Message
+	max   int
+	mu    sync.RWMutex
+}
+
+// NewMemoryMessages creates an in-memory repository keeping the last max
+// messages of each room.
+func NewMemoryMessages(max int) *MemoryMessages {
+	return &MemoryMessages{rooms: make(map[string][]Message), max: max}
+}
+
+// Add stores a message, dropping the oldest of its room beyond the maximum.
+func (r *MemoryMessages) Add(ctx context.Context, msg Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	messages := append(r.rooms[msg.Room], msg)
+	if len(messages) > r.max {
+		messages = messages[len(messages)-r.max:]
+	}
+	r.rooms[msg.Room] = messages
+	return nil
+}
+
+// Recent returns the last limit messages of room, oldest first.
+func (r *MemoryMessages) Recent(ctx context.Context, room string, limit int) ([]Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	messages := r.rooms[room]
+	start := max(len(messages)-limit, 0)
+	result := make([]Message, len(messages)-start)
+	copy(result, messages[start:])
+	return result, nil
+}
+
+// SQLMessages stores messages in the messages table.
+type SQLMessages struct {
+	db *sql.DB
+}
+
+// NewSQLMessages creates a repository over a database opened with Open.
+func NewSQLMessages(db *sql.DB) *SQLMessages {
+	return &SQLMessages{db: db}
+}
+
+// Add stores a message.
+func (r *SQLMessages) Add(ctx context.Context, msg Message) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO messages (id, room, username, content, sent_at) VALUES (?, ?, ?, ?, ?)",
+		msg.ID, msg.Room, msg.Username, msg.Content, msg.Timestamp.UnixNano())
+	return err
+}
+
+// Recent returns the last limit messages of room, oldest first.
+func (r *SQLMessages) Recent(ctx context.Context, room string, limit int) ([]Message, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, room, username, content, sent_at FROM (
+		SELECT * FROM messages WHERE room = ? ORDER BY sent_at DESC, id DESC LIMIT ?
+	) ORDER BY sent_at, id`, room, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		var sent int64
+		if err := rows.Scan(&m.ID, &m.Room, &m.Username, &m.Content, &sent); err != nil {
+			return nil, err
+		}
+		m.Timestamp = time.Unix(0, sent)
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,7 @@
+CREATE TABLE messages (
+    id       TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    content  TEXT NOT NULL,
+    sent_at  INTEGER NOT NULL
+);
+CREATE INDEX messages_sent_at ON messages (sent_at);
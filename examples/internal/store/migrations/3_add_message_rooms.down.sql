@@ -0,0 +1,3 @@
+DROP INDEX messages_room_sent_at;
+CREATE INDEX messages_sent_at ON messages (sent_at);
+ALTER TABLE messages DROP COLUMN room;
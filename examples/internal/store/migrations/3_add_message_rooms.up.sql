@@ -0,0 +1,3 @@
+ALTER TABLE messages ADD COLUMN room TEXT NOT NULL DEFAULT 'lobby';
+DROP INDEX messages_sent_at;
+CREATE INDEX messages_room_sent_at ON messages (room, sent_at);
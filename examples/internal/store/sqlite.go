@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package store
+
+// The pure-Go SQLite driver, registered as "sqlite".
+import _ "modernc.org/sqlite"
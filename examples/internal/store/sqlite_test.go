@@ -0,0 +1,47 @@
+//go:build sqlite
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func openTest(t *testing.T) *SQLTodos {
+	t.Helper()
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewSQLTodos(db)
+}
+
+func TestSQLTodos(t *testing.T) {
+	testTodoRepo(t, openTest(t))
+}
+
+func TestSQLMessages(t *testing.T) {
+	testMessageRepo(t, NewSQLMessages(openTest(t).db))
+}
+
+func TestOpen_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewSQLMessages(db).Add(ctx, Message{ID: "1", Content: "kept"})
+	db.Close()
+
+	db, err = Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if recent, _ := NewSQLMessages(db).Recent(ctx, "", 10); len(recent) != 1 || recent[0].Content != "kept" {
+		t.Errorf("Expected the message after reopening, got %+v", recent)
+	}
+}
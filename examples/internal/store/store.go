@@ -0,0 +1,56 @@
+// Package store is the persistence layer of the bundled examples. It shows
+// the recommended repository pattern: components depend on small
+// interfaces (TodoRepo, MessageRepo) with an in-memory implementation for
+// tests and quick starts, and a database/sql one that survives restarts.
+//
+// The SQL repositories target SQLite. The driver is a dependency of the
+// examples module only, and is compiled in with the sqlite build tag:
+//
+//	cd examples && go run -tags sqlite ./chat -db chat.db
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+// driverName is the database/sql driver of the SQL repositories.
+const driverName = "sqlite"
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open opens the SQLite database at path and applies the pending
+// migrations.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if !slices.Contains(sql.Drivers(), driverName) {
+		return nil, fmt.Errorf("store: SQLite driver not compiled in (build with -tags sqlite)")
+	}
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	// SQLite allows one writer at a time
+	db.SetMaxOpenConns(1)
+
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	m, err := migrate.New(db, migrate.SQLite, fsys)
+	if err == nil {
+		_, err = m.Up(ctx)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testTodoRepo checks the TodoRepo contract; every implementation runs it.
+func testTodoRepo(t *testing.T, repo TodoRepo) {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	for i, title := range []string{"First", "Second", "Third"} {
+		at := base.Add(time.Duration(i) * time.Hour)
+		if err := repo.Save(ctx, Todo{ID: title, Title: title, Completed: i == 1, CreatedAt: at, UpdatedAt: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, _ := repo.Get(ctx, "First")
+	first.Title, first.Completed = "First, renamed", true
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := repo.Get(ctx, "First"); got.Title != "First, renamed" || !got.Completed || !got.CreatedAt.Equal(base) {
+		t.Errorf("Expected the update saved, got %+v", got)
+	}
+	if _, err := repo.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := repo.DeleteCompleted(ctx); err != nil {
+		t.Fatal(err)
+	}
+	todos, _ := repo.List(ctx)
+	if len(todos) != 1 || todos[0].ID != "Third" {
+		t.Errorf("Expected only Third left, got %+v", todos)
+	}
+	repo.Delete(ctx, "Third")
+	if todos, _ := repo.List(ctx); len(todos) != 0 {
+		t.Errorf("Expected no todos, got %+v", todos)
+	}
+}
+
+// testMessageRepo checks the MessageRepo contract.
+func testMessageRepo(t *testing.T, repo MessageRepo) {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	for i, content := range []string{"one", "two", "three"} {
+		repo.Add(ctx, Message{ID: content, Room: "lobby", Username: "ada", Content: content, Timestamp: base.Add(time.Duration(i) * time.Minute)})
+	}
+	repo.Add(ctx, Message{ID: "other", Room: "go", Username: "ada", Content: "other", Timestamp: base.Add(time.Hour)})
+	recent, err := repo.Recent(ctx, "lobby", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].Content != "two" || recent[1].Content != "three" || !recent[1].Timestamp.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("Expected the last two messages, oldest first, got %+v", recent)
+	}
+	if other, _ := repo.Recent(ctx, "go", 10); len(other) != 1 || other[0].Room != "go" {
+		t.Errorf("Expected only the message of the other room, got %+v", other)
+	}
+}
+
+func TestMemoryTodos(t *testing.T) {
+	testTodoRepo(t, NewMemoryTodos())
+}
+
+func TestMemoryMessages(t *testing.T) {
+	testMessageRepo(t, NewMemoryMessages(100))
+
+	repo := NewMemoryMessages(2)
+	for _, id := range []string{"a", "b", "c"} {
+		repo.Add(context.Background(), Message{ID: id})
+	}
+	if all, _ := repo.Recent(context.Background(), "", 10); len(all) != 2 || all[0].ID != "b" {
+		t.Errorf("Expected the oldest message dropped, got %+v", all)
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for unknown ids.
+var ErrNotFound = errors.New("store: not found")
+
+// Todo is a todo item.
+type Todo struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Completed   bool      `json:"completed"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TodoRepo stores todos.
+type TodoRepo interface {
+	// List returns the todos, oldest first.
+	List(ctx context.Context) ([]Todo, error)
+	Get(ctx context.Context, id string) (Todo, error)
+	// Save inserts or updates a todo.
+	Save(ctx context.Context, todo Todo) error
+	Delete(ctx context.Context, id string) error
+	// DeleteCompleted removes the completed todos.
+	DeleteCompleted(ctx context.Context) error
+}
+
+// MemoryTodos keeps todos in memory.
+type MemoryTodos struct {
+	todos map[string]Todo
+	mu    sync.RWMutex
+}
+
+// NewMemoryTodos creates an empty in-memory repository.
+func NewMemoryTodos() *MemoryTodos {
+	return &MemoryTodos{todos: make(map[string]Todo)}
+}
+
+// List returns the todos, oldest first.
+func (r *MemoryTodos) List(ctx context.Context) ([]Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	todos := make([]Todo, 0, len(r.todos))
+	for _, t := range r.todos {
+		todos = append(todos, t)
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		if !todos[i].CreatedAt.Equal(todos[j].CreatedAt) {
+			return todos[i].CreatedAt.Before(todos[j].CreatedAt)
+		}
+		return todos[i].ID < todos[j].ID
+	})
+	return todos, nil
+}
+
+// Get returns a todo.
+func (r *MemoryTodos) Get(ctx context.Context, id string) (Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.todos[id]
+	if !ok {
+		return Todo{}, ErrNotFound
+	}
+	return t, nil
+}
+
+// Save inserts or updates a todo.
+func (r *MemoryTodos) Save(ctx context.Context, todo Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.todos[todo.ID] = todo
+	return nil
+}
+
+// Delete removes a todo.
+func (r *MemoryTodos) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.todos, id)
+	return nil
+}
+
+// DeleteCompleted removes the completed todos.
+func (r *MemoryTodos) DeleteCompleted(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, t := range r.todos {
+		if t.Completed {
+			delete(r.todos, id)
+		}
+	}
+	return nil
+}
+
+// SQLTodos stores todos in the todos table.
+type SQLTodos struct {
+	db *sql.DB
+}
+
+// NewSQLTodos creates a repository over a database opened with Open.
+func NewSQLTodos(db *sql.DB) *SQLTodos {
+	return &SQLTodos{db: db}
+}
+
+const todoColumns = "id, title, description, completed, created_at, updated_at"
+
+// List returns the todos, oldest first.
+func (r *SQLTodos) List(ctx context.Context) ([]Todo, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+todoColumns+" FROM todos ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []Todo
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+// Get returns a todo.
+func (r *SQLTodos) Get(ctx context.Context, id string) (Todo, error) {
+	t, err := scanTodo(r.db.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Todo{}, ErrNotFound
+	}
+	return t, err
+}
+
+// Save inserts or updates a todo.
+func (r *SQLTodos) Save(ctx context.Context, t Todo) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO todos (`+todoColumns+`) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET title = excluded.title, description = excluded.description,
+			completed = excluded.completed, updated_at = excluded.updated_at`,
+		t.ID, t.Title, t.Description, t.Completed, t.CreatedAt.UnixNano(), t.UpdatedAt.UnixNano())
+	return err
+}
+
+// Delete removes a todo.
+func (r *SQLTodos) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM todos WHERE id = ?", id)
+	return err
+}
+
+// DeleteCompleted removes the completed todos.
+func (r *SQLTodos) DeleteCompleted(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM todos WHERE completed = 1")
+	return err
+}
+
+func scanTodo(row interface{ Scan(...any) error }) (Todo, error) {
+	var t Todo
+	var created, updated int64
+	err := row.Scan(&t.ID, &t.Title, &t.Description, &t.Completed, &created, &updated)
+	t.CreatedAt, t.UpdatedAt = time.Unix(0, created), time.Unix(0, updated)
+	return t, err
+}
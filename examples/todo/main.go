@@ -3,6 +3,8 @@ package main
 
 import (
 	"context"
+	"embed"
+	"flag"
 	"fmt"
 	"html/template"
 	"io"
@@ -11,13 +13,37 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gabrielmiguelok/golivekit/examples/internal/store"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
 	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+	"github.com/gabrielmiguelok/golivekit/pkg/seed"
 )
 
+// fixtureFiles holds the todos the list starts with.
+//
+//go:embed fixtures/*.json
+var fixtureFiles embed.FS
+
 func main() {
+	dbPath := flag.String("db", "", "SQLite database file (build with -tags sqlite); empty keeps todos in memory")
+	flag.Parse()
+
+	// Todos live in memory by default, in SQLite with -db
+	var repo store.TodoRepo = store.NewMemoryTodos()
+	if *dbPath != "" {
+		db, err := store.Open(context.Background(), *dbPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+		repo = store.NewSQLTodos(db)
+	}
+	if err := seedTodos(context.Background(), repo); err != nil {
+		log.Fatal(err)
+	}
+
 	// Create todo list component
-	todoList := NewTodoList()
+	todoList := NewTodoList(repo)
 
 	// HTTP handlers
 	http.HandleFunc("/", handleHome)
@@ -27,21 +53,35 @@ func main() {
 	log.Fatal(http.ListenAndServe(":3001", nil))
 }
 
-// Todo represents a todo item.
-type Todo struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+// seedTodos fills an empty repository with the fixtures, so a database
+// keeps the user's todos across restarts.
+func seedTodos(ctx context.Context, repo store.TodoRepo) error {
+	existing, err := repo.List(ctx)
+	if err != nil || len(existing) > 0 {
+		return err
+	}
+	fixtures, err := seed.ReadFixtures(fixtureFiles, "fixtures/*.json")
+	if err != nil {
+		return err
+	}
+	var todos []store.Todo
+	if err := fixtures.Decode("todos", &todos); err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		if err := repo.Save(ctx, todo); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // TodoList is a LiveView component for managing todos.
 type TodoList struct {
 	core.BaseComponent
 
-	todos      []Todo
+	repo       store.TodoRepo
+	todos      []store.Todo
 	filter     string // all, active, completed
 	editingID  string
 	form       *forms.Form
@@ -49,8 +89,8 @@ type TodoList struct {
 	mu         sync.RWMutex
 }
 
-// NewTodoList creates a new todo list component.
-func NewTodoList() *TodoList {
+// NewTodoList creates a new todo list component backed by repo.
+func NewTodoList(repo store.TodoRepo) *TodoList {
 	// Create form for adding todos
 	form := forms.NewForm("todo_form")
 	form.AddField(forms.Field{
@@ -70,7 +110,8 @@ func NewTodoList() *TodoList {
 	})
 
 	return &TodoList{
-		todos:  make([]Todo, 0),
+		repo:   repo,
+		todos:  make([]store.Todo, 0),
 		filter: "all",
 		form:   form,
 	}
@@ -83,30 +124,11 @@ func (c *TodoList) Name() string {
 
 // Mount initializes the component.
 func (c *TodoList) Mount(ctx context.Context, params core.Params, session core.Session) error {
-	// Load initial todos (in a real app, this would come from a database)
-	c.todos = []Todo{
-		{
-			ID:        "1",
-			Title:     "Learn GoliveKit",
-			Completed: false,
-			CreatedAt: time.Now().Add(-24 * time.Hour),
-			UpdatedAt: time.Now().Add(-24 * time.Hour),
-		},
-		{
-			ID:        "2",
-			Title:     "Build a real-time app",
-			Completed: false,
-			CreatedAt: time.Now().Add(-12 * time.Hour),
-			UpdatedAt: time.Now().Add(-12 * time.Hour),
-		},
-		{
-			ID:          "3",
-			Title:       "Deploy to production",
-			Description: "Use Docker for deployment",
-			Completed:   true,
-			CreatedAt:   time.Now().Add(-6 * time.Hour),
-			UpdatedAt:   time.Now(),
-		},
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reload(ctx); err != nil {
+		return err
 	}
 
 	// Initialize changeset
@@ -195,7 +217,7 @@ func (c *TodoList) handleAdd(ctx context.Context, payload map[string]any) error
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	todo := Todo{
+	todo := store.Todo{
 		ID:          fmt.Sprintf("%d", time.Now().UnixNano()),
 		Title:       title,
 		Description: description,
@@ -204,11 +226,13 @@ func (c *TodoList) handleAdd(ctx context.Context, payload map[string]any) error
 		UpdatedAt:   time.Now(),
 	}
 
-	c.todos = append(c.todos, todo)
+	if err := c.repo.Save(ctx, todo); err != nil {
+		return err
+	}
 	c.changeset = forms.Cast(nil, nil, []string{"title", "description"})
 	c.form.Reset()
 
-	return nil
+	return c.reload(ctx)
 }
 
 func (c *TodoList) handleToggle(ctx context.Context, payload map[string]any) error {
@@ -217,15 +241,17 @@ func (c *TodoList) handleToggle(ctx context.Context, payload map[string]any) err
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for i := range c.todos {
-		if c.todos[i].ID == id {
-			c.todos[i].Completed = !c.todos[i].Completed
-			c.todos[i].UpdatedAt = time.Now()
-			break
-		}
+	todo, err := c.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	todo.Completed = !todo.Completed
+	todo.UpdatedAt = time.Now()
+	if err := c.repo.Save(ctx, todo); err != nil {
+		return err
 	}
 
-	return nil
+	return c.reload(ctx)
 }
 
 func (c *TodoList) handleDelete(ctx context.Context, payload map[string]any) error {
@@ -234,14 +260,11 @@ func (c *TodoList) handleDelete(ctx context.Context, payload map[string]any) err
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for i := range c.todos {
-		if c.todos[i].ID == id {
-			c.todos = append(c.todos[:i], c.todos[i+1:]...)
-			break
-		}
+	if err := c.repo.Delete(ctx, id); err != nil {
+		return err
 	}
 
-	return nil
+	return c.reload(ctx)
 }
 
 func (c *TodoList) handleEdit(ctx context.Context, payload map[string]any) error {
@@ -271,19 +294,21 @@ func (c *TodoList) handleSave(ctx context.Context, payload map[string]any) error
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for i := range c.todos {
-		if c.todos[i].ID == c.editingID {
-			c.todos[i].Title = title
-			c.todos[i].Description = description
-			c.todos[i].UpdatedAt = time.Now()
-			break
-		}
+	todo, err := c.repo.Get(ctx, c.editingID)
+	if err != nil {
+		return err
+	}
+	todo.Title = title
+	todo.Description = description
+	todo.UpdatedAt = time.Now()
+	if err := c.repo.Save(ctx, todo); err != nil {
+		return err
 	}
 
 	c.editingID = ""
 	c.form.Reset()
 
-	return nil
+	return c.reload(ctx)
 }
 
 func (c *TodoList) handleCancel(ctx context.Context) error {
@@ -313,15 +338,11 @@ func (c *TodoList) handleClearCompleted(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	filtered := make([]Todo, 0)
-	for _, todo := range c.todos {
-		if !todo.Completed {
-			filtered = append(filtered, todo)
-		}
+	if err := c.repo.DeleteCompleted(ctx); err != nil {
+		return err
 	}
-	c.todos = filtered
 
-	return nil
+	return c.reload(ctx)
 }
 
 func (c *TodoList) handleValidate(ctx context.Context, payload map[string]any) error {
@@ -338,8 +359,18 @@ func (c *TodoList) handleValidate(ctx context.Context, payload map[string]any) e
 	return nil
 }
 
-func (c *TodoList) filteredTodos() []Todo {
-	filtered := make([]Todo, 0)
+// reload reads the todos from the repository; the caller holds c.mu.
+func (c *TodoList) reload(ctx context.Context) error {
+	todos, err := c.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load todos: %w", err)
+	}
+	c.todos = todos
+	return nil
+}
+
+func (c *TodoList) filteredTodos() []store.Todo {
+	filtered := make([]store.Todo, 0)
 	for _, todo := range c.todos {
 		switch c.filter {
 		case "active":
@@ -359,9 +390,12 @@ func (c *TodoList) filteredTodos() []Todo {
 
 // HandleAPI handles REST API requests.
 func (c *TodoList) HandleAPI(w http.ResponseWriter, r *http.Request) {
-	c.mu.RLock()
-	count := len(c.todos)
-	c.mu.RUnlock()
+	todos, err := c.repo.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	count := len(todos)
 
 	w.Header().Set("Content-Type", "application/json")
 	fmt.Fprintf(w, `{"count": %d}`, count)
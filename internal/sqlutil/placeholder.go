@@ -0,0 +1,9 @@
+// Package sqlutil holds small helpers shared by the SQL-backed packages.
+package sqlutil
+
+import "strconv"
+
+// PostgresPlaceholder numbers bind parameters ($1, $2, ...).
+func PostgresPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gabrielmiguelok/golivekit/internal/website"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
 )
 
 // FeaturesOptions configures the features section.
@@ -20,8 +21,15 @@ type FeaturesOptions struct {
 	Columns int
 }
 
-// RenderFeatures generates a feature grid section.
+// RenderFeatures generates a feature grid section. Renders are memoized by
+// options, so the grid is built once per distinct feature set.
 func RenderFeatures(opts FeaturesOptions) string {
+	return featuresMemo.Render(opts)
+}
+
+var featuresMemo = core.Memo(renderFeatures)
+
+func renderFeatures(opts FeaturesOptions) string {
 	var sb strings.Builder
 
 	columns := opts.Columns
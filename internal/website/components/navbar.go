@@ -62,6 +62,8 @@ func RenderNavbar(opts NavbarOptions) string {
 		if link.External {
 			target = ` target="_blank"`
 			rel = ` rel="noopener noreferrer"`
+		} else if link.Live {
+			rel = ` lv-navigate`
 		}
 		sb.WriteString(fmt.Sprintf(`<a href="%s" class="btn btn-ghost"%s%s>%s</a>`,
 			html.EscapeString(link.URL),
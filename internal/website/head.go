@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"html"
 	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/client"
+	"github.com/gabrielmiguelok/golivekit/pkg/i18n"
 )
 
 // RenderHead generates a complete <head> section with SEO, Open Graph, and JSON-LD.
@@ -73,9 +76,24 @@ func RenderHead(cfg PageConfig, customCSS string) string {
 
 	// Preconnect for performance (none needed since we use inline CSS and system fonts)
 
-	// Inline CSS
+	// Preload the non-critical stylesheet; it is applied once loaded so it
+	// never blocks the first paint.
+	if cfg.StylesheetURL != "" {
+		href := html.EscapeString(cfg.StylesheetURL)
+		sb.WriteString(fmt.Sprintf(`<link rel="preload" href="%s" as="style" onload="this.onload=null;this.rel='stylesheet'">`+"\n", href))
+		sb.WriteString(fmt.Sprintf(`<noscript><link rel="stylesheet" href="%s"></noscript>`+"\n", href))
+	}
+
+	// Inline CSS (only the critical part when the rest is loaded async)
 	sb.WriteString("<style>\n")
-	sb.WriteString(RenderStyles())
+	if cfg.StylesheetURL != "" {
+		sb.WriteString(CriticalStyles())
+	} else {
+		sb.WriteString(RenderStyles())
+	}
+	if cfg.TextDirection() == i18n.DirRTL {
+		sb.WriteString(cssDirection())
+	}
 	if customCSS != "" {
 		sb.WriteString("\n")
 		sb.WriteString(customCSS)
@@ -149,17 +167,35 @@ func renderJSONLD(cfg PageConfig) string {
 	return fmt.Sprintf(`<script type="application/ld+json">%s</script>`+"\n", jsonLD)
 }
 
-// RenderDocument wraps content in a complete HTML document.
+// clientAlias is the stable, redirecting URL of the GoliveKit client.
+const clientAlias = `<script src="` + client.Prefix + `golivekit.js"></script>`
+
+// RenderDocument wraps content in a complete HTML document. Script tags
+// loading the client through its stable alias are rewritten to the versioned
+// URL, which browsers cache until the next deploy.
 func RenderDocument(cfg PageConfig, customCSS, bodyContent string) string {
 	lang := cfg.Language
 	if lang == "" {
 		lang = "en"
 	}
+	bodyContent = strings.ReplaceAll(bodyContent, clientAlias, client.ScriptTag())
 
 	return fmt.Sprintf(`<!DOCTYPE html>
-<html lang="%s">
+<html lang="%s" dir="%s">
 %s<body>
 %s
 </body>
-</html>`, lang, RenderHead(cfg, customCSS), bodyContent)
+</html>`, html.EscapeString(lang), cfg.TextDirection(), RenderHead(cfg, customCSS), bodyContent)
+}
+
+// TextDirection returns the page's text direction: the explicit Direction if
+// set, otherwise the direction of Language.
+func (cfg PageConfig) TextDirection() string {
+	switch strings.ToLower(cfg.Direction) {
+	case i18n.DirRTL:
+		return i18n.DirRTL
+	case i18n.DirLTR:
+		return i18n.DirLTR
+	}
+	return i18n.Direction(cfg.Language)
 }
@@ -2,35 +2,17 @@ package website
 
 import (
 	"fmt"
+	"hash/fnv"
+	"net/http"
 	"strings"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/theme"
 )
 
-// Color palette (WCAG 2.1 AA compliant - 4.5:1 minimum contrast ratio)
-var Colors = map[string]string{
-	// Backgrounds
-	"bg":        "#0F172A", // Dark blue - main background
-	"bgAlt":     "#1E293B", // Lighter - cards, sections
-	"bgHover":   "#334155", // Hover states
-	"bgCode":    "#0D1117", // Code blocks
-
-	// Text - WCAG compliant (tested with WebAIM contrast checker)
-	"text":      "#F8FAFC", // White - primary text (15.5:1 on bg)
-	"textMuted": "#CBD5E1", // Light gray - secondary text (8.5:1 on bg, 6.5:1 on bgAlt)
-	"textDim":   "#94A3B8", // Dimmer text (5.2:1 on bg, 4.5:1 on bgAlt)
-
-	// Brand colors - bright versions for dark backgrounds
-	"primary":       "#A78BFA", // Lighter purple (7:1 on bg)
-	"primaryBright": "#C4B5FD", // Even lighter for small text
-	"secondary":     "#22D3EE", // Bright cyan (8:1 on bg)
-	"accent":        "#67E8F9", // Light cyan - highlights (10:1 on bg)
-
-	// Status colors - bright versions for dark backgrounds
-	"success": "#34D399", // Bright green (7:1 on bg)
-	"warning": "#FBBF24", // Bright amber (9:1 on bg)
-	"danger":  "#F87171", // Bright red (5.5:1 on bg)
-	"info":    "#60A5FA", // Bright blue (6:1 on bg)
-
-	// Category colors - bright versions for text on dark backgrounds (min 4.5:1)
+// categoryColors are the package category colors of the landing page -
+// bright versions for text on dark backgrounds (min 4.5:1)
+var categoryColors = map[string]string{
 	"catCore":     "#C4B5FD", // Light purple (9:1 on bgAlt)
 	"catUI":       "#93C5FD", // Light blue (8:1 on bgAlt)
 	"catState":    "#6EE7B7", // Light green (9:1 on bgAlt)
@@ -39,27 +21,24 @@ var Colors = map[string]string{
 	"catUtils":    "#D1D5DB", // Light gray (9:1 on bgAlt)
 	"catPlugins":  "#F9A8D4", // Light pink (8:1 on bgAlt)
 	"catCLI":      "#67E8F9", // Light cyan (10:1 on bgAlt)
+}
 
-	// Borders
-	"border":      "#334155", // Subtle borders
-	"borderLight": "#475569", // Lighter borders
+// siteTokens returns the tokens of the site: the dark mode of t plus the
+// category colors
+func siteTokens(t theme.Theme) theme.Tokens {
+	return t.Resolve(true).With(theme.Tokens{Colors: categoryColors})
 }
 
+// Color palette: the dark mode of the default theme (WCAG 2.1 AA compliant -
+// 4.5:1 minimum contrast ratio) plus the category colors
+var Colors = siteTokens(theme.Default()).Colors
+
 // Typography uses system font stack for instant loading
-var FontFamily = `system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif`
-var FontMono = `'SF Mono', SFMono-Regular, ui-monospace, 'DejaVu Sans Mono', Menlo, Consolas, monospace`
+var FontFamily = theme.Default().Light.Fonts["sans"]
+var FontMono = theme.Default().Light.Fonts["mono"]
 
 // Spacing uses 8px grid system
-var Spacing = map[string]string{
-	"xs":  "0.25rem", // 4px
-	"sm":  "0.5rem",  // 8px
-	"md":  "1rem",    // 16px
-	"lg":  "1.5rem",  // 24px
-	"xl":  "2rem",    // 32px
-	"2xl": "3rem",    // 48px
-	"3xl": "4rem",    // 64px
-	"4xl": "6rem",    // 96px
-}
+var Spacing = theme.Default().Light.Spacing
 
 // Breakpoints for responsive design (mobile-first: min-width)
 var Breakpoints = map[string]string{
@@ -102,8 +81,68 @@ func WithAnimations(include bool) StyleOption {
 	}
 }
 
+// Style parts selected by renderStyles.
+const (
+	partCritical = 1 << iota
+	partDeferred
+	partAll = partCritical | partDeferred
+)
+
 // RenderStyles generates the complete CSS for the landing page.
 func RenderStyles(opts ...StyleOption) string {
+	return renderStyles(partAll, opts)
+}
+
+// CriticalStyles generates the CSS needed for above-the-fold content: reset,
+// variables, typography, layout, navigation, hero and buttons. It is inlined
+// in the document head when PageConfig.StylesheetURL is set.
+func CriticalStyles(opts ...StyleOption) string {
+	return renderStyles(partCritical, opts)
+}
+
+// DeferredStyles generates the below-the-fold CSS (cards, code blocks,
+// package grid, stats, animations) that is loaded asynchronously. It is
+// wrapped in a cascade layer so it never overrides the inlined critical CSS
+// or page-specific custom CSS, whatever order the browser applies them in.
+func DeferredStyles(opts ...StyleOption) string {
+	return "@layer deferred{" + renderStyles(partDeferred, opts) + "}"
+}
+
+// CodeStyles returns the code block CSS for pages that load DeferredStyles
+// asynchronously but show code above the fold (e.g. the docs).
+func CodeStyles() string {
+	return cssCode()
+}
+
+var (
+	deferredOnce sync.Once
+	deferredCSS  string
+	deferredETag string
+)
+
+// StylesheetHandler serves DeferredStyles for PageConfig.StylesheetURL with
+// long-lived caching and ETag revalidation.
+func StylesheetHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deferredOnce.Do(func() {
+			deferredCSS = DeferredStyles()
+			h := fnv.New64a()
+			h.Write([]byte(deferredCSS))
+			deferredETag = fmt.Sprintf(`"%x"`, h.Sum64())
+		})
+
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		w.Header().Set("ETag", deferredETag)
+		if r.Header.Get("If-None-Match") == deferredETag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(deferredCSS))
+	})
+}
+
+func renderStyles(part int, opts []StyleOption) string {
 	cfg := &styleConfig{
 		customColors:      make(map[string]string),
 		includeReset:      true,
@@ -114,62 +153,62 @@ func RenderStyles(opts ...StyleOption) string {
 		opt(cfg)
 	}
 
-	// Merge custom colors
-	colors := make(map[string]string)
-	for k, v := range Colors {
-		colors[k] = v
-	}
-	for k, v := range cfg.customColors {
-		colors[k] = v
-	}
+	// Theme in use, with custom colors on top
+	tokens := siteTokens(theme.Current()).With(theme.Tokens{Colors: cfg.customColors})
 
 	var sb strings.Builder
 
-	// CSS Reset
-	if cfg.includeReset {
-		sb.WriteString(cssReset())
-	}
+	if part&partCritical != 0 {
+		// CSS Reset
+		if cfg.includeReset {
+			sb.WriteString(cssReset())
+		}
 
-	// CSS Variables
-	sb.WriteString(cssVariables(colors))
+		// CSS Variables
+		sb.WriteString(cssVariables(tokens))
 
-	// Base styles
-	sb.WriteString(cssBase())
+		// Base styles
+		sb.WriteString(cssBase())
 
-	// Typography
-	sb.WriteString(cssTypography())
+		// Typography
+		sb.WriteString(cssTypography())
 
-	// Layout
-	sb.WriteString(cssLayout())
+		// Layout
+		sb.WriteString(cssLayout())
 
-	// Components
-	sb.WriteString(cssComponents())
+		// Components
+		sb.WriteString(cssComponents())
 
-	// Buttons
-	sb.WriteString(cssButtons())
+		// Buttons
+		sb.WriteString(cssButtons())
+	}
 
-	// Cards
-	sb.WriteString(cssCards())
+	if part&partDeferred != 0 {
+		// Cards
+		sb.WriteString(cssCards())
 
-	// Code blocks
-	sb.WriteString(cssCode())
+		// Code blocks
+		sb.WriteString(cssCode())
 
-	// Package grid
-	sb.WriteString(cssPackageGrid())
+		// Package grid
+		sb.WriteString(cssPackageGrid())
 
-	// Stats
-	sb.WriteString(cssStats())
+		// Stats
+		sb.WriteString(cssStats())
 
-	// Animations
-	if cfg.includeAnimations {
-		sb.WriteString(cssAnimations())
+		// Animations
+		if cfg.includeAnimations {
+			sb.WriteString(cssAnimations())
+		}
 	}
 
-	// Accessibility
-	sb.WriteString(cssAccessibility())
+	if part&partCritical != 0 {
+		// Accessibility
+		sb.WriteString(cssAccessibility())
 
-	// Responsive (mobile-first)
-	sb.WriteString(cssResponsive())
+		// Responsive (mobile-first)
+		sb.WriteString(cssResponsive())
+	}
 
 	return sb.String()
 }
@@ -187,12 +226,8 @@ ul,ol{list-style:none}
 `
 }
 
-func cssVariables(colors map[string]string) string {
-	var vars []string
-	for name, value := range colors {
-		vars = append(vars, fmt.Sprintf("--color-%s:%s", name, value))
-	}
-	return fmt.Sprintf(`:root{%s;--font-sans:%s;--font-mono:%s}`, strings.Join(vars, ";"), FontFamily, FontMono)
+func cssVariables(tokens theme.Tokens) string {
+	return tokens.CSS(":root")
 }
 
 func cssBase() string {
@@ -235,7 +270,7 @@ func cssComponents() string {
 	return `
 .badge{display:none;align-items:center;gap:0.5rem;padding:0.4rem 0.75rem;border-radius:9999px;font-size:0.75rem;font-weight:600;background:#7C3AED;color:#FFFFFF;border:1px solid #8B5CF6}
 .badge-success{background:#059669;color:#FFFFFF;border-color:#10B981}
-.nav{position:fixed;top:0;left:0;right:0;z-index:100;padding:0.5rem 0;background:rgba(15,23,42,0.98);backdrop-filter:blur(12px);border-bottom:1px solid var(--color-border)}
+.nav{position:fixed;inset-block-start:0;inset-inline:0;z-index:100;padding:0.5rem 0;background:rgba(15,23,42,0.98);backdrop-filter:blur(12px);border-bottom:1px solid var(--color-border)}
 .nav-inner{display:flex;align-items:center;justify-content:space-between;gap:0.5rem}
 .nav-links{display:none;align-items:center;gap:0.5rem}
 .logo{font-size:1.1rem;font-weight:800;letter-spacing:-0.02em}
@@ -350,12 +385,22 @@ func cssAnimations() string {
 func cssAccessibility() string {
 	return `
 .sr-only{position:absolute;width:1px;height:1px;padding:0;margin:-1px;overflow:hidden;clip:rect(0,0,0,0);white-space:nowrap;border:0}
-.skip-link{position:absolute;top:-40px;left:0;background:#7C3AED;color:#FFFFFF;padding:0.5rem 1rem;z-index:1000;transition:top 0.3s;font-weight:600}
+.skip-link{position:absolute;top:-40px;inset-inline-start:0;background:#7C3AED;color:#FFFFFF;padding:0.5rem 1rem;z-index:1000;transition:top 0.3s;font-weight:600}
 .skip-link:focus{top:0}
 :focus-visible{outline:2px solid var(--color-primary);outline-offset:2px}
 `
 }
 
+// cssDirection returns right-to-left overrides. Layout uses logical
+// properties (inset-inline, padding-inline) so it mirrors automatically under
+// dir="rtl"; code is always left-to-right.
+func cssDirection() string {
+	return `
+[dir=rtl] pre,[dir=rtl] code,[dir=rtl] .code-content{direction:ltr;text-align:left;unicode-bidi:isolate}
+[dir=rtl] .flip-rtl{transform:scaleX(-1)}
+`
+}
+
 func cssResponsive() string {
 	// Mobile-first: breakpoints use min-width
 	return `
@@ -23,10 +23,16 @@ type PageConfig struct {
 	OGImage string
 	// Language is the page language (default: "en")
 	Language string
+	// Direction is the text direction ("ltr" or "rtl"). When empty it is
+	// derived from Language, so Arabic or Hebrew pages render right-to-left.
+	Direction string
 	// ThemeColor is the mobile browser theme color
 	ThemeColor string
 	// Favicon is the path to the favicon
 	Favicon string
+	// StylesheetURL serves DeferredStyles (see StylesheetHandler). When set,
+	// only critical CSS is inlined and the rest loads asynchronously.
+	StylesheetURL string
 }
 
 // Feature represents a feature card in the features section.
@@ -89,6 +95,8 @@ type NavLink struct {
 	URL string
 	// External indicates if the link opens in a new tab
 	External bool
+	// Live opens the route over the page's socket (lv-navigate)
+	Live bool
 }
 
 // FooterConfig configures the footer section.
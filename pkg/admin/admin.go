@@ -0,0 +1,208 @@
+// Package admin serves a CRUD admin area for registered models, in the
+// spirit of Django's admin: an index of models, a searchable list per model
+// with delete, and create/edit forms validated by the model's changeset.
+//
+// Sites are usually generated from the application's models with
+// "golive generate admin", then mounted behind an authorization check:
+//
+//	site := adminsite.NewSite(adminsite.Repos{Post: posts, User: users})
+//	site.Authorize = func(ctx context.Context, session core.Session) bool {
+//		return session.GetString("role") == "admin"
+//	}
+//	site.Mount(r, "/admin")
+//
+// or built by hand:
+//
+//	site := admin.NewSite("Acme Admin")
+//	site.Register(admin.Model{
+//		Name:      "post",
+//		Fields:    []forms.Field{forms.TextField("title", "Title", forms.WithRequired())},
+//		Repo:      posts,
+//		Changeset: models.PostChangeset,
+//	})
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+)
+
+// Repo stores a model's records. forms.MemoryRepo implements it; database
+// repositories follow the same changeset conventions.
+type Repo interface {
+	Insert(cs *forms.Changeset) (string, error)
+	Get(id string) (map[string]any, error)
+	Update(id string, cs *forms.Changeset) error
+	Delete(id string) error
+	List() []map[string]any
+}
+
+// ChangesetFunc casts and validates params over a record's data, as the
+// application does outside the admin.
+type ChangesetFunc func(data, params map[string]any) *forms.Changeset
+
+// Model is a registered model.
+type Model struct {
+	// Name is the model's URL segment ("post").
+	Name string
+
+	// Label is the display name of the records (default: Name, capitalized
+	// with an "s").
+	Label string
+
+	// Fields are the editable fields, in form order.
+	Fields []forms.Field
+
+	// ListFields are the list's columns (default: the first three fields).
+	ListFields []string
+
+	// SearchFields are matched by the list's search (default: the text,
+	// email and textarea fields).
+	SearchFields []string
+
+	Repo Repo
+
+	// Changeset validates saves (default: casts Fields and requires the
+	// Required ones).
+	Changeset ChangesetFunc
+}
+
+// Site is an admin area.
+type Site struct {
+	Title string
+
+	// Authorize decides who may use the site. Everyone else is redirected
+	// to LoginPath; a nil Authorize denies everyone.
+	Authorize func(ctx context.Context, session core.Session) bool
+
+	// LoginPath is where unauthorized visitors are sent (default "/login").
+	LoginPath string
+
+	mu     sync.RWMutex
+	prefix string
+	router *router.Router
+	models []*Model
+	byName map[string]*Model
+}
+
+// NewSite creates a site without models.
+func NewSite(title string) *Site {
+	return &Site{
+		Title:     title,
+		LoginPath: "/login",
+		byName:    make(map[string]*Model),
+	}
+}
+
+// Register adds a model. It panics when the name is taken or the model has
+// no repository, as http.ServeMux does for conflicting patterns.
+func (s *Site) Register(m Model) {
+	if m.Name == "" || m.Repo == nil {
+		panic(fmt.Sprintf("admin: model %q needs a name and a repository", m.Name))
+	}
+	if m.Label == "" {
+		m.Label = strings.ToUpper(m.Name[:1]) + m.Name[1:] + "s"
+	}
+	if len(m.ListFields) == 0 {
+		for _, f := range m.Fields[:min(3, len(m.Fields))] {
+			m.ListFields = append(m.ListFields, f.Name)
+		}
+	}
+	if len(m.SearchFields) == 0 {
+		for _, f := range m.Fields {
+			if f.Type == forms.FieldText || f.Type == forms.FieldEmail || f.Type == forms.FieldTextarea {
+				m.SearchFields = append(m.SearchFields, f.Name)
+			}
+		}
+	}
+	if m.Changeset == nil {
+		m.Changeset = defaultChangeset(m.Fields)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.byName[m.Name]; ok {
+		panic(fmt.Sprintf("admin: model %q is already registered", m.Name))
+	}
+	s.models = append(s.models, &m)
+	s.byName[m.Name] = &m
+}
+
+// Models returns the registered models in registration order.
+func (s *Site) Models() []*Model {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Model(nil), s.models...)
+}
+
+// Model returns a registered model.
+func (s *Site) Model(name string) (*Model, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.byName[name]
+	return m, ok
+}
+
+// Mount registers the site's live views under prefix:
+//
+//	/admin/              models
+//	/admin/{model}       list, search and delete
+//	/admin/{model}/new   create
+//	/admin/{model}/{id}  edit
+//	/admin/_sessions     live sessions that cost the most
+//
+// Authorize runs on the HTTP render and on join, before any view mounts.
+func (s *Site) Mount(r *router.Router, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	s.mu.Lock()
+	s.prefix = prefix
+	s.router = r
+	s.mu.Unlock()
+
+	r.Group(prefix, func(g *router.RouteGroup) {
+		g.OnMount(s.authorize)
+		g.Live("/{$}", func() core.Component { return &indexView{site: s} })
+		g.Live("/"+sessionsPage, func() core.Component { return &sessionsView{site: s} })
+		g.Live("/{model}", func() core.Component { return &listView{site: s} })
+		g.Live("/{model}/new", func() core.Component { return &editView{site: s} })
+		g.Live("/{model}/{id}", func() core.Component { return &editView{site: s} })
+	})
+}
+
+func (s *Site) authorize(ctx context.Context, params core.Params, session core.Session) error {
+	if s.Authorize == nil || !s.Authorize(ctx, session) {
+		return router.HaltRedirect(s.LoginPath)
+	}
+	return nil
+}
+
+// path returns the URL of a page of the site.
+func (s *Site) path(parts ...string) string {
+	s.mu.RLock()
+	prefix := s.prefix
+	s.mu.RUnlock()
+	return prefix + "/" + strings.Join(parts, "/")
+}
+
+// defaultChangeset casts the fields and requires the Required ones.
+func defaultChangeset(fields []forms.Field) ChangesetFunc {
+	var allowed, required []string
+	for _, f := range fields {
+		if f.Disabled || f.ReadOnly {
+			continue
+		}
+		allowed = append(allowed, f.Name)
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+	return func(data, params map[string]any) *forms.Changeset {
+		return forms.Cast(data, params, allowed).ValidateRequired(required...)
+	}
+}
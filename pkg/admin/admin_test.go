@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+)
+
+func newTestSite(t *testing.T) (*Site, *forms.MemoryRepo) {
+	t.Helper()
+	posts := forms.NewMemoryRepo()
+	posts.Insert(forms.Cast(nil, map[string]any{"title": "Hello world", "views": 3}, []string{"title", "views"}))
+	posts.Insert(forms.Cast(nil, map[string]any{"title": "Second post"}, []string{"title"}))
+
+	site := NewSite("Admin")
+	site.Register(Model{
+		Name: "post",
+		Fields: []forms.Field{
+			forms.TextField("title", "Title", forms.WithRequired()),
+			forms.NumberField("views", "Views"),
+			forms.CheckboxField("published", "Published"),
+		},
+		Repo: posts,
+	})
+	return site, posts
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestSite_Authorize(t *testing.T) {
+	site, _ := newTestSite(t)
+	r := router.New()
+	site.Mount(r, "/admin")
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := ts.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, _ := get(t, client, ts.URL+"/admin/post")
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/login" {
+		t.Errorf("Expected a redirect to /login without Authorize, got %d %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	site.Authorize = func(ctx context.Context, session core.Session) bool { return true }
+	resp, body := get(t, client, ts.URL+"/admin/post")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "Hello world") || !strings.Contains(body, `href="/admin/post/new"`) {
+		t.Errorf("Expected the post list, got %d:\n%s", resp.StatusCode, body)
+	}
+
+	_, body = get(t, client, ts.URL+"/admin/post/1")
+	if !strings.Contains(body, `name="title" value="Hello world"`) {
+		t.Errorf("Expected the edit form, got:\n%s", body)
+	}
+
+	_, body = get(t, client, ts.URL+"/admin/_sessions")
+	if !strings.Contains(body, `aria-current="page">Sessions`) || !strings.Contains(body, "No live sessions.") {
+		t.Errorf("Expected the sessions view, got:\n%s", body)
+	}
+
+	resp, _ = get(t, client, ts.URL+"/admin/comment")
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/admin/" {
+		t.Errorf("Expected unknown models to redirect to the index, got %d %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+}
+
+func render(t *testing.T, c core.Component) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := c.Render(context.Background()).Render(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestListView_SearchDelete(t *testing.T) {
+	site, posts := newTestSite(t)
+	ctx := context.Background()
+	v := &listView{site: site}
+	v.Mount(ctx, core.Params{"model": "post"}, nil)
+
+	v.HandleEvent(ctx, "search", map[string]any{"value": "SECOND"})
+	if out := render(t, v); strings.Contains(out, "Hello world") || !strings.Contains(out, "Second post") {
+		t.Errorf("Expected only the matching post:\n%s", out)
+	}
+
+	v.HandleEvent(ctx, "delete", map[string]any{"id": "2"})
+	if len(posts.List()) != 1 {
+		t.Errorf("Expected the post to be deleted, got %v", posts.List())
+	}
+}
+
+func TestEditView_Save(t *testing.T) {
+	site, posts := newTestSite(t)
+	ctx := context.Background()
+	v := &editView{site: site}
+	v.Mount(ctx, core.Params{"model": "post", "id": "1"}, nil)
+
+	v.HandleEvent(ctx, "save", map[string]any{"title": "", "views": "5"})
+	if out := render(t, v); !strings.Contains(out, `aria-invalid="true"`) {
+		t.Errorf("Expected the title error:\n%s", out)
+	}
+	if rec, _ := posts.Get("1"); rec["title"] != "Hello world" {
+		t.Errorf("Expected an invalid save to change nothing, got %v", rec)
+	}
+
+	v.HandleEvent(ctx, "save", map[string]any{"title": "Edited", "views": "5", "published": "true"})
+	rec, _ := posts.Get("1")
+	if rec["title"] != "Edited" || rec["views"] != 5 || rec["published"] != true {
+		t.Errorf("Expected the cast values to be saved, got %v", rec)
+	}
+}
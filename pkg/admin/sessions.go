@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+)
+
+// sessionsPage is the URL segment of the sessions view.
+const sessionsPage = "_sessions"
+
+// sessionsRefresh is how often the sessions view reloads its table.
+const sessionsRefresh = 2 * time.Second
+
+// sessionsShown caps the rows of the sessions view.
+const sessionsShown = 50
+
+// sessionsView ranks the router's live sessions by what they cost.
+type sessionsView struct {
+	core.BaseComponent
+	site  *Site
+	order router.UsageOrder
+	stop  func()
+}
+
+func (v *sessionsView) Name() string { return "admin-sessions" }
+
+func (v *sessionsView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	v.order = usageOrder(params.Get("by"))
+	if socket := v.Socket(); socket != nil {
+		v.stop = socket.SendInterval(sessionsRefresh, "refresh")
+	}
+	return nil
+}
+
+func (v *sessionsView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if event == "sort" {
+		by, _ := payload["by"].(string)
+		v.order = usageOrder(by)
+	}
+	return nil
+}
+
+// HandleInfo re-renders on the refresh ticks.
+func (v *sessionsView) HandleInfo(ctx context.Context, msg any) error {
+	return nil
+}
+
+func (v *sessionsView) Terminate(ctx context.Context, reason core.TerminateReason) error {
+	if v.stop != nil {
+		v.stop()
+	}
+	return nil
+}
+
+// usageOrders are the sort keys of the sessions view, in column order.
+var usageOrders = []struct {
+	by    string
+	label string
+	order router.UsageOrder
+}{
+	{"event", "Event time", router.ByEventTime},
+	{"render", "Render time", router.ByRenderTime},
+	{"bytes", "Sent", router.ByBytesSent},
+}
+
+func usageOrder(by string) router.UsageOrder {
+	for _, o := range usageOrders {
+		if o.by == by {
+			return o.order
+		}
+	}
+	return router.ByEventTime
+}
+
+func (v *sessionsView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		v.site.mu.RLock()
+		r := v.site.router
+		v.site.mu.RUnlock()
+
+		var sb strings.Builder
+		v.site.header(&sb, sessionsPage)
+		fmt.Fprintf(&sb, `<div class="lv-admin-toolbar"><h1>Sessions</h1><span>%d connected</span></div>`, r.SessionManager().Count())
+
+		sb.WriteString(`<div data-slot="admin-sessions"><table class="lv-admin-table"><thead><tr><th>Component</th><th>Path</th><th>Connected</th><th>Events</th>`)
+		for _, o := range usageOrders {
+			attr := ""
+			if o.order == v.order {
+				attr = ` aria-sort="descending"`
+			}
+			fmt.Fprintf(&sb, `<th%s><button type="button" lv-click="sort" lv-value-by="%s">%s</button></th>`, attr, o.by, o.label)
+		}
+		sb.WriteString(`</tr></thead><tbody>`)
+		top := r.SessionManager().Top(sessionsShown, v.order)
+		for _, u := range top {
+			fmt.Fprintf(&sb, `<tr><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+				html.EscapeString(u.Component), html.EscapeString(u.Path),
+				time.Since(u.Since).Round(time.Second), u.Events,
+				u.EventTime.Round(time.Microsecond), u.RenderTime.Round(time.Microsecond), byteSize(u.BytesSent))
+		}
+		sb.WriteString(`</tbody></table>`)
+		if len(top) == 0 {
+			sb.WriteString(`<p class="lv-admin-empty">No live sessions.</p>`)
+		}
+		sb.WriteString(`</div></main></div>`)
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// byteSize formats n bytes for people.
+func byteSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,409 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+	"github.com/gabrielmiguelok/golivekit/pkg/router"
+	"github.com/gabrielmiguelok/golivekit/pkg/search"
+)
+
+// indexView lists the models.
+type indexView struct {
+	core.BaseComponent
+	site *Site
+}
+
+func (v *indexView) Name() string { return "admin-index" }
+
+func (v *indexView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		var sb strings.Builder
+		v.site.header(&sb, "")
+		sb.WriteString(`<ul class="lv-admin-models">`)
+		for _, m := range v.site.Models() {
+			fmt.Fprintf(&sb, `<li><a href="%s">%s</a> <span>%d</span></li>`,
+				html.EscapeString(v.site.path(m.Name)), html.EscapeString(m.Label), len(m.Repo.List()))
+		}
+		sb.WriteString(`</ul></main></div>`)
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// listView lists a model's records with search and delete.
+type listView struct {
+	core.BaseComponent
+	site  *Site
+	model *Model
+	query string
+	flash string
+}
+
+func (v *listView) Name() string { return "admin-list" }
+
+func (v *listView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	m, ok := v.site.Model(params.Get("model"))
+	if !ok {
+		return router.HaltRedirect(v.site.path())
+	}
+	v.model = m
+	v.query = params.Get("q")
+	return nil
+}
+
+func (v *listView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	switch event {
+	case "search":
+		v.query, _ = payload["value"].(string)
+	case "delete":
+		id := fmt.Sprint(payload["id"])
+		if err := v.model.Repo.Delete(id); err != nil && !errors.Is(err, forms.ErrNotFound) {
+			return err
+		}
+		v.flash = "Deleted " + id + "."
+	}
+	return nil
+}
+
+// records returns the records matching every search term in one of the
+// search fields.
+func (v *listView) records() []map[string]any {
+	records := v.model.Repo.List()
+	terms := search.Terms(v.query)
+	if len(terms) == 0 {
+		return records
+	}
+	var matched []map[string]any
+	for _, rec := range records {
+		var text strings.Builder
+		for _, f := range v.model.SearchFields {
+			text.WriteString(strings.ToLower(fmt.Sprint(rec[f])))
+			text.WriteByte(' ')
+		}
+		all := true
+		for _, term := range terms {
+			if !strings.Contains(text.String(), term) {
+				all = false
+				break
+			}
+		}
+		if all {
+			matched = append(matched, rec)
+		}
+	}
+	return matched
+}
+
+func (v *listView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		m := v.model
+		var sb strings.Builder
+		v.site.header(&sb, m.Name)
+		fmt.Fprintf(&sb, `<div class="lv-admin-toolbar"><h1>%s</h1><input type="search" placeholder="Search…" aria-label="Search %s" value="%s" lv-input="search" lv-debounce="200"><a class="lv-admin-button" href="%s">New</a></div>`,
+			html.EscapeString(m.Label), html.EscapeString(m.Label), html.EscapeString(v.query), html.EscapeString(v.site.path(m.Name, "new")))
+
+		sb.WriteString(`<div data-slot="admin-rows">`)
+		if v.flash != "" {
+			fmt.Fprintf(&sb, `<p class="lv-admin-flash" role="status">%s</p>`, html.EscapeString(v.flash))
+		}
+		sb.WriteString(`<table class="lv-admin-table"><thead><tr><th>ID</th>`)
+		for _, name := range m.ListFields {
+			fmt.Fprintf(&sb, `<th>%s</th>`, html.EscapeString(m.label(name)))
+		}
+		sb.WriteString(`<th></th></tr></thead><tbody>`)
+		records := v.records()
+		for _, rec := range records {
+			id := fmt.Sprint(rec["id"])
+			fmt.Fprintf(&sb, `<tr><td><a href="%s">%s</a></td>`, html.EscapeString(v.site.path(m.Name, id)), html.EscapeString(id))
+			for _, name := range m.ListFields {
+				fmt.Fprintf(&sb, `<td>%s</td>`, html.EscapeString(display(rec[name])))
+			}
+			fmt.Fprintf(&sb, `<td><button type="button" lv-click="delete" lv-value-id="%s" lv-confirm="Delete %s?">Delete</button></td></tr>`,
+				html.EscapeString(id), html.EscapeString(id))
+		}
+		sb.WriteString(`</tbody></table>`)
+		if len(records) == 0 {
+			sb.WriteString(`<p class="lv-admin-empty">Nothing found.</p>`)
+		}
+		sb.WriteString(`</div></main></div>`)
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// editView creates or edits a record.
+type editView struct {
+	core.BaseComponent
+	site   *Site
+	model  *Model
+	id     string // empty when creating
+	record map[string]any
+	cs     *forms.Changeset
+	err    error
+}
+
+func (v *editView) Name() string { return "admin-edit" }
+
+func (v *editView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	m, ok := v.site.Model(params.Get("model"))
+	if !ok {
+		return router.HaltRedirect(v.site.path())
+	}
+	v.model = m
+	v.id = params.Get("id")
+	v.record = make(map[string]any)
+	if v.id == "" {
+		for _, f := range m.Fields {
+			if f.Default != nil {
+				v.record[f.Name] = f.Default
+			}
+		}
+		return nil
+	}
+	record, err := m.Repo.Get(v.id)
+	if errors.Is(err, forms.ErrNotFound) {
+		return router.HaltRedirect(v.site.path(m.Name))
+	}
+	v.record = record
+	return err
+}
+
+func (v *editView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if event != "save" {
+		return nil
+	}
+	params := castParams(v.model.Fields, payload)
+	v.cs = v.model.Changeset(v.record, params)
+	if v.id == "" {
+		_, v.err = v.model.Repo.Insert(v.cs)
+	} else {
+		v.err = v.model.Repo.Update(v.id, v.cs)
+	}
+	if v.err != nil {
+		if !v.cs.Valid {
+			v.err = nil // shown next to the fields
+		}
+		return nil
+	}
+	if socket := v.Socket(); socket != nil {
+		return socket.PushNavigate(v.site.path(v.model.Name))
+	}
+	return nil
+}
+
+// value returns what a field shows: the submitted value after a failed
+// save, otherwise the record's.
+func (v *editView) value(name string) any {
+	if v.cs != nil {
+		return v.cs.GetField(name)
+	}
+	return v.record[name]
+}
+
+func (v *editView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		m := v.model
+		title := "New " + strings.TrimSuffix(m.Label, "s")
+		if v.id != "" {
+			title = fmt.Sprintf("%s %s", strings.TrimSuffix(m.Label, "s"), v.id)
+		}
+
+		var sb strings.Builder
+		v.site.header(&sb, m.Name)
+		fmt.Fprintf(&sb, `<h1>%s</h1><form class="lv-admin-form" lv-submit="save" data-slot="admin-form">`, html.EscapeString(title))
+		if v.err != nil {
+			fmt.Fprintf(&sb, `<p class="lv-admin-error" role="alert">%s</p>`, html.EscapeString(v.err.Error()))
+		}
+		if v.cs != nil && !v.cs.Valid {
+			// Errors on fields without an input, such as a stale lock version
+			var other []string
+			for field, errs := range v.cs.Errors {
+				if f := m.field(field); f == nil || f.Type == forms.FieldHidden {
+					other = append(other, strings.Join(errs, ", "))
+				}
+			}
+			sort.Strings(other)
+			if len(other) > 0 {
+				fmt.Fprintf(&sb, `<p class="lv-admin-error" role="alert">%s</p>`, html.EscapeString(strings.Join(other, "; ")))
+			}
+		}
+		for _, f := range m.Fields {
+			errMsg := ""
+			if v.cs != nil {
+				errMsg = v.cs.FirstError(f.Name)
+			}
+			writeField(&sb, f, v.value(f.Name), errMsg)
+		}
+		fmt.Fprintf(&sb, `<div class="lv-admin-actions"><button type="submit" class="lv-admin-button">Save</button><a href="%s">Cancel</a></div></form></main></div>`,
+			html.EscapeString(v.site.path(m.Name)))
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// header opens the layout: the site title and the model navigation, with
+// current highlighted.
+func (s *Site) header(sb *strings.Builder, current string) {
+	fmt.Fprintf(sb, `<div class="lv-admin" data-live-view="admin"><nav class="lv-admin-nav"><a class="lv-admin-title" href="%s">%s</a>`,
+		html.EscapeString(s.path()), html.EscapeString(s.Title))
+	for _, m := range s.Models() {
+		attr := ""
+		if m.Name == current {
+			attr = ` aria-current="page"`
+		}
+		fmt.Fprintf(sb, `<a href="%s"%s>%s</a>`, html.EscapeString(s.path(m.Name)), attr, html.EscapeString(m.Label))
+	}
+	attr := ""
+	if current == sessionsPage {
+		attr = ` aria-current="page"`
+	}
+	fmt.Fprintf(sb, `<a class="lv-admin-sessions" href="%s"%s>Sessions</a>`, html.EscapeString(s.path(sessionsPage)), attr)
+	sb.WriteString(`</nav><main>`)
+}
+
+func (m *Model) field(name string) *forms.Field {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (m *Model) label(name string) string {
+	if f := m.field(name); f != nil && f.Label != "" {
+		return f.Label
+	}
+	return name
+}
+
+// castParams converts submitted strings to the fields' types. Unchecked
+// checkboxes are not submitted, so they become false.
+func castParams(fields []forms.Field, payload map[string]any) map[string]any {
+	params := make(map[string]any, len(payload))
+	for k, val := range payload {
+		params[k] = val
+	}
+	for _, f := range fields {
+		s, _ := params[f.Name].(string)
+		switch f.Type {
+		case forms.FieldCheckbox:
+			params[f.Name] = s != "" && s != "false"
+		case forms.FieldDate:
+			if t, err := time.Parse(dateLayout, s); err == nil {
+				params[f.Name] = t
+			}
+		case forms.FieldNumber, forms.FieldRange:
+			if n, err := strconv.Atoi(s); err == nil {
+				params[f.Name] = n
+			} else if x, err := strconv.ParseFloat(s, 64); err == nil {
+				params[f.Name] = x
+			}
+		}
+	}
+	return params
+}
+
+// writeField renders a labeled input for f.
+func writeField(sb *strings.Builder, f forms.Field, value any, errMsg string) {
+	name := html.EscapeString(f.Name)
+	id := "admin-" + name
+	attrs := ""
+	if f.Required {
+		attrs += " required"
+	}
+	if f.Disabled {
+		attrs += " disabled"
+	}
+	if f.ReadOnly {
+		attrs += " readonly"
+	}
+	if f.Placeholder != "" {
+		attrs += fmt.Sprintf(` placeholder="%s"`, html.EscapeString(f.Placeholder))
+	}
+	if errMsg != "" {
+		attrs += fmt.Sprintf(` aria-invalid="true" aria-describedby="%s-error"`, id)
+	}
+
+	if f.Type == forms.FieldHidden {
+		fmt.Fprintf(sb, `<input type="hidden" name="%s" value="%s">`, name, html.EscapeString(display(value)))
+		return
+	}
+
+	fmt.Fprintf(sb, `<div class="lv-admin-field"><label for="%s">%s</label>`, id, html.EscapeString(f.Label))
+	switch f.Type {
+	case forms.FieldTextarea:
+		fmt.Fprintf(sb, `<textarea id="%s" name="%s"%s>%s</textarea>`, id, name, attrs, html.EscapeString(display(value)))
+	case forms.FieldSelect:
+		fmt.Fprintf(sb, `<select id="%s" name="%s"%s>`, id, name, attrs)
+		for _, opt := range f.Options {
+			selected := ""
+			if opt.Value == display(value) {
+				selected = " selected"
+			}
+			fmt.Fprintf(sb, `<option value="%s"%s>%s</option>`, html.EscapeString(opt.Value), selected, html.EscapeString(opt.Label))
+		}
+		sb.WriteString(`</select>`)
+	case forms.FieldCheckbox:
+		checked := ""
+		if b, _ := value.(bool); b {
+			checked = " checked"
+		}
+		fmt.Fprintf(sb, `<input type="checkbox" id="%s" name="%s" value="true"%s%s>`, id, name, checked, attrs)
+	default:
+		fmt.Fprintf(sb, `<input type="%s" id="%s" name="%s" value="%s"%s>`, f.Type, id, name, html.EscapeString(display(value)), attrs)
+	}
+	if errMsg != "" {
+		fmt.Fprintf(sb, `<p class="lv-admin-field-error" id="%s-error">%s</p>`, id, html.EscapeString(errMsg))
+	}
+	if f.Help != "" {
+		fmt.Fprintf(sb, `<p class="lv-admin-help">%s</p>`, html.EscapeString(f.Help))
+	}
+	sb.WriteString(`</div>`)
+}
+
+// dateLayout is the value format of date inputs.
+const dateLayout = "2006-01-02"
+
+func display(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(dateLayout)
+	}
+	return fmt.Sprint(v)
+}
+
+// CSS is a default style for the admin area.
+const CSS = `.lv-admin{display:flex;min-height:100vh;font-family:system-ui,sans-serif;color:#1e293b}
+.lv-admin-nav{display:flex;flex-direction:column;gap:0.25rem;width:14rem;padding:1rem;background:#0f172a}
+.lv-admin-nav a{color:#cbd5e1;text-decoration:none;padding:0.375rem 0.5rem;border-radius:0.375rem}
+.lv-admin-nav a[aria-current]{color:#fff;background:#1e293b}
+.lv-admin-nav .lv-admin-title{color:#fff;font-weight:600;margin-bottom:0.5rem}
+.lv-admin main{flex:1;padding:1.5rem 2rem}
+.lv-admin-toolbar{display:flex;align-items:center;gap:1rem}
+.lv-admin-toolbar h1{flex:1}
+.lv-admin-button{padding:0.5rem 1rem;border:0;border-radius:0.375rem;color:#fff;background:#4f46e5;text-decoration:none;cursor:pointer}
+.lv-admin-table{width:100%;border-collapse:collapse}
+.lv-admin-table th,.lv-admin-table td{padding:0.5rem;border-bottom:1px solid #e2e8f0;text-align:left}
+.lv-admin-flash{padding:0.5rem 1rem;border-radius:0.375rem;background:#ecfdf5}
+.lv-admin-empty,.lv-admin-help{color:#64748b}
+.lv-admin-form{display:flex;flex-direction:column;gap:1rem;max-width:36rem}
+.lv-admin-field{display:flex;flex-direction:column;gap:0.25rem}
+.lv-admin-field input:not([type=checkbox]),.lv-admin-field textarea,.lv-admin-field select{padding:0.5rem;border:1px solid #cbd5e1;border-radius:0.375rem}
+.lv-admin-error,.lv-admin-field-error{color:#b91c1c}
+.lv-admin-actions{display:flex;align-items:center;gap:1rem}`
@@ -9,6 +9,8 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/redact"
 )
 
 // Event types for security auditing.
@@ -62,10 +64,12 @@ type Logger interface {
 	Close() error
 }
 
-// JSONLogger logs security events as JSON to an io.Writer.
+// JSONLogger logs security events as JSON to an io.Writer. Event details
+// and paths are redacted with redact.Default before they are written.
 type JSONLogger struct {
 	encoder *json.Encoder
 	writer  io.Writer
+	policy  *redact.Policy
 	mu      sync.Mutex
 }
 
@@ -74,9 +78,19 @@ func NewJSONLogger(w io.Writer) *JSONLogger {
 	return &JSONLogger{
 		encoder: json.NewEncoder(w),
 		writer:  w,
+		policy:  redact.Default,
 	}
 }
 
+// SetRedaction sets the policy applied to event details. nil disables
+// redaction.
+func (l *JSONLogger) SetRedaction(p *redact.Policy) *JSONLogger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.policy = p
+	return l
+}
+
 // NewFileLogger creates a logger that writes to a file.
 func NewFileLogger(path string) (*JSONLogger, error) {
 	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
@@ -99,6 +113,8 @@ func (l *JSONLogger) Log(event SecurityEvent) {
 	if event.Timestamp.IsZero() {
 		event.Timestamp = time.Now()
 	}
+	event.Details = l.policy.Map(event.Details)
+	event.Path = l.policy.URL(event.Path)
 
 	if err := l.encoder.Encode(event); err != nil {
 		log.Printf("audit: failed to encode event: %v", err)
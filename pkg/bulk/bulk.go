@@ -0,0 +1,271 @@
+// Package bulk adds multi-select and bulk actions to table and list
+// components: a Selection tracks checked rows, a Toolbar renders the actions
+// for the current selection, and Run executes an action in the background,
+// reporting Progress and a final Result to the component's HandleInfo.
+//
+// Usage:
+//
+//	// Mount
+//	c.sel = bulk.NewSelection()
+//	c.toolbar = bulk.NewToolbar(c.sel, bulk.Action{Name: "archive", Label: "Archive", Fn: c.archive})
+//
+//	// HandleEvent
+//	if c.sel.HandleEvent(event, payload, c.visibleIDs()) {
+//		return nil
+//	}
+//	if action, ok := c.toolbar.Action(event, payload); ok {
+//		c.job = bulk.Run(ctx, c.Socket(), action, c.sel.IDs())
+//		return nil
+//	}
+//
+//	// HandleInfo
+//	switch m := msg.(type) {
+//	case bulk.Progress:
+//		c.progress = m
+//	case bulk.Result:
+//		c.result = m
+//		c.sel.Clear()
+//	}
+//
+//	// Render
+//	c.sel.HeaderCheckbox(ids) ... c.sel.Checkbox(id) ... c.toolbar.Render()
+package bulk
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+)
+
+// Client events handled by Selection and Toolbar.
+const (
+	EventToggle    = "bulk:toggle"     // {"id": "42"}
+	EventToggleAll = "bulk:toggle_all" // selects or clears all visible rows
+	EventClear     = "bulk:clear"
+	EventRun       = "bulk:run" // {"action": "archive"}
+)
+
+// Selection is the set of selected row IDs, kept in selection order.
+// It is safe for concurrent use.
+type Selection struct {
+	mu    sync.RWMutex
+	ids   map[string]bool
+	order []string
+}
+
+// NewSelection creates an empty selection.
+func NewSelection() *Selection {
+	return &Selection{ids: make(map[string]bool)}
+}
+
+// Select adds ids to the selection.
+func (s *Selection) Select(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if id == "" || s.ids[id] {
+			continue
+		}
+		s.ids[id] = true
+		s.order = append(s.order, id)
+	}
+}
+
+// Deselect removes ids from the selection.
+func (s *Selection) Deselect(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.ids, id)
+	}
+	s.compact()
+}
+
+// Toggle flips the selection state of id.
+func (s *Selection) Toggle(id string) {
+	if s.IsSelected(id) {
+		s.Deselect(id)
+	} else {
+		s.Select(id)
+	}
+}
+
+// ToggleAll selects every visible row, or clears them if all are selected.
+func (s *Selection) ToggleAll(visible []string) {
+	if s.AllSelected(visible) {
+		s.Deselect(visible...)
+	} else {
+		s.Select(visible...)
+	}
+}
+
+// Clear empties the selection.
+func (s *Selection) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = make(map[string]bool)
+	s.order = nil
+}
+
+// Retain drops selected IDs not in ids, e.g. after rows were deleted or the
+// list was filtered.
+func (s *Selection) Retain(ids []string) {
+	keep := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.ids {
+		if !keep[id] {
+			delete(s.ids, id)
+		}
+	}
+	s.compact()
+}
+
+// IsSelected reports whether id is selected.
+func (s *Selection) IsSelected(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ids[id]
+}
+
+// AllSelected reports whether every visible row is selected.
+func (s *Selection) AllSelected(visible []string) bool {
+	if len(visible) == 0 {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, id := range visible {
+		if !s.ids[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// IDs returns the selected IDs in selection order.
+func (s *Selection) IDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.order...)
+}
+
+// Count returns the number of selected rows.
+func (s *Selection) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.order)
+}
+
+// HandleEvent applies selection events and reports whether it consumed the
+// event. visible lists the rows currently shown, used by EventToggleAll.
+func (s *Selection) HandleEvent(event string, payload map[string]any, visible []string) bool {
+	switch event {
+	case EventToggle:
+		id, _ := payload["id"].(string)
+		s.Toggle(id)
+	case EventToggleAll:
+		s.ToggleAll(visible)
+	case EventClear:
+		s.Clear()
+	default:
+		return false
+	}
+	return true
+}
+
+// Checkbox renders the row checkbox for id.
+func (s *Selection) Checkbox(id string) string {
+	checked := ""
+	if s.IsSelected(id) {
+		checked = " checked"
+	}
+	return fmt.Sprintf(`<input type="checkbox" class="lv-bulk-check" aria-label="Select row" lv-click="%s" lv-value-id="%s"%s>`,
+		EventToggle, html.EscapeString(id), checked)
+}
+
+// HeaderCheckbox renders the "select all" checkbox for the visible rows.
+func (s *Selection) HeaderCheckbox(visible []string) string {
+	checked := ""
+	if s.AllSelected(visible) {
+		checked = " checked"
+	}
+	return fmt.Sprintf(`<input type="checkbox" class="lv-bulk-check" aria-label="Select all" lv-click="%s"%s>`,
+		EventToggleAll, checked)
+}
+
+// compact rebuilds order from ids. Caller holds s.mu.
+func (s *Selection) compact() {
+	order := s.order[:0]
+	for _, id := range s.order {
+		if s.ids[id] {
+			order = append(order, id)
+		}
+	}
+	s.order = order
+}
+
+// Toolbar renders the bulk actions available for a selection.
+type Toolbar struct {
+	selection *Selection
+	actions   []Action
+}
+
+// NewToolbar creates a toolbar for selection.
+func NewToolbar(selection *Selection, actions ...Action) *Toolbar {
+	return &Toolbar{selection: selection, actions: actions}
+}
+
+// Action returns the action requested by an EventRun event.
+func (t *Toolbar) Action(event string, payload map[string]any) (Action, bool) {
+	if event != EventRun {
+		return Action{}, false
+	}
+	name, _ := payload["action"].(string)
+	for _, a := range t.actions {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Action{}, false
+}
+
+// Render returns the toolbar HTML. It renders an empty placeholder while
+// nothing is selected so the slot keeps a stable position.
+func (t *Toolbar) Render() string {
+	count := t.selection.Count()
+	if count == 0 {
+		return `<div class="lv-bulk-toolbar" data-slot="bulk-toolbar" hidden></div>`
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<div class="lv-bulk-toolbar" data-slot="bulk-toolbar" role="toolbar" aria-label="Bulk actions">`)
+	fmt.Fprintf(&sb, `<span class="lv-bulk-count">%d selected</span>`, count)
+	for _, a := range t.actions {
+		class := "lv-bulk-action"
+		if a.Destructive {
+			class += " lv-bulk-danger"
+		}
+		confirm := ""
+		if a.Confirm != "" {
+			confirm = fmt.Sprintf(` lv-confirm="%s"`, html.EscapeString(strings.ReplaceAll(a.Confirm, "{count}", fmt.Sprint(count))))
+		}
+		fmt.Fprintf(&sb, `<button type="button" class="%s" lv-click="%s" lv-value-action="%s"%s>%s</button>`,
+			class, EventRun, html.EscapeString(a.Name), confirm, html.EscapeString(a.label()))
+	}
+	fmt.Fprintf(&sb, `<button type="button" class="lv-bulk-clear" lv-click="%s">Clear</button></div>`, EventClear)
+	return sb.String()
+}
+
+// CSS is a default style for the toolbar and progress bar.
+const CSS = `.lv-bulk-toolbar{display:flex;align-items:center;gap:0.5rem;padding:0.5rem 0.75rem;border-radius:0.5rem;background:#EEF2FF}
+.lv-bulk-count{font-weight:600;margin-inline-end:auto}
+.lv-bulk-action,.lv-bulk-clear{padding:0.375rem 0.75rem;border:1px solid #C7D2FE;border-radius:0.375rem;background:#fff;cursor:pointer}
+.lv-bulk-danger{color:#B91C1C;border-color:#FCA5A5}
+.lv-bulk-progress{width:100%}
+.lv-bulk-result[data-failed]{color:#B91C1C}`
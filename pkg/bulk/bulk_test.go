@@ -0,0 +1,112 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSelection_Events(t *testing.T) {
+	s := NewSelection()
+	visible := []string{"1", "2", "3"}
+
+	s.HandleEvent(EventToggle, map[string]any{"id": "2"}, visible)
+	s.HandleEvent(EventToggle, map[string]any{"id": "1"}, visible)
+	if got := s.IDs(); len(got) != 2 || got[0] != "2" || got[1] != "1" {
+		t.Fatalf("Expected [2 1] in selection order, got %v", got)
+	}
+
+	s.HandleEvent(EventToggleAll, nil, visible)
+	if !s.AllSelected(visible) || s.Count() != 3 {
+		t.Fatalf("Expected all selected, got %v", s.IDs())
+	}
+	s.HandleEvent(EventToggleAll, nil, visible)
+	if s.Count() != 0 {
+		t.Fatalf("Expected toggle all to clear, got %v", s.IDs())
+	}
+
+	s.Select("1", "2", "3")
+	s.Retain([]string{"3"})
+	if got := s.IDs(); len(got) != 1 || got[0] != "3" {
+		t.Errorf("Expected Retain to keep [3], got %v", got)
+	}
+	if s.HandleEvent("other", nil, visible) {
+		t.Error("Expected unrelated events to be ignored")
+	}
+}
+
+func TestToolbar_Render(t *testing.T) {
+	s := NewSelection()
+	tb := NewToolbar(s, Action{Name: "delete", Label: "Delete", Confirm: "Delete {count} items?", Destructive: true})
+
+	if out := tb.Render(); !strings.Contains(out, "hidden") {
+		t.Errorf("Expected hidden toolbar with empty selection: %s", out)
+	}
+
+	s.Select("a", "b")
+	out := tb.Render()
+	for _, want := range []string{"2 selected", `lv-value-action="delete"`, `lv-confirm="Delete 2 items?"`, "lv-bulk-danger"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %s", want, out)
+		}
+	}
+
+	if a, ok := tb.Action(EventRun, map[string]any{"action": "delete"}); !ok || a.Name != "delete" {
+		t.Errorf("Expected delete action, got %+v %v", a, ok)
+	}
+	if _, ok := tb.Action(EventRun, map[string]any{"action": "nope"}); ok {
+		t.Error("Expected unknown action to be rejected")
+	}
+}
+
+func TestRun_PartialFailure(t *testing.T) {
+	action := Action{Name: "archive", Fn: func(ctx context.Context, id string) error {
+		switch id {
+		case "2":
+			return errors.New("locked")
+		case "3":
+			panic("boom")
+		}
+		return nil
+	}}
+
+	result := Run(context.Background(), nil, action, []string{"1", "2", "3", "4"}).Wait()
+	if len(result.Succeeded) != 2 || len(result.Failed) != 2 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if got := result.FailedIDs(); got[0] != "2" || got[1] != "3" {
+		t.Errorf("Unexpected failed IDs: %v", got)
+	}
+	if got := result.Summary(); got != "archive: 2 of 4 items succeeded, 2 failed" {
+		t.Errorf("Unexpected summary: %q", got)
+	}
+	if out := result.Render(); !strings.Contains(out, "locked") || !strings.Contains(out, "data-failed") {
+		t.Errorf("Expected failures in render: %s", out)
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	action := Action{Name: "slow", Fn: func(ctx context.Context, id string) error {
+		if id == "1" {
+			close(started)
+			<-release
+		}
+		return nil
+	}}
+
+	job := Run(context.Background(), nil, action, []string{"1", "2", "3"})
+	<-started
+	job.Cancel()
+	close(release)
+
+	result := job.Wait()
+	if !result.Canceled || len(result.Succeeded) != 1 || len(result.Skipped) != 2 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if result.OK() {
+		t.Error("Expected cancelled result not to be OK")
+	}
+}
@@ -0,0 +1,248 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Action is a bulk operation applied to each selected ID.
+type Action struct {
+	// Name identifies the action in EventRun payloads.
+	Name string
+	// Label is the button text; defaults to Name.
+	Label string
+	// Confirm, if set, asks the user before running. "{count}" is replaced
+	// with the number of selected rows.
+	Confirm string
+	// Destructive styles the button as dangerous.
+	Destructive bool
+	// Fn processes one ID. Errors are collected in the Result; the run
+	// continues with the next ID.
+	Fn func(ctx context.Context, id string) error
+}
+
+func (a Action) label() string {
+	if a.Label != "" {
+		return a.Label
+	}
+	return a.Name
+}
+
+// Progress is delivered to HandleInfo while a job runs.
+type Progress struct {
+	JobID  string
+	Action string
+	Done   int
+	Failed int
+	Total  int
+}
+
+// Percent returns the completion percentage (0-100).
+func (p Progress) Percent() int {
+	if p.Total == 0 {
+		return 100
+	}
+	return p.Done * 100 / p.Total
+}
+
+// Render returns an accessible progress bar.
+func (p Progress) Render() string {
+	return fmt.Sprintf(`<progress class="lv-bulk-progress" data-slot="bulk-progress" max="%d" value="%d" aria-label="%s">%d%%</progress>`,
+		p.Total, p.Done, html.EscapeString(p.Action), p.Percent())
+}
+
+// Failure is an ID an action could not process.
+type Failure struct {
+	ID  string
+	Err error
+}
+
+// Result is delivered to HandleInfo when a job finishes.
+type Result struct {
+	JobID     string
+	Action    string
+	Succeeded []string
+	Failed    []Failure
+	// Skipped lists IDs not attempted because the job was cancelled.
+	Skipped  []string
+	Canceled bool
+}
+
+// Total returns the number of IDs the job was started with.
+func (r Result) Total() int {
+	return len(r.Succeeded) + len(r.Failed) + len(r.Skipped)
+}
+
+// OK reports whether every ID was processed successfully.
+func (r Result) OK() bool {
+	return len(r.Failed) == 0 && len(r.Skipped) == 0
+}
+
+// FailedIDs returns the IDs that failed, e.g. to keep them selected for a retry.
+func (r Result) FailedIDs() []string {
+	ids := make([]string, len(r.Failed))
+	for i, f := range r.Failed {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// Summary formats the result for display: "archive: 10 items succeeded" or
+// "archive: 8 of 10 items succeeded, 2 failed".
+func (r Result) Summary() string {
+	total := r.Total()
+	items := "items"
+	if total == 1 {
+		items = "item"
+	}
+	if r.OK() {
+		return fmt.Sprintf("%s: %d %s succeeded", r.Action, total, items)
+	}
+
+	s := fmt.Sprintf("%s: %d of %d %s succeeded", r.Action, len(r.Succeeded), total, items)
+	if len(r.Failed) > 0 {
+		s += fmt.Sprintf(", %d failed", len(r.Failed))
+	}
+	if r.Canceled {
+		s += fmt.Sprintf(", %d skipped (cancelled)", len(r.Skipped))
+	}
+	return s
+}
+
+// Render returns the summary and, for partial failures, the failing IDs and
+// their errors.
+func (r Result) Render() string {
+	var sb strings.Builder
+	if r.OK() {
+		sb.WriteString(`<div class="lv-bulk-result" data-slot="bulk-result" role="status">`)
+	} else {
+		sb.WriteString(`<div class="lv-bulk-result" data-slot="bulk-result" role="alert" data-failed>`)
+	}
+	sb.WriteString(html.EscapeString(r.Summary()))
+	if len(r.Failed) > 0 {
+		sb.WriteString(`<ul>`)
+		for _, f := range r.Failed {
+			fmt.Fprintf(&sb, `<li>%s: %s</li>`, html.EscapeString(f.ID), html.EscapeString(f.Err.Error()))
+		}
+		sb.WriteString(`</ul>`)
+	}
+	sb.WriteString(`</div>`)
+	return sb.String()
+}
+
+// ProgressInterval throttles Progress messages so large jobs do not flood
+// the socket with re-renders.
+var ProgressInterval = 100 * time.Millisecond
+
+// Job is a running bulk action.
+type Job struct {
+	ID     string
+	cancel context.CancelFunc
+	done   chan struct{}
+	result Result
+}
+
+var jobSeq atomic.Int64
+
+func nextJobID() string {
+	return fmt.Sprintf("bulk-%d", jobSeq.Add(1))
+}
+
+// Run starts action over ids in the background. Progress and the final Result
+// are delivered to socket's HandleInfo; a nil socket runs silently (use Wait).
+// The job stops early if ctx is cancelled or Cancel is called.
+func Run(ctx context.Context, socket *core.Socket, action Action, ids []string) *Job {
+	ctx, cancel := context.WithCancel(ctx)
+	job := &Job{
+		ID:     nextJobID(),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	ids = append([]string(nil), ids...)
+
+	go func() {
+		defer close(job.done)
+		defer cancel()
+		job.result = job.run(ctx, socket, action, ids)
+		if socket != nil {
+			deliver(socket, job.result)
+		}
+	}()
+	return job
+}
+
+// Cancel stops the job after the current ID.
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+// Wait blocks until the job finishes and returns its result.
+func (j *Job) Wait() Result {
+	<-j.done
+	return j.result
+}
+
+// Done is closed when the job finishes.
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+func (j *Job) run(ctx context.Context, socket *core.Socket, action Action, ids []string) Result {
+	result := Result{JobID: j.ID, Action: action.Name}
+	progress := Progress{JobID: j.ID, Action: action.Name, Total: len(ids)}
+	last := time.Time{}
+
+	for i, id := range ids {
+		if ctx.Err() != nil {
+			result.Canceled = true
+			result.Skipped = append(result.Skipped, ids[i:]...)
+			break
+		}
+
+		if err := call(ctx, action, id); err != nil {
+			result.Failed = append(result.Failed, Failure{ID: id, Err: err})
+			progress.Failed++
+		} else {
+			result.Succeeded = append(result.Succeeded, id)
+		}
+		progress.Done++
+
+		if socket != nil && time.Since(last) >= ProgressInterval {
+			last = time.Now()
+			socket.SendInfo(progress) // dropped if the queue is full; the next one catches up
+		}
+	}
+	return result
+}
+
+// call runs action.Fn, converting panics into failures.
+func call(ctx context.Context, action Action, id string) (err error) {
+	if action.Fn == nil {
+		return errors.New("bulk: action has no Fn")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return action.Fn(ctx, id)
+}
+
+// deliver sends the result, retrying while the info queue is full so the
+// component always sees the final state.
+func deliver(socket *core.Socket, result Result) {
+	for attempt := 0; attempt < 50; attempt++ {
+		err := socket.SendInfo(result)
+		if !errors.Is(err, core.ErrInfoQueueFull) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
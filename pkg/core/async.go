@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// asyncLoads numbers AssignAsync calls, so a load superseded by a later
+// call for the same key does not overwrite its result.
+var asyncLoads atomic.Uint64
+
+// AsyncResult is the state of an assign loaded in the background with
+// AssignAsync. Templates render a placeholder while it is Loading (see the
+// skeleton package), then the Value or the Err.
+type AsyncResult struct {
+	Loading bool
+	Value   any
+	Err     error
+
+	load uint64
+}
+
+// Ok reports whether the load finished without error.
+func (r AsyncResult) Ok() bool {
+	return !r.Loading && r.Err == nil
+}
+
+// GetAsync returns the AsyncResult assigned to key. Keys never assigned
+// are reported as loading.
+func (a *Assigns) GetAsync(key string) AsyncResult {
+	if r, ok := a.Get(key).(AsyncResult); ok {
+		return r
+	}
+	return AsyncResult{Loading: true}
+}
+
+// AssignAsync assigns key as loading and runs load in a goroutine, so Mount
+// returns and the page renders its placeholder right away. When load
+// returns, key holds its result and the component is re-rendered, which
+// sends the slot that held the placeholder in a diff.
+//
+// Calling it again for key before the load finished supersedes the
+// earlier load. load's context is cancelled when the socket closes.
+// Without a socket
+// (the static render of the page) load does not run: the connected mount
+// loads the data.
+func (bc *BaseComponent) AssignAsync(key string, load func(ctx context.Context) (any, error)) {
+	id := asyncLoads.Add(1)
+	assigns := bc.Assigns()
+	assigns.Set(key, AsyncResult{Loading: true, load: id})
+
+	socket := bc.socket
+	if socket == nil || !socket.IsConnected() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	socket.OnClose(cancel)
+
+	go func() {
+		defer cancel()
+		value, err := load(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if assigns.resolveAsync(key, id, AsyncResult{Value: value, Err: err}) {
+			socket.PushRender()
+		}
+	}()
+}
+
+// resolveAsync stores the result of load id for key, unless a later
+// AssignAsync or a Set replaced its loading state.
+func (a *Assigns) resolveAsync(key string, id uint64, result AsyncResult) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if current, ok := a.data[key].(AsyncResult); !ok || current.load != id {
+		return false
+	}
+	result.load = id
+	a.data[key] = result
+	a.tracker.Track(key, result)
+	return true
+}
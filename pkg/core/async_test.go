@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAssignAsync_ResolvesAndRenders(t *testing.T) {
+	var c BaseComponent
+	c.SetSocket(NewSocket("s", NewMockTransport()))
+
+	release := make(chan struct{})
+	c.AssignAsync("stats", func(ctx context.Context) (any, error) {
+		<-release
+		return 42, nil
+	})
+	if r := c.Assigns().GetAsync("stats"); !r.Loading || r.Ok() {
+		t.Fatalf("before the load: %+v", r)
+	}
+
+	close(release)
+	select {
+	case info := <-c.Socket().Info():
+		if _, ok := info.(RenderRequest); !ok {
+			t.Fatalf("info = %T, want a RenderRequest", info)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no render after the load")
+	}
+	if r := c.Assigns().GetAsync("stats"); !r.Ok() || r.Value != 42 {
+		t.Errorf("after the load: %+v", r)
+	}
+}
+
+func TestAssignAsync_Superseded(t *testing.T) {
+	var c BaseComponent
+	c.SetSocket(NewSocket("s", NewMockTransport()))
+
+	first := make(chan struct{})
+	done := make(chan struct{})
+	c.AssignAsync("q", func(ctx context.Context) (any, error) {
+		defer close(done)
+		<-first
+		return "stale", nil
+	})
+	c.AssignAsync("q", func(ctx context.Context) (any, error) {
+		return nil, errors.New("boom")
+	})
+	<-c.Socket().Info()
+	close(first)
+	<-done
+
+	if r := c.Assigns().GetAsync("q"); r.Err == nil || r.Value != nil {
+		t.Errorf("result = %+v, want the second load's error", r)
+	}
+}
+
+func TestAssignAsync_WithoutSocket(t *testing.T) {
+	var c BaseComponent
+	c.AssignAsync("stats", func(ctx context.Context) (any, error) {
+		t.Error("loaded during the static render")
+		return nil, nil
+	})
+	if r := c.Assigns().GetAsync("stats"); !r.Loading {
+		t.Errorf("result = %+v", r)
+	}
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Badge events exchanged with the client.
+const (
+	// BadgeEvent updates the favicon badge and title prefix ("(3) Chat").
+	BadgeEvent = "lv:badge"
+	// FocusEvent is sent by the client when the page regains focus while
+	// a badge is showing (or receives a badge while already focused).
+	FocusEvent = "lv:focus"
+)
+
+// PushBadge shows count on the favicon and prefixes title with "(count) ".
+// A count of zero restores the original favicon and title.
+func (s *Socket) PushBadge(count int, title string) error {
+	payload := map[string]any{"count": count}
+	if title != "" {
+		payload["title"] = BadgeTitle(count, title)
+	}
+	return s.Push(BadgeEvent, payload)
+}
+
+// BadgeTitle formats a notification title: "(3) Chat", or title unchanged
+// when count is zero.
+func BadgeTitle(count int, title string) string {
+	if count <= 0 {
+		return title
+	}
+	if count > 99 {
+		return "(99+) " + title
+	}
+	return fmt.Sprintf("(%d) %s", count, title)
+}
+
+// Badge tracks an unread count shown in the favicon and document title while
+// the user is looking elsewhere. The client clears it when the page regains
+// focus and reports back with FocusEvent, which HandleEvent consumes:
+//
+//	func (c *Chat) Mount(ctx context.Context, params core.Params, session core.Session) error {
+//	    c.unread = core.NewBadge(c.Socket(), "Chat")
+//	    ...
+//	}
+//
+//	func (c *Chat) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//	    if c.unread.HandleEvent(event, payload) {
+//	        return nil
+//	    }
+//	    ...
+//	}
+//
+// A Badge with a nil socket (the initial HTTP render) only keeps the count.
+type Badge struct {
+	mu     sync.Mutex
+	socket *Socket
+	title  string
+	count  int
+}
+
+// NewBadge creates a badge that decorates title on the client.
+func NewBadge(socket *Socket, title string) *Badge {
+	return &Badge{socket: socket, title: title}
+}
+
+// Increment adds one to the count and pushes it.
+func (b *Badge) Increment() error {
+	return b.Add(1)
+}
+
+// Add adds n to the count and pushes it.
+func (b *Badge) Add(n int) error {
+	b.mu.Lock()
+	b.count += n
+	if b.count < 0 {
+		b.count = 0
+	}
+	count := b.count
+	b.mu.Unlock()
+	return b.push(count)
+}
+
+// Set replaces the count and pushes it.
+func (b *Badge) Set(count int) error {
+	if count < 0 {
+		count = 0
+	}
+	b.mu.Lock()
+	b.count = count
+	b.mu.Unlock()
+	return b.push(count)
+}
+
+// Reset clears the badge.
+func (b *Badge) Reset() error {
+	return b.Set(0)
+}
+
+// Count returns the current count.
+func (b *Badge) Count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.count
+}
+
+// HandleEvent resets the badge on FocusEvent and reports whether it
+// consumed the event.
+func (b *Badge) HandleEvent(event string, payload map[string]any) bool {
+	if event != FocusEvent {
+		return false
+	}
+	b.mu.Lock()
+	b.count = 0
+	b.mu.Unlock()
+	return true
+}
+
+func (b *Badge) push(count int) error {
+	if b.socket == nil {
+		return nil
+	}
+	return b.socket.PushBadge(count, b.title)
+}
@@ -0,0 +1,52 @@
+package core
+
+import "testing"
+
+func TestBadge_PushesAndResetsOnFocus(t *testing.T) {
+	transport := NewMockTransport()
+	b := NewBadge(NewSocket("s1", transport), "Chat")
+
+	b.Increment()
+	b.Increment()
+	if b.Count() != 2 {
+		t.Fatalf("Count() = %d, want 2", b.Count())
+	}
+
+	msgs := transport.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 pushes, got %d", len(msgs))
+	}
+	last := msgs[1]
+	if last.Event != BadgeEvent || last.Payload["count"] != 2 || last.Payload["title"] != "(2) Chat" {
+		t.Errorf("unexpected push: %+v", last)
+	}
+
+	if b.HandleEvent("other", nil) {
+		t.Error("HandleEvent should ignore unrelated events")
+	}
+	if !b.HandleEvent(FocusEvent, map[string]any{"focused": true}) {
+		t.Error("HandleEvent should consume FocusEvent")
+	}
+	if b.Count() != 0 {
+		t.Errorf("Count() after focus = %d, want 0", b.Count())
+	}
+}
+
+func TestBadge_NilSocket(t *testing.T) {
+	b := NewBadge(nil, "Chat")
+	if err := b.Set(5); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if b.Count() != 5 {
+		t.Errorf("Count() = %d, want 5", b.Count())
+	}
+}
+
+func TestBadgeTitle(t *testing.T) {
+	tests := map[int]string{0: "Chat", 3: "(3) Chat", 150: "(99+) Chat"}
+	for n, want := range tests {
+		if got := BadgeTitle(n, "Chat"); got != want {
+			t.Errorf("BadgeTitle(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
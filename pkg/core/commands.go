@@ -0,0 +1,19 @@
+package core
+
+// ExecJS queues client commands, as built by package js, to run once the
+// next diff sent to the socket has been applied. The router sends the diff
+// even when the render did not change.
+func (s *Socket) ExecJS(ops []any) {
+	s.mu.Lock()
+	s.jsOps = append(s.jsOps, ops...)
+	s.mu.Unlock()
+}
+
+// TakeJS returns the commands queued with ExecJS and clears the queue.
+func (s *Socket) TakeJS() []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ops := s.jsOps
+	s.jsOps = nil
+	return ops
+}
@@ -27,7 +27,7 @@ type Component interface {
 
 	// HandleInfo processes internal messages sent to the component.
 	// These are typically used for pub/sub, timers, or background task results.
-	HandleInfo(ctx context.Context, msg any) error
+	InfoReceiver
 
 	// Terminate is called when the component is being destroyed.
 	// Use this for cleanup operations.
@@ -91,6 +91,9 @@ const (
 	TerminateError
 	// TerminateTimeout indicates termination due to inactivity.
 	TerminateTimeout
+	// TerminateTakeover indicates another connection of the same client
+	// took the session over, possibly on another node.
+	TerminateTakeover
 )
 
 func (r TerminateReason) String() string {
@@ -103,6 +106,8 @@ func (r TerminateReason) String() string {
 		return "error"
 	case TerminateTimeout:
 		return "timeout"
+	case TerminateTakeover:
+		return "takeover"
 	default:
 		return "unknown"
 	}
@@ -113,6 +118,7 @@ func (r TerminateReason) String() string {
 type BaseComponent struct {
 	socket  *Socket
 	assigns *Assigns
+	dirty   []string
 }
 
 // SetSocket sets the socket for the component (called by the framework).
@@ -133,6 +139,22 @@ func (bc *BaseComponent) Assigns() *Assigns {
 	return bc.assigns
 }
 
+// Dirty marks the slots affected by the current event or message. When any
+// slot is marked, the next diff extracts and compares only those slots, so
+// hot paths skip scanning the rest of the page. Changes to unmarked slots are
+// not sent; without Dirty every slot is extracted as usual.
+func (bc *BaseComponent) Dirty(slots ...string) {
+	bc.dirty = append(bc.dirty, slots...)
+}
+
+// TakeDirty returns the slots marked with Dirty and clears them
+// (called by the framework before each diff).
+func (bc *BaseComponent) TakeDirty() []string {
+	dirty := bc.dirty
+	bc.dirty = nil
+	return dirty
+}
+
 // Name returns an empty string (override in your component).
 func (bc *BaseComponent) Name() string {
 	return ""
@@ -200,6 +222,24 @@ type TemplateProvider interface {
 	TemplateSource() string
 }
 
+// Snapshotter is implemented by components whose state can move to a new
+// connection, possibly on another instance (e.g. during a rolling deploy).
+// The snapshot travels through JSON, so numbers come back as float64.
+type Snapshotter interface {
+	// Snapshot returns the state to carry over.
+	Snapshot() map[string]any
+	// Restore applies a snapshot after Mount on the new connection.
+	Restore(state map[string]any) error
+}
+
+// DirtyTracker is implemented by components that mark the slots an update
+// affects. BaseComponent implements it through Dirty.
+type DirtyTracker interface {
+	// TakeDirty returns and clears the slots marked since the last diff.
+	// An empty result means every slot may have changed.
+	TakeDirty() []string
+}
+
 // ListItem represents an item in a keyed list for efficient diffing.
 // Used by ListProvider to enable insert/delete/move/update operations
 // instead of full list re-renders.
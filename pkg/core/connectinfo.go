@@ -0,0 +1,46 @@
+package core
+
+import "time"
+
+// ConnectInfo describes the client connection of a socket. The router fills
+// it in when the client connects and joins, apart from the component's
+// assigns, so components read it without keeping it in their state and can
+// adapt what they render, such as leaving out animations:
+//
+//	info := c.Socket().ConnectInfo()
+//	if !info.ReducedMotion {
+//	    ...
+//	}
+//
+// Everything but IP, UserAgent and JoinedAt is reported by the browser:
+// use it to adapt the page, not to make security decisions.
+type ConnectInfo struct {
+	IP        string          // Client address, from X-Forwarded-For or X-Real-IP behind a proxy
+	UserAgent string          // User-Agent of the connection request
+	JoinedAt  time.Time       // When the view was joined
+	Timezone  string          // IANA timezone, such as "Europe/Madrid"
+	Features  map[string]bool // Browser features, such as "touch"
+
+	ViewportWidth  int    // Window size in CSS pixels, 0 when unknown
+	ViewportHeight int    // Window size in CSS pixels, 0 when unknown
+	ColorScheme    string // Preferred color scheme: "light" or "dark"
+	ReducedMotion  bool   // The user asked for less motion
+	Locale         string // Preferred language, such as "es-AR"
+	Connection     string // Effective connection type: "slow-2g", "2g", "3g", "4g" or "" when unknown
+}
+
+// ConnectInfo returns the socket's connection metadata. The zero value is
+// returned during the HTTP render, before a client connects.
+func (s *Socket) ConnectInfo() ConnectInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connectInfo
+}
+
+// SetConnectInfo replaces the socket's connection metadata. It is called
+// by the router.
+func (s *Socket) SetConnectInfo(info ConnectInfo) {
+	s.mu.Lock()
+	s.connectInfo = info
+	s.mu.Unlock()
+}
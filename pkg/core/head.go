@@ -0,0 +1,154 @@
+package core
+
+import (
+	"html"
+	"strings"
+)
+
+// HeadEvent is the event the client handles to update document head elements.
+const HeadEvent = "lv:head"
+
+// Meta is a <meta> element, identified by Name or Property.
+type Meta struct {
+	Name     string `json:"name,omitempty"`
+	Property string `json:"property,omitempty"`
+	Content  string `json:"content"`
+}
+
+// Link is a <link> element, identified by Rel (and Hreflang when set).
+type Link struct {
+	Rel      string `json:"rel"`
+	Href     string `json:"href"`
+	Type     string `json:"type,omitempty"`
+	Sizes    string `json:"sizes,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+}
+
+// Head describes the document head elements a component manages. Empty
+// fields are left untouched on the client.
+type Head struct {
+	Title string `json:"title,omitempty"`
+	Meta  []Meta `json:"meta,omitempty"`
+	Links []Link `json:"links,omitempty"`
+}
+
+// HeadProvider is implemented by components that manage the document head.
+// The router renders Head into the page on the initial request and pushes a
+// HeadEvent whenever it changes after an event or info message, so a title
+// like "(3) Inbox" stays current without a page reload.
+type HeadProvider interface {
+	Head() Head
+}
+
+// IsEmpty reports whether h has nothing to apply.
+func (h Head) IsEmpty() bool {
+	return h.Title == "" && len(h.Meta) == 0 && len(h.Links) == 0
+}
+
+// Payload returns h as an event payload for HeadEvent.
+func (h Head) Payload() map[string]any {
+	payload := make(map[string]any, 3)
+	if h.Title != "" {
+		payload["title"] = h.Title
+	}
+	if len(h.Meta) > 0 {
+		payload["meta"] = h.Meta
+	}
+	if len(h.Links) > 0 {
+		payload["links"] = h.Links
+	}
+	return payload
+}
+
+// Key returns a string that changes whenever h does.
+func (h Head) Key() string {
+	return h.Title + "\x00" + h.Tags()
+}
+
+// Tags renders the meta and link elements. Each is marked data-lv-head so
+// the client can find and update it later.
+func (h Head) Tags() string {
+	var sb strings.Builder
+	for _, m := range h.Meta {
+		sb.WriteString("<meta")
+		if m.Name != "" {
+			writeAttr(&sb, "name", m.Name)
+		}
+		if m.Property != "" {
+			writeAttr(&sb, "property", m.Property)
+		}
+		writeAttr(&sb, "content", m.Content)
+		sb.WriteString(" data-lv-head>\n")
+	}
+	for _, l := range h.Links {
+		sb.WriteString("<link")
+		writeAttr(&sb, "rel", l.Rel)
+		writeAttr(&sb, "href", l.Href)
+		if l.Type != "" {
+			writeAttr(&sb, "type", l.Type)
+		}
+		if l.Sizes != "" {
+			writeAttr(&sb, "sizes", l.Sizes)
+		}
+		if l.Hreflang != "" {
+			writeAttr(&sb, "hreflang", l.Hreflang)
+		}
+		sb.WriteString(" data-lv-head>\n")
+	}
+	return sb.String()
+}
+
+// Inject applies h to a rendered document: the <title> text is replaced
+// (or a title added) and the meta and link tags are inserted before </head>.
+// Documents without a head are returned unchanged.
+func (h Head) Inject(document string) string {
+	if h.IsEmpty() {
+		return document
+	}
+	lower := strings.ToLower(document)
+	end := strings.Index(lower, "</head>")
+	if end < 0 {
+		return document
+	}
+
+	tags := h.Tags()
+	if h.Title != "" {
+		title := "<title>" + html.EscapeString(h.Title) + "</title>"
+		open := strings.Index(lower[:end], "<title")
+		if open >= 0 {
+			if n := strings.Index(lower[open:end], "</title>"); n >= 0 {
+				document = document[:open] + title + document[open+n+len("</title>"):]
+				return insertBeforeHead(document, tags)
+			}
+		}
+		tags = title + "\n" + tags
+	}
+	return insertBeforeHead(document, tags)
+}
+
+func insertBeforeHead(document, tags string) string {
+	end := strings.Index(strings.ToLower(document), "</head>")
+	return document[:end] + tags + document[end:]
+}
+
+func writeAttr(sb *strings.Builder, name, value string) {
+	sb.WriteString(" ")
+	sb.WriteString(name)
+	sb.WriteString(`="`)
+	sb.WriteString(html.EscapeString(value))
+	sb.WriteString(`"`)
+}
+
+// UpdateHead sets the document title (unless empty) and meta tags on the
+// client, e.g. to show an unread count after live navigation or new messages.
+func (s *Socket) UpdateHead(title string, meta ...Meta) error {
+	return s.SetHead(Head{Title: title, Meta: meta})
+}
+
+// SetHead applies h to the client's document head.
+func (s *Socket) SetHead(h Head) error {
+	if h.IsEmpty() {
+		return nil
+	}
+	return s.Push(HeadEvent, h.Payload())
+}
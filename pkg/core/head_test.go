@@ -0,0 +1,54 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHead_Inject(t *testing.T) {
+	h := Head{
+		Title: "(3) Inbox <new>",
+		Meta:  []Meta{{Name: "description", Content: `a "quoted" value`}},
+		Links: []Link{{Rel: "icon", Href: "/badge.png"}},
+	}
+
+	doc := h.Inject("<html><head><title>Inbox</title></head><body></body></html>")
+
+	if !strings.Contains(doc, "<title>(3) Inbox &lt;new&gt;</title>") {
+		t.Errorf("title not replaced: %s", doc)
+	}
+	if strings.Count(doc, "<title>") != 1 {
+		t.Errorf("expected a single title: %s", doc)
+	}
+	if !strings.Contains(doc, `<meta name="description" content="a &#34;quoted&#34; value" data-lv-head>`) {
+		t.Errorf("meta not injected: %s", doc)
+	}
+	if !strings.Contains(doc, `<link rel="icon" href="/badge.png" data-lv-head>`) {
+		t.Errorf("link not injected: %s", doc)
+	}
+	if strings.Index(doc, "data-lv-head") > strings.Index(doc, "</head>") {
+		t.Errorf("tags should be inside head: %s", doc)
+	}
+}
+
+func TestHead_InjectAddsMissingTitle(t *testing.T) {
+	doc := Head{Title: "Hello"}.Inject("<head></head>")
+	if doc != "<head><title>Hello</title>\n</head>" {
+		t.Errorf("got %q", doc)
+	}
+
+	if got := (Head{Title: "x"}).Inject("<div></div>"); got != "<div></div>" {
+		t.Errorf("documents without head should be unchanged, got %q", got)
+	}
+}
+
+func TestHead_KeyChanges(t *testing.T) {
+	a := Head{Title: "a"}
+	b := Head{Title: "a", Meta: []Meta{{Name: "x", Content: "1"}}}
+	if a.Key() == b.Key() {
+		t.Error("expected different keys")
+	}
+	if a.Key() != (Head{Title: "a"}).Key() {
+		t.Error("expected equal keys for equal heads")
+	}
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// InfoReceiver handles server-side messages: Socket.SendInfo, tickers and
+// pubsub broadcasts. The router delivers them on the session's message loop,
+// then re-renders and pushes the diff.
+type InfoReceiver interface {
+	HandleInfo(ctx context.Context, msg any) error
+}
+
+// Broadcast is delivered to HandleInfo for each message published on a topic
+// the socket joined with Socket.Subscribe. Messages sent with
+// pubsub.Broadcaster or pubsub.Channel carry an Event and Payload; Data is
+// always the raw message.
+//
+// CorrelationID and TraceID are those of the publisher's context. The
+// router handles the message with them in ctx, so the trace of the event
+// that published it also covers this session's HandleInfo and render.
+type Broadcast struct {
+	Topic         string
+	Event         string
+	Payload       map[string]any
+	Data          []byte
+	CorrelationID string
+	TraceID       string
+}
+
+// Subscriber subscribes to a pubsub topic, calling deliver for each message,
+// and returns a function that cancels the subscription. The router installs
+// one on every socket (see Socket.SetSubscriber).
+type Subscriber func(topic string, deliver func(data []byte)) (cancel func(), err error)
+
+// newBroadcast decodes the {"event", "payload"} encoding used by the
+// pubsub package, leaving Event empty for other messages.
+func newBroadcast(topic string, data []byte) Broadcast {
+	b := Broadcast{Topic: topic, Data: data}
+	var msg struct {
+		Event         string         `json:"event"`
+		Payload       map[string]any `json:"payload"`
+		CorrelationID string         `json:"correlation_id"`
+		TraceID       string         `json:"trace_id"`
+	}
+	if json.Unmarshal(data, &msg) == nil && msg.Event != "" {
+		b.Event, b.Payload = msg.Event, msg.Payload
+		b.CorrelationID, b.TraceID = msg.CorrelationID, msg.TraceID
+	}
+	return b
+}
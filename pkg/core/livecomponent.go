@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"sync"
+)
+
+// TargetKey is the payload key naming the LiveComponent an event is for.
+// The client sets it from the closest lv-target attribute; the router
+// removes it before calling HandleEvent.
+const TargetKey = "_target"
+
+// LiveComponent is a stateful child of a LiveView: a cart, a comment form,
+// a row editor. It keeps its own state, handles the events sent from
+// inside it and owns its data-slot names, so an event re-renders and
+// diffs the child only. The parent creates its children (usually in
+// Mount), adds them to its LiveComponents and renders them with Embed:
+//
+//	type Shop struct {
+//		core.BaseComponent
+//		core.LiveComponents
+//	}
+//
+//	func (s *Shop) Mount(ctx context.Context, params core.Params, session core.Session) error {
+//		s.Add(NewCart("cart"))
+//		return nil
+//	}
+//
+// Inside the child, slot names come from Slot so they do not collide with
+// the parent's or another child's:
+//
+//	<span data-slot="{{ .Slot "total" }}">{{ .Total }}</span>
+type LiveComponent interface {
+	// ComponentID identifies the child within its view.
+	ComponentID() string
+
+	// Render returns the child's HTML, without the Embed wrapper.
+	Render(ctx context.Context) Renderer
+
+	// HandleEvent processes the events sent from inside the child.
+	HandleEvent(ctx context.Context, event string, payload map[string]any) error
+}
+
+// LiveComponentHost is implemented by views with children. LiveComponents
+// implements it.
+type LiveComponentHost interface {
+	// LiveComponent returns the child with the given ID, or nil.
+	LiveComponent(id string) LiveComponent
+}
+
+// LiveComponents holds a view's children by ID. Embed it in the parent so
+// the router can route targeted events to them.
+type LiveComponents struct {
+	mu       sync.RWMutex
+	children map[string]LiveComponent
+}
+
+// Add adds a child, replacing any child with the same ID, and returns it.
+func (s *LiveComponents) Add(c LiveComponent) LiveComponent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.children == nil {
+		s.children = make(map[string]LiveComponent)
+	}
+	s.children[c.ComponentID()] = c
+	return c
+}
+
+// Remove removes the child with the given ID.
+func (s *LiveComponents) Remove(id string) {
+	s.mu.Lock()
+	delete(s.children, id)
+	s.mu.Unlock()
+}
+
+// LiveComponent returns the child with the given ID, or nil.
+func (s *LiveComponents) LiveComponent(id string) LiveComponent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.children[id]
+}
+
+// Embed renders a child inside its wrapper element, which routes the
+// events of the elements inside to the child. An element can send to the
+// parent instead with lv-target="".
+func Embed(c LiveComponent) Renderer {
+	return RendererFunc(func(ctx context.Context, w io.Writer) error {
+		id := html.EscapeString(c.ComponentID())
+		if _, err := fmt.Fprintf(w, `<div data-lv-component="%s" lv-target="%s">`, id, id); err != nil {
+			return err
+		}
+		if r := c.Render(ctx); r != nil {
+			if err := r.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, `</div>`)
+		return err
+	})
+}
+
+// BaseLiveComponent provides the ID and a no-op HandleEvent. Embed it in
+// your children:
+//
+//	type Cart struct {
+//		core.BaseLiveComponent
+//		Items []Item
+//	}
+//
+//	func NewCart(id string) *Cart {
+//		return &Cart{BaseLiveComponent: core.BaseLiveComponent{ID: id}}
+//	}
+type BaseLiveComponent struct {
+	ID string
+}
+
+// ComponentID returns ID.
+func (c *BaseLiveComponent) ComponentID() string {
+	return c.ID
+}
+
+// Slot returns the child's data-slot name for name ("cart.total").
+func (c *BaseLiveComponent) Slot(name string) string {
+	return c.ID + "." + name
+}
+
+// HandleEvent does nothing by default.
+func (c *BaseLiveComponent) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+type noteChild struct {
+	BaseLiveComponent
+}
+
+func (c *noteChild) Render(ctx context.Context) Renderer {
+	return RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<p data-slot="`+c.Slot("text")+`">hi</p>`)
+		return err
+	})
+}
+
+func TestEmbed(t *testing.T) {
+	var buf bytes.Buffer
+	child := &noteChild{BaseLiveComponent{ID: "note"}}
+	if err := Embed(child).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := `<div data-lv-component="note" lv-target="note"><p data-slot="note.text">hi</p></div>`
+	if buf.String() != want {
+		t.Errorf("Expected %s, got %s", want, buf.String())
+	}
+}
+
+func TestLiveComponents(t *testing.T) {
+	var set LiveComponents
+	if set.LiveComponent("note") != nil {
+		t.Error("Expected no child in an empty set")
+	}
+	child := set.Add(&noteChild{BaseLiveComponent{ID: "note"}})
+	if set.LiveComponent("note") != child {
+		t.Error("Expected the added child")
+	}
+	set.Remove("note")
+	if set.LiveComponent("note") != nil {
+		t.Error("Expected the child to be removed")
+	}
+}
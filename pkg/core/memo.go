@@ -0,0 +1,127 @@
+package core
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultMemoSize is the number of renders a Memo keeps by default.
+const DefaultMemoSize = 64
+
+// Memoized caches the output of a render function keyed by a hash of its
+// input, so subtrees whose assigns have not changed are not rendered again.
+// It is safe for concurrent use and is usually shared by every session:
+//
+//	var sidebar = core.Memo(func(section string) string {
+//	    return renderSidebar(section)
+//	})
+//
+//	func (d *Docs) Render(ctx context.Context) core.Renderer {
+//	    ... sidebar.Render(d.CurrentSection) ...
+//	}
+//
+// Inputs are hashed like tracked assigns: scalars, []any and map[string]any
+// directly, anything else by its JSON encoding (so unexported struct fields
+// are ignored). The render function must depend on its input only.
+type Memoized[T any] struct {
+	fn func(T) string
+
+	mu      sync.Mutex
+	max     int
+	entries map[uint64]*list.Element
+	lru     *list.List
+	hits    uint64
+	misses  uint64
+}
+
+type memoEntry struct {
+	hash uint64
+	out  string
+}
+
+// Memo wraps fn with an LRU cache of DefaultMemoSize renders.
+func Memo[T any](fn func(T) string) *Memoized[T] {
+	return &Memoized[T]{
+		fn:      fn,
+		max:     DefaultMemoSize,
+		entries: make(map[uint64]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// WithSize sets the maximum number of cached renders and returns m. A size
+// of zero or less disables caching.
+func (m *Memoized[T]) WithSize(n int) *Memoized[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.max = n
+	m.evict()
+	return m
+}
+
+// Render returns the cached output for input, rendering it on a miss.
+func (m *Memoized[T]) Render(input T) string {
+	hash := hashValue(input)
+
+	m.mu.Lock()
+	if el, ok := m.entries[hash]; ok {
+		m.hits++
+		m.lru.MoveToFront(el)
+		out := el.Value.(*memoEntry).out
+		m.mu.Unlock()
+		return out
+	}
+	m.misses++
+	m.mu.Unlock()
+
+	// Render outside the lock: concurrent misses for the same input may
+	// both render, which is cheaper than serializing every render.
+	out := m.fn(input)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.max <= 0 {
+		return out
+	}
+	if el, ok := m.entries[hash]; ok {
+		el.Value.(*memoEntry).out = out
+		m.lru.MoveToFront(el)
+		return out
+	}
+	m.entries[hash] = m.lru.PushFront(&memoEntry{hash: hash, out: out})
+	m.evict()
+	return out
+}
+
+// Len returns the number of cached renders.
+func (m *Memoized[T]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+// Stats returns the number of cache hits and misses.
+func (m *Memoized[T]) Stats() (hits, misses uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits, m.misses
+}
+
+// Reset drops every cached render, e.g. after the data behind the render
+// function changed.
+func (m *Memoized[T]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[uint64]*list.Element)
+	m.lru.Init()
+}
+
+// evict drops least recently used renders over the size limit. Must be
+// called with m.mu held.
+func (m *Memoized[T]) evict() {
+	for m.lru.Len() > max(m.max, 0) {
+		el := m.lru.Back()
+		delete(m.entries, el.Value.(*memoEntry).hash)
+		m.lru.Remove(el)
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMemo_SkipsUnchangedInput(t *testing.T) {
+	calls := 0
+	m := Memo(func(assigns map[string]any) string {
+		calls++
+		return fmt.Sprintf("<li>%v</li>", assigns["section"])
+	})
+
+	for i := 0; i < 3; i++ {
+		if out := m.Render(map[string]any{"section": "intro"}); out != "<li>intro</li>" {
+			t.Fatalf("Render() = %q", out)
+		}
+	}
+	m.Render(map[string]any{"section": "forms"})
+
+	if calls != 2 {
+		t.Errorf("Expected 2 renders, got %d", calls)
+	}
+	if hits, misses := m.Stats(); hits != 2 || misses != 2 {
+		t.Errorf("Stats() = %d hits, %d misses", hits, misses)
+	}
+}
+
+func TestMemo_Size(t *testing.T) {
+	calls := 0
+	m := Memo(func(n int) string {
+		calls++
+		return fmt.Sprint(n)
+	}).WithSize(2)
+
+	m.Render(1)
+	m.Render(2)
+	m.Render(1) // 2 is now least recently used
+	m.Render(3)
+	if m.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", m.Len())
+	}
+	m.Render(1)
+	if calls != 3 {
+		t.Errorf("Expected 1 to stay cached, got %d renders", calls)
+	}
+	m.Render(2)
+	if calls != 4 {
+		t.Errorf("Expected 2 to be evicted, got %d renders", calls)
+	}
+
+	m.WithSize(0)
+	m.Render(5)
+	if m.Len() != 0 {
+		t.Errorf("Expected a zero size to disable caching, got %d entries", m.Len())
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import "context"
+
+// PatchEvent changes the page URL without a page load (push_patch). The
+// client adds a history entry, or replaces the current one, and keeps the
+// UI state of the entry it leaves (scroll positions, elements marked
+// lv-ui-state) for back/forward navigation.
+const PatchEvent = "lv:patch"
+
+// PushPatch points the browser at to, a same-origin path such as
+// "/products?page=2", adding a history entry.
+func (s *Socket) PushPatch(to string) error {
+	return s.Push(PatchEvent, map[string]any{"to": to})
+}
+
+// ReplacePatch is PushPatch replacing the current history entry, for state
+// the back button should skip (filters being typed, sort order).
+func (s *Socket) ReplacePatch(to string) error {
+	return s.Push(PatchEvent, map[string]any{"to": to, "replace": true})
+}
+
+// NavigateEvent loads another route (push_navigate), keeping the UI state of
+// the page it leaves for the back button. The client moves its socket to the
+// route (live_redirect) when the router allows it, and loads the page
+// otherwise.
+const NavigateEvent = "lv:navigate"
+
+// ParamsEvent is sent by the client when its URL changed without a page
+// load. The router handles it and calls HandleParams.
+const ParamsEvent = "lv:params"
+
+// PushNavigate points the browser at to, a same-origin path served by
+// another route, adding a history entry.
+func (s *Socket) PushNavigate(to string) error {
+	return s.Push(NavigateEvent, map[string]any{"to": to})
+}
+
+// RouteKey is the params key holding the path of the route being shown, for
+// components serving stacked routes (a list and its edit modal): "/users"
+// or "/users/{id}/edit". It is only set for routes in a stack.
+const RouteKey = "_route"
+
+// NavigationKind tells how the URL changed.
+type NavigationKind string
+
+const (
+	// NavigationPatch is a URL changed by PushPatch or ReplacePatch.
+	NavigationPatch NavigationKind = "patch"
+	// NavigationPopState is the user going back or forward in history.
+	NavigationPopState NavigationKind = "pop"
+	// NavigationNavigate is a URL reached by live navigation: PushNavigate
+	// or an lv-navigate link.
+	NavigationNavigate NavigationKind = "navigate"
+)
+
+// Navigation describes a URL change within a mounted view.
+type Navigation struct {
+	// URI is the new path and query, e.g. "/products?page=2".
+	URI  string
+	Kind NavigationKind
+}
+
+// ParamsHandler is implemented by components that follow URL changes in
+// place. HandleParams receives the new query parameters after a push_patch
+// and on back/forward, then the router re-renders. Components without it
+// are reloaded when the user goes back or forward to a patched URL.
+type ParamsHandler interface {
+	HandleParams(ctx context.Context, params Params, nav Navigation) error
+}
@@ -0,0 +1,22 @@
+package core
+
+import "testing"
+
+func TestSocket_PushPatch(t *testing.T) {
+	transport := NewMockTransport()
+	s := NewSocket("s1", transport)
+
+	s.PushPatch("/products?page=2")
+	s.ReplacePatch("/products?sort=price")
+
+	msgs := transport.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 pushes, got %d", len(msgs))
+	}
+	if msgs[0].Event != PatchEvent || msgs[0].Payload["to"] != "/products?page=2" || msgs[0].Payload["replace"] != nil {
+		t.Errorf("unexpected push: %+v", msgs[0])
+	}
+	if msgs[1].Payload["to"] != "/products?sort=price" || msgs[1].Payload["replace"] != true {
+		t.Errorf("unexpected replace: %+v", msgs[1])
+	}
+}
@@ -0,0 +1,7 @@
+package core
+
+// PropsEvent is the event a host page (a React or Vue app embedding the
+// view) sends with its props: once when the view joins, before the first
+// render, and again whenever the props change. Components receive it in
+// HandleEvent with the props as the payload.
+const PropsEvent = "lv:props"
@@ -0,0 +1,38 @@
+package core
+
+import "context"
+
+// Reply sets the value the client receives for the event being handled:
+// pushEvent resolves its promise with it, once the diff of the event has
+// been applied. It lets a handler answer a question, such as whether a
+// coupon is valid, without rendering the answer. The value is sent as
+// JSON; a later Reply for the same event replaces it.
+func (s *Socket) Reply(value any) {
+	s.mu.Lock()
+	s.reply = value
+	s.mu.Unlock()
+}
+
+// TakeReply returns the value set with Reply, or nil, and clears it.
+func (s *Socket) TakeReply() any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value := s.reply
+	s.reply = nil
+	return value
+}
+
+// Reply sets the reply to the event handled with ctx (see Socket.Reply).
+// It does nothing outside a connected view.
+//
+//	func (c *Checkout) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//	    if event == "check_coupon" {
+//	        core.Reply(ctx, map[string]any{"valid": c.coupons.Valid(payload["code"])})
+//	    }
+//	    return nil
+//	}
+func Reply(ctx context.Context, value any) {
+	if socket := SocketFromContext(ctx); socket != nil {
+		socket.Reply(value)
+	}
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+)
+
+// SlotRenderer is implemented by components with precompiled render
+// functions (see "golive generate render"). RenderSlots returns the full
+// HTML together with the trimmed content of each top-level data-slot
+// element, so the router does not scan the HTML for slots.
+type SlotRenderer interface {
+	RenderSlots(ctx context.Context) (html string, slots map[string]string)
+}
+
+// EscapeValue formats v for a text position in generated render functions.
+func EscapeValue(v any) string {
+	return html.EscapeString(RawValue(v))
+}
+
+// RawValue formats v for a trusted HTML position in generated render
+// functions.
+func RawValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return ""
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,8 +16,13 @@ var (
 	ErrSocketNotFound = errors.New("socket not found")
 	ErrSendFailed     = errors.New("failed to send message")
 	ErrInvalidMessage = errors.New("invalid message format")
+	ErrInfoQueueFull  = errors.New("info queue full")
+	ErrNoSubscriber   = errors.New("socket has no pubsub subscriber")
 )
 
+// infoQueueSize is the buffer size of the per-socket info queue.
+const infoQueueSize = 64
+
 // Socket represents a WebSocket connection to a client.
 // It provides methods for sending messages and managing connection state.
 type Socket struct {
@@ -37,15 +43,41 @@ type Socket struct {
 	// Transport layer (WebSocket, SSE, etc.)
 	transport Transport
 
-	// Subscriptions to topics
-	subscriptions map[string]bool
+	// Subscriptions to topics, with the functions cancelling them
+	subscriptions map[string]func()
+	subscriber    Subscriber
 
 	// Metadata
-	metadata map[string]any
+	metadata    map[string]any
+	connectInfo ConnectInfo
 
 	// Error count for circuit breaker
 	errorCount int
 
+	// Server-side messages delivered to the component's HandleInfo
+	info       chan any
+	infoNotify func()
+
+	// A PushRender is queued and not yet rendered
+	renderPending atomic.Bool
+
+	// Functions run once when the socket closes
+	closeHooks []func()
+
+	// Client commands sent with the next diff (see ExecJS)
+	jsOps []any
+
+	// Reply to the event being handled (see Reply)
+	reply any
+
+	// Closed when the hidden page is shown again, nil while it is visible
+	// (see SetHidden)
+	shown chan struct{}
+
+	// Values already pushed to the other tabs of the browser (see
+	// PushShared)
+	tabGroup *TabGroup
+
 	// Mutex for thread safety (not used for lastActivity anymore)
 	mu sync.RWMutex
 }
@@ -63,8 +95,15 @@ type Message struct {
 	Topic   string         `json:"topic"`
 	Event   string         `json:"event"`
 	Payload map[string]any `json:"payload,omitempty"`
+
+	// Binary is an optional raw attachment delivered without base64 encoding.
+	Binary []byte `json:"-"`
 }
 
+// BinaryKey is the payload key under which a binary attachment sent by the
+// client is exposed to HandleEvent, as a []byte.
+const BinaryKey = "binary"
+
 // Upload represents a file upload in progress.
 type Upload struct {
 	Config  UploadConfig
@@ -101,8 +140,9 @@ func NewSocket(id string, transport Transport) *Socket {
 		connectedAt:   now,
 		assigns:       NewAssigns(),
 		uploads:       make(map[string]*Upload),
-		subscriptions: make(map[string]bool),
+		subscriptions: make(map[string]func()),
 		metadata:      make(map[string]any),
+		info:          make(chan any, infoQueueSize),
 		transport:     transport,
 	}
 	s.lastActivity.Store(now.UnixNano())
@@ -198,11 +238,35 @@ func (s *Socket) Push(event string, payload map[string]any) error {
 	})
 }
 
-// PushEvent is an alias for Push.
+// PushBinary sends an event with a raw binary attachment to the client.
+// The client receives the attachment as an ArrayBuffer alongside the payload.
+func (s *Socket) PushBinary(event string, payload map[string]any, data []byte) error {
+	return s.Send(Message{
+		Topic:   "lv:" + s.id,
+		Event:   event,
+		Payload: payload,
+		Binary:  data,
+	})
+}
+
+// PushEvent is an alias for Push. Events the client does not handle itself
+// reach its on() listeners, the handleEvent callbacks of hooks and, on
+// pages, a "golive:<event>" window event, for client-only behavior such as
+// a chart update or a toast.
 func (s *Socket) PushEvent(event string, payload map[string]any) error {
 	return s.Push(event, payload)
 }
 
+// PushToEvent carries an event pushed with PushEventTo.
+const PushToEvent = "lv:push_to"
+
+// PushEventTo pushes event to the hooks of the elements matching selector
+// (a CSS selector such as "#sales-chart"), which receive it through
+// handleEvent. Other listeners do not see it.
+func (s *Socket) PushEventTo(selector, event string, payload map[string]any) error {
+	return s.Push(PushToEvent, map[string]any{"to": selector, "event": event, "payload": payload})
+}
+
 // ListOp represents a single list operation for the client.
 // Used in DiffPayload.ListOps for efficient list updates.
 type ListOp struct {
@@ -213,21 +277,50 @@ type ListOp struct {
 }
 
 // DiffPayload is the optimized diff format sent to clients.
-// Supports text slots (s), HTML slots (h), list operations (l), and full render (f).
+// Supports text slots (s), text slot splices (w), HTML slots (h), slot trees
+// (t), list operations (l), full render (f), full render as a delta of the
+// previous one (fd), and client commands (j).
 type DiffPayload struct {
-	Version   uint64              `json:"v"`           // Version for ordering
-	Slots     map[string]string   `json:"s,omitempty"` // Text-only slots (fast path)
-	HTMLSlots map[string]string   `json:"h,omitempty"` // HTML slots (innerHTML)
-	ListOps   map[string][]ListOp `json:"l,omitempty"` // List operations
-	Full      string              `json:"f,omitempty"` // Full render (fallback)
+	Version   uint64                  `json:"v"`            // Version for ordering
+	Slots     map[string]string       `json:"s,omitempty"`  // Text-only slots (fast path)
+	Splices   map[string][]TextSplice `json:"w,omitempty"`  // Edits of large text slots
+	HTMLSlots map[string]string       `json:"h,omitempty"`  // HTML slots (innerHTML)
+	Trees     map[string]any          `json:"t,omitempty"`  // Changed dynamics of slot trees (see diff.DiffRendered)
+	ListOps   map[string][]ListOp     `json:"l,omitempty"`  // List operations
+	Full      string                  `json:"f,omitempty"`  // Full render (fallback)
+	FullDelta *FullDelta              `json:"fd,omitempty"` // Full render as a delta of the previous one
+	JS        []any                   `json:"j,omitempty"`  // Client commands run after the diff (see ExecJS)
+}
+
+// TextSplice replaces Del characters at Pos of a text slot with Text.
+// Positions count UTF-16 code units of the text before any of the splices
+// of the slot, which come in increasing order.
+type TextSplice struct {
+	Pos  int    `json:"p"`
+	Del  int    `json:"d,omitempty"`
+	Text string `json:"t,omitempty"`
+}
+
+// FullDelta is a full render sent as its changes to the previous full
+// render, which the client keeps: the Ops are copies of the previous
+// render, as start and length pairs, and strings to insert between them.
+// Base is the length of the previous render. Lengths and offsets count
+// UTF-16 code units, like JavaScript strings.
+type FullDelta struct {
+	Base int   `json:"b"`
+	Ops  []any `json:"o"`
 }
 
 // IsEmpty returns true if the payload has no changes.
 func (d *DiffPayload) IsEmpty() bool {
 	return len(d.Slots) == 0 &&
+		len(d.Splices) == 0 &&
 		len(d.HTMLSlots) == 0 &&
+		len(d.Trees) == 0 &&
 		len(d.ListOps) == 0 &&
-		d.Full == ""
+		d.Full == "" &&
+		d.FullDelta == nil &&
+		len(d.JS) == 0
 }
 
 // Size returns the total size of the payload in bytes.
@@ -236,31 +329,90 @@ func (d *DiffPayload) Size() int {
 	for _, content := range d.Slots {
 		size += len(content)
 	}
+	for _, splices := range d.Splices {
+		for _, sp := range splices {
+			size += len(sp.Text)
+		}
+	}
 	for _, content := range d.HTMLSlots {
 		size += len(content)
 	}
+	for _, tree := range d.Trees {
+		size += EncodedSize(tree)
+	}
 	for _, ops := range d.ListOps {
 		for _, op := range ops {
 			size += len(op.Content)
 		}
 	}
 	size += len(d.Full)
+	if d.FullDelta != nil {
+		size += EncodedSize(d.FullDelta.Ops)
+	}
 	return size
 }
 
+// EncodedSize estimates the JSON size of a value made of strings, slices
+// and maps, such as an encoded diff.Rendered: its strings plus keys and
+// separators.
+func EncodedSize(v any) int {
+	switch v := v.(type) {
+	case string:
+		return len(v) + 2
+	case int:
+		return len(strconv.Itoa(v))
+	case []string:
+		size := 2
+		for _, s := range v {
+			size += len(s) + 3
+		}
+		return size
+	case []any:
+		size := 2
+		for _, item := range v {
+			size += EncodedSize(item) + 1
+		}
+		return size
+	case [][]any:
+		size := 2
+		for _, row := range v {
+			size += EncodedSize(row) + 1
+		}
+		return size
+	case map[string]any:
+		size := 2
+		for key, item := range v {
+			size += len(key) + 4 + EncodedSize(item)
+		}
+		return size
+	}
+	return 0
+}
+
 // SendOptimizedDiff sends an optimized diff payload to the client.
 func (s *Socket) SendOptimizedDiff(payload *DiffPayload) error {
 	if payload == nil || payload.IsEmpty() {
 		return nil
 	}
 
-	return s.Push("diff", map[string]any{
+	msg := map[string]any{
 		"v": payload.Version,
 		"s": payload.Slots,
 		"h": payload.HTMLSlots,
+		"t": payload.Trees,
 		"l": payload.ListOps,
 		"f": payload.Full,
-	})
+	}
+	if len(payload.Splices) > 0 {
+		msg["w"] = payload.Splices
+	}
+	if payload.FullDelta != nil {
+		msg["fd"] = payload.FullDelta
+	}
+	if len(payload.JS) > 0 {
+		msg["j"] = payload.JS
+	}
+	return s.Push("diff", msg)
 }
 
 // SendDiff sends a diff update to the client (legacy compatibility).
@@ -269,19 +421,166 @@ func (s *Socket) SendDiff(payload *DiffPayload) error {
 	return s.SendOptimizedDiff(payload)
 }
 
-// Subscribe adds a topic subscription.
-func (s *Socket) Subscribe(topic string, handler func(any)) {
+// SendInfo queues a message for the component's HandleInfo. The router
+// delivers it on the session's message loop and re-renders afterwards, so it
+// is safe to call from any goroutine (tickers, pubsub callbacks).
+// It never blocks: ErrInfoQueueFull is returned if the loop is behind.
+func (s *Socket) SendInfo(msg any) error {
+	s.mu.RLock()
+	connected := s.connected
+	notify := s.infoNotify
+	s.mu.RUnlock()
+	if !connected {
+		return ErrSocketClosed
+	}
+
+	select {
+	case s.info <- msg:
+		if notify != nil {
+			notify()
+		}
+		return nil
+	default:
+		return ErrInfoQueueFull
+	}
+}
+
+// RenderRequest is queued by PushRender. The router renders the component
+// for it without calling HandleInfo.
+type RenderRequest struct {
+	socket *Socket
+}
+
+// Done lets the next PushRender queue another render (called by the router
+// before rendering).
+func (r RenderRequest) Done() {
+	if r.socket != nil {
+		r.socket.renderPending.Store(false)
+	}
+}
+
+// PushRender re-renders the component and pushes the diff from any
+// goroutine (tickers, database watchers, pubsub callbacks), without waiting
+// for a client event. Calls made before the render runs are coalesced into
+// one. The render happens on the session's message loop, so state the
+// goroutine changes should go through Assigns or be guarded by a mutex.
+func (s *Socket) PushRender() error {
+	if !s.renderPending.CompareAndSwap(false, true) {
+		return nil
+	}
+	if err := s.SendInfo(RenderRequest{socket: s}); err != nil {
+		s.renderPending.Store(false)
+		return err
+	}
+	return nil
+}
+
+// PushUpdate runs fn on the session's message loop and re-renders the
+// component, for goroutines that finish work the component state should
+// show (a lookup, a background check). fn runs between events, so it can
+// change the component's fields without a mutex.
+func (s *Socket) PushUpdate(fn func()) error {
+	return s.SendInfo(UpdateRequest{fn: fn})
+}
+
+// UpdateRequest is queued by PushUpdate. The router runs it and renders the
+// component without calling HandleInfo.
+type UpdateRequest struct {
+	fn func()
+}
+
+// Run calls the function given to PushUpdate.
+func (r UpdateRequest) Run() {
+	if r.fn != nil {
+		r.fn()
+	}
+}
+
+// OnInfo sets a function called after each SendInfo, so a shared event loop
+// can deliver info messages without a goroutine blocked on Info.
+func (s *Socket) OnInfo(fn func()) {
+	s.mu.Lock()
+	s.infoNotify = fn
+	s.mu.Unlock()
+}
+
+// OnClose adds a function run once when the socket closes, for state kept
+// outside the component such as presence entries. On a closed socket, fn
+// runs immediately.
+func (s *Socket) OnClose(fn func()) {
+	s.mu.Lock()
+	if !s.connected {
+		s.mu.Unlock()
+		fn()
+		return
+	}
+	s.closeHooks = append(s.closeHooks, fn)
+	s.mu.Unlock()
+}
+
+// Info returns the channel of queued info messages (consumed by the router).
+func (s *Socket) Info() <-chan any {
+	return s.info
+}
+
+// SetSubscriber sets the pubsub used by Subscribe (called by the router).
+func (s *Socket) SetSubscriber(fn Subscriber) {
+	s.mu.Lock()
+	s.subscriber = fn
+	s.mu.Unlock()
+}
+
+// Subscribe joins a pubsub topic: each message published on it is delivered
+// to the component's HandleInfo as a Broadcast, and the router pushes the
+// resulting diff. Subscribing twice to a topic is a no-op. Subscriptions end
+// with Unsubscribe or when the socket closes.
+func (s *Socket) Subscribe(topic string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.subscriptions[topic] = true
-	// Note: actual subscription logic would be handled by PubSub
+	if _, ok := s.subscriptions[topic]; ok {
+		return nil
+	}
+	if s.subscriber == nil {
+		return ErrNoSubscriber
+	}
+	if !s.connected {
+		return ErrSocketClosed
+	}
+
+	cancel, err := s.subscriber(topic, func(data []byte) {
+		s.SendInfo(newBroadcast(topic, data)) // dropped if the queue is full
+	})
+	if err != nil {
+		return err
+	}
+	s.subscriptions[topic] = cancel
+	return nil
 }
 
-// Unsubscribe removes a topic subscription.
+// Unsubscribe leaves a topic joined with Subscribe.
 func (s *Socket) Unsubscribe(topic string) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	cancel := s.subscriptions[topic]
 	delete(s.subscriptions, topic)
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// unsubscribeAll leaves every topic.
+func (s *Socket) unsubscribeAll() {
+	s.mu.Lock()
+	subs := s.subscriptions
+	s.subscriptions = make(map[string]func())
+	s.mu.Unlock()
+
+	for _, cancel := range subs {
+		if cancel != nil {
+			cancel()
+		}
+	}
 }
 
 // Subscriptions returns all active subscriptions.
@@ -324,12 +623,19 @@ func (s *Socket) Assign(key string, value any) {
 	s.assigns.Set(key, value)
 }
 
-// Close closes the socket connection.
+// Close closes the socket connection, leaves its topics and runs the
+// OnClose functions.
 func (s *Socket) Close() error {
 	s.mu.Lock()
 	s.connected = false
 	transport := s.transport
+	hooks := s.closeHooks
+	s.closeHooks = nil
 	s.mu.Unlock()
+	s.unsubscribeAll()
+	for _, fn := range hooks {
+		fn()
+	}
 
 	if transport != nil {
 		return transport.Close()
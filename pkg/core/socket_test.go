@@ -99,6 +99,24 @@ func TestSocket_Send(t *testing.T) {
 	}
 }
 
+func TestSocket_PushEventTo(t *testing.T) {
+	transport := NewMockTransport()
+	socket := NewSocket("test-id", transport)
+
+	if err := socket.PushEventTo("#chart", "points", map[string]any{"n": 3}); err != nil {
+		t.Fatalf("PushEventTo: %v", err)
+	}
+
+	messages := transport.Messages()
+	if len(messages) != 1 || messages[0].Event != PushToEvent {
+		t.Fatalf("expected one %s message, got %+v", PushToEvent, messages)
+	}
+	p := messages[0].Payload
+	if p["to"] != "#chart" || p["event"] != "points" || p["payload"].(map[string]any)["n"] != 3 {
+		t.Errorf("unexpected payload %v", p)
+	}
+}
+
 func TestSocket_Send_Closed(t *testing.T) {
 	transport := NewMockTransport()
 	socket := NewSocket("test-id", transport)
@@ -571,3 +589,41 @@ func TestSocket_RaceCondition(t *testing.T) {
 		t.Errorf("expected %d operations, got %d", expected, ops.Load())
 	}
 }
+
+func TestSocket_PushRenderCoalesces(t *testing.T) {
+	socket := NewSocket("test-id", NewMockTransport())
+
+	socket.PushRender()
+	socket.PushRender()
+	if n := len(socket.Info()); n != 1 {
+		t.Fatalf("expected 1 queued render, got %d", n)
+	}
+
+	req, ok := (<-socket.Info()).(RenderRequest)
+	if !ok {
+		t.Fatal("expected a RenderRequest")
+	}
+	req.Done()
+	socket.PushRender()
+	if n := len(socket.Info()); n != 1 {
+		t.Errorf("expected a new render after Done, got %d queued", n)
+	}
+}
+
+func TestSocket_OnClose(t *testing.T) {
+	socket := NewSocket("test-socket", NewMockTransport())
+
+	calls := 0
+	socket.OnClose(func() { calls++ })
+	socket.Close()
+	socket.Close()
+	if calls != 1 {
+		t.Errorf("OnClose ran %d times, want 1", calls)
+	}
+
+	// Registered after the close, it runs right away
+	socket.OnClose(func() { calls++ })
+	if calls != 2 {
+		t.Errorf("OnClose on a closed socket ran %d times, want 2", calls)
+	}
+}
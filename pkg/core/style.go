@@ -0,0 +1,355 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Stylesheet is CSS scoped to a component. Selectors are rewritten so they
+// only match inside an element carrying the sheet's scope class:
+//
+//	var cardStyles = core.NewStylesheet("card", `
+//	    :host { border: 1px solid #ddd }
+//	    .title { font-weight: 600 }
+//	`)
+//
+//	fmt.Fprintf(w, `<div class="%s">...`, cardStyles.Scope())
+//
+// A selector matches both descendants of the scoped element and the scoped
+// element itself (".title" becomes ".card-x1y2 .title,.title.card-x1y2").
+// ":host" refers to the scoped element and ":global(...)" opts a selector out
+// of scoping. @media, @supports, @container and @layer blocks are scoped
+// recursively; @keyframes and @font-face are left untouched.
+type Stylesheet struct {
+	scope string
+	css   string
+}
+
+// NewStylesheet scopes css under a class derived from name and a hash of the
+// source, so two different sheets never share a scope.
+func NewStylesheet(name, css string) *Stylesheet {
+	h := fnv.New32a()
+	h.Write([]byte(css))
+	scope := fmt.Sprintf("%s-%06x", cssIdent(name), h.Sum32()&0xffffff)
+	return &Stylesheet{
+		scope: scope,
+		css:   scopeCSS(stripCSSComments(css), scope),
+	}
+}
+
+// Scope returns the class to put on the component's root element.
+func (s *Stylesheet) Scope() string {
+	return s.scope
+}
+
+// CSS returns the scoped CSS.
+func (s *Stylesheet) CSS() string {
+	return s.css
+}
+
+// Tag returns the sheet as a <style> element.
+func (s *Stylesheet) Tag() string {
+	return fmt.Sprintf("<style data-lv-style=%q>%s</style>", s.scope, s.css)
+}
+
+// StyleProvider is implemented by components that co-locate their CSS.
+// The router collects the sheets on the initial render and injects them,
+// deduplicated, into the document head.
+type StyleProvider interface {
+	Styles() []*Stylesheet
+}
+
+// StyleCollector gathers the stylesheets used during a render.
+type StyleCollector struct {
+	mu     sync.Mutex
+	seen   map[string]bool
+	sheets []*Stylesheet
+}
+
+// NewStyleCollector creates an empty collector.
+func NewStyleCollector() *StyleCollector {
+	return &StyleCollector{seen: make(map[string]bool)}
+}
+
+// Add records sheets, ignoring ones already collected.
+func (c *StyleCollector) Add(sheets ...*Stylesheet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range sheets {
+		if s == nil || c.seen[s.scope] {
+			continue
+		}
+		c.seen[s.scope] = true
+		c.sheets = append(c.sheets, s)
+	}
+}
+
+// Sheets returns the collected sheets in the order they were first used.
+func (c *StyleCollector) Sheets() []*Stylesheet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]*Stylesheet, len(c.sheets))
+	copy(out, c.sheets)
+	return out
+}
+
+// HTML returns the collected sheets as <style> elements.
+func (c *StyleCollector) HTML() string {
+	var sb strings.Builder
+	for _, s := range c.Sheets() {
+		sb.WriteString(s.Tag())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Inject inserts the collected styles before </head> in document, or
+// prepends them when the document has no head.
+func (c *StyleCollector) Inject(document string) string {
+	styles := c.HTML()
+	if styles == "" {
+		return document
+	}
+	if i := strings.Index(strings.ToLower(document), "</head>"); i >= 0 {
+		return document[:i] + styles + document[i:]
+	}
+	return styles + document
+}
+
+const styleCollectorKey contextKey = "golivekit:styles"
+
+// WithStyleCollector adds a style collector to the context.
+func WithStyleCollector(ctx context.Context, c *StyleCollector) context.Context {
+	return context.WithValue(ctx, styleCollectorKey, c)
+}
+
+// StyleCollectorFromContext retrieves the style collector from context.
+func StyleCollectorFromContext(ctx context.Context) *StyleCollector {
+	c, _ := ctx.Value(styleCollectorKey).(*StyleCollector)
+	return c
+}
+
+// UseStyles records sheets used while rendering, typically by nested
+// components that are not themselves StyleProviders. It is a no-op when
+// the render has no collector.
+func UseStyles(ctx context.Context, sheets ...*Stylesheet) {
+	if c := StyleCollectorFromContext(ctx); c != nil {
+		c.Add(sheets...)
+	}
+}
+
+// StyleTags renders a component's own sheets as <style> elements.
+func StyleTags(comp Component) string {
+	sp, ok := comp.(StyleProvider)
+	if !ok {
+		return ""
+	}
+	c := NewStyleCollector()
+	c.Add(sp.Styles()...)
+	return c.HTML()
+}
+
+var (
+	cssCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssIdentPattern   = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+)
+
+func stripCSSComments(css string) string {
+	return cssCommentPattern.ReplaceAllString(css, "")
+}
+
+func cssIdent(name string) string {
+	name = cssIdentPattern.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "s" + name
+	}
+	return name
+}
+
+// scopeCSS rewrites the selectors of every rule in css.
+func scopeCSS(css, scope string) string {
+	var sb strings.Builder
+	i := 0
+	for i < len(css) {
+		open := strings.IndexAny(css[i:], "{;}")
+		if open < 0 {
+			sb.WriteString(strings.TrimSpace(css[i:]))
+			break
+		}
+		open += i
+		prelude := strings.TrimSpace(css[i:open])
+
+		switch css[open] {
+		case ';', '}':
+			// Statement at-rule (@import, @charset) or stray token.
+			if prelude != "" {
+				sb.WriteString(prelude)
+				sb.WriteByte(css[open])
+			}
+			i = open + 1
+			continue
+		}
+
+		end := matchingBrace(css, open)
+		body := css[open+1 : end]
+
+		switch {
+		case isGroupingAtRule(prelude):
+			sb.WriteString(prelude)
+			sb.WriteByte('{')
+			sb.WriteString(scopeCSS(body, scope))
+			sb.WriteByte('}')
+		case strings.HasPrefix(prelude, "@"):
+			sb.WriteString(prelude)
+			sb.WriteByte('{')
+			sb.WriteString(strings.TrimSpace(body))
+			sb.WriteByte('}')
+		default:
+			sb.WriteString(scopeSelectorList(prelude, scope))
+			sb.WriteByte('{')
+			sb.WriteString(strings.TrimSpace(body))
+			sb.WriteByte('}')
+		}
+		sb.WriteByte('\n')
+		i = end + 1
+	}
+	return sb.String()
+}
+
+func isGroupingAtRule(prelude string) bool {
+	for _, at := range []string{"@media", "@supports", "@container", "@layer", "@document"} {
+		if strings.HasPrefix(prelude, at) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingBrace returns the index of the '}' closing the '{' at open, or
+// len(css) when it is unbalanced.
+func matchingBrace(css string, open int) int {
+	depth := 0
+	var quote byte
+	for j := open; j < len(css); j++ {
+		c := css[j]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				j++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(css)
+}
+
+func scopeSelectorList(list, scope string) string {
+	var out []string
+	for _, sel := range splitTopLevel(list, ',') {
+		sel = strings.Join(strings.Fields(sel), " ")
+		if sel == "" {
+			continue
+		}
+		out = append(out, scopeSelector(sel, scope)...)
+	}
+	return strings.Join(out, ",")
+}
+
+func scopeSelector(sel, scope string) []string {
+	class := "." + scope
+
+	if strings.HasPrefix(sel, ":global(") {
+		end := closingParen(sel, len(":global"))
+		return []string{strings.TrimSpace(sel[len(":global("):end] + sel[min(end+1, len(sel)):])}
+	}
+	if strings.HasPrefix(sel, ":host") {
+		rest := sel[len(":host"):]
+		if strings.HasPrefix(rest, "(") {
+			end := closingParen(rest, 0)
+			return []string{class + rest[1:end] + rest[min(end+1, len(rest)):]}
+		}
+		return []string{class + rest}
+	}
+
+	// Descendant of the scoped element, or the scoped element itself.
+	first, rest := splitFirstCompound(sel)
+	at := len(first)
+	if k := strings.Index(first, "::"); k >= 0 {
+		at = k
+	}
+	return []string{
+		class + " " + sel,
+		first[:at] + class + first[at:] + rest,
+	}
+}
+
+// splitFirstCompound splits sel after its first compound selector.
+func splitFirstCompound(sel string) (string, string) {
+	depth := 0
+	for i := 0; i < len(sel); i++ {
+		switch sel[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ' ', '>', '+', '~':
+			if depth == 0 {
+				return sel[:i], sel[i:]
+			}
+		}
+	}
+	return sel, ""
+}
+
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// closingParen returns the index of the ')' matching the '(' at open.
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewStylesheet_Scopes(t *testing.T) {
+	s := NewStylesheet("Card", `
+/* comment */
+:host { border: 1px solid }
+.title, .body > p:hover { color: red }
+.icon::before { content: "}" }
+:global(body) .dark { color: white }
+@media (max-width: 600px) { .title { font-size: 1rem } }
+@keyframes spin { from { opacity: 0 } to { opacity: 1 } }
+`)
+	scope := s.Scope()
+	if !strings.HasPrefix(scope, "card-") {
+		t.Fatalf("Scope() = %q, want card- prefix", scope)
+	}
+
+	css := s.CSS()
+	want := []string{
+		"." + scope + "{border: 1px solid}",
+		"." + scope + " .title,.title." + scope + ",",
+		".body." + scope + " > p:hover",
+		".icon." + scope + "::before",
+		`content: "}"`,
+		"body .dark{color: white}",
+		"@media (max-width: 600px){." + scope + " .title",
+		"@keyframes spin{from { opacity: 0 }",
+	}
+	for _, w := range want {
+		if !strings.Contains(css, w) {
+			t.Errorf("CSS() missing %q in:\n%s", w, css)
+		}
+	}
+	if strings.Contains(css, "comment") {
+		t.Error("comments should be stripped")
+	}
+}
+
+func TestStyleCollector_DedupAndInject(t *testing.T) {
+	a := NewStylesheet("a", ".x{color:red}")
+	b := NewStylesheet("b", ".y{color:blue}")
+
+	c := NewStyleCollector()
+	ctx := WithStyleCollector(context.Background(), c)
+	UseStyles(ctx, a, b)
+	UseStyles(ctx, a)
+
+	if got := len(c.Sheets()); got != 2 {
+		t.Fatalf("len(Sheets()) = %d, want 2", got)
+	}
+
+	doc := c.Inject("<html><head><title>t</title></head><body></body></html>")
+	if strings.Count(doc, "<style data-lv-style") != 2 {
+		t.Errorf("expected 2 style tags, got %s", doc)
+	}
+	if strings.Index(doc, a.Scope()) > strings.Index(doc, "</head>") {
+		t.Error("styles should be injected into the head")
+	}
+
+	// Without a collector UseStyles is a no-op.
+	UseStyles(context.Background(), a)
+}
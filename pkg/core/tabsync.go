@@ -0,0 +1,80 @@
+package core
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// SharedEvent carries values shared across the tabs of a browser.
+const SharedEvent = "lv:shared"
+
+// PushShared sets values of the state a browser shares across its tabs,
+// such as an unread count, the theme or when the login expires. The client
+// shows each value in its [lv-shared="key"] elements, hands them to its
+// "lv:shared" listeners and, with the tabSync option, relays them over a
+// BroadcastChannel to the other tabs of the same origin.
+//
+// When the socket belongs to a TabGroup (the client has tabSync on), values
+// already pushed to another tab of the group are left out, so a broadcast
+// every tab's session receives is pushed once instead of once per tab:
+//
+//	func (c *Inbox) HandleInfo(ctx context.Context, msg any) error {
+//	    if b, ok := msg.(core.Broadcast); ok && b.Event == "unread" {
+//	        return c.Socket().PushShared(map[string]any{"unread": b.Payload["count"]})
+//	    }
+//	    return nil
+//	}
+func (s *Socket) PushShared(values map[string]any) error {
+	s.mu.RLock()
+	group := s.tabGroup
+	s.mu.RUnlock()
+	if group != nil {
+		values = group.changed(values)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return s.Push(SharedEvent, values)
+}
+
+// SetTabGroup puts the socket in the group of the tabs of its browser. It
+// is called by the router.
+func (s *Socket) SetTabGroup(g *TabGroup) {
+	s.mu.Lock()
+	s.tabGroup = g
+	s.mu.Unlock()
+}
+
+// TabGroup holds the shared values pushed to the tabs of one browser, which
+// relay them to each other.
+type TabGroup struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// NewTabGroup creates an empty group.
+func NewTabGroup() *TabGroup {
+	return &TabGroup{values: make(map[string]string)}
+}
+
+// changed records values and returns the ones that differ from the values
+// already pushed to the group.
+func (g *TabGroup) changed(values map[string]any) map[string]any {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	out := make(map[string]any, len(values))
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			out[key] = value // Push reports it
+			continue
+		}
+		if prev, ok := g.values[key]; ok && prev == string(data) {
+			continue
+		}
+		g.values[key] = string(data)
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package core
+
+import "testing"
+
+func TestSocket_PushSharedOncePerTabGroup(t *testing.T) {
+	group := NewTabGroup()
+	first, second := NewMockTransport(), NewMockTransport()
+	a, b := NewSocket("a", first), NewSocket("b", second)
+	a.SetTabGroup(group)
+	b.SetTabGroup(group)
+
+	a.PushShared(map[string]any{"unread": 3, "theme": "dark"})
+	b.PushShared(map[string]any{"unread": 3, "theme": "light"})
+
+	if msgs := first.Messages(); len(msgs) != 1 || msgs[0].Event != SharedEvent || len(msgs[0].Payload) != 2 {
+		t.Fatalf("Expected both values on the first tab, got %+v", msgs)
+	}
+	msgs := second.Messages()
+	if len(msgs) != 1 || len(msgs[0].Payload) != 1 || msgs[0].Payload["theme"] != "light" {
+		t.Fatalf("Expected only the changed theme on the second tab, got %+v", msgs)
+	}
+
+	// Sockets without a group get every push
+	c := NewMockTransport()
+	NewSocket("c", c).PushShared(map[string]any{"unread": 3})
+	if len(c.Messages()) != 1 {
+		t.Errorf("Expected the push without a group, got %+v", c.Messages())
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"time"
+)
+
+// SessionTimezoneKey is the session key holding the client's IANA timezone
+// ("Europe/Madrid"), reported by the client when it joins.
+const SessionTimezoneKey = "timezone"
+
+// SessionLocationKey is the session key caching the *time.Location of
+// SessionTimezoneKey, so renders do not load the timezone database.
+const SessionLocationKey = "timezone:location"
+
+// Time styles understood by the client-side formatter.
+const (
+	TimeStyleTime     = "time"     // 15:04:05
+	TimeStyleDate     = "date"     // Jan 2, 2006
+	TimeStyleDateTime = "datetime" // Jan 2, 2006, 15:04
+)
+
+// fallbackLayouts are used for the server-rendered text shown until the
+// client localizes the element (or when JavaScript is disabled).
+var fallbackLayouts = map[string]string{
+	TimeStyleTime:     "15:04:05",
+	TimeStyleDate:     "Jan 2, 2006",
+	TimeStyleDateTime: "Jan 2, 2006 15:04",
+}
+
+// LocalTime renders t as a <time> element that the client reformats in the
+// browser's timezone and locale, so timestamps are not shown in server time.
+func LocalTime(t time.Time) string {
+	return LocalTimeAs(t, TimeStyleDateTime, nil)
+}
+
+// LocalTimeAs renders t with the given style. The fallback text is formatted
+// in loc (typically Session.Location()), or UTC when loc is nil.
+func LocalTimeAs(t time.Time, style string, loc *time.Location) string {
+	layout, ok := fallbackLayouts[style]
+	if !ok {
+		style = TimeStyleDateTime
+		layout = fallbackLayouts[style]
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	fallback := t.In(loc).Format(layout)
+	if loc == time.UTC {
+		fallback += " UTC"
+	}
+
+	return fmt.Sprintf(`<time datetime="%s" lv-localtime="%s">%s</time>`,
+		t.UTC().Format(time.RFC3339),
+		style,
+		html.EscapeString(fallback),
+	)
+}
+
+// SetLocation records the client's timezone, as its name and its cached
+// location.
+func (s Session) SetLocation(loc *time.Location) {
+	s[SessionTimezoneKey] = loc.String()
+	s[SessionLocationKey] = loc
+}
+
+// Location returns the client's timezone captured at join, or UTC if it is
+// unknown or invalid.
+func (s Session) Location() *time.Location {
+	if loc, ok := s[SessionLocationKey].(*time.Location); ok {
+		return loc
+	}
+	// Set by name only, as in tests
+	if name := s.GetString(SessionTimezoneKey); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
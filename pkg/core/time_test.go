@@ -0,0 +1,56 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSession_Location(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip("no timezone database")
+	}
+
+	tests := []struct {
+		name    string
+		session Session
+		want    string
+	}{
+		{"cached at join", func() Session { s := Session{}; s.SetLocation(madrid); return s }(), "Europe/Madrid"},
+		{"valid name", Session{SessionTimezoneKey: "America/New_York"}, "America/New_York"},
+		{"invalid name", Session{SessionTimezoneKey: "Mars/Olympus_Mons"}, "UTC"},
+		{"empty name", Session{SessionTimezoneKey: ""}, "UTC"},
+		{"unknown", Session{}, "UTC"},
+		{"nil session", nil, "UTC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.Location().String(); got != tt.want {
+				t.Errorf("Location() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	s := Session{}
+	s.SetLocation(madrid)
+	if s.Location() != madrid || s.GetString(SessionTimezoneKey) != "Europe/Madrid" {
+		t.Error("Expected SetLocation to cache the location and record its name")
+	}
+}
+
+func TestLocalTimeAs(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	got := LocalTimeAs(at, TimeStyleTime, nil)
+	if !strings.Contains(got, `datetime="2024-03-05T14:30:00Z"`) || !strings.Contains(got, ">14:30:00 UTC<") {
+		t.Errorf("LocalTimeAs(UTC) = %s", got)
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no timezone database")
+	}
+	if got := LocalTimeAs(at, "bogus", tokyo); !strings.Contains(got, `lv-localtime="datetime"`) || !strings.Contains(got, ">Mar 5, 2024 23:30<") {
+		t.Errorf("LocalTimeAs(Tokyo) = %s", got)
+	}
+}
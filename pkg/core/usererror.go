@@ -0,0 +1,55 @@
+package core
+
+import "errors"
+
+// ErrorEvent carries a UserError returned by HandleInfo, which has no
+// event to reply to. UserErrors returned by HandleEvent come in the reply
+// to their event.
+const ErrorEvent = "lv:error"
+
+// EventError is an error meant for the user: a rejected coupon, a taken
+// username. Returned from HandleEvent or HandleInfo, it reaches the client,
+// which shows Message in a toast and each of Fields next to the form field
+// of that name. Other errors are logged and reported to the client only as
+// an internal error.
+type EventError struct {
+	Code    string            // Stable identifier for scripts, such as "coupon_expired"
+	Message string            // Shown to the user
+	Fields  map[string]string // Messages of form fields, keyed by field name
+}
+
+// UserError returns an error shown to the user:
+//
+//	if taken {
+//	    return core.UserError("username_taken", "Check the form",
+//	        map[string]string{"username": "This username is taken"})
+//	}
+func UserError(code, message string, fields map[string]string) *EventError {
+	return &EventError{Code: code, Message: message, Fields: fields}
+}
+
+func (e *EventError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+// Payload returns e as sent to the client.
+func (e *EventError) Payload() map[string]any {
+	payload := map[string]any{
+		"code":    e.Code,
+		"message": e.Message,
+	}
+	if len(e.Fields) > 0 {
+		payload["fields"] = e.Fields
+	}
+	return payload
+}
+
+// AsUserError returns the EventError in err's chain, if any.
+func AsUserError(err error) (*EventError, bool) {
+	var e *EventError
+	ok := errors.As(err, &e)
+	return e, ok
+}
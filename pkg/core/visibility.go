@@ -0,0 +1,87 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// VisibilityEvent is sent by the client when its page is hidden or shown
+// again (the tab is switched, the window minimized), with {"hidden": bool}.
+// The router handles it: while the page is hidden, info messages still
+// reach HandleInfo but their renders are skipped, and SendInterval ticks
+// are paused. Showing the page renders once to catch up.
+const VisibilityEvent = "lv:visibility"
+
+// Hidden reports whether the client's page is hidden.
+func (s *Socket) Hidden() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.shown != nil
+}
+
+// SetHidden records whether the client's page is hidden. It is called by
+// the router on VisibilityEvent.
+func (s *Socket) SetHidden(hidden bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case hidden && s.shown == nil:
+		s.shown = make(chan struct{})
+	case !hidden && s.shown != nil:
+		close(s.shown)
+		s.shown = nil
+	}
+}
+
+// whenShown returns a channel closed when the hidden page is shown again,
+// or nil if it is not hidden.
+func (s *Socket) whenShown() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.shown == nil {
+		return nil
+	}
+	return s.shown
+}
+
+// SendInterval delivers msg to HandleInfo every d, from a goroutine of its
+// own, until stop is called or the socket closes. Ticks are paused while
+// the client's page is hidden: a dashboard left in a background tab costs
+// nothing, and gets one msg to catch up when it is shown again.
+//
+//	func (c *Dashboard) Mount(ctx context.Context, params core.Params, session core.Session) error {
+//	    c.Socket().SendInterval(time.Second, refresh{})
+//	    return nil
+//	}
+func (s *Socket) SendInterval(d time.Duration, msg any) (stop func()) {
+	ticker := time.NewTicker(d)
+	done := make(chan struct{})
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+	s.OnClose(stop)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if shown := s.whenShown(); shown != nil {
+				select {
+				case <-shown:
+					ticker.Reset(d)
+				case <-done:
+					return
+				}
+			}
+			s.SendInfo(msg) // dropped if the queue is full; the next tick catches up
+		}
+	}()
+	return stop
+}
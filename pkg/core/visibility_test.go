@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSocket_SendIntervalPausedWhileHidden(t *testing.T) {
+	socket := NewSocket("test-id", NewMockTransport())
+	defer socket.Close()
+
+	stop := socket.SendInterval(5*time.Millisecond, "tick")
+	defer stop()
+	select {
+	case msg := <-socket.Info():
+		if msg != "tick" {
+			t.Fatalf("got %v, want tick", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no tick while visible")
+	}
+
+	socket.SetHidden(true)
+	if !socket.Hidden() {
+		t.Fatal("Hidden() = false after SetHidden(true)")
+	}
+	time.Sleep(20 * time.Millisecond)
+	for len(socket.Info()) > 0 {
+		<-socket.Info() // a tick racing SetHidden
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := len(socket.Info()); n != 0 {
+		t.Fatalf("%d ticks while hidden, want 0", n)
+	}
+
+	socket.SetHidden(false)
+	select {
+	case <-socket.Info():
+	case <-time.After(time.Second):
+		t.Fatal("no catch-up tick once shown")
+	}
+}
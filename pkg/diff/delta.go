@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// ErrBadDelta is returned by ApplyFullDelta for a delta computed against
+// another base.
+var ErrBadDelta = errors.New("diff: delta does not apply to base")
+
+// deltaBlock is the length of the runs of the base indexed for matching.
+// Shorter runs of a render are sent as text.
+const deltaBlock = 16
+
+// FullDelta returns next as the parts of prev it copies and the text it
+// inserts, so a client holding prev can rebuild next (see core.FullDelta).
+// Offsets count UTF-16 code units, like JavaScript strings.
+func FullDelta(prev, next string) *core.FullDelta {
+	units := utf16Offsets(prev)
+	delta := &core.FullDelta{Base: units[len(prev)]}
+
+	index := make(map[string]int, len(prev)/deltaBlock)
+	for i := 0; i+deltaBlock <= len(prev); i += deltaBlock {
+		if _, ok := index[prev[i:i+deltaBlock]]; !ok {
+			index[prev[i:i+deltaBlock]] = i
+		}
+	}
+
+	inserted := 0 // start of the text not yet copied or inserted
+	for i := 0; i+deltaBlock <= len(next); {
+		at, ok := index[next[i:i+deltaBlock]]
+		if !ok {
+			i++
+			continue
+		}
+
+		// Grow the match both ways, then trim it to whole characters
+		start, end := i, i+deltaBlock
+		from, to := at, at+deltaBlock
+		for start > inserted && from > 0 && next[start-1] == prev[from-1] {
+			start--
+			from--
+		}
+		for end < len(next) && to < len(prev) && next[end] == prev[to] {
+			end++
+			to++
+		}
+		for start < end && !utf8.RuneStart(next[start]) {
+			start++
+			from++
+		}
+		for end > start && (end < len(next) && !utf8.RuneStart(next[end]) || to < len(prev) && !utf8.RuneStart(prev[to])) {
+			end--
+			to--
+		}
+		if end-start < deltaBlock {
+			i++
+			continue
+		}
+
+		if start > inserted {
+			delta.Ops = append(delta.Ops, next[inserted:start])
+		}
+		delta.Ops = append(delta.Ops, units[from], units[to]-units[from])
+		i, inserted = end, end
+	}
+	if inserted < len(next) {
+		delta.Ops = append(delta.Ops, next[inserted:])
+	}
+	return delta
+}
+
+// ApplyFullDelta rebuilds the render delta was computed for from prev.
+// Copies may be ints or, once decoded from JSON, float64s.
+func ApplyFullDelta(prev string, delta *core.FullDelta) (string, error) {
+	base := utf16.Encode([]rune(prev))
+	if len(base) != delta.Base {
+		return "", ErrBadDelta
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(delta.Ops); i++ {
+		if text, ok := delta.Ops[i].(string); ok {
+			sb.WriteString(text)
+			continue
+		}
+		if i+1 == len(delta.Ops) {
+			return "", ErrBadDelta
+		}
+		start, ok1 := deltaInt(delta.Ops[i])
+		length, ok2 := deltaInt(delta.Ops[i+1])
+		if !ok1 || !ok2 || start < 0 || length < 0 || start+length > len(base) {
+			return "", ErrBadDelta
+		}
+		sb.WriteString(string(utf16.Decode(base[start : start+length])))
+		i++
+	}
+	return sb.String(), nil
+}
+
+func deltaInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), n == float64(int(n))
+	}
+	return 0, false
+}
+
+// utf16Offsets maps the byte offsets of the characters of s, and len(s),
+// to UTF-16 offsets. Invalid bytes count as one unit, like the U+FFFD
+// they are sent as.
+func utf16Offsets(s string) []int {
+	units := make([]int, len(s)+1)
+	u := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		units[i] = u
+		u += utf16.RuneLen(r)
+		i += size
+	}
+	units[len(s)] = u
+	return units
+}
@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestFullDelta_RoundTrip(t *testing.T) {
+	lorem := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing. ", 8)
+	accents := strings.Repeat("Él comió ñandú en Córdoba, añoró el café. ", 8)
+	han := strings.Repeat("実時間の画面更新はサーバーで描画されます。", 8)
+	emoji := strings.Repeat("launch 🚀 party 🎉 done 😀 ", 8)
+
+	tests := []struct {
+		name       string
+		prev, next string
+	}{
+		{"identical", lorem, lorem},
+		{"empty prev", "", lorem},
+		{"empty next", lorem, ""},
+		{"both empty", "", ""},
+		{"prefix only", lorem, lorem[:100] + "a new ending that was never there"},
+		{"suffix only", lorem, "a brand new beginning, " + lorem[150:]},
+		{"appended", lorem, lorem + "and more"},
+		{"middle edit", lorem, lorem[:120] + "EDIT" + lorem[130:]},
+		{"multibyte", accents, strings.Replace(accents, "ñandú", "pingüino", 3)},
+		{"multibyte prefix", accents, accents[:len(accents)/2] + "¿qué más?"},
+		{"han", han, strings.Replace(han, "サーバー", "クライアント", 2)},
+		{"astral", emoji, strings.Replace(emoji, "🎉", "🎊🎈", 4)},
+		{"astral suffix", emoji, "👋 " + emoji[len("launch 🚀 "):]},
+		{"mixed", accents + emoji, emoji + accents},
+		{"unrelated", lorem, han},
+	}
+	for _, tt := range tests {
+		delta := FullDelta(tt.prev, tt.next)
+		got, err := ApplyFullDelta(tt.prev, delta)
+		if err != nil {
+			t.Errorf("%s: ApplyFullDelta: %v", tt.name, err)
+			continue
+		}
+		if got != tt.next {
+			t.Errorf("%s: round trip = %q, want %q", tt.name, got, tt.next)
+		}
+
+		// The same delta must apply once decoded from JSON (float64 offsets).
+		data, _ := json.Marshal(delta)
+		var decoded core.FullDelta
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := ApplyFullDelta(tt.prev, &decoded); err != nil || got != tt.next {
+			t.Errorf("%s: round trip from JSON = %q, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestFullDelta_CopiesSharedText(t *testing.T) {
+	prev := strings.Repeat("launch 🚀 party 🎉 done 😀 ", 8)
+
+	delta := FullDelta(prev, prev)
+	if len(delta.Ops) != 2 {
+		t.Fatalf("identical delta ops = %v, want one copy", delta.Ops)
+	}
+	// Offsets count UTF-16 code units: each emoji is a surrogate pair.
+	want := len([]rune(prev)) + 3*8
+	if delta.Base != want || delta.Ops[0] != 0 || delta.Ops[1] != want {
+		t.Errorf("identical delta = %+v, want a copy of [0, %d)", delta, want)
+	}
+
+	next := prev + "!"
+	delta = FullDelta(prev, next)
+	if len(delta.Ops) != 3 || delta.Ops[2] != "!" {
+		t.Errorf("appended delta ops = %v, want a copy then %q", delta.Ops, "!")
+	}
+
+	// Texts shorter than a block are sent whole.
+	delta = FullDelta("short", "short")
+	if len(delta.Ops) != 1 || delta.Ops[0] != "short" {
+		t.Errorf("short delta ops = %v, want the text", delta.Ops)
+	}
+}
+
+func TestApplyFullDelta_BadDelta(t *testing.T) {
+	prev := "héllo 😀"
+	base := 8 // h é l l o space + surrogate pair
+
+	tests := []struct {
+		name  string
+		delta *core.FullDelta
+	}{
+		{"wrong base", &core.FullDelta{Base: len(prev), Ops: []any{0, 1}}},
+		{"copy past end", &core.FullDelta{Base: base, Ops: []any{4, 5}}},
+		{"negative start", &core.FullDelta{Base: base, Ops: []any{-1, 2}}},
+		{"missing length", &core.FullDelta{Base: base, Ops: []any{"x", 0}}},
+		{"fractional", &core.FullDelta{Base: base, Ops: []any{0.5, 1.0}}},
+		{"bad type", &core.FullDelta{Base: base, Ops: []any{true, 1}}},
+	}
+	for _, tt := range tests {
+		if _, err := ApplyFullDelta(prev, tt.delta); !errors.Is(err, ErrBadDelta) {
+			t.Errorf("%s: err = %v, want ErrBadDelta", tt.name, err)
+		}
+	}
+
+	got, err := ApplyFullDelta(prev, &core.FullDelta{Base: base, Ops: []any{6, 2, "!", 0, 1}})
+	if err != nil || got != "😀!h" {
+		t.Errorf("ApplyFullDelta = %q, %v, want %q", got, err, "😀!h")
+	}
+}
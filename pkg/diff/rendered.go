@@ -0,0 +1,290 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Markers delimit the dynamic parts of a render, so it can be split into
+// the statics of its template and the dynamics of its data (see
+// ParseMarked). They are Unicode noncharacters, which never occur in
+// interchanged text.
+const (
+	MarkDynamicStart = "\uFDD0"
+	MarkDynamicEnd   = "\uFDD1"
+	MarkListStart    = "\uFDD2"
+	MarkListEnd      = "\uFDD3"
+	MarkRowStart     = "\uFDD4"
+	MarkRowEnd       = "\uFDD5"
+)
+
+// ErrMalformedMarkers is returned by ParseMarked for output whose markers
+// do not nest.
+var ErrMalformedMarkers = errors.New("diff: malformed render markers")
+
+// Rendered is a render split like Phoenix's ~H templates: the Statics of
+// the template around its Dynamics, which are strings, *Rendered or
+// *Comprehension. len(Statics) == len(Dynamics)+1.
+//
+// Once a client holds a Rendered, a change is sent as the dynamics that
+// changed (DiffRendered) instead of the whole HTML.
+type Rendered struct {
+	Statics  []string
+	Dynamics []any
+}
+
+// Comprehension is the output of a loop: the statics of its body, shared
+// by all rows, and the dynamics of each row.
+type Comprehension struct {
+	Statics []string
+	Rows    [][]any
+}
+
+// TreeRenderer is implemented by renderers that can split their output per
+// data-slot (templates.LiveTemplate). RenderTrees writes the HTML like
+// Render and returns the Rendered of the slots that have dynamics.
+type TreeRenderer interface {
+	RenderTrees(ctx context.Context, w io.Writer) (map[string]*Rendered, error)
+}
+
+// HTML returns the HTML of the render.
+func (r *Rendered) HTML() string {
+	var sb strings.Builder
+	writeParts(&sb, r.Statics, r.Dynamics)
+	return sb.String()
+}
+
+func writeParts(sb *strings.Builder, statics []string, dynamics []any) {
+	for i, s := range statics {
+		sb.WriteString(s)
+		if i < len(dynamics) {
+			writeDynamic(sb, dynamics[i])
+		}
+	}
+}
+
+func writeDynamic(sb *strings.Builder, d any) {
+	switch v := d.(type) {
+	case string:
+		sb.WriteString(v)
+	case *Rendered:
+		writeParts(sb, v.Statics, v.Dynamics)
+	case *Comprehension:
+		for _, row := range v.Rows {
+			writeParts(sb, v.Statics, row)
+		}
+	}
+}
+
+// Encode returns the wire form of the render:
+//
+//	{"s": [statics], "0": dynamic, "1": dynamic, ...}
+//
+// where a dynamic is a string, an encoded Rendered, or an encoded
+// Comprehension {"s": [statics], "d": [[dynamics of a row], ...]}.
+func (r *Rendered) Encode() map[string]any {
+	out := make(map[string]any, len(r.Dynamics)+1)
+	out["s"] = r.Statics
+	for i, d := range r.Dynamics {
+		out[strconv.Itoa(i)] = encodeDynamic(d)
+	}
+	return out
+}
+
+func encodeDynamic(d any) any {
+	switch v := d.(type) {
+	case *Rendered:
+		return v.Encode()
+	case *Comprehension:
+		return map[string]any{"s": v.Statics, "d": encodeRows(v.Rows)}
+	}
+	return d
+}
+
+func encodeRows(rows [][]any) [][]any {
+	out := make([][]any, len(rows))
+	for i, row := range rows {
+		out[i] = make([]any, len(row))
+		for j, d := range row {
+			out[i][j] = encodeDynamic(d)
+		}
+	}
+	return out
+}
+
+// DiffRendered returns what a client holding prev needs to reach next:
+// the encoded dynamics that changed, keyed by index, with nested renders
+// diffed the same way. Renders of other statics are sent whole (with
+// "s"), which replaces what the client holds. prev nil sends next whole.
+// It returns false when nothing changed.
+func DiffRendered(prev, next *Rendered) (map[string]any, bool) {
+	if prev == nil || !slices.Equal(prev.Statics, next.Statics) {
+		return next.Encode(), true
+	}
+	out := make(map[string]any)
+	for i, d := range next.Dynamics {
+		if change, ok := diffDynamic(prev.Dynamics[i], d); ok {
+			out[strconv.Itoa(i)] = change
+		}
+	}
+	return out, len(out) > 0
+}
+
+func diffDynamic(prev, next any) (any, bool) {
+	switch n := next.(type) {
+	case string:
+		if p, ok := prev.(string); ok && p == n {
+			return nil, false
+		}
+		return n, true
+	case *Rendered:
+		if p, ok := prev.(*Rendered); ok {
+			return DiffRendered(p, n)
+		}
+	case *Comprehension:
+		if p, ok := prev.(*Comprehension); ok && slices.Equal(p.Statics, n.Statics) {
+			if rowsEqual(p.Rows, n.Rows) {
+				return nil, false
+			}
+			// Rows are sent whole: the statics of their body are not
+			return map[string]any{"d": encodeRows(n.Rows)}, true
+		}
+	}
+	return encodeDynamic(next), true
+}
+
+func rowsEqual(a, b [][]any) bool {
+	return slices.EqualFunc(a, b, func(x, y []any) bool {
+		return slices.EqualFunc(x, y, dynamicEqual)
+	})
+}
+
+func dynamicEqual(a, b any) bool {
+	_, changed := diffDynamic(a, b)
+	return !changed
+}
+
+// ParseMarked splits output written with markers into a Rendered: text
+// outside markers is static, text between MarkDynamicStart and
+// MarkDynamicEnd is a dynamic, and a list (MarkListStart ... MarkListEnd)
+// of rows (MarkRowStart ... MarkRowEnd) is a Comprehension whose rows must
+// share their statics.
+func ParseMarked(s string) (*Rendered, error) {
+	p := markedParser{s: s}
+	r, err := p.sequence()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, ErrMalformedMarkers
+	}
+	return r, nil
+}
+
+// StripMarkers returns s without markers: the HTML of a marked render.
+func StripMarkers(s string) string {
+	return markerReplacer.Replace(s)
+}
+
+var markerReplacer = strings.NewReplacer(
+	MarkDynamicStart, "", MarkDynamicEnd, "",
+	MarkListStart, "", MarkListEnd, "",
+	MarkRowStart, "", MarkRowEnd, "",
+)
+
+type markedParser struct {
+	s   string
+	pos int
+}
+
+// next returns the position of the next marker and the marker, or
+// len(s) and "".
+func (p *markedParser) next() (int, string) {
+	for i := p.pos; i < len(p.s); i++ {
+		// The markers are U+FDD0-U+FDD5: EF B7 90-95 in UTF-8
+		if p.s[i] == 0xEF && i+2 < len(p.s) && p.s[i+1] == 0xB7 && p.s[i+2] >= 0x90 && p.s[i+2] <= 0x95 {
+			return i, p.s[i : i+3]
+		}
+	}
+	return len(p.s), ""
+}
+
+// sequence parses statics and dynamics until a marker closing a row or
+// the end of the input.
+func (p *markedParser) sequence() (*Rendered, error) {
+	r := &Rendered{}
+	var static strings.Builder
+	for {
+		at, marker := p.next()
+		static.WriteString(p.s[p.pos:at])
+		p.pos = at
+
+		switch marker {
+		case "", MarkRowEnd, MarkListEnd, MarkDynamicEnd:
+			r.Statics = append(r.Statics, static.String())
+			return r, nil
+
+		case MarkDynamicStart:
+			p.pos += len(marker)
+			end, closing := p.next()
+			if closing != MarkDynamicEnd {
+				return nil, ErrMalformedMarkers
+			}
+			r.Statics = append(r.Statics, static.String())
+			r.Dynamics = append(r.Dynamics, p.s[p.pos:end])
+			static.Reset()
+			p.pos = end + len(closing)
+
+		case MarkListStart:
+			p.pos += len(marker)
+			c, err := p.comprehension()
+			if err != nil {
+				return nil, err
+			}
+			r.Statics = append(r.Statics, static.String())
+			r.Dynamics = append(r.Dynamics, c)
+			static.Reset()
+
+		default: // MarkRowStart outside a list
+			return nil, ErrMalformedMarkers
+		}
+	}
+}
+
+// comprehension parses rows until MarkListEnd.
+func (p *markedParser) comprehension() (*Comprehension, error) {
+	c := &Comprehension{}
+	for {
+		at, marker := p.next()
+		if at != p.pos {
+			return nil, ErrMalformedMarkers // text between rows
+		}
+		switch marker {
+		case MarkListEnd:
+			p.pos += len(marker)
+			return c, nil
+		case MarkRowStart:
+			p.pos += len(marker)
+			row, err := p.sequence()
+			if err != nil {
+				return nil, err
+			}
+			if _, closing := p.next(); closing != MarkRowEnd {
+				return nil, ErrMalformedMarkers
+			}
+			p.pos += len(MarkRowEnd)
+			if len(c.Rows) == 0 {
+				c.Statics = row.Statics
+			} else if !slices.Equal(c.Statics, row.Statics) {
+				return nil, ErrMalformedMarkers
+			}
+			c.Rows = append(c.Rows, row.Dynamics)
+		default:
+			return nil, ErrMalformedMarkers
+		}
+	}
+}
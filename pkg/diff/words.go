@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// maxWordEdits bounds the word diff of SpliceText: text that changed in
+// more places is sent whole.
+const maxWordEdits = 64
+
+// SpliceText returns the splices turning prev into next, found by a diff
+// of their words (see core.TextSplice). It returns false when the texts
+// differ in too many places for splices to pay off.
+func SpliceText(prev, next string) ([]core.TextSplice, bool) {
+	a, b := words(prev), words(next)
+
+	// Only the middle of an edited text takes the diff
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	end := 0
+	for end < len(a)-start && end < len(b)-start && a[len(a)-1-end] == b[len(b)-1-end] {
+		end++
+	}
+	hunks, ok := diffWords(a[start:len(a)-end], b[start:len(b)-end])
+	if !ok {
+		return nil, false
+	}
+
+	// Positions are in UTF-16 code units of prev, like JavaScript strings
+	units := utf16Offsets(prev)
+	offsets := make([]int, len(a)+1)
+	pos := 0
+	for i, w := range a {
+		offsets[i] = units[pos]
+		pos += len(w)
+	}
+	offsets[len(a)] = units[len(prev)]
+
+	splices := make([]core.TextSplice, 0, len(hunks))
+	for _, h := range hunks {
+		from, to := offsets[start+h.a0], offsets[start+h.a1]
+		splices = append(splices, core.TextSplice{
+			Pos:  from,
+			Del:  to - from,
+			Text: strings.Join(b[start+h.b0:start+h.b1], ""),
+		})
+	}
+	return splices, true
+}
+
+// words splits s into runs of spaces and runs of other characters.
+func words(s string) []string {
+	var out []string
+	start := 0
+	space := false
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i > start && isSpace != space {
+			out = append(out, s[start:i])
+			start = i
+		}
+		space = isSpace
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
+// hunk replaces a[a0:a1] with b[b0:b1].
+type hunk struct {
+	a0, a1, b0, b1 int
+}
+
+// diffWords finds the hunks turning a into b with Myers' algorithm, giving
+// up after maxWordEdits inserted or deleted words.
+func diffWords(a, b []string) ([]hunk, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxWordEdits)
+	off := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			x := v[off+k-1] + 1
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return hunks(trace, off, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// hunks walks the trace of diffWords back from (n, m) and returns the
+// runs between the words a and b share.
+func hunks(trace [][]int, off, n, m int) []hunk {
+	type pair struct{ x, y int }
+	var same []pair
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+			prevK = k + 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = v[off+prevK]
+		}
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			same = append(same, pair{x, y})
+		}
+		x, y = prevX, prevY
+	}
+
+	var out []hunk
+	ai, bi := 0, 0
+	for i := len(same) - 1; i >= 0; i-- {
+		p := same[i]
+		if p.x > ai || p.y > bi {
+			out = append(out, hunk{ai, p.x, bi, p.y})
+		}
+		ai, bi = p.x+1, p.y+1
+	}
+	if ai < n || bi < m {
+		out = append(out, hunk{ai, n, bi, m})
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// applySplices applies splices like the client, on UTF-16 code units.
+func applySplices(text string, splices []core.TextSplice) string {
+	units := utf16.Encode([]rune(text))
+	for i := len(splices) - 1; i >= 0; i-- {
+		sp := splices[i]
+		ins := utf16.Encode([]rune(sp.Text))
+		units = append(units[:sp.Pos], append(ins, units[sp.Pos+sp.Del:]...)...)
+	}
+	return string(utf16.Decode(units))
+}
+
+func TestSpliceText(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		want       []core.TextSplice
+	}{
+		{"identical", "one two three", "one two three", []core.TextSplice{}},
+		{"insert only", "one three", "one two three", []core.TextSplice{{Pos: 4, Text: "two "}}},
+		{"delete only", "one two three", "one three", []core.TextSplice{{Pos: 4, Del: 4}}},
+		{"replace", "one two three", "one 2 three", []core.TextSplice{{Pos: 4, Del: 3, Text: "2"}}},
+		{"at start", "one two three", "zero two three", []core.TextSplice{{Pos: 0, Del: 3, Text: "zero"}}},
+		{"at end", "one two three", "one two four", []core.TextSplice{{Pos: 8, Del: 5, Text: "four"}}},
+		{
+			"both ends",
+			"one two three four",
+			"uno two three cuatro",
+			[]core.TextSplice{{Pos: 0, Del: 3, Text: "uno"}, {Pos: 14, Del: 4, Text: "cuatro"}},
+		},
+		{"whitespace only", "one two three", "one  two\nthree", []core.TextSplice{{Pos: 3, Del: 1, Text: "  "}, {Pos: 7, Del: 1, Text: "\n"}}},
+		{"trailing space", "one two", "one two ", []core.TextSplice{{Pos: 7, Text: " "}}},
+		{"from empty", "", "one two", []core.TextSplice{{Pos: 0, Text: "one two"}}},
+		{"to empty", "one two", "", []core.TextSplice{{Pos: 0, Del: 7}}},
+		{"astral", "😀 one two", "😀 one 🎉 two", []core.TextSplice{{Pos: 7, Text: "🎉 "}}},
+		{"kept space splits hunks", "a b c d", "a x y d", []core.TextSplice{{Pos: 2, Del: 1, Text: "x"}, {Pos: 4, Del: 1, Text: "y"}}},
+		{"adjacent words merge", "a b c d", "a x-y d", []core.TextSplice{{Pos: 2, Del: 3, Text: "x-y"}}},
+	}
+	for _, tt := range tests {
+		got, ok := SpliceText(tt.prev, tt.next)
+		if !ok {
+			t.Errorf("%s: SpliceText gave up", tt.name)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: SpliceText = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if applied := applySplices(tt.prev, got); applied != tt.next {
+			t.Errorf("%s: applied splices = %q, want %q", tt.name, applied, tt.next)
+		}
+	}
+}
+
+func TestSpliceText_EditLimit(t *testing.T) {
+	// Every replaced word is one deletion and one insertion.
+	text := func(n, replaced int) string {
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			if i%2 == 1 && i/2 < replaced {
+				fmt.Fprintf(&sb, "new%d ", i)
+			} else {
+				fmt.Fprintf(&sb, "w%d ", i)
+			}
+		}
+		return sb.String()
+	}
+
+	prev := text(200, 0)
+	within := text(200, maxWordEdits/2)
+	splices, ok := SpliceText(prev, within)
+	if !ok {
+		t.Fatalf("SpliceText gave up on %d edits", maxWordEdits)
+	}
+	if len(splices) != maxWordEdits/2 || applySplices(prev, splices) != within {
+		t.Errorf("SpliceText = %d splices, want %d applying to next", len(splices), maxWordEdits/2)
+	}
+
+	if splices, ok := SpliceText(prev, text(200, maxWordEdits/2+1)); ok {
+		t.Errorf("SpliceText past the limit = %d splices, want fallback", len(splices))
+	}
+}
+
+func TestDiffWords(t *testing.T) {
+	split := strings.Fields
+	tests := []struct {
+		a, b string
+		want []hunk
+	}{
+		{"", "", nil},
+		{"x y z", "x y z", nil},
+		{"x z", "x y z", []hunk{{1, 1, 1, 2}}},
+		{"x y z", "x z", []hunk{{1, 2, 1, 1}}},
+		{"x y z", "q y r", []hunk{{0, 1, 0, 1}, {2, 3, 2, 3}}},
+		{"x y z", "p q", []hunk{{0, 3, 0, 2}}},
+	}
+	for _, tt := range tests {
+		got, ok := diffWords(split(tt.a), split(tt.b))
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("diffWords(%q, %q) = %v, %v, want %v", tt.a, tt.b, got, ok, tt.want)
+		}
+	}
+
+	// Disjoint texts need len(a)+len(b) edits.
+	disjoint := func(n int, prefix string) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf("%s%d", prefix, i)
+		}
+		return out
+	}
+	half := maxWordEdits / 2
+	if got, ok := diffWords(disjoint(half, "a"), disjoint(half, "b")); !ok || len(got) != 1 {
+		t.Errorf("diffWords at the limit = %v, %v, want one hunk", got, ok)
+	}
+	if _, ok := diffWords(disjoint(half+1, "a"), disjoint(half, "b")); ok {
+		t.Error("diffWords past the limit succeeded")
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := words("  one two\n\tthree ")
+	want := []string{"  ", "one", " ", "two", "\n\t", "three", " "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("words = %q, want %q", got, want)
+	}
+	if got := words(""); got != nil {
+		t.Errorf("words(\"\") = %q, want nil", got)
+	}
+}
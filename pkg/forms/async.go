@@ -0,0 +1,200 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// ErrValidationPending is returned by Apply while a ValidateAsync check has
+// not finished.
+var ErrValidationPending = errors.New("validation pending")
+
+// DefaultDebounce is how long ValidateAsync waits for the user to stop
+// typing before running its check.
+const DefaultDebounce = 300 * time.Millisecond
+
+// AsyncCheck checks a field value against something slow, such as a
+// database lookup. The returned error's message is added to the field. ctx
+// is cancelled when a newer value supersedes this one or the socket closes.
+type AsyncCheck func(ctx context.Context, value any) error
+
+// AsyncOption configures ValidateAsync.
+type AsyncOption func(*asyncOptions)
+
+type asyncOptions struct {
+	debounce time.Duration
+}
+
+// Debounce sets how long ValidateAsync waits before running the check.
+// Zero runs it right away and waits for the result, as a submit handler
+// should.
+func Debounce(d time.Duration) AsyncOption {
+	return func(o *asyncOptions) { o.debounce = d }
+}
+
+// ValidateAsync runs check on field without blocking the event. While it is
+// debounced or running, Checking(field) is true, so the template can show a
+// spinner; when it returns, its error (if any) is added to the field and the
+// component is re-rendered. The component must keep this changeset in its
+// state for the result to show.
+//
+//	case "validate":
+//	    c.Form = forms.Cast(nil, payload, fields).
+//	        ValidateRequired("username").
+//	        ValidateAsync(ctx, "username", c.usernameFree)
+//
+// A newer ValidateAsync for the same field on the same socket cancels the
+// earlier check, so a check runs only once the user pauses. Empty values and
+// fields that already have errors are not checked. Without a connected
+// socket in ctx (the static render, tests) or with Debounce(0), check runs
+// synchronously.
+//
+// Apply returns ErrValidationPending while a check is running.
+func (cs *Changeset) ValidateAsync(ctx context.Context, field string, check AsyncCheck, opts ...AsyncOption) *Changeset {
+	o := asyncOptions{debounce: DefaultDebounce}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var checks *socketChecks
+	socket := core.SocketFromContext(ctx)
+	if socket != nil && socket.IsConnected() {
+		checks = checksFor(socket)
+		checks.cancel(field)
+	}
+
+	value := cs.GetField(field)
+	if isEmpty(value) || cs.HasError(field) {
+		return cs
+	}
+
+	if checks == nil || o.debounce <= 0 {
+		if err := check(ctx, value); err != nil {
+			cs.AddError(field, err.Error())
+		}
+		return cs
+	}
+
+	if cs.checking == nil {
+		cs.checking = make(map[string]bool)
+	}
+	cs.checking[field] = true
+
+	checkCtx, cancel := context.WithCancel(context.Background())
+	id := checks.start(field, cancel)
+	go func() {
+		defer cancel()
+		timer := time.NewTimer(o.debounce)
+		select {
+		case <-checkCtx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		err := check(checkCtx, value)
+		if checkCtx.Err() != nil {
+			return
+		}
+		socket.PushUpdate(func() {
+			if !checks.finish(field, id) {
+				return // superseded while the update was queued
+			}
+			delete(cs.checking, field)
+			if err != nil {
+				cs.AddError(field, err.Error())
+			}
+		})
+	}()
+	return cs
+}
+
+// Checking reports whether a ValidateAsync check on field is still running.
+func (cs *Changeset) Checking(field string) bool {
+	return cs.checking[field]
+}
+
+// Pending reports whether any ValidateAsync check is still running.
+func (cs *Changeset) Pending() bool {
+	return len(cs.checking) > 0
+}
+
+// CheckingFields returns the fields with a check still running, sorted.
+func (cs *Changeset) CheckingFields() []string {
+	fields := make([]string, 0, len(cs.checking))
+	for field := range cs.checking {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// socketChecks tracks the running checks of one socket by field, so a new
+// check cancels the one it supersedes.
+type socketChecks struct {
+	mu      sync.Mutex
+	next    uint64
+	running map[string]asyncRun
+}
+
+type asyncRun struct {
+	id     uint64
+	cancel context.CancelFunc
+}
+
+const checksKey = "forms:async_checks"
+
+// checksFor returns the checks of socket. It is called from the session's
+// message loop, which serializes the lookup.
+func checksFor(socket *core.Socket) *socketChecks {
+	if checks, ok := socket.GetMetadata(checksKey).(*socketChecks); ok {
+		return checks
+	}
+	checks := &socketChecks{running: make(map[string]asyncRun)}
+	socket.SetMetadata(checksKey, checks)
+	socket.OnClose(checks.cancelAll)
+	return checks
+}
+
+func (c *socketChecks) start(field string, cancel context.CancelFunc) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	c.running[field] = asyncRun{id: c.next, cancel: cancel}
+	return c.next
+}
+
+// finish removes check id of field, reporting false if a later check
+// replaced it.
+func (c *socketChecks) finish(field string, id uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if run, ok := c.running[field]; !ok || run.id != id {
+		return false
+	}
+	delete(c.running, field)
+	return true
+}
+
+func (c *socketChecks) cancel(field string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if run, ok := c.running[field]; ok {
+		run.cancel()
+		delete(c.running, field)
+	}
+}
+
+func (c *socketChecks) cancelAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for field, run := range c.running {
+		run.cancel()
+		delete(c.running, field)
+	}
+}
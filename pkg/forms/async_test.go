@@ -0,0 +1,79 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+type nopTransport struct{}
+
+func (nopTransport) Send(core.Message) error { return nil }
+func (nopTransport) Close() error            { return nil }
+func (nopTransport) IsConnected() bool       { return true }
+
+var errTaken = errors.New("is already taken")
+
+func usernameFree(ctx context.Context, value any) error {
+	if value == "admin" {
+		return errTaken
+	}
+	return nil
+}
+
+func TestValidateAsync_WithoutSocket(t *testing.T) {
+	cs := Cast(nil, map[string]any{"username": "admin"}, []string{"username"}).
+		ValidateAsync(context.Background(), "username", usernameFree)
+
+	if cs.Pending() || cs.FirstError("username") != errTaken.Error() {
+		t.Fatalf("pending = %v, errors = %v", cs.Pending(), cs.Errors)
+	}
+}
+
+func TestValidateAsync_Debounced(t *testing.T) {
+	socket := core.NewSocket("test-id", nopTransport{})
+	defer socket.Close()
+	ctx := core.WithSocket(context.Background(), socket)
+
+	calls := make(chan any, 4)
+	check := func(ctx context.Context, value any) error {
+		calls <- value
+		return usernameFree(ctx, value)
+	}
+
+	first := Cast(nil, map[string]any{"username": "adm"}, []string{"username"}).
+		ValidateAsync(ctx, "username", check, Debounce(20*time.Millisecond))
+	cs := Cast(nil, map[string]any{"username": "admin"}, []string{"username"}).
+		ValidateAsync(ctx, "username", check, Debounce(20*time.Millisecond))
+
+	if !first.Checking("username") || !cs.Checking("username") {
+		t.Fatal("Checking = false while the check is debounced")
+	}
+	if _, err := cs.Apply(); !errors.Is(err, ErrValidationPending) {
+		t.Fatalf("Apply err = %v, want ErrValidationPending", err)
+	}
+
+	select {
+	case info := <-socket.Info():
+		req, ok := info.(core.UpdateRequest)
+		if !ok {
+			t.Fatalf("info = %T, want core.UpdateRequest", info)
+		}
+		req.Run()
+	case <-time.After(time.Second):
+		t.Fatal("check result was not pushed")
+	}
+
+	if got := <-calls; got != "admin" {
+		t.Errorf("checked %v, want only the latest value", got)
+	}
+	if len(calls) != 0 {
+		t.Errorf("superseded value was checked too")
+	}
+	if cs.Checking("username") || cs.FirstError("username") != errTaken.Error() {
+		t.Errorf("checking = %v, errors = %v", cs.Checking("username"), cs.Errors)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/uploads"
 )
 
 // Changeset provides an Ecto-inspired way to manage form data and validation.
@@ -26,6 +28,26 @@ type Changeset struct {
 
 	// Required fields that must be present.
 	required []string
+
+	// params are the raw input given to Cast.
+	params map[string]any
+
+	// Fields with a ValidateAsync check still running, or files still
+	// uploading (see CastUploads).
+	checking map[string]bool
+
+	// Uploaders of the file fields, by field (see CastUploads).
+	uploaders map[string]*uploads.Uploader
+
+	// Changesets of the embedded values, by field (see CastEmbedded and
+	// CastEmbeddedList).
+	embeds     map[string]*Changeset
+	embedLists map[string][]*Changeset
+
+	// Optimistic locking state set by ValidateVersion.
+	lockField   string
+	lockVersion int
+	stale       bool
 }
 
 // NewChangeset creates a new changeset from existing data.
@@ -47,6 +69,7 @@ func NewChangeset(data map[string]any) *Changeset {
 // Only fields in the allowed list are included.
 func Cast(data, params map[string]any, allowed []string) *Changeset {
 	cs := NewChangeset(data)
+	cs.params = params
 
 	allowedSet := make(map[string]bool)
 	for _, field := range allowed {
@@ -273,10 +296,11 @@ func (cs *Changeset) FirstError(field string) string {
 	return ""
 }
 
-// ErrorMessages returns all errors as a single string.
+// ErrorMessages returns all errors, including those of embedded changesets,
+// as a single string.
 func (cs *Changeset) ErrorMessages() string {
 	var msgs []string
-	for field, errs := range cs.Errors {
+	for field, errs := range cs.AllErrors() {
 		for _, err := range errs {
 			msgs = append(msgs, fmt.Sprintf("%s %s", field, err))
 		}
@@ -287,31 +311,24 @@ func (cs *Changeset) ErrorMessages() string {
 // Apply returns the merged data with changes.
 // Returns error if changeset is invalid.
 func (cs *Changeset) Apply() (map[string]any, error) {
-	if !cs.Valid {
-		return nil, fmt.Errorf("changeset is invalid: %s", cs.ErrorMessages())
-	}
-
-	result := make(map[string]any)
-	for k, v := range cs.Data {
-		result[k] = v
-	}
-	for k, v := range cs.Changes {
-		result[k] = v
+	if err := cs.invalidError(); err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return cs.merged(), nil
 }
 
 // ApplyChanges returns changes only (for partial updates).
 func (cs *Changeset) ApplyChanges() (map[string]any, error) {
-	if !cs.Valid {
-		return nil, fmt.Errorf("changeset is invalid: %s", cs.ErrorMessages())
+	if err := cs.invalidError(); err != nil {
+		return nil, err
 	}
 
 	result := make(map[string]any)
 	for k, v := range cs.Changes {
 		result[k] = v
 	}
+	cs.embedChanges(result)
 
 	return result, nil
 }
@@ -0,0 +1,187 @@
+package forms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Standard errors for concurrent edits and soft-deleted records. Use
+// errors.Is to detect them and render StaleEntryMessage / DeletedEntryMessage.
+var (
+	ErrStaleEntry   = errors.New("stale entry")
+	ErrEntryDeleted = errors.New("entry deleted")
+)
+
+// Messages added to the changeset when a concurrency check fails.
+const (
+	StaleEntryMessage   = "was changed by someone else. Reload to see the latest version."
+	DeletedEntryMessage = "has been deleted"
+)
+
+// DeletedAtField is the conventional soft-delete timestamp field.
+const DeletedAtField = "deleted_at"
+
+// Soft-delete changeset actions.
+const (
+	ActionSoftDelete = "soft_delete"
+	ActionRestore    = "restore"
+)
+
+// StaleEntryError reports an optimistic locking conflict: the record was
+// updated after the user loaded it.
+type StaleEntryError struct {
+	Field    string
+	Expected int // version the user edited
+	Actual   int // version currently stored
+}
+
+func (e *StaleEntryError) Error() string {
+	return fmt.Sprintf("stale entry: %s is %d, expected %d", e.Field, e.Actual, e.Expected)
+}
+
+// Is makes errors.Is(err, ErrStaleEntry) match.
+func (e *StaleEntryError) Is(target error) bool {
+	return target == ErrStaleEntry
+}
+
+// ValidateVersion implements optimistic locking on field (e.g. "lock_version").
+// Data holds the stored version and the submitted form carries the version
+// the user loaded, typically in a hidden input. On a match the version is
+// bumped in Changes; otherwise the changeset gets StaleEntryMessage on field
+// and Apply returns a *StaleEntryError. A form that omits the version of a
+// stored record is rejected with "is required", so dropping the field cannot
+// bypass the check; new records without a version start at 1.
+//
+// Repositories should still guard the write ("... WHERE lock_version = ?")
+// using LockVersion, since another update can land after validation.
+func (cs *Changeset) ValidateVersion(field string) *Changeset {
+	current, _ := toInt(cs.Data[field])
+
+	submitted, ok := cs.params[field]
+	if !ok {
+		submitted, ok = cs.Changes[field]
+	}
+	if !ok {
+		if cs.Data[field] != nil {
+			// The user must say which version they edited
+			return cs.AddError(field, "is required")
+		}
+		// A new record: nothing to compare against
+		cs.lockField, cs.lockVersion = field, current
+		cs.Changes[field] = current + 1
+		return cs
+	}
+
+	expected, ok := toInt(submitted)
+	if !ok || expected != current {
+		cs.stale = true
+		cs.lockField, cs.lockVersion = field, expected
+		delete(cs.Changes, field)
+		return cs.AddError(field, StaleEntryMessage)
+	}
+
+	cs.lockField, cs.lockVersion = field, current
+	cs.Changes[field] = current + 1
+	return cs
+}
+
+// LockVersion returns the field and version a repository must match when
+// writing this changeset. ok is false if ValidateVersion was not called.
+func (cs *Changeset) LockVersion() (field string, version int, ok bool) {
+	return cs.lockField, cs.lockVersion, cs.lockField != ""
+}
+
+// IsStale reports whether ValidateVersion detected a conflicting edit.
+func (cs *Changeset) IsStale() bool {
+	return cs.stale
+}
+
+// MarkStale records a conflict detected at write time (the repository's
+// guarded update matched no rows) so the UI renders it like a validation error.
+func (cs *Changeset) MarkStale(actual int) *Changeset {
+	if cs.lockField == "" {
+		return cs
+	}
+	cs.stale = true
+	cs.Data[cs.lockField] = actual
+	delete(cs.Changes, cs.lockField)
+	return cs.AddError(cs.lockField, StaleEntryMessage)
+}
+
+// SoftDelete marks the record deleted by setting DeletedAtField instead of
+// removing it.
+func (cs *Changeset) SoftDelete() *Changeset {
+	cs.Action = ActionSoftDelete
+	cs.Changes[DeletedAtField] = time.Now().UTC()
+	return cs
+}
+
+// Restore clears DeletedAtField on a soft-deleted record.
+func (cs *Changeset) Restore() *Changeset {
+	cs.Action = ActionRestore
+	cs.Changes[DeletedAtField] = nil
+	return cs
+}
+
+// ValidateNotDeleted rejects edits to a soft-deleted record.
+func (cs *Changeset) ValidateNotDeleted() *Changeset {
+	if IsDeleted(cs.Data) {
+		cs.AddError(DeletedAtField, DeletedEntryMessage)
+	}
+	return cs
+}
+
+// IsDeleted reports whether a record has a non-empty DeletedAtField.
+func IsDeleted(data map[string]any) bool {
+	switch v := data[DeletedAtField].(type) {
+	case nil:
+		return false
+	case time.Time:
+		return !v.IsZero()
+	case *time.Time:
+		return v != nil && !v.IsZero()
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// invalidError returns the error Apply reports for an invalid changeset.
+func (cs *Changeset) invalidError() error {
+	if cs.Pending() {
+		return fmt.Errorf("%w: %s", ErrValidationPending, strings.Join(cs.CheckingFields(), ", "))
+	}
+	if cs.Valid {
+		return nil
+	}
+	if cs.stale {
+		expected := cs.lockVersion
+		actual, _ := toInt(cs.Data[cs.lockField])
+		return &StaleEntryError{Field: cs.lockField, Expected: expected, Actual: actual}
+	}
+	if cs.HasError(DeletedAtField) {
+		return fmt.Errorf("%w: %s", ErrEntryDeleted, cs.ErrorMessages())
+	}
+	return fmt.Errorf("changeset is invalid: %s", cs.ErrorMessages())
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	case nil:
+		return 0, true
+	}
+	return 0, false
+}
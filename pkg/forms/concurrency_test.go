@@ -0,0 +1,71 @@
+package forms
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateVersion_Match(t *testing.T) {
+	cs := Cast(map[string]any{"lock_version": 3}, map[string]any{"title": "b", "lock_version": "3"}, []string{"title"}).
+		ValidateVersion("lock_version")
+
+	if !cs.Valid || cs.IsStale() {
+		t.Fatalf("Expected a valid changeset, got errors %v", cs.Errors)
+	}
+	if cs.Changes["lock_version"] != 4 {
+		t.Errorf("Expected the version bumped to 4, got %v", cs.Changes["lock_version"])
+	}
+	if field, version, ok := cs.LockVersion(); !ok || field != "lock_version" || version != 3 {
+		t.Errorf("LockVersion() = %q, %d, %v", field, version, ok)
+	}
+}
+
+func TestValidateVersion_Mismatch(t *testing.T) {
+	cs := Cast(map[string]any{"lock_version": 5}, map[string]any{"title": "b", "lock_version": "3"}, []string{"title"}).
+		ValidateVersion("lock_version")
+
+	if cs.Valid || !cs.IsStale() || cs.FirstError("lock_version") != StaleEntryMessage {
+		t.Fatalf("Expected a stale changeset, got valid=%v errors=%v", cs.Valid, cs.Errors)
+	}
+	if _, ok := cs.Changes["lock_version"]; ok {
+		t.Error("Expected no version change on a stale changeset")
+	}
+
+	_, err := cs.Apply()
+	var stale *StaleEntryError
+	if !errors.As(err, &stale) || !errors.Is(err, ErrStaleEntry) {
+		t.Fatalf("Expected a *StaleEntryError, got %v", err)
+	}
+	if stale.Field != "lock_version" || stale.Expected != 3 || stale.Actual != 5 {
+		t.Errorf("Unexpected error: %+v", stale)
+	}
+}
+
+func TestValidateVersion_Missing(t *testing.T) {
+	// Dropping the version from the form must not skip the check
+	cs := Cast(map[string]any{"lock_version": 5}, map[string]any{"title": "b"}, []string{"title"}).
+		ValidateVersion("lock_version")
+
+	if cs.Valid || cs.FirstError("lock_version") != "is required" {
+		t.Fatalf("Expected the version required, got valid=%v errors=%v", cs.Valid, cs.Errors)
+	}
+	if _, err := cs.Apply(); err == nil {
+		t.Error("Expected Apply to fail")
+	}
+
+	// A new record has no version to compare
+	cs = Cast(nil, map[string]any{"title": "a"}, []string{"title"}).ValidateVersion("lock_version")
+	if !cs.Valid || cs.Changes["lock_version"] != 1 {
+		t.Errorf("Expected a new record at version 1, got valid=%v changes=%v", cs.Valid, cs.Changes)
+	}
+}
+
+func TestStaleEntryError(t *testing.T) {
+	err := error(&StaleEntryError{Field: "lock_version", Expected: 2, Actual: 3})
+	if err.Error() != "stale entry: lock_version is 3, expected 2" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, ErrStaleEntry) || errors.Is(err, ErrEntryDeleted) {
+		t.Error("Expected the error to match ErrStaleEntry only")
+	}
+}
@@ -0,0 +1,234 @@
+package forms
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode casts a form payload into the struct dst points to and validates
+// it, returning the changeset of the fields. Fields are named by their form
+// tag (the Go field name without one; "-" skips the field) and validated by
+// their validate tag:
+//
+//	type signup struct {
+//	    Email string `form:"email" validate:"required,email"`
+//	    Age   int    `form:"age" validate:"min=18"`
+//	    Terms bool   `form:"terms" validate:"required"`
+//	}
+//
+//	func (c *Signup) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//	    var in signup
+//	    if c.Form = forms.Decode(payload, &in); !c.Form.Valid {
+//	        return nil // the template shows c.Form.Errors
+//	    }
+//	    return c.users.Create(in.Email, in.Age)
+//	}
+//
+// Values arrive as strings from lv-submit and are converted to the field's
+// type: strings, bools ("on", "true", "1"), integers, floats, []string and
+// time.Time (from date, datetime-local or RFC 3339 inputs). A value that
+// does not convert is reported as "is invalid" and leaves the field at its
+// zero value. dst is filled in even when the changeset is invalid, so the
+// form can be rendered again with what the user typed.
+//
+// Validation rules, separated by commas:
+//
+//	required    the value is present (a bool is true)
+//	email       an email address
+//	url         an http(s) URL
+//	min=N       at least N characters, or a number of at least N
+//	max=N       at most N characters, or a number of at most N
+//	len=N       exactly N characters
+//	oneof=a b c one of the values, separated by spaces
+//
+// Decode panics if dst is not a pointer to a struct, or a validate tag has
+// an unknown rule.
+func Decode(payload map[string]any, dst any) *Changeset {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("forms: Decode needs a pointer to a struct, got %T", dst))
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	cs := NewChangeset(nil)
+	cs.params = payload
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("form")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		raw, present := payload[name]
+		if str, ok := raw.(string); ok && strings.TrimSpace(str) == "" && sf.Type.Kind() != reflect.String {
+			present = false // a blank number or date input is missing, not zero
+		}
+		if present {
+			value, err := convertField(raw, sf.Type)
+			if err != nil {
+				cs.AddError(name, "is invalid")
+				continue
+			}
+			v.Field(i).Set(reflect.ValueOf(value))
+			cs.Changes[name] = value
+		}
+		if rules := sf.Tag.Get("validate"); rules != "" {
+			validateField(cs, name, sf.Type, rules)
+		}
+	}
+	return cs
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Layouts of the time values browsers send: date, datetime-local (with and
+// without seconds) and RFC 3339.
+var timeLayouts = []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05", time.RFC3339}
+
+// convertField converts a payload value to typ.
+func convertField(raw any, typ reflect.Type) (any, error) {
+	if typ == timeType {
+		s := strings.TrimSpace(fmt.Sprint(raw))
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("forms: invalid time %q", s)
+	}
+
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String {
+		var list []string
+		switch r := raw.(type) {
+		case []any:
+			for _, item := range r {
+				list = append(list, fmt.Sprint(item))
+			}
+		case []string:
+			list = r
+		case nil:
+		default:
+			list = []string{fmt.Sprint(r)}
+		}
+		return reflect.ValueOf(list).Convert(typ).Interface(), nil
+	}
+
+	s, isString := raw.(string)
+	if !isString {
+		// Values from a JSON payload keep their type
+		if rv := reflect.ValueOf(raw); rv.IsValid() && rv.Type().ConvertibleTo(typ) && rv.Kind() != reflect.String {
+			return rv.Convert(typ).Interface(), nil
+		}
+		s = fmt.Sprint(raw)
+	}
+	s = strings.TrimSpace(s)
+
+	out := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		out.SetString(s)
+	case reflect.Bool:
+		out.SetBool(s == "on" || s == "true" || s == "1")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		out.SetFloat(f)
+	default:
+		return nil, fmt.Errorf("forms: cannot decode into %s", typ)
+	}
+	return out.Interface(), nil
+}
+
+// validateField applies the rules of a validate tag to the field name.
+func validateField(cs *Changeset, name string, typ reflect.Type, rules string) {
+	numeric := isNumeric(typ.Kind())
+	for _, rule := range strings.Split(rules, ",") {
+		rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch rule {
+		case "required":
+			switch v := cs.GetField(name).(type) {
+			case bool:
+				if !v {
+					cs.AddError(name, "is required")
+				}
+			case nil, string:
+				cs.ValidateRequired(name)
+			default:
+				if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Len() == 0 {
+					cs.AddError(name, "is required")
+				}
+			}
+		case "email":
+			cs.ValidateFormat(name, emailRegex.String(), WithMessage("is not a valid email address"))
+		case "url":
+			cs.ValidateFormat(name, urlRegex.String(), WithMessage("is not a valid URL"))
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic(fmt.Sprintf("forms: invalid validate rule %s=%s", rule, arg))
+			}
+			if numeric {
+				if cs.GetField(name) == nil {
+					continue
+				}
+				opts := NumberOpts{GreaterThanOrEq: &n}
+				if rule == "max" {
+					opts = NumberOpts{LessThanOrEq: &n}
+				}
+				cs.ValidateNumber(name, opts)
+			} else if rule == "min" {
+				cs.ValidateLength(name, LengthOpts{Min: int(n)})
+			} else {
+				cs.ValidateLength(name, LengthOpts{Max: int(n)})
+			}
+		case "len":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				panic(fmt.Sprintf("forms: invalid validate rule len=%s", arg))
+			}
+			cs.ValidateLength(name, LengthOpts{Is: n})
+		case "oneof":
+			var values []any
+			for _, value := range strings.Fields(arg) {
+				values = append(values, value)
+			}
+			if cs.GetString(name) != "" {
+				cs.ValidateInclusion(name, values)
+			}
+		case "":
+		default:
+			panic(fmt.Sprintf("forms: unknown validate rule %q", rule))
+		}
+	}
+}
+
+func isNumeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package forms
+
+import (
+	"testing"
+	"time"
+)
+
+type signupForm struct {
+	Email    string    `form:"email" validate:"required,email"`
+	Age      int       `form:"age" validate:"min=18,max=130"`
+	Plan     string    `form:"plan" validate:"oneof=free pro"`
+	Terms    bool      `form:"terms" validate:"required"`
+	Tags     []string  `form:"tags"`
+	Birthday time.Time `form:"birthday"`
+	Internal string    `form:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	var in signupForm
+	cs := Decode(map[string]any{
+		"email":    "ana@example.com",
+		"age":      "30",
+		"plan":     "pro",
+		"terms":    "on",
+		"tags":     []any{"go", "web"},
+		"birthday": "1995-04-12",
+		"Internal": "ignored",
+	}, &in)
+
+	if !cs.Valid {
+		t.Fatalf("errors = %v", cs.Errors)
+	}
+	if in.Email != "ana@example.com" || in.Age != 30 || in.Plan != "pro" || !in.Terms {
+		t.Errorf("decoded %+v", in)
+	}
+	if len(in.Tags) != 2 || in.Tags[1] != "web" {
+		t.Errorf("tags = %v", in.Tags)
+	}
+	if want := time.Date(1995, 4, 12, 0, 0, 0, 0, time.UTC); !in.Birthday.Equal(want) {
+		t.Errorf("birthday = %v", in.Birthday)
+	}
+	if in.Internal != "" {
+		t.Errorf("form:\"-\" field was decoded: %q", in.Internal)
+	}
+	if cs.GetField("age") != 30 {
+		t.Errorf("change age = %#v, want the typed value", cs.GetField("age"))
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	var in signupForm
+	cs := Decode(map[string]any{
+		"email": "not-an-email",
+		"age":   "16",
+		"plan":  "gold",
+		"terms": "",
+	}, &in)
+
+	if cs.Valid {
+		t.Fatal("changeset is valid")
+	}
+	for field, want := range map[string]string{
+		"email": "is not a valid email address",
+		"age":   "must be greater than or equal to 18",
+		"plan":  "is invalid",
+		"terms": "is required",
+	} {
+		if got := cs.FirstError(field); got != want {
+			t.Errorf("%s error = %q, want %q", field, got, want)
+		}
+	}
+	if in.Email != "not-an-email" {
+		t.Errorf("invalid input was not kept: %q", in.Email)
+	}
+
+	cs = Decode(map[string]any{"email": "a@b.co", "age": "old", "terms": "on"}, &in)
+	if cs.FirstError("age") != "is invalid" {
+		t.Errorf("unconvertible age errors = %v", cs.Errors)
+	}
+
+	cs = Decode(map[string]any{"email": "a@b.co", "age": "", "terms": "on"}, &in)
+	if cs.HasError("age") {
+		t.Errorf("blank optional age errors = %v", cs.Errors)
+	}
+}
@@ -0,0 +1,307 @@
+package forms
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EmbedFunc builds the changeset of an embedded value from its stored data
+// and submitted params, usually a Cast followed by validations:
+//
+//	func addressChangeset(data, params map[string]any) *forms.Changeset {
+//	    return forms.Cast(data, params, []string{"street", "city"}).
+//	        ValidateRequired("street", "city")
+//	}
+type EmbedFunc func(data, params map[string]any) *Changeset
+
+// EmbedFields is an EmbedFunc that only casts the allowed fields.
+func EmbedFields(allowed ...string) EmbedFunc {
+	return func(data, params map[string]any) *Changeset {
+		return Cast(data, params, allowed)
+	}
+}
+
+// CastEmbedded casts the nested value field with its own changeset, built
+// by with from Data[field] (a map) and the submitted params. The params
+// come as a nested map from a JSON payload, or from inputs named with
+// brackets in a form:
+//
+//	<input name="address[street]">
+//	<input name="address[city]">
+//
+//	cs := forms.Cast(order, params, []string{"notes"}).
+//	    CastEmbedded("address", addressChangeset)
+//
+// The changeset is invalid when the embedded one is, and its errors are in
+// Embedded(field) (and in AllErrors, as "address[street]"). Apply returns
+// the embedded value as a map under field.
+func (cs *Changeset) CastEmbedded(field string, with EmbedFunc) *Changeset {
+	data, _ := cs.Data[field].(map[string]any)
+	params, submitted := nestedParams(cs.params, field)
+
+	child := with(data, params)
+	if cs.embeds == nil {
+		cs.embeds = make(map[string]*Changeset)
+	}
+	cs.embeds[field] = child
+
+	if submitted && child.HasChanges() {
+		cs.Changes[field] = child.merged()
+	}
+	if !child.Valid {
+		cs.Valid = false
+	}
+	return cs
+}
+
+// CastEmbeddedList casts a list of nested values, such as the line items
+// of an order, with a changeset per item. Items are submitted as a list in
+// a JSON payload, or from inputs named with an index:
+//
+//	<input name="items[0][product]"> <input name="items[0][quantity]">
+//	<input name="items[1][product]"> <input name="items[1][quantity]">
+//
+// Submitted items are matched to the stored ones (Data[field]) by their
+// "id" param, or by position when the stored items have no id. Items
+// without a match are new. The submitted list replaces the stored one:
+// stored items left out are removed, and so are items submitted with a
+// truthy "_delete" param (a "remove" checkbox).
+//
+// The changeset is invalid when any item is, and the errors of each item
+// are in EmbeddedList(field) (and in AllErrors, as "items[1][quantity]").
+func (cs *Changeset) CastEmbeddedList(field string, with EmbedFunc) *Changeset {
+	stored := mapList(cs.Data[field])
+	params, submitted := nestedListParams(cs.params, field)
+
+	var items []*Changeset
+	changed := false
+	if !submitted {
+		for _, data := range stored {
+			items = append(items, with(data, nil))
+		}
+	} else {
+		byID := make(map[string]map[string]any)
+		for _, data := range stored {
+			if id, ok := data["id"]; ok {
+				byID[fmt.Sprint(id)] = data
+			}
+		}
+		for i, p := range params {
+			if isTruthy(p["_delete"]) {
+				changed = true
+				continue
+			}
+			var data map[string]any
+			if id, ok := p["id"]; ok && fmt.Sprint(id) != "" {
+				data = byID[fmt.Sprint(id)]
+			} else if len(byID) == 0 && i < len(stored) {
+				data = stored[i]
+			}
+			item := with(data, p)
+			changed = changed || data == nil || item.HasChanges()
+			items = append(items, item)
+		}
+		changed = changed || len(items) != len(stored)
+	}
+
+	if cs.embedLists == nil {
+		cs.embedLists = make(map[string][]*Changeset)
+	}
+	cs.embedLists[field] = items
+
+	if changed {
+		cs.Changes[field] = mergedList(items)
+	}
+	for _, item := range items {
+		if !item.Valid {
+			cs.Valid = false
+		}
+	}
+	return cs
+}
+
+// Embedded returns the changeset of a field cast with CastEmbedded, or nil.
+func (cs *Changeset) Embedded(field string) *Changeset {
+	return cs.embeds[field]
+}
+
+// EmbeddedList returns the item changesets of a field cast with
+// CastEmbeddedList, in order.
+func (cs *Changeset) EmbeddedList(field string) []*Changeset {
+	return cs.embedLists[field]
+}
+
+// AllErrors returns the errors of the changeset and its embedded ones,
+// keyed by input name ("email", "address[city]", "items[0][quantity]").
+func (cs *Changeset) AllErrors() map[string][]string {
+	all := make(map[string][]string, len(cs.Errors))
+	for field, errs := range cs.Errors {
+		all[field] = errs
+	}
+	for field, child := range cs.embeds {
+		for key, errs := range child.AllErrors() {
+			all[nestName(field, key)] = errs
+		}
+	}
+	for field, items := range cs.embedLists {
+		for i, item := range items {
+			prefix := nestName(field, strconv.Itoa(i))
+			for key, errs := range item.AllErrors() {
+				all[nestName(prefix, key)] = errs
+			}
+		}
+	}
+	return all
+}
+
+// merged returns Data with Changes applied, and the embedded values as
+// maps.
+func (cs *Changeset) merged() map[string]any {
+	result := make(map[string]any, len(cs.Data)+len(cs.Changes))
+	for k, v := range cs.Data {
+		result[k] = v
+	}
+	for k, v := range cs.Changes {
+		result[k] = v
+	}
+	for field, child := range cs.embeds {
+		result[field] = child.merged()
+	}
+	for field, items := range cs.embedLists {
+		result[field] = mergedList(items)
+	}
+	return result
+}
+
+// embedChanges adds the embedded values that changed to changes.
+func (cs *Changeset) embedChanges(changes map[string]any) {
+	for field, child := range cs.embeds {
+		if _, ok := changes[field]; ok {
+			changes[field] = child.merged()
+		}
+	}
+	for field, items := range cs.embedLists {
+		if _, ok := changes[field]; ok {
+			changes[field] = mergedList(items)
+		}
+	}
+}
+
+func mergedList(items []*Changeset) []map[string]any {
+	list := make([]map[string]any, len(items))
+	for i, item := range items {
+		list[i] = item.merged()
+	}
+	return list
+}
+
+// nestName returns the input name of key inside prefix: nestName("items",
+// "0[name]") is "items[0][name]".
+func nestName(prefix, key string) string {
+	head, rest := key, ""
+	if i := strings.IndexByte(key, '['); i > 0 {
+		head, rest = key[:i], key[i:]
+	}
+	return prefix + "[" + head + "]" + rest
+}
+
+// nestedParams returns the params of field: a nested map, or the
+// "field[key]" params with the prefix removed ("field[a][b]" becomes
+// "a[b]").
+func nestedParams(params map[string]any, field string) (map[string]any, bool) {
+	if v, ok := params[field]; ok {
+		m, _ := v.(map[string]any)
+		return m, true
+	}
+	var nested map[string]any
+	prefix := field + "["
+	for key, value := range params {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := key[len(prefix):]
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			continue
+		}
+		if nested == nil {
+			nested = make(map[string]any)
+		}
+		nested[rest[:end]+rest[end+1:]] = value
+	}
+	return nested, nested != nil
+}
+
+// nestedListParams returns the items of field: a list of maps, or the
+// "field[i][key]" params grouped by index, in index order.
+func nestedListParams(params map[string]any, field string) ([]map[string]any, bool) {
+	if v, ok := params[field]; ok {
+		if list := mapList(v); list != nil {
+			return list, true
+		}
+	}
+	nested, ok := nestedParams(params, field)
+	if !ok {
+		return nil, false
+	}
+
+	var indexes []string
+	seen := make(map[string]bool)
+	for key := range nested {
+		index := key
+		if i := strings.IndexByte(key, '['); i > 0 {
+			index = key[:i]
+		}
+		if !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		a, errA := strconv.Atoi(indexes[i])
+		b, errB := strconv.Atoi(indexes[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return indexes[i] < indexes[j]
+	})
+
+	items := make([]map[string]any, 0, len(indexes))
+	for _, index := range indexes {
+		item, _ := nestedParams(nested, index)
+		if item == nil {
+			item = make(map[string]any)
+		}
+		items = append(items, item)
+	}
+	return items, true
+}
+
+// mapList converts a list of maps from data or a JSON payload.
+func mapList(v any) []map[string]any {
+	switch list := v.(type) {
+	case []map[string]any:
+		return list
+	case []any:
+		maps := make([]map[string]any, 0, len(list))
+		for _, item := range list {
+			if m, ok := item.(map[string]any); ok {
+				maps = append(maps, m)
+			}
+		}
+		return maps
+	}
+	return nil
+}
+
+func isTruthy(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true" || b == "on" || b == "1"
+	}
+	return false
+}
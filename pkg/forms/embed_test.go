@@ -0,0 +1,120 @@
+package forms
+
+import "testing"
+
+func addressChangeset(data, params map[string]any) *Changeset {
+	return Cast(data, params, []string{"street", "city"}).ValidateRequired("street", "city")
+}
+
+func lineItemChangeset(data, params map[string]any) *Changeset {
+	return Cast(data, params, []string{"product", "quantity"}).
+		ValidateRequired("product").
+		ValidateFormat("quantity", `^[1-9][0-9]*$`)
+}
+
+func TestCastEmbedded(t *testing.T) {
+	data := map[string]any{"address": map[string]any{"street": "Main 1", "city": "Lima"}}
+	cs := Cast(data, map[string]any{
+		"notes":           "leave at door",
+		"address[street]": "Side 2",
+		"address[city]":   "Lima",
+	}, []string{"notes"}).CastEmbedded("address", addressChangeset)
+
+	if !cs.Valid {
+		t.Fatalf("errors = %v", cs.AllErrors())
+	}
+	if got := cs.Embedded("address").GetString("street"); got != "Side 2" {
+		t.Errorf("embedded street = %q", got)
+	}
+	result, err := cs.Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := result["address"].(map[string]any)
+	if address["street"] != "Side 2" || address["city"] != "Lima" {
+		t.Errorf("applied address = %v", address)
+	}
+}
+
+func TestCastEmbedded_Errors(t *testing.T) {
+	cs := Cast(nil, map[string]any{
+		"address": map[string]any{"street": "Main 1"},
+	}, nil).CastEmbedded("address", addressChangeset)
+
+	if cs.Valid {
+		t.Fatal("changeset with an invalid embed is valid")
+	}
+	if got := cs.AllErrors()["address[city]"]; len(got) != 1 || got[0] != "is required" {
+		t.Errorf("AllErrors = %v", cs.AllErrors())
+	}
+	if _, err := cs.Apply(); err == nil {
+		t.Error("Apply succeeded")
+	}
+}
+
+func TestCastEmbeddedList(t *testing.T) {
+	data := map[string]any{"items": []map[string]any{
+		{"id": 1, "product": "tea", "quantity": "2"},
+		{"id": 2, "product": "milk", "quantity": "1"},
+	}}
+	cs := Cast(data, map[string]any{
+		"items[0][id]":        "2",
+		"items[0][quantity]":  "3",
+		"items[1][id]":        "1",
+		"items[1][_delete]":   "on",
+		"items[10][product]":  "bread",
+		"items[10][quantity]": "0",
+	}, nil).CastEmbeddedList("items", lineItemChangeset)
+
+	items := cs.EmbeddedList("items")
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	if items[0].GetString("product") != "milk" || items[0].GetField("quantity") != "3" {
+		t.Errorf("item 0 did not match the stored id 2: %v", items[0].merged())
+	}
+	if items[1].Data["product"] != nil || items[1].GetString("product") != "bread" {
+		t.Errorf("item 1 is not new: %v", items[1].merged())
+	}
+
+	if cs.Valid {
+		t.Fatal("changeset with an invalid item is valid")
+	}
+	errs := cs.AllErrors()
+	if len(errs) != 1 || len(errs["items[1][quantity]"]) != 1 {
+		t.Errorf("AllErrors = %v", errs)
+	}
+}
+
+func TestCastEmbeddedList_JSON(t *testing.T) {
+	cs := Cast(nil, map[string]any{
+		"items": []any{
+			map[string]any{"product": "tea", "quantity": "1"},
+			map[string]any{"product": "milk", "quantity": "2"},
+		},
+	}, nil).CastEmbeddedList("items", lineItemChangeset)
+
+	changes, err := cs.ApplyChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := changes["items"].([]map[string]any)
+	if len(items) != 2 || items[1]["product"] != "milk" {
+		t.Errorf("applied items = %v", items)
+	}
+}
+
+func TestCastEmbeddedList_Unchanged(t *testing.T) {
+	data := map[string]any{"items": []any{map[string]any{"product": "tea", "quantity": "1"}}}
+	cs := Cast(data, map[string]any{}, nil).CastEmbeddedList("items", lineItemChangeset)
+
+	if cs.HasChanges() {
+		t.Errorf("changes = %v", cs.Changes)
+	}
+	if len(cs.EmbeddedList("items")) != 1 {
+		t.Error("stored items were not loaded")
+	}
+	if !cs.Valid {
+		t.Errorf("errors = %v", cs.AllErrors())
+	}
+}
@@ -1,5 +1,7 @@
 package forms
 
+import "strings"
+
 // FieldType identifies the type of form field.
 type FieldType string
 
@@ -240,6 +242,14 @@ func WithMultiple() FieldOption {
 	}
 }
 
+// WithAccept sets the file types a file field accepts, as extensions
+// (".pdf") or MIME types ("image/*").
+func WithAccept(types ...string) FieldOption {
+	return func(f *Field) {
+		f.Attrs["accept"] = strings.Join(types, ",")
+	}
+}
+
 // WithAutocomplete sets the autocomplete attribute.
 func WithAutocomplete(value string) FieldOption {
 	return func(f *Field) {
@@ -0,0 +1,177 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"  // decode GIF dimensions
+	_ "image/jpeg" // decode JPEG dimensions
+	_ "image/png"  // decode PNG dimensions
+	"os"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/uploads"
+)
+
+// CastUploads adds the files chosen in the lv-upload inputs names to the
+// changeset, so file fields are validated and saved like the others:
+//
+//	func (c *Profile) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//	    if _, err := c.uploads.HandleEvent(event, payload); err != nil || uploads.IsUploadEvent(event) {
+//	        return err
+//	    }
+//	    c.Form = forms.Cast(c.user, payload, []string{"name"}).
+//	        CastUploads(c.uploads, "avatar").
+//	        ValidateRequired("name", "avatar").
+//	        ValidateFileType("avatar", "image/png", "image/jpeg").
+//	        ValidateFileSize("avatar", 2<<20).
+//	        ValidateImage("avatar", ImageOpts{MinWidth: 128, MinHeight: 128})
+//	    if event != "save" {
+//	        return nil
+//	    }
+//	    if err := c.Form.PersistUploads(ctx); err != nil {
+//	        return nil // the template shows c.Form.Errors
+//	    }
+//	    return c.users.Update(c.Form) // avatar holds the stored entries
+//	}
+//
+// Each field holds the upload's []*uploads.UploadEntry (see Files); fields
+// without files are not set, so ValidateRequired applies. Errors the upload
+// reported for a file are added to its field. While files are still
+// arriving, Checking(name) is true and Apply returns ErrValidationPending.
+//
+// CastUploads panics if name was not allowed with AllowUpload.
+func (cs *Changeset) CastUploads(u *uploads.Uploader, names ...string) *Changeset {
+	for _, name := range names {
+		l, ok := u.Upload(name)
+		if !ok {
+			panic(fmt.Sprintf("forms: upload %q is not allowed", name))
+		}
+		if cs.uploaders == nil {
+			cs.uploaders = make(map[string]*uploads.Uploader)
+		}
+		cs.uploaders[name] = u
+
+		var files []*uploads.UploadEntry
+		for _, e := range l.Entries() {
+			if len(e.Errors) > 0 {
+				for _, msg := range e.Errors {
+					cs.AddError(name, msg)
+				}
+				continue
+			}
+			files = append(files, e)
+		}
+		if len(files) > 0 {
+			cs.Changes[name] = files
+		}
+		if l.Pending() {
+			if cs.checking == nil {
+				cs.checking = make(map[string]bool)
+			}
+			cs.checking[name] = true
+		}
+	}
+	return cs
+}
+
+// Files returns the files of a file field.
+func (cs *Changeset) Files(field string) []*uploads.UploadEntry {
+	files, _ := cs.GetField(field).([]*uploads.UploadEntry)
+	return files
+}
+
+// ValidateFileType validates that every file of field matches accept:
+// extensions (".pdf"), MIME types and wildcards ("image/*").
+func (cs *Changeset) ValidateFileType(field string, accept ...string) *Changeset {
+	for _, f := range cs.Files(field) {
+		if !f.Accepts(accept...) {
+			return cs.AddError(field, "has an invalid file type")
+		}
+	}
+	return cs
+}
+
+// ValidateFileSize validates that no file of field is larger than max
+// bytes.
+func (cs *Changeset) ValidateFileSize(field string, max int64) *Changeset {
+	for _, f := range cs.Files(field) {
+		if f.Size > max {
+			return cs.AddError(field, "should be at most "+formatBytes(max))
+		}
+	}
+	return cs
+}
+
+// ImageOpts configures image validation. Zero fields are not checked.
+type ImageOpts struct {
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+}
+
+// ValidateImage validates that the files of field are GIF, JPEG or PNG
+// images within the dimensions of opts. Only the image header is read.
+// Files uploaded straight to the storage (AllowOptions.External) are not
+// on the server and are skipped.
+func (cs *Changeset) ValidateImage(field string, opts ImageOpts) *Changeset {
+	for _, f := range cs.Files(field) {
+		if f.TempPath == "" {
+			continue
+		}
+		config, err := imageConfig(f.TempPath)
+		switch {
+		case err != nil:
+			return cs.AddError(field, "is not an image")
+		case config.Width < opts.MinWidth || config.Height < opts.MinHeight:
+			return cs.AddError(field, fmt.Sprintf("should be at least %dx%d pixels", opts.MinWidth, opts.MinHeight))
+		case (opts.MaxWidth > 0 && config.Width > opts.MaxWidth) || (opts.MaxHeight > 0 && config.Height > opts.MaxHeight):
+			return cs.AddError(field, fmt.Sprintf("should be at most %dx%d pixels", opts.MaxWidth, opts.MaxHeight))
+		}
+	}
+	return cs
+}
+
+func imageConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+	config, _, err := image.DecodeConfig(f)
+	return config, err
+}
+
+// PersistUploads moves the files of a valid changeset to the uploader's
+// storage with Uploader.Consume. The file fields then hold the stored
+// entries, with their Key and URL. An invalid or pending changeset stores
+// nothing and returns the error Apply would.
+func (cs *Changeset) PersistUploads(ctx context.Context) error {
+	if err := cs.invalidError(); err != nil {
+		return err
+	}
+	for field, u := range cs.uploaders {
+		if len(cs.Files(field)) == 0 {
+			continue
+		}
+		stored, err := u.Consume(ctx, field)
+		if err != nil {
+			cs.AddError(field, "could not be saved")
+			return err
+		}
+		cs.Changes[field] = stored
+	}
+	return nil
+}
+
+// formatBytes formats a size for error messages.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
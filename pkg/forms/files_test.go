@@ -0,0 +1,84 @@
+package forms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/uploads"
+)
+
+// uploadPNG sends a w×h PNG to the upload name of u in two chunks,
+// leaving out the second one when partial is true.
+func uploadPNG(t *testing.T, u *uploads.Uploader, name string, w, h int, partial bool) {
+	t.Helper()
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)))
+	data := buf.Bytes()
+
+	_, err := u.HandleEvent(uploads.UploadStartEvent, map[string]any{"name": name, "files": []any{
+		map[string]any{"ref": "0", "name": "avatar.png", "size": float64(len(data)), "type": "image/png"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	half := len(data) / 2
+	u.HandleEvent(uploads.UploadChunkEvent, map[string]any{"name": name, "ref": "0", "offset": float64(0), core.BinaryKey: data[:half]})
+	if !partial {
+		u.HandleEvent(uploads.UploadChunkEvent, map[string]any{"name": name, "ref": "0", "offset": float64(half), core.BinaryKey: data[half:]})
+	}
+}
+
+func TestChangeset_Uploads(t *testing.T) {
+	store := uploads.NewDiskStore(t.TempDir(), "/uploads/")
+	u := uploads.NewUploader(store).TempDir(t.TempDir())
+	defer u.Close()
+	u.AllowUpload("avatar", uploads.AllowOptions{Accept: []string{"image/*"}})
+
+	cs := Cast(nil, map[string]any{}, nil).CastUploads(u, "avatar").ValidateRequired("avatar")
+	if cs.FirstError("avatar") != "is required" {
+		t.Errorf("errors without a file = %v", cs.Errors)
+	}
+
+	uploadPNG(t, u, "avatar", 32, 24, true)
+	cs = Cast(nil, map[string]any{}, nil).CastUploads(u, "avatar")
+	if !cs.Checking("avatar") {
+		t.Error("Checking = false while the file is arriving")
+	}
+	if err := cs.PersistUploads(context.Background()); !errors.Is(err, ErrValidationPending) {
+		t.Errorf("PersistUploads while pending = %v", err)
+	}
+
+	uploadPNG(t, u, "avatar", 32, 24, false)
+	cs = Cast(nil, map[string]any{}, nil).CastUploads(u, "avatar").
+		ValidateFileType("avatar", ".jpg").
+		ValidateFileSize("avatar", 16).
+		ValidateImage("avatar", ImageOpts{MinWidth: 64, MinHeight: 64})
+	want := []string{"has an invalid file type", "should be at most 16 bytes", "should be at least 64x64 pixels"}
+	if got := cs.FieldErrors("avatar"); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("errors = %q, want %q", got, want)
+	}
+	if err := cs.PersistUploads(context.Background()); err == nil {
+		t.Error("invalid changeset persisted its uploads")
+	}
+
+	cs = Cast(nil, map[string]any{}, nil).CastUploads(u, "avatar").
+		ValidateFileType("avatar", "image/png").
+		ValidateFileSize("avatar", 1<<20).
+		ValidateImage("avatar", ImageOpts{MinWidth: 16, MaxWidth: 32, MaxHeight: 32})
+	if !cs.Valid {
+		t.Fatalf("errors = %v", cs.Errors)
+	}
+	if err := cs.PersistUploads(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	files := cs.Files("avatar")
+	if len(files) != 1 || !strings.HasPrefix(files[0].URL, "/uploads/avatar/") {
+		t.Errorf("stored files = %+v", files)
+	}
+}
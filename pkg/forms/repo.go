@@ -0,0 +1,157 @@
+package forms
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ErrNotFound is returned by MemoryRepo for unknown ids.
+var ErrNotFound = errors.New("record not found")
+
+// MemoryRepo is an in-memory record store that follows the changeset
+// conventions: the lock field from ValidateVersion is checked again at write
+// time and DeletedAtField hides soft-deleted records. It is meant for
+// examples and tests, and as a reference for database-backed repositories.
+type MemoryRepo struct {
+	mu      sync.RWMutex
+	records map[string]map[string]any
+	nextID  int
+}
+
+// NewMemoryRepo creates an empty repository.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{records: make(map[string]map[string]any)}
+}
+
+// Insert stores the changeset as a new record and returns its id.
+func (r *MemoryRepo) Insert(cs *Changeset) (string, error) {
+	data, err := cs.Apply()
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	id := strconv.Itoa(r.nextID)
+	data["id"] = id
+	r.records[id] = data
+	return id, nil
+}
+
+// Get returns a copy of a record that is not soft-deleted.
+func (r *MemoryRepo) Get(id string) (map[string]any, error) {
+	data, err := r.GetWithDeleted(id)
+	if err != nil {
+		return nil, err
+	}
+	if IsDeleted(data) {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// GetWithDeleted returns a copy of a record, including soft-deleted ones.
+func (r *MemoryRepo) GetWithDeleted(id string) (map[string]any, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyRecord(data), nil
+}
+
+// Update applies a changeset to the record. If the stored version no longer
+// matches the changeset's LockVersion the changeset is marked stale and a
+// *StaleEntryError is returned. Soft-deleted records can only be restored.
+func (r *MemoryRepo) Update(id string, cs *Changeset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.records[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if IsDeleted(current) && cs.Action != ActionRestore {
+		cs.AddError(DeletedAtField, DeletedEntryMessage)
+		return cs.invalidError()
+	}
+
+	if field, version, ok := cs.LockVersion(); ok && cs.Valid {
+		if actual, _ := toInt(current[field]); actual != version {
+			cs.MarkStale(actual)
+			return cs.invalidError()
+		}
+	}
+
+	changes, err := cs.ApplyChanges()
+	if err != nil {
+		return err
+	}
+	for k, v := range changes {
+		current[k] = v
+	}
+	return nil
+}
+
+// Delete soft-deletes a record.
+func (r *MemoryRepo) Delete(id string) error {
+	data, err := r.GetWithDeleted(id)
+	if err != nil {
+		return err
+	}
+	return r.Update(id, NewChangeset(data).SoftDelete())
+}
+
+// Restore undoes a soft delete.
+func (r *MemoryRepo) Restore(id string) error {
+	data, err := r.GetWithDeleted(id)
+	if err != nil {
+		return err
+	}
+	return r.Update(id, NewChangeset(data).Restore())
+}
+
+// List returns records that are not soft-deleted, ordered by id.
+func (r *MemoryRepo) List() []map[string]any {
+	return r.list(false)
+}
+
+// ListDeleted returns soft-deleted records (the "trash"), ordered by id.
+func (r *MemoryRepo) ListDeleted() []map[string]any {
+	return r.list(true)
+}
+
+func (r *MemoryRepo) list(deleted bool) []map[string]any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.records))
+	for id, data := range r.records {
+		if IsDeleted(data) != deleted {
+			continue
+		}
+		n, _ := strconv.Atoi(id)
+		ids = append(ids, n)
+	}
+	sort.Ints(ids)
+
+	out := make([]map[string]any, len(ids))
+	for i, n := range ids {
+		out[i] = copyRecord(r.records[strconv.Itoa(n)])
+	}
+	return out
+}
+
+func copyRecord(data map[string]any) map[string]any {
+	out := make(map[string]any, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	return out
+}
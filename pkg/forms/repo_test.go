@@ -0,0 +1,79 @@
+package forms
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryRepo_Update(t *testing.T) {
+	repo := NewMemoryRepo()
+	id, err := repo.Insert(Cast(nil, map[string]any{"title": "a"}, []string{"title"}).ValidateVersion("lock_version"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two users load version 1
+	first, _ := repo.Get(id)
+	second, _ := repo.Get(id)
+
+	cs := Cast(first, map[string]any{"title": "b", "lock_version": "1"}, []string{"title"}).ValidateVersion("lock_version")
+	if err := repo.Update(id, cs); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := repo.Get(id); got["title"] != "b" || got["lock_version"] != 2 {
+		t.Errorf("Expected title b at version 2, got %v", got)
+	}
+
+	// The second edit passed validation against its stale copy
+	cs = Cast(second, map[string]any{"title": "c", "lock_version": "1"}, []string{"title"}).ValidateVersion("lock_version")
+	err = repo.Update(id, cs)
+	var stale *StaleEntryError
+	if !errors.As(err, &stale) || stale.Expected != 1 || stale.Actual != 2 {
+		t.Fatalf("Expected a stale entry at write time, got %v", err)
+	}
+	if !cs.IsStale() || cs.FirstError("lock_version") != StaleEntryMessage {
+		t.Errorf("Expected the changeset marked stale, got %v", cs.Errors)
+	}
+	if got, _ := repo.Get(id); got["title"] != "b" {
+		t.Errorf("Expected the stale edit discarded, got %v", got["title"])
+	}
+
+	if err := repo.Update("404", NewChangeset(nil)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryRepo_SoftDelete(t *testing.T) {
+	repo := NewMemoryRepo()
+	a, _ := repo.Insert(Cast(nil, map[string]any{"title": "a"}, []string{"title"}))
+	b, _ := repo.Insert(Cast(nil, map[string]any{"title": "b"}, []string{"title"}))
+
+	if err := repo.Delete(a); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(a); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a deleted record hidden, got %v", err)
+	}
+	if list := repo.List(); len(list) != 1 || list[0]["id"] != b {
+		t.Errorf("Expected only %s listed, got %v", b, list)
+	}
+	if trash := repo.ListDeleted(); len(trash) != 1 || trash[0]["id"] != a {
+		t.Errorf("Expected %s in the trash, got %v", a, trash)
+	}
+
+	data, _ := repo.GetWithDeleted(a)
+	err := repo.Update(a, Cast(data, map[string]any{"title": "x"}, []string{"title"}))
+	if !errors.Is(err, ErrEntryDeleted) {
+		t.Errorf("Expected edits to a deleted record refused, got %v", err)
+	}
+
+	if err := repo.Restore(a); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, err := repo.Get(a); err != nil || got["title"] != "a" {
+		t.Errorf("Expected the record restored, got %v (%v)", got, err)
+	}
+	if list := repo.List(); len(list) != 2 || list[0]["id"] != a {
+		t.Errorf("Expected both records listed by id, got %v", list)
+	}
+}
@@ -247,6 +247,20 @@ func toFloat64(value any) float64 {
 		return float64(v)
 	case int32:
 		return float64(v)
+	case int16:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint8:
+		return float64(v)
 	default:
 		return 0
 	}
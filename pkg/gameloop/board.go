@@ -0,0 +1,153 @@
+package gameloop
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidBoardDiff is returned when a board diff cannot be decoded.
+var ErrInvalidBoardDiff = errors.New("invalid board diff")
+
+// Board diff frame kinds.
+const (
+	boardFull  byte = 0
+	boardDelta byte = 1
+)
+
+// boardHeaderSize is kind (1) + width (2) + height (2).
+const boardHeaderSize = 5
+
+// Board is a grid of byte-sized cells (tile IDs, colors, entity kinds).
+type Board struct {
+	Width  int
+	Height int
+	Cells  []byte
+}
+
+// NewBoard creates an empty board.
+func NewBoard(width, height int) *Board {
+	return &Board{
+		Width:  width,
+		Height: height,
+		Cells:  make([]byte, width*height),
+	}
+}
+
+// Set sets the cell at (x, y). Out-of-range coordinates are ignored.
+func (b *Board) Set(x, y int, v byte) {
+	if x < 0 || y < 0 || x >= b.Width || y >= b.Height {
+		return
+	}
+	b.Cells[y*b.Width+x] = v
+}
+
+// Get returns the cell at (x, y), or 0 if out of range.
+func (b *Board) Get(x, y int) byte {
+	if x < 0 || y < 0 || x >= b.Width || y >= b.Height {
+		return 0
+	}
+	return b.Cells[y*b.Width+x]
+}
+
+// Fill sets every cell to v.
+func (b *Board) Fill(v byte) {
+	for i := range b.Cells {
+		b.Cells[i] = v
+	}
+}
+
+// Clone returns a deep copy of the board.
+func (b *Board) Clone() *Board {
+	c := &Board{Width: b.Width, Height: b.Height, Cells: make([]byte, len(b.Cells))}
+	copy(c.Cells, b.Cells)
+	return c
+}
+
+// EncodeDiff encodes the changes from prev to next as a binary frame,
+// suitable for Socket.PushBinary. A full frame is produced when prev is nil,
+// has different dimensions, or the delta would be larger than the board.
+//
+// Layout (big-endian):
+//
+//	full:  [0][u16 width][u16 height][width*height cells]
+//	delta: [1][u16 width][u16 height][u32 count]{[u32 index][u8 value]}*count
+func EncodeDiff(prev, next *Board) []byte {
+	if prev == nil || prev.Width != next.Width || prev.Height != next.Height {
+		return encodeFull(next)
+	}
+
+	var changed []int
+	for i, v := range next.Cells {
+		if prev.Cells[i] != v {
+			changed = append(changed, i)
+		}
+	}
+	if 4+len(changed)*5 >= len(next.Cells) {
+		return encodeFull(next)
+	}
+
+	frame := make([]byte, boardHeaderSize+4+len(changed)*5)
+	writeBoardHeader(frame, boardDelta, next)
+	binary.BigEndian.PutUint32(frame[boardHeaderSize:], uint32(len(changed)))
+	off := boardHeaderSize + 4
+	for _, i := range changed {
+		binary.BigEndian.PutUint32(frame[off:], uint32(i))
+		frame[off+4] = next.Cells[i]
+		off += 5
+	}
+	return frame
+}
+
+// ApplyDiff applies a frame produced by EncodeDiff to b, resizing it on a
+// full frame. It mirrors the client-side GoliveKit.applyBoardDiff helper.
+func ApplyDiff(b *Board, frame []byte) error {
+	if len(frame) < boardHeaderSize {
+		return ErrInvalidBoardDiff
+	}
+	kind := frame[0]
+	width := int(binary.BigEndian.Uint16(frame[1:]))
+	height := int(binary.BigEndian.Uint16(frame[3:]))
+	body := frame[boardHeaderSize:]
+
+	switch kind {
+	case boardFull:
+		if len(body) != width*height {
+			return ErrInvalidBoardDiff
+		}
+		b.Width, b.Height = width, height
+		b.Cells = append(b.Cells[:0], body...)
+		return nil
+
+	case boardDelta:
+		if width != b.Width || height != b.Height || len(body) < 4 {
+			return ErrInvalidBoardDiff
+		}
+		n := int(binary.BigEndian.Uint32(body))
+		body = body[4:]
+		if len(body) != n*5 {
+			return ErrInvalidBoardDiff
+		}
+		for off := 0; off < len(body); off += 5 {
+			i := int(binary.BigEndian.Uint32(body[off:]))
+			if i >= len(b.Cells) {
+				return ErrInvalidBoardDiff
+			}
+			b.Cells[i] = body[off+4]
+		}
+		return nil
+	}
+	return ErrInvalidBoardDiff
+}
+
+func encodeFull(b *Board) []byte {
+	frame := make([]byte, boardHeaderSize+len(b.Cells))
+	writeBoardHeader(frame, boardFull, b)
+	copy(frame[boardHeaderSize:], b.Cells)
+	return frame
+}
+
+func writeBoardHeader(frame []byte, kind byte, b *Board) {
+	frame[0] = kind
+	binary.BigEndian.PutUint16(frame[1:], uint16(b.Width))
+	binary.BigEndian.PutUint16(frame[3:], uint16(b.Height))
+}
@@ -0,0 +1,144 @@
+package gameloop
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoop_Ticks(t *testing.T) {
+	var mu sync.Mutex
+	var ticks []Tick
+
+	l := New(10*time.Millisecond, func(msg any) error {
+		mu.Lock()
+		ticks = append(ticks, msg.(Tick))
+		mu.Unlock()
+		return nil
+	})
+	l.Start()
+	time.Sleep(55 * time.Millisecond)
+	l.Stop()
+
+	if l.Running() {
+		t.Error("Expected loop to be stopped")
+	}
+
+	mu.Lock()
+	n := len(ticks)
+	mu.Unlock()
+	if n < 3 {
+		t.Fatalf("Expected at least 3 ticks, got %d", n)
+	}
+	for i, tick := range ticks {
+		if tick.Seq != uint64(i+1) {
+			t.Errorf("Tick %d has Seq %d", i, tick.Seq)
+		}
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ticks) != n {
+		t.Errorf("Expected no ticks after Stop, got %d more", len(ticks)-n)
+	}
+}
+
+func TestLoop_DropsWhenBehind(t *testing.T) {
+	l := New(5*time.Millisecond, func(msg any) error {
+		return errors.New("queue full")
+	})
+	l.Start()
+	time.Sleep(30 * time.Millisecond)
+	l.Stop()
+
+	if l.Dropped() == 0 {
+		t.Error("Expected dropped ticks to be counted")
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	payload := map[string]any{
+		"inputs": []any{
+			map[string]any{"t": "key", "d": map[string]any{"key": "ArrowUp"}, "f": float64(3)},
+			map[string]any{"d": map[string]any{}}, // missing type
+			"garbage",
+			map[string]any{"t": "pointer", "f": float64(4)},
+		},
+	}
+
+	inputs := ParseBatch(payload)
+	if len(inputs) != 2 {
+		t.Fatalf("Expected 2 inputs, got %d", len(inputs))
+	}
+	if inputs[0].Type != "key" || inputs[0].Data["key"] != "ArrowUp" || inputs[0].Frame != 3 {
+		t.Errorf("Unexpected first input: %+v", inputs[0])
+	}
+	if inputs[1].Type != "pointer" || inputs[1].Frame != 4 {
+		t.Errorf("Unexpected second input: %+v", inputs[1])
+	}
+
+	if ParseBatch(map[string]any{}) != nil {
+		t.Error("Expected nil for payload without inputs")
+	}
+}
+
+func TestInputBuffer_Max(t *testing.T) {
+	b := NewInputBuffer(2)
+	b.Push(Input{Type: "a"}, Input{Type: "b"}, Input{Type: "c"})
+
+	got := b.Drain()
+	if len(got) != 2 || got[0].Type != "b" || got[1].Type != "c" {
+		t.Errorf("Expected newest 2 inputs, got %+v", got)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Expected empty buffer after Drain, got %d", b.Len())
+	}
+}
+
+func TestBoardDiff_RoundTrip(t *testing.T) {
+	prev := NewBoard(20, 20)
+	next := prev.Clone()
+	next.Set(3, 4, 1)
+	next.Set(19, 19, 2)
+
+	frame := EncodeDiff(prev, next)
+	if frame[0] != boardDelta {
+		t.Fatalf("Expected delta frame, got kind %d", frame[0])
+	}
+	if len(frame) != boardHeaderSize+4+2*5 {
+		t.Errorf("Unexpected delta frame size %d", len(frame))
+	}
+
+	client := prev.Clone()
+	if err := ApplyDiff(client, frame); err != nil {
+		t.Fatalf("ApplyDiff error: %v", err)
+	}
+	if !bytes.Equal(client.Cells, next.Cells) {
+		t.Error("Board mismatch after applying delta")
+	}
+}
+
+func TestBoardDiff_Full(t *testing.T) {
+	next := NewBoard(4, 3)
+	next.Fill(7)
+
+	frame := EncodeDiff(nil, next)
+	if frame[0] != boardFull {
+		t.Fatalf("Expected full frame, got kind %d", frame[0])
+	}
+
+	client := &Board{}
+	if err := ApplyDiff(client, frame); err != nil {
+		t.Fatalf("ApplyDiff error: %v", err)
+	}
+	if client.Width != 4 || client.Height != 3 || !bytes.Equal(client.Cells, next.Cells) {
+		t.Errorf("Unexpected board %+v", client)
+	}
+
+	if err := ApplyDiff(NewBoard(2, 2), []byte{boardDelta, 0, 4, 0, 3}); err == nil {
+		t.Error("Expected error for delta with mismatched dimensions")
+	}
+}
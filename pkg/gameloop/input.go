@@ -0,0 +1,92 @@
+package gameloop
+
+import "sync"
+
+// BatchEvent is the event name used by the client to send a frame's worth
+// of inputs (see liveView.queueInput).
+const BatchEvent = "input_batch"
+
+// Input is a single client input (key press, pointer move, ...).
+type Input struct {
+	// Type identifies the input ("key", "pointer", ...).
+	Type string
+
+	// Data holds input-specific values.
+	Data map[string]any
+
+	// Frame is the client frame counter when the input was captured.
+	Frame uint64
+}
+
+// ParseBatch extracts inputs from a BatchEvent payload of the form
+// {"inputs": [{"t": "key", "d": {...}, "f": 12}, ...]}.
+// Malformed entries are skipped.
+func ParseBatch(payload map[string]any) []Input {
+	raw, ok := payload["inputs"].([]any)
+	if !ok {
+		return nil
+	}
+
+	inputs := make([]Input, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		typ, _ := m["t"].(string)
+		if typ == "" {
+			continue
+		}
+		in := Input{Type: typ}
+		if d, ok := m["d"].(map[string]any); ok {
+			in.Data = d
+		}
+		if f, ok := m["f"].(float64); ok && f >= 0 {
+			in.Frame = uint64(f)
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// InputBuffer collects inputs between ticks. Events push into it from
+// HandleEvent; the tick handler drains it once per frame.
+type InputBuffer struct {
+	inputs []Input
+	max    int
+	mu     sync.Mutex
+}
+
+// NewInputBuffer creates a buffer holding at most max inputs per tick
+// (0 = unlimited). When full, the oldest inputs are discarded.
+func NewInputBuffer(max int) *InputBuffer {
+	return &InputBuffer{max: max}
+}
+
+// Push appends inputs to the buffer.
+func (b *InputBuffer) Push(inputs ...Input) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inputs = append(b.inputs, inputs...)
+	if b.max > 0 && len(b.inputs) > b.max {
+		b.inputs = b.inputs[len(b.inputs)-b.max:]
+	}
+}
+
+// Drain returns and clears the buffered inputs.
+func (b *InputBuffer) Drain() []Input {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	inputs := b.inputs
+	b.inputs = nil
+	return inputs
+}
+
+// Len returns the number of buffered inputs.
+func (b *InputBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.inputs)
+}
@@ -0,0 +1,141 @@
+// Package gameloop provides helpers for real-time, canvas-style LiveViews
+// such as games: fixed-rate server ticks delivered to HandleInfo, per-frame
+// client input batching, and a compact binary format for board state diffs.
+package gameloop
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Tick is delivered to the component's HandleInfo on every loop iteration.
+type Tick struct {
+	// Seq is the tick number, starting at 1.
+	Seq uint64
+
+	// Time is when the tick fired.
+	Time time.Time
+
+	// Delta is the time elapsed since the previous tick.
+	Delta time.Duration
+}
+
+// Loop emits Ticks at a fixed rate.
+// If the receiver falls behind, ticks are dropped rather than queued, so a
+// slow render never causes a burst of catch-up frames.
+type Loop struct {
+	rate    time.Duration
+	send    func(msg any) error
+	seq     uint64
+	dropped atomic.Uint64
+
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	mu      sync.Mutex
+}
+
+// New creates a loop that calls send with a Tick every rate.
+func New(rate time.Duration, send func(msg any) error) *Loop {
+	if rate <= 0 {
+		rate = 100 * time.Millisecond
+	}
+	return &Loop{
+		rate: rate,
+		send: send,
+	}
+}
+
+// ForSocket creates a loop that delivers ticks to the socket's component
+// through HandleInfo. The router re-renders after each tick.
+func ForSocket(s *core.Socket, rate time.Duration) *Loop {
+	return New(rate, s.SendInfo)
+}
+
+// Start begins emitting ticks. Calling Start on a running loop is a no-op.
+func (l *Loop) Start() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running {
+		return
+	}
+	l.running = true
+	l.stopCh = make(chan struct{})
+	l.doneCh = make(chan struct{})
+
+	go l.run(l.rate, l.stopCh, l.doneCh)
+}
+
+// Stop halts the loop and waits for the ticking goroutine to exit.
+func (l *Loop) Stop() {
+	l.mu.Lock()
+	if !l.running {
+		l.mu.Unlock()
+		return
+	}
+	l.running = false
+	close(l.stopCh)
+	done := l.doneCh
+	l.mu.Unlock()
+
+	<-done
+}
+
+// SetRate changes the tick interval, restarting the loop if it is running.
+func (l *Loop) SetRate(rate time.Duration) {
+	if rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	running := l.running
+	l.mu.Unlock()
+
+	if running {
+		l.Stop()
+	}
+	l.mu.Lock()
+	l.rate = rate
+	l.mu.Unlock()
+	if running {
+		l.Start()
+	}
+}
+
+// Running reports whether the loop is ticking.
+func (l *Loop) Running() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running
+}
+
+// Dropped returns the number of ticks that could not be delivered.
+func (l *Loop) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+func (l *Loop) run(rate time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(rate)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			l.seq++
+			tick := Tick{Seq: l.seq, Time: now, Delta: now.Sub(last)}
+			last = now
+			if err := l.send(tick); err != nil {
+				l.dropped.Add(1)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package i18n
+
+// Text directions for the HTML dir attribute.
+const (
+	DirLTR = "ltr"
+	DirRTL = "rtl"
+)
+
+// rtlLanguages are the base languages written right-to-left.
+var rtlLanguages = map[string]bool{
+	"ar":  true, // Arabic
+	"ckb": true, // Central Kurdish
+	"dv":  true, // Divehi
+	"fa":  true, // Persian
+	"he":  true, // Hebrew
+	"iw":  true, // Hebrew (legacy code)
+	"ps":  true, // Pashto
+	"sd":  true, // Sindhi
+	"ug":  true, // Uyghur
+	"ur":  true, // Urdu
+	"yi":  true, // Yiddish
+}
+
+// IsRTL reports whether locale is written right-to-left.
+func IsRTL(locale string) bool {
+	return rtlLanguages[baseLanguage(locale)]
+}
+
+// Direction returns the text direction for locale: DirRTL or DirLTR.
+func Direction(locale string) string {
+	if IsRTL(locale) {
+		return DirRTL
+	}
+	return DirLTR
+}
+
+// Direction returns the text direction of the translator's locale.
+func (t *Translator) Direction() string {
+	return Direction(t.Locale())
+}
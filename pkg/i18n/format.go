@@ -0,0 +1,337 @@
+package i18n
+
+import (
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date styles for FormatDate.
+const (
+	DateShort  = "short"  // 1/2/06
+	DateMedium = "medium" // Jan 2, 2006
+	DateLong   = "long"   // January 2, 2006
+	DateFull   = "full"   // Monday, January 2, 2006
+)
+
+// LocaleFormat holds the CLDR number and date conventions of a locale.
+type LocaleFormat struct {
+	// Decimal is the decimal separator.
+	Decimal string
+
+	// Group is the thousands separator.
+	Group string
+
+	// CurrencyAfter places the currency symbol after the amount ("12,50 €").
+	CurrencyAfter bool
+
+	// PercentSpace inserts a non-breaking space before "%".
+	PercentSpace bool
+
+	// Short is the numeric date pattern using d, m and y placeholders.
+	Short string
+
+	// Medium and Long are date patterns with d, MMM/MMMM (month name) and y.
+	Medium string
+	Long   string
+
+	// Months and Days hold localized month (January..) and weekday (Sunday..) names.
+	Months []string
+	Days   []string
+}
+
+var (
+	monthsEN = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	daysEN   = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	monthsES = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+	daysES   = []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}
+	monthsFR = []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}
+	daysFR   = []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+	monthsDE = []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+	daysDE   = []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}
+	monthsPT = []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+	daysPT   = []string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+	monthsIT = []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"}
+	daysIT   = []string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"}
+)
+
+// localeFormats maps locales (or base languages) to their conventions.
+// Regional variants fall back to the base language ("es-MX" -> "es").
+var localeFormats = map[string]LocaleFormat{
+	"en":    {Decimal: ".", Group: ",", Short: "m/d/yy", Medium: "MMM d, y", Long: "MMMM d, y", Months: monthsEN, Days: daysEN},
+	"en-GB": {Decimal: ".", Group: ",", Short: "dd/mm/y", Medium: "d MMM y", Long: "d MMMM y", Months: monthsEN, Days: daysEN},
+	"es":    {Decimal: ",", Group: ".", CurrencyAfter: true, PercentSpace: true, Short: "d/m/yy", Medium: "d MMM y", Long: "d 'de' MMMM 'de' y", Months: monthsES, Days: daysES},
+	"es-MX": {Decimal: ".", Group: ",", Short: "dd/mm/yy", Medium: "d MMM y", Long: "d 'de' MMMM 'de' y", Months: monthsES, Days: daysES},
+	"fr":    {Decimal: ",", Group: "\u202f", CurrencyAfter: true, PercentSpace: true, Short: "dd/mm/y", Medium: "d MMM y", Long: "d MMMM y", Months: monthsFR, Days: daysFR},
+	"de":    {Decimal: ",", Group: ".", CurrencyAfter: true, PercentSpace: true, Short: "dd.mm.yy", Medium: "dd.mm.y", Long: "d. MMMM y", Months: monthsDE, Days: daysDE},
+	"pt":    {Decimal: ",", Group: ".", Short: "dd/mm/y", Medium: "d 'de' MMM 'de' y", Long: "d 'de' MMMM 'de' y", Months: monthsPT, Days: daysPT},
+	"it":    {Decimal: ",", Group: ".", CurrencyAfter: true, Short: "dd/mm/yy", Medium: "d MMM y", Long: "d MMMM y", Months: monthsIT, Days: daysIT},
+}
+
+// currencyInfo holds a currency's symbol and minor units.
+type currencyInfo struct {
+	symbol   string
+	decimals int
+}
+
+var currencies = map[string]currencyInfo{
+	"USD": {"$", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"JPY": {"¥", 0},
+	"CNY": {"¥", 2},
+	"MXN": {"$", 2},
+	"ARS": {"$", 2},
+	"CLP": {"$", 0},
+	"COP": {"$", 2},
+	"BRL": {"R$", 2},
+	"CAD": {"CA$", 2},
+	"AUD": {"A$", 2},
+	"CHF": {"CHF", 2},
+	"INR": {"₹", 2},
+	"KRW": {"₩", 0},
+}
+
+// Format returns the formatting conventions for a locale, falling back to
+// the base language and then to English.
+func Format(locale string) LocaleFormat {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if f, ok := localeFormats[locale]; ok {
+		return f
+	}
+	if f, ok := localeFormats[baseLanguage(locale)]; ok {
+		return f
+	}
+	return localeFormats["en"]
+}
+
+// RegisterFormat adds or replaces the conventions for a locale.
+// It is not safe to call concurrently with formatting; register at startup.
+func RegisterFormat(locale string, f LocaleFormat) {
+	localeFormats[locale] = f
+}
+
+// FormatNumber formats v with the given number of decimals using the
+// locale's separators ("1,234.50" in en, "1.234,50" in es).
+func FormatNumber(locale string, v float64, decimals int) string {
+	return formatNumber(Format(locale), v, decimals)
+}
+
+// FormatInteger formats an integer with grouping separators.
+func FormatInteger(locale string, v int64) string {
+	f := Format(locale)
+	neg := v < 0
+	if neg {
+		v = -v
+	}
+	s := group(strconv.FormatInt(v, 10), f.Group)
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// FormatCurrency formats an amount in an ISO 4217 currency ("$1,234.50",
+// "1.234,50 €"). Unknown currencies use the code as the symbol.
+func FormatCurrency(locale string, amount float64, currency string) string {
+	f := Format(locale)
+	code := strings.ToUpper(currency)
+	info, ok := currencies[code]
+	if !ok {
+		info = currencyInfo{symbol: code, decimals: 2}
+	}
+
+	neg := amount < 0
+	num := formatNumber(f, math.Abs(amount), info.decimals)
+
+	var s string
+	if f.CurrencyAfter {
+		s = num + "\u00a0" + info.symbol
+	} else if len(info.symbol) > 1 && !strings.ContainsAny(info.symbol, "$£€¥₹₩") {
+		s = info.symbol + "\u00a0" + num
+	} else {
+		s = info.symbol + num
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// FormatPercent formats a ratio as a percentage (0.256 -> "25.6%").
+func FormatPercent(locale string, ratio float64, decimals int) string {
+	f := Format(locale)
+	s := formatNumber(f, ratio*100, decimals)
+	if f.PercentSpace {
+		return s + "\u00a0%"
+	}
+	return s + "%"
+}
+
+// FormatDate formats t in the locale's date style (DateShort, DateMedium,
+// DateLong or DateFull).
+func FormatDate(locale string, t time.Time, style string) string {
+	f := Format(locale)
+	switch style {
+	case DateShort:
+		return applyDatePattern(f, f.Short, t)
+	case DateLong:
+		return applyDatePattern(f, f.Long, t)
+	case DateFull:
+		return f.Days[t.Weekday()] + ", " + applyDatePattern(f, f.Long, t)
+	default:
+		return applyDatePattern(f, f.Medium, t)
+	}
+}
+
+func formatNumber(f LocaleFormat, v float64, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	intPart, frac, _ := strings.Cut(s, ".")
+	out := group(intPart, f.Group)
+	if frac != "" {
+		out += f.Decimal + frac
+	}
+	if neg && strings.Trim(out, "0"+f.Decimal+f.Group) != "" {
+		out = "-" + out
+	}
+	return out
+}
+
+// group inserts sep every three digits from the right.
+func group(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var sb strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		sb.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
+
+// applyDatePattern expands a CLDR-like pattern. Supported fields: d, dd,
+// m, mm (numeric month), MMM, MMMM (month name), yy, y; text in single
+// quotes is literal.
+func applyDatePattern(f LocaleFormat, pattern string, t time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				sb.WriteString(pattern[i+1:])
+				break
+			}
+			sb.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == c {
+			n++
+		}
+		switch c {
+		case 'd':
+			sb.WriteString(pad(t.Day(), n))
+		case 'm':
+			sb.WriteString(pad(int(t.Month()), n))
+		case 'M':
+			name := f.Months[t.Month()-1]
+			if n == 3 {
+				name = abbreviate(name)
+			}
+			sb.WriteString(name)
+		case 'y':
+			if n == 2 {
+				sb.WriteString(pad(t.Year()%100, 2))
+			} else {
+				sb.WriteString(strconv.Itoa(t.Year()))
+			}
+		default:
+			sb.WriteString(pattern[i : i+n])
+		}
+		i += n
+	}
+	return sb.String()
+}
+
+func pad(v, width int) string {
+	s := strconv.Itoa(v)
+	for len(s) < width {
+		s = "0" + s
+	}
+	return s
+}
+
+// abbreviate shortens a month name to its first three letters.
+func abbreviate(name string) string {
+	r := []rune(name)
+	if len(r) <= 3 {
+		return name
+	}
+	return string(r[:3])
+}
+
+func baseLanguage(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	return strings.ToLower(lang)
+}
+
+// FormatNumber formats a number in the translator's locale.
+func (t *Translator) FormatNumber(v float64, decimals int) string {
+	return FormatNumber(t.Locale(), v, decimals)
+}
+
+// FormatCurrency formats an amount in the translator's locale.
+func (t *Translator) FormatCurrency(amount float64, currency string) string {
+	return FormatCurrency(t.Locale(), amount, currency)
+}
+
+// FormatPercent formats a ratio in the translator's locale.
+func (t *Translator) FormatPercent(ratio float64, decimals int) string {
+	return FormatPercent(t.Locale(), ratio, decimals)
+}
+
+// FormatDate formats a date in the translator's locale.
+func (t *Translator) FormatDate(tm time.Time, style string) string {
+	return FormatDate(t.Locale(), tm, style)
+}
+
+// FuncMap returns template helpers bound to the translator:
+//
+//	{{t "greeting" .Name}}   {{tn "items" .Count}}
+//	{{number .Total 2}}      {{currency .Price "EUR"}}
+//	{{percent .Ratio 1}}     {{date .CreatedAt "long"}}
+//	{{ago .UpdatedAt}}
+func FuncMap(t *Translator) template.FuncMap {
+	return template.FuncMap{
+		"t":        t.T,
+		"tn":       t.TPlural,
+		"number":   t.FormatNumber,
+		"currency": t.FormatCurrency,
+		"percent":  t.FormatPercent,
+		"date":     t.FormatDate,
+		"dir":      t.Direction,
+		"ago": func(tm time.Time) string {
+			return t.RelativeTime(tm, time.Now())
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		locale   string
+		v        float64
+		decimals int
+		want     string
+	}{
+		{"en", 1234567.891, 2, "1,234,567.89"},
+		{"es", 1234567.891, 2, "1.234.567,89"},
+		{"de-AT", 1234.5, 1, "1.234,5"},
+		{"fr", 1234.6, 0, "1\u202f235"},
+		{"en", -0.001, 2, "0.00"},
+		{"en", -42, 0, "-42"},
+		{"xx", 999, 0, "999"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.locale, tt.v, tt.decimals); got != tt.want {
+			t.Errorf("FormatNumber(%q, %v, %d) = %q, want %q", tt.locale, tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		locale   string
+		amount   float64
+		currency string
+		want     string
+	}{
+		{"en", 1234.5, "USD", "$1,234.50"},
+		{"es", 1234.5, "EUR", "1.234,50\u00a0€"},
+		{"en", 1500, "JPY", "¥1,500"},
+		{"en", -3, "GBP", "-£3.00"},
+		{"en", 10, "chf", "CHF\u00a010.00"},
+		{"en", 10, "XYZ", "XYZ\u00a010.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatCurrency(tt.locale, tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatCurrency(%q, %v, %q) = %q, want %q", tt.locale, tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	d := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale, style, want string
+	}{
+		{"en", DateShort, "3/5/24"},
+		{"en", DateMedium, "Mar 5, 2024"},
+		{"en", DateFull, "Tuesday, March 5, 2024"},
+		{"en-GB", DateShort, "05/03/2024"},
+		{"es", DateLong, "5 de marzo de 2024"},
+		{"de", DateMedium, "05.03.2024"},
+	}
+	for _, tt := range tests {
+		if got := FormatDate(tt.locale, d, tt.style); got != tt.want {
+			t.Errorf("FormatDate(%q, %q) = %q, want %q", tt.locale, tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestPluralForm(t *testing.T) {
+	tests := []struct {
+		locale string
+		count  int
+		want   string
+	}{
+		{"en", 1, "one"},
+		{"en", 0, "other"},
+		{"fr", 0, "one"},
+		{"pt-BR", 0, "one"},
+		{"ru", 21, "one"},
+		{"ru", 12, "many"},
+		{"pl", 22, "few"},
+		{"pl", 12, "many"},
+		{"cs", 3, "few"},
+		{"ar", 2, "two"},
+	}
+	for _, tt := range tests {
+		if got := PluralForm(tt.locale, tt.count); got != tt.want {
+			t.Errorf("PluralForm(%q, %d) = %q, want %q", tt.locale, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestDirection(t *testing.T) {
+	tests := map[string]string{
+		"ar":    DirRTL,
+		"he-IL": DirRTL,
+		"fa_IR": DirRTL,
+		"en":    DirLTR,
+		"es-MX": DirLTR,
+		"":      DirLTR,
+	}
+	for locale, want := range tests {
+		if got := Direction(locale); got != want {
+			t.Errorf("Direction(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
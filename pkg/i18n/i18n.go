@@ -163,19 +163,47 @@ func (t *Translator) interpolate(template string, args ...any) string {
 }
 
 func (t *Translator) getPluralKey(locale, key string, count int) string {
+	return key + "." + t.pluralForm(locale, count)
+}
+
+// pluralForm returns the CLDR plural category for count in locale,
+// falling back to the base language ("pt-BR" -> "pt") and then English.
+func (t *Translator) pluralForm(locale string, count int) string {
 	t.mu.RLock()
 	rule := t.pluralRules[locale]
+	if rule == nil {
+		rule = t.pluralRules[baseLanguage(locale)]
+	}
 	t.mu.RUnlock()
 
 	if rule == nil {
 		rule = defaultPluralRules()["en"]
 	}
+	return rule(count)
+}
 
-	form := rule(count)
-	return key + "." + form
+// SetPluralRule sets the plural rule for a locale.
+func (t *Translator) SetPluralRule(locale string, rule PluralRule) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pluralRules[locale] = rule
 }
 
-// Default plural rules for common languages
+// PluralForm returns the CLDR plural category ("zero", "one", "two", "few",
+// "many" or "other") for count in locale.
+func PluralForm(locale string, count int) string {
+	rules := defaultPluralRules()
+	rule := rules[locale]
+	if rule == nil {
+		rule = rules[baseLanguage(locale)]
+	}
+	if rule == nil {
+		rule = rules["en"]
+	}
+	return rule(count)
+}
+
+// Default plural rules for common languages (CLDR, integer counts)
 func defaultPluralRules() map[string]PluralRule {
 	return map[string]PluralRule{
 		"en": func(count int) string {
@@ -202,17 +230,40 @@ func defaultPluralRules() map[string]PluralRule {
 			}
 			return "other"
 		},
-		"ru": func(count int) string {
+		"pt": func(count int) string {
+			if count == 0 || count == 1 {
+				return "one"
+			}
+			return "other"
+		},
+		"it": func(count int) string {
+			if count == 1 {
+				return "one"
+			}
+			return "other"
+		},
+		"ru": slavicPlural,
+		"uk": slavicPlural,
+		"pl": func(count int) string {
 			mod10 := count % 10
 			mod100 := count % 100
-			if mod10 == 1 && mod100 != 11 {
+			if count == 1 {
 				return "one"
 			}
-			if mod10 >= 2 && mod10 <= 4 && (mod100 < 10 || mod100 >= 20) {
+			if mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14) {
 				return "few"
 			}
 			return "many"
 		},
+		"cs": func(count int) string {
+			if count == 1 {
+				return "one"
+			}
+			if count >= 2 && count <= 4 {
+				return "few"
+			}
+			return "other"
+		},
 		"zh": func(count int) string {
 			return "other"
 		},
@@ -244,6 +295,19 @@ func defaultPluralRules() map[string]PluralRule {
 	}
 }
 
+// slavicPlural implements the CLDR rule shared by Russian and Ukrainian.
+func slavicPlural(count int) string {
+	mod10 := count % 10
+	mod100 := count % 100
+	if mod10 == 1 && mod100 != 11 {
+		return "one"
+	}
+	if mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14) {
+		return "few"
+	}
+	return "many"
+}
+
 // Context helpers
 
 type i18nContextKey struct{}
@@ -0,0 +1,167 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+)
+
+// relativeUnit is a step in the relative time scale.
+type relativeUnit struct {
+	name string
+	size time.Duration
+	max  time.Duration // use the next unit at or above this
+}
+
+var relativeUnits = []relativeUnit{
+	{"second", time.Second, time.Minute},
+	{"minute", time.Minute, time.Hour},
+	{"hour", time.Hour, 24 * time.Hour},
+	{"day", 24 * time.Hour, 7 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour, 30 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour, 365 * 24 * time.Hour},
+	{"year", 365 * 24 * time.Hour, 0},
+}
+
+// builtinRelative holds default phrases per language, used when the
+// translator has no "time.relative.*" keys loaded. Keys are
+// "<unit>.<past|future>.<one|other>" plus "now".
+var builtinRelative = map[string]map[string]string{
+	"en": {
+		"now":                 "just now",
+		"second.past.one":     "%1 second ago",
+		"second.past.other":   "%1 seconds ago",
+		"second.future.one":   "in %1 second",
+		"second.future.other": "in %1 seconds",
+		"minute.past.one":     "%1 minute ago",
+		"minute.past.other":   "%1 minutes ago",
+		"minute.future.one":   "in %1 minute",
+		"minute.future.other": "in %1 minutes",
+		"hour.past.one":       "%1 hour ago",
+		"hour.past.other":     "%1 hours ago",
+		"hour.future.one":     "in %1 hour",
+		"hour.future.other":   "in %1 hours",
+		"day.past.one":        "yesterday",
+		"day.past.other":      "%1 days ago",
+		"day.future.one":      "tomorrow",
+		"day.future.other":    "in %1 days",
+		"week.past.one":       "last week",
+		"week.past.other":     "%1 weeks ago",
+		"week.future.one":     "next week",
+		"week.future.other":   "in %1 weeks",
+		"month.past.one":      "last month",
+		"month.past.other":    "%1 months ago",
+		"month.future.one":    "next month",
+		"month.future.other":  "in %1 months",
+		"year.past.one":       "last year",
+		"year.past.other":     "%1 years ago",
+		"year.future.one":     "next year",
+		"year.future.other":   "in %1 years",
+	},
+	"es": {
+		"now":                 "ahora mismo",
+		"second.past.one":     "hace %1 segundo",
+		"second.past.other":   "hace %1 segundos",
+		"second.future.one":   "dentro de %1 segundo",
+		"second.future.other": "dentro de %1 segundos",
+		"minute.past.one":     "hace %1 minuto",
+		"minute.past.other":   "hace %1 minutos",
+		"minute.future.one":   "dentro de %1 minuto",
+		"minute.future.other": "dentro de %1 minutos",
+		"hour.past.one":       "hace %1 hora",
+		"hour.past.other":     "hace %1 horas",
+		"hour.future.one":     "dentro de %1 hora",
+		"hour.future.other":   "dentro de %1 horas",
+		"day.past.one":        "ayer",
+		"day.past.other":      "hace %1 días",
+		"day.future.one":      "mañana",
+		"day.future.other":    "dentro de %1 días",
+		"week.past.one":       "la semana pasada",
+		"week.past.other":     "hace %1 semanas",
+		"week.future.one":     "la próxima semana",
+		"week.future.other":   "dentro de %1 semanas",
+		"month.past.one":      "el mes pasado",
+		"month.past.other":    "hace %1 meses",
+		"month.future.one":    "el próximo mes",
+		"month.future.other":  "dentro de %1 meses",
+		"year.past.one":       "el año pasado",
+		"year.past.other":     "hace %1 años",
+		"year.future.one":     "el próximo año",
+		"year.future.other":   "dentro de %1 años",
+	},
+}
+
+// RelativeTime formats tm relative to now ("3 minutes ago", "in 2 days")
+// in the translator's locale. Phrases can be customized per locale with
+// keys like "time.relative.minute.past.other"; otherwise built-in English
+// and Spanish phrases are used.
+func (t *Translator) RelativeTime(tm, now time.Time) string {
+	d := now.Sub(tm)
+	dir := "past"
+	if d < 0 {
+		dir = "future"
+		d = -d
+	}
+
+	if d < 10*time.Second {
+		return t.relativePhrase("now", 0)
+	}
+
+	unit := relativeUnits[len(relativeUnits)-1]
+	for _, u := range relativeUnits {
+		if u.max == 0 || d < u.max {
+			unit = u
+			break
+		}
+	}
+
+	count := int(d / unit.size)
+	if count < 1 {
+		count = 1
+	}
+
+	form := t.pluralForm(t.Locale(), count)
+	if form != "one" {
+		form = "other"
+	}
+	return t.relativePhrase(unit.name+"."+dir+"."+form, count)
+}
+
+// relativePhrase resolves a relative-time phrase: translator keys first,
+// then built-in phrases for the locale's base language, then English.
+func (t *Translator) relativePhrase(key string, count int) string {
+	t.mu.RLock()
+	locale := t.locale
+	t.mu.RUnlock()
+
+	template := t.get(locale, "time.relative."+key)
+	if template == "" {
+		phrases, ok := builtinRelative[baseLanguage(locale)]
+		if !ok {
+			phrases = builtinRelative["en"]
+		}
+		template = phrases[key]
+	}
+	return t.interpolate(template, count)
+}
+
+// RelativeTimeHTML renders tm as a <time> element that the client keeps
+// up to date ("3 minutes ago" becomes "4 minutes ago") without server round
+// trips, using Intl.RelativeTimeFormat in the translator's locale. The
+// server-rendered text is shown until the client takes over.
+func (t *Translator) RelativeTimeHTML(tm time.Time) string {
+	return fmt.Sprintf(`<time datetime="%s" lv-relative lang="%s">%s</time>`,
+		tm.UTC().Format(time.RFC3339),
+		html.EscapeString(t.Locale()),
+		html.EscapeString(t.RelativeTime(tm, time.Now())),
+	)
+}
+
+// RelativeTime formats tm relative to now using the translator from context.
+func RelativeTime(ctx context.Context, tm time.Time) string {
+	if t := TranslatorFromContext(ctx); t != nil {
+		return t.RelativeTime(tm, time.Now())
+	}
+	return NewTranslator("en").RelativeTime(tm, time.Now())
+}
@@ -0,0 +1,76 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		locale string
+		d      time.Duration // now - tm; negative is the future
+		want   string
+	}{
+		{"en", 0, "just now"},
+		{"en", 9 * time.Second, "just now"},
+		{"en", -9 * time.Second, "just now"},
+		{"en", 10 * time.Second, "10 seconds ago"},
+		{"en", 59 * time.Second, "59 seconds ago"},
+		{"en", time.Minute, "1 minute ago"},
+		{"en", 2 * time.Minute, "2 minutes ago"},
+		{"en", 59 * time.Minute, "59 minutes ago"},
+		{"en", time.Hour, "1 hour ago"},
+		{"en", 23 * time.Hour, "23 hours ago"},
+		{"en", day, "yesterday"},
+		{"en", 3 * day, "3 days ago"},
+		{"en", 7 * day, "last week"},
+		{"en", 30 * day, "last month"},
+		{"en", 400 * day, "last year"},
+		{"en", 800 * day, "2 years ago"},
+		{"en", -59 * time.Second, "in 59 seconds"},
+		{"en", -time.Minute, "in 1 minute"},
+		{"en", -23 * time.Hour, "in 23 hours"},
+		{"en", -day, "tomorrow"},
+		{"en", -3 * day, "in 3 days"},
+		{"en", -14 * day, "in 2 weeks"},
+
+		{"es", 5 * time.Second, "ahora mismo"},
+		{"es", 59 * time.Second, "hace 59 segundos"},
+		{"es", time.Minute, "hace 1 minuto"},
+		{"es", 2 * time.Minute, "hace 2 minutos"},
+		{"es", 23 * time.Hour, "hace 23 horas"},
+		{"es", day, "ayer"},
+		{"es", 2 * day, "hace 2 días"},
+		{"es", -time.Hour, "dentro de 1 hora"},
+		{"es", -day, "mañana"},
+		{"es", -5 * day, "dentro de 5 días"},
+		{"es-MX", 60 * day, "hace 2 meses"},
+
+		{"xx", 2 * time.Hour, "2 hours ago"},
+	}
+	for _, tt := range tests {
+		tr := NewTranslator(tt.locale)
+		if got := tr.RelativeTime(now.Add(-tt.d), now); got != tt.want {
+			t.Errorf("RelativeTime(%q, %v) = %q, want %q", tt.locale, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeTime_Overrides(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+
+	tr := NewTranslator("en")
+	tr.Load("en", map[string]string{
+		"time.relative.minute.past.other": "%1 min ago",
+	})
+
+	if got := tr.RelativeTime(now.Add(-5*time.Minute), now); got != "5 min ago" {
+		t.Errorf("overridden phrase = %q, want %q", got, "5 min ago")
+	}
+	if got := tr.RelativeTime(now.Add(-time.Minute), now); got != "1 minute ago" {
+		t.Errorf("built-in phrase = %q, want %q", got, "1 minute ago")
+	}
+}
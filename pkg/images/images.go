@@ -0,0 +1,205 @@
+// Package images renders responsive image markup (<img srcset> and
+// <picture>) from the variants an image pipeline or resizing CDN produced.
+//
+// Images carry width and height attributes and an aspect-ratio, so the
+// browser reserves their box before they load: content inserted or
+// re-rendered by a live diff does not shift the page.
+package images
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// Variant is one rendition of an image.
+type Variant struct {
+	// URL of the rendition.
+	URL string
+
+	// Width and Height in pixels.
+	Width  int
+	Height int
+
+	// Type is the MIME type ("image/avif", "image/webp", "image/jpeg").
+	// Empty is treated as the type of the original image.
+	Type string
+}
+
+// Options configures the markup of an image.
+type Options struct {
+	// Alt is the alternative text. Decorative images leave it empty.
+	Alt string
+
+	// Sizes is the sizes attribute: the width the image takes in the
+	// layout ("(min-width: 768px) 50vw, 100vw"). Default "100vw".
+	Sizes string
+
+	// Class is the class attribute of the <img>.
+	Class string
+
+	// Eager loads the image right away with high priority, for the
+	// largest image above the fold. Images are lazy by default.
+	Eager bool
+
+	// Attrs are extra <img> attributes, e.g. data-testid.
+	Attrs map[string]string
+}
+
+// typePreference orders <picture> sources: the smallest formats first,
+// since the browser takes the first one it supports.
+var typePreference = []string{"image/avif", "image/webp"}
+
+// fallbackTypes are formats every browser decodes, preferred for the <img>.
+var fallbackTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// Img renders an <img> offering every variant in its srcset. Use it when
+// the variants share one format; Picture serves several.
+func Img(variants []Variant, opts Options) string {
+	if len(variants) == 0 {
+		return ""
+	}
+	return img(byWidth(variants), opts)
+}
+
+// Picture renders a <picture> with a <source> per modern format (AVIF,
+// WebP, then any other) and an <img> for the JPEG, PNG or GIF variants.
+// Without such variants, the least preferred format becomes the <img>.
+func Picture(variants []Variant, opts Options) string {
+	if len(variants) == 0 {
+		return ""
+	}
+
+	groups := make(map[string][]Variant)
+	var types []string
+	for _, v := range variants {
+		if _, ok := groups[v.Type]; !ok {
+			types = append(types, v.Type)
+		}
+		groups[v.Type] = append(groups[v.Type], v)
+	}
+	if len(types) == 1 {
+		return Img(variants, opts)
+	}
+	slices.SortStableFunc(types, func(a, b string) int {
+		return typeRank(a) - typeRank(b)
+	})
+
+	fallback := types[len(types)-1]
+	for _, t := range types {
+		if t == "" || slices.Contains(fallbackTypes, t) {
+			fallback = t
+			break
+		}
+	}
+
+	sizes := opts.Sizes
+	if sizes == "" {
+		sizes = "100vw"
+	}
+	var sb strings.Builder
+	sb.WriteString("<picture>")
+	for _, t := range types {
+		if t == fallback {
+			continue
+		}
+		fmt.Fprintf(&sb, `<source type="%s" srcset="%s" sizes="%s">`,
+			html.EscapeString(t), srcset(byWidth(groups[t])), html.EscapeString(sizes))
+	}
+	sb.WriteString(img(byWidth(groups[fallback]), opts))
+	sb.WriteString("</picture>")
+	return sb.String()
+}
+
+// typeRank returns the position of a type in the sources: preferred
+// formats first, then unknown ones, then the fallback formats.
+func typeRank(t string) int {
+	if i := slices.Index(typePreference, t); i >= 0 {
+		return i
+	}
+	if i := slices.Index(fallbackTypes, t); i >= 0 || t == "" {
+		return len(typePreference) + 1 + i
+	}
+	return len(typePreference)
+}
+
+// img renders the <img> for variants sorted by width. The widest one is the
+// src for browsers without srcset and gives the intrinsic size.
+func img(variants []Variant, opts Options) string {
+	largest := variants[len(variants)-1]
+	sizes := opts.Sizes
+	if sizes == "" {
+		sizes = "100vw"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<img src="%s" alt="%s"`, html.EscapeString(largest.URL), html.EscapeString(opts.Alt))
+	if len(variants) > 1 {
+		fmt.Fprintf(&sb, ` srcset="%s" sizes="%s"`, srcset(variants), html.EscapeString(sizes))
+	}
+	if largest.Width > 0 && largest.Height > 0 {
+		fmt.Fprintf(&sb, ` width="%d" height="%d" style="aspect-ratio:%d/%d;height:auto"`,
+			largest.Width, largest.Height, largest.Width, largest.Height)
+	}
+	if opts.Class != "" {
+		fmt.Fprintf(&sb, ` class="%s"`, html.EscapeString(opts.Class))
+	}
+	if opts.Eager {
+		sb.WriteString(` loading="eager" fetchpriority="high"`)
+	} else {
+		sb.WriteString(` loading="lazy"`)
+	}
+	sb.WriteString(` decoding="async"`)
+	names := make([]string, 0, len(opts.Attrs))
+	for name := range opts.Attrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, ` %s="%s"`, html.EscapeString(name), html.EscapeString(opts.Attrs[name]))
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// srcset returns the srcset of variants sorted by width.
+func srcset(variants []Variant) string {
+	parts := make([]string, 0, len(variants))
+	for _, v := range variants {
+		if v.Width > 0 {
+			parts = append(parts, fmt.Sprintf("%s %dw", html.EscapeString(v.URL), v.Width))
+		} else {
+			parts = append(parts, html.EscapeString(v.URL))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// byWidth returns a copy of variants sorted by width.
+func byWidth(variants []Variant) []Variant {
+	sorted := slices.Clone(variants)
+	slices.SortStableFunc(sorted, func(a, b Variant) int {
+		return a.Width - b.Width
+	})
+	return sorted
+}
+
+// Widths builds the variants of a resizing CDN or image endpoint, which
+// serves any width of an image at url(width). The height follows the
+// original's aspect ratio.
+//
+//	images.Widths(func(w int) string {
+//	    return fmt.Sprintf("/img/%s?w=%d", id, w)
+//	}, 1600, 900, "image/webp", 400, 800, 1600)
+func Widths(url func(width int) string, width, height int, typ string, widths ...int) []Variant {
+	variants := make([]Variant, 0, len(widths))
+	for _, w := range widths {
+		h := 0
+		if width > 0 {
+			h = (height*w + width/2) / width
+		}
+		variants = append(variants, Variant{URL: url(w), Width: w, Height: h, Type: typ})
+	}
+	return variants
+}
@@ -0,0 +1,48 @@
+package images
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestImg_Srcset(t *testing.T) {
+	got := Img([]Variant{
+		{URL: "/p-800.jpg", Width: 800, Height: 600},
+		{URL: "/p-400.jpg", Width: 400, Height: 300},
+	}, Options{Alt: `A "cat"`, Sizes: "50vw", Attrs: map[string]string{"data-testid": "photo"}})
+
+	want := `<img src="/p-800.jpg" alt="A &#34;cat&#34;" srcset="/p-400.jpg 400w, /p-800.jpg 800w" sizes="50vw"` +
+		` width="800" height="600" style="aspect-ratio:800/600;height:auto" loading="lazy" decoding="async" data-testid="photo">`
+	if got != want {
+		t.Errorf("Img =\n%s\nwant\n%s", got, want)
+	}
+
+	if eager := Img([]Variant{{URL: "/hero.jpg"}}, Options{Eager: true}); strings.Contains(eager, "srcset") ||
+		!strings.Contains(eager, `loading="eager" fetchpriority="high"`) {
+		t.Errorf("eager single image = %s", eager)
+	}
+}
+
+func TestPicture_SourcesPerFormat(t *testing.T) {
+	url := func(ext string) func(int) string {
+		return func(w int) string { return fmt.Sprintf("/p-%d.%s", w, ext) }
+	}
+	var variants []Variant
+	variants = append(variants, Widths(url("jpg"), 1600, 900, "image/jpeg", 400, 800)...)
+	variants = append(variants, Widths(url("webp"), 1600, 900, "image/webp", 400, 800)...)
+	variants = append(variants, Widths(url("avif"), 1600, 900, "image/avif", 400, 800)...)
+
+	got := Picture(variants, Options{Alt: "p"})
+
+	avif := strings.Index(got, `<source type="image/avif" srcset="/p-400.avif 400w, /p-800.avif 800w" sizes="100vw">`)
+	webp := strings.Index(got, `<source type="image/webp"`)
+	img := strings.Index(got, `<img src="/p-800.jpg" alt="p" srcset="/p-400.jpg 400w, /p-800.jpg 800w"`)
+	if avif < 0 || webp < avif || img < webp || !strings.HasSuffix(got, "></picture>") {
+		t.Fatalf("Picture = %s", got)
+	}
+	// Heights follow the original's aspect ratio
+	if !strings.Contains(got, `width="800" height="450"`) {
+		t.Errorf("Picture dimensions = %s", got)
+	}
+}
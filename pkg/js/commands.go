@@ -1,364 +1,246 @@
-// Package js provides JavaScript command utilities for GoliveKit.
-// These commands execute on the client without a server roundtrip.
+// Package js builds commands the client runs without a round trip to the
+// server: showing a modal, opening a dropdown, moving focus.
+//
+// Commands chain, and options such as Transition apply to the command
+// before them:
+//
+//	open := js.Show("#modal").Transition(js.TransitionFadeIn).FocusFirst("#modal")
+//
+// A command renders as JSON, so it can be the value of lv-click, which then
+// runs it instead of sending an event (a Push in the chain still sends one):
+//
+//	<button lv-click="{{.Open}}">Edit</button>
+//
+// Handlers send commands with Exec; they run once the diff of the event
+// has been applied.
 package js
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"strings"
-)
+	"maps"
+	"slices"
+	"time"
 
-// Command represents a JavaScript command to execute on the client.
-type Command interface {
-	// ToJS returns the JavaScript code to execute.
-	ToJS() string
-}
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
 
-// Commands holds a sequence of commands.
-type Commands []Command
+// Common transitions, as CSS classes the client applies while an element
+// is shown, hidden or changes class. Apps define them in their styles.
+const (
+	TransitionFadeIn   = "fade-in"
+	TransitionFadeOut  = "fade-out"
+	TransitionSlideIn  = "slide-in"
+	TransitionSlideOut = "slide-out"
+	TransitionScaleIn  = "scale-in"
+	TransitionScaleOut = "scale-out"
+)
 
-// ToJS returns the JavaScript for all commands.
-func (cs Commands) ToJS() string {
-	var parts []string
-	for _, c := range cs {
-		parts = append(parts, c.ToJS())
-	}
-	return strings.Join(parts, ";")
+// JS is a chain of client commands. The zero value is an empty chain, and
+// chains are values: adding to one returns a new chain.
+//
+// Commands whose target is "" act on the element the chain runs on, such
+// as the clicked element of lv-click.
+type JS struct {
+	ops []op
 }
 
-// String implements fmt.Stringer.
-func (cs Commands) String() string {
-	return cs.ToJS()
+// op is one command: its name and arguments, sent as [name, args].
+type op struct {
+	name string
+	args map[string]any
 }
 
-// jsCommand is a simple command holder.
-type jsCommand struct {
-	code string
-}
+// Show starts a chain showing the elements matching to.
+func Show(to string) JS { return JS{}.Show(to) }
 
-func (c jsCommand) ToJS() string {
-	return c.code
-}
+// Hide starts a chain hiding the elements matching to.
+func Hide(to string) JS { return JS{}.Hide(to) }
 
-func (c jsCommand) String() string {
-	return c.code
-}
+// Toggle starts a chain showing or hiding the elements matching to.
+func Toggle(to string) JS { return JS{}.Toggle(to) }
 
-// JS is the namespace for JavaScript commands.
-var JS = jsNamespace{}
+// AddClass starts a chain adding space-separated classes to the elements
+// matching to.
+func AddClass(to, names string) JS { return JS{}.AddClass(to, names) }
 
-type jsNamespace struct{}
+// RemoveClass starts a chain removing classes from the elements matching to.
+func RemoveClass(to, names string) JS { return JS{}.RemoveClass(to, names) }
 
-// Show shows an element.
-func (js jsNamespace) Show(selector string, opts ...ShowOption) Command {
-	config := showConfig{
-		transition: "",
-		time:       200,
-	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// ToggleClass starts a chain toggling classes on the elements matching to.
+func ToggleClass(to, names string) JS { return JS{}.ToggleClass(to, names) }
 
-	if config.transition != "" {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.show("%s",{transition:"%s",time:%d})`, selector, config.transition, config.time)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.show("%s")`, selector)}
-}
+// SetAttr starts a chain setting an attribute of the elements matching to.
+func SetAttr(to, attr, value string) JS { return JS{}.SetAttr(to, attr, value) }
 
-// Hide hides an element.
-func (js jsNamespace) Hide(selector string, opts ...HideOption) Command {
-	config := hideConfig{
-		transition: "",
-		time:       200,
-	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// RemoveAttr starts a chain removing an attribute of the elements matching to.
+func RemoveAttr(to, attr string) JS { return JS{}.RemoveAttr(to, attr) }
 
-	if config.transition != "" {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.hide("%s",{transition:"%s",time:%d})`, selector, config.transition, config.time)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.hide("%s")`, selector)}
-}
+// Dispatch starts a chain dispatching a DOM event on the elements matching to.
+func Dispatch(to, event string) JS { return JS{}.Dispatch(to, event) }
 
-// Toggle toggles element visibility.
-func (js jsNamespace) Toggle(selector string, opts ...ToggleOption) Command {
-	config := toggleConfig{
-		inTransition:  "",
-		outTransition: "",
-		time:          200,
-	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// Focus starts a chain focusing the first element matching to.
+func Focus(to string) JS { return JS{}.Focus(to) }
 
-	if config.inTransition != "" || config.outTransition != "" {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.toggle("%s",{in:"%s",out:"%s",time:%d})`,
-			selector, config.inTransition, config.outTransition, config.time)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.toggle("%s")`, selector)}
-}
+// FocusFirst starts a chain focusing the first focusable element inside
+// the first element matching to.
+func FocusFirst(to string) JS { return JS{}.FocusFirst(to) }
 
-// AddClass adds CSS class(es) to an element.
-func (js jsNamespace) AddClass(selector, class string, opts ...ClassOption) Command {
-	config := classConfig{
-		transition: "",
-		time:       200,
-	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// Push starts a chain sending event to the server.
+func Push(event string) JS { return JS{}.Push(event) }
 
-	if config.transition != "" {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.addClass("%s","%s",{transition:"%s",time:%d})`,
-			selector, class, config.transition, config.time)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.addClass("%s","%s")`, selector, class)}
-}
+// Navigate starts a chain opening another route (see lv-navigate).
+func Navigate(href string) JS { return JS{}.Navigate(href) }
 
-// RemoveClass removes CSS class(es) from an element.
-func (js jsNamespace) RemoveClass(selector, class string, opts ...ClassOption) Command {
-	config := classConfig{
-		transition: "",
-		time:       200,
-	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// Patch starts a chain changing the URL of the current route.
+func Patch(href string) JS { return JS{}.Patch(href) }
 
-	if config.transition != "" {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.removeClass("%s","%s",{transition:"%s",time:%d})`,
-			selector, class, config.transition, config.time)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.removeClass("%s","%s")`, selector, class)}
-}
+// Show shows the elements matching to.
+func (j JS) Show(to string) JS { return j.add("show", map[string]any{"to": to}) }
 
-// ToggleClass toggles CSS class(es) on an element.
-func (js jsNamespace) ToggleClass(selector, class string) Command {
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.toggleClass("%s","%s")`, selector, class)}
-}
+// Hide hides the elements matching to.
+func (j JS) Hide(to string) JS { return j.add("hide", map[string]any{"to": to}) }
 
-// SetAttr sets an attribute on an element.
-func (js jsNamespace) SetAttr(selector, attr, value string) Command {
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.setAttr("%s","%s","%s")`, selector, attr, value)}
-}
+// Toggle shows the elements matching to that are hidden and hides the
+// others. Transition applies when showing, OutTransition when hiding.
+func (j JS) Toggle(to string) JS { return j.add("toggle", map[string]any{"to": to}) }
 
-// RemoveAttr removes an attribute from an element.
-func (js jsNamespace) RemoveAttr(selector, attr string) Command {
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.removeAttr("%s","%s")`, selector, attr)}
+// AddClass adds space-separated classes to the elements matching to.
+func (j JS) AddClass(to, names string) JS {
+	return j.add("add_class", map[string]any{"to": to, "names": names})
 }
 
-// ToggleAttr toggles an attribute on an element.
-func (js jsNamespace) ToggleAttr(selector, attr string, values ...string) Command {
-	if len(values) == 2 {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.toggleAttr("%s","%s",["%s","%s"])`,
-			selector, attr, values[0], values[1])}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.toggleAttr("%s","%s")`, selector, attr)}
+// RemoveClass removes space-separated classes from the elements matching to.
+func (j JS) RemoveClass(to, names string) JS {
+	return j.add("remove_class", map[string]any{"to": to, "names": names})
 }
 
-// Dispatch dispatches a DOM event on an element.
-func (js jsNamespace) Dispatch(selector, event string, opts ...DispatchOption) Command {
-	config := dispatchConfig{}
-	for _, opt := range opts {
-		opt(&config)
-	}
-
-	if config.detail != nil {
-		detailJSON, _ := json.Marshal(config.detail)
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.dispatch("%s","%s",{detail:%s})`,
-			selector, event, string(detailJSON))}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.dispatch("%s","%s")`, selector, event)}
+// ToggleClass toggles space-separated classes on the elements matching to.
+func (j JS) ToggleClass(to, names string) JS {
+	return j.add("toggle_class", map[string]any{"to": to, "names": names})
 }
 
-// Focus sets focus on an element.
-func (js jsNamespace) Focus(selector string) Command {
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.focus("%s")`, selector)}
+// SetAttr sets an attribute of the elements matching to.
+func (j JS) SetAttr(to, attr, value string) JS {
+	return j.add("set_attr", map[string]any{"to": to, "attr": attr, "value": value})
 }
 
-// FocusFirst focuses the first focusable element in a container.
-func (js jsNamespace) FocusFirst(selector string) Command {
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.focusFirst("%s")`, selector)}
+// RemoveAttr removes an attribute of the elements matching to.
+func (j JS) RemoveAttr(to, attr string) JS {
+	return j.add("remove_attr", map[string]any{"to": to, "attr": attr})
 }
 
-// PushFocus pushes focus state before changing it.
-func (js jsNamespace) PushFocus(selector string) Command {
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.pushFocus("%s")`, selector)}
+// Dispatch dispatches a bubbling CustomEvent on the elements matching to,
+// for hooks and other scripts of the page. Detail sets its detail.
+func (j JS) Dispatch(to, event string) JS {
+	return j.add("dispatch", map[string]any{"to": to, "event": event})
 }
 
-// PopFocus restores previous focus state.
-func (js jsNamespace) PopFocus() Command {
-	return jsCommand{code: `liveview.JS.popFocus()`}
-}
+// Focus focuses the first element matching to.
+func (j JS) Focus(to string) JS { return j.add("focus", map[string]any{"to": to}) }
 
-// Push sends an event to the server.
-func (js jsNamespace) Push(event string, opts ...PushOption) Command {
-	config := pushConfig{
-		value: make(map[string]any),
-	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// FocusFirst focuses the first focusable element inside the first element
+// matching to.
+func (j JS) FocusFirst(to string) JS { return j.add("focus_first", map[string]any{"to": to}) }
 
-	valueJSON, _ := json.Marshal(config.value)
-	if config.target != "" {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.push("%s",{value:%s,target:"%s"})`,
-			event, string(valueJSON), config.target)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.push("%s",{value:%s})`, event, string(valueJSON))}
-}
-
-// Navigate navigates to a new page.
-func (js jsNamespace) Navigate(path string, opts ...NavigateOption) Command {
-	config := navigateConfig{}
-	for _, opt := range opts {
-		opt(&config)
-	}
-
-	if config.replace {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.navigate("%s",{replace:true})`, path)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.navigate("%s")`, path)}
-}
+// Push sends event to the server, with the lv-value-* attributes of the
+// element the chain runs on. Value adds values; Target sends it to a
+// LiveComponent.
+func (j JS) Push(event string) JS { return j.add("push", map[string]any{"event": event}) }
 
-// Patch patches the current URL.
-func (js jsNamespace) Patch(path string, opts ...PatchOption) Command {
-	config := patchConfig{}
-	for _, opt := range opts {
-		opt(&config)
-	}
+// Navigate opens another route of the app on the same socket.
+func (j JS) Navigate(href string) JS { return j.add("navigate", map[string]any{"href": href}) }
 
-	if config.replace {
-		return jsCommand{code: fmt.Sprintf(`liveview.JS.patch("%s",{replace:true})`, path)}
-	}
-	return jsCommand{code: fmt.Sprintf(`liveview.JS.patch("%s")`, path)}
-}
+// Patch changes the URL of the current route, which receives it in
+// HandleParams.
+func (j JS) Patch(href string) JS { return j.add("patch", map[string]any{"href": href}) }
 
-// Exec executes arbitrary JavaScript.
-func (js jsNamespace) Exec(code string) Command {
-	return jsCommand{code: code}
-}
-
-// Pipe chains multiple commands.
-func (js jsNamespace) Pipe(commands ...Command) Command {
-	var parts []string
-	for _, cmd := range commands {
-		parts = append(parts, cmd.ToJS())
-	}
-	return jsCommand{code: strings.Join(parts, ";")}
-}
-
-// Option types
-
-type showConfig struct {
-	transition string
-	time       int
-}
-
-type ShowOption func(*showConfig)
-
-func Transition(name string) ShowOption {
-	return func(c *showConfig) {
-		c.transition = name
-	}
-}
+// Transition sets the transition class of the last command: Show, Hide and
+// class changes apply it for their Time, Toggle when it shows.
+func (j JS) Transition(class string) JS { return j.with("transition", class) }
 
-func Time(ms int) ShowOption {
-	return func(c *showConfig) {
-		c.time = ms
-	}
-}
+// OutTransition sets the transition class of the last command, a Toggle,
+// when it hides.
+func (j JS) OutTransition(class string) JS { return j.with("out", class) }
 
-type hideConfig struct {
-	transition string
-	time       int
-}
+// Time sets how long the transition of the last command lasts. The client
+// defaults to 200ms.
+func (j JS) Time(d time.Duration) JS { return j.with("time", d.Milliseconds()) }
 
-type HideOption func(*hideConfig)
+// Display sets the CSS display of the last command, a Show or Toggle, for
+// elements that are not blocks (flex, inline). The default is "block".
+func (j JS) Display(value string) JS { return j.with("display", value) }
 
-type toggleConfig struct {
-	inTransition  string
-	outTransition string
-	time          int
-}
+// Detail sets the detail of the last command, a Dispatch.
+func (j JS) Detail(detail map[string]any) JS { return j.with("detail", detail) }
 
-type ToggleOption func(*toggleConfig)
+// Value adds values to the payload of the last command, a Push.
+func (j JS) Value(values map[string]any) JS { return j.with("value", values) }
 
-func InTransition(name string) ToggleOption {
-	return func(c *toggleConfig) {
-		c.inTransition = name
-	}
-}
+// Target sends the last command, a Push, to the LiveComponent with the
+// given ID (see lv-target).
+func (j JS) Target(id string) JS { return j.with("target", id) }
 
-func OutTransition(name string) ToggleOption {
-	return func(c *toggleConfig) {
-		c.outTransition = name
-	}
-}
+// Replace makes the last command, a Navigate or Patch, replace the current
+// history entry instead of adding one.
+func (j JS) Replace() JS { return j.with("replace", true) }
 
-type classConfig struct {
-	transition string
-	time       int
+// Then appends the commands of other to j.
+func (j JS) Then(other JS) JS {
+	return JS{ops: append(slices.Clip(j.ops), other.ops...)}
 }
 
-type ClassOption func(*classConfig)
-
-type dispatchConfig struct {
-	detail map[string]any
+// IsEmpty reports whether j has no commands.
+func (j JS) IsEmpty() bool {
+	return len(j.ops) == 0
 }
 
-type DispatchOption func(*dispatchConfig)
-
-func Detail(d map[string]any) DispatchOption {
-	return func(c *dispatchConfig) {
-		c.detail = d
+// Ops returns the commands of j as the client receives them, each a
+// [name, args] pair.
+func (j JS) Ops() []any {
+	ops := make([]any, len(j.ops))
+	for i, o := range j.ops {
+		ops[i] = []any{o.name, o.args}
 	}
+	return ops
 }
 
-type pushConfig struct {
-	value  map[string]any
-	target string
+// MarshalJSON encodes j as its Ops.
+func (j JS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Ops())
 }
 
-type PushOption func(*pushConfig)
-
-func Value(v map[string]any) PushOption {
-	return func(c *pushConfig) {
-		c.value = v
+// String returns j as JSON, the value of an lv-click attribute.
+func (j JS) String() string {
+	data, err := j.MarshalJSON()
+	if err != nil {
+		return "[]"
 	}
+	return string(data)
 }
 
-func Target(t string) PushOption {
-	return func(c *pushConfig) {
-		c.target = t
+// Exec runs j in the browser of the socket of ctx, once the diff of the
+// current event or message has been applied. It does nothing outside a
+// connected view, such as during the HTTP render.
+func Exec(ctx context.Context, j JS) {
+	if socket := core.SocketFromContext(ctx); socket != nil && !j.IsEmpty() {
+		socket.ExecJS(j.Ops())
 	}
 }
 
-type navigateConfig struct {
-	replace bool
+func (j JS) add(name string, args map[string]any) JS {
+	return JS{ops: append(slices.Clip(j.ops), op{name: name, args: args})}
 }
 
-type NavigateOption func(*navigateConfig)
-
-func Replace() NavigateOption {
-	return func(c *navigateConfig) {
-		c.replace = true
+// with sets an argument of the last command, copying it so chains sharing
+// a prefix stay independent.
+func (j JS) with(key string, value any) JS {
+	if len(j.ops) == 0 {
+		return j
 	}
+	ops := slices.Clone(j.ops)
+	last := &ops[len(ops)-1]
+	last.args = maps.Clone(last.args)
+	last.args[key] = value
+	return JS{ops: ops}
 }
-
-type patchConfig struct {
-	replace bool
-}
-
-type PatchOption func(*patchConfig)
-
-// Common transitions
-const (
-	TransitionFadeIn    = "fade-in"
-	TransitionFadeOut   = "fade-out"
-	TransitionSlideIn   = "slide-in"
-	TransitionSlideOut  = "slide-out"
-	TransitionScaleIn   = "scale-in"
-	TransitionScaleOut  = "scale-out"
-)
@@ -0,0 +1,72 @@
+package js
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestJS_String(t *testing.T) {
+	cmd := Show("#modal").Transition(TransitionFadeIn).Time(300 * time.Millisecond).
+		FocusFirst("#modal").
+		Push("opened").Value(map[string]any{"id": 7})
+
+	want := `[["show",{"time":300,"to":"#modal","transition":"fade-in"}],` +
+		`["focus_first",{"to":"#modal"}],` +
+		`["push",{"event":"opened","value":{"id":7}}]]`
+	if got := cmd.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if got := (JS{}).String(); got != "[]" {
+		t.Errorf("Empty chain = %s, want []", got)
+	}
+}
+
+func TestJS_ChainsAreValues(t *testing.T) {
+	base := Toggle("#menu")
+	open := base.Transition(TransitionSlideIn)
+	closed := base.OutTransition(TransitionSlideOut).Then(RemoveClass("body", "locked"))
+
+	if got := base.String(); got != `[["toggle",{"to":"#menu"}]]` {
+		t.Errorf("Options changed the chain they were added to: %s", got)
+	}
+	if got := open.String(); got != `[["toggle",{"to":"#menu","transition":"slide-in"}]]` {
+		t.Errorf("open = %s", got)
+	}
+	if len(closed.Ops()) != 2 || len(open.Ops()) != 1 {
+		t.Errorf("Then changed another chain: %v / %v", closed, open)
+	}
+
+	// Options without a command are ignored
+	if !(JS{}).Transition("x").IsEmpty() {
+		t.Error("Expected an empty chain")
+	}
+}
+
+func TestExec(t *testing.T) {
+	socket := core.NewSocket("s", nil)
+	ctx := core.WithSocket(context.Background(), socket)
+
+	Exec(ctx, Hide("#modal"))
+	Exec(ctx, JS{})
+	Exec(ctx, Focus("#search"))
+	Exec(context.Background(), Focus("#ignored"))
+
+	ops := socket.TakeJS()
+	if len(ops) != 2 {
+		t.Fatalf("Expected 2 queued commands, got %v", ops)
+	}
+	if name := ops[1].([]any)[0]; name != "focus" {
+		t.Errorf("Expected focus second, got %v", name)
+	}
+	if rest := socket.TakeJS(); rest != nil {
+		t.Errorf("Expected TakeJS to clear the queue, got %v", rest)
+	}
+
+	payload := &core.DiffPayload{Version: 1, JS: ops}
+	if payload.IsEmpty() {
+		t.Error("A diff carrying commands should be sent")
+	}
+}
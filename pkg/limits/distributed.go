@@ -161,40 +161,6 @@ func (rl *MemoryRateLimiter) GetCount(key string, window time.Duration) int {
 	return count
 }
 
-// RateLimitConfig configures rate limiting.
-type RateLimitConfig struct {
-	// Requests per window
-	Limit int
-
-	// Time window for the limit
-	Window time.Duration
-
-	// Key function extracts the rate limit key from context
-	KeyFunc func(ctx context.Context) string
-
-	// OnLimitExceeded is called when rate limit is exceeded
-	OnLimitExceeded func(ctx context.Context, key string)
-}
-
-// DefaultRateLimitConfig returns default rate limit configuration.
-func DefaultRateLimitConfig() RateLimitConfig {
-	return RateLimitConfig{
-		Limit:  100,
-		Window: time.Minute,
-		KeyFunc: func(ctx context.Context) string {
-			return "global"
-		},
-	}
-}
-
-// MessageRateLimitConfig returns rate limit config for WebSocket messages.
-func MessageRateLimitConfig() RateLimitConfig {
-	return RateLimitConfig{
-		Limit:  100, // 100 messages per second
-		Window: time.Second,
-	}
-}
-
 // SimpleTokenBucket implements a simple in-memory token bucket for testing.
 // For production with keyed rate limiting, use TokenBucket from ratelimit.go.
 type SimpleTokenBucket struct {
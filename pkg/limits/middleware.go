@@ -0,0 +1,188 @@
+package limits
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyFunc returns the key a request is counted under.
+type KeyFunc func(r *http.Request) string
+
+// RateLimitConfig configures a token bucket Limiter.
+type RateLimitConfig struct {
+	// Rate is the number of requests allowed per Period (default 100).
+	Rate int
+
+	// Period is the time over which Rate requests are allowed (default 1
+	// minute).
+	Period time.Duration
+
+	// Burst is the number of requests allowed at once (default Rate).
+	Burst int
+
+	// KeyFunc returns the key requests are counted under (default ByIP).
+	KeyFunc KeyFunc
+
+	// Store keeps the buckets (default a new MemoryStore). Use a
+	// RedisStore to share them between instances.
+	Store Store
+
+	// Name separates the buckets of limiters sharing a Store (default
+	// "http").
+	Name string
+
+	// OnLimitExceeded is called when a request is rejected.
+	OnLimitExceeded func(ctx context.Context, key string)
+}
+
+// DefaultRateLimitConfig returns default rate limit configuration: 100
+// requests per minute per IP.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Rate:    100,
+		Period:  time.Minute,
+		KeyFunc: ByIP,
+	}
+}
+
+// MessageRateLimitConfig returns rate limit config for socket events: 100
+// per second per socket.
+func MessageRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Rate:   100,
+		Period: time.Second,
+		Name:   "events",
+	}
+}
+
+func (c RateLimitConfig) withDefaults() RateLimitConfig {
+	d := DefaultRateLimitConfig()
+	if c.Rate <= 0 {
+		c.Rate = d.Rate
+	}
+	if c.Period <= 0 {
+		c.Period = d.Period
+	}
+	if c.Burst <= 0 {
+		c.Burst = c.Rate
+	}
+	if c.KeyFunc == nil {
+		c.KeyFunc = d.KeyFunc
+	}
+	if c.Store == nil {
+		c.Store = NewMemoryStore()
+	}
+	if c.Name == "" {
+		c.Name = "http"
+	}
+	return c
+}
+
+// Limiter is a token bucket rate limiter over a Store.
+type Limiter struct {
+	config RateLimitConfig
+	rate   float64 // tokens per second
+}
+
+// NewLimiter creates a limiter, filling unset config fields with defaults.
+func NewLimiter(config RateLimitConfig) *Limiter {
+	config = config.withDefaults()
+	return &Limiter{
+		config: config,
+		rate:   float64(config.Rate) / config.Period.Seconds(),
+	}
+}
+
+// Config returns the limiter's configuration, defaults included.
+func (l *Limiter) Config() RateLimitConfig {
+	return l.config
+}
+
+// Allow takes one token from the bucket of key.
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+// AllowN takes n tokens from the bucket of key. OnLimitExceeded is called
+// when they are not available.
+func (l *Limiter) AllowN(ctx context.Context, key string, n int) (Result, error) {
+	res, err := l.config.Store.Take(ctx, l.config.Name+":"+key, n, l.rate, l.config.Burst)
+	if err != nil {
+		return res, err
+	}
+	if !res.Allowed && l.config.OnLimitExceeded != nil {
+		l.config.OnLimitExceeded(ctx, key)
+	}
+	return res, nil
+}
+
+// RateLimit returns HTTP middleware that answers 429 Too Many Requests,
+// with a Retry-After header, once a key used its requests. Every response
+// carries X-RateLimit-Limit and X-RateLimit-Remaining. Requests are let
+// through when the Store fails, so an unreachable Redis does not take the
+// site down.
+func RateLimit(config RateLimitConfig) func(http.Handler) http.Handler {
+	l := NewLimiter(config)
+	return l.Middleware()
+}
+
+// Middleware returns the limiter as HTTP middleware (see RateLimit).
+func (l *Limiter) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := l.Allow(r.Context(), l.config.KeyFunc(r))
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(l.config.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			if !res.Allowed {
+				seconds := int((res.RetryAfter + time.Second - 1) / time.Second)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ByIP keys requests by client IP: the first X-Forwarded-For address, then
+// X-Real-IP, then the connection's address. Only trust the headers behind a
+// proxy that sets them.
+func ByIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		first, _, _ := strings.Cut(xff, ",")
+		return strings.TrimSpace(first)
+	}
+	if xri := r.Header.Get("X-Real-IP"); xri != "" {
+		return xri
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// BySession keys requests by the session cookie named cookie, falling back
+// to ByIP for requests without one. The cookie value is hashed, so session
+// IDs never reach the Store.
+func BySession(cookie string) KeyFunc {
+	return func(r *http.Request) string {
+		c, err := r.Cookie(cookie)
+		if err != nil || c.Value == "" {
+			return "ip:" + ByIP(r)
+		}
+		sum := sha256.Sum256([]byte(c.Value))
+		return "session:" + hex.EncodeToString(sum[:16])
+	}
+}
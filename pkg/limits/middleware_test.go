@@ -0,0 +1,126 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+
+	// 10 tokens per second, bursts of 3
+	for i := 0; i < 3; i++ {
+		if res, _ := s.Take(ctx, "k", 1, 10, 3); !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("take %d: %+v", i+1, res)
+		}
+	}
+	res, _ := s.Take(ctx, "k", 1, 10, 3)
+	if res.Allowed || res.RetryAfter <= 0 || res.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("take over the burst: %+v", res)
+	}
+	if res, _ := s.Take(ctx, "other", 1, 10, 3); !res.Allowed {
+		t.Error("buckets are shared between keys")
+	}
+
+	time.Sleep(res.RetryAfter + 10*time.Millisecond)
+	if res, _ := s.Take(ctx, "k", 1, 10, 3); !res.Allowed {
+		t.Errorf("bucket did not refill: %+v", res)
+	}
+}
+
+func TestRateLimit_Middleware(t *testing.T) {
+	var exceeded []string
+	handler := RateLimit(RateLimitConfig{
+		Rate:            2,
+		Period:          time.Minute,
+		OnLimitExceeded: func(ctx context.Context, key string) { exceeded = append(exceeded, key) },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	get := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	get("10.0.0.1")
+	if rec := get("10.0.0.1"); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("second request: %d, remaining %q", rec.Code, rec.Header().Get("X-RateLimit-Remaining"))
+	}
+	rec := get("10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("third request: %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if len(exceeded) != 1 || exceeded[0] != "10.0.0.1" {
+		t.Errorf("OnLimitExceeded keys = %v", exceeded)
+	}
+	if rec := get("10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("other IP: %d", rec.Code)
+	}
+}
+
+func TestBySession_HashesCookie(t *testing.T) {
+	key := BySession("sid")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	if got := key(req); got != "ip:10.0.0.1" {
+		t.Errorf("without a cookie: %q", got)
+	}
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "secret-session-id"})
+	if got := key(req); !strings.HasPrefix(got, "session:") || strings.Contains(got, "secret") {
+		t.Errorf("with a cookie: %q", got)
+	}
+}
+
+// fakeRedisClient answers the take script without running it, and does
+// not know the script until it was sent with EVAL.
+type fakeRedisClient struct {
+	loaded   bool
+	commands [][]string
+}
+
+func (c *fakeRedisClient) Do(ctx context.Context, args ...string) (any, error) {
+	c.commands = append(c.commands, args)
+	if args[0] == "EVALSHA" && !c.loaded {
+		return nil, errors.New("redis: NOSCRIPT No matching script. Please use EVAL.")
+	}
+	c.loaded = true
+	return []any{int64(0), int64(0), int64(1500)}, nil
+}
+
+func TestRedisStore_Take(t *testing.T) {
+	client := &fakeRedisClient{}
+	s := NewRedisStore(client, "")
+
+	for i := 0; i < 2; i++ {
+		res, err := s.Take(context.Background(), "http:10.0.0.1", 1, 2, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed || res.RetryAfter != 1500*time.Millisecond {
+			t.Errorf("result = %+v", res)
+		}
+	}
+
+	// The script is loaded once, then run by hash
+	var names []string
+	for _, cmd := range client.commands {
+		names = append(names, cmd[0])
+	}
+	if strings.Join(names, ",") != "EVALSHA,EVAL,EVALSHA" {
+		t.Errorf("commands = %v", names)
+	}
+	if args := client.commands[2]; args[3] != "ratelimit:http:10.0.0.1" || args[4] != "2" || args[5] != "5" || args[6] != "1" {
+		t.Errorf("EVALSHA args = %v", args[3:])
+	}
+}
@@ -0,0 +1,97 @@
+package limits
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RedisClient runs Redis commands. *pubsub.RedisPubSub implements it, so
+// the limiter can share the connection settings of the pub/sub.
+type RedisClient interface {
+	// Do runs a command and returns its reply: integers as int64, bulk
+	// strings as []byte and arrays as []any.
+	Do(ctx context.Context, args ...string) (any, error)
+}
+
+// takeScript refills and takes from a bucket atomically, using the server
+// clock so every instance sees the same time. Buckets expire once they
+// would be full again.
+const takeScript = `
+redis.replicate_commands()
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+local b = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(b[1]) or burst
+local ts = tonumber(b[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+else
+	retry = math.ceil((n - tokens) / rate * 1000)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, math.floor(tokens), retry}
+`
+
+var takeScriptSHA = func() string {
+	sum := sha1.Sum([]byte(takeScript))
+	return hex.EncodeToString(sum[:])
+}()
+
+// RedisStore keeps buckets in Redis, so every instance shares them.
+type RedisStore struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisStore creates a store whose keys start with prefix (default
+// "ratelimit:").
+func NewRedisStore(client RedisClient, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "ratelimit:"
+	}
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Take implements Store. The script is run by its hash and loaded with
+// EVAL the first time a server does not know it.
+func (s *RedisStore) Take(ctx context.Context, key string, n int, rate float64, burst int) (Result, error) {
+	args := []string{
+		s.prefix + key,
+		strconv.FormatFloat(rate, 'g', -1, 64),
+		strconv.Itoa(burst),
+		strconv.Itoa(n),
+	}
+	reply, err := s.client.Do(ctx, append([]string{"EVALSHA", takeScriptSHA, "1"}, args...)...)
+	if err != nil && strings.Contains(err.Error(), "NOSCRIPT") {
+		reply, err = s.client.Do(ctx, append([]string{"EVAL", takeScript, "1"}, args...)...)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("limits: redis: %w", err)
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return Result{}, fmt.Errorf("limits: redis: unexpected reply %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	retry, _ := values[2].(int64)
+	return Result{
+		Allowed:    allowed == 1,
+		Remaining:  int(remaining),
+		RetryAfter: time.Duration(retry) * time.Millisecond,
+	}, nil
+}
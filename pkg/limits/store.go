@@ -0,0 +1,105 @@
+package limits
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of taking tokens from a bucket.
+type Result struct {
+	// Allowed is true when the bucket had enough tokens.
+	Allowed bool
+
+	// Remaining is the number of whole tokens left.
+	Remaining int
+
+	// RetryAfter is how long until the request would be allowed (zero
+	// when allowed).
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. A bucket starts full with burst tokens and
+// refills at rate tokens per second, never above burst.
+type Store interface {
+	// Take removes n tokens from the bucket of key when it has them.
+	Take(ctx context.Context, key string, n int, rate float64, burst int) (Result, error)
+}
+
+// retryAfter returns how long a bucket holding tokens takes to reach n.
+func retryAfter(tokens float64, n int, rate float64) time.Duration {
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration((float64(n) - tokens) / rate * float64(time.Second))
+}
+
+// MemoryStore keeps buckets in memory, for single instances. Buckets that
+// refilled completely are dropped.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	swept   time.Time
+}
+
+type memoryBucket struct {
+	tokens float64
+	filled time.Time
+	full   time.Time // when the bucket is full again
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		swept:   time.Now(),
+	}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(ctx context.Context, key string, n int, rate float64, burst int) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(burst), filled: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.filled).Seconds()*rate)
+	b.filled = now
+
+	res := Result{Allowed: b.tokens >= float64(n)}
+	if res.Allowed {
+		b.tokens -= float64(n)
+	} else {
+		res.RetryAfter = retryAfter(b.tokens, n, rate)
+	}
+	res.Remaining = int(b.tokens)
+	b.full = now.Add(retryAfter(b.tokens, burst, rate))
+	return res, nil
+}
+
+// sweep drops the buckets that are full again, once a minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of buckets kept.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
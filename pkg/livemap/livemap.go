@@ -0,0 +1,183 @@
+// Package livemap provides a server-managed map component. The server owns a
+// keyed collection of markers; changes reach the browser as list operations
+// (insert/update/move/delete) that the LiveMap client hook applies to a
+// Leaflet or MapLibre map, so marker state never round-trips as HTML.
+//
+// Usage:
+//
+//	type Tracker struct {
+//		core.BaseComponent
+//		Map *livemap.Map
+//	}
+//
+//	func (t *Tracker) GetLists() map[string][]core.ListItem {
+//		return t.Map.Lists()
+//	}
+//
+//	// In Render: t.Map.Render()
+//	// In the page: <script src="/_live/livemap.js"></script> plus Leaflet or MapLibre.
+package livemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// HookName is the client hook that renders the map.
+const HookName = "LiveMap"
+
+// Marker is a point on the map.
+type Marker struct {
+	ID    string  `json:"id"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+	Label string  `json:"label,omitempty"`
+	Popup string  `json:"popup,omitempty"` // plain text, escaped by the client
+	Color string  `json:"color,omitempty"`
+}
+
+// Map is a keyed marker collection bound to a map element.
+type Map struct {
+	// ID identifies the map element and its list (data-list).
+	ID string
+
+	// Lat, Lng and Zoom set the initial view.
+	Lat  float64
+	Lng  float64
+	Zoom int
+
+	// Height is the CSS height of the map element.
+	Height string
+
+	markers map[string]Marker
+	order   []string
+	mu      sync.RWMutex
+}
+
+// New creates a map centered on lat/lng.
+func New(id string, lat, lng float64, zoom int) *Map {
+	return &Map{
+		ID:      id,
+		Lat:     lat,
+		Lng:     lng,
+		Zoom:    zoom,
+		Height:  "400px",
+		markers: make(map[string]Marker),
+	}
+}
+
+// Put adds a marker or replaces the marker with the same ID (e.g. to move it).
+func (m *Map) Put(marker Marker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.markers[marker.ID]; !exists {
+		m.order = append(m.order, marker.ID)
+	}
+	m.markers[marker.ID] = marker
+}
+
+// Move updates a marker's position. Returns false if the marker is unknown.
+func (m *Map) Move(id string, lat, lng float64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	marker, ok := m.markers[id]
+	if !ok {
+		return false
+	}
+	marker.Lat, marker.Lng = lat, lng
+	m.markers[id] = marker
+	return true
+}
+
+// Remove deletes a marker.
+func (m *Map) Remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.markers[id]; !ok {
+		return
+	}
+	delete(m.markers, id)
+	for i, key := range m.order {
+		if key == id {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Clear removes all markers.
+func (m *Map) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.markers = make(map[string]Marker)
+	m.order = nil
+}
+
+// Get returns a marker by ID.
+func (m *Map) Get(id string) (Marker, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	marker, ok := m.markers[id]
+	return marker, ok
+}
+
+// Markers returns the markers in insertion order.
+func (m *Map) Markers() []Marker {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]Marker, 0, len(m.order))
+	for _, id := range m.order {
+		out = append(out, m.markers[id])
+	}
+	return out
+}
+
+// Len returns the number of markers.
+func (m *Map) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.markers)
+}
+
+// ListItems returns the markers as keyed list items whose content is the
+// marker JSON, for core.ListProvider.
+func (m *Map) ListItems() []core.ListItem {
+	markers := m.Markers()
+	items := make([]core.ListItem, len(markers))
+	for i, marker := range markers {
+		data, _ := json.Marshal(marker)
+		items[i] = core.ListItem{Key: marker.ID, Content: string(data)}
+	}
+	return items
+}
+
+// Lists returns the map's list keyed by its ID. Merge it into the
+// component's GetLists result when the component has other lists.
+func (m *Map) Lists() map[string][]core.ListItem {
+	return map[string][]core.ListItem{m.ID: m.ListItems()}
+}
+
+// Render returns the map element. Initial markers are embedded so the map
+// is populated before the first diff; the element must sit outside
+// data-slot regions so re-renders do not replace it.
+func (m *Map) Render() string {
+	data, _ := json.Marshal(m.Markers())
+
+	return fmt.Sprintf(
+		`<div id="%s" data-list="%s" lv-hook="%s" data-lat="%g" data-lng="%g" data-zoom="%d" data-markers="%s" style="height:%s"></div>`,
+		html.EscapeString(m.ID),
+		html.EscapeString(m.ID),
+		HookName,
+		m.Lat, m.Lng, m.Zoom,
+		html.EscapeString(string(data)),
+		html.EscapeString(m.Height),
+	)
+}
@@ -0,0 +1,43 @@
+package livemap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMap_ListItems(t *testing.T) {
+	m := New("fleet", 0, 0, 3)
+	m.Put(Marker{ID: "a", Lat: 1, Lng: 2})
+	m.Put(Marker{ID: "b", Lat: 3, Lng: 4, Popup: "<b>x</b>"})
+	m.Move("a", 5, 6)
+	m.Remove("missing")
+
+	items := m.Lists()["fleet"]
+	if len(items) != 2 || items[0].Key != "a" || items[1].Key != "b" {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+	if items[0].Content != `{"id":"a","lat":5,"lng":6}` {
+		t.Errorf("Unexpected content: %s", items[0].Content)
+	}
+
+	m.Remove("a")
+	if m.Len() != 1 || m.Markers()[0].ID != "b" {
+		t.Errorf("Expected only b after removal, got %+v", m.Markers())
+	}
+	if m.Move("a", 0, 0) {
+		t.Error("Expected Move on removed marker to fail")
+	}
+}
+
+func TestMap_RenderEscapes(t *testing.T) {
+	m := New(`m"1`, 40.4, -3.7, 12)
+	m.Put(Marker{ID: "x", Popup: `"><script>`})
+
+	out := m.Render()
+	if strings.Contains(out, "<script>") || strings.Contains(out, `m"1`) {
+		t.Errorf("Render did not escape attributes: %s", out)
+	}
+	if !strings.Contains(out, `lv-hook="LiveMap"`) || !strings.Contains(out, `data-zoom="12"`) {
+		t.Errorf("Unexpected render: %s", out)
+	}
+}
@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LevelEnv is the environment variable ConfigureFromEnv reads.
+const LevelEnv = "GOLIVE_LOG_LEVEL"
+
+// Levels holds the log level of a logger and its per-route overrides, and
+// can be changed while the application runs:
+//
+//	levels := logger.Levels()
+//	levels.SetRoute("/chat", slog.LevelDebug) // debug one route
+//	admin.Handle("/debug/log-level", levels.Handler())
+//
+// Routes are path prefixes; the longest one matching a request's path wins.
+// They apply to the loggers RequestLogger puts in request contexts.
+type Levels struct {
+	mu     sync.RWMutex
+	level  slog.Level
+	routes map[string]slog.Level
+}
+
+// NewLevels creates levels with a default level and no routes.
+func NewLevels(level slog.Level) *Levels {
+	return &Levels{level: level, routes: make(map[string]slog.Level)}
+}
+
+// Level returns the default level.
+func (l *Levels) Level() slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level
+}
+
+// Set changes the default level.
+func (l *Levels) Set(level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetRoute sets the level of the requests under the path prefix.
+func (l *Levels) SetRoute(prefix string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.routes[prefix] = level
+}
+
+// ClearRoute removes the level of prefix, so its requests use the default.
+func (l *Levels) ClearRoute(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.routes, prefix)
+}
+
+// Routes returns the route levels by prefix.
+func (l *Levels) Routes() map[string]slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	routes := make(map[string]slog.Level, len(l.routes))
+	for prefix, level := range l.routes {
+		routes[prefix] = level
+	}
+	return routes
+}
+
+// ForPath returns the level of a request path: the level of the longest
+// route prefix it starts with, or the default.
+func (l *Levels) ForPath(path string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	level, longest := l.level, -1
+	for prefix, routeLevel := range l.routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			level, longest = routeLevel, len(prefix)
+		}
+	}
+	return level
+}
+
+// enabled reports whether a message at level is logged for route ("" is
+// no route).
+func (l *Levels) enabled(route string, level slog.Level) bool {
+	if route == "" {
+		return level >= l.Level()
+	}
+	return level >= l.ForPath(route)
+}
+
+// Parse applies a level spec: a default level and route levels, separated
+// by commas, as in "info,/chat=debug,/admin=warn". Either part may be
+// left out. Nothing changes when the spec is invalid.
+func (l *Levels) Parse(spec string) error {
+	var level *slog.Level
+	routes := make(map[string]slog.Level)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		prefix, name, isRoute := strings.Cut(part, "=")
+		if !isRoute {
+			name = part
+		}
+		var parsed slog.Level
+		if err := parsed.UnmarshalText([]byte(name)); err != nil {
+			return fmt.Errorf("logging: invalid level %q", name)
+		}
+		if isRoute {
+			routes[strings.TrimSpace(prefix)] = parsed
+		} else {
+			level = &parsed
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level != nil {
+		l.level = *level
+	}
+	for prefix, routeLevel := range routes {
+		l.routes[prefix] = routeLevel
+	}
+	return nil
+}
+
+// ConfigureFromEnv applies the level spec in GOLIVE_LOG_LEVEL (see Parse),
+// if set.
+func (l *Levels) ConfigureFromEnv() error {
+	spec, ok := os.LookupEnv(LevelEnv)
+	if !ok {
+		return nil
+	}
+	return l.Parse(spec)
+}
+
+// Handler serves the levels for an admin area, behind its authorization:
+//
+//	GET                      {"level":"INFO","routes":{"/chat":"DEBUG"}}
+//	POST ?level=debug        set the default level
+//	POST ?route=/chat&level=debug
+//	DELETE ?route=/chat      back to the default level
+//
+// Every request answers with the levels after the change.
+func (l *Levels) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.URL.Query().Get("route")
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodPut:
+			var level slog.Level
+			if err := level.UnmarshalText([]byte(r.URL.Query().Get("level"))); err != nil {
+				http.Error(w, "invalid level", http.StatusBadRequest)
+				return
+			}
+			if route != "" {
+				l.SetRoute(route, level)
+			} else {
+				l.Set(level)
+			}
+		case http.MethodDelete:
+			if route == "" {
+				http.Error(w, "route required", http.StatusBadRequest)
+				return
+			}
+			l.ClearRoute(route)
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		routes := l.Routes()
+		prefixes := make([]string, 0, len(routes))
+		for prefix := range routes {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		names := make(map[string]string, len(routes))
+		for _, prefix := range prefixes {
+			names[prefix] = routes[prefix].String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"level": l.Level().String(), "routes": names})
+	})
+}
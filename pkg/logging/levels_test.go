@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelsForPath(t *testing.T) {
+	lv := NewLevels(slog.LevelInfo)
+	lv.SetRoute("/chat", slog.LevelDebug)
+	lv.SetRoute("/chat/admin", slog.LevelError)
+
+	tests := map[string]slog.Level{
+		"/":             slog.LevelInfo,
+		"/chat":         slog.LevelDebug,
+		"/chat/room":    slog.LevelDebug,
+		"/chat/admin/x": slog.LevelError,
+		"/counter":      slog.LevelInfo,
+	}
+	for path, want := range tests {
+		if got := lv.ForPath(path); got != want {
+			t.Errorf("ForPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	lv.ClearRoute("/chat")
+	if got := lv.ForPath("/chat/room"); got != slog.LevelInfo {
+		t.Errorf("after ClearRoute, ForPath = %v, want INFO", got)
+	}
+}
+
+func TestLevelsParse(t *testing.T) {
+	lv := NewLevels(slog.LevelInfo)
+	if err := lv.Parse("warn, /chat=debug"); err != nil {
+		t.Fatal(err)
+	}
+	if lv.Level() != slog.LevelWarn || lv.ForPath("/chat") != slog.LevelDebug {
+		t.Errorf("Parse: level %v, /chat %v", lv.Level(), lv.ForPath("/chat"))
+	}
+
+	if err := lv.Parse("error,/x=loud"); err == nil {
+		t.Error("Parse accepted an invalid level")
+	}
+	if lv.Level() != slog.LevelWarn {
+		t.Error("an invalid spec changed the levels")
+	}
+}
+
+func TestLoggerRuntimeLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(WithOutput(&buf))
+
+	logger.Debug("hidden")
+	logger.Levels().Set(slog.LevelDebug)
+	logger.Debug("shown")
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, "shown") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRequestLoggerRouteLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(WithOutput(&buf), WithLevel(slog.LevelWarn))
+	logger.Levels().SetRoute("/chat", slog.LevelDebug)
+
+	handler := RequestLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		L(r.Context()).Debug("handling")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/counter", nil))
+	if buf.Len() != 0 {
+		t.Fatalf("/counter logged at WARN level: %q", buf.String())
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/chat", nil))
+	if !strings.Contains(buf.String(), "handling") {
+		t.Errorf("/chat did not log at DEBUG level: %q", buf.String())
+	}
+}
+
+func TestLevelsHandler(t *testing.T) {
+	lv := NewLevels(slog.LevelInfo)
+	h := lv.Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/?route=/chat&level=debug", nil))
+	if rec.Code != http.StatusOK || lv.ForPath("/chat") != slog.LevelDebug {
+		t.Fatalf("POST route: status %d, level %v", rec.Code, lv.ForPath("/chat"))
+	}
+	if want := `{"level":"INFO","routes":{"/chat":"DEBUG"}}`; strings.TrimSpace(rec.Body.String()) != want {
+		t.Errorf("body = %s, want %s", rec.Body, want)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/?level=loud", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid level: status %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/?route=/chat", nil))
+	if len(lv.Routes()) != 0 {
+		t.Errorf("DELETE left routes %v", lv.Routes())
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(WithOutput(&buf), WithSampling(time.Hour, 2, 3))
+
+	for i := 0; i < 10; i++ {
+		logger.Info("event")
+		logger.Warn("slow")
+	}
+	// Messages 1, 2, 5 and 8 pass
+	if got := strings.Count(buf.String(), "msg=event"); got != 4 {
+		t.Errorf("wrote %d sampled messages, want 4", got)
+	}
+	if got := strings.Count(buf.String(), "msg=slow"); got != 10 {
+		t.Errorf("wrote %d warnings, want 10", got)
+	}
+	if logger.Dropped() != 6 {
+		t.Errorf("Dropped() = %d, want 6", logger.Dropped())
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/redact"
 )
 
 // Logger is the interface for structured logging.
@@ -65,10 +67,19 @@ func Any(key string, value any) Field {
 	return Field{Key: key, Value: value}
 }
 
-// SlogLogger implements Logger using slog.
+// SlogLogger implements Logger using slog. Field values pass through a
+// redact.Policy (redact.Default unless set with WithRedaction), so passwords
+// and tokens are dropped and emails hashed before they are written.
+//
+// Its level can be changed at runtime, for all messages or per route,
+// through Levels.
 type SlogLogger struct {
-	logger *slog.Logger
-	ctx    context.Context
+	logger  *slog.Logger
+	ctx     context.Context
+	policy  *redact.Policy
+	levels  *Levels
+	route   string
+	sampler *sampler
 }
 
 // NewSlogLogger creates a new slog-based logger.
@@ -77,42 +88,59 @@ func NewSlogLogger(opts ...LoggerOption) *SlogLogger {
 		level:  slog.LevelInfo,
 		output: os.Stdout,
 		json:   false,
+		policy: redact.Default,
 	}
 
 	for _, opt := range opts {
 		opt(config)
 	}
 
+	levels := config.levels
+	if levels == nil {
+		levels = NewLevels(config.level)
+	}
+
+	// The handler writes every level; the logger filters with levels, which
+	// can change after the handler is built.
 	var handler slog.Handler
 	if config.json {
 		handler = slog.NewJSONHandler(config.output, &slog.HandlerOptions{
-			Level:     config.level,
+			Level:     minLevel,
 			AddSource: config.addSource,
 		})
 	} else {
 		handler = slog.NewTextHandler(config.output, &slog.HandlerOptions{
-			Level:     config.level,
+			Level:     minLevel,
 			AddSource: config.addSource,
 		})
 	}
 
 	return &SlogLogger{
-		logger: slog.New(handler),
-		ctx:    context.Background(),
+		logger:  slog.New(handler),
+		ctx:     context.Background(),
+		policy:  config.policy,
+		levels:  levels,
+		sampler: config.sampler,
 	}
 }
 
+// minLevel is below every level a Logger writes.
+const minLevel = slog.LevelDebug - 4
+
 type loggerConfig struct {
 	level     slog.Level
 	output    io.Writer
 	json      bool
 	addSource bool
+	policy    *redact.Policy
+	levels    *Levels
+	sampler   *sampler
 }
 
 // LoggerOption configures the logger.
 type LoggerOption func(*loggerConfig)
 
-// WithLevel sets the log level.
+// WithLevel sets the initial log level.
 func WithLevel(level slog.Level) LoggerOption {
 	return func(c *loggerConfig) {
 		c.level = level
@@ -140,48 +168,96 @@ func WithSource() LoggerOption {
 	}
 }
 
+// WithRedaction sets the policy applied to field values. nil disables
+// redaction.
+func WithRedaction(p *redact.Policy) LoggerOption {
+	return func(c *loggerConfig) {
+		c.policy = p
+	}
+}
+
+// WithLevels makes the logger use lv for its levels, so several loggers
+// can be controlled together. WithLevel is ignored.
+func WithLevels(lv *Levels) LoggerOption {
+	return func(c *loggerConfig) {
+		c.levels = lv
+	}
+}
+
+// Levels returns the levels of the logger, to change them at runtime.
+func (l *SlogLogger) Levels() *Levels {
+	return l.levels
+}
+
+// ForRoute returns a logger that uses the level of the route path (see
+// Levels.SetRoute). RequestLogger calls it for each request.
+func (l *SlogLogger) ForRoute(path string) Logger {
+	c := l.clone()
+	c.route = path
+	return c
+}
+
+func (l *SlogLogger) clone() *SlogLogger {
+	c := *l
+	return &c
+}
+
+// log writes msg if level is enabled for the logger's route and the
+// sampler lets it through.
+func (l *SlogLogger) log(level slog.Level, msg string, fields []Field) {
+	if !l.levels.enabled(l.route, level) {
+		return
+	}
+	if l.sampler != nil && !l.sampler.allow(level, msg) {
+		return
+	}
+	l.logger.Log(l.ctx, level, msg, l.toAttrs(fields)...)
+}
+
 func (l *SlogLogger) toAttrs(fields []Field) []any {
 	attrs := make([]any, 0, len(fields)*2)
 	for _, f := range fields {
-		attrs = append(attrs, f.Key, f.Value)
+		value, ok := l.policy.Value(f.Key, f.Value)
+		if !ok {
+			continue
+		}
+		attrs = append(attrs, f.Key, value)
 	}
 	return attrs
 }
 
 // Debug logs a debug message.
 func (l *SlogLogger) Debug(msg string, fields ...Field) {
-	l.logger.DebugContext(l.ctx, msg, l.toAttrs(fields)...)
+	l.log(slog.LevelDebug, msg, fields)
 }
 
 // Info logs an info message.
 func (l *SlogLogger) Info(msg string, fields ...Field) {
-	l.logger.InfoContext(l.ctx, msg, l.toAttrs(fields)...)
+	l.log(slog.LevelInfo, msg, fields)
 }
 
 // Warn logs a warning message.
 func (l *SlogLogger) Warn(msg string, fields ...Field) {
-	l.logger.WarnContext(l.ctx, msg, l.toAttrs(fields)...)
+	l.log(slog.LevelWarn, msg, fields)
 }
 
 // Error logs an error message.
 func (l *SlogLogger) Error(msg string, fields ...Field) {
-	l.logger.ErrorContext(l.ctx, msg, l.toAttrs(fields)...)
+	l.log(slog.LevelError, msg, fields)
 }
 
 // With returns a logger with additional fields.
 func (l *SlogLogger) With(fields ...Field) Logger {
-	return &SlogLogger{
-		logger: l.logger.With(l.toAttrs(fields)...),
-		ctx:    l.ctx,
-	}
+	c := l.clone()
+	c.logger = l.logger.With(l.toAttrs(fields)...)
+	return c
 }
 
 // WithContext returns a logger with context.
 func (l *SlogLogger) WithContext(ctx context.Context) Logger {
-	return &SlogLogger{
-		logger: l.logger,
-		ctx:    ctx,
-	}
+	c := l.clone()
+	c.ctx = ctx
+	return c
 }
 
 // Context helpers
@@ -246,12 +322,23 @@ func (NopLogger) Error(msg string, fields ...Field) {}
 func (l NopLogger) With(fields ...Field) Logger     { return l }
 func (l NopLogger) WithContext(ctx context.Context) Logger { return l }
 
-// RequestLogger logs HTTP requests.
+// routeLogger is implemented by loggers with per-route levels.
+type routeLogger interface {
+	ForRoute(path string) Logger
+}
+
+// RequestLogger logs HTTP requests. With a logger that has per-route levels
+// (see Levels.SetRoute), each request logs at the level of its path.
 func RequestLogger(logger Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
+			logger := logger
+			if rl, ok := logger.(routeLogger); ok {
+				logger = rl.ForRoute(r.URL.Path)
+			}
+
 			// Create request ID
 			reqID := r.Header.Get("X-Request-ID")
 			if reqID == "" {
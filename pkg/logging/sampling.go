@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithSampling thins out repeated messages, such as a log line per live
+// event: in every tick, the first `first` messages with the same level and
+// text are written, then one in every `thereafter` (none if thereafter is
+// 0). Only Debug and Info messages are sampled; warnings and errors are
+// always written.
+//
+//	logging.NewSlogLogger(logging.WithSampling(time.Second, 100, 50))
+func WithSampling(tick time.Duration, first, thereafter int) LoggerOption {
+	return func(c *loggerConfig) {
+		c.sampler = &sampler{
+			tick:       tick,
+			first:      int64(first),
+			thereafter: int64(thereafter),
+			counts:     make(map[sampleKey]int64),
+		}
+	}
+}
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+// sampler counts messages by level and text, per tick.
+type sampler struct {
+	tick       time.Duration
+	first      int64
+	thereafter int64
+
+	mu      sync.Mutex
+	counts  map[sampleKey]int64
+	resetAt time.Time
+	dropped atomic.Int64
+}
+
+// allow reports whether a message is written, and counts it.
+func (s *sampler) allow(level slog.Level, msg string) bool {
+	if level > slog.LevelInfo {
+		return true
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	if now.After(s.resetAt) {
+		clear(s.counts)
+		s.resetAt = now.Add(s.tick)
+	}
+	key := sampleKey{level, msg}
+	s.counts[key]++
+	n := s.counts[key]
+	s.mu.Unlock()
+
+	if n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// Dropped returns how many messages sampling has skipped (0 without
+// WithSampling).
+func (l *SlogLogger) Dropped() int64 {
+	if l.sampler == nil {
+		return 0
+	}
+	return l.sampler.dropped.Load()
+}
@@ -2,11 +2,16 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
 )
 
 // Metrics holds all application metrics.
@@ -25,6 +30,13 @@ type Metrics struct {
 	RenderDuration *Histogram
 	DiffSize       *Histogram
 
+	// Diff vs full render decisions (label "mode": "diff" or "full")
+	DiffDecisions  *CounterVec
+	DiffBytesSaved *Counter
+
+	// Component telemetry events (label "event", see pkg/telemetry)
+	TelemetryEvents *CounterVec
+
 	// Errors
 	ErrorsTotal *CounterVec
 	PanicsTotal *Counter
@@ -50,21 +62,33 @@ func NewMetrics(namespace string) *Metrics {
 
 		RenderCount:    NewCounter(namespace+"_render_total", "Total render operations"),
 		RenderDuration: NewHistogram(namespace+"_render_duration_seconds", "Render duration"),
-		DiffSize:       NewHistogram(namespace+"_diff_size_bytes", "Diff size in bytes"),
+		DiffSize:       NewHistogramWithBuckets(namespace+"_diff_size_bytes", "Diff size in bytes", SizeBuckets),
+
+		DiffDecisions:  NewCounterVec(namespace+"_diff_decisions_total", "Updates sent as slot diffs or full renders", "mode"),
+		DiffBytesSaved: NewCounter(namespace+"_diff_bytes_saved_total", "Bytes saved by choosing the smaller update"),
+
+		TelemetryEvents: NewCounterVec(namespace+"_telemetry_events_total", "Telemetry events emitted by components", "event"),
 
 		ErrorsTotal: NewCounterVec(namespace+"_errors_total", "Total errors", "type"),
 		PanicsTotal: NewCounter(namespace+"_panics_total", "Total panics recovered"),
 
-		MemoryPerSocket:  NewHistogram(namespace+"_memory_per_socket_bytes", "Memory per socket"),
+		MemoryPerSocket:  NewHistogramWithBuckets(namespace+"_memory_per_socket_bytes", "Memory per socket", SizeBuckets),
 		GoroutinesActive: NewGauge(namespace+"_goroutines_active", "Active goroutines"),
 
 		custom: make(map[string]any),
 	}
 }
 
-// Handler returns an HTTP handler for metrics.
+// Handler returns an HTTP handler for metrics. Scrapers that accept
+// OpenMetrics (Prometheus with exemplar storage enabled) get
+// WriteOpenMetrics, with histogram buckets and their exemplars.
 func (m *Metrics) Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if acceptsOpenMetrics(r) {
+			w.Header().Set("Content-Type", OpenMetricsContentType)
+			m.WriteOpenMetrics(w)
+			return
+		}
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 
 		// Output in Prometheus format
@@ -84,6 +108,13 @@ func (m *Metrics) Handler() http.Handler {
 		for label, value := range m.ErrorsTotal.Values() {
 			m.writeMetricWithLabel(w, "errors_total", "type", label, value)
 		}
+		for label, value := range m.DiffDecisions.Values() {
+			m.writeMetricWithLabel(w, "diff_decisions_total", "mode", label, value)
+		}
+		m.writeMetric(w, "diff_bytes_saved_total", m.DiffBytesSaved.Value())
+		for label, value := range m.TelemetryEvents.Values() {
+			m.writeMetricWithLabel(w, "telemetry_events_total", "event", label, value)
+		}
 
 		// Histograms
 		m.writeHistogram(w, "message_latency_seconds", m.MessageLatency)
@@ -237,6 +268,13 @@ func (cv *CounterVec) Values() map[string]float64 {
 	return result
 }
 
+// DefaultBuckets are the bucket upper bounds of duration histograms, in
+// seconds.
+var DefaultBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// SizeBuckets are the bucket upper bounds of byte size histograms.
+var SizeBuckets = []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576}
+
 // Histogram tracks the distribution of values.
 type Histogram struct {
 	name   string
@@ -246,21 +284,58 @@ type Histogram struct {
 	count  int64
 	min    float64
 	max    float64
-	mu     sync.Mutex
+
+	// Per-bucket counts and latest exemplars; the last entry is +Inf
+	buckets   []float64
+	counts    []int64
+	exemplars []*Exemplar
+
+	mu sync.Mutex
 }
 
-// NewHistogram creates a new histogram.
+// NewHistogram creates a new histogram with DefaultBuckets.
 func NewHistogram(name, help string) *Histogram {
+	return NewHistogramWithBuckets(name, help, DefaultBuckets)
+}
+
+// NewHistogramWithBuckets creates a histogram with the given sorted bucket
+// upper bounds. A +Inf bucket is always added.
+func NewHistogramWithBuckets(name, help string, buckets []float64) *Histogram {
 	return &Histogram{
-		name:   name,
-		help:   help,
-		values: make([]float64, 0),
-		min:    -1,
+		name:      name,
+		help:      help,
+		values:    make([]float64, 0),
+		min:       -1,
+		buckets:   append([]float64(nil), buckets...),
+		counts:    make([]int64, len(buckets)+1),
+		exemplars: make([]*Exemplar, len(buckets)+1),
 	}
 }
 
 // Observe records a value.
 func (h *Histogram) Observe(value float64) {
+	h.observe(value, nil)
+}
+
+// ObserveExemplar records a value with an exemplar: labels (usually a
+// trace_id) that identify one request behind the value. Each bucket keeps
+// its latest exemplar, so a spike in a slow bucket points at a trace that
+// landed in it.
+func (h *Histogram) ObserveExemplar(value float64, labels map[string]string) {
+	h.observe(value, &Exemplar{Labels: labels, Value: value, Timestamp: time.Now()})
+}
+
+// ObserveContext records a value with the trace of ctx as its exemplar
+// (see tracing.WithTraceID). Outside a trace it is Observe.
+func (h *Histogram) ObserveContext(ctx context.Context, value float64) {
+	if id := tracing.TraceID(ctx); id != "" {
+		h.ObserveExemplar(value, map[string]string{"trace_id": id})
+		return
+	}
+	h.Observe(value)
+}
+
+func (h *Histogram) observe(value float64, exemplar *Exemplar) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -275,6 +350,12 @@ func (h *Histogram) Observe(value float64) {
 		h.max = value
 	}
 
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.counts[i]++
+	if exemplar != nil {
+		h.exemplars[i] = exemplar
+	}
+
 	// Keep only last 10000 values to bound memory
 	if len(h.values) > 10000 {
 		h.values = h.values[5000:]
@@ -313,6 +394,39 @@ func (h *Histogram) Stats() HistogramStats {
 	return stats
 }
 
+// Buckets returns the cumulative bucket counts, ending with +Inf, each
+// with the latest exemplar observed in it.
+func (h *Histogram) Buckets() []Bucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := make([]Bucket, len(h.counts))
+	var cumulative int64
+	for i, n := range h.counts {
+		cumulative += n
+		bound := math.Inf(1)
+		if i < len(h.buckets) {
+			bound = h.buckets[i]
+		}
+		result[i] = Bucket{UpperBound: bound, Count: cumulative, Exemplar: h.exemplars[i]}
+	}
+	return result
+}
+
+// Bucket is a cumulative histogram bucket.
+type Bucket struct {
+	UpperBound float64
+	Count      int64
+	Exemplar   *Exemplar // nil when no value in the bucket had one
+}
+
+// Exemplar links an observed value to the request that produced it.
+type Exemplar struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
 // HistogramStats contains histogram statistics.
 type HistogramStats struct {
 	Count int64
@@ -364,8 +478,33 @@ func RecordError(errType string) {
 	GlobalMetrics.ErrorsTotal.Inc(errType)
 }
 
+// RecordDiffDecision records whether an update was sent as a slot diff or a
+// full render ("diff" or "full") and how many bytes the choice saved.
+func RecordDiffDecision(mode string, diffSize, fullSize int) {
+	GlobalMetrics.DiffDecisions.Inc(mode)
+	saved := fullSize - diffSize
+	if mode == "full" {
+		saved = -saved
+	}
+	if saved > 0 {
+		GlobalMetrics.DiffBytesSaved.Add(int64(saved))
+	}
+}
+
+// RecordTelemetryEvent counts n occurrences of a telemetry event (more
+// than one when the event is sampled).
+func RecordTelemetryEvent(name string, n int64) {
+	GlobalMetrics.TelemetryEvents.WithLabel(name).Add(n)
+}
+
 func RecordRender(duration time.Duration, diffSize int) {
+	RecordRenderContext(context.Background(), duration, diffSize)
+}
+
+// RecordRenderContext records a render like RecordRender, with the trace of
+// ctx as the exemplar of its duration and diff size buckets.
+func RecordRenderContext(ctx context.Context, duration time.Duration, diffSize int) {
 	GlobalMetrics.RenderCount.Inc()
-	GlobalMetrics.RenderDuration.ObserveDuration(duration)
-	GlobalMetrics.DiffSize.Observe(float64(diffSize))
+	GlobalMetrics.RenderDuration.ObserveContext(ctx, duration.Seconds())
+	GlobalMetrics.DiffSize.ObserveContext(ctx, float64(diffSize))
 }
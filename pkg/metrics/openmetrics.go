@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenMetricsContentType is the content type of WriteOpenMetrics.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// acceptsOpenMetrics reports whether the scraper asked for OpenMetrics.
+func acceptsOpenMetrics(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+}
+
+// WriteOpenMetrics writes the metrics in the OpenMetrics text format.
+// Histograms are written as buckets, each followed by its latest exemplar,
+// so Grafana can link a slow render bucket to the trace of the session that
+// rendered it:
+//
+//	golivekit_render_duration_seconds_bucket{le="0.25"} 1021 # {trace_id="4bf92f35"} 0.21 1718000000.123
+func (m *Metrics) WriteOpenMetrics(w io.Writer) {
+	writeFamily(w, "connections_active", "gauge", m.ConnectionsActive.help, func() {
+		writeSample(w, "connections_active", "", "", m.ConnectionsActive.Value())
+	})
+	writeFamily(w, "goroutines_active", "gauge", m.GoroutinesActive.help, func() {
+		writeSample(w, "goroutines_active", "", "", m.GoroutinesActive.Value())
+	})
+
+	for _, c := range []struct {
+		name    string
+		counter *Counter
+	}{
+		{"connections", m.ConnectionsTotal},
+		{"render", m.RenderCount},
+		{"panics", m.PanicsTotal},
+		{"diff_bytes_saved", m.DiffBytesSaved},
+	} {
+		writeFamily(w, c.name, "counter", c.counter.help, func() {
+			writeSample(w, c.name+"_total", "", "", c.counter.Value())
+		})
+	}
+
+	for _, c := range []struct {
+		name  string
+		label string
+		vec   *CounterVec
+	}{
+		{"messages_received", "type", m.MessagesReceived},
+		{"messages_sent", "type", m.MessagesSent},
+		{"errors", "type", m.ErrorsTotal},
+		{"diff_decisions", "mode", m.DiffDecisions},
+		{"telemetry_events", "event", m.TelemetryEvents},
+	} {
+		writeFamily(w, c.name, "counter", c.vec.help, func() {
+			values := c.vec.Values()
+			labels := make([]string, 0, len(values))
+			for label := range values {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
+			for _, label := range labels {
+				writeSample(w, c.name+"_total", c.label, label, values[label])
+			}
+		})
+	}
+
+	for _, h := range []struct {
+		name      string
+		histogram *Histogram
+	}{
+		{"message_latency_seconds", m.MessageLatency},
+		{"render_duration_seconds", m.RenderDuration},
+		{"diff_size_bytes", m.DiffSize},
+	} {
+		writeFamily(w, h.name, "histogram", h.histogram.help, func() {
+			writeHistogramBuckets(w, h.name, h.histogram)
+		})
+	}
+
+	io.WriteString(w, "# EOF\n")
+}
+
+func writeFamily(w io.Writer, name, typ, help string, samples func()) {
+	if help != "" {
+		fmt.Fprintf(w, "# HELP golivekit_%s %s\n", name, escapeLabel(help))
+	}
+	fmt.Fprintf(w, "# TYPE golivekit_%s %s\n", name, typ)
+	samples()
+}
+
+func writeSample(w io.Writer, name, labelName, labelValue string, value float64) {
+	if labelName == "" {
+		fmt.Fprintf(w, "golivekit_%s %s\n", name, formatFloat(value))
+		return
+	}
+	fmt.Fprintf(w, "golivekit_%s{%s=\"%s\"} %s\n", name, labelName, escapeLabel(labelValue), formatFloat(value))
+}
+
+func writeHistogramBuckets(w io.Writer, name string, h *Histogram) {
+	buckets := h.Buckets()
+	for _, b := range buckets {
+		fmt.Fprintf(w, "golivekit_%s_bucket{le=\"%s\"} %d", name, formatFloat(b.UpperBound), b.Count)
+		if e := b.Exemplar; e != nil {
+			fmt.Fprintf(w, " # {%s} %s %s", exemplarLabels(e.Labels), formatFloat(e.Value),
+				strconv.FormatFloat(float64(e.Timestamp.UnixMilli())/1000, 'f', 3, 64))
+		}
+		io.WriteString(w, "\n")
+	}
+	stats := h.Stats()
+	fmt.Fprintf(w, "golivekit_%s_count %d\n", name, stats.Count)
+	fmt.Fprintf(w, "golivekit_%s_sum %s\n", name, formatFloat(stats.Sum))
+}
+
+// exemplarLabels formats an exemplar's label set, sorted by name.
+func exemplarLabels(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=\"%s\"", name, escapeLabel(labels[name]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
+)
+
+func TestHistogram_Exemplars(t *testing.T) {
+	h := NewHistogramWithBuckets("test_seconds", "", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.ObserveContext(tracing.WithTraceID(context.Background(), "trace-slow"), 0.5)
+	h.ObserveContext(context.Background(), 0.7)
+	h.Observe(3)
+
+	buckets := h.Buckets()
+	if len(buckets) != 3 {
+		t.Fatalf("got %d buckets, want 3 with +Inf", len(buckets))
+	}
+	for i, want := range []int64{1, 3, 4} {
+		if buckets[i].Count != want {
+			t.Errorf("bucket %v count = %d, want %d", buckets[i].UpperBound, buckets[i].Count, want)
+		}
+	}
+	if e := buckets[1].Exemplar; e == nil || e.Labels["trace_id"] != "trace-slow" || e.Value != 0.5 {
+		t.Errorf("bucket 1 exemplar = %+v, want trace-slow", e)
+	}
+	if buckets[0].Exemplar != nil || buckets[2].Exemplar != nil {
+		t.Error("buckets without traced values have exemplars")
+	}
+}
+
+func TestHandler_OpenMetrics(t *testing.T) {
+	m := NewMetrics("golivekit")
+	m.RenderCount.Inc()
+	m.RenderDuration.ObserveExemplar(0.2, map[string]string{"trace_id": "abc"})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != OpenMetricsContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE golivekit_render counter\ngolivekit_render_total 1\n",
+		"# TYPE golivekit_render_duration_seconds histogram\n",
+		`golivekit_render_duration_seconds_bucket{le="0.25"} 1 # {trace_id="abc"} 0.2 `,
+		`golivekit_render_duration_seconds_bucket{le="+Inf"} 1` + "\n",
+		"golivekit_render_duration_seconds_count 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in:\n%s", want, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Error("body does not end with # EOF")
+	}
+
+	rec = httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if strings.Contains(rec.Body.String(), "# EOF") {
+		t.Error("plain scrape got OpenMetrics")
+	}
+}
@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strconv"
+)
+
+// ErrPending is returned by "status -check" when migrations are pending.
+var ErrPending = errors.New("migrate: pending migrations")
+
+// Main runs the migrate command given in os.Args and exits. Applications
+// call it from cmd/migrate, with the embedded migrations of each database in
+// golive.toml, after importing their database/sql drivers:
+//
+//	import _ "github.com/jackc/pgx/v5/stdlib"
+//
+//	func main() {
+//		migrate.Main(map[string]fs.FS{"main": migrations.FS})
+//	}
+func Main(sources map[string]fs.FS) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := Run(ctx, os.Args[1:], sources, os.Stdout)
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Run runs a migrate command:
+//
+//	up                 apply pending migrations
+//	down [n]           roll back the last n migrations (default 1)
+//	status [-check]    list migrations; -check fails when some are pending
+//
+// Flags before the command select the configuration: -config (default
+// golive.toml), -env (default $GOLIVE_ENV, then development) and -db (a
+// database name; default all).
+func Run(ctx context.Context, args []string, sources map[string]fs.FS, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	configPath := flags.String("config", ConfigFile, "configuration file")
+	env := flags.String("env", DefaultEnv(), "environment")
+	only := flags.String("db", "", "database (default: all)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("usage: migrate [-env name] [-db name] up | down [n] | status [-check]")
+	}
+	command, rest := flags.Arg(0), flags.Args()[1:]
+
+	steps, check := 1, false
+	switch command {
+	case "up":
+	case "down":
+		if len(rest) > 0 {
+			n, err := strconv.Atoi(rest[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("migrate: down: invalid step count %q", rest[0])
+			}
+			steps = n
+		}
+	case "status":
+		check = len(rest) > 0 && (rest[0] == "-check" || rest[0] == "--check")
+	default:
+		return fmt.Errorf("migrate: unknown command %q", command)
+	}
+
+	cfg, err := LoadConfig(*configPath, *env)
+	if err != nil {
+		return err
+	}
+
+	pending := false
+	for _, db := range cfg.Databases {
+		if *only != "" && db.Name != *only {
+			continue
+		}
+		fsys, ok := sources[db.Name]
+		if !ok {
+			return fmt.Errorf("migrate: no migrations given to Main for database %q", db.Name)
+		}
+		if db.DSN == "" {
+			return fmt.Errorf("migrate: [environments.%s] has no connection string for %q", cfg.Env, db.Name)
+		}
+
+		conn, err := sql.Open(db.Driver, db.DSN)
+		if err != nil {
+			return fmt.Errorf("migrate: %s: %w (is the driver imported?)", db.Name, err)
+		}
+		m, err := New(conn, db.Dialect, fsys)
+		if err == nil {
+			var p bool
+			p, err = runCommand(ctx, m, db.Name, command, steps, out)
+			pending = pending || p
+		}
+		conn.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if check && pending {
+		return ErrPending
+	}
+	return nil
+}
+
+// runCommand runs command on one database, reporting whether migrations are
+// pending.
+func runCommand(ctx context.Context, m *Migrator, name, command string, steps int, out io.Writer) (bool, error) {
+	switch command {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, mig := range done {
+			fmt.Fprintf(out, "%s: applied %s\n", name, mig)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Fprintf(out, "%s: up to date\n", name)
+		}
+		return false, err
+	case "down":
+		done, err := m.Down(ctx, steps)
+		for _, mig := range done {
+			fmt.Fprintf(out, "%s: rolled back %s\n", name, mig)
+		}
+		return false, err
+	default:
+		states, err := m.Status(ctx)
+		if err != nil {
+			return false, err
+		}
+		pending := false
+		for _, s := range states {
+			at := "pending"
+			if s.Applied {
+				at = s.AppliedAt.Format("2006-01-02 15:04:05")
+			} else {
+				pending = true
+			}
+			fmt.Fprintf(out, "%s: %-19s %s\n", name, at, s.Migration)
+		}
+		return pending, nil
+	}
+}
+
+// DefaultEnv returns $GOLIVE_ENV, or "development".
+func DefaultEnv() string {
+	if env := os.Getenv("GOLIVE_ENV"); env != "" {
+		return env
+	}
+	return "development"
+}
@@ -0,0 +1,179 @@
+package migrate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigFile is the project configuration read by the CLI and Main.
+const ConfigFile = "golive.toml"
+
+// Config is the migration setup of one environment, from golive.toml:
+//
+//	[migrate]
+//	auto = true                # apply pending migrations on golive dev
+//
+//	[databases.main]
+//	driver = "pgx"             # database/sql driver name
+//	dir = "migrations"
+//
+//	[databases.analytics]
+//	driver = "sqlite3"
+//	dir = "migrations/analytics"
+//
+//	[environments.development]
+//	main = "postgres://localhost/app_dev"
+//	analytics = "file:analytics.db"
+//
+//	[environments.production]
+//	main = "${DATABASE_URL}"
+//
+// Connection strings expand environment variables.
+type Config struct {
+	Env       string
+	Auto      bool
+	Databases []Database // sorted by name
+}
+
+// Database is a database of the environment.
+type Database struct {
+	Name    string
+	Driver  string
+	Dialect Dialect
+	Dir     string
+	DSN     string // empty when the environment does not set it
+}
+
+// Database returns the database named name.
+func (c *Config) Database(name string) (Database, bool) {
+	for _, db := range c.Databases {
+		if db.Name == name {
+			return db, true
+		}
+	}
+	return Database{}, false
+}
+
+// LoadConfig reads the configuration of env from path.
+func LoadConfig(path, env string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseConfig(f, env)
+}
+
+// ParseConfig parses the configuration of env.
+func ParseConfig(r io.Reader, env string) (*Config, error) {
+	tables, err := parseTOML(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Env: env}
+	cfg.Auto, _ = tables["migrate"]["auto"].(bool)
+
+	dsns := tables["environments."+env]
+	for name, table := range tables {
+		dbName, ok := strings.CutPrefix(name, "databases.")
+		if !ok {
+			continue
+		}
+		db := Database{Name: dbName, Dir: "migrations"}
+		db.Driver, _ = table["driver"].(string)
+		if dir, ok := table["dir"].(string); ok {
+			db.Dir = dir
+		}
+		db.Dialect = DialectFor(db.Driver)
+		if d, ok := table["dialect"].(string); ok {
+			db.Dialect = Dialect(d)
+		}
+		if db.Dialect == "" {
+			return nil, fmt.Errorf("migrate: %s: database %q: unknown driver %q, set dialect", ConfigFile, dbName, db.Driver)
+		}
+		if dsn, ok := dsns[dbName].(string); ok {
+			db.DSN = os.ExpandEnv(dsn)
+		}
+		cfg.Databases = append(cfg.Databases, db)
+	}
+	sort.Slice(cfg.Databases, func(i, j int) bool { return cfg.Databases[i].Name < cfg.Databases[j].Name })
+	return cfg, nil
+}
+
+// parseTOML parses the TOML subset golive.toml uses: [table] headers and
+// key = value pairs with string, integer and boolean values.
+func parseTOML(r io.Reader) (map[string]map[string]any, error) {
+	tables := map[string]map[string]any{"": {}}
+	current := ""
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: unterminated table header", ConfigFile, n)
+			}
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if tables[current] == nil {
+				tables[current] = make(map[string]any)
+			}
+			continue
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", ConfigFile, n)
+		}
+		key, raw = strings.Trim(strings.TrimSpace(key), `"`), strings.TrimSpace(raw)
+		value, err := parseTOMLValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", ConfigFile, n, err)
+		}
+		tables[current][key] = value
+	}
+	return tables, scanner.Err()
+}
+
+func parseTOMLValue(raw string) (any, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		return strconv.Unquote(raw)
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return nil, fmt.Errorf("unterminated string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	case raw == "true" || raw == "false":
+		return raw == "true", nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value %s", raw)
+	}
+	return n, nil
+}
+
+// stripComment removes a # comment outside quotes.
+func stripComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,305 @@
+// Package migrate applies versioned SQL migrations from an fs.FS, usually
+// embedded in the application, to PostgreSQL, MySQL or SQLite databases.
+//
+// Migrations are pairs of files named after a version and a description:
+//
+//	migrations/20261016120000_create_users.up.sql
+//	migrations/20261016120000_create_users.down.sql
+//
+// Applied versions are recorded in the schema_migrations table. Each
+// migration runs in its own transaction, where the database allows it.
+//
+//	//go:embed *.sql
+//	var FS embed.FS
+//
+//	m, err := migrate.New(db, migrate.Postgres, migrations.FS)
+//	applied, err := m.Up(ctx)
+//
+// The golive CLI runs migrations through the application's cmd/migrate,
+// which calls Main with the embedded files of each database in golive.toml.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/internal/sqlutil"
+)
+
+// Dialect is a SQL database flavor.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+// DialectFor returns the dialect of a database/sql driver name ("pgx",
+// "mysql", "sqlite3"...), or "" when unknown.
+func DialectFor(driver string) Dialect {
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql", "pgx", "pq":
+		return Postgres
+	case "mysql":
+		return MySQL
+	case "sqlite", "sqlite3", "libsql":
+		return SQLite
+	}
+	return ""
+}
+
+// placeholder returns the bind parameter for position n (1-based).
+func (d Dialect) placeholder(n int) string {
+	if d == Postgres {
+		return sqlutil.PostgresPlaceholder(n)
+	}
+	return "?"
+}
+
+// ErrNoDown is returned when rolling back a migration without a down file.
+var ErrNoDown = errors.New("migrate: migration has no down file")
+
+// Migration is a versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string // empty when irreversible
+}
+
+// String returns the migration's file prefix, "20261016120000_create_users".
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Load reads the migrations in the root of fsys, sorted by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migrate: %s: expected <version>_<name>.up.sql or .down.sql", name)
+		}
+		v, desc, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: invalid version %q", name, v)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(".", name))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: desc}
+			byVersion[version] = m
+		} else if m.Name != desc {
+			return nil, fmt.Errorf("migrate: version %d is used by %q and %q", version, m.Name, desc)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrate: %s has no up file", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// State is a migration and whether it has been applied.
+type State struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	DB      *sql.DB
+	Dialect Dialect
+
+	// Table records the applied versions (default "schema_migrations").
+	Table string
+
+	migrations []Migration
+}
+
+// New loads the migrations of fsys for db.
+func New(db *sql.DB, dialect Dialect, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		DB:         db,
+		Dialect:    dialect,
+		Table:      "schema_migrations",
+		migrations: migrations,
+	}, nil
+}
+
+// Migrations returns the loaded migrations, oldest first.
+func (m *Migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at BIGINT NOT NULL
+)`, m.Table)
+	if _, err := m.DB.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("migrate: create %s: %w", m.Table, err)
+	}
+	return nil
+}
+
+// applied returns the applied versions with their times.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.QueryContext(ctx, fmt.Sprintf("SELECT version, applied_at FROM %s", m.Table))
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read %s: %w", m.Table, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version, at int64
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("migrate: scan: %w", err)
+		}
+		applied[version] = time.UnixMilli(at)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate: rows: %w", err)
+	}
+	return applied, nil
+}
+
+// Status returns every migration with whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]State, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]State, len(m.migrations))
+	for i, mig := range m.migrations {
+		at, ok := applied[mig.Version]
+		states[i] = State{Migration: mig, Applied: ok, AppliedAt: at}
+	}
+	return states, nil
+}
+
+// Pending returns the migrations not applied yet, oldest first.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	states, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range states {
+		if !s.Applied {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations, oldest first, stopping at the first
+// failure. It returns the migrations applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, mig := range pending {
+		insert := fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%s, %s, %s)",
+			m.Table, m.Dialect.placeholder(1), m.Dialect.placeholder(2), m.Dialect.placeholder(3))
+		if err := m.run(ctx, mig, mig.Up, insert, mig.Version, mig.Name, time.Now().UnixMilli()); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first. It
+// returns the migrations rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	states, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(states) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := states[i].Migration
+		if !states[i].Applied {
+			continue
+		}
+		if mig.Down == "" {
+			return done, fmt.Errorf("%w: %s", ErrNoDown, mig)
+		}
+		del := fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.Table, m.Dialect.placeholder(1))
+		if err := m.run(ctx, mig, mig.Down, del, mig.Version); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// run executes a migration's statements and the bookkeeping statement in a
+// transaction. MySQL commits DDL implicitly, so a failed MySQL migration
+// can leave part of its changes behind.
+func (m *Migrator) run(ctx context.Context, mig Migration, body, record string, args ...any) error {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: %s: %w", mig, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range Split(body) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migrate: %s: %w", mig, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("migrate: %s: record: %w", mig, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate: %s: %w", mig, err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// fakeDB is the state of a database opened with the "migratetest" driver:
+// it records statements and keeps schema_migrations in memory.
+type fakeDB struct {
+	mu       sync.Mutex
+	execs    []string
+	versions map[int64]int64
+	fail     string // statements containing it fail
+}
+
+var (
+	fakeMu  sync.Mutex
+	fakeDBs = map[string]*fakeDB{}
+)
+
+func openFake(dsn string) *fakeDB {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	if fakeDBs[dsn] == nil {
+		fakeDBs[dsn] = &fakeDB{versions: make(map[int64]int64)}
+	}
+	return fakeDBs[dsn]
+}
+
+// resetFake empties the database named dsn.
+func resetFake(dsn string) *fakeDB {
+	fakeMu.Lock()
+	delete(fakeDBs, dsn)
+	fakeMu.Unlock()
+	return openFake(dsn)
+}
+
+func init() {
+	sql.Register("migratetest", fakeDriver{})
+	sql.Register("sqlite", fakeDriver{}) // for Run, which picks the dialect from the driver name
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) { return &fakeConn{db: openFake(dsn)}, nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *fakeConn) Commit() error                       { return nil }
+func (c *fakeConn) Rollback() error                     { return nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	db := c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	switch {
+	case db.fail != "" && strings.Contains(query, db.fail):
+		return nil, errors.New("syntax error")
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		db.versions[args[0].Value.(int64)] = args[2].Value.(int64)
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations"):
+		delete(db.versions, args[0].Value.(int64))
+	case !strings.Contains(query, "schema_migrations"):
+		db.execs = append(db.execs, query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	db := c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	rows := &fakeRows{}
+	for v, at := range db.versions {
+		rows.data = append(rows.data, []driver.Value{v, at})
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	data [][]driver.Value
+	i    int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"version", "applied_at"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.i])
+	r.i++
+	return nil
+}
+
+var testMigrations = fstest.MapFS{
+	"1_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);\nCREATE INDEX users_id ON users (id);")},
+	"1_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	"2_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD email TEXT;")},
+	"README.md":               {Data: []byte("ignored")},
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].String() != "1_create_users" || migrations[1].Down != "" {
+		t.Errorf("Unexpected migrations %+v", migrations)
+	}
+
+	if _, err := Load(fstest.MapFS{"1_x.down.sql": {}}); err == nil {
+		t.Error("Expected an error for a migration without an up file")
+	}
+	if _, err := Load(fstest.MapFS{"x_y.up.sql": {}}); err == nil {
+		t.Error("Expected an error for an invalid version")
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	ctx := context.Background()
+	db := resetFake(t.Name())
+	conn, _ := sql.Open("migratetest", t.Name())
+	defer conn.Close()
+
+	m, err := New(conn, SQLite, testMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, err := m.Up(ctx)
+	if err != nil || len(done) != 2 {
+		t.Fatalf("Expected 2 migrations applied, got %v, %v", done, err)
+	}
+	if len(db.execs) != 3 {
+		t.Errorf("Expected the up files split into 3 statements, got %q", db.execs)
+	}
+	if pending, _ := m.Pending(ctx); len(pending) != 0 {
+		t.Errorf("Expected nothing pending, got %v", pending)
+	}
+
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrNoDown) {
+		t.Errorf("Expected ErrNoDown for an irreversible migration, got %v", err)
+	}
+
+	db.versions = map[int64]int64{1: 0}
+	done, err = m.Down(ctx, 5)
+	if err != nil || len(done) != 1 || len(db.versions) != 0 {
+		t.Errorf("Expected migration 1 rolled back, got %v, %v, %v", done, err, db.versions)
+	}
+}
+
+func TestMigrator_StopsAtFailure(t *testing.T) {
+	ctx := context.Background()
+	resetFake(t.Name()).fail = "ALTER"
+	conn, _ := sql.Open("migratetest", t.Name())
+	defer conn.Close()
+
+	m, _ := New(conn, Postgres, testMigrations)
+	done, err := m.Up(ctx)
+	if err == nil || len(done) != 1 || !strings.Contains(err.Error(), "2_add_email") {
+		t.Errorf("Expected the second migration to fail, got %v, %v", done, err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	sql := `CREATE TABLE a (s TEXT DEFAULT 'x;y'); -- trailing; comment
+/* block; comment */
+CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql;
+SELECT $1;
+-- only a comment;
+`
+	stmts := Split(sql)
+	if len(stmts) != 3 {
+		t.Fatalf("Expected 3 statements, got %d: %q", len(stmts), stmts)
+	}
+	if !strings.Contains(stmts[1], "RETURN NEW; END;") {
+		t.Errorf("Expected the dollar-quoted body kept whole, got %q", stmts[1])
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Setenv("PROD_DB", "postgres://prod")
+	cfg, err := ParseConfig(strings.NewReader(`
+[migrate]
+auto = true # apply on dev
+
+[databases.main]
+driver = "pgx"
+
+[databases.analytics]
+driver = "sqlite3"
+dir = "migrations/analytics"
+
+[environments.production]
+main = "${PROD_DB}"
+analytics = 'file:#analytics.db'
+`), "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	main, _ := cfg.Database("main")
+	analytics, _ := cfg.Database("analytics")
+	if !cfg.Auto || main.Dialect != Postgres || main.Dir != "migrations" || main.DSN != "postgres://prod" {
+		t.Errorf("Unexpected main database %+v", main)
+	}
+	if analytics.Dialect != SQLite || analytics.DSN != "file:#analytics.db" {
+		t.Errorf("Unexpected analytics database %+v", analytics)
+	}
+
+	if _, err := ParseConfig(strings.NewReader("[databases.x]\ndriver = \"odbc\""), "dev"); err == nil {
+		t.Error("Expected an error for an unknown driver without dialect")
+	}
+}
+
+func TestRun_StatusCheck(t *testing.T) {
+	resetFake(t.Name())
+	dir := t.TempDir()
+	config := filepath.Join(dir, ConfigFile)
+	os.WriteFile(config, []byte("[databases.main]\ndriver = \"sqlite\"\n[environments.test]\nmain = \""+t.Name()+"\"\n"), 0644)
+
+	var out bytes.Buffer
+	sources := map[string]fs.FS{"main": testMigrations}
+	err := Run(context.Background(), []string{"-config", config, "-env", "test", "status", "-check"}, sources, &out)
+	if !errors.Is(err, ErrPending) || !strings.Contains(out.String(), "pending") {
+		t.Errorf("Expected ErrPending, got %v:\n%s", err, out.String())
+	}
+
+	if err := Run(context.Background(), []string{"-config", config, "-env", "test", "up"}, sources, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(context.Background(), []string{"-config", config, "-env", "test", "status", "-check"}, sources, &out); err != nil {
+		t.Errorf("Expected no pending migrations after up, got %v", err)
+	}
+}
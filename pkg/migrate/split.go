@@ -0,0 +1,91 @@
+package migrate
+
+import "strings"
+
+// Split splits a migration into statements at the semicolons outside
+// quotes, comments and PostgreSQL dollar-quoted bodies, so files with
+// several statements run on drivers that execute one at a time (MySQL
+// without multiStatements). Empty statements are dropped.
+func Split(sql string) []string {
+	var stmts []string
+	start := 0
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(sql, i, c)
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			i = skipUntil(sql, i, "\n")
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			i = skipUntil(sql, i+2, "*/")
+		case c == '$':
+			if tag, ok := dollarTag(sql[i:]); ok {
+				i = skipUntil(sql, i+len(tag), tag)
+			}
+		case c == ';':
+			stmts = appendStatement(stmts, sql[start:i])
+			start = i + 1
+		}
+	}
+	return appendStatement(stmts, sql[start:])
+}
+
+func appendStatement(stmts []string, s string) []string {
+	if isBlank(s) {
+		return stmts
+	}
+	return append(stmts, strings.TrimSpace(s))
+}
+
+// isBlank reports whether s holds only whitespace and comments.
+func isBlank(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+// skipQuoted returns the index of the quote closing the one at i; doubled
+// quotes are escapes.
+func skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] == '\\' && quote != '`' {
+			j++
+			continue
+		}
+		if s[j] == quote {
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j
+		}
+	}
+	return len(s)
+}
+
+// skipUntil returns the index of the last byte of end's next occurrence
+// at or after i (len(s) when missing).
+func skipUntil(s string, i int, end string) int {
+	j := strings.Index(s[i:], end)
+	if j < 0 {
+		return len(s)
+	}
+	return i + j + len(end) - 1
+}
+
+// dollarTag returns the $tag$ opening s, if any.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		if c == '$' {
+			return s[:j+1], true
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
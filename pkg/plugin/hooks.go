@@ -41,6 +41,10 @@ const (
 	// Error hooks
 	HookOnError HookPoint = "onError"
 	HookOnPanic HookPoint = "onPanic"
+
+	// Telemetry hooks (analytics plugins): Metadata holds "event" and
+	// "fields", see pkg/telemetry
+	HookOnTelemetry HookPoint = "onTelemetry"
 )
 
 // AllHookPoints returns all available hook points.
@@ -53,6 +57,7 @@ func AllHookPoints() []HookPoint {
 		HookBeforeAssign, HookAfterAssign, HookOnStateRestore,
 		HookBeforeSend, HookAfterReceive,
 		HookOnError, HookOnPanic,
+		HookOnTelemetry,
 	}
 }
 
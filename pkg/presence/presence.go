@@ -1,8 +1,20 @@
 // Package presence provides user presence tracking for GoliveKit.
+//
+// Each node (server process) owns the entries of its own sockets and
+// replicates them to the other nodes over pubsub, on the topic's
+// "<topic>:presence" channel. Every node keeps, per remote node, the last
+// state it saw with the node's clock, which grows with each change. States
+// merge CRDT-style: for each node the higher clock wins, so deltas arriving
+// late, twice or out of order converge to the same list everywhere. A node
+// that sees a gap in a clock asks for the full state, and nodes that stop
+// sending heartbeats are dropped with their entries.
 package presence
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
@@ -10,14 +22,66 @@ import (
 	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
 )
 
+// DiffEvent is the client event name for presence diffs, for components
+// forwarding them with Socket.Push(DiffEvent, diff.ToMap()).
+const DiffEvent = "presence_diff"
+
+// Config configures replication between nodes.
+type Config struct {
+	// NodeID names this node; random by default.
+	NodeID string
+
+	// HeartbeatInterval is how often the node announces its clock
+	// (default 5s).
+	HeartbeatInterval time.Duration
+
+	// NodeTimeout drops nodes not heard from for this long, with their
+	// entries (default 3 heartbeats).
+	NodeTimeout time.Duration
+}
+
+// DefaultConfig returns the default replication settings.
+func DefaultConfig() Config {
+	return Config{
+		HeartbeatInterval: 5 * time.Second,
+		NodeTimeout:       15 * time.Second,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	d := DefaultConfig()
+	if c.NodeID == "" {
+		c.NodeID = newNodeID()
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = d.HeartbeatInterval
+	}
+	if c.NodeTimeout <= 0 {
+		c.NodeTimeout = 3 * c.HeartbeatInterval
+	}
+	return c
+}
+
 // Presence tracks user presence in a topic.
 type Presence struct {
-	topic     string
-	presences map[string]*PresenceInfo
-	pubsub    pubsub.PubSub
-	onJoin    []func(PresenceInfo)
-	onLeave   []func(PresenceInfo)
-	mu        sync.RWMutex
+	topic    string
+	config   Config
+	pubsub   pubsub.PubSub
+	sub      pubsub.Subscription
+	nodes    map[string]*nodeState
+	watchers map[*core.Socket]struct{}
+	onJoin   []func(PresenceInfo)
+	onLeave  []func(PresenceInfo)
+	stop     chan struct{}
+	closed   sync.Once
+	mu       sync.RWMutex
+}
+
+// nodeState is what a node knows of one node's entries.
+type nodeState struct {
+	clock    uint64
+	lastSeen time.Time
+	entries  map[string]PresenceInfo
 }
 
 // PresenceInfo contains information about a present user.
@@ -34,120 +98,144 @@ type PresenceInfo struct {
 	// OnlineAt is when the user came online.
 	OnlineAt time.Time `json:"online_at"`
 
-	// PhxRef is the Phoenix reference (for compatibility).
+	// PhxRef is the Phoenix reference (for compatibility): the node and
+	// its clock at the last change of the entry.
 	PhxRef string `json:"phx_ref,omitempty"`
 
+	// Node is the node the socket is connected to.
+	Node string `json:"node,omitempty"`
+
 	// Metas contains additional metadata.
 	Metas map[string]any `json:"metas,omitempty"`
 }
 
 // NewPresence creates a new presence tracker for a topic.
 func NewPresence(topic string, ps pubsub.PubSub) *Presence {
+	return NewPresenceWithConfig(topic, ps, Config{})
+}
+
+// NewPresenceWithConfig creates a presence tracker with custom replication
+// settings. With a pubsub, it asks the other nodes for their state and
+// starts sending heartbeats until Close.
+func NewPresenceWithConfig(topic string, ps pubsub.PubSub, config Config) *Presence {
+	config = config.withDefaults()
 	p := &Presence{
-		topic:     topic,
-		presences: make(map[string]*PresenceInfo),
-		pubsub:    ps,
-		onJoin:    make([]func(PresenceInfo), 0),
-		onLeave:   make([]func(PresenceInfo), 0),
+		topic:    topic,
+		config:   config,
+		pubsub:   ps,
+		nodes:    map[string]*nodeState{config.NodeID: {entries: make(map[string]PresenceInfo)}},
+		watchers: make(map[*core.Socket]struct{}),
+		onJoin:   make([]func(PresenceInfo), 0),
+		onLeave:  make([]func(PresenceInfo), 0),
+		stop:     make(chan struct{}),
 	}
 
-	// Subscribe to presence events
 	if ps != nil {
-		ps.Subscribe(topic+":presence", func(msg []byte) {
-			p.handlePresenceMessage(msg)
-		})
+		p.sub, _ = ps.Subscribe(p.channel(), p.handlePresenceMessage)
+		p.publish(presenceMessage{Type: msgSync})
+		go p.heartbeat()
 	}
 
 	return p
 }
 
-// Track adds a socket to presence tracking.
-func (p *Presence) Track(socket *core.Socket, info PresenceInfo) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// channel is the pubsub topic carrying replication messages.
+func (p *Presence) channel() string {
+	return p.topic + ":presence"
+}
+
+// Topic returns the tracked topic.
+func (p *Presence) Topic() string {
+	return p.topic
+}
 
+// Track adds a socket to presence tracking. The entry is removed when the
+// socket closes.
+func (p *Presence) Track(socket *core.Socket, info PresenceInfo) error {
 	info.Key = socket.ID()
 	info.OnlineAt = time.Now()
 
-	p.presences[socket.ID()] = &info
+	p.mu.Lock()
+	local := p.local()
+	local.clock++
+	info.Node = p.config.NodeID
+	info.PhxRef = p.ref(local.clock)
+	previous, existed := local.entries[info.Key]
+	local.entries[info.Key] = info
+	msg := presenceMessage{Type: msgDelta, Clock: local.clock, Joins: []PresenceInfo{info}}
+	p.mu.Unlock()
 
-	// Notify local handlers
-	for _, handler := range p.onJoin {
-		go handler(info)
-	}
-
-	// Broadcast to other nodes
-	if p.pubsub != nil {
-		msg := presenceMessage{
-			Type: "join",
-			Info: info,
-		}
-		data, _ := json.Marshal(msg)
-		p.pubsub.Publish(p.topic+":presence", data)
+	diff := PresenceDiff{Topic: p.topic, Joins: []PresenceInfo{info}}
+	if existed {
+		diff.Leaves = []PresenceInfo{previous}
+		msg.Leaves = []string{previous.Key}
 	}
+	p.changed(diff)
+	p.publish(msg)
 
+	socket.OnClose(func() { p.Untrack(socket) })
 	return nil
 }
 
 // Untrack removes a socket from presence tracking.
 func (p *Presence) Untrack(socket *core.Socket) error {
 	p.mu.Lock()
-	info, exists := p.presences[socket.ID()]
-	if exists {
-		delete(p.presences, socket.ID())
-	}
-	p.mu.Unlock()
-
+	local := p.local()
+	info, exists := local.entries[socket.ID()]
 	if !exists {
+		p.mu.Unlock()
 		return nil
 	}
+	delete(local.entries, socket.ID())
+	local.clock++
+	msg := presenceMessage{Type: msgDelta, Clock: local.clock, Leaves: []string{info.Key}}
+	p.mu.Unlock()
 
-	// Notify local handlers
-	for _, handler := range p.onLeave {
-		go handler(*info)
-	}
-
-	// Broadcast to other nodes
-	if p.pubsub != nil {
-		msg := presenceMessage{
-			Type: "leave",
-			Info: *info,
-		}
-		data, _ := json.Marshal(msg)
-		p.pubsub.Publish(p.topic+":presence", data)
-	}
-
+	p.changed(PresenceDiff{Topic: p.topic, Leaves: []PresenceInfo{info}})
+	p.publish(msg)
 	return nil
 }
 
-// Update updates presence metadata for a socket.
+// Update updates presence metadata for a socket. Watchers see the entry
+// leave and join again with the new metadata.
 func (p *Presence) Update(socket *core.Socket, metas map[string]any) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	info, exists := p.presences[socket.ID()]
+	local := p.local()
+	previous, exists := local.entries[socket.ID()]
 	if !exists {
+		p.mu.Unlock()
 		return nil
 	}
 
-	if info.Metas == nil {
-		info.Metas = make(map[string]any)
+	info := previous
+	info.Metas = make(map[string]any, len(previous.Metas)+len(metas))
+	for k, v := range previous.Metas {
+		info.Metas[k] = v
 	}
 	for k, v := range metas {
 		info.Metas[k] = v
 	}
+	local.clock++
+	info.PhxRef = p.ref(local.clock)
+	local.entries[info.Key] = info
+	msg := presenceMessage{Type: msgDelta, Clock: local.clock, Joins: []PresenceInfo{info}, Leaves: []string{info.Key}}
+	p.mu.Unlock()
 
+	p.changed(PresenceDiff{Topic: p.topic, Joins: []PresenceInfo{info}, Leaves: []PresenceInfo{previous}})
+	p.publish(msg)
 	return nil
 }
 
-// List returns all currently present users.
+// List returns all currently present users, on every node.
 func (p *Presence) List() []PresenceInfo {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	result := make([]PresenceInfo, 0, len(p.presences))
-	for _, info := range p.presences {
-		result = append(result, *info)
+	result := make([]PresenceInfo, 0)
+	for _, node := range p.nodes {
+		for _, info := range node.entries {
+			result = append(result, info)
+		}
 	}
 	return result
 }
@@ -156,18 +244,23 @@ func (p *Presence) List() []PresenceInfo {
 func (p *Presence) Get(key string) (*PresenceInfo, bool) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	info, ok := p.presences[key]
-	if !ok {
-		return nil, false
+	for _, node := range p.nodes {
+		if info, ok := node.entries[key]; ok {
+			return &info, true
+		}
 	}
-	return info, true
+	return nil, false
 }
 
 // Count returns the number of present users.
 func (p *Presence) Count() int {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	return len(p.presences)
+	n := 0
+	for _, node := range p.nodes {
+		n += len(node.entries)
+	}
+	return n
 }
 
 // OnJoin registers a handler for join events.
@@ -184,11 +277,37 @@ func (p *Presence) OnLeave(handler func(PresenceInfo)) {
 	p.onLeave = append(p.onLeave, handler)
 }
 
+// Watch delivers a PresenceDiff to the component of socket (its HandleInfo)
+// each time entries join or leave, on any node. It stops when the socket
+// closes or the returned function is called.
+//
+//	// Mount
+//	c.online = pres.List()
+//	pres.Watch(c.Socket())
+//	// HandleInfo
+//	if diff, ok := msg.(presence.PresenceDiff); ok && diff.Topic == "room:lobby" {
+//		c.online = pres.List()
+//	}
+func (p *Presence) Watch(socket *core.Socket) func() {
+	p.mu.Lock()
+	p.watchers[socket] = struct{}{}
+	p.mu.Unlock()
+
+	unwatch := func() {
+		p.mu.Lock()
+		delete(p.watchers, socket)
+		p.mu.Unlock()
+	}
+	socket.OnClose(unwatch)
+	return unwatch
+}
+
 // Diff returns the difference between two presence states.
 func (p *Presence) Diff(previous []PresenceInfo) PresenceDiff {
 	current := p.List()
 
 	diff := PresenceDiff{
+		Topic:  p.topic,
 		Joins:  make([]PresenceInfo, 0),
 		Leaves: make([]PresenceInfo, 0),
 	}
@@ -221,8 +340,21 @@ func (p *Presence) Diff(previous []PresenceInfo) PresenceDiff {
 	return diff
 }
 
-// PresenceDiff represents changes in presence state.
+// Close stops replication. Other nodes drop this node's entries when its
+// heartbeats stop.
+func (p *Presence) Close() {
+	p.closed.Do(func() {
+		close(p.stop)
+		if p.sub != nil {
+			p.sub.Unsubscribe()
+		}
+	})
+}
+
+// PresenceDiff represents changes in presence state. It is also the
+// message Watch delivers to HandleInfo.
 type PresenceDiff struct {
+	Topic  string         `json:"-"`
 	Joins  []PresenceInfo `json:"joins"`
 	Leaves []PresenceInfo `json:"leaves"`
 }
@@ -268,53 +400,264 @@ func (d PresenceDiff) ToMap() map[string]any {
 	}
 }
 
+// Replication message types.
+const (
+	// msgDelta carries one change of the sender's entries.
+	msgDelta = "delta"
+	// msgState carries all the sender's entries.
+	msgState = "state"
+	// msgSync asks every node for its state.
+	msgSync = "sync"
+	// msgHeartbeat announces the sender's clock.
+	msgHeartbeat = "heartbeat"
+)
+
 type presenceMessage struct {
-	Type string       `json:"type"`
-	Info PresenceInfo `json:"info"`
+	Type   string         `json:"type"`
+	Node   string         `json:"node"`
+	Clock  uint64         `json:"clock,omitempty"`
+	Joins  []PresenceInfo `json:"joins,omitempty"`
+	Leaves []string       `json:"leaves,omitempty"`
+	State  []PresenceInfo `json:"state,omitempty"`
 }
 
 func (p *Presence) handlePresenceMessage(data []byte) {
 	var msg presenceMessage
-	if err := json.Unmarshal(data, &msg); err != nil {
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Node == "" || msg.Node == p.config.NodeID {
 		return
 	}
 
 	switch msg.Type {
-	case "join":
+	case msgSync:
+		p.publishState()
+
+	case msgHeartbeat:
 		p.mu.Lock()
-		p.presences[msg.Info.Key] = &msg.Info
+		node := p.remote(msg.Node)
+		behind := msg.Clock > node.clock
 		p.mu.Unlock()
+		if behind {
+			p.publish(presenceMessage{Type: msgSync})
+		}
 
-		for _, handler := range p.onJoin {
-			go handler(msg.Info)
+	case msgDelta:
+		p.mu.Lock()
+		node := p.remote(msg.Node)
+		switch {
+		case msg.Clock <= node.clock:
+			// Already seen
+			p.mu.Unlock()
+			return
+		case msg.Clock != node.clock+1:
+			// Missed a delta: ask for the full state
+			p.mu.Unlock()
+			p.publish(presenceMessage{Type: msgSync})
+			return
+		}
+		node.clock = msg.Clock
+		diff := PresenceDiff{Topic: p.topic}
+		for _, key := range msg.Leaves {
+			if info, ok := node.entries[key]; ok {
+				delete(node.entries, key)
+				diff.Leaves = append(diff.Leaves, info)
+			}
+		}
+		for _, info := range msg.Joins {
+			node.entries[info.Key] = info
+			diff.Joins = append(diff.Joins, info)
 		}
+		p.mu.Unlock()
+		p.changed(diff)
 
-	case "leave":
+	case msgState:
 		p.mu.Lock()
-		delete(p.presences, msg.Info.Key)
+		node := p.remote(msg.Node)
+		if msg.Clock <= node.clock {
+			p.mu.Unlock()
+			return
+		}
+		node.clock = msg.Clock
+		entries := make(map[string]PresenceInfo, len(msg.State))
+		for _, info := range msg.State {
+			entries[info.Key] = info
+		}
+		diff := diffEntries(p.topic, node.entries, entries)
+		node.entries = entries
 		p.mu.Unlock()
+		p.changed(diff)
+	}
+}
+
+// diffEntries lists the entries of next not in prev, or changed, as joins
+// and the others of prev as leaves.
+func diffEntries(topic string, prev, next map[string]PresenceInfo) PresenceDiff {
+	diff := PresenceDiff{Topic: topic}
+	for key, info := range prev {
+		if n, ok := next[key]; !ok || n.PhxRef != info.PhxRef {
+			diff.Leaves = append(diff.Leaves, info)
+		}
+	}
+	for key, info := range next {
+		if o, ok := prev[key]; !ok || o.PhxRef != info.PhxRef {
+			diff.Joins = append(diff.Joins, info)
+		}
+	}
+	return diff
+}
+
+// local returns this node's state; the caller holds p.mu.
+func (p *Presence) local() *nodeState {
+	return p.nodes[p.config.NodeID]
+}
+
+// remote returns the state of another node, marking it as seen; the caller
+// holds p.mu.
+func (p *Presence) remote(id string) *nodeState {
+	node, ok := p.nodes[id]
+	if !ok {
+		node = &nodeState{entries: make(map[string]PresenceInfo)}
+		p.nodes[id] = node
+	}
+	node.lastSeen = time.Now()
+	return node
+}
+
+// ref names an entry version.
+func (p *Presence) ref(clock uint64) string {
+	return fmt.Sprintf("%s:%d", p.config.NodeID, clock)
+}
+
+// publish sends a replication message from this node.
+func (p *Presence) publish(msg presenceMessage) {
+	if p.pubsub == nil {
+		return
+	}
+	msg.Node = p.config.NodeID
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	p.pubsub.Publish(p.channel(), data)
+}
+
+// publishState sends this node's entries.
+func (p *Presence) publishState() {
+	p.mu.RLock()
+	local := p.local()
+	msg := presenceMessage{Type: msgState, Clock: local.clock, State: make([]PresenceInfo, 0, len(local.entries))}
+	for _, info := range local.entries {
+		msg.State = append(msg.State, info)
+	}
+	p.mu.RUnlock()
+	p.publish(msg)
+}
 
-		for _, handler := range p.onLeave {
-			go handler(msg.Info)
+// heartbeat announces this node's clock and drops silent nodes until Close.
+func (p *Presence) heartbeat() {
+	ticker := time.NewTicker(p.config.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
 		}
+
+		p.mu.RLock()
+		clock := p.local().clock
+		p.mu.RUnlock()
+		p.publish(presenceMessage{Type: msgHeartbeat, Clock: clock})
+
+		p.dropSilentNodes()
 	}
 }
 
+// dropSilentNodes removes the nodes not heard from within NodeTimeout,
+// reporting their entries as leaves.
+func (p *Presence) dropSilentNodes() {
+	deadline := time.Now().Add(-p.config.NodeTimeout)
+	diff := PresenceDiff{Topic: p.topic}
+
+	p.mu.Lock()
+	for id, node := range p.nodes {
+		if id == p.config.NodeID || node.lastSeen.After(deadline) {
+			continue
+		}
+		for _, info := range node.entries {
+			diff.Leaves = append(diff.Leaves, info)
+		}
+		delete(p.nodes, id)
+	}
+	p.mu.Unlock()
+
+	p.changed(diff)
+}
+
+// changed notifies the handlers and watchers of a non-empty diff.
+func (p *Presence) changed(diff PresenceDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+
+	p.mu.RLock()
+	onJoin := p.onJoin
+	onLeave := p.onLeave
+	watchers := make([]*core.Socket, 0, len(p.watchers))
+	for socket := range p.watchers {
+		watchers = append(watchers, socket)
+	}
+	p.mu.RUnlock()
+
+	for _, info := range diff.Leaves {
+		for _, handler := range onLeave {
+			go handler(info)
+		}
+	}
+	for _, info := range diff.Joins {
+		for _, handler := range onJoin {
+			go handler(info)
+		}
+	}
+	for _, socket := range watchers {
+		socket.SendInfo(diff)
+	}
+}
+
+// newNodeID returns a random node name.
+func newNodeID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // PresenceManager manages presence across multiple topics.
 type PresenceManager struct {
 	presences map[string]*Presence
 	pubsub    pubsub.PubSub
+	config    Config
 	mu        sync.RWMutex
 }
 
 // NewPresenceManager creates a new presence manager.
 func NewPresenceManager(ps pubsub.PubSub) *PresenceManager {
+	return NewPresenceManagerWithConfig(ps, Config{})
+}
+
+// NewPresenceManagerWithConfig creates a presence manager whose topics
+// share config, and so the node ID.
+func NewPresenceManagerWithConfig(ps pubsub.PubSub, config Config) *PresenceManager {
 	return &PresenceManager{
 		presences: make(map[string]*Presence),
 		pubsub:    ps,
+		config:    config.withDefaults(),
 	}
 }
 
+// NodeID returns the name of this node.
+func (pm *PresenceManager) NodeID() string {
+	return pm.config.NodeID
+}
+
 // GetOrCreate gets or creates a presence tracker for a topic.
 func (pm *PresenceManager) GetOrCreate(topic string) *Presence {
 	pm.mu.RLock()
@@ -333,16 +676,35 @@ func (pm *PresenceManager) GetOrCreate(topic string) *Presence {
 		return p
 	}
 
-	p = NewPresence(topic, pm.pubsub)
+	p = NewPresenceWithConfig(topic, pm.pubsub, pm.config)
 	pm.presences[topic] = p
 	return p
 }
 
-// Remove removes a presence tracker.
+// Track adds socket to topic's presence (see Presence.Track).
+func (pm *PresenceManager) Track(topic string, socket *core.Socket, info PresenceInfo) error {
+	return pm.GetOrCreate(topic).Track(socket, info)
+}
+
+// Untrack removes socket from topic's presence.
+func (pm *PresenceManager) Untrack(topic string, socket *core.Socket) error {
+	return pm.GetOrCreate(topic).Untrack(socket)
+}
+
+// List returns the entries of topic on every node.
+func (pm *PresenceManager) List(topic string) []PresenceInfo {
+	return pm.GetOrCreate(topic).List()
+}
+
+// Remove removes a presence tracker and stops its replication.
 func (pm *PresenceManager) Remove(topic string) {
 	pm.mu.Lock()
-	defer pm.mu.Unlock()
+	p, ok := pm.presences[topic]
 	delete(pm.presences, topic)
+	pm.mu.Unlock()
+	if ok {
+		p.Close()
+	}
 }
 
 // Topics returns all tracked topics.
@@ -0,0 +1,171 @@
+package presence
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+// testConfig replicates quickly.
+func testConfig(node string) Config {
+	return Config{NodeID: node, HeartbeatInterval: 20 * time.Millisecond, NodeTimeout: 100 * time.Millisecond}
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func nextDiff(t *testing.T, socket *core.Socket) PresenceDiff {
+	t.Helper()
+	select {
+	case msg := <-socket.Info():
+		diff, ok := msg.(PresenceDiff)
+		if !ok {
+			t.Fatalf("info = %T, want PresenceDiff", msg)
+		}
+		return diff
+	case <-time.After(2 * time.Second):
+		t.Fatal("no presence diff delivered")
+		return PresenceDiff{}
+	}
+}
+
+func TestPresence_TrackUntrack(t *testing.T) {
+	p := NewPresence("room:1", nil)
+	a := core.NewSocket("a", nil)
+	b := core.NewSocket("b", nil)
+
+	p.Track(a, PresenceInfo{UserID: "u1", Username: "alice"})
+	p.Track(b, PresenceInfo{UserID: "u2", Username: "bob"})
+	if p.Count() != 2 {
+		t.Fatalf("Count = %d, want 2", p.Count())
+	}
+	if info, ok := p.Get("a"); !ok || info.Username != "alice" || info.PhxRef == "" {
+		t.Errorf("Get(a) = %+v, %v", info, ok)
+	}
+
+	p.Untrack(a)
+	if _, ok := p.Get("a"); ok || p.Count() != 1 {
+		t.Errorf("a still present after Untrack")
+	}
+
+	// Closing the socket untracks it
+	b.Close()
+	if p.Count() != 0 {
+		t.Errorf("Count = %d after the socket closed, want 0", p.Count())
+	}
+}
+
+func TestPresence_WatchDeliversDiffs(t *testing.T) {
+	p := NewPresence("room:1", nil)
+	watcher := core.NewSocket("watcher", nil)
+	p.Watch(watcher)
+
+	a := core.NewSocket("a", nil)
+	p.Track(a, PresenceInfo{Username: "alice"})
+	if diff := nextDiff(t, watcher); diff.Topic != "room:1" || len(diff.Joins) != 1 || diff.Joins[0].Key != "a" {
+		t.Errorf("join diff = %+v", diff)
+	}
+
+	p.Update(a, map[string]any{"typing": true})
+	if diff := nextDiff(t, watcher); len(diff.Joins) != 1 || len(diff.Leaves) != 1 || diff.Joins[0].Metas["typing"] != true {
+		t.Errorf("update diff = %+v", diff)
+	}
+
+	a.Close()
+	if diff := nextDiff(t, watcher); len(diff.Leaves) != 1 || diff.Leaves[0].Key != "a" {
+		t.Errorf("leave diff = %+v", diff)
+	}
+}
+
+func TestPresence_ReplicatesAcrossNodes(t *testing.T) {
+	ps := pubsub.NewMemoryPubSub()
+	defer ps.Close()
+
+	nodeA := NewPresenceManagerWithConfig(ps, testConfig("A"))
+	presA := nodeA.GetOrCreate("room:1")
+	defer presA.Close()
+	nodeA.Track("room:1", core.NewSocket("a1", nil), PresenceInfo{Username: "alice"})
+
+	// A node starting later asks for the state of the others
+	nodeB := NewPresenceManagerWithConfig(ps, testConfig("B"))
+	presB := nodeB.GetOrCreate("room:1")
+	defer presB.Close()
+	eventually(t, "B to see alice", func() bool { return presB.Count() == 1 })
+
+	watcher := core.NewSocket("watcher", nil)
+	presB.Watch(watcher)
+
+	b1 := core.NewSocket("b1", nil)
+	nodeB.Track("room:1", b1, PresenceInfo{Username: "bob"})
+	nextDiff(t, watcher)
+	eventually(t, "A to see bob", func() bool { return presA.Count() == 2 })
+
+	if info, _ := presA.Get("b1"); info.Node != "B" {
+		t.Errorf("b1 node = %q, want B", info.Node)
+	}
+
+	// A leave on A reaches B's watchers
+	nodeA.Untrack("room:1", core.NewSocket("a1", nil))
+	if diff := nextDiff(t, watcher); len(diff.Leaves) != 1 || diff.Leaves[0].Key != "a1" {
+		t.Errorf("leave diff on B = %+v", diff)
+	}
+	if presB.Count() != 1 {
+		t.Errorf("B count = %d, want 1", presB.Count())
+	}
+}
+
+func TestPresence_MergeIgnoresStaleAndDuplicateDeltas(t *testing.T) {
+	p := NewPresenceWithConfig("room:1", nil, testConfig("local"))
+	deliver := func(msg presenceMessage) {
+		data, _ := json.Marshal(msg)
+		p.handlePresenceMessage(data)
+	}
+	alice := PresenceInfo{Key: "x1", Username: "alice", PhxRef: "X:1"}
+	bob := PresenceInfo{Key: "x2", Username: "bob", PhxRef: "X:2"}
+
+	deliver(presenceMessage{Type: msgDelta, Node: "X", Clock: 1, Joins: []PresenceInfo{alice}})
+	deliver(presenceMessage{Type: msgDelta, Node: "X", Clock: 1, Joins: []PresenceInfo{alice}})
+	if p.Count() != 1 {
+		t.Fatalf("Count = %d after a duplicate delta, want 1", p.Count())
+	}
+
+	// A delta after a gap is not applied on its own
+	deliver(presenceMessage{Type: msgDelta, Node: "X", Clock: 3, Leaves: []string{"x1"}})
+	if p.Count() != 1 {
+		t.Fatalf("delta after a gap was applied")
+	}
+
+	// The full state settles it; older states lose
+	deliver(presenceMessage{Type: msgState, Node: "X", Clock: 3, State: []PresenceInfo{bob}})
+	deliver(presenceMessage{Type: msgState, Node: "X", Clock: 2, State: []PresenceInfo{alice, bob}})
+	list := p.List()
+	if len(list) != 1 || list[0].Key != "x2" {
+		t.Errorf("List = %+v, want bob only", list)
+	}
+}
+
+func TestPresence_DropsSilentNodes(t *testing.T) {
+	ps := pubsub.NewMemoryPubSub()
+	defer ps.Close()
+
+	presA := NewPresenceWithConfig("room:1", ps, testConfig("A"))
+	presA.Track(core.NewSocket("a1", nil), PresenceInfo{Username: "alice"})
+	presB := NewPresenceWithConfig("room:1", ps, testConfig("B"))
+	defer presB.Close()
+	eventually(t, "B to see alice", func() bool { return presB.Count() == 1 })
+
+	// A crashes: its heartbeats stop
+	presA.Close()
+	eventually(t, "B to drop A", func() bool { return presB.Count() == 0 })
+}
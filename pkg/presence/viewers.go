@@ -0,0 +1,164 @@
+package presence
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Viewers is a drop-in "N people viewing" widget. It tracks the socket in a
+// presence topic (usually the route path), re-renders the host component
+// when viewers come and go, and renders a live count with an avatar stack:
+//
+//	// Mount
+//	c.viewers, _ = presence.NewViewers(pm, "route:/docs", c.Socket(), presence.PresenceInfo{Username: name})
+//	// HandleInfo
+//	if c.viewers.HandleInfo(msg) { return nil }
+//	// Render
+//	c.viewers.Render()
+//	// Terminate
+//	c.viewers.Leave()
+//
+// A nil socket (the initial HTTP render) only renders the current count.
+type Viewers struct {
+	topic      string
+	presence   *Presence
+	socket     *core.Socket
+	unwatch    func()
+	maxAvatars int
+	once       sync.Once
+}
+
+// NewViewers tracks socket in topic and watches presence changes, which
+// arrive in HandleInfo as PresenceDiff messages.
+func NewViewers(pm *PresenceManager, topic string, socket *core.Socket, info PresenceInfo) (*Viewers, error) {
+	v := &Viewers{
+		topic:      topic,
+		presence:   pm.GetOrCreate(topic),
+		socket:     socket,
+		maxAvatars: 5,
+	}
+	if socket == nil {
+		return v, nil
+	}
+
+	v.unwatch = v.presence.Watch(socket)
+
+	if info.UserID == "" {
+		info.UserID = socket.ID()
+	}
+	if err := v.presence.Track(socket, info); err != nil {
+		v.Leave()
+		return nil, err
+	}
+	return v, nil
+}
+
+// SetMaxAvatars sets how many avatars are shown before "+N".
+func (v *Viewers) SetMaxAvatars(n int) *Viewers {
+	if n >= 0 {
+		v.maxAvatars = n
+	}
+	return v
+}
+
+// HandleInfo reports whether msg is a change for this widget. The router
+// re-renders after HandleInfo, so callers just return.
+func (v *Viewers) HandleInfo(msg any) bool {
+	diff, ok := msg.(PresenceDiff)
+	return ok && diff.Topic == v.topic
+}
+
+// List returns the distinct viewers (one entry per user), oldest first.
+func (v *Viewers) List() []PresenceInfo {
+	seen := make(map[string]bool)
+	var out []PresenceInfo
+	all := v.presence.List()
+	sort.Slice(all, func(i, j int) bool { return all[i].OnlineAt.Before(all[j].OnlineAt) })
+	for _, info := range all {
+		id := info.UserID
+		if id == "" {
+			id = info.Key
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, info)
+	}
+	return out
+}
+
+// Count returns the number of distinct viewers.
+func (v *Viewers) Count() int {
+	return len(v.List())
+}
+
+// Render returns the widget HTML: an avatar stack and "N people viewing".
+// The outer element is a data-slot, so only it is patched on changes.
+func (v *Viewers) Render() string {
+	viewers := v.List()
+	count := len(viewers)
+
+	var sb strings.Builder
+	sb.WriteString(`<div class="lv-viewers" data-slot="viewers" aria-live="polite">`)
+	sb.WriteString(`<span class="lv-viewers-avatars">`)
+	for i, info := range viewers {
+		if i == v.maxAvatars {
+			fmt.Fprintf(&sb, `<span class="lv-viewers-more">+%d</span>`, count-v.maxAvatars)
+			break
+		}
+		name := info.Username
+		if name == "" {
+			name = "Guest"
+		}
+		fmt.Fprintf(&sb, `<span class="lv-viewers-avatar" title="%s">%s</span>`,
+			html.EscapeString(name), html.EscapeString(initials(name)))
+	}
+	sb.WriteString(`</span>`)
+
+	label := "people viewing"
+	if count == 1 {
+		label = "person viewing"
+	}
+	fmt.Fprintf(&sb, `<span class="lv-viewers-count">%d %s</span></div>`, count, label)
+	return sb.String()
+}
+
+// Leave untracks the socket and stops listening for changes.
+func (v *Viewers) Leave() {
+	v.once.Do(func() {
+		if v.unwatch != nil {
+			v.unwatch()
+		}
+		if v.socket != nil {
+			v.presence.Untrack(v.socket)
+		}
+	})
+}
+
+// ViewersCSS is a default style for the widget.
+const ViewersCSS = `.lv-viewers{display:inline-flex;align-items:center;gap:0.5rem;font-size:0.875rem}
+.lv-viewers-avatars{display:inline-flex}
+.lv-viewers-avatar,.lv-viewers-more{display:inline-flex;align-items:center;justify-content:center;width:1.75rem;height:1.75rem;border-radius:50%;background:#6366F1;color:#fff;font-size:0.7rem;font-weight:600;border:2px solid #fff;margin-inline-start:-0.5rem}
+.lv-viewers-avatar:first-child{margin-inline-start:0}
+.lv-viewers-more{background:#94A3B8}`
+
+func initials(name string) string {
+	fields := strings.Fields(name)
+	var out []rune
+	for _, f := range fields {
+		out = append(out, []rune(strings.ToUpper(f))[0])
+		if len(out) == 2 {
+			break
+		}
+	}
+	if len(out) == 0 {
+		return "?"
+	}
+	return string(out)
+}
@@ -0,0 +1,149 @@
+package presence
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// join tracks a new viewer, spacing joins so OnlineAt orders them.
+func join(t *testing.T, pm *PresenceManager, id string, info PresenceInfo) *Viewers {
+	t.Helper()
+	time.Sleep(2 * time.Millisecond)
+	v, err := NewViewers(pm, "route:/docs", core.NewSocket(id, nil), info)
+	if err != nil {
+		t.Fatalf("NewViewers(%s): %v", id, err)
+	}
+	return v
+}
+
+func TestViewers_ListDedupesUsersOldestFirst(t *testing.T) {
+	pm := NewPresenceManager(nil)
+	a := join(t, pm, "a", PresenceInfo{UserID: "u1", Username: "Ada Lovelace"})
+	join(t, pm, "b", PresenceInfo{UserID: "u2", Username: "Bob"})
+	join(t, pm, "c", PresenceInfo{UserID: "u1", Username: "Ada Lovelace"}) // second tab
+	join(t, pm, "d", PresenceInfo{Username: "Anon"})                       // keyed by socket
+
+	var got []string
+	for _, info := range a.List() {
+		got = append(got, info.Key)
+	}
+	if strings.Join(got, ",") != "a,b,d" {
+		t.Errorf("List keys = %v, want [a b d]", got)
+	}
+	if a.Count() != 3 {
+		t.Errorf("Count = %d, want 3", a.Count())
+	}
+	if info := a.List()[2]; info.UserID != "d" {
+		t.Errorf("UserID of a viewer without one = %q, want the socket ID", info.UserID)
+	}
+}
+
+func TestViewers_HandleInfo(t *testing.T) {
+	pm := NewPresenceManager(nil)
+	socket := core.NewSocket("a", nil)
+	v, err := NewViewers(pm, "route:/docs", socket, PresenceInfo{Username: "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	join(t, pm, "b", PresenceInfo{Username: "Bob"})
+	diff := nextDiff(t, socket)
+	if !v.HandleInfo(diff) {
+		t.Errorf("HandleInfo(%+v) = false for the widget's topic", diff)
+	}
+	if v.HandleInfo(PresenceDiff{Topic: "route:/other"}) {
+		t.Error("HandleInfo accepted another topic")
+	}
+	if v.HandleInfo("tick") {
+		t.Error("HandleInfo accepted a non-presence message")
+	}
+}
+
+func TestViewers_Render(t *testing.T) {
+	pm := NewPresenceManager(nil)
+	v := join(t, pm, "a", PresenceInfo{Username: "ada lovelace byron"})
+	join(t, pm, "b", PresenceInfo{Username: `<script>"x"</script>`})
+	join(t, pm, "c", PresenceInfo{})
+	join(t, pm, "d", PresenceInfo{Username: "Dan"})
+
+	out := v.SetMaxAvatars(3).Render()
+	for _, want := range []string{
+		`data-slot="viewers"`,
+		`title="ada lovelace byron">AL</span>`,
+		`title="&lt;script&gt;&#34;x&#34;&lt;/script&gt;">&lt;</span>`,
+		`title="Guest">G</span>`,
+		`<span class="lv-viewers-more">+1</span>`,
+		`4 people viewing`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>") || strings.Contains(out, `title="Dan"`) {
+		t.Errorf("Render() = %s", out)
+	}
+
+	// SetMaxAvatars ignores negative values; 0 shows only the overflow.
+	if out := v.SetMaxAvatars(-1).SetMaxAvatars(0).Render(); !strings.Contains(out, "+4") || strings.Contains(out, "lv-viewers-avatar\"") {
+		t.Errorf("Render() with no avatars = %s", out)
+	}
+}
+
+func TestViewers_RenderSingular(t *testing.T) {
+	pm := NewPresenceManager(nil)
+	v := join(t, pm, "a", PresenceInfo{Username: "Ada"})
+	if out := v.Render(); !strings.Contains(out, "1 person viewing") || strings.Contains(out, "lv-viewers-more") {
+		t.Errorf("Render() = %s", out)
+	}
+}
+
+func TestViewers_LeaveIsIdempotent(t *testing.T) {
+	pm := NewPresenceManager(nil)
+	v := join(t, pm, "a", PresenceInfo{Username: "Ada"})
+	other := join(t, pm, "b", PresenceInfo{Username: "Bob"})
+
+	v.Leave()
+	v.Leave()
+	if other.Count() != 1 {
+		t.Errorf("Count = %d after Leave, want 1", other.Count())
+	}
+	if _, ok := pm.GetOrCreate("route:/docs").watchers[v.socket]; ok {
+		t.Error("socket still watching after Leave")
+	}
+}
+
+func TestViewers_NilSocket(t *testing.T) {
+	pm := NewPresenceManager(nil)
+	join(t, pm, "a", PresenceInfo{Username: "Ada"})
+
+	v, err := NewViewers(pm, "route:/docs", nil, PresenceInfo{Username: "ignored"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Count() != 1 {
+		t.Errorf("Count = %d with a nil socket, want 1 (not tracked)", v.Count())
+	}
+	if out := v.Render(); !strings.Contains(out, "1 person viewing") {
+		t.Errorf("Render() = %s", out)
+	}
+	v.Leave()
+}
+
+func TestInitials(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Ada Lovelace", "AL"},
+		{"ada", "A"},
+		{"  ada   king  lovelace ", "AK"},
+		{"élodie durand", "ÉD"},
+		{"", "?"},
+		{"   ", "?"},
+	}
+	for _, tt := range tests {
+		if got := initials(tt.name); got != tt.want {
+			t.Errorf("initials(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
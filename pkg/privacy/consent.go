@@ -0,0 +1,218 @@
+// Package privacy provides the controls analytics and session replay need to
+// run under GDPR-style rules: per-session consent (opt-in by default and
+// persisted in a cookie), redaction of sensitive payload fields before they
+// are recorded, and retention limits for recorded data.
+//
+// Usage:
+//
+//	gate := privacy.NewGate(sink, privacy.NewRedactor())
+//
+//	// Mount
+//	c.session = session
+//	c.consent = privacy.FromSession(session)
+//
+//	// HandleEvent
+//	if consent, ok := privacy.HandleConsentEvent(c.Socket(), c.session, event, payload); ok {
+//		c.consent = consent
+//		return nil
+//	}
+//	gate.Record(ctx, c.consent, privacy.Record{Category: privacy.Replay, Event: event, Payload: payload})
+package privacy
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Category is a purpose the user can consent to.
+type Category string
+
+// Consent categories. Necessary is always allowed and cannot be refused.
+const (
+	Necessary Category = "necessary"
+	Analytics Category = "analytics"
+	Replay    Category = "replay"
+	Marketing Category = "marketing"
+)
+
+// Consent defaults.
+const (
+	// CookieName persists consent between visits. It appears in the live
+	// session as "cookie:lv_consent".
+	CookieName = "lv_consent"
+	// SessionKey caches the decoded Consent in core.Session.
+	SessionKey = "privacy.consent"
+	// ConsentEvent is pushed by the consent banner:
+	// {"grant": "analytics,replay"} or {"grant": ""} to refuse all.
+	ConsentEvent = "lv:consent"
+	// CookieMaxAge is how long a consent decision is remembered.
+	CookieMaxAge = 180 * 24 * time.Hour
+	// consentVersion is bumped when categories change meaning, so stale
+	// decisions are asked again.
+	consentVersion = 1
+)
+
+// Consent is a user's decision. The zero value means "not asked yet" and
+// allows only Necessary.
+type Consent struct {
+	granted   map[Category]bool
+	decided   bool
+	UpdatedAt time.Time
+}
+
+// NewConsent records a decision granting categories.
+func NewConsent(categories ...Category) Consent {
+	c := Consent{granted: make(map[Category]bool), decided: true, UpdatedAt: time.Now().UTC()}
+	for _, cat := range categories {
+		if cat != "" && cat != Necessary {
+			c.granted[cat] = true
+		}
+	}
+	return c
+}
+
+// Allows reports whether recording for cat is permitted.
+func (c Consent) Allows(cat Category) bool {
+	return cat == Necessary || c.granted[cat]
+}
+
+// Decided reports whether the user has answered the consent prompt.
+func (c Consent) Decided() bool {
+	return c.decided
+}
+
+// Granted returns the granted categories, sorted.
+func (c Consent) Granted() []Category {
+	out := make([]Category, 0, len(c.granted))
+	for cat := range c.granted {
+		out = append(out, cat)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Encode returns the cookie value: "<version>:<cat.cat>:<unix time>".
+func (c Consent) Encode() string {
+	cats := make([]string, 0, len(c.granted))
+	for _, cat := range c.Granted() {
+		cats = append(cats, string(cat))
+	}
+	return strconv.Itoa(consentVersion) + ":" + strings.Join(cats, ".") + ":" + strconv.FormatInt(c.UpdatedAt.Unix(), 10)
+}
+
+// DecodeConsent parses a cookie value. Malformed or outdated values decode
+// to an undecided Consent, so the user is asked again.
+func DecodeConsent(value string) Consent {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 || parts[0] != strconv.Itoa(consentVersion) {
+		return Consent{}
+	}
+	unix, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Consent{}
+	}
+
+	var cats []Category
+	if parts[1] != "" {
+		for _, cat := range strings.Split(parts[1], ".") {
+			cats = append(cats, Category(cat))
+		}
+	}
+	c := NewConsent(cats...)
+	c.UpdatedAt = time.Unix(unix, 0).UTC()
+	return c
+}
+
+// FromSession returns the consent stored in the session, falling back to the
+// consent cookie.
+func FromSession(session core.Session) Consent {
+	if c, ok := session.Get(SessionKey).(Consent); ok {
+		return c
+	}
+	return DecodeConsent(session.GetString("cookie:" + CookieName))
+}
+
+// SetConsent stores c in the session.
+func SetConsent(session core.Session, c Consent) {
+	if session != nil {
+		session[SessionKey] = c
+	}
+}
+
+// Cookie returns the cookie persisting c.
+func (c Consent) Cookie(secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     CookieName,
+		Value:    c.Encode(),
+		Path:     "/",
+		MaxAge:   int(CookieMaxAge.Seconds()),
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// ParseGrant parses a comma-separated category list.
+func ParseGrant(s string) []Category {
+	var cats []Category
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			cats = append(cats, Category(f))
+		}
+	}
+	return cats
+}
+
+// HandleConsentEvent applies ConsentEvent from a live component: the
+// decision is stored in session, returned, and pushed back to the client,
+// which persists it in the consent cookie. ok is false for other events.
+func HandleConsentEvent(socket *core.Socket, session core.Session, event string, payload map[string]any) (Consent, bool) {
+	if event != ConsentEvent {
+		return Consent{}, false
+	}
+	grant, _ := payload["grant"].(string)
+	c := NewConsent(ParseGrant(grant)...)
+	SetConsent(session, c)
+
+	if socket != nil {
+		socket.Push(ConsentEvent, map[string]any{
+			"cookie": CookieName + "=" + c.Encode() + "; Path=/; Max-Age=" +
+				strconv.Itoa(int(CookieMaxAge.Seconds())) + "; SameSite=Lax",
+		})
+	}
+	return c, true
+}
+
+// ConsentHandler records a decision posted by a plain HTML form
+// (grant=analytics&grant=replay) and redirects back to the referring page.
+// It is the no-JavaScript fallback for the consent banner.
+func ConsentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		var cats []Category
+		for _, v := range r.PostForm["grant"] {
+			cats = append(cats, ParseGrant(v)...)
+		}
+		http.SetCookie(w, NewConsent(cats...).Cookie(r.TLS != nil))
+
+		back := "/"
+		if ref, err := url.Parse(r.Referer()); err == nil && ref.Host == r.Host && ref.Path != "" {
+			back = ref.RequestURI()
+		}
+		http.Redirect(w, r, back, http.StatusSeeOther)
+	})
+}
@@ -0,0 +1,138 @@
+package privacy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestConsent_EncodeDecode(t *testing.T) {
+	c := NewConsent(Replay, Analytics, Necessary)
+	decoded := DecodeConsent(c.Encode())
+
+	if !decoded.Decided() || !decoded.Allows(Analytics) || !decoded.Allows(Replay) || decoded.Allows(Marketing) {
+		t.Errorf("Unexpected decoded consent: %+v", decoded)
+	}
+	if decoded.UpdatedAt.Unix() != c.UpdatedAt.Unix() {
+		t.Errorf("Expected UpdatedAt to round-trip, got %v", decoded.UpdatedAt)
+	}
+
+	for _, bad := range []string{"", "garbage", "0:analytics:1", "1:analytics:x"} {
+		if DecodeConsent(bad).Decided() {
+			t.Errorf("Expected %q to decode as undecided", bad)
+		}
+	}
+
+	var none Consent
+	if !none.Allows(Necessary) || none.Allows(Analytics) {
+		t.Error("Expected undecided consent to allow only necessary")
+	}
+}
+
+func TestConsent_Session(t *testing.T) {
+	session := core.Session{"cookie:" + CookieName: NewConsent(Analytics).Encode()}
+	if !FromSession(session).Allows(Analytics) {
+		t.Fatal("Expected consent from cookie")
+	}
+
+	c, ok := HandleConsentEvent(nil, session, ConsentEvent, map[string]any{"grant": ""})
+	if !ok || c.Allows(Analytics) || !c.Decided() {
+		t.Fatalf("Expected refusal, got %+v %v", c, ok)
+	}
+	if FromSession(session).Allows(Analytics) {
+		t.Error("Expected session consent to be updated")
+	}
+	if _, ok := HandleConsentEvent(nil, session, "other", nil); ok {
+		t.Error("Expected other events to be ignored")
+	}
+}
+
+func TestConsentHandler(t *testing.T) {
+	form := url.Values{"grant": {"analytics", "replay"}}
+	req := httptest.NewRequest(http.MethodPost, "/_privacy/consent", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", "https://evil.example/")
+	rec := httptest.NewRecorder()
+
+	ConsentHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/" {
+		t.Errorf("Expected redirect to /, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !DecodeConsent(cookies[0].Value).Allows(Replay) {
+		t.Errorf("Unexpected cookies: %v", cookies)
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor().MarkEventSensitive("save_note", "notes")
+	r.AddHook(func(event string, p map[string]any) map[string]any {
+		delete(p, "debug")
+		return p
+	})
+
+	payload := map[string]any{
+		"email":        "a@b.c",
+		"New_Password": "hunter2",
+		"notes":        "private",
+		"debug":        true,
+		"billing":      map[string]any{"card_number": "4242", "city": "Madrid"},
+		"items":        []any{map[string]any{"token": "x"}},
+	}
+	out := r.Redact("save_note", payload)
+
+	if out["New_Password"] != Redacted || out["notes"] != Redacted || out["email"] != "a@b.c" {
+		t.Errorf("Unexpected redaction: %v", out)
+	}
+	if billing := out["billing"].(map[string]any); billing["card_number"] != Redacted || billing["city"] != "Madrid" {
+		t.Errorf("Expected nested redaction: %v", billing)
+	}
+	if item := out["items"].([]any)[0].(map[string]any); item["token"] != Redacted {
+		t.Errorf("Expected redaction inside slices: %v", item)
+	}
+	if _, ok := out["debug"]; ok {
+		t.Error("Expected hook to drop debug")
+	}
+	if payload["New_Password"] != "hunter2" {
+		t.Error("Redact must not modify its input")
+	}
+	if r.IsSensitive("other_event", "notes") {
+		t.Error("Expected event-specific fields to apply only to that event")
+	}
+}
+
+func TestGateAndRetention(t *testing.T) {
+	ctx := context.Background()
+	sink := NewMemorySink()
+	gate := NewGate(sink, NewRedactor())
+
+	if ok, _ := gate.Record(ctx, Consent{}, Record{SessionID: "s1", Category: Replay}); ok {
+		t.Error("Expected record without consent to be dropped")
+	}
+
+	consent := NewConsent(Replay, Analytics)
+	now := time.Now()
+	gate.Record(ctx, consent, Record{SessionID: "s1", Category: Replay, Event: "login", Payload: map[string]any{"password": "x"}, At: now.Add(-40 * 24 * time.Hour)})
+	gate.Record(ctx, consent, Record{SessionID: "s1", Category: Analytics, At: now.Add(-40 * 24 * time.Hour)})
+	gate.Record(ctx, consent, Record{SessionID: "s2", Category: Replay})
+
+	if got := sink.Records()[0].Payload["password"]; got != Redacted {
+		t.Errorf("Expected recorded payload to be redacted, got %v", got)
+	}
+
+	n, err := DefaultRetention.Apply(ctx, sink, now)
+	if err != nil || n != 1 || len(sink.Records()) != 2 {
+		t.Errorf("Expected only the old replay to expire, removed %d (%v), left %d", n, err, len(sink.Records()))
+	}
+
+	if n, _ := gate.Withdraw(ctx, "s1"); n != 1 || len(sink.Records()) != 1 {
+		t.Errorf("Expected withdraw to remove s1 records, removed %d", n)
+	}
+}
@@ -0,0 +1,134 @@
+package privacy
+
+import (
+	"strings"
+	"sync"
+)
+
+// Redacted replaces sensitive values.
+const Redacted = "[REDACTED]"
+
+// DefaultSensitiveFields are matched case-insensitively as substrings of
+// payload keys ("new_password", "apiToken", ...).
+var DefaultSensitiveFields = []string{
+	"password", "passwd", "secret", "token", "api_key", "apikey",
+	"authorization", "cookie", "card", "cvv", "iban", "ssn",
+}
+
+// RedactHook transforms a payload before it is recorded. Hooks run after
+// field redaction and may drop values or mask free text.
+type RedactHook func(event string, payload map[string]any) map[string]any
+
+// Redactor removes sensitive fields from event payloads. Fields are marked
+// sensitive globally (MarkSensitive) or per event (MarkEventSensitive).
+// It is safe for concurrent use.
+type Redactor struct {
+	mu     sync.RWMutex
+	fields []string
+	exact  map[string]map[string]bool // event -> exact keys
+	hooks  []RedactHook
+}
+
+// NewRedactor creates a redactor using DefaultSensitiveFields.
+func NewRedactor() *Redactor {
+	r := &Redactor{exact: make(map[string]map[string]bool)}
+	r.MarkSensitive(DefaultSensitiveFields...)
+	return r
+}
+
+// MarkSensitive redacts keys containing any of fields, in every event.
+func (r *Redactor) MarkSensitive(fields ...string) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range fields {
+		r.fields = append(r.fields, strings.ToLower(f))
+	}
+	return r
+}
+
+// MarkEventSensitive redacts the exact keys in event's payload, e.g. a
+// free-text "notes" field of one form.
+func (r *Redactor) MarkEventSensitive(event string, keys ...string) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.exact[event] == nil {
+		r.exact[event] = make(map[string]bool)
+	}
+	for _, k := range keys {
+		r.exact[event][k] = true
+	}
+	return r
+}
+
+// AddHook registers a custom redaction step.
+func (r *Redactor) AddHook(hook RedactHook) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+	return r
+}
+
+// IsSensitive reports whether key is redacted in event.
+func (r *Redactor) IsSensitive(event, key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.isSensitive(event, key)
+}
+
+func (r *Redactor) isSensitive(event, key string) bool {
+	if r.exact[event][key] {
+		return true
+	}
+	lower := strings.ToLower(key)
+	for _, f := range r.fields {
+		if strings.Contains(lower, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of payload with sensitive values replaced by
+// Redacted, recursing into nested maps and slices. The input is not modified.
+func (r *Redactor) Redact(event string, payload map[string]any) map[string]any {
+	if payload == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	out := r.redactMap(event, payload)
+	hooks := r.hooks
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		out = hook(event, out)
+	}
+	return out
+}
+
+func (r *Redactor) redactMap(event string, m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if r.isSensitive(event, k) {
+			out[k] = Redacted
+			continue
+		}
+		out[k] = r.redactValue(event, v)
+	}
+	return out
+}
+
+func (r *Redactor) redactValue(event string, v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return r.redactMap(event, val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(event, item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,162 @@
+package privacy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Record is one recorded interaction (an analytics event or a replay frame).
+type Record struct {
+	SessionID string
+	Category  Category
+	Event     string
+	Payload   map[string]any
+	At        time.Time
+}
+
+// Sink stores records for an analytics or replay backend.
+type Sink interface {
+	Write(ctx context.Context, rec Record) error
+	// DeleteBefore removes records of category older than t and returns how
+	// many were removed.
+	DeleteBefore(ctx context.Context, category Category, t time.Time) (int, error)
+	// DeleteSession removes every record of a session (right to erasure).
+	DeleteSession(ctx context.Context, sessionID string) (int, error)
+}
+
+// Gate sits in front of a Sink: records are dropped unless the session
+// consented to their category, and payloads are redacted before writing.
+type Gate struct {
+	sink     Sink
+	redactor *Redactor
+}
+
+// NewGate creates a gate. A nil redactor writes payloads unchanged.
+func NewGate(sink Sink, redactor *Redactor) *Gate {
+	return &Gate{sink: sink, redactor: redactor}
+}
+
+// Record writes rec if consent allows its category and reports whether it
+// was written.
+func (g *Gate) Record(ctx context.Context, consent Consent, rec Record) (bool, error) {
+	if !consent.Allows(rec.Category) {
+		return false, nil
+	}
+	if rec.At.IsZero() {
+		rec.At = time.Now().UTC()
+	}
+	if g.redactor != nil {
+		rec.Payload = g.redactor.Redact(rec.Event, rec.Payload)
+	}
+	if err := g.sink.Write(ctx, rec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Withdraw deletes a session's records, e.g. when the user revokes consent.
+func (g *Gate) Withdraw(ctx context.Context, sessionID string) (int, error) {
+	return g.sink.DeleteSession(ctx, sessionID)
+}
+
+// Retention maps categories to how long their records are kept.
+type Retention map[Category]time.Duration
+
+// DefaultRetention keeps analytics for 13 months and replays for 30 days.
+var DefaultRetention = Retention{
+	Analytics: 395 * 24 * time.Hour,
+	Replay:    30 * 24 * time.Hour,
+}
+
+// Apply deletes expired records from sink and returns the number removed.
+func (r Retention) Apply(ctx context.Context, sink Sink, now time.Time) (int, error) {
+	total := 0
+	for cat, ttl := range r {
+		if ttl <= 0 {
+			continue
+		}
+		n, err := sink.DeleteBefore(ctx, cat, now.Add(-ttl))
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// StartRetention applies r every interval until ctx is done. Errors are
+// passed to onError (which may be nil).
+func StartRetention(ctx context.Context, sink Sink, r Retention, interval time.Duration, onError func(error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := r.Apply(ctx, sink, now); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// MemorySink is an in-memory Sink for development and tests.
+type MemorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewMemorySink creates an empty sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Write implements Sink.
+func (s *MemorySink) Write(ctx context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+	return nil
+}
+
+// DeleteBefore implements Sink.
+func (s *MemorySink) DeleteBefore(ctx context.Context, category Category, t time.Time) (int, error) {
+	return s.deleteWhere(func(rec Record) bool {
+		return rec.Category == category && rec.At.Before(t)
+	}), nil
+}
+
+// DeleteSession implements Sink.
+func (s *MemorySink) DeleteSession(ctx context.Context, sessionID string) (int, error) {
+	return s.deleteWhere(func(rec Record) bool {
+		return rec.SessionID == sessionID
+	}), nil
+}
+
+// Records returns a copy of the stored records.
+func (s *MemorySink) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Record(nil), s.records...)
+}
+
+func (s *MemorySink) deleteWhere(match func(Record) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.records[:0]
+	removed := 0
+	for _, rec := range s.records {
+		if match(rec) {
+			removed++
+			continue
+		}
+		kept = append(kept, rec)
+	}
+	s.records = kept
+	return removed
+}
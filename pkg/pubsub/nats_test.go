@@ -0,0 +1,327 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeNATS is a minimal in-process NATS server: subjects with wildcards,
+// queue groups, PING/PONG.
+type fakeNATS struct {
+	ln      net.Listener
+	mu      sync.Mutex
+	clients map[*fakeNATSClient]bool
+	next    int
+}
+
+type fakeNATSClient struct {
+	conn net.Conn
+	mu   sync.Mutex
+	subs map[string]fakeNATSSub // by sid
+}
+
+type fakeNATSSub struct {
+	subject string
+	queue   string
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeNATS{ln: ln, clients: make(map[*fakeNATSClient]bool)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c := &fakeNATSClient{conn: conn, subs: make(map[string]fakeNATSSub)}
+			s.mu.Lock()
+			s.clients[c] = true
+			s.mu.Unlock()
+			go s.serve(c)
+		}
+	}()
+	t.Cleanup(func() {
+		ln.Close()
+		s.dropAll()
+	})
+	return s
+}
+
+func (s *fakeNATS) url() string {
+	return "nats://" + s.ln.Addr().String()
+}
+
+func (s *fakeNATS) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.conn.Close()
+		delete(s.clients, c)
+	}
+}
+
+// subscriptions counts the subscriptions to a subject across clients.
+func (s *fakeNATS) subscriptions(subject string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for c := range s.clients {
+		c.mu.Lock()
+		for _, sub := range c.subs {
+			if sub.subject == subject {
+				n++
+			}
+		}
+		c.mu.Unlock()
+	}
+	return n
+}
+
+func (s *fakeNATS) serve(c *fakeNATSClient) {
+	defer func() {
+		c.conn.Close()
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	c.write(`INFO {"server_id":"fake","max_payload":1048576}` + "\r\n")
+	r := bufio.NewReader(c.conn)
+	for {
+		line, err := readNATSLine(r)
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "CONNECT":
+		case "PING":
+			c.write("PONG\r\n")
+		case "SUB":
+			sub := fakeNATSSub{subject: fields[1]}
+			if len(fields) == 4 {
+				sub.queue = fields[2]
+			}
+			c.mu.Lock()
+			c.subs[fields[len(fields)-1]] = sub
+			c.mu.Unlock()
+		case "UNSUB":
+			c.mu.Lock()
+			delete(c.subs, fields[1])
+			c.mu.Unlock()
+		case "PUB":
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			s.publish(fields[1], payload[:size])
+		}
+	}
+}
+
+func (s *fakeNATS) publish(subject string, payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type target struct {
+		c   *fakeNATSClient
+		sid string
+	}
+	var targets []target
+	queues := map[string][]target{}
+	for c := range s.clients {
+		c.mu.Lock()
+		for sid, sub := range c.subs {
+			if !natsSubjectMatch(sub.subject, subject) {
+				continue
+			}
+			if sub.queue != "" {
+				queues[sub.queue] = append(queues[sub.queue], target{c, sid})
+				continue
+			}
+			targets = append(targets, target{c, sid})
+		}
+		c.mu.Unlock()
+	}
+	for _, members := range queues {
+		s.next++
+		targets = append(targets, members[s.next%len(members)])
+	}
+	for _, t := range targets {
+		t.c.msg(subject, t.sid, payload)
+	}
+}
+
+func (c *fakeNATSClient) msg(subject, sid string, payload []byte) {
+	c.write(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", subject, sid, len(payload), payload))
+}
+
+func (c *fakeNATSClient) write(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.Write([]byte(s))
+}
+
+// natsSubjectMatch matches a subject against a pattern with * and >.
+func natsSubjectMatch(pattern, subject string) bool {
+	p, s := strings.Split(pattern, "."), strings.Split(subject, ".")
+	for i, tok := range p {
+		if tok == ">" {
+			return len(s) > i
+		}
+		if i >= len(s) || (tok != "*" && tok != s[i]) {
+			return false
+		}
+	}
+	return len(p) == len(s)
+}
+
+func newTestNATSPubSub(t *testing.T, url string) *NATSPubSub {
+	t.Helper()
+	ps, err := NewNATSPubSub(&NATSConfig{URL: url})
+	if err != nil {
+		t.Fatalf("NewNATSPubSub: %v", err)
+	}
+	t.Cleanup(func() { ps.Close() })
+	return ps
+}
+
+func TestNATSPubSub_CrossInstance(t *testing.T) {
+	server := newFakeNATS(t)
+	a := newTestNATSPubSub(t, server.url())
+	b := newTestNATSPubSub(t, server.url())
+
+	got := make(chan string, 10)
+	sub, err := b.Subscribe("room.lobby", func(msg []byte) { got <- string(msg) })
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	waitFor(t, "subscription", func() bool { return server.subscriptions("room.lobby") == 1 })
+
+	if err := a.Publish("room.lobby", []byte("hello")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if msg := receive(t, got); msg != "hello" {
+		t.Errorf("Expected hello, got %q", msg)
+	}
+
+	sub.Unsubscribe()
+	waitFor(t, "unsubscription", func() bool { return server.subscriptions("room.lobby") == 0 })
+}
+
+func TestNATSPubSub_Wildcards(t *testing.T) {
+	server := newFakeNATS(t)
+	ps := newTestNATSPubSub(t, server.url())
+
+	got := make(chan string, 10)
+	ps.SubscribeTopic("room.*.messages", func(topic string, msg []byte) { got <- topic })
+	ps.SubscribeTopic("presence.>", func(topic string, msg []byte) { got <- topic })
+	waitFor(t, "subscriptions", func() bool {
+		return server.subscriptions("room.*.messages") == 1 && server.subscriptions("presence.>") == 1
+	})
+
+	ps.Publish("room.lobby.typing", []byte("x"))
+	ps.Publish("room.lobby.messages", []byte("x"))
+	if topic := receive(t, got); topic != "room.lobby.messages" {
+		t.Errorf("Expected room.lobby.messages, got %q", topic)
+	}
+	ps.Publish("presence.lobby.join", []byte("x"))
+	if topic := receive(t, got); topic != "presence.lobby.join" {
+		t.Errorf("Expected presence.lobby.join, got %q", topic)
+	}
+}
+
+func TestNATSPubSub_QueueGroup(t *testing.T) {
+	server := newFakeNATS(t)
+	a := newTestNATSPubSub(t, server.url())
+	b := newTestNATSPubSub(t, server.url())
+
+	var na, nb atomic.Int32
+	a.QueueSubscribe("jobs", "workers", func([]byte) { na.Add(1) })
+	b.QueueSubscribe("jobs", "workers", func([]byte) { nb.Add(1) })
+	waitFor(t, "subscriptions", func() bool { return server.subscriptions("jobs") == 2 })
+
+	const n = 20
+	for i := 0; i < n; i++ {
+		a.Publish("jobs", []byte("job"))
+	}
+	waitFor(t, "deliveries", func() bool { return na.Load()+nb.Load() == n })
+	time.Sleep(20 * time.Millisecond)
+	if total := na.Load() + nb.Load(); total != n {
+		t.Errorf("Expected each job once (%d), got %d", n, total)
+	}
+	if na.Load() == 0 || nb.Load() == 0 {
+		t.Errorf("Expected both workers to get jobs, got %d and %d", na.Load(), nb.Load())
+	}
+}
+
+func TestNATSPubSub_Drain(t *testing.T) {
+	server := newFakeNATS(t)
+	ps := newTestNATSPubSub(t, server.url())
+
+	release := make(chan struct{})
+	var handled atomic.Int32
+	ps.Subscribe("work", func([]byte) {
+		<-release
+		handled.Add(1)
+	})
+	waitFor(t, "subscription", func() bool { return server.subscriptions("work") == 1 })
+
+	for i := 0; i < 3; i++ {
+		ps.Publish("work", []byte("x"))
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- ps.Drain(context.Background()) }()
+	waitFor(t, "unsubscription", func() bool { return server.subscriptions("work") == 0 })
+
+	if _, err := ps.Subscribe("more", func([]byte) {}); err != ErrNATSDraining {
+		t.Errorf("Expected ErrNATSDraining while draining, got %v", err)
+	}
+
+	close(release)
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if n := handled.Load(); n != 3 {
+		t.Errorf("Expected the 3 received messages to be handled, got %d", n)
+	}
+	if err := ps.Publish("work", nil); err != ErrPubSubClosed {
+		t.Errorf("Expected ErrPubSubClosed after drain, got %v", err)
+	}
+}
+
+func TestNATSPubSub_Reconnect(t *testing.T) {
+	server := newFakeNATS(t)
+	ps := newTestNATSPubSub(t, server.url())
+
+	got := make(chan string, 10)
+	ps.Subscribe("chat", func(msg []byte) { got <- string(msg) })
+	waitFor(t, "subscription", func() bool { return server.subscriptions("chat") == 1 })
+
+	server.dropAll()
+	waitFor(t, "resubscription", func() bool { return server.subscriptions("chat") == 1 })
+
+	waitFor(t, "publish", func() bool { return ps.Publish("chat", []byte("after")) == nil })
+	if msg := receive(t, got); msg != "after" {
+		t.Errorf("Expected after, got %q", msg)
+	}
+}
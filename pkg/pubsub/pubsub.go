@@ -7,6 +7,8 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
 )
 
 // channelWrapper wraps a channel with sync.Once for safe closing.
@@ -282,18 +284,16 @@ func NewBroadcaster(ps PubSub) *Broadcaster {
 
 // Broadcast sends a message to a topic.
 func (b *Broadcaster) Broadcast(topic string, event string, payload map[string]any) error {
-	// Simple encoding
-	msg := Message{
-		Topic:   topic,
-		Payload: encodeMessage(event, payload),
-	}
-
-	data, err := encodeEnvelope(msg)
-	if err != nil {
-		return err
-	}
+	return b.BroadcastContext(context.Background(), topic, event, payload)
+}
 
-	return b.pubsub.Publish(topic, data)
+// BroadcastContext sends a message to a topic, carrying the correlation
+// and trace IDs of ctx (see tracing.CorrelationID). Live sessions receive
+// them in core.Broadcast and handle the message in the same trace, so a
+// trace shows which event caused the renders of other sessions.
+func (b *Broadcaster) BroadcastContext(ctx context.Context, topic string, event string, payload map[string]any) error {
+	// Same encoding as Channel.Push, so Subscribe can decode it
+	return b.pubsub.Publish(topic, encodeMessage(ctx, event, payload))
 }
 
 // BroadcastFrom sends a message to all subscribers except the sender.
@@ -315,28 +315,59 @@ func (b *Broadcaster) Subscribe(topic string, handler func(event string, payload
 	})
 }
 
+// SubscribeContext is Subscribe with a handler that gets the correlation
+// and trace IDs of the message in ctx, so work it does, such as another
+// broadcast, stays in the chain.
+func (b *Broadcaster) SubscribeContext(topic string, handler func(ctx context.Context, event string, payload map[string]any)) (Subscription, error) {
+	return b.pubsub.Subscribe(topic, func(data []byte) {
+		msg := decodeEnvelope(data)
+		handler(msg.context(context.Background()), msg.Event, msg.Payload)
+	})
+}
+
 // Helper functions for encoding/decoding
 
-func encodeMessage(event string, payload map[string]any) []byte {
-	// Simple JSON encoding
-	data := map[string]any{
-		"event":   event,
-		"payload": payload,
+// envelope is the JSON encoding of Broadcaster and Channel messages.
+type envelope struct {
+	Event         string         `json:"event"`
+	Payload       map[string]any `json:"payload"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+	TraceID       string         `json:"trace_id,omitempty"`
+}
+
+func newEnvelope(ctx context.Context, event string, payload map[string]any) envelope {
+	return envelope{
+		Event:         event,
+		Payload:       payload,
+		CorrelationID: tracing.CorrelationID(ctx),
+		TraceID:       tracing.TraceID(ctx),
+	}
+}
+
+// context returns ctx with the correlation and trace IDs of the message.
+func (e envelope) context(ctx context.Context) context.Context {
+	if e.CorrelationID != "" {
+		ctx = tracing.WithCorrelationID(ctx, e.CorrelationID)
+	}
+	if e.TraceID != "" {
+		ctx = tracing.WithTraceID(ctx, e.TraceID)
 	}
-	result, _ := json.Marshal(data)
+	return ctx
+}
+
+func encodeMessage(ctx context.Context, event string, payload map[string]any) []byte {
+	result, _ := json.Marshal(newEnvelope(ctx, event, payload))
 	return result
 }
 
-func encodeEnvelope(msg Message) ([]byte, error) {
-	return json.Marshal(msg)
+func decodeEnvelope(data []byte) envelope {
+	var msg envelope
+	json.Unmarshal(data, &msg)
+	return msg
 }
 
 func decodeMessage(data []byte) (string, map[string]any) {
-	var msg struct {
-		Event   string         `json:"event"`
-		Payload map[string]any `json:"payload"`
-	}
-	json.Unmarshal(data, &msg)
+	msg := decodeEnvelope(data)
 	return msg.Event, msg.Payload
 }
 
@@ -362,9 +393,10 @@ func (c *Channel) Name() string {
 	return c.name
 }
 
-// Push sends a message to the channel.
+// Push sends a message to the channel, with the correlation and trace IDs
+// of ctx.
 func (c *Channel) Push(ctx context.Context, event string, payload map[string]any) error {
-	data := encodeMessage(event, payload)
+	data := encodeMessage(ctx, event, payload)
 	return c.pubsub.Publish(c.name, data)
 }
 
@@ -1,10 +1,14 @@
 package pubsub
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
 )
 
 func TestPubSub_ConcurrentSubscribeUnsubscribe(t *testing.T) {
@@ -256,3 +260,36 @@ func BenchmarkPubSub_SubscribeUnsubscribe(b *testing.B) {
 		sub.Unsubscribe()
 	}
 }
+
+func TestBroadcaster_RoundTrip(t *testing.T) {
+	ps := NewMemoryPubSub()
+	defer ps.Close()
+	b := NewBroadcaster(ps)
+
+	got := make(chan string, 1)
+	b.Subscribe("room", func(event string, payload map[string]any) {
+		got <- fmt.Sprint(event, " ", payload["text"])
+	})
+	b.Broadcast("room", "message", map[string]any{"text": "hi"})
+
+	if msg := receive(t, got); msg != "message hi" {
+		t.Errorf("Expected \"message hi\", got %q", msg)
+	}
+}
+
+func TestBroadcaster_CorrelationID(t *testing.T) {
+	ps := NewMemoryPubSub()
+	defer ps.Close()
+	b := NewBroadcaster(ps)
+
+	got := make(chan string, 1)
+	b.SubscribeContext("room", func(ctx context.Context, event string, payload map[string]any) {
+		got <- tracing.CorrelationID(ctx) + " " + tracing.TraceID(ctx)
+	})
+	ctx := tracing.WithTraceID(tracing.WithCorrelationID(context.Background(), "corr-1"), "trace-1")
+	b.BroadcastContext(ctx, "room", "message", nil)
+
+	if msg := receive(t, got); msg != "corr-1 trace-1" {
+		t.Errorf("Expected \"corr-1 trace-1\", got %q", msg)
+	}
+}
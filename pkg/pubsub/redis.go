@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -15,18 +17,36 @@ var (
 	ErrRedisSubscription = errors.New("redis subscription failed")
 )
 
+// Subscriber connection tuning.
+const (
+	// redisPingInterval is how often the subscriber connection is pinged, so
+	// a dead connection is detected and replaced.
+	redisPingInterval = 30 * time.Second
+	// redisMinBackoff and redisMaxBackoff bound the delay between
+	// reconnect attempts.
+	redisMinBackoff = 100 * time.Millisecond
+	redisMaxBackoff = 5 * time.Second
+	// redisSubscriptionBuffer is the per-subscription delivery queue size.
+	// Messages are dropped when a handler falls this far behind.
+	redisSubscriptionBuffer = 256
+)
+
 // RedisConfig configures the Redis PubSub connection.
 type RedisConfig struct {
 	// Addr is the Redis server address (default: "localhost:6379")
 	Addr string
 
+	// Username is the ACL user (empty for the default user)
+	Username string
+
 	// Password is the Redis password (empty for no auth)
 	Password string
 
-	// DB is the Redis database number (default: 0)
+	// DB is the Redis database number (default: 0). Pub/sub channels are
+	// shared by all databases.
 	DB int
 
-	// PoolSize is the connection pool size (default: 10)
+	// PoolSize is the number of idle publishing connections kept (default: 10)
 	PoolSize int
 
 	// ReadTimeout for operations (default: 3s)
@@ -38,7 +58,7 @@ type RedisConfig struct {
 	// DialTimeout for initial connection (default: 5s)
 	DialTimeout time.Duration
 
-	// MaxRetries before giving up (default: 3)
+	// MaxRetries before a publish gives up (default: 3)
 	MaxRetries int
 }
 
@@ -55,37 +75,71 @@ func DefaultRedisConfig() *RedisConfig {
 	}
 }
 
-// RedisPubSub implements PubSub using Redis.
-// Note: This is a stub implementation. To use Redis, add the redis client dependency:
-// go get github.com/redis/go-redis/v9
+// withDefaults returns a copy of the config with unset fields defaulted.
+func (c RedisConfig) withDefaults() RedisConfig {
+	d := DefaultRedisConfig()
+	if c.Addr == "" {
+		c.Addr = d.Addr
+	}
+	if c.PoolSize <= 0 {
+		c.PoolSize = d.PoolSize
+	}
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = d.ReadTimeout
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = d.WriteTimeout
+	}
+	if c.DialTimeout <= 0 {
+		c.DialTimeout = d.DialTimeout
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = d.MaxRetries
+	}
+	return c
+}
+
+// RedisPubSub implements PubSub using Redis, so broadcasts reach the
+// subscribers of every instance connected to the same server.
+//
+// Publishes go through a pool of connections. Subscriptions share one
+// subscriber connection, which is re-established with backoff when it drops;
+// all channels and patterns are then subscribed again. Messages published
+// while it is down are lost, as with any Redis pub/sub client.
 type RedisPubSub struct {
-	config *RedisConfig
+	config RedisConfig
+	pool   chan *redisConn
 
-	// Subscriptions tracking
-	subs   map[string][]*redisSubscription
-	nextID int64
+	// Subscriptions by channel and by pattern
+	subs     map[string][]*redisSubscription
+	patterns map[string][]*redisSubscription
+	nextID   int64
+
+	// sub is the subscriber connection, nil while reconnecting
+	sub *redisConn
 
 	// State
 	ctx    context.Context
 	cancel context.CancelFunc
+	done   chan struct{}
 	closed bool
 
-	mu sync.RWMutex
+	mu sync.Mutex
 }
 
 // redisSubscription represents a Redis subscription.
 type redisSubscription struct {
 	id      int64
 	topic   string
-	handler func([]byte)
+	pattern bool
+	handler func(topic string, msg []byte)
+	ch      chan Message
 	ps      *RedisPubSub
 	closed  bool
-	mu      sync.Mutex
 }
 
-// NewRedisPubSub creates a new Redis PubSub.
-// Note: This creates a mock implementation. For real Redis support,
-// uncomment the go-redis integration below.
+// NewRedisPubSub connects to Redis. It fails if the server cannot be
+// reached; later connection losses are retried in the background.
 func NewRedisPubSub(config *RedisConfig) (*RedisPubSub, error) {
 	if config == nil {
 		config = DefaultRedisConfig()
@@ -94,36 +148,44 @@ func NewRedisPubSub(config *RedisConfig) (*RedisPubSub, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	ps := &RedisPubSub{
-		config: config,
-		subs:   make(map[string][]*redisSubscription),
-		ctx:    ctx,
-		cancel: cancel,
-	}
-
-	// In a real implementation, you would connect to Redis here:
-	// ps.client = redis.NewClient(&redis.Options{
-	//     Addr:         config.Addr,
-	//     Password:     config.Password,
-	//     DB:           config.DB,
-	//     PoolSize:     config.PoolSize,
-	//     ReadTimeout:  config.ReadTimeout,
-	//     WriteTimeout: config.WriteTimeout,
-	//     DialTimeout:  config.DialTimeout,
-	//     MaxRetries:   config.MaxRetries,
-	// })
-	//
-	// if err := ps.client.Ping(ctx).Err(); err != nil {
-	//     cancel()
-	//     return nil, fmt.Errorf("redis connection failed: %w", err)
-	// }
-	//
-	// ps.pubsub = ps.client.Subscribe(ctx)
+		config:   config.withDefaults(),
+		subs:     make(map[string][]*redisSubscription),
+		patterns: make(map[string][]*redisSubscription),
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	ps.pool = make(chan *redisConn, ps.config.PoolSize)
 
+	c, err := ps.conn()
+	if err == nil {
+		_, err = c.do("PING")
+	}
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("redis connection failed: %w", err)
+	}
+	ps.put(c)
+
+	go ps.run()
 	return ps, nil
 }
 
 // Subscribe adds a handler for a topic.
 func (ps *RedisPubSub) Subscribe(topic string, handler func(msg []byte)) (Subscription, error) {
+	return ps.subscribe(topic, false, func(_ string, msg []byte) {
+		handler(msg)
+	})
+}
+
+// PSubscribe adds a handler for all topics matching a Redis glob pattern
+// ("presence:*"). The handler receives the topic each message was
+// published to.
+func (ps *RedisPubSub) PSubscribe(pattern string, handler func(topic string, msg []byte)) (Subscription, error) {
+	return ps.subscribe(pattern, true, handler)
+}
+
+func (ps *RedisPubSub) subscribe(topic string, pattern bool, handler func(string, []byte)) (Subscription, error) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
@@ -135,103 +197,330 @@ func (ps *RedisPubSub) Subscribe(topic string, handler func(msg []byte)) (Subscr
 	sub := &redisSubscription{
 		id:      ps.nextID,
 		topic:   topic,
+		pattern: pattern,
 		handler: handler,
+		ch:      make(chan Message, redisSubscriptionBuffer),
 		ps:      ps,
 	}
 
-	ps.subs[topic] = append(ps.subs[topic], sub)
+	set, cmd := ps.subs, "SUBSCRIBE"
+	if pattern {
+		set, cmd = ps.patterns, "PSUBSCRIBE"
+	}
+	first := len(set[topic]) == 0
+	set[topic] = append(set[topic], sub)
 
-	// In a real implementation, you would subscribe to Redis here:
-	// if err := ps.pubsub.Subscribe(ps.ctx, topic); err != nil {
-	//     return nil, fmt.Errorf("redis subscribe failed: %w", err)
-	// }
+	// While disconnected, run subscribes on reconnect
+	if first && ps.sub != nil {
+		ps.sendSub(cmd, topic)
+	}
 
+	go sub.deliver()
 	return sub, nil
 }
 
-// Publish sends a message to all subscribers of a topic.
+// Publish sends a message to all subscribers of a topic, on every instance.
+// Failed attempts are retried on a new connection up to MaxRetries times.
 func (ps *RedisPubSub) Publish(topic string, msg []byte) error {
-	ps.mu.RLock()
-	defer ps.mu.RUnlock()
-
-	if ps.closed {
+	ps.mu.Lock()
+	closed := ps.closed
+	ps.mu.Unlock()
+	if closed {
 		return ErrPubSubClosed
 	}
 
-	// In a real implementation, you would publish to Redis:
-	// return ps.client.Publish(ps.ctx, topic, msg).Err()
-
-	// For now, deliver locally (useful for single-node or testing)
-	subs := ps.subs[topic]
-	for _, sub := range subs {
-		if !sub.closed {
-			// Deliver asynchronously to prevent blocking
-			go sub.handler(msg)
+	var err error
+	for attempt := 0; attempt <= ps.config.MaxRetries; attempt++ {
+		var c *redisConn
+		if c, err = ps.conn(); err != nil {
+			continue
+		}
+		_, err = c.do("PUBLISH", topic, string(msg))
+		var rerr redisError
+		if err == nil || errors.As(err, &rerr) {
+			ps.put(c)
+			return err
 		}
+		c.close()
 	}
+	return fmt.Errorf("%w: %v", ErrRedisNotConnected, err)
+}
 
-	return nil
+// Do runs a command on a pooled connection and returns its reply: simple
+// strings as string, integers as int64, bulk strings as []byte and arrays
+// as []any. Other packages use it to share the connection settings, e.g.
+// limits.RedisStore.
+func (ps *RedisPubSub) Do(ctx context.Context, args ...string) (any, error) {
+	ps.mu.Lock()
+	closed := ps.closed
+	ps.mu.Unlock()
+	if closed {
+		return nil, ErrPubSubClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c, err := ps.conn()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRedisNotConnected, err)
+	}
+	reply, err := c.do(args...)
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		c.close()
+		return nil, err
+	}
+	ps.put(c)
+	return reply, err
 }
 
 // Close shuts down the pubsub system.
 func (ps *RedisPubSub) Close() error {
 	ps.mu.Lock()
-	defer ps.mu.Unlock()
-
 	if ps.closed {
+		ps.mu.Unlock()
 		return nil
 	}
 
 	ps.closed = true
 	ps.cancel()
-
-	// In a real implementation:
-	// ps.pubsub.Close()
-	// ps.client.Close()
+	if ps.sub != nil {
+		ps.sub.close()
+		ps.sub = nil
+	}
 
 	// Clear subscriptions
-	for _, subs := range ps.subs {
-		for _, sub := range subs {
-			sub.closed = true
+	for _, set := range []map[string][]*redisSubscription{ps.subs, ps.patterns} {
+		for _, subs := range set {
+			for _, sub := range subs {
+				sub.closed = true
+				close(sub.ch)
+			}
 		}
 	}
 	ps.subs = make(map[string][]*redisSubscription)
+	ps.patterns = make(map[string][]*redisSubscription)
+	ps.mu.Unlock()
+
+	for {
+		select {
+		case c := <-ps.pool:
+			c.close()
+		default:
+			<-ps.done
+			return nil
+		}
+	}
+}
 
-	return nil
+// conn takes an idle publishing connection or dials a new one.
+func (ps *RedisPubSub) conn() (*redisConn, error) {
+	select {
+	case c := <-ps.pool:
+		return c, nil
+	default:
+	}
+	c, err := dialRedis(ps.ctx, &ps.config)
+	if err != nil {
+		return nil, err
+	}
+	if ps.config.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(ps.config.DB)); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// put returns a healthy connection to the pool.
+func (ps *RedisPubSub) put(c *redisConn) {
+	ps.mu.Lock()
+	closed := ps.closed
+	ps.mu.Unlock()
+	if !closed {
+		select {
+		case ps.pool <- c:
+			return
+		default:
+		}
+	}
+	c.close()
+}
+
+// run maintains the subscriber connection until Close.
+func (ps *RedisPubSub) run() {
+	defer close(ps.done)
+
+	backoff := redisMinBackoff
+	for {
+		c, err := dialRedis(ps.ctx, &ps.config)
+		if err == nil {
+			if ps.resubscribe(c) {
+				backoff = redisMinBackoff
+				ps.listen(c)
+			}
+			ps.mu.Lock()
+			if ps.sub == c {
+				ps.sub = nil
+			}
+			ps.mu.Unlock()
+			c.close()
+		}
+
+		select {
+		case <-ps.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > redisMaxBackoff {
+			backoff = redisMaxBackoff
+		}
+	}
+}
+
+// resubscribe makes c the subscriber connection and subscribes it to every
+// channel and pattern. It returns false if the pubsub was closed.
+func (ps *RedisPubSub) resubscribe(c *redisConn) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.closed {
+		return false
+	}
+	ps.sub = c
+	for cmd, set := range map[string]map[string][]*redisSubscription{"SUBSCRIBE": ps.subs, "PSUBSCRIBE": ps.patterns} {
+		if len(set) == 0 {
+			continue
+		}
+		args := make([]string, 0, len(set)+1)
+		args = append(args, cmd)
+		for topic := range set {
+			args = append(args, topic)
+		}
+		ps.sendSub(args...)
+	}
+	return true
+}
+
+// sendSub sends a command on the subscriber connection. Called with ps.mu
+// held. On failure the connection is closed, so the listener reconnects and
+// subscribes again.
+func (ps *RedisPubSub) sendSub(args ...string) {
+	if err := ps.sub.send(args...); err != nil {
+		ps.sub.close()
+	}
+}
+
+// listen dispatches messages from the subscriber connection until it fails.
+func (ps *RedisPubSub) listen(c *redisConn) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(redisPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ps.mu.Lock()
+				if ps.sub == c {
+					ps.sendSub("PING")
+				}
+				ps.mu.Unlock()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for {
+		reply, err := c.receive(redisPingInterval + ps.config.ReadTimeout)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]any)
+		if len(items) < 3 {
+			continue
+		}
+		kind, _ := items[0].([]byte)
+		switch string(kind) {
+		case "message":
+			topic, _ := items[1].([]byte)
+			payload, _ := items[2].([]byte)
+			ps.dispatch(ps.subs, string(topic), string(topic), payload)
+		case "pmessage":
+			if len(items) < 4 {
+				continue
+			}
+			pattern, _ := items[1].([]byte)
+			topic, _ := items[2].([]byte)
+			payload, _ := items[3].([]byte)
+			ps.dispatch(ps.patterns, string(pattern), string(topic), payload)
+		}
+	}
+}
+
+// dispatch queues a message for the subscriptions registered under key.
+func (ps *RedisPubSub) dispatch(set map[string][]*redisSubscription, key, topic string, payload []byte) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, sub := range set[key] {
+		select {
+		case sub.ch <- Message{Topic: topic, Payload: payload}:
+		default:
+			// Channel full, drop message (backpressure)
+		}
+	}
+}
+
+// deliver runs the handler for each queued message, in order.
+func (s *redisSubscription) deliver() {
+	for msg := range s.ch {
+		s.handler(msg.Topic, msg.Payload)
+	}
 }
 
 // Unsubscribe removes this subscription.
 func (s *redisSubscription) Unsubscribe() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	ps := s.ps
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
 
 	if s.closed {
 		return nil
 	}
-
 	s.closed = true
+	close(s.ch)
 
-	s.ps.mu.Lock()
-	defer s.ps.mu.Unlock()
+	set, cmd := ps.subs, "UNSUBSCRIBE"
+	if s.pattern {
+		set, cmd = ps.patterns, "PUNSUBSCRIBE"
+	}
 
 	// Remove from subscriptions list
-	subs := s.ps.subs[s.topic]
+	subs := set[s.topic]
 	for i, sub := range subs {
 		if sub.id == s.id {
-			s.ps.subs[s.topic] = append(subs[:i], subs[i+1:]...)
+			set[s.topic] = append(subs[:i:i], subs[i+1:]...)
 			break
 		}
 	}
 
 	// If no more subscribers for this topic, unsubscribe from Redis
-	// if len(s.ps.subs[s.topic]) == 0 {
-	//     s.ps.pubsub.Unsubscribe(s.ps.ctx, s.topic)
-	// }
+	if len(set[s.topic]) == 0 {
+		delete(set, s.topic)
+		if ps.sub != nil {
+			ps.sendSub(cmd, s.topic)
+		}
+	}
 
 	return nil
 }
 
-// Topic returns the subscribed topic.
+// Topic returns the subscribed topic, or pattern.
 func (s *redisSubscription) Topic() string {
 	return s.topic
 }
@@ -252,17 +541,15 @@ func NewRedisBroadcaster(ps *RedisPubSub, prefix string) *RedisBroadcaster {
 
 // Broadcast sends a message to a topic.
 func (b *RedisBroadcaster) Broadcast(topic string, event string, payload map[string]any) error {
-	fullTopic := b.prefix + topic
+	return b.BroadcastContext(context.Background(), topic, event, payload)
+}
 
-	msg := struct {
-		Event   string         `json:"event"`
-		Payload map[string]any `json:"payload"`
-	}{
-		Event:   event,
-		Payload: payload,
-	}
+// BroadcastContext sends a message to a topic with the correlation and
+// trace IDs of ctx (see Broadcaster.BroadcastContext).
+func (b *RedisBroadcaster) BroadcastContext(ctx context.Context, topic string, event string, payload map[string]any) error {
+	fullTopic := b.prefix + topic
 
-	data, err := json.Marshal(msg)
+	data, err := json.Marshal(newEnvelope(ctx, event, payload))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,282 @@
+package pubsub
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal in-process Redis server supporting the pub/sub
+// commands RedisPubSub uses.
+type fakeRedis struct {
+	ln      net.Listener
+	mu      sync.Mutex
+	clients map[*fakeRedisClient]bool
+}
+
+type fakeRedisClient struct {
+	conn     net.Conn
+	mu       sync.Mutex
+	channels map[string]bool
+	patterns map[string]bool
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeRedis{ln: ln, clients: make(map[*fakeRedisClient]bool)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c := &fakeRedisClient{conn: conn, channels: map[string]bool{}, patterns: map[string]bool{}}
+			s.mu.Lock()
+			s.clients[c] = true
+			s.mu.Unlock()
+			go s.serve(c)
+		}
+	}()
+	t.Cleanup(func() {
+		ln.Close()
+		s.dropAll()
+	})
+	return s
+}
+
+func (s *fakeRedis) addr() string {
+	return s.ln.Addr().String()
+}
+
+// dropAll closes every client connection.
+func (s *fakeRedis) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.conn.Close()
+		delete(s.clients, c)
+	}
+}
+
+// subscribers counts the clients subscribed to a channel or pattern.
+func (s *fakeRedis) subscribers(topic string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for c := range s.clients {
+		c.mu.Lock()
+		if c.channels[topic] || c.patterns[topic] {
+			n++
+		}
+		c.mu.Unlock()
+	}
+	return n
+}
+
+func (s *fakeRedis) serve(c *fakeRedisClient) {
+	defer func() {
+		c.conn.Close()
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	r := bufio.NewReader(c.conn)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			c.write("+PONG\r\n")
+		case "AUTH", "SELECT":
+			c.write("+OK\r\n")
+		case "PUBLISH":
+			c.write(fmt.Sprintf(":%d\r\n", s.publish(args[1], args[2])))
+		case "SUBSCRIBE", "PSUBSCRIBE", "UNSUBSCRIBE", "PUNSUBSCRIBE":
+			kind := strings.ToLower(args[0])
+			set := c.channels
+			if strings.HasPrefix(kind, "p") {
+				set = c.patterns
+			}
+			for _, topic := range args[1:] {
+				c.mu.Lock()
+				if strings.Contains(kind, "unsub") {
+					delete(set, topic)
+				} else {
+					set[topic] = true
+				}
+				c.mu.Unlock()
+				c.write(bulkArray(kind, topic) + ":1\r\n")
+			}
+		default:
+			c.write("-ERR unknown command\r\n")
+		}
+	}
+}
+
+func (s *fakeRedis) publish(channel, payload string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for c := range s.clients {
+		c.mu.Lock()
+		subscribed := c.channels[channel]
+		var matched []string
+		for pattern := range c.patterns {
+			if ok, _ := path.Match(pattern, channel); ok {
+				matched = append(matched, pattern)
+			}
+		}
+		c.mu.Unlock()
+
+		if subscribed {
+			c.write("*3\r\n" + bulk("message") + bulk(channel) + bulk(payload))
+			n++
+		}
+		for _, pattern := range matched {
+			c.write("*4\r\n" + bulk("pmessage") + bulk(pattern) + bulk(channel) + bulk(payload))
+			n++
+		}
+	}
+	return n
+}
+
+func (c *fakeRedisClient) write(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.Write([]byte(s))
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// bulkArray starts a 3 element array with two bulk strings.
+func bulkArray(a, b string) string {
+	return "*3\r\n" + bulk(a) + bulk(b)
+}
+
+func newTestRedisPubSub(t *testing.T, addr string) *RedisPubSub {
+	t.Helper()
+	ps, err := NewRedisPubSub(&RedisConfig{Addr: addr, PoolSize: 2})
+	if err != nil {
+		t.Fatalf("NewRedisPubSub: %v", err)
+	}
+	t.Cleanup(func() { ps.Close() })
+	return ps
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return ""
+	}
+}
+
+func TestRedisPubSub_CrossInstance(t *testing.T) {
+	server := newFakeRedis(t)
+	a := newTestRedisPubSub(t, server.addr())
+	b := newTestRedisPubSub(t, server.addr())
+
+	got := make(chan string, 10)
+	sub, err := b.Subscribe("chat", func(msg []byte) { got <- string(msg) })
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	waitFor(t, "subscription", func() bool { return server.subscribers("chat") == 1 })
+
+	if err := a.Publish("chat", []byte("hello")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if msg := receive(t, got); msg != "hello" {
+		t.Errorf("Expected hello, got %q", msg)
+	}
+
+	sub.Unsubscribe()
+	waitFor(t, "unsubscription", func() bool { return server.subscribers("chat") == 0 })
+}
+
+func TestRedisPubSub_PatternSubscription(t *testing.T) {
+	server := newFakeRedis(t)
+	ps := newTestRedisPubSub(t, server.addr())
+
+	got := make(chan string, 10)
+	_, err := ps.PSubscribe("presence:*", func(topic string, msg []byte) { got <- topic + "=" + string(msg) })
+	if err != nil {
+		t.Fatalf("PSubscribe: %v", err)
+	}
+	waitFor(t, "pattern subscription", func() bool { return server.subscribers("presence:*") == 1 })
+
+	ps.Publish("chat:room1", []byte("ignored"))
+	ps.Publish("presence:room1", []byte("join"))
+	if msg := receive(t, got); msg != "presence:room1=join" {
+		t.Errorf("Expected presence:room1=join, got %q", msg)
+	}
+}
+
+func TestRedisPubSub_Reconnect(t *testing.T) {
+	server := newFakeRedis(t)
+	ps := newTestRedisPubSub(t, server.addr())
+
+	got := make(chan string, 10)
+	ps.Subscribe("chat", func(msg []byte) { got <- string(msg) })
+	waitFor(t, "subscription", func() bool { return server.subscribers("chat") == 1 })
+
+	// Drop every connection: the subscriber reconnects and subscribes
+	// again, the publisher retries on a fresh connection
+	server.dropAll()
+	waitFor(t, "resubscription", func() bool { return server.subscribers("chat") == 1 })
+
+	if err := ps.Publish("chat", []byte("after")); err != nil {
+		t.Fatalf("Publish after reconnect: %v", err)
+	}
+	if msg := receive(t, got); msg != "after" {
+		t.Errorf("Expected after, got %q", msg)
+	}
+}
+
+func TestRedisPubSub_Unreachable(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := NewRedisPubSub(&RedisConfig{Addr: addr, DialTimeout: time.Second}); err == nil {
+		t.Error("Expected an error for an unreachable server")
+	}
+}
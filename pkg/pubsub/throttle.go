@@ -0,0 +1,186 @@
+package pubsub
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ThrottledPubSub wraps a PubSub and limits how often messages on chatty
+// topics are delivered to each subscriber. Messages that arrive faster than
+// the configured rate are coalesced: only the latest one is delivered when the
+// next slot opens (latest-wins), which suits cursor positions, progress ticks
+// and similar state streams where intermediate values are disposable.
+type ThrottledPubSub struct {
+	PubSub
+
+	rules     []throttleRule
+	coalesced atomic.Uint64
+	mu        sync.RWMutex
+}
+
+// throttleRule maps a topic pattern to a minimum delivery interval.
+type throttleRule struct {
+	pattern  string
+	interval time.Duration
+}
+
+// NewThrottledPubSub creates a throttling wrapper around inner.
+func NewThrottledPubSub(inner PubSub) *ThrottledPubSub {
+	return &ThrottledPubSub{PubSub: inner}
+}
+
+// Throttle limits delivery on topics matching pattern to perSecond messages
+// per subscriber. A pattern ending in "*" matches by prefix ("cursor:*").
+// Rules apply to subscriptions created after the call; perSecond <= 0
+// removes the rule.
+func (t *ThrottledPubSub) Throttle(pattern string, perSecond int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rules := t.rules[:0:0]
+	for _, r := range t.rules {
+		if r.pattern != pattern {
+			rules = append(rules, r)
+		}
+	}
+	if perSecond > 0 {
+		rules = append(rules, throttleRule{
+			pattern:  pattern,
+			interval: time.Second / time.Duration(perSecond),
+		})
+	}
+	t.rules = rules
+}
+
+// intervalFor returns the delivery interval for a topic (0 = unthrottled).
+// Exact matches win over prefix matches; longer prefixes win over shorter.
+func (t *ThrottledPubSub) intervalFor(topic string) time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var best time.Duration
+	bestLen := -1
+	for _, r := range t.rules {
+		if r.pattern == topic {
+			return r.interval
+		}
+		if prefix, ok := strings.CutSuffix(r.pattern, "*"); ok && strings.HasPrefix(topic, prefix) {
+			if len(prefix) > bestLen {
+				best = r.interval
+				bestLen = len(prefix)
+			}
+		}
+	}
+	return best
+}
+
+// Subscribe adds a handler for a topic, throttled if a rule matches.
+func (t *ThrottledPubSub) Subscribe(topic string, handler func(msg []byte)) (Subscription, error) {
+	interval := t.intervalFor(topic)
+	if interval <= 0 {
+		return t.PubSub.Subscribe(topic, handler)
+	}
+
+	th := &throttler{
+		interval:  interval,
+		handler:   handler,
+		coalesced: &t.coalesced,
+	}
+
+	sub, err := t.PubSub.Subscribe(topic, th.deliver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttledSubscription{Subscription: sub, throttler: th}, nil
+}
+
+// Coalesced returns the number of messages replaced by a newer one before
+// delivery, across all throttled subscriptions.
+func (t *ThrottledPubSub) Coalesced() uint64 {
+	return t.coalesced.Load()
+}
+
+// throttler enforces a minimum interval between deliveries to one handler.
+type throttler struct {
+	interval  time.Duration
+	handler   func([]byte)
+	coalesced *atomic.Uint64
+
+	last    time.Time
+	pending []byte
+	timer   *time.Timer
+	stopped bool
+	mu      sync.Mutex
+}
+
+// deliver delivers msg immediately if the interval has elapsed, otherwise
+// keeps it as the pending message and schedules a flush.
+func (th *throttler) deliver(msg []byte) {
+	th.mu.Lock()
+	if th.stopped {
+		th.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(th.last)
+	if th.timer == nil && elapsed >= th.interval {
+		th.last = now
+		th.mu.Unlock()
+		th.handler(msg)
+		return
+	}
+
+	if th.pending != nil {
+		th.coalesced.Add(1)
+	}
+	th.pending = msg
+	if th.timer == nil {
+		th.timer = time.AfterFunc(th.interval-elapsed, th.flush)
+	}
+	th.mu.Unlock()
+}
+
+// flush delivers the latest pending message.
+func (th *throttler) flush() {
+	th.mu.Lock()
+	msg := th.pending
+	th.pending = nil
+	th.timer = nil
+	if th.stopped || msg == nil {
+		th.mu.Unlock()
+		return
+	}
+	th.last = time.Now()
+	th.mu.Unlock()
+
+	th.handler(msg)
+}
+
+// stop discards any pending message and prevents further deliveries.
+func (th *throttler) stop() {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	th.stopped = true
+	th.pending = nil
+	if th.timer != nil {
+		th.timer.Stop()
+		th.timer = nil
+	}
+}
+
+// throttledSubscription stops the throttler when unsubscribed.
+type throttledSubscription struct {
+	Subscription
+	throttler *throttler
+}
+
+// Unsubscribe removes this subscription and drops any pending message.
+func (s *throttledSubscription) Unsubscribe() error {
+	s.throttler.stop()
+	return s.Subscription.Unsubscribe()
+}
@@ -0,0 +1,132 @@
+package pubsub
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestThrottledPubSub_LatestWins(t *testing.T) {
+	ps := NewThrottledPubSub(NewMemoryPubSub())
+	defer ps.Close()
+
+	ps.Throttle("cursor:*", 10) // one delivery per 100ms
+
+	var mu sync.Mutex
+	var received []string
+
+	sub, err := ps.Subscribe("cursor:room1", func(msg []byte) {
+		mu.Lock()
+		received = append(received, string(msg))
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	for _, m := range []string{"a", "b", "c", "d", "e"} {
+		ps.Publish("cursor:room1", []byte(m))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 deliveries (first + latest), got %d: %v", len(received), received)
+	}
+	if received[0] != "a" || received[1] != "e" {
+		t.Errorf("Expected [a e], got %v", received)
+	}
+	if ps.Coalesced() != 3 {
+		t.Errorf("Expected 3 coalesced messages, got %d", ps.Coalesced())
+	}
+}
+
+func TestThrottledPubSub_UnthrottledTopic(t *testing.T) {
+	ps := NewThrottledPubSub(NewMemoryPubSub())
+	defer ps.Close()
+
+	ps.Throttle("cursor:*", 1)
+
+	var mu sync.Mutex
+	count := 0
+
+	sub, _ := ps.Subscribe("chat:room1", func(msg []byte) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+	defer sub.Unsubscribe()
+
+	for i := 0; i < 5; i++ {
+		ps.Publish("chat:room1", []byte("x"))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count != 5 {
+		t.Errorf("Expected 5 deliveries on unthrottled topic, got %d", count)
+	}
+}
+
+func TestThrottledPubSub_UnsubscribeDropsPending(t *testing.T) {
+	ps := NewThrottledPubSub(NewMemoryPubSub())
+	defer ps.Close()
+
+	ps.Throttle("progress", 5)
+
+	var mu sync.Mutex
+	count := 0
+
+	sub, _ := ps.Subscribe("progress", func(msg []byte) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+
+	ps.Publish("progress", []byte("1"))
+	ps.Publish("progress", []byte("2"))
+	time.Sleep(20 * time.Millisecond)
+	sub.Unsubscribe()
+
+	time.Sleep(250 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count != 1 {
+		t.Errorf("Expected pending message to be dropped after unsubscribe, got %d deliveries", count)
+	}
+}
+
+func TestThrottledPubSub_IntervalFor(t *testing.T) {
+	ps := NewThrottledPubSub(NewMemoryPubSub())
+	defer ps.Close()
+
+	ps.Throttle("a:*", 10)
+	ps.Throttle("a:b:*", 20)
+	ps.Throttle("a:b:c", 50)
+
+	tests := map[string]time.Duration{
+		"a:x":     100 * time.Millisecond,
+		"a:b:x":   50 * time.Millisecond,
+		"a:b:c":   20 * time.Millisecond,
+		"other":   0,
+		"a:b:c:d": 50 * time.Millisecond,
+	}
+	for topic, want := range tests {
+		if got := ps.intervalFor(topic); got != want {
+			t.Errorf("intervalFor(%q) = %v, want %v", topic, got, want)
+		}
+	}
+
+	ps.Throttle("a:*", 0)
+	if got := ps.intervalFor("a:x"); got != 0 {
+		t.Errorf("Expected rule removal, got %v", got)
+	}
+}
@@ -0,0 +1,333 @@
+// Package receipts provides per-message delivery and read tracking for chat
+// style components. Clients acknowledge messages they have received or seen
+// (see the lv-receipt attribute in the client), the Tracker aggregates the
+// acknowledgements, and the sender's component is notified through
+// HandleInfo so it can render ✓ / ✓✓ / "Seen" indicators.
+package receipts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+// AckEvent is the client event carrying acknowledgements:
+// {"ids": ["m1", "m2"], "status": "delivered" | "read"}.
+const AckEvent = "lv:ack"
+
+// Status is the delivery state of a message, ordered sent < delivered < read.
+type Status int
+
+// Message statuses.
+const (
+	StatusSent Status = iota
+	StatusDelivered
+	StatusRead
+)
+
+// String returns the status name used on the wire.
+func (s Status) String() string {
+	switch s {
+	case StatusDelivered:
+		return "delivered"
+	case StatusRead:
+		return "read"
+	default:
+		return "sent"
+	}
+}
+
+// ParseStatus parses a wire status name.
+func ParseStatus(s string) (Status, error) {
+	switch s {
+	case "sent":
+		return StatusSent, nil
+	case "delivered":
+		return StatusDelivered, nil
+	case "read":
+		return StatusRead, nil
+	}
+	return StatusSent, fmt.Errorf("%w: %q", ErrInvalidStatus, s)
+}
+
+// Errors returned by the tracker.
+var (
+	ErrInvalidStatus = errors.New("receipts: invalid status")
+	ErrInvalidAck    = errors.New("receipts: invalid ack payload")
+)
+
+// DefaultMaxMessages bounds how many messages a Tracker remembers.
+const DefaultMaxMessages = 1000
+
+// Receipt is the aggregated state of one message, delivered to the sender's
+// component via HandleInfo whenever it changes.
+type Receipt struct {
+	MessageID string
+	Sender    string
+	// Status is the best status reached by any recipient.
+	Status Status
+	// Delivered and Read list recipients by user ID, in acknowledgement order.
+	// Read recipients are also listed in Delivered.
+	Delivered []string
+	Read      []string
+}
+
+// message is the tracked state of one message.
+type message struct {
+	sender     string
+	recipients map[string]Status
+	order      []string
+}
+
+// event is published so trackers on other nodes apply the same changes.
+type event struct {
+	Origin    string `json:"o"`
+	Type      string `json:"t"` // "sent" or "ack"
+	MessageID string `json:"m"`
+	User      string `json:"u"`
+	Status    Status `json:"s,omitempty"`
+}
+
+// Tracker tracks receipts for one conversation.
+type Tracker struct {
+	id          string
+	topic       string
+	ps          pubsub.PubSub
+	sub         pubsub.Subscription
+	maxMessages int
+
+	mu       sync.Mutex
+	messages map[string]*message
+	order    []string
+	watchers map[string]map[*core.Socket]bool // sender -> sockets
+}
+
+// NewTracker creates a tracker for a conversation. With a non-nil pubsub,
+// trackers for the same room on different nodes share state.
+func NewTracker(ps pubsub.PubSub, room string) (*Tracker, error) {
+	var origin [8]byte
+	rand.Read(origin[:])
+
+	t := &Tracker{
+		id:          hex.EncodeToString(origin[:]),
+		topic:       "receipts:" + room,
+		ps:          ps,
+		maxMessages: DefaultMaxMessages,
+		messages:    make(map[string]*message),
+		watchers:    make(map[string]map[*core.Socket]bool),
+	}
+
+	if ps != nil {
+		sub, err := ps.Subscribe(t.topic, t.handleRemote)
+		if err != nil {
+			return nil, fmt.Errorf("receipts: subscribe: %w", err)
+		}
+		t.sub = sub
+	}
+	return t, nil
+}
+
+// SetMaxMessages bounds memory use; the oldest messages are forgotten first.
+func (t *Tracker) SetMaxMessages(n int) *Tracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n > 0 {
+		t.maxMessages = n
+	}
+	return t
+}
+
+// Sent registers a message sent by sender.
+func (t *Tracker) Sent(messageID, sender string) {
+	t.apply(event{Type: "sent", MessageID: messageID, User: sender})
+	t.publish(event{Type: "sent", MessageID: messageID, User: sender})
+}
+
+// Ack records that user received (or read) a message. Statuses only move
+// forward and acknowledgements from the sender are ignored.
+func (t *Tracker) Ack(messageID, user string, status Status) {
+	ev := event{Type: "ack", MessageID: messageID, User: user, Status: status}
+	t.apply(ev)
+	t.publish(ev)
+}
+
+// HandleEvent processes AckEvent from user's client and reports whether it
+// consumed the event.
+func (t *Tracker) HandleEvent(user, eventName string, payload map[string]any) (bool, error) {
+	if eventName != AckEvent {
+		return false, nil
+	}
+
+	statusName, _ := payload["status"].(string)
+	status, err := ParseStatus(statusName)
+	if err != nil {
+		return true, err
+	}
+	ids, ok := payload["ids"].([]any)
+	if !ok {
+		return true, ErrInvalidAck
+	}
+	for _, raw := range ids {
+		if id, ok := raw.(string); ok && id != "" {
+			t.Ack(id, user, status)
+		}
+	}
+	return true, nil
+}
+
+// Watch delivers Receipt updates for messages sent by sender to socket's
+// HandleInfo. Call the returned function to stop.
+func (t *Tracker) Watch(sender string, socket *core.Socket) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.watchers[sender] == nil {
+		t.watchers[sender] = make(map[*core.Socket]bool)
+	}
+	t.watchers[sender][socket] = true
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.watchers[sender], socket)
+		if len(t.watchers[sender]) == 0 {
+			delete(t.watchers, sender)
+		}
+	}
+}
+
+// Receipt returns the current state of a message.
+func (t *Tracker) Receipt(messageID string) (Receipt, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	m, ok := t.messages[messageID]
+	if !ok {
+		return Receipt{}, false
+	}
+	return m.receipt(messageID), true
+}
+
+// Close stops listening for remote changes.
+func (t *Tracker) Close() error {
+	if t.sub != nil {
+		return t.sub.Unsubscribe()
+	}
+	return nil
+}
+
+func (t *Tracker) apply(ev event) {
+	t.mu.Lock()
+
+	m, exists := t.messages[ev.MessageID]
+	switch ev.Type {
+	case "sent":
+		if !exists {
+			t.messages[ev.MessageID] = &message{sender: ev.User, recipients: make(map[string]Status)}
+			t.order = append(t.order, ev.MessageID)
+			t.evict()
+		}
+		t.mu.Unlock()
+		return
+
+	case "ack":
+		if !exists || ev.User == m.sender || ev.Status <= m.recipients[ev.User] {
+			t.mu.Unlock()
+			return
+		}
+		if _, seen := m.recipients[ev.User]; !seen {
+			m.order = append(m.order, ev.User)
+		}
+		m.recipients[ev.User] = ev.Status
+	default:
+		t.mu.Unlock()
+		return
+	}
+
+	receipt := m.receipt(ev.MessageID)
+	sockets := make([]*core.Socket, 0, len(t.watchers[m.sender]))
+	for s := range t.watchers[m.sender] {
+		sockets = append(sockets, s)
+	}
+	t.mu.Unlock()
+
+	for _, s := range sockets {
+		s.SendInfo(receipt)
+	}
+}
+
+// evict drops the oldest messages beyond maxMessages. Caller holds t.mu.
+func (t *Tracker) evict() {
+	for len(t.order) > t.maxMessages {
+		delete(t.messages, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+func (t *Tracker) publish(ev event) {
+	if t.ps == nil {
+		return
+	}
+	ev.Origin = t.id
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	t.ps.Publish(t.topic, data)
+}
+
+func (t *Tracker) handleRemote(data []byte) {
+	var ev event
+	if err := json.Unmarshal(data, &ev); err != nil || ev.Origin == t.id {
+		return
+	}
+	t.apply(ev)
+}
+
+func (m *message) receipt(id string) Receipt {
+	r := Receipt{MessageID: id, Sender: m.sender}
+	for _, user := range m.order {
+		status := m.recipients[user]
+		if status >= StatusDelivered {
+			r.Delivered = append(r.Delivered, user)
+		}
+		if status == StatusRead {
+			r.Read = append(r.Read, user)
+		}
+		if status > r.Status {
+			r.Status = status
+		}
+	}
+	return r
+}
+
+// Summary formats the receipt for display: "Sent", "Delivered",
+// "Seen by Ana" or "Seen by Ana, Bob and 2 others".
+func (r Receipt) Summary() string {
+	switch {
+	case len(r.Read) == 0 && len(r.Delivered) > 0:
+		return "Delivered"
+	case len(r.Read) == 0:
+		return "Sent"
+	}
+
+	names := append([]string(nil), r.Read...)
+	sort.Strings(names)
+	switch len(names) {
+	case 1:
+		return "Seen by " + names[0]
+	case 2:
+		return "Seen by " + names[0] + " and " + names[1]
+	case 3:
+		return fmt.Sprintf("Seen by %s, %s and 1 other", names[0], names[1])
+	default:
+		return fmt.Sprintf("Seen by %s, %s and %d others", names[0], names[1], len(names)-2)
+	}
+}
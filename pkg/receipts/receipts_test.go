@@ -0,0 +1,112 @@
+package receipts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+type nopTransport struct{}
+
+func (nopTransport) Send(core.Message) error { return nil }
+func (nopTransport) Close() error            { return nil }
+func (nopTransport) IsConnected() bool       { return true }
+
+func TestTracker_AggregatesAcks(t *testing.T) {
+	tr, _ := NewTracker(nil, "room")
+	tr.Sent("m1", "ana")
+
+	tr.Ack("m1", "ana", StatusRead) // sender acks are ignored
+	tr.Ack("m1", "bob", StatusDelivered)
+	tr.Ack("m1", "cat", StatusRead)
+	tr.Ack("m1", "cat", StatusDelivered) // never moves backwards
+
+	r, ok := tr.Receipt("m1")
+	if !ok {
+		t.Fatal("expected receipt")
+	}
+	if r.Status != StatusRead {
+		t.Errorf("Status = %v, want read", r.Status)
+	}
+	if len(r.Delivered) != 2 || len(r.Read) != 1 || r.Read[0] != "cat" {
+		t.Errorf("unexpected receipt: %+v", r)
+	}
+	if got := r.Summary(); got != "Seen by cat" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	if _, ok := tr.Receipt("unknown"); ok {
+		t.Error("unknown messages should have no receipt")
+	}
+}
+
+func TestTracker_HandleEventNotifiesSender(t *testing.T) {
+	tr, _ := NewTracker(nil, "room")
+	socket := core.NewSocket("s1", nopTransport{})
+	stop := tr.Watch("ana", socket)
+	defer stop()
+
+	tr.Sent("m1", "ana")
+	handled, err := tr.HandleEvent("bob", AckEvent, map[string]any{
+		"ids":    []any{"m1"},
+		"status": "read",
+	})
+	if !handled || err != nil {
+		t.Fatalf("HandleEvent = %v, %v", handled, err)
+	}
+
+	select {
+	case msg := <-socket.Info():
+		r, ok := msg.(Receipt)
+		if !ok || r.MessageID != "m1" || r.Status != StatusRead {
+			t.Errorf("unexpected info: %#v", msg)
+		}
+	default:
+		t.Fatal("expected a receipt for the sender")
+	}
+
+	if handled, _ := tr.HandleEvent("bob", "other", nil); handled {
+		t.Error("other events should not be handled")
+	}
+	if _, err := tr.HandleEvent("bob", AckEvent, map[string]any{"status": "bogus"}); err == nil {
+		t.Error("expected error for invalid status")
+	}
+}
+
+func TestTracker_SharesStateOverPubSub(t *testing.T) {
+	ps := pubsub.NewMemoryPubSub()
+	a, _ := NewTracker(ps, "room")
+	b, _ := NewTracker(ps, "room")
+	defer a.Close()
+	defer b.Close()
+
+	a.Sent("m1", "ana")
+	time.Sleep(20 * time.Millisecond)
+	b.Ack("m1", "bob", StatusDelivered)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if r, ok := a.Receipt("m1"); ok && r.Status == StatusDelivered {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("ack from another tracker was not applied")
+}
+
+func TestTracker_EvictsOldMessages(t *testing.T) {
+	tr, _ := NewTracker(nil, "room")
+	tr.SetMaxMessages(2)
+	tr.Sent("m1", "ana")
+	tr.Sent("m2", "ana")
+	tr.Sent("m3", "ana")
+
+	if _, ok := tr.Receipt("m1"); ok {
+		t.Error("m1 should have been evicted")
+	}
+	if _, ok := tr.Receipt("m3"); !ok {
+		t.Error("m3 should be tracked")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
 )
 
 // Common errors.
@@ -31,6 +33,7 @@ type StateStore interface {
 type RecoveryManager struct {
 	store   StateStore
 	secret  []byte
+	keys    *secrets.Keyring
 	ttl     time.Duration
 	prefix  string
 	mu      sync.RWMutex
@@ -40,6 +43,9 @@ type RecoveryManager struct {
 type Config struct {
 	Store  StateStore
 	Secret []byte
+	// Keys, if set, replaces Secret and allows rotating the signing key
+	// without invalidating outstanding tokens.
+	Keys   *secrets.Keyring
 	TTL    time.Duration
 	Prefix string
 }
@@ -62,6 +68,7 @@ func NewRecoveryManager(config *Config) *RecoveryManager {
 	return &RecoveryManager{
 		store:  config.Store,
 		secret: config.Secret,
+		keys:   config.Keys,
 		ttl:    config.TTL,
 		prefix: config.Prefix,
 	}
@@ -143,7 +150,11 @@ func (rm *RecoveryManager) GenerateToken(socketID string, state *ComponentState)
 	}
 
 	// Sign the token
-	token.Signature = rm.signToken(token)
+	signature, err := rm.signToken(token)
+	if err != nil {
+		return "", err
+	}
+	token.Signature = signature
 
 	// Encode to JSON then base64
 	data, err := json.Marshal(token)
@@ -181,7 +192,14 @@ func (rm *RecoveryManager) validateToken(token *RecoveryToken) error {
 	}
 
 	// Verify signature
-	expectedSig := rm.signToken(token)
+	if rm.keys != nil {
+		sig, err := base64.URLEncoding.DecodeString(token.Signature)
+		if err != nil || !rm.keys.Verify(tokenData(token), sig) {
+			return ErrTokenInvalid
+		}
+		return nil
+	}
+	expectedSig, _ := rm.signToken(token)
 	if !hmac.Equal([]byte(token.Signature), []byte(expectedSig)) {
 		return ErrTokenInvalid
 	}
@@ -189,19 +207,30 @@ func (rm *RecoveryManager) validateToken(token *RecoveryToken) error {
 	return nil
 }
 
-func (rm *RecoveryManager) signToken(token *RecoveryToken) string {
-	// Create signature data (without the signature field)
-	data := fmt.Sprintf("%s:%s:%d:%d:%d",
+func (rm *RecoveryManager) signToken(token *RecoveryToken) (string, error) {
+	data := tokenData(token)
+	if rm.keys != nil {
+		sig, err := rm.keys.Sign(data)
+		if err != nil {
+			return "", err
+		}
+		return base64.URLEncoding.EncodeToString(sig), nil
+	}
+
+	mac := hmac.New(sha256.New, rm.secret)
+	mac.Write(data)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// tokenData returns the signed fields of a token (without the signature).
+func tokenData(token *RecoveryToken) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%d:%d:%d",
 		token.SocketID,
 		token.ComponentName,
 		token.StateVersion,
 		token.CreatedAt,
 		token.ExpiresAt,
-	)
-
-	mac := hmac.New(sha256.New, rm.secret)
-	mac.Write([]byte(data))
-	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+	))
 }
 
 // MemoryStore is an in-memory state store.
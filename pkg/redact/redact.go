@@ -0,0 +1,338 @@
+// Package redact removes personal and secret data from values before the
+// logging, tracing and audit subsystems emit them. A Policy decides per key
+// whether a value is kept, dropped, hashed (so it can still be correlated
+// across log lines without being readable) or masked.
+//
+// Rules come from three places, in order of precedence:
+//
+//   - `redact:"drop|hash|mask|keep"` struct tags on values being logged
+//   - exact key rules and an optional allowlist set on the Policy
+//   - substring rules matched case-insensitively ("password" matches
+//     "new_password"), with defaults for passwords, tokens and emails
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Action is what a policy does with a value.
+type Action int
+
+// Actions.
+const (
+	Keep Action = iota
+	Drop
+	Hash
+	Mask
+)
+
+// Placeholders used for masked values.
+const (
+	Masked     = "[REDACTED]"
+	hashPrefix = "sha256:"
+)
+
+// ParseAction parses a struct tag value. Unknown values drop, to fail closed.
+func ParseAction(s string) Action {
+	switch s {
+	case "", "keep":
+		return Keep
+	case "hash":
+		return Hash
+	case "mask":
+		return Mask
+	default:
+		return Drop
+	}
+}
+
+// DefaultRules are the substring rules of NewPolicy.
+var DefaultRules = map[string]Action{
+	"password":      Drop,
+	"passwd":        Drop,
+	"secret":        Drop,
+	"token":         Drop,
+	"api_key":       Drop,
+	"apikey":        Drop,
+	"authorization": Drop,
+	"cookie":        Drop,
+	"card":          Mask,
+	"cvv":           Drop,
+	"ssn":           Mask,
+	"email":         Hash,
+	"phone":         Hash,
+}
+
+// Policy holds redaction rules. It is safe for concurrent use.
+type Policy struct {
+	mu        sync.RWMutex
+	contains  map[string]Action
+	exact     map[string]Action
+	allowlist map[string]bool
+	salt      []byte
+}
+
+// NewPolicy creates a policy with DefaultRules.
+func NewPolicy() *Policy {
+	p := &Policy{contains: make(map[string]Action), exact: make(map[string]Action)}
+	for k, a := range DefaultRules {
+		p.contains[k] = a
+	}
+	return p
+}
+
+// Default is used by the logging, tracing and audit packages unless they are
+// given another policy. Configure it in place (Default.SetSalt(...)) before
+// creating loggers and tracers.
+var Default = NewPolicy()
+
+// SetKey sets the action for an exact key (case-insensitive).
+func (p *Policy) SetKey(key string, action Action) *Policy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exact[strings.ToLower(key)] = action
+	return p
+}
+
+// SetContains sets the action for keys containing substr.
+func (p *Policy) SetContains(substr string, action Action) *Policy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.contains[strings.ToLower(substr)] = action
+	return p
+}
+
+// Allow restricts output to the given keys, at every nesting level: any
+// other key is dropped. Rules still apply to allowed keys, so an allowed
+// "email" is hashed.
+func (p *Policy) Allow(keys ...string) *Policy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.allowlist == nil {
+		p.allowlist = make(map[string]bool)
+	}
+	for _, k := range keys {
+		p.allowlist[strings.ToLower(k)] = true
+	}
+	return p
+}
+
+// SetSalt sets the HMAC key used by Hash. Without a salt, hashes of
+// low-entropy values such as emails can be reversed by brute force.
+func (p *Policy) SetSalt(salt []byte) *Policy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.salt = append([]byte(nil), salt...)
+	return p
+}
+
+// ActionFor returns the action for key.
+func (p *Policy) ActionFor(key string) Action {
+	if p == nil {
+		return Keep
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	lower := strings.ToLower(key)
+	if p.allowlist != nil && !p.allowlist[lower] {
+		return Drop
+	}
+	if a, ok := p.exact[lower]; ok {
+		return a
+	}
+	best, bestLen := Keep, 0
+	for substr, a := range p.contains {
+		// The longest match wins so "card_holder" rules beat "card".
+		if len(substr) > bestLen && strings.Contains(lower, substr) {
+			best, bestLen = a, len(substr)
+		}
+	}
+	return best
+}
+
+// Value applies the policy to a key/value pair. ok is false if the value
+// must be dropped. Structs (and pointers to structs) are converted to maps
+// honoring their `redact` tags; maps are redacted recursively.
+func (p *Policy) Value(key string, v any) (out any, ok bool) {
+	if p == nil {
+		return v, true
+	}
+	switch p.ActionFor(key) {
+	case Drop:
+		return nil, false
+	case Hash:
+		return p.Hash(v), true
+	case Mask:
+		return Masked, true
+	}
+	return p.walk(v), true
+}
+
+// Map returns a redacted copy of m.
+func (p *Policy) Map(m map[string]any) map[string]any {
+	if p == nil || m == nil {
+		return m
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if rv, ok := p.Value(k, v); ok {
+			out[k] = rv
+		}
+	}
+	return out
+}
+
+// Strings returns a redacted copy of a string map (span tags, headers).
+func (p *Policy) Strings(m map[string]string) map[string]string {
+	if p == nil || m == nil {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if rv, ok := p.Value(k, v); ok {
+			out[k] = rv.(string)
+		}
+	}
+	return out
+}
+
+// URL redacts query parameter values of a URL by parameter name, so
+// "/reset?token=abc&page=2" becomes "/reset?page=2".
+func (p *Policy) URL(raw string) string {
+	if p == nil {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	query := u.Query()
+	for key, values := range query {
+		kept := values[:0]
+		for _, v := range values {
+			if rv, ok := p.Value(key, v); ok {
+				kept = append(kept, rv.(string))
+			}
+		}
+		if len(kept) == 0 {
+			query.Del(key)
+		} else {
+			query[key] = kept
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Hash returns a stable keyed hash of v's string form: equal inputs give
+// equal outputs, so redacted values can still be correlated.
+func (p *Policy) Hash(v any) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case nil:
+		return ""
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	p.mu.RLock()
+	salt := p.salt
+	p.mu.RUnlock()
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(s))
+	return hashPrefix + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// walk redacts nested values: maps by key, structs by tag and key.
+func (p *Policy) walk(v any) any {
+	switch val := v.(type) {
+	case nil, string, bool, int, int64, float64, error:
+		return v
+	case map[string]any:
+		return p.Map(val)
+	case map[string]string:
+		return p.Strings(val)
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || !hasRedactTags(rv.Type()) {
+		return v
+	}
+
+	rt := rv.Type()
+	out := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := fieldName(f)
+		if name == "-" {
+			continue
+		}
+		fv := rv.Field(i).Interface()
+
+		tag, tagged := f.Tag.Lookup("redact")
+		if !tagged {
+			if rv, ok := p.Value(name, fv); ok {
+				out[name] = rv
+			}
+			continue
+		}
+		switch ParseAction(tag) {
+		case Keep:
+			out[name] = p.walk(fv)
+		case Hash:
+			out[name] = p.Hash(fv)
+		case Mask:
+			out[name] = Masked
+		}
+	}
+	return out
+}
+
+// typeTags caches whether a struct type has any redact tags.
+var typeTags sync.Map
+
+func hasRedactTags(t reflect.Type) bool {
+	if v, ok := typeTags.Load(t); ok {
+		return v.(bool)
+	}
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("redact"); ok {
+			found = true
+			break
+		}
+	}
+	typeTags.Store(t, found)
+	return found
+}
+
+// fieldName returns the json name of a field, or its Go name.
+func fieldName(f reflect.StructField) string {
+	if tag := f.Tag.Get("json"); tag != "" {
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
@@ -0,0 +1,112 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicy_DefaultRules(t *testing.T) {
+	p := NewPolicy()
+
+	cases := []struct {
+		key  string
+		want Action
+	}{
+		{"password", Drop},
+		{"New_Password", Drop},
+		{"access_token", Drop},
+		{"email", Hash},
+		{"card_number", Mask},
+		{"username", Keep},
+	}
+	for _, tc := range cases {
+		if got := p.ActionFor(tc.key); got != tc.want {
+			t.Errorf("ActionFor(%q) = %v, want %v", tc.key, got, tc.want)
+		}
+	}
+
+	if _, ok := p.Value("password", "hunter2"); ok {
+		t.Error("Expected password to be dropped")
+	}
+	h1, _ := p.Value("email", "ana@example.com")
+	h2, _ := p.Value("user_email", "ana@example.com")
+	if h1 != h2 || !strings.HasPrefix(h1.(string), hashPrefix) || strings.Contains(h1.(string), "ana") {
+		t.Errorf("Expected stable hashes, got %v and %v", h1, h2)
+	}
+
+	salted := NewPolicy().SetSalt([]byte("pepper"))
+	if salted.Hash("ana@example.com") == p.Hash("ana@example.com") {
+		t.Error("Expected the salt to change the hash")
+	}
+}
+
+func TestPolicy_RulesAndAllowlist(t *testing.T) {
+	p := NewPolicy().SetKey("notes", Mask).SetContains("card_holder", Keep)
+	if p.ActionFor("NOTES") != Mask {
+		t.Error("Expected exact key rule to apply case-insensitively")
+	}
+	if p.ActionFor("card_holder_name") != Keep {
+		t.Error("Expected the longest substring rule to win")
+	}
+
+	p.Allow("event", "email")
+	out := p.Map(map[string]any{"event": "login", "email": "a@b.c", "ip": "1.2.3.4"})
+	if _, ok := out["ip"]; ok || out["event"] != "login" || out["email"] == "a@b.c" {
+		t.Errorf("Unexpected allowlisted output: %v", out)
+	}
+}
+
+func TestPolicy_StructTags(t *testing.T) {
+	type card struct {
+		Number string `redact:"mask"`
+		Expiry string
+	}
+	type user struct {
+		ID       int    `json:"id"`
+		Email    string `json:"email" redact:"keep"`
+		Password string `json:"-"`
+		Phone    string `redact:"hash"`
+		Internal string `redact:"drop"`
+		Payment  *card
+		Token    string
+		internal string
+	}
+
+	v, ok := NewPolicy().Value("user", &user{
+		ID: 7, Email: "a@b.c", Password: "x", Phone: "555", Internal: "y",
+		Payment: &card{Number: "4242", Expiry: "12/30"}, Token: "t", internal: "z",
+	})
+	if !ok {
+		t.Fatal("Expected struct to be kept")
+	}
+	m := v.(map[string]any)
+
+	if m["id"] != 7 || m["email"] != "a@b.c" {
+		t.Errorf("Expected id and tagged email to be kept: %v", m)
+	}
+	for _, key := range []string{"Password", "-", "Internal", "Token", "internal"} {
+		if _, ok := m[key]; ok {
+			t.Errorf("Expected %s to be dropped: %v", key, m)
+		}
+	}
+	if !strings.HasPrefix(m["Phone"].(string), hashPrefix) {
+		t.Errorf("Expected phone to be hashed: %v", m["Phone"])
+	}
+	if c := m["Payment"].(map[string]any); c["Number"] != Masked || c["Expiry"] != "12/30" {
+		t.Errorf("Expected nested struct tags to apply: %v", c)
+	}
+}
+
+func TestPolicy_URL(t *testing.T) {
+	p := NewPolicy()
+	if got := p.URL("/reset?token=abc&page=2"); got != "/reset?page=2" {
+		t.Errorf("URL = %q", got)
+	}
+	if got := p.URL("/plain"); got != "/plain" {
+		t.Errorf("URL = %q", got)
+	}
+	var nilPolicy *Policy
+	if got, ok := nilPolicy.Value("password", "x"); !ok || got != "x" {
+		t.Error("Expected a nil policy to keep everything")
+	}
+}
@@ -0,0 +1,44 @@
+package router
+
+import "github.com/gabrielmiguelok/golivekit/pkg/transport"
+
+// BackpressureConfig bounds the messages waiting to be written to each
+// socket (see SetBackpressure).
+type BackpressureConfig struct {
+	// MaxQueueDepth is how many messages may wait for a slow client.
+	// Default: the transport's send buffer size.
+	MaxQueueDepth int
+
+	// OnOverflow decides what happens to a message that finds the queue of
+	// socketID full. nil coalesces diffs, keeping the latest content of
+	// each slot, and disconnects the socket when nothing can be merged.
+	// It runs on the render goroutine and must not send on the socket.
+	OnOverflow func(socketID string, overflow transport.QueueOverflow) transport.OverflowAction
+}
+
+// SetBackpressure configures the send queues of sockets connected from
+// now on. Renders never wait for a slow client: its pending diffs are
+// merged or it is disconnected, and it recovers its state on reconnect
+// when recovery is enabled.
+func (r *Router) SetBackpressure(config BackpressureConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.backpressure = config
+}
+
+// configureQueue applies the backpressure config to the transport config
+// of socketID.
+func (r *Router) configureQueue(config *transport.TransportConfig, socketID string) {
+	r.mu.RLock()
+	bp := r.backpressure
+	r.mu.RUnlock()
+
+	if bp.MaxQueueDepth > 0 {
+		config.MaxQueueDepth = bp.MaxQueueDepth
+	}
+	if bp.OnOverflow != nil {
+		config.OnOverflow = func(overflow transport.QueueOverflow) transport.OverflowAction {
+			return bp.OnOverflow(socketID, overflow)
+		}
+	}
+}
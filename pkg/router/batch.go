@@ -0,0 +1,100 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/limits"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// BatchEvent carries the user events a client fired within one animation
+// frame, in one message: {"events": [{"ref", "event", "payload"}, ...]}.
+// They are handled in order and answered with one diff.
+const BatchEvent = "lv:batch"
+
+// errNotBatchable rejects events that must be sent on their own.
+var errNotBatchable = errors.New("event cannot be batched")
+
+// handleBatch dispatches the events of a batch in order, then renders once.
+// An event that fails gets an error reply with its ref and does not stop
+// the others.
+func (r *Router) handleBatch(ctx context.Context, session *LiveViewSession, msg transport.Message) {
+	events, _ := msg.Payload["events"].([]any)
+
+	renderView := false
+	var children []core.LiveComponent
+	var replies []eventReply
+	for _, e := range events {
+		fields, _ := e.(map[string]any)
+		event := transport.Message{Topic: msg.Topic}
+		event.Ref, _ = fields["ref"].(string)
+		event.Event, _ = fields["event"].(string)
+		event.Payload, _ = fields["payload"].(map[string]any)
+
+		if !batchable(event.Event) {
+			r.sendError(session, event.Ref, msg.Topic, errNotBatchable)
+			continue
+		}
+		if !r.allowEvent(ctx, session) {
+			r.sendError(session, event.Ref, msg.Topic, limits.ErrRateLimitExceeded)
+			continue
+		}
+		child := eventTarget(session, event)
+		if err := r.dispatchEvent(ctx, session, child, event); err != nil {
+			r.sendEventError(ctx, session, event, err)
+			continue
+		}
+		if reply := session.Socket.TakeReply(); reply != nil {
+			replies = append(replies, eventReply{event, reply})
+		}
+		switch {
+		case child == nil:
+			renderView = true
+		case !containsComponent(children, child):
+			children = append(children, child)
+		}
+	}
+
+	// A render of the view includes its LiveComponents
+	if renderView {
+		r.renderAndSendDiff(ctx, session)
+	} else {
+		for _, child := range children {
+			r.renderLiveComponent(ctx, session, child)
+		}
+	}
+	if renderView || len(children) > 0 {
+		r.flushCookieSession(session)
+	}
+	for _, reply := range replies {
+		r.sendEventReply(session, reply.msg, reply.value)
+	}
+}
+
+// eventReply is the value a handler of a batch set with core.Reply, sent
+// once the batch is rendered.
+type eventReply struct {
+	msg   transport.Message
+	value any
+}
+
+// batchable reports whether event is a user event, which handleMessage
+// would dispatch to the component.
+func batchable(event string) bool {
+	switch event {
+	case "", "heartbeat", "phx_heartbeat", "phx_join", "phx_leave", core.ParamsEvent, core.VisibilityEvent, BatchEvent:
+		return false
+	}
+	return true
+}
+
+func containsComponent(list []core.LiveComponent, c core.LiveComponent) bool {
+	for _, item := range list {
+		if item == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package router
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestRouter_EventBatch(t *testing.T) {
+	var handled atomic.Int32
+	r := New()
+	r.Live("/", func() core.Component { return &countingView{handled: &handled} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.CloseNow()
+
+	wsjson.Write(ctx, ws, map[string]any{"ref": "0", "topic": "lv:t", "event": "phx_join", "payload": map[string]any{}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "4", "topic": "lv:t", "event": BatchEvent, "payload": map[string]any{
+		"events": []any{
+			map[string]any{"ref": "1", "event": "inc", "payload": map[string]any{}},
+			map[string]any{"ref": "2", "event": "phx_leave", "payload": map[string]any{}},
+			map[string]any{"ref": "3", "event": "inc", "payload": map[string]any{}},
+		},
+	}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "hb", "topic": "phoenix", "event": "heartbeat", "payload": map[string]any{}})
+
+	var diffs []map[string]any
+	var rejected []string
+	for {
+		var msg struct {
+			Ref     string         `json:"ref"`
+			Event   string         `json:"event"`
+			Payload map[string]any `json:"payload"`
+		}
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if msg.Ref == "hb" {
+			break
+		}
+		switch {
+		case msg.Event == "diff":
+			diffs = append(diffs, msg.Payload)
+		case msg.Event == "phx_reply" && msg.Payload["status"] == "error":
+			rejected = append(rejected, msg.Ref)
+		}
+	}
+
+	if n := handled.Load(); n != 2 {
+		t.Errorf("handled %d events, want 2", n)
+	}
+	if len(rejected) != 1 || rejected[0] != "2" {
+		t.Errorf("rejected %v, want [2]", rejected)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1: %v", len(diffs), diffs)
+	}
+	if s, _ := diffs[0]["s"].(map[string]any); s["n"] != "2" {
+		t.Errorf("diff = %v, want n=2", diffs[0])
+	}
+}
+
+// couponView replies to check_coupon with whether the code is valid.
+type couponView struct {
+	core.BaseComponent
+}
+
+func (v *couponView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if event == "check_coupon" {
+		core.Reply(ctx, map[string]any{"valid": payload["code"] == "SAVE10"})
+	}
+	return nil
+}
+
+func (v *couponView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<form></form>`)
+		return err
+	})
+}
+
+func TestRouter_EventReply(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &couponView{} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.CloseNow()
+
+	wsjson.Write(ctx, ws, map[string]any{"ref": "0", "topic": "lv:t", "event": "phx_join", "payload": map[string]any{}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:t", "event": "check_coupon", "payload": map[string]any{"code": "SAVE10"}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "5", "topic": "lv:t", "event": BatchEvent, "payload": map[string]any{
+		"events": []any{
+			map[string]any{"ref": "2", "event": "check_coupon", "payload": map[string]any{"code": "NOPE"}},
+			map[string]any{"ref": "3", "event": "other", "payload": map[string]any{}},
+		},
+	}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "hb", "topic": "phoenix", "event": "heartbeat", "payload": map[string]any{}})
+
+	replies := map[string]any{}
+	for {
+		var msg struct {
+			Ref     string         `json:"ref"`
+			Event   string         `json:"event"`
+			Payload map[string]any `json:"payload"`
+		}
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if msg.Ref == "hb" {
+			break
+		}
+		if msg.Event == "phx_reply" && msg.Ref != "0" {
+			response, _ := msg.Payload["response"].(map[string]any)
+			replies[msg.Ref] = response["reply"]
+		}
+	}
+
+	if reply, _ := replies["1"].(map[string]any); reply["valid"] != true {
+		t.Errorf("reply to 1 = %v, want valid", replies["1"])
+	}
+	if reply, _ := replies["2"].(map[string]any); reply["valid"] != false {
+		t.Errorf("reply to 2 = %v, want not valid", replies["2"])
+	}
+	if _, ok := replies["3"]; ok {
+		t.Errorf("event 3 set no reply but got %v", replies["3"])
+	}
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+	"github.com/gabrielmiguelok/golivekit/pkg/metrics"
+)
+
+// DefaultDiffBudget sends a full render once the estimated diff is larger
+// than the full render itself.
+const DefaultDiffBudget = 1.0
+
+// SetDiffBudget sets the full-render fallback: when the estimated size of a
+// slot diff exceeds ratio times the size of the full render, the full
+// render is sent instead. This bounds pathological updates (hundreds of
+// changed or nested slots) at roughly the page size. A ratio of 0 always
+// sends diffs.
+func (r *Router) SetDiffBudget(ratio float64) {
+	r.mu.Lock()
+	r.diffBudget = ratio
+	r.mu.Unlock()
+}
+
+// SetFullRenderDelta turns off (or back on) sending full renders as deltas
+// of the previous full render. Deltas are on by default; each session then
+// keeps the last full render it sent.
+func (r *Router) SetFullRenderDelta(on bool) {
+	r.mu.Lock()
+	r.fullDelta = on
+	r.mu.Unlock()
+}
+
+// trackFullBase records html as the last full render the client holds.
+func (r *Router) trackFullBase(session *LiveViewSession, html string) {
+	r.mu.RLock()
+	on := r.fullDelta
+	r.mu.RUnlock()
+	if on {
+		session.swapFullBase(html)
+	}
+}
+
+// deltaFull replaces payload's full render with its delta against the
+// previous full render when the delta is smaller.
+func (r *Router) deltaFull(session *LiveViewSession, payload *core.DiffPayload) {
+	r.mu.RLock()
+	on := r.fullDelta
+	r.mu.RUnlock()
+	if !on {
+		return
+	}
+	prev := session.swapFullBase(payload.Full)
+	if prev == "" {
+		return
+	}
+
+	delta := diff.FullDelta(prev, payload.Full)
+	size := core.EncodedSize(delta.Ops)
+	if size >= len(payload.Full) {
+		return
+	}
+	metrics.RecordDiffDecision("delta", size, len(payload.Full))
+	payload.FullDelta = delta
+	payload.Full = ""
+}
+
+// applyDiffBudget replaces payload's slots and list operations with the full
+// render when that is smaller, and records the decision.
+func (r *Router) applyDiffBudget(payload *core.DiffPayload, html string) {
+	if payload.Full != "" || payload.IsEmpty() {
+		return
+	}
+	r.mu.RLock()
+	ratio := r.diffBudget
+	r.mu.RUnlock()
+
+	diffSize := estimateDiffSize(payload)
+	if ratio <= 0 || float64(diffSize) <= ratio*float64(len(html)) {
+		metrics.RecordDiffDecision("diff", diffSize, len(html))
+		return
+	}
+
+	payload.Slots = nil
+	payload.Splices = nil
+	payload.HTMLSlots = nil
+	payload.Trees = nil
+	payload.ListOps = nil
+	payload.Full = html
+	metrics.RecordDiffDecision("full", diffSize, len(html))
+}
+
+// estimateDiffSize estimates the encoded size of a diff payload: contents
+// plus keys and per-entry JSON overhead.
+func estimateDiffSize(payload *core.DiffPayload) int {
+	size := 0
+	for id, content := range payload.Slots {
+		size += len(id) + len(content) + 6
+	}
+	for id, content := range payload.HTMLSlots {
+		size += len(id) + len(content) + 6
+	}
+	for id, splices := range payload.Splices {
+		size += len(id) + spliceSize(splices) + 6
+	}
+	for id, tree := range payload.Trees {
+		size += len(id) + core.EncodedSize(tree) + 6
+	}
+	for id, ops := range payload.ListOps {
+		size += len(id) + 6
+		for _, op := range ops {
+			size += len(op.Key) + len(op.Content) + 24
+		}
+	}
+	return size
+}
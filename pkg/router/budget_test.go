@@ -0,0 +1,80 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+)
+
+func TestRouter_DiffBudget(t *testing.T) {
+	r := New()
+	html := `<div data-slot="a">` + strings.Repeat("x", 100) + `</div>`
+
+	small := &core.DiffPayload{Slots: map[string]string{"a": "y"}}
+	r.applyDiffBudget(small, html)
+	if small.Full != "" || small.Slots["a"] != "y" {
+		t.Errorf("Expected a small diff to be kept: %+v", small)
+	}
+
+	// Hundreds of slot updates larger than the page fall back to a full render.
+	big := &core.DiffPayload{Slots: map[string]string{}, HTMLSlots: map[string]string{}}
+	for i := 0; i < 300; i++ {
+		big.Slots[strings.Repeat("s", i%7+1)+string(rune('a'+i%26))+string(rune('a'+i/26))] = "z"
+	}
+	r.applyDiffBudget(big, html)
+	if big.Full != html || big.Slots != nil || big.HTMLSlots != nil {
+		t.Errorf("Expected full render fallback, got %d slots", len(big.Slots))
+	}
+
+	r.SetDiffBudget(0)
+	big = &core.DiffPayload{HTMLSlots: map[string]string{"a": strings.Repeat("w", 500)}}
+	r.applyDiffBudget(big, html)
+	if big.Full != "" {
+		t.Error("Expected a zero budget to always send diffs")
+	}
+}
+
+func TestRouter_FullRenderDelta(t *testing.T) {
+	r := New()
+	session := NewLiveViewSession("s", NewMockComponent(), nil, nil)
+	page := func(title string) string {
+		return "<main><h1>" + title + "</h1><p>" + strings.Repeat("Ünïcode text 😀 ", 40) + "</p></main>"
+	}
+
+	// Without slots every render is full; the first has no base
+	first := r.buildDiffPayload(context.Background(), session, session.Component, page("Uno"), nil, nil, false, nil)
+	if first.Full != page("Uno") || first.FullDelta != nil {
+		t.Fatalf("Expected the first render whole, got %+v", first)
+	}
+
+	next := r.buildDiffPayload(context.Background(), session, session.Component, page("Dos ✓"), nil, nil, false, nil)
+	if next.Full != "" || next.FullDelta == nil {
+		t.Fatalf("Expected a delta, got %+v", next)
+	}
+	if size := core.EncodedSize(next.FullDelta.Ops); size > len(page("Dos ✓"))/4 {
+		t.Errorf("Expected a small delta, got %d bytes", size)
+	}
+
+	// The client decodes offsets as float64
+	data, _ := json.Marshal(next.FullDelta)
+	var decoded core.FullDelta
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := diff.ApplyFullDelta(page("Uno"), &decoded); err != nil || got != page("Dos ✓") {
+		t.Errorf("ApplyFullDelta = %q, %v", got, err)
+	}
+	if _, err := diff.ApplyFullDelta(page("Tres"), &decoded); err == nil {
+		t.Error("Expected a delta of another base to fail")
+	}
+
+	r.SetFullRenderDelta(false)
+	off := r.buildDiffPayload(context.Background(), session, session.Component, page("Tres"), nil, nil, false, nil)
+	if off.Full != page("Tres") || off.FullDelta != nil {
+		t.Errorf("Expected deltas off, got %+v", off)
+	}
+}
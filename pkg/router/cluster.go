@@ -0,0 +1,361 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/state"
+)
+
+// TakeoverEvent tells a client that its session moved to another
+// connection, so it must not reconnect. Its payload's "reason" is one of
+// the Takeover reasons.
+const TakeoverEvent = "lv:takeover"
+
+// Reasons of a TakeoverEvent.
+const (
+	// TakeoverReconnect: the same page rejoined on another connection.
+	TakeoverReconnect = "reconnect"
+
+	// TakeoverTab: the user opened a single-session route in another tab
+	// (see WithSingleSession).
+	TakeoverTab = "tab"
+)
+
+// Defaults of ClusterConfig.
+const (
+	DefaultClusterHeartbeat = 5 * time.Second
+	DefaultHandoffTimeout   = 2 * time.Second
+	DefaultClusterKeyPrefix = "golivekit:cluster:"
+)
+
+// ownerTTL bounds how long the owner of a session is remembered when its
+// node dies without releasing it.
+const ownerTTL = 24 * time.Hour
+
+// ClusterConfig configures a Cluster.
+type ClusterConfig struct {
+	// NodeID names this node in the registry. Default: a random ID.
+	NodeID string
+
+	// Heartbeat is how often the node renews its registration. A node
+	// that misses three heartbeats is considered gone. Default:
+	// DefaultClusterHeartbeat.
+	Heartbeat time.Duration
+
+	// HandoffTimeout bounds how long a node waits for another one to hand
+	// over a session. Default: DefaultHandoffTimeout.
+	HandoffTimeout time.Duration
+
+	// KeyPrefix prefixes the keys of the cluster in the store. Default:
+	// DefaultClusterKeyPrefix.
+	KeyPrefix string
+}
+
+// ClusterNode is a node in the registry.
+type ClusterNode struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"started_at"`
+	SeenAt    time.Time `json:"seen_at"`
+	Sessions  int       `json:"sessions"`
+}
+
+// Cluster lets the nodes of a deployment share live sessions, so a load
+// balancer without sticky sessions can send a reconnecting client to any
+// node:
+//
+//	cluster := router.NewCluster(store, router.ClusterConfig{})
+//	r.SetPubSub(redisPubSub) // shared by every node
+//	r.SetCluster(cluster)
+//	go cluster.Run(ctx)
+//
+// The nodes share a state.Store, which holds the node registry, the owner
+// of each live session and the snapshots of disconnected ones, and the
+// router's pubsub, which carries handoff requests.
+//
+// When a session's connection drops, its snapshot (see core.Snapshotter)
+// is stored instead of kept in memory. Whichever node the client rejoins
+// adopts it. If the client rejoins while its old connection is still open,
+// as when a network switch leaves a half-open socket, the adopting node
+// asks the owner to hand the session over: the owner snapshots it, sends
+// the old client a TakeoverEvent and terminates the component with
+// core.TerminateTakeover.
+type Cluster struct {
+	store  state.Store
+	config ClusterConfig
+	router *Router
+	start  time.Time
+}
+
+// NewCluster creates a cluster over store.
+func NewCluster(store state.Store, config ClusterConfig) *Cluster {
+	if config.NodeID == "" {
+		config.NodeID = generateSessionID()
+	}
+	if config.Heartbeat <= 0 {
+		config.Heartbeat = DefaultClusterHeartbeat
+	}
+	if config.HandoffTimeout <= 0 {
+		config.HandoffTimeout = DefaultHandoffTimeout
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultClusterKeyPrefix
+	}
+	return &Cluster{store: store, config: config, start: time.Now()}
+}
+
+// SetCluster makes the router a node of cluster. Call cluster.Run to join.
+func (r *Router) SetCluster(c *Cluster) {
+	r.mu.Lock()
+	r.cluster = c
+	c.router = r
+	r.mu.Unlock()
+	r.sessionManager.setCluster(c)
+}
+
+// NodeID returns the ID of this node.
+func (c *Cluster) NodeID() string {
+	return c.config.NodeID
+}
+
+func (c *Cluster) key(kind, id string) string {
+	return c.config.KeyPrefix + kind + ":" + id
+}
+
+// Run registers the node and listens for handoff requests until ctx is
+// done, then leaves the registry. It returns the error of the first
+// registration; later heartbeats retry on failure.
+func (c *Cluster) Run(ctx context.Context) error {
+	r := c.router
+	if r == nil {
+		return errors.New("router: cluster is not set on a router")
+	}
+	sub, err := r.PubSub().Subscribe(c.key("node", c.NodeID()), func(msg []byte) {
+		var req handoffRequest
+		if json.Unmarshal(msg, &req) == nil {
+			r.sessionManager.takeOver(req.Token, req.Reason)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	if err := c.heartbeat(ctx); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(c.config.Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.heartbeat(ctx)
+		case <-ctx.Done():
+			c.store.Delete(context.Background(), c.key("node", c.NodeID()))
+			return nil
+		}
+	}
+}
+
+// heartbeat renews the node's registration.
+func (c *Cluster) heartbeat(ctx context.Context) error {
+	node := ClusterNode{
+		ID:        c.NodeID(),
+		StartedAt: c.start,
+		SeenAt:    time.Now(),
+		Sessions:  c.router.sessionManager.Count(),
+	}
+	data, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	return c.store.Set(ctx, c.key("node", node.ID), data, 3*c.config.Heartbeat)
+}
+
+// Nodes returns the nodes in the registry.
+func (c *Cluster) Nodes(ctx context.Context) ([]ClusterNode, error) {
+	keys, err := c.store.Keys(ctx, c.key("node", "*"))
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]ClusterNode, 0, len(keys))
+	for _, key := range keys {
+		data, err := c.store.Get(ctx, key)
+		if err != nil {
+			continue // left since Keys
+		}
+		var node ClusterNode
+		if json.Unmarshal(data, &node) == nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// clusterSnapshot is a parkedSession in the store.
+type clusterSnapshot struct {
+	Component string          `json:"component"`
+	Path      string          `json:"path"`
+	Owner     string          `json:"owner"`
+	State     json.RawMessage `json:"state"`
+}
+
+// claim records this node as the owner of the session with token.
+func (c *Cluster) claim(token string) {
+	c.store.Set(context.Background(), c.key("owner", token), []byte(c.NodeID()), ownerTTL)
+}
+
+// release forgets the owner of the session with token.
+func (c *Cluster) release(token string) {
+	c.store.Delete(context.Background(), c.key("owner", token))
+}
+
+// park stores the snapshot of a disconnected session for ttl.
+func (c *Cluster) park(token string, p parkedSession, ttl time.Duration) bool {
+	data, err := json.Marshal(clusterSnapshot{Component: p.component, Path: p.path, Owner: p.owner, State: p.state})
+	if err != nil {
+		return false
+	}
+	return c.store.Set(context.Background(), c.key("session", token), data, ttl) == nil
+}
+
+// takeParked removes and returns the snapshot stored under token.
+func (c *Cluster) takeParked(ctx context.Context, token string) (parkedSession, bool) {
+	key := c.key("session", token)
+	data, err := c.store.Get(ctx, key)
+	if err != nil {
+		return parkedSession{}, false
+	}
+	c.store.Delete(ctx, key)
+
+	var snap clusterSnapshot
+	if json.Unmarshal(data, &snap) != nil {
+		return parkedSession{}, false
+	}
+	return parkedSession{
+		component: snap.Component,
+		path:      snap.Path,
+		owner:     snap.Owner,
+		state:     snap.State,
+		expires:   time.Now().Add(c.config.HandoffTimeout), // the store expires it
+	}, true
+}
+
+// handoffRequest asks the node owning a session to hand it over.
+type handoffRequest struct {
+	Token  string `json:"token"`
+	Reason string `json:"reason"`
+}
+
+// adopt returns the snapshot of the session with token, asking its owner
+// to hand it over, with reason, if it is still connected somewhere. Unless
+// wait is set, it only asks.
+func (c *Cluster) adopt(token, reason string, wait bool) (parkedSession, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.HandoffTimeout)
+	defer cancel()
+	if p, ok := c.takeParked(ctx, token); ok {
+		return p, true
+	}
+
+	owner, err := c.store.Get(ctx, c.key("owner", token))
+	if err != nil {
+		return parkedSession{}, false
+	}
+	switch node := string(owner); {
+	case node == c.NodeID():
+		c.router.sessionManager.takeOver(token, reason)
+	default:
+		if alive, _ := c.store.Exists(ctx, c.key("node", node)); !alive {
+			c.release(token) // its node is gone, and the state with it
+			return parkedSession{}, false
+		}
+		req, _ := json.Marshal(handoffRequest{Token: token, Reason: reason})
+		if c.router.PubSub().Publish(c.key("node", node), req) != nil {
+			return parkedSession{}, false
+		}
+	}
+	if !wait {
+		return parkedSession{}, false
+	}
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if p, ok := c.takeParked(ctx, token); ok {
+				return p, true
+			}
+		case <-ctx.Done():
+			return parkedSession{}, false
+		}
+	}
+}
+
+// takeoverNotice asks a session's message loop to hand the session over.
+type takeoverNotice struct {
+	reason string
+}
+
+// handOff ends a session taken over by another connection, on its message
+// loop: handleDisconnect stores its snapshot for the adopting session.
+func (r *Router) handOff(session *LiveViewSession, reason string) {
+	session.takenOver.Store(true)
+	if session.Socket.Push(TakeoverEvent, map[string]any{"reason": reason}) == nil {
+		flush(session.Transport, 100*time.Millisecond)
+	}
+	r.handleDisconnect(session)
+}
+
+// flush waits up to timeout for the messages queued on t to be written, so
+// closing it does not drop them.
+func flush(t any, timeout time.Duration) {
+	q, ok := t.(interface{ QueueDepth() int })
+	if !ok {
+		return
+	}
+	deadline := time.Now().Add(timeout)
+	for q.QueueDepth() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(5 * time.Millisecond) // the last one may still be writing
+}
+
+// claimSession records this node as the owner of a mounted session.
+func (r *Router) claimSession(session *LiveViewSession) {
+	r.mu.RLock()
+	c := r.cluster
+	r.mu.RUnlock()
+	if c != nil {
+		c.claim(session.Token)
+	}
+}
+
+// releaseSession forgets the owner of a session that ended.
+func (r *Router) releaseSession(session *LiveViewSession) {
+	r.mu.RLock()
+	c := r.cluster
+	r.mu.RUnlock()
+	if !session.IsMounted() {
+		return
+	}
+	r.sessionManager.releaseSingle(session)
+	if c != nil {
+		c.release(session.Token)
+	}
+}
+
+// terminateReason is the reason a session's component is terminated when
+// it disconnects.
+func terminateReason(session *LiveViewSession) core.TerminateReason {
+	if session.takenOver.Load() {
+		return core.TerminateTakeover
+	}
+	if session.reaped.Load() {
+		return core.TerminateTimeout
+	}
+	return core.TerminateShutdown
+}
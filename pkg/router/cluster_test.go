@@ -0,0 +1,150 @@
+package router
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+	"github.com/gabrielmiguelok/golivekit/pkg/state"
+)
+
+// clusterCounter is a snapCounter that reports why it was terminated.
+type clusterCounter struct {
+	snapCounter
+	reasons chan core.TerminateReason
+}
+
+func (c *clusterCounter) Terminate(ctx context.Context, reason core.TerminateReason) error {
+	c.reasons <- reason
+	return nil
+}
+
+type testNode struct {
+	router *Router
+	server *httptest.Server
+}
+
+// startCluster starts n nodes sharing a store and a pubsub.
+func startCluster(t *testing.T, ctx context.Context, n int, reasons chan core.TerminateReason) []testNode {
+	t.Helper()
+	store := state.NewMemoryStore()
+	ps := pubsub.NewMemoryPubSub()
+	t.Cleanup(func() { store.Close(); ps.Close() })
+
+	var nodes []testNode
+	var cluster *Cluster
+	for i := 0; i < n; i++ {
+		r := New()
+		r.SetPubSub(ps)
+		r.Live("/", func() core.Component { return &clusterCounter{reasons: reasons} })
+		cluster = NewCluster(store, ClusterConfig{HandoffTimeout: time.Second})
+		r.SetCluster(cluster)
+		go cluster.Run(ctx)
+
+		server := httptest.NewServer(r)
+		t.Cleanup(server.Close)
+		nodes = append(nodes, testNode{router: r, server: server})
+	}
+	for {
+		if registered, _ := cluster.Nodes(ctx); len(registered) == n {
+			return nodes
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("nodes did not register")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// joinNode connects to a node with a session token and increments the
+// counter, returning the socket, its session token and the count.
+func joinNode(t *testing.T, ctx context.Context, node testNode, token string) (*websocket.Conn, string, any) {
+	t.Helper()
+	ws, _, err := websocket.Dial(ctx, "ws"+node.server.URL[4:]+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := map[string]any{}
+	if token != "" {
+		payload["session"] = token
+	}
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": payload})
+	var reply struct {
+		Payload struct {
+			Response map[string]any `json:"response"`
+		} `json:"payload"`
+	}
+	if err := wsjson.Read(ctx, ws, &reply); err != nil {
+		t.Fatal(err)
+	}
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+	var diff struct {
+		Payload struct {
+			S map[string]any `json:"s"`
+		} `json:"payload"`
+	}
+	wsjson.Read(ctx, ws, &diff)
+	session, _ := reply.Payload.Response["session"].(string)
+	return ws, session, diff.Payload.S["n"]
+}
+
+func TestCluster_AdoptsDroppedSession(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	nodes := startCluster(t, ctx, 2, make(chan core.TerminateReason, 4))
+
+	ws, token, _ := joinNode(t, ctx, nodes[0], "")
+	ws.CloseNow()
+	for nodes[0].router.SessionManager().Count() > 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("session still open after the connection dropped")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// The client comes back through the load balancer to the other node
+	ws, _, n := joinNode(t, ctx, nodes[1], token)
+	defer ws.CloseNow()
+	if n != "2" {
+		t.Errorf("Expected the adopted count to continue at 2, got %v", n)
+	}
+}
+
+func TestCluster_TakesOverOpenSession(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reasons := make(chan core.TerminateReason, 4)
+	nodes := startCluster(t, ctx, 2, reasons)
+
+	old, token, _ := joinNode(t, ctx, nodes[0], "")
+	defer old.CloseNow()
+
+	// The client rejoins elsewhere while its old socket is still open
+	ws, _, n := joinNode(t, ctx, nodes[1], token)
+	defer ws.CloseNow()
+	if n != "2" {
+		t.Errorf("Expected the handed-over count to continue at 2, got %v", n)
+	}
+
+	var msg struct {
+		Event string `json:"event"`
+	}
+	if err := wsjson.Read(ctx, old, &msg); err != nil || msg.Event != TakeoverEvent {
+		t.Errorf("Expected %s on the old socket, got %+v (%v)", TakeoverEvent, msg, err)
+	}
+	select {
+	case reason := <-reasons:
+		if reason != core.TerminateTakeover {
+			t.Errorf("Expected the old component terminated with takeover, got %v", reason)
+		}
+	case <-ctx.Done():
+		t.Fatal("old component not terminated")
+	}
+}
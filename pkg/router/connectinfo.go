@@ -0,0 +1,62 @@
+package router
+
+import (
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Caps on what a client reports on join, which is untrusted input.
+const (
+	maxJoinFeatures = 32
+	maxJoinString   = 64
+	maxJoinPixels   = 1 << 15
+)
+
+// setJoinInfo completes the socket's connection metadata with what the
+// client reports when it joins: its timezone (already validated), the
+// browser features it supports and, under "client", its viewport and
+// preferences.
+func setJoinInfo(socket *core.Socket, tz string, payload map[string]any) {
+	info := socket.ConnectInfo()
+	info.JoinedAt = time.Now()
+	info.Timezone = tz
+	info.Features = make(map[string]bool)
+	list, _ := payload["features"].([]any)
+	for _, f := range list {
+		if name, ok := f.(string); ok && len(name) <= maxJoinString && len(info.Features) < maxJoinFeatures {
+			info.Features[name] = true
+		}
+	}
+
+	client, _ := payload["client"].(map[string]any)
+	info.ViewportWidth = joinPixels(client["viewport_width"])
+	info.ViewportHeight = joinPixels(client["viewport_height"])
+	info.ColorScheme = joinString(client["color_scheme"])
+	if info.ColorScheme != "dark" {
+		info.ColorScheme = "light"
+	}
+	info.ReducedMotion, _ = client["reduced_motion"].(bool)
+	info.Locale = joinString(client["locale"])
+	info.Connection = joinString(client["connection"])
+	socket.SetConnectInfo(info)
+}
+
+// joinString returns v if it is a string of a sensible length.
+func joinString(v any) string {
+	s, _ := v.(string)
+	if len(s) > maxJoinString {
+		return ""
+	}
+	return s
+}
+
+// joinPixels returns v as a size in CSS pixels, 0 if it is not one. JSON
+// numbers arrive as float64.
+func joinPixels(v any) int {
+	f, _ := v.(float64)
+	if f < 0 || f > maxJoinPixels {
+		return 0
+	}
+	return int(f)
+}
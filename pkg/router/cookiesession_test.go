@@ -0,0 +1,131 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// themeView shows the theme of the cookie session and changes it on
+// events.
+type themeView struct {
+	core.BaseComponent
+	theme string
+}
+
+func (v *themeView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	v.theme = session.GetString("theme")
+	return nil
+}
+
+func (v *themeView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	v.theme = event
+	security.CookieSessionFromContext(ctx).Set("theme", event)
+	return nil
+}
+
+func (v *themeView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<div data-slot="theme">theme:%s</div>`, v.theme)
+		return err
+	})
+}
+
+func TestRouter_FlushesCookieSessionWrittenOverSocket(t *testing.T) {
+	key, _ := secrets.Generate("k1")
+	keys, _ := secrets.NewKeyring(key)
+	sessions, err := security.NewCookieSessionStore(security.CookieSessionConfig{Keys: keys, Encrypt: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New()
+	r.Use(sessions.Middleware())
+	r.Live("/", func() core.Component { return &themeView{} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	// get loads the page with cookie and returns the session cookie sent
+	get := func(cookie *http.Cookie) (string, *http.Cookie) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		for _, c := range resp.Cookies() {
+			if c.Name == "_golive_session" {
+				return string(body), c
+			}
+		}
+		return string(body), nil
+	}
+	_, cookie := get(nil)
+	if cookie == nil {
+		t.Fatal("first page did not start a session")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", &websocket.DialOptions{
+		HTTPHeader: http.Header{"Cookie": {cookie.String()}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.CloseNow()
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:t", "event": "phx_join", "payload": map[string]any{}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:t", "event": "dark", "payload": map[string]any{}})
+
+	var token, url string
+	for token == "" {
+		var msg struct {
+			Event   string         `json:"event"`
+			Payload map[string]any `json:"payload"`
+		}
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("no %s event: %v", security.CookieSessionEvent, err)
+		}
+		if msg.Event == security.CookieSessionEvent {
+			token, _ = msg.Payload["token"].(string)
+			url, _ = msg.Payload["url"].(string)
+		}
+	}
+
+	// The client posts the session to the flush path
+	req, _ := http.NewRequest(http.MethodPost, server.URL+url, strings.NewReader(token))
+	req.AddCookie(cookie)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	var updated *http.Cookie
+	for _, c := range resp.Cookies() {
+		if c.Name == "_golive_session" {
+			updated = c
+		}
+	}
+	if resp.StatusCode != http.StatusNoContent || updated == nil {
+		t.Fatalf("flush: status %d, cookie %v", resp.StatusCode, updated)
+	}
+
+	// The next page load mounts with the theme set over the socket
+	if body, _ := get(updated); !strings.Contains(body, "theme:dark") {
+		t.Errorf("page after the flush = %q, want theme:dark", body)
+	}
+}
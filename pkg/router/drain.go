@@ -0,0 +1,209 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/recovery"
+	"github.com/gabrielmiguelok/golivekit/pkg/shutdown"
+)
+
+// ReconnectEvent tells a client to reconnect through the load balancer:
+// {"reason": "deploy", "version": "...", "min": ms, "max": ms, "token": "..."},
+// with reason "shutdown" when the instance stops (see Shutdown).
+// The client waits a random delay between min and max, reconnects and sends
+// token in its join so the new instance can restore the session.
+const ReconnectEvent = "lv:reconnect"
+
+// DefaultDrainMaxDelay spreads reconnects when DrainConfig.MaxDelay is unset.
+const DefaultDrainMaxDelay = 5 * time.Second
+
+// DrainConfig configures Drain.
+type DrainConfig struct {
+	// Version is the release clients are moving to, reported to them.
+	Version string
+
+	// MinDelay and MaxDelay bound the jittered delay before clients
+	// reconnect, spreading the reconnect wave over the new instances.
+	// Default: 0 to DefaultDrainMaxDelay.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+// SetRecovery sets the store used to carry sessions across instances.
+// Components implementing core.Snapshotter are saved when the router drains
+// and restored when their client rejoins with the recovery token.
+// Instances of one deployment must share the manager's store and secret.
+func (r *Router) SetRecovery(m *recovery.RecoveryManager) {
+	r.mu.Lock()
+	r.recovery = m
+	r.mu.Unlock()
+}
+
+// Draining reports whether Drain or Shutdown was called. Use it to fail
+// readiness probes so the load balancer stops sending new visitors.
+func (r *Router) Draining() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.draining
+}
+
+// Drain prepares the instance to go away during a rolling deploy: new
+// sockets are refused, the state of every live session is saved, and each
+// client is told to reconnect to the new version with a jittered delay.
+// Drain returns once all clients are gone, or with ctx's error if some are
+// still connected when ctx is done.
+func (r *Router) Drain(ctx context.Context, config DrainConfig) error {
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultDrainMaxDelay
+	}
+	if config.MinDelay < 0 || config.MinDelay > config.MaxDelay {
+		config.MinDelay = 0
+	}
+
+	notice := drainNotice{config: config, reason: "deploy"}
+	r.mu.Lock()
+	r.draining = true
+	r.drainNotice = notice
+	r.mu.Unlock()
+
+	// Sessions are snapshotted on their own message loop, so the component
+	// is never read while it handles an event.
+	for _, session := range r.sessionManager.All() {
+		if session.Socket == nil {
+			continue
+		}
+		if session.Socket.SendInfo(notice) != nil {
+			session.Socket.Push(ReconnectEvent, notice.payload(""))
+		}
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for r.sessionManager.Count() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// DrainHook returns a shutdown hook that drains the router before the HTTP
+// server stops.
+func (r *Router) DrainHook(config DrainConfig) shutdown.Hook {
+	return shutdown.Hook{
+		Name:     "router-drain",
+		Priority: shutdown.PriorityFirst,
+		Fn: func(ctx context.Context) error {
+			return r.Drain(ctx, config)
+		},
+	}
+}
+
+// drainNotice asks a session's message loop to save the session and send
+// the client a ReconnectEvent. With a closing context (see Shutdown), the
+// session is then closed, its component terminated with that context.
+type drainNotice struct {
+	config  DrainConfig
+	reason  string
+	closing context.Context
+}
+
+func (n drainNotice) payload(token string) map[string]any {
+	payload := map[string]any{
+		"reason":  n.reason,
+		"version": n.config.Version,
+		"min":     n.config.MinDelay.Milliseconds(),
+		"max":     n.config.MaxDelay.Milliseconds(),
+	}
+	if token != "" {
+		payload["token"] = token
+	}
+	return payload
+}
+
+// sendReconnect handles a drainNotice on the session's message loop.
+func (r *Router) sendReconnect(ctx context.Context, session *LiveViewSession, notice drainNotice) {
+	err := session.Socket.Push(ReconnectEvent, notice.payload(r.saveSession(ctx, session)))
+	if notice.closing == nil {
+		return
+	}
+	if err == nil {
+		flush(session.Transport, 100*time.Millisecond)
+	}
+	r.disconnect(notice.closing, session)
+}
+
+// refuseJoin moves a client that joins while the router drains or shuts
+// down to another instance.
+func (r *Router) refuseJoin(session *LiveViewSession) bool {
+	r.mu.RLock()
+	draining, notice := r.draining, r.drainNotice
+	r.mu.RUnlock()
+	if !draining {
+		return false
+	}
+	session.Socket.Push(ReconnectEvent, notice.payload(""))
+	return true
+}
+
+// refuseDraining rejects new sockets while draining, so clients retry
+// through the load balancer.
+func (r *Router) refuseDraining(w http.ResponseWriter) bool {
+	if !r.Draining() {
+		return false
+	}
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, "draining", http.StatusServiceUnavailable)
+	return true
+}
+
+// saveSession stores the session's snapshot and returns its recovery
+// token, or "" if the session cannot be recovered.
+func (r *Router) saveSession(ctx context.Context, session *LiveViewSession) string {
+	r.mu.RLock()
+	m := r.recovery
+	r.mu.RUnlock()
+	snap, ok := session.Component.(core.Snapshotter)
+	if m == nil || !ok || !session.IsMounted() {
+		return ""
+	}
+
+	state := &recovery.ComponentState{
+		ComponentName: session.Component.Name(),
+		Assigns:       snap.Snapshot(),
+		Version:       session.Version,
+		CreatedAt:     time.Now().Unix(),
+	}
+	if err := m.Save(ctx, session.SocketID, state); err != nil {
+		return ""
+	}
+	token, err := m.GenerateToken(session.SocketID, state)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// restoreSession applies a saved snapshot to a freshly mounted component.
+// Invalid or expired tokens are ignored: the component keeps its mounted
+// state.
+func (r *Router) restoreSession(ctx context.Context, session *LiveViewSession, token string) {
+	r.mu.RLock()
+	m := r.recovery
+	r.mu.RUnlock()
+	snap, ok := session.Component.(core.Snapshotter)
+	if m == nil || !ok {
+		return
+	}
+
+	state, err := m.Restore(ctx, token)
+	if err != nil || state.ComponentName != session.Component.Name() {
+		return
+	}
+	snap.Restore(state.Assigns)
+}
@@ -0,0 +1,181 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/recovery"
+)
+
+// snapCounter is a loopCounter that survives reconnects.
+type snapCounter struct {
+	loopCounter
+}
+
+func (c *snapCounter) Name() string { return "snap-counter" }
+
+func (c *snapCounter) Snapshot() map[string]any {
+	return map[string]any{"count": c.count}
+}
+
+func (c *snapCounter) Restore(state map[string]any) error {
+	n, _ := state["count"].(float64)
+	c.count = int(n)
+	return nil
+}
+
+func TestRouter_DrainAndRestore(t *testing.T) {
+	manager := recovery.NewRecoveryManager(nil)
+	newRouter := func() *Router {
+		r := New()
+		r.SetRecovery(manager)
+		r.Live("/", func() core.Component { return &snapCounter{} })
+		return r
+	}
+	old, next := newRouter(), newRouter()
+	oldServer, nextServer := httptest.NewServer(old), httptest.NewServer(next)
+	defer oldServer.Close()
+	defer nextServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, oldServer.URL)
+	for i := 0; i < 3; i++ {
+		wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+		var diff map[string]any
+		wsjson.Read(ctx, ws, &diff)
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- old.Drain(ctx, DrainConfig{Version: "v2", MaxDelay: time.Second}) }()
+
+	var msg struct {
+		Event   string         `json:"event"`
+		Payload map[string]any `json:"payload"`
+	}
+	if err := wsjson.Read(ctx, ws, &msg); err != nil || msg.Event != ReconnectEvent {
+		t.Fatalf("Expected %s, got %+v (%v)", ReconnectEvent, msg, err)
+	}
+	token, _ := msg.Payload["token"].(string)
+	if token == "" || msg.Payload["version"] != "v2" || msg.Payload["max"] != float64(1000) {
+		t.Fatalf("Unexpected reconnect payload: %+v", msg.Payload)
+	}
+
+	// The draining instance refuses new sockets
+	if _, resp, err := websocket.Dial(ctx, "ws"+oldServer.URL[4:]+"/", nil); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected new sockets to be refused while draining")
+	}
+	if !old.Draining() {
+		t.Error("Expected Draining() to report true")
+	}
+
+	ws.Close(websocket.StatusNormalClosure, "")
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+
+	// The client rejoins the new instance with its token
+	ws, _, err := websocket.Dial(ctx, "ws"+nextServer.URL[4:]+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.CloseNow()
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{"recovery": token}})
+	var reply map[string]any
+	wsjson.Read(ctx, ws, &reply)
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+	var diff struct {
+		Payload struct {
+			S map[string]any `json:"s"`
+		} `json:"payload"`
+	}
+	wsjson.Read(ctx, ws, &diff)
+	if diff.Payload.S["n"] != "4" {
+		t.Errorf("Expected the restored count to continue at 4, got %v", diff.Payload.S["n"])
+	}
+}
+
+func TestRouter_RecoversDroppedSession(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &snapCounter{} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// join connects and returns the session token and the count after one
+	// more increment.
+	join := func(token string) (string, any) {
+		ws, _, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ws.CloseNow()
+		payload := map[string]any{}
+		if token != "" {
+			payload["session"] = token
+		}
+		wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": payload})
+		var reply struct {
+			Payload struct {
+				Response map[string]any `json:"response"`
+			} `json:"payload"`
+		}
+		if err := wsjson.Read(ctx, ws, &reply); err != nil {
+			t.Fatal(err)
+		}
+		var diff struct {
+			Payload struct {
+				S map[string]any `json:"s"`
+			} `json:"payload"`
+		}
+		for i := 0; i < 2; i++ {
+			wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+			wsjson.Read(ctx, ws, &diff)
+		}
+		session, _ := reply.Payload.Response["session"].(string)
+		return session, diff.Payload.S["n"]
+	}
+	waitGone := func() {
+		for r.SessionManager().Count() > 0 {
+			select {
+			case <-ctx.Done():
+				t.Fatal("session still open after the connection dropped")
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	token, n := join("")
+	if token == "" || n != "2" {
+		t.Fatalf("Expected a session token and a count of 2, got %q, %v", token, n)
+	}
+	waitGone()
+
+	// The rejoining client gets its count back
+	if _, n := join(token); n != "4" {
+		t.Errorf("Expected the recovered count to continue at 4, got %v", n)
+	}
+	waitGone()
+
+	// A token recovers once
+	if _, n := join(token); n != "2" {
+		t.Errorf("Expected a reused token to start over, got %v", n)
+	}
+	waitGone()
+
+	// Without a recovery TTL nothing is kept
+	r.SessionManager().SetRecoveryTTL(0)
+	token, _ = join("")
+	waitGone()
+	if _, n := join(token); n != "2" {
+		t.Errorf("Expected no recovery with a zero TTL, got %v", n)
+	}
+}
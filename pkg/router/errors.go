@@ -0,0 +1,52 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/logging"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// ErrInternal is the reason the client receives for handler errors that
+// are not user errors (see core.UserError). Their details stay in the log.
+var ErrInternal = errors.New("internal error")
+
+// sendEventError replies to an event whose handler failed. A user error
+// reaches the client with its code, message and field messages; other
+// errors are logged and reported as ErrInternal.
+func (r *Router) sendEventError(ctx context.Context, session *LiveViewSession, msg transport.Message, err error) {
+	if ue, ok := core.AsUserError(err); ok {
+		session.Transport.Send(transport.Message{
+			Ref:   msg.Ref,
+			Topic: msg.Topic,
+			Event: "phx_reply",
+			Payload: map[string]any{
+				"status": "error",
+				"response": map[string]any{
+					"reason": ue.Message,
+					"error":  ue.Payload(),
+				},
+			},
+		})
+		return
+	}
+	logging.L(ctx).Error("event handler failed",
+		logging.String("event", msg.Event),
+		logging.String("socket", session.SocketID),
+		logging.Err(err))
+	r.sendError(session, msg.Ref, msg.Topic, ErrInternal)
+}
+
+// reportInfoError handles an error of HandleInfo, which has no event to
+// reply to: a user error is pushed as core.ErrorEvent, others are logged.
+func (r *Router) reportInfoError(ctx context.Context, session *LiveViewSession, err error) {
+	if ue, ok := core.AsUserError(err); ok {
+		session.Socket.Push(core.ErrorEvent, ue.Payload())
+		return
+	}
+	logging.L(ctx).Error("info handler failed",
+		logging.String("socket", session.SocketID),
+		logging.Err(err))
+}
@@ -0,0 +1,87 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// failingView fails its events with a user error ("taken") or an
+// internal one ("crash").
+type failingView struct {
+	core.BaseComponent
+}
+
+func (v *failingView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	switch event {
+	case "taken":
+		return core.UserError("username_taken", "Check the form",
+			map[string]string{"username": "This username is taken"})
+	case "crash":
+		return errors.New("db: connection refused")
+	}
+	return nil
+}
+
+func (v *failingView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<form></form>`)
+		return err
+	})
+}
+
+func TestRouter_EventErrors(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &failingView{} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.CloseNow()
+
+	wsjson.Write(ctx, ws, map[string]any{"ref": "0", "topic": "lv:t", "event": "phx_join", "payload": map[string]any{}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:t", "event": "taken", "payload": map[string]any{}})
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:t", "event": "crash", "payload": map[string]any{}})
+
+	replies := map[string]map[string]any{}
+	for len(replies) < 2 {
+		var msg struct {
+			Ref     string         `json:"ref"`
+			Event   string         `json:"event"`
+			Payload map[string]any `json:"payload"`
+		}
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if msg.Event == "phx_reply" && msg.Ref != "0" {
+			replies[msg.Ref], _ = msg.Payload["response"].(map[string]any)
+		}
+	}
+
+	userErr, _ := replies["1"]["error"].(map[string]any)
+	if userErr["code"] != "username_taken" || userErr["message"] != "Check the form" {
+		t.Errorf("user error = %v", replies["1"])
+	}
+	if fields, _ := userErr["fields"].(map[string]any); fields["username"] != "This username is taken" {
+		t.Errorf("fields = %v", userErr["fields"])
+	}
+
+	if replies["2"]["reason"] != ErrInternal.Error() {
+		t.Errorf("internal error reason = %v, want %q", replies["2"]["reason"], ErrInternal)
+	}
+	if _, ok := replies["2"]["error"]; ok {
+		t.Errorf("internal error carries a user error: %v", replies["2"])
+	}
+}
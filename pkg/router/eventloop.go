@@ -0,0 +1,179 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// Event loop defaults.
+const (
+	// DefaultEventLoopBufferSize is the per-connection send and receive
+	// queue size in event loop mode.
+	DefaultEventLoopBufferSize = 32
+	// eventLoopBatch caps the messages a worker handles for one session
+	// before moving on, so a chatty client cannot starve the others.
+	eventLoopBatch = 16
+)
+
+// EventLoopConfig configures the shared event loop.
+type EventLoopConfig struct {
+	// Workers is the number of goroutines processing sessions. Zero keeps
+	// the default of one message loop goroutine per connection.
+	Workers int
+
+	// BufferSize is the per-connection send and receive queue size.
+	// Default: DefaultEventLoopBufferSize.
+	BufferSize int
+}
+
+// SetEventLoop switches new connections to a shared event loop for very
+// high connection counts. By default every socket runs its own message loop,
+// close watcher and ping ticker; with an event loop a fixed pool of workers
+// picks up sessions only when they have messages, and server pings are
+// replaced by the client heartbeat. The socket's read and write goroutines
+// remain. Call it before the router serves connections.
+func (r *Router) SetEventLoop(config EventLoopConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if config.Workers <= 0 {
+		r.events = nil
+		return
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultEventLoopBufferSize
+	}
+	r.events = newEventLoop(r, config)
+}
+
+// loop returns the shared event loop, or nil.
+func (r *Router) loop() *eventLoop {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.events
+}
+
+// eventLoop runs sessions on a shared pool of workers. A session is queued
+// at most once at a time (LiveViewSession.scheduled), so its messages are
+// still handled one by one and in order.
+type eventLoop struct {
+	router *Router
+	config EventLoopConfig
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue []*LiveViewSession
+}
+
+func newEventLoop(r *Router, config EventLoopConfig) *eventLoop {
+	l := &eventLoop{router: r, config: config}
+	l.cond = sync.NewCond(&l.mu)
+	for i := 0; i < config.Workers; i++ {
+		go l.worker()
+	}
+	return l
+}
+
+// configure adapts a transport config to event loop mode.
+func (l *eventLoop) configure(config *transport.TransportConfig) {
+	config.PingInterval = 0
+	config.SendBufferSize = l.config.BufferSize
+	config.ReceiveBufferSize = l.config.BufferSize
+}
+
+// attach hands a new session to the loop.
+func (l *eventLoop) attach(session *LiveViewSession) {
+	notify := func() { l.schedule(session) }
+	session.Transport.SetNotify(notify)
+	session.Socket.OnInfo(notify)
+
+	// Messages may have arrived before the hooks were set
+	l.schedule(session)
+}
+
+// schedule queues session unless it is already queued or running.
+func (l *eventLoop) schedule(session *LiveViewSession) {
+	if !session.scheduled.CompareAndSwap(false, true) {
+		return
+	}
+	l.mu.Lock()
+	l.queue = append(l.queue, session)
+	l.mu.Unlock()
+	l.cond.Signal()
+}
+
+func (l *eventLoop) worker() {
+	for {
+		l.mu.Lock()
+		for len(l.queue) == 0 {
+			l.cond.Wait()
+		}
+		session := l.queue[0]
+		l.queue[0] = nil
+		l.queue = l.queue[1:]
+		l.mu.Unlock()
+
+		l.run(session)
+	}
+}
+
+// run handles a batch of the session's pending messages, then releases it
+// and queues it again if more arrived meanwhile.
+func (l *eventLoop) run(session *LiveViewSession) {
+	for i := 0; i < eventLoopBatch && !session.done; i++ {
+		if !l.step(session) {
+			break
+		}
+	}
+	done := session.done
+	session.scheduled.Store(false)
+	if !done && l.pending(session) {
+		l.schedule(session)
+	}
+}
+
+// step handles one pending message. It returns false when there is none.
+// A closed connection is only handled once its queued messages are done; a
+// session closed by the router (see disconnect) is released at once.
+func (l *eventLoop) step(session *LiveViewSession) bool {
+	r := l.router
+	select {
+	case <-session.base.Done():
+		session.done = true
+		return false
+	default:
+	}
+	select {
+	case info := <-session.Socket.Info():
+		r.handleInfo(session.ctx, session, info)
+	case msg := <-session.Transport.Receive():
+		if !r.handleMessage(session.ctx, session, msg) {
+			session.done = true
+		}
+	default:
+		select {
+		case <-session.Transport.CloseChan():
+			session.done = true
+			r.handleDisconnect(session)
+		default:
+		}
+		return false
+	}
+	return true
+}
+
+// pending reports whether the session has work left. It only reads
+// channels, so it is safe after the session was released.
+func (l *eventLoop) pending(session *LiveViewSession) bool {
+	if len(session.Socket.Info()) > 0 || len(session.Transport.Receive()) > 0 {
+		return true
+	}
+	select {
+	case <-session.Transport.CloseChan():
+		return true
+	case <-session.base.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,141 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// loopCounter counts events and info messages.
+type loopCounter struct {
+	core.BaseComponent
+	count int
+}
+
+func (c *loopCounter) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	c.count++
+	return nil
+}
+
+func (c *loopCounter) HandleInfo(ctx context.Context, msg any) error {
+	c.count += msg.(int)
+	return nil
+}
+
+func (c *loopCounter) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<div data-live-view="c"><span data-slot="n">%d</span></div>`, c.count)
+		return err
+	})
+}
+
+func dialLive(t testing.TB, ctx context.Context, url string) *websocket.Conn {
+	t.Helper()
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(url, "http")+"/", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	join := map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{}}
+	if err := wsjson.Write(ctx, ws, join); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	var reply map[string]any
+	if err := wsjson.Read(ctx, ws, &reply); err != nil {
+		t.Fatalf("join reply: %v", err)
+	}
+	return ws
+}
+
+func TestEventLoop(t *testing.T) {
+	r := New()
+	r.SetEventLoop(EventLoopConfig{Workers: 2})
+	var comp *loopCounter
+	r.Live("/", func() core.Component {
+		comp = &loopCounter{}
+		return comp
+	})
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+
+	readSlot := func() any {
+		var msg map[string]any
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		payload, _ := msg["payload"].(map[string]any)
+		slots, _ := payload["s"].(map[string]any)
+		return slots["n"]
+	}
+
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+	if n := readSlot(); n != "1" {
+		t.Fatalf("Expected event diff n=1, got %v", n)
+	}
+
+	comp.Socket().SendInfo(10)
+	if n := readSlot(); n != "11" {
+		t.Fatalf("Expected info diff n=11, got %v", n)
+	}
+
+	ws.Close(websocket.StatusNormalClosure, "")
+	deadline := time.Now().Add(2 * time.Second)
+	for r.sessionManager.Count() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := r.sessionManager.Count(); n != 0 {
+		t.Errorf("Expected the session to be cleaned up, %d left", n)
+	}
+}
+
+// benchmarkConnections opens b.N live connections and reports the memory
+// and goroutines each one costs (server and client side).
+func benchmarkConnections(b *testing.B, config EventLoopConfig) {
+	r := New()
+	r.SetEventLoop(config)
+	r.Live("/", func() core.Component { return &loopCounter{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx := context.Background()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	goroutines := runtime.NumGoroutine()
+
+	conns := make([]*websocket.Conn, 0, b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conns = append(conns, dialLive(b, ctx, ts.URL))
+	}
+	b.StopTimer()
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(runtime.NumGoroutine()-goroutines)/float64(b.N), "goroutines/conn")
+	b.ReportMetric(float64(after.HeapInuse+after.StackInuse-before.HeapInuse-before.StackInuse)/float64(b.N), "bytes/conn")
+
+	for _, ws := range conns {
+		ws.CloseNow()
+	}
+}
+
+func BenchmarkConnections_PerConnectionLoop(b *testing.B) {
+	benchmarkConnections(b, EventLoopConfig{})
+}
+
+func BenchmarkConnections_EventLoop(b *testing.B) {
+	benchmarkConnections(b, EventLoopConfig{Workers: runtime.GOMAXPROCS(0) * 4})
+}
@@ -0,0 +1,61 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/pool"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// eventTarget returns the LiveComponent an event is for (its payload's
+// core.TargetKey), or nil for events for the view itself.
+func eventTarget(session *LiveViewSession, msg transport.Message) core.LiveComponent {
+	id, _ := msg.Payload[core.TargetKey].(string)
+	if id == "" {
+		return nil
+	}
+	host, ok := session.Component.(core.LiveComponentHost)
+	if !ok {
+		return nil
+	}
+	return host.LiveComponent(id)
+}
+
+// renderLiveComponent renders child alone and sends the changes of its
+// slots. Children without slots fall back to a diff of the whole view.
+func (r *Router) renderLiveComponent(ctx context.Context, session *LiveViewSession, child core.LiveComponent) {
+	renderer := child.Render(ctx)
+	if renderer == nil {
+		return
+	}
+	buf := pool.GetBuffer()
+	defer pool.PutBuffer(buf)
+	if err := renderer.Render(ctx, buf); err != nil {
+		return
+	}
+
+	textSlots, htmlSlots := extractSlotsOptimized(buf.String())
+	if len(textSlots) == 0 && len(htmlSlots) == 0 {
+		r.renderAndSendDiff(ctx, session)
+		return
+	}
+
+	session.mu.Lock()
+	session.Version++
+	version := session.Version
+	session.mu.Unlock()
+
+	payload := &core.DiffPayload{
+		Version:   version,
+		Slots:     make(map[string]string),
+		HTMLSlots: make(map[string]string),
+	}
+	r.diffSlots(session, payload, textSlots, htmlSlots, true)
+	r.diffTrees(session, payload, nil)
+	r.spliceTextSlots(session, payload)
+	payload.JS = session.Socket.TakeJS()
+	if !payload.IsEmpty() {
+		session.Socket.SendOptimizedDiff(payload)
+	}
+}
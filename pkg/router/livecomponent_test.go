@@ -0,0 +1,99 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+type cartChild struct {
+	core.BaseLiveComponent
+	items int
+}
+
+func (c *cartChild) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if _, ok := payload[core.TargetKey]; ok {
+		return fmt.Errorf("target key not removed")
+	}
+	c.items++
+	return nil
+}
+
+func (c *cartChild) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<span data-slot="%s">%d</span>`, c.Slot("items"), c.items)
+		return err
+	})
+}
+
+type shopView struct {
+	core.BaseComponent
+	core.LiveComponents
+	visits int
+}
+
+func (v *shopView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	v.Add(&cartChild{BaseLiveComponent: core.BaseLiveComponent{ID: "cart"}})
+	return nil
+}
+
+func (v *shopView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	v.visits++
+	return nil
+}
+
+func (v *shopView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		fmt.Fprintf(w, `<div data-live-view="c"><span data-slot="visits">%d</span>`, v.visits)
+		if err := core.Embed(v.LiveComponent("cart")).Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, `</div>`)
+		return err
+	})
+}
+
+func TestRouter_LiveComponentEvents(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &shopView{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+
+	send := func(ref string, payload map[string]any) map[string]any {
+		t.Helper()
+		msg := map[string]any{"ref": ref, "topic": "lv:c", "event": "click", "payload": payload}
+		if err := wsjson.Write(ctx, ws, msg); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		var diff map[string]any
+		if err := wsjson.Read(ctx, ws, &diff); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		p, _ := diff["payload"].(map[string]any)
+		slots, _ := p["s"].(map[string]any)
+		return slots
+	}
+
+	// The child's event updates its slot only
+	slots := send("2", map[string]any{core.TargetKey: "cart"})
+	if len(slots) != 1 || slots["cart.items"] != "1" {
+		t.Errorf("Expected only cart.items=1, got %v", slots)
+	}
+
+	// Events without a target go to the view
+	slots = send("3", map[string]any{})
+	if len(slots) != 1 || slots["visits"] != "1" {
+		t.Errorf("Expected only visits=1, got %v", slots)
+	}
+}
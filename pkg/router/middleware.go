@@ -12,8 +12,10 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/limits"
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
 )
 
 // Common errors.
@@ -21,7 +23,9 @@ var (
 	ErrNilRenderer = errors.New("component returned nil renderer")
 )
 
-// RequestID middleware adds a unique request ID to the context.
+// RequestID middleware adds a unique request ID to the context. It is also
+// the correlation ID of the request (see tracing.CorrelationID), so
+// broadcasts from its handlers carry it.
 func RequestID() Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -31,6 +35,7 @@ func RequestID() Middleware {
 			}
 
 			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			ctx = tracing.WithCorrelationID(ctx, id)
 			w.Header().Set("X-Request-ID", id)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -388,91 +393,13 @@ func SecureHeadersWithConfig(config SecureHeadersConfig) Middleware {
 	}
 }
 
-// RateLimit middleware applies simple rate limiting.
-// For production, use a proper rate limiter with distributed state.
+// RateLimit middleware allows requestsPerSecond requests per second per
+// client IP, with bursts of as many. For other keys, periods or a store
+// shared between instances (Redis), use limits.RateLimit.
 func RateLimit(requestsPerSecond int) Middleware {
-	// Simple token bucket per IP
-	buckets := make(map[string]*tokenBucket)
-	var mu sync.Mutex
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := getClientIP(r)
-
-			mu.Lock()
-			bucket, exists := buckets[ip]
-			if !exists {
-				bucket = newTokenBucket(requestsPerSecond)
-				buckets[ip] = bucket
-			}
-			mu.Unlock()
-
-			if !bucket.Allow() {
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		parts := strings.Split(xff, ",")
-		return strings.TrimSpace(parts[0])
-	}
-
-	// Check X-Real-IP
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
-	}
-
-	// Fall back to remote addr
-	parts := strings.Split(r.RemoteAddr, ":")
-	return parts[0]
-}
-
-type tokenBucket struct {
-	tokens   int
-	maxRate  int
-	lastFill time.Time
-	mu       sync.Mutex
-}
-
-func newTokenBucket(rate int) *tokenBucket {
-	return &tokenBucket{
-		tokens:   rate,
-		maxRate:  rate,
-		lastFill: time.Now(),
-	}
-}
-
-func (tb *tokenBucket) Allow() bool {
-	tb.mu.Lock()
-	defer tb.mu.Unlock()
-
-	// Refill tokens
-	now := time.Now()
-	elapsed := now.Sub(tb.lastFill)
-	refill := int(elapsed.Seconds()) * tb.maxRate
-	if refill > 0 {
-		tb.tokens = min(tb.tokens+refill, tb.maxRate)
-		tb.lastFill = now
-	}
-
-	if tb.tokens <= 0 {
-		return false
-	}
-
-	tb.tokens--
-	return true
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+	return limits.RateLimit(limits.RateLimitConfig{
+		Rate:    requestsPerSecond,
+		Period:  time.Second,
+		KeyFunc: limits.ByIP,
+	})
 }
@@ -0,0 +1,152 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// ErrInvalidNavigation is returned for a core.ParamsEvent whose URL is not
+// a same-origin path.
+var ErrInvalidNavigation = errors.New("navigation to an invalid URL")
+
+// handleParams handles core.ParamsEvent, sent by the client when its URL
+// changed without a page load: after a push_patch or a live navigation, or
+// when the user went back or forward. A URL of the session's route goes to
+// HandleParams and the diff is pushed; a URL of another Live route mounts
+// that route's component on the socket (navigate); anything else reloads
+// the page at that URL. The reply follows the diff, so the client restores
+// its UI state on the new content.
+func (r *Router) handleParams(ctx context.Context, session *LiveViewSession, msg transport.Message) {
+	raw, _ := msg.Payload["url"].(string)
+	u, err := url.Parse(raw)
+	if err != nil || !strings.HasPrefix(u.Path, "/") || u.Host != "" {
+		r.sendError(session, msg.Ref, msg.Topic, ErrInvalidNavigation)
+		return
+	}
+	nav := core.Navigation{URI: u.RequestURI(), Kind: core.NavigationPopState}
+	switch kind, _ := msg.Payload["kind"].(string); core.NavigationKind(kind) {
+	case core.NavigationPatch, core.NavigationNavigate:
+		nav.Kind = core.NavigationKind(kind)
+	}
+
+	// The component follows URLs of its route and of the routes stacked
+	// with it (WithParent)
+	route := r.matchRoute(u)
+	if route != nil && session.IsMounted() && r.stackRoot(route) != r.stackRoot(session.Route) {
+		r.navigate(session, route, u, msg)
+		return
+	}
+	ph, ok := session.Component.(core.ParamsHandler)
+	if !ok || !session.IsMounted() || route == nil {
+		// Replace: the client already moved to this history entry
+		session.Socket.Push(RedirectEvent, map[string]any{"to": nav.URI, "replace": true})
+		return
+	}
+
+	params := r.routeParams(route, u)
+	session.mu.Lock()
+	session.Route = route
+	session.Params = params
+	session.mu.Unlock()
+	if err := ph.HandleParams(ctx, params, nav); err != nil {
+		r.sendError(session, msg.Ref, msg.Topic, err)
+		return
+	}
+	r.renderAndSendDiff(ctx, session)
+	r.flushCookieSession(session)
+	r.sendReply(session, msg.Ref, msg.Topic, map[string]any{})
+}
+
+// navigate moves session to route, the Live route serving u, keeping the
+// socket open (live_redirect): the route's on-mount hooks run, the current
+// component terminates and leaves its topics, and the route's component
+// mounts. The reply carries its document, which the client swaps in.
+//
+// HTTP middleware only run on page requests, so routes with their own
+// middleware (RouteGroup.Use, WithRouteMiddleware), or left from one, are
+// loaded as pages instead; OnMount hooks run either way. A mount error
+// also loads the page, which renders the error or follows a HaltRedirect.
+func (r *Router) navigate(session *LiveViewSession, route *LiveRoute, u *url.URL, msg transport.Message) {
+	reload := func(to string) {
+		session.Socket.Push(RedirectEvent, map[string]any{"to": to, "replace": true})
+	}
+	if session.Route == nil || len(session.Route.Middleware) > 0 || len(route.Middleware) > 0 {
+		reload(u.RequestURI())
+		return
+	}
+
+	socket := session.Socket
+	component := r.newComponent(route)
+	params := r.routeParams(route, u)
+	ctx := r.withValueSigner(core.BuildContext(session.base, socket, component, session.Session, params))
+	if err := runOnMount(ctx, route, params, session.Session); err != nil {
+		var redirect *RedirectError
+		if errors.As(err, &redirect) {
+			reload(redirect.To)
+		} else {
+			reload(u.RequestURI())
+		}
+		return
+	}
+
+	// The session slot moves to the new route
+	r.quota.release(session.SocketID)
+	if _, ok := r.quota.acquire(route, session.SocketID); !ok {
+		reload(u.RequestURI())
+		return
+	}
+
+	session.Component.Terminate(session.ctx, core.TerminateNormal)
+	for _, topic := range socket.Subscriptions() {
+		socket.Unsubscribe(topic)
+	}
+
+	// Diff state of the old view
+	r.diffEngine.InvalidateSocket(session.SocketID)
+	r.clearSlotState(session.SocketID)
+	r.clearListState(session.SocketID)
+	r.clearSlotHashCache(session.SocketID)
+	session.SetSlotHashes(nil)
+	session.setSlotTrees(nil)
+	session.setSlotTexts(nil)
+	session.swapFullBase("")
+
+	if bc, ok := component.(interface{ SetSocket(*core.Socket) }); ok {
+		bc.SetSocket(socket)
+	}
+	session.mu.Lock()
+	session.Component = component
+	session.Route = route
+	session.Params = params
+	session.Mounted = false
+	session.headKey = ""
+	session.mu.Unlock()
+	session.ctx = ctx
+
+	if err := component.Mount(ctx, params, session.Session); err != nil {
+		reload(u.RequestURI())
+		return
+	}
+	session.SetMounted(true)
+	if dt, ok := component.(core.DirtyTracker); ok {
+		dt.TakeDirty()
+	}
+
+	document, err := renderDocument(ctx, component)
+	if err != nil {
+		r.sendError(session, msg.Ref, msg.Topic, err)
+		return
+	}
+	// The document carries the head
+	if hp, ok := component.(core.HeadProvider); ok {
+		session.mu.Lock()
+		session.headKey = hp.Head().Key()
+		session.mu.Unlock()
+	}
+	r.sendReply(session, msg.Ref, msg.Topic, map[string]any{"document": document})
+}
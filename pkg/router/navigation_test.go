@@ -0,0 +1,180 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// pagedList follows the page query parameter in place.
+type pagedList struct {
+	core.BaseComponent
+	page string
+	kind core.NavigationKind
+}
+
+func (c *pagedList) HandleParams(ctx context.Context, params core.Params, nav core.Navigation) error {
+	c.page, c.kind = params.Get("page"), nav.Kind
+	return nil
+}
+
+func (c *pagedList) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<div data-live-view="c"><span data-slot="page">%s</span></div>`, c.page)
+		return err
+	})
+}
+
+func TestRouter_HandleParams(t *testing.T) {
+	r := New()
+	var comp *pagedList
+	r.Live("/", func() core.Component {
+		comp = &pagedList{page: "1"}
+		return comp
+	})
+	r.Live("/other", func() core.Component { return &loopCounter{} })
+	r.Live("/guarded", func() core.Component { return &loopCounter{} }, WithRouteMiddleware(func(h http.Handler) http.Handler { return h }))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+
+	type message struct {
+		Ref     string         `json:"ref"`
+		Event   string         `json:"event"`
+		Payload map[string]any `json:"payload"`
+	}
+	navigate := func(url string) []message {
+		params := map[string]any{"url": url, "kind": "pop"}
+		wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": core.ParamsEvent, "payload": params})
+		var msgs []message
+		for {
+			var msg message
+			if err := wsjson.Read(ctx, ws, &msg); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			msgs = append(msgs, msg)
+			if msg.Event != "diff" {
+				return msgs
+			}
+		}
+	}
+
+	msgs := navigate("/?page=2")
+	if len(msgs) != 2 || msgs[0].Event != "diff" || msgs[1].Event != "phx_reply" || msgs[1].Ref != "2" {
+		t.Fatalf("Expected a diff then the reply, got %+v", msgs)
+	}
+	slots, _ := msgs[0].Payload["s"].(map[string]any)
+	if slots["page"] != "2" || comp.kind != core.NavigationPopState {
+		t.Errorf("Expected page 2 from a back/forward navigation, got %v (%s)", slots["page"], comp.kind)
+	}
+
+	// A route with its own middleware is loaded instead
+	msgs = navigate("/guarded?x=1")
+	if msgs[0].Event != RedirectEvent || msgs[0].Payload["to"] != "/guarded?x=1" || msgs[0].Payload["replace"] != true {
+		t.Errorf("Expected a replacing redirect to /guarded, got %+v", msgs)
+	}
+}
+
+// navPage is a view subscribed to a topic, recording its termination.
+type navPage struct {
+	core.BaseComponent
+	name       string
+	tab        string
+	terminated bool
+	events     int
+}
+
+func (c *navPage) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	c.tab = params.Get("tab")
+	return c.Socket().Subscribe("news:" + c.name)
+}
+
+func (c *navPage) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	c.events++
+	return nil
+}
+
+func (c *navPage) Terminate(ctx context.Context, reason core.TerminateReason) error {
+	c.terminated = true
+	return nil
+}
+
+func (c *navPage) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<html><body><div data-live-view="%s"><span data-slot="tab">%s</span><span data-slot="n">%d</span></div></body></html>`, c.name, c.tab, c.events)
+		return err
+	})
+}
+
+func TestRouter_Navigate(t *testing.T) {
+	r := New()
+	pages := map[string]*navPage{}
+	page := func(name string) func() core.Component {
+		return func() core.Component {
+			pages[name] = &navPage{name: name}
+			return pages[name]
+		}
+	}
+	r.Live("/", page("home"))
+	r.Live("/about", page("about"))
+	r.Live("/private", page("private"), WithOnMount(func(ctx context.Context, params core.Params, session core.Session) error {
+		return HaltRedirect("/login")
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+
+	var msg struct {
+		Ref     string         `json:"ref"`
+		Event   string         `json:"event"`
+		Payload map[string]any `json:"payload"`
+	}
+	send := func(ref, event string, payload map[string]any) {
+		t.Helper()
+		wsjson.Write(ctx, ws, map[string]any{"ref": ref, "topic": "lv:home", "event": event, "payload": payload})
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+	}
+
+	send("2", core.ParamsEvent, map[string]any{"url": "/about?tab=team", "kind": "navigate"})
+	response, _ := msg.Payload["response"].(map[string]any)
+	document, _ := response["document"].(string)
+	if msg.Event != "phx_reply" || msg.Ref != "2" || !strings.Contains(document, `<div data-live-view="about"><span data-slot="tab">team</span>`) {
+		t.Fatalf("Expected the reply to carry the about document, got %+v", msg)
+	}
+	home, about := pages["home"], pages["about"]
+	if !home.terminated || about.Socket() != home.Socket() {
+		t.Errorf("Expected home terminated and about mounted on its socket")
+	}
+	if subs := about.Socket().Subscriptions(); len(subs) != 1 || subs[0] != "news:about" {
+		t.Errorf("Expected only the about topic, got %v", subs)
+	}
+
+	// Events reach the new view
+	send("3", "click", map[string]any{})
+	slots, _ := msg.Payload["s"].(map[string]any)
+	if msg.Event != "diff" || about.events != 1 || home.events != 0 || slots["n"] != "1" {
+		t.Errorf("Expected a diff of the about view, got %+v", msg)
+	}
+
+	// On-mount hooks run, and their redirects load the target page
+	send("4", core.ParamsEvent, map[string]any{"url": "/private", "kind": "navigate"})
+	if msg.Event != RedirectEvent || msg.Payload["to"] != "/login" || about.terminated {
+		t.Errorf("Expected a redirect to /login keeping the about view, got %+v", msg)
+	}
+}
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// OnMountHook runs before a route's component mounts: on the HTTP render
+// and again on join. Groups use it to load shared data once for all their
+// routes (the current user, the organization) and store it in the session
+// every component's Mount receives. An error aborts the mount; return
+// HaltRedirect to send the visitor elsewhere.
+//
+//	r.Group("/admin", func(g *router.RouteGroup) {
+//		g.OnMount(func(ctx context.Context, params core.Params, session core.Session) error {
+//			user, err := users.Find(ctx, session.GetString("user_id"))
+//			if err != nil {
+//				return router.HaltRedirect("/login")
+//			}
+//			session["current_user"] = user
+//			return nil
+//		})
+//		g.Live("/", NewDashboard)
+//	})
+type OnMountHook func(ctx context.Context, params core.Params, session core.Session) error
+
+// RedirectError halts a mount and redirects to To (see HaltRedirect).
+type RedirectError struct {
+	To string
+}
+
+func (e *RedirectError) Error() string {
+	return "router: mount halted, redirect to " + e.To
+}
+
+// HaltRedirect returns an error that halts the mount and redirects: a 302
+// on the HTTP render, an lv:redirect on join.
+func HaltRedirect(to string) error {
+	return &RedirectError{To: to}
+}
+
+// WithOnMount adds hooks run before the route's component mounts, after the
+// hooks of its groups.
+func WithOnMount(hooks ...OnMountHook) RouteOption {
+	return func(r *LiveRoute) {
+		r.OnMount = append(r.OnMount, hooks...)
+	}
+}
+
+// runOnMount runs route's hooks in order, stopping at the first error.
+func runOnMount(ctx context.Context, route *LiveRoute, params core.Params, session core.Session) error {
+	if route == nil {
+		return nil
+	}
+	for _, hook := range route.OnMount {
+		if err := hook(ctx, params, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mountError answers an HTTP render whose mount failed.
+func (r *Router) mountError(w http.ResponseWriter, req *http.Request, err error) {
+	var redirect *RedirectError
+	if errors.As(err, &redirect) {
+		http.Redirect(w, req, redirect.To, http.StatusFound)
+		return
+	}
+	r.errorHandler(w, req, err)
+}
+
+// sendMountError answers a join whose mount failed.
+func (r *Router) sendMountError(session *LiveViewSession, msg transport.Message, err error) {
+	var redirect *RedirectError
+	if errors.As(err, &redirect) {
+		session.Socket.Push(RedirectEvent, map[string]any{"to": redirect.To})
+	}
+	r.sendError(session, msg.Ref, msg.Topic, err)
+}
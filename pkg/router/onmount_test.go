@@ -0,0 +1,83 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// sessionView renders the session values its Mount received.
+type sessionView struct {
+	core.BaseComponent
+	org, team string
+}
+
+func (v *sessionView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	v.org = session.GetString("org")
+	v.team = session.GetString("team")
+	return nil
+}
+
+func (v *sessionView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "<div>%s/%s</div>", v.org, v.team)
+		return err
+	})
+}
+
+func TestRouteGroup_OnMount(t *testing.T) {
+	r := New()
+	var calls []string
+	r.Group("/orgs", func(g *RouteGroup) {
+		g.OnMount(func(ctx context.Context, params core.Params, session core.Session) error {
+			calls = append(calls, "org")
+			session["org"] = "acme"
+			return nil
+		})
+		g.Group("/teams", func(g *RouteGroup) {
+			g.OnMount(func(ctx context.Context, params core.Params, session core.Session) error {
+				calls = append(calls, "team")
+				session["team"] = session.GetString("org") + "-core"
+				return nil
+			})
+			g.Live("/core", func() core.Component { return &sessionView{} })
+		})
+		g.Live("/settings", func() core.Component { return &sessionView{} })
+		g.Live("/private", func() core.Component { return &sessionView{} },
+			WithOnMount(func(ctx context.Context, params core.Params, session core.Session) error {
+				return HaltRedirect("/login")
+			}))
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/orgs/teams/core"); !strings.Contains(w.Body.String(), "acme/acme-core") {
+		t.Errorf("Expected nested hooks to fill the session, got %d %q", w.Code, w.Body.String())
+	}
+	if strings.Join(calls, ",") != "org,team" {
+		t.Errorf("Expected outer hooks before nested ones, got %v", calls)
+	}
+
+	calls = nil
+	if w := get("/orgs/settings"); !strings.Contains(w.Body.String(), "acme/") {
+		t.Errorf("Expected the group hook to run, got %q", w.Body.String())
+	}
+	if strings.Join(calls, ",") != "org" {
+		t.Errorf("Expected only the group hook, got %v", calls)
+	}
+
+	w := get("/orgs/private")
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/login" {
+		t.Errorf("Expected a redirect to /login, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+}
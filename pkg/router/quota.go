@@ -0,0 +1,425 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/client"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// OverflowMode selects what visitors get when a route or the server is full.
+type OverflowMode int
+
+const (
+	// OverflowReject serves a "server full" page (503 with Retry-After).
+	OverflowReject OverflowMode = iota
+	// OverflowQueue holds visitors in a FIFO queue. The queue page shows
+	// their position and refreshes until a slot frees up.
+	OverflowQueue
+	// OverflowWaitingRoom queues visitors like OverflowQueue but serves a
+	// waiting room LiveView that updates their position live and sends them
+	// to the route as soon as a slot frees up.
+	OverflowWaitingRoom
+)
+
+// QueueCookie holds a visitor's queue ticket.
+const QueueCookie = "_lv_queue"
+
+// OverflowInfo describes why a visitor was not admitted.
+type OverflowInfo struct {
+	// Route is the path of the live route.
+	Route string
+	// Active and Limit are the connected sessions and the cap that was hit
+	// (the route's or the global one).
+	Active int
+	Limit  int
+	// Global is true when the server-wide cap was hit.
+	Global bool
+	// Position is the 1-based queue position, 0 when not queued.
+	Position int
+	// Waiting is the queue length.
+	Waiting int
+	// RetryAfter is when the visitor should try again.
+	RetryAfter time.Duration
+}
+
+// OverflowHandler writes the response for a visitor that was not admitted.
+type OverflowHandler func(w http.ResponseWriter, r *http.Request, info OverflowInfo)
+
+// QuotaConfig configures session caps. Per-route caps are set with
+// WithMaxSessions.
+type QuotaConfig struct {
+	// MaxSessions caps live sessions across all routes (0 = unlimited).
+	MaxSessions int
+
+	// Mode is the overflow behavior (default OverflowReject).
+	Mode OverflowMode
+
+	// Handler renders the overflow response (default DefaultOverflowHandler).
+	Handler OverflowHandler
+
+	// RetryAfter is the queue page refresh interval (default 5s).
+	RetryAfter time.Duration
+
+	// TicketTTL drops queued visitors that stopped refreshing
+	// (default 3 * RetryAfter).
+	TicketTTL time.Duration
+
+	// ReserveTTL is how long an admitted visitor's slot is held while the
+	// page loads and the socket connects (default 30s).
+	ReserveTTL time.Duration
+
+	// WaitingRoom renders the waiting room body in OverflowWaitingRoom mode
+	// (default DefaultWaitingRoom). Position updates are sent as slot diffs,
+	// so keep the changing parts in data-slot elements.
+	WaitingRoom func(info OverflowInfo) string
+
+	// ClientScript is the URL of the GoliveKit client loaded by the waiting
+	// room page (default client.ScriptPath()).
+	ClientScript string
+}
+
+// quotaState tracks live sessions, reservations and queues per route.
+type quotaState struct {
+	config QuotaConfig
+
+	total    int
+	active   map[string]int         // route -> connected sessions
+	sockets  map[string]string      // socket ID -> route, so release is idempotent
+	reserved map[string][]time.Time // route -> reservation expiries
+	queues   map[string][]*ticket   // route -> FIFO queue
+	tickets  map[string]*ticket
+	routes   map[string]*LiveRoute // route -> definition, for promote
+
+	// Connected waiting rooms and whether the promote loop runs.
+	watchers int
+	ticking  bool
+
+	mu sync.Mutex
+}
+
+type ticket struct {
+	id       string
+	route    string
+	lastSeen time.Time
+
+	// admitted is set when a waiting room visitor got a slot; the ticket is
+	// kept until their page request uses the reservation.
+	admitted   bool
+	admittedAt time.Time
+	notified   bool
+
+	// watcher is the waiting room socket of the visitor, if connected.
+	watcher  *core.Socket
+	position int
+}
+
+func newQuotaState() *quotaState {
+	q := &quotaState{
+		active:   make(map[string]int),
+		sockets:  make(map[string]string),
+		reserved: make(map[string][]time.Time),
+		queues:   make(map[string][]*ticket),
+		tickets:  make(map[string]*ticket),
+		routes:   make(map[string]*LiveRoute),
+	}
+	q.configure(QuotaConfig{})
+	return q
+}
+
+func (q *quotaState) configure(config QuotaConfig) {
+	if config.Handler == nil {
+		config.Handler = DefaultOverflowHandler
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = 5 * time.Second
+	}
+	if config.TicketTTL <= 0 {
+		config.TicketTTL = 3 * config.RetryAfter
+	}
+	if config.ReserveTTL <= 0 {
+		config.ReserveTTL = 30 * time.Second
+	}
+	if config.WaitingRoom == nil {
+		config.WaitingRoom = DefaultWaitingRoom
+	}
+	if config.ClientScript == "" {
+		config.ClientScript = client.ScriptPath()
+	}
+	q.mu.Lock()
+	q.config = config
+	q.mu.Unlock()
+}
+
+// SetQuota configures the global session cap and the overflow behavior.
+func (r *Router) SetQuota(config QuotaConfig) {
+	r.quota.configure(config)
+}
+
+// ActiveSessions returns the connected live sessions of a route, or of all
+// routes when path is "".
+func (r *Router) ActiveSessions(path string) int {
+	r.quota.mu.Lock()
+	defer r.quota.mu.Unlock()
+	if path == "" {
+		return r.quota.total
+	}
+	return r.quota.active[path]
+}
+
+// WithMaxSessions caps the live sessions of a route (0 = unlimited).
+func WithMaxSessions(n int) RouteOption {
+	return func(r *LiveRoute) {
+		r.MaxSessions = n
+	}
+}
+
+// free returns the free slots for a route, after reservations, and the cap
+// that limits it. Must be called with q.mu held.
+func (q *quotaState) free(route *LiveRoute, now time.Time) (free int, info OverflowInfo) {
+	path := route.Path
+	pending := q.reserved[path][:0]
+	for _, exp := range q.reserved[path] {
+		if now.Before(exp) {
+			pending = append(pending, exp)
+		}
+	}
+	q.reserved[path] = pending
+
+	totalPending := 0
+	for _, r := range q.reserved {
+		totalPending += len(r)
+	}
+
+	free = int(^uint(0) >> 1)
+	info = OverflowInfo{Route: path}
+	if route.MaxSessions > 0 {
+		free = route.MaxSessions - q.active[path] - len(pending)
+		info.Active, info.Limit = q.active[path], route.MaxSessions
+	}
+	if max := q.config.MaxSessions; max > 0 && max-q.total-totalPending < free {
+		free = max - q.total - totalPending
+		info.Active, info.Limit, info.Global = q.total, max, true
+	}
+	return free, info
+}
+
+// admit decides whether a page request may load the route. id is the
+// visitor's queue ticket, if any; the returned ticket must be set as a
+// cookie when the visitor was queued.
+func (q *quotaState) admit(route *LiveRoute, id string) (tk *ticket, info OverflowInfo, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if route.MaxSessions <= 0 && q.config.MaxSessions <= 0 {
+		return nil, OverflowInfo{}, true
+	}
+
+	now := time.Now()
+	q.routes[route.Path] = route
+	q.pruneTickets(route.Path, now)
+	free, info := q.free(route, now)
+	info.RetryAfter = q.config.RetryAfter
+	queue := q.queues[route.Path]
+
+	if tk = q.tickets[id]; tk != nil && tk.route == route.Path {
+		if tk.admitted {
+			// Promoted from the waiting room: the slot is already reserved.
+			q.dropTicket(tk)
+			return nil, info, true
+		}
+		tk.lastSeen = now
+		pos := indexOf(queue, tk) + 1
+		if pos <= free {
+			q.dequeue(tk)
+			q.reserve(route.Path, now)
+			return nil, info, true
+		}
+		info.Position, info.Waiting = pos, len(queue)
+		return tk, info, false
+	}
+
+	if len(queue) == 0 && free > 0 {
+		q.reserve(route.Path, now)
+		return nil, info, true
+	}
+	if q.config.Mode == OverflowReject {
+		return nil, info, false
+	}
+
+	tk = &ticket{id: newTicketID(), route: route.Path, lastSeen: now}
+	q.tickets[tk.id] = tk
+	q.queues[route.Path] = append(queue, tk)
+	info.Position, info.Waiting = len(queue)+1, len(queue)+1
+	return tk, info, false
+}
+
+// acquire registers a connecting socket. Sockets of visitors admitted by
+// admit use their reservation; others (reconnects) need a free slot.
+func (q *quotaState) acquire(route *LiveRoute, socketID string) (OverflowInfo, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	limited := route.MaxSessions > 0 || q.config.MaxSessions > 0
+	if limited {
+		now := time.Now()
+		free, info := q.free(route, now)
+		if pending := q.reserved[route.Path]; len(pending) > 0 {
+			q.reserved[route.Path] = pending[1:]
+		} else if free <= 0 {
+			info.RetryAfter = q.config.RetryAfter
+			return info, false
+		}
+	}
+
+	q.sockets[socketID] = route.Path
+	q.active[route.Path]++
+	q.total++
+	return OverflowInfo{}, true
+}
+
+// release unregisters a socket. It is safe to call more than once.
+func (q *quotaState) release(socketID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	path, ok := q.sockets[socketID]
+	if !ok {
+		return
+	}
+	delete(q.sockets, socketID)
+	q.active[path]--
+	q.total--
+	q.promoteAll()
+}
+
+func (q *quotaState) reserve(path string, now time.Time) {
+	q.reserved[path] = append(q.reserved[path], now.Add(q.config.ReserveTTL))
+}
+
+func (q *quotaState) dequeue(tk *ticket) {
+	queue := q.queues[tk.route]
+	if i := indexOf(queue, tk); i >= 0 {
+		q.queues[tk.route] = append(queue[:i:i], queue[i+1:]...)
+	}
+	delete(q.tickets, tk.id)
+}
+
+// pruneTickets drops queued visitors that stopped refreshing.
+func (q *quotaState) pruneTickets(path string, now time.Time) {
+	queue := q.queues[path]
+	kept := queue[:0]
+	for _, tk := range queue {
+		// Connected waiting rooms keep their ticket alive.
+		if tk.watcher != nil || now.Sub(tk.lastSeen) < q.config.TicketTTL {
+			kept = append(kept, tk)
+		} else {
+			delete(q.tickets, tk.id)
+		}
+	}
+	q.queues[path] = kept
+
+	for _, tk := range q.tickets {
+		if tk.admitted && tk.route == path && now.Sub(tk.admittedAt) >= q.config.ReserveTTL {
+			q.dropTicket(tk)
+		}
+	}
+}
+
+// dropTicket forgets a ticket that is no longer queued.
+func (q *quotaState) dropTicket(tk *ticket) {
+	if tk.watcher != nil {
+		tk.watcher = nil
+		q.watchers--
+	}
+	delete(q.tickets, tk.id)
+}
+
+func indexOf(queue []*ticket, tk *ticket) int {
+	for i, t := range queue {
+		if t == tk {
+			return i
+		}
+	}
+	return -1
+}
+
+func newTicketID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// checkQuota admits a page request or writes the overflow response.
+func (r *Router) checkQuota(w http.ResponseWriter, req *http.Request, route *LiveRoute) bool {
+	var id string
+	if c, err := req.Cookie(QueueCookie); err == nil {
+		id = c.Value
+	}
+	tk, info, ok := r.quota.admit(route, id)
+	if ok {
+		if id != "" {
+			http.SetCookie(w, &http.Cookie{Name: QueueCookie, Path: "/", MaxAge: -1})
+		}
+		return true
+	}
+	if tk != nil {
+		http.SetCookie(w, &http.Cookie{
+			Name:     QueueCookie,
+			Value:    tk.id,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	r.quota.mu.Lock()
+	config := r.quota.config
+	r.quota.mu.Unlock()
+	if config.Mode == OverflowWaitingRoom && info.Position > 0 {
+		serveWaitingRoom(w, config, info)
+		return false
+	}
+	config.Handler(w, req, info)
+	return false
+}
+
+// DefaultOverflowHandler serves a minimal "server full" or queue page with
+// status 503 and a Retry-After header. Queued visitors' pages refresh
+// themselves to update their position.
+func DefaultOverflowHandler(w http.ResponseWriter, r *http.Request, info OverflowInfo) {
+	seconds := int(info.RetryAfter.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Header.Get("Accept") == "application/json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, `{"error":"server_full","position":%d,"waiting":%d,"retry_after":%d}`,
+			info.Position, info.Waiting, seconds)
+		return
+	}
+
+	title, message := "Server full", "We're at capacity right now. Please try again in a moment."
+	refresh := ""
+	if info.Position > 0 {
+		title = "You're in line"
+		message = fmt.Sprintf("You are number %d of %d in line. This page updates automatically.", info.Position, info.Waiting)
+		refresh = fmt.Sprintf(`<meta http-equiv="refresh" content="%d">`, seconds)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">%s<title>%s</title>
+<style>body{font-family:system-ui,sans-serif;display:flex;min-height:100vh;align-items:center;justify-content:center;margin:0;color:#334155;background:#f8fafc}main{text-align:center;max-width:28rem;padding:2rem}h1{font-size:1.5rem;margin:0 0 .5rem}</style>
+</head><body><main><h1>%s</h1><p>%s</p></main></body></html>`,
+		refresh, html.EscapeString(title), html.EscapeString(title), html.EscapeString(message))
+}
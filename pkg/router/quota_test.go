@@ -0,0 +1,170 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestQuota_RouteCapRejects(t *testing.T) {
+	r := New()
+	r.Live("/demo", func() core.Component { return NewMockComponent() }, WithMaxSessions(1))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/demo", nil))
+		return rec
+	}
+
+	if rec := get(); rec.Code != http.StatusOK {
+		t.Fatalf("Expected first visitor to be admitted, got %d", rec.Code)
+	}
+	rec := get()
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("Expected 503 with Retry-After, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Server full") {
+		t.Errorf("Expected server full page, got %q", rec.Body.String())
+	}
+}
+
+func TestQuota_ReservationsAndSockets(t *testing.T) {
+	q := newQuotaState()
+	q.configure(QuotaConfig{MaxSessions: 2})
+	route := &LiveRoute{Path: "/"}
+
+	if _, _, ok := q.admit(route, ""); !ok {
+		t.Fatal("Expected admission")
+	}
+	if _, ok := q.acquire(route, "s1"); !ok {
+		t.Fatal("Expected the reserved socket to connect")
+	}
+	if _, ok := q.acquire(route, "s2"); !ok {
+		t.Fatal("Expected a reconnect to use the free slot")
+	}
+	if _, ok := q.acquire(route, "s3"); ok {
+		t.Fatal("Expected the global cap to reject a third socket")
+	}
+
+	q.release("s1")
+	q.release("s1")
+	if q.total != 1 || q.active["/"] != 1 {
+		t.Errorf("Expected release to be idempotent, total=%d active=%d", q.total, q.active["/"])
+	}
+}
+
+func TestQuota_Queue(t *testing.T) {
+	q := newQuotaState()
+	q.configure(QuotaConfig{Mode: OverflowQueue})
+	route := &LiveRoute{Path: "/", MaxSessions: 1}
+
+	q.admit(route, "")
+	q.acquire(route, "s1")
+
+	first, info, ok := q.admit(route, "")
+	if ok || first == nil || info.Position != 1 {
+		t.Fatalf("Expected to be queued first, got %+v %v", info, ok)
+	}
+	second, info, _ := q.admit(route, "")
+	if info.Position != 2 || info.Waiting != 2 {
+		t.Fatalf("Expected position 2 of 2, got %+v", info)
+	}
+
+	// A slot frees up: only the head of the queue is admitted.
+	q.release("s1")
+	if _, info, ok := q.admit(route, second.id); ok || info.Position != 2 {
+		t.Errorf("Expected second visitor to keep waiting, got %+v", info)
+	}
+	if _, _, ok := q.admit(route, first.id); !ok {
+		t.Error("Expected head of queue to be admitted")
+	}
+	if _, info, _ := q.admit(route, second.id); info.Position != 1 {
+		t.Errorf("Expected second visitor to move up, got %+v", info)
+	}
+}
+
+func TestDefaultOverflowHandler_Queue(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DefaultOverflowHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil), OverflowInfo{Position: 3, Waiting: 7})
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "number 3 of 7") || !strings.Contains(body, `http-equiv="refresh"`) {
+		t.Errorf("Unexpected queue page: %q", body)
+	}
+}
+
+func TestQuota_WaitingRoom(t *testing.T) {
+	q := newQuotaState()
+	q.configure(QuotaConfig{Mode: OverflowWaitingRoom})
+	route := &LiveRoute{Path: "/", MaxSessions: 1}
+
+	q.admit(route, "")
+	q.acquire(route, "s1")
+	tk, info, ok := q.admit(route, "")
+	if ok || info.Position != 1 {
+		t.Fatalf("Expected to be queued, got %+v", info)
+	}
+
+	socket := core.NewSocket("wr", nil)
+	room := &waitingRoom{quota: q, ticketID: tk.id, target: "/", render: DefaultWaitingRoom}
+	room.SetSocket(socket)
+	if err := room.Mount(context.Background(), nil, nil); err != nil || room.info.Position != 1 {
+		t.Fatalf("Expected waiting room to show position 1, got %+v", room.info)
+	}
+
+	// Freeing the slot admits the connected visitor without a page reload.
+	q.release("s1")
+	select {
+	case msg := <-socket.Info():
+		if u, ok := msg.(waitingUpdate); !ok || !u.admitted {
+			t.Fatalf("Expected admission, got %+v", msg)
+		}
+	default:
+		t.Fatal("Expected the waiting room to be notified")
+	}
+
+	if _, _, ok := q.admit(route, tk.id); !ok {
+		t.Error("Expected the admitted ticket to load the route")
+	}
+	if _, ok := q.acquire(route, "s2"); !ok {
+		t.Error("Expected the admitted socket to use its reservation")
+	}
+	if q.watchers != 0 {
+		t.Errorf("Expected no watchers left, got %d", q.watchers)
+	}
+}
+
+func TestQuota_WaitingRoomPage(t *testing.T) {
+	r := New()
+	r.SetQuota(QuotaConfig{Mode: OverflowWaitingRoom})
+	r.Live("/", func() core.Component { return NewMockComponent() }, WithMaxSessions(1))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	body := rec.Body.String()
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(body, `data-live-view="waiting-room"`) ||
+		!strings.Contains(body, `data-slot="waiting-position">1<`) {
+		t.Errorf("Unexpected waiting room page: %d %q", rec.Code, body)
+	}
+
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == QueueCookie {
+			cookie = c
+		}
+	}
+	if cookie == nil {
+		t.Fatal("Expected a queue ticket cookie")
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?x=1", nil)
+	req.AddCookie(cookie)
+	if wr := r.waitingRoomFor(req, r.liveRoutes["/"]); wr == nil || wr.target != "/?x=1" {
+		t.Errorf("Expected socket requests with the ticket to get the waiting room, got %+v", wr)
+	}
+}
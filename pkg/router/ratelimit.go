@@ -0,0 +1,63 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/limits"
+)
+
+// eventLimit limits the events sockets send (see SetEventRateLimit).
+type eventLimit struct {
+	limiter *limits.Limiter
+	keyFunc limits.KeyFunc // nil keys buckets by socket
+}
+
+// SetEventRateLimit limits the user events each socket may send, so a
+// client cannot flood HandleEvent: limits.MessageRateLimitConfig allows
+// 100 events per second with bursts of 100. Events over the limit are not
+// handled and the client gets an error reply for them; joins, heartbeats
+// and navigation are never limited.
+//
+// Buckets are per socket unless config.KeyFunc is set: it is called with
+// the socket's connection request, so limits.ByIP shares one bucket
+// between all the sockets of an address. The config's Store and Name are
+// used as for HTTP requests.
+func (r *Router) SetEventRateLimit(config limits.RateLimitConfig) {
+	if config.Name == "" {
+		config.Name = "events"
+	}
+	keyFunc := config.KeyFunc
+	if keyFunc == nil {
+		// Set to keep NewLimiter from defaulting to ByIP; never called
+		config.KeyFunc = func(*http.Request) string { return "" }
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventLimit = &eventLimit{limiter: limits.NewLimiter(config), keyFunc: keyFunc}
+}
+
+// eventLimitKey returns the bucket key of a socket connected by req.
+func (r *Router) eventLimitKey(req *http.Request, socketID string) string {
+	r.mu.RLock()
+	limit := r.eventLimit
+	r.mu.RUnlock()
+	if limit == nil || limit.keyFunc == nil {
+		return "socket:" + socketID
+	}
+	return limit.keyFunc(req)
+}
+
+// allowEvent takes a token for a user event of session. Events are let
+// through when the Store fails.
+func (r *Router) allowEvent(ctx context.Context, session *LiveViewSession) bool {
+	r.mu.RLock()
+	limit := r.eventLimit
+	r.mu.RUnlock()
+	if limit == nil {
+		return true
+	}
+	res, err := limit.limiter.Allow(ctx, session.rateKey)
+	return err != nil || res.Allowed
+}
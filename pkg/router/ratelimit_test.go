@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/limits"
+)
+
+// countingView counts the events it handles.
+type countingView struct {
+	core.BaseComponent
+	handled *atomic.Int32
+}
+
+func (v *countingView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	v.handled.Add(1)
+	return nil
+}
+
+func (v *countingView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<div data-slot="n">%d</div>`, v.handled.Load())
+		return err
+	})
+}
+
+func TestRouter_EventRateLimit(t *testing.T) {
+	var handled atomic.Int32
+	r := New()
+	r.SetEventRateLimit(limits.RateLimitConfig{Rate: 3, Period: time.Hour})
+	r.Live("/", func() core.Component { return &countingView{handled: &handled} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect joins a socket and sends it n events, returning the refs of
+	// the events that were rejected
+	connect := func(n int) []string {
+		ws, _, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ws.CloseNow()
+		wsjson.Write(ctx, ws, map[string]any{"ref": "0", "topic": "lv:t", "event": "phx_join", "payload": map[string]any{}})
+		for i := 1; i <= n; i++ {
+			wsjson.Write(ctx, ws, map[string]any{"ref": fmt.Sprint(i), "topic": "lv:t", "event": "inc", "payload": map[string]any{}})
+		}
+		// A heartbeat is never limited and is answered after the events
+		wsjson.Write(ctx, ws, map[string]any{"ref": "hb", "topic": "phoenix", "event": "heartbeat", "payload": map[string]any{}})
+
+		var rejected []string
+		for {
+			var msg struct {
+				Ref     string `json:"ref"`
+				Event   string `json:"event"`
+				Payload struct {
+					Status   string         `json:"status"`
+					Response map[string]any `json:"response"`
+				} `json:"payload"`
+			}
+			if err := wsjson.Read(ctx, ws, &msg); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if msg.Ref == "hb" {
+				return rejected
+			}
+			if msg.Event == "phx_reply" && msg.Payload.Status == "error" {
+				if reason := msg.Payload.Response["reason"]; reason != limits.ErrRateLimitExceeded.Error() {
+					t.Errorf("reason = %v", reason)
+				}
+				rejected = append(rejected, msg.Ref)
+			}
+		}
+	}
+
+	if rejected := connect(5); fmt.Sprint(rejected) != "[4 5]" {
+		t.Errorf("rejected events %v, want [4 5]", rejected)
+	}
+	if n := handled.Load(); n != 3 {
+		t.Errorf("handled %d events, want 3", n)
+	}
+
+	// Buckets are per socket
+	if rejected := connect(1); len(rejected) != 0 {
+		t.Errorf("new socket had events rejected: %v", rejected)
+	}
+}
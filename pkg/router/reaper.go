@@ -0,0 +1,53 @@
+package router
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultReapInterval is how often a router looks for idle sessions.
+// Session TTLs shorter than it are checked every TTL.
+const DefaultReapInterval = time.Minute
+
+// startReaper runs the session manager's reaper (see
+// LiveViewSessionManager.RunReaper) from the first live session until
+// Shutdown. Nothing is reaped while the SessionTTL is 0.
+func (r *Router) startReaper() {
+	ttl := r.sessionManager.ttl()
+	if ttl <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopReaper != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.stopReaper = cancel
+	go r.sessionManager.RunReaper(ctx, min(ttl, DefaultReapInterval))
+}
+
+// reapNotice asks an idle session's message loop to close the session.
+type reapNotice struct{}
+
+// reap closes a session whose client went silent (see
+// LiveViewSessionManager.Cleanup). It is closed on its message loop, after
+// the event it is handling, unless the loop is gone.
+func (r *Router) reap(session *LiveViewSession) {
+	if session.Socket == nil || session.Socket.SendInfo(reapNotice{}) != nil {
+		session.reaped.Store(true)
+		r.handleDisconnect(session)
+	}
+}
+
+// reapIdle handles a reapNotice: the session is closed, its component
+// terminated with core.TerminateTimeout, unless the client was heard from
+// since it was found idle.
+func (r *Router) reapIdle(session *LiveViewSession) {
+	if !r.sessionManager.idle(session) {
+		return
+	}
+	session.reaped.Store(true)
+	r.handleDisconnect(session)
+}
@@ -0,0 +1,167 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestRouter_ReapsIdleSessions(t *testing.T) {
+	reasons := make(chan core.TerminateReason, 4)
+	r := New()
+	r.Live("/", func() core.Component { return &clusterCounter{reasons: reasons} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	idle := dialLive(t, ctx, server.URL)
+	defer idle.CloseNow()
+	active := dialLive(t, ctx, server.URL)
+	defer active.CloseNow()
+	// Set after the reaper started at its default interval, so only
+	// Cleanup below reaps
+	r.SessionManager().SetSessionTTL(100 * time.Millisecond)
+
+	time.Sleep(150 * time.Millisecond)
+	heartbeat := map[string]any{"ref": "2", "topic": "phoenix", "event": "heartbeat", "payload": map[string]any{}}
+	wsjson.Write(ctx, active, heartbeat)
+	var reply map[string]any
+	wsjson.Read(ctx, active, &reply)
+
+	if n := r.SessionManager().Cleanup(); n != 1 {
+		t.Fatalf("Expected 1 idle session, got %d", n)
+	}
+	select {
+	case reason := <-reasons:
+		if reason != core.TerminateTimeout {
+			t.Errorf("Expected the idle component terminated with timeout, got %v", reason)
+		}
+	case <-ctx.Done():
+		t.Fatal("idle component not terminated")
+	}
+	for r.SessionManager().Count() != 1 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Expected only the active session left, got %d", r.SessionManager().Count())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if _, _, err := idle.Read(ctx); err == nil {
+		t.Error("Expected the idle session's connection closed")
+	}
+}
+
+// messageLoops counts the goroutines running a message loop of r.
+func messageLoops(r *Router) int {
+	buf := make([]byte, 1<<20)
+	frame := fmt.Sprintf("router.(*Router).messageLoop(%p", r)
+	return bytes.Count(buf[:runtime.Stack(buf, true)], []byte(frame))
+}
+
+func TestRouter_ReapEndsMessageLoops(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &snapCounter{} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		ws := dialLive(t, ctx, server.URL)
+		defer ws.CloseNow()
+		ws.CloseRead(ctx) // only answers the close handshake, like a stale proxy
+	}
+	if got := messageLoops(r); got != 5 {
+		t.Fatalf("Expected 5 message loops, got %d", got)
+	}
+	r.SessionManager().SetSessionTTL(50 * time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	r.SessionManager().Cleanup()
+	for messageLoops(r) > 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Expected the message loops of reaped sessions to end, %d left", messageLoops(r))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if got := r.SessionManager().Count(); got != 0 {
+		t.Errorf("Expected no live sessions, got %d", got)
+	}
+}
+
+// reapers counts the goroutines running the reaper of r's session manager.
+func reapers(r *Router) int {
+	buf := make([]byte, 1<<20)
+	frame := fmt.Sprintf("router.(*LiveViewSessionManager).RunReaper(%p", r.SessionManager())
+	return bytes.Count(buf[:runtime.Stack(buf, true)], []byte(frame))
+}
+
+func TestRouter_RunsReaperUntilShutdown(t *testing.T) {
+	reasons := make(chan core.TerminateReason, 2)
+	r := New()
+	r.SessionManager().SetSessionTTL(50 * time.Millisecond)
+	r.Live("/", func() core.Component { return &clusterCounter{reasons: reasons} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	if got := reapers(r); got != 0 {
+		t.Fatalf("Expected no reaper before the first session, got %d", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, server.URL)
+	defer ws.CloseNow()
+	ws.CloseRead(ctx)
+	dialLive(t, ctx, server.URL).CloseRead(ctx) // starts no second reaper
+
+	// Reaped without calling Cleanup
+	select {
+	case reason := <-reasons:
+		if reason != core.TerminateTimeout {
+			t.Errorf("Expected the idle component terminated with timeout, got %v", reason)
+		}
+	case <-ctx.Done():
+		t.Fatal("idle session not reaped")
+	}
+	if got := reapers(r); got != 1 {
+		t.Errorf("Expected one reaper, got %d", got)
+	}
+
+	if err := r.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	for reapers(r) > 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("Expected the reaper to stop on Shutdown")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestRouter_NoReaperWithoutTTL(t *testing.T) {
+	r := New()
+	r.SessionManager().SetSessionTTL(0)
+	r.Live("/", func() core.Component { return &snapCounter{} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, server.URL)
+	defer ws.CloseNow()
+
+	if got := reapers(r); got != 0 {
+		t.Errorf("Expected no reaper with a zero TTL, got %d", got)
+	}
+}
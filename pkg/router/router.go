@@ -6,16 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
 	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+	"github.com/gabrielmiguelok/golivekit/pkg/limits"
+	"github.com/gabrielmiguelok/golivekit/pkg/metrics"
 	"github.com/gabrielmiguelok/golivekit/pkg/pool"
 	"github.com/gabrielmiguelok/golivekit/pkg/protocol"
 	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+	"github.com/gabrielmiguelok/golivekit/pkg/recovery"
 	"github.com/gabrielmiguelok/golivekit/pkg/security"
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
 	"github.com/gabrielmiguelok/golivekit/pkg/transport"
 )
 
@@ -48,6 +55,59 @@ type Router struct {
 	// PubSub for real-time messaging
 	pubsub pubsub.PubSub
 
+	// Session caps and overflow queues
+	quota *quotaState
+
+	// Slot streaming (see SetSlotStreaming)
+	streamThreshold int
+	streamChunkSize int
+
+	// Full-render fallback ratio (see SetDiffBudget), and whether full
+	// renders are sent as deltas (see SetFullRenderDelta)
+	diffBudget float64
+	fullDelta  bool
+
+	// Minimum size of text slots sent as splices (see SetTextSlotSplicing)
+	spliceMinSize int
+
+	// Signer of lv-value-* attributes (see SetValueSigning)
+	valueSigner *security.ValueSigner
+
+	// Shared event loop (see SetEventLoop)
+	events *eventLoop
+
+	// Stops the idle session reaper (see startReaper)
+	stopReaper context.CancelFunc
+
+	// Rolling deploys and shutdown (see Drain and Shutdown)
+	recovery    *recovery.RecoveryManager
+	draining    bool
+	drainNotice drainNotice
+
+	// Sessions shared across nodes (see SetCluster)
+	cluster *Cluster
+
+	// Expected client build (see SetClientVersion)
+	clientVersion string
+
+	// Cross-origin socket origins (see SetAllowedOrigins)
+	allowedOrigins []string
+
+	// Per-socket event throughput (see SetEventRateLimit)
+	eventLimit *eventLimit
+
+	// Per-session usage thresholds (see SetUsageAlert)
+	usageAlert *usageAlert
+
+	// Send queue bounds (see SetBackpressure)
+	backpressure BackpressureConfig
+
+	// Open SSE streams by socket ID, for their POSTed messages
+	sseStreams sync.Map
+
+	// Groups of the tabs of each browser (see core.Socket.PushShared)
+	tabGroups tabGroups
+
 	mu sync.RWMutex
 }
 
@@ -70,6 +130,19 @@ type LiveRoute struct {
 
 	// Meta contains route metadata.
 	Meta map[string]any
+
+	// MaxSessions caps the route's live sessions (0 = unlimited).
+	MaxSessions int
+
+	// Parent is the route this one stacks over (WithParent).
+	Parent string
+
+	// OnMount are hooks run before the component mounts (WithOnMount,
+	// RouteGroup.OnMount).
+	OnMount []OnMountHook
+
+	// SingleSession keeps one live session per user (WithSingleSession).
+	SingleSession bool
 }
 
 // Middleware is a function that wraps an HTTP handler.
@@ -80,7 +153,7 @@ type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
 
 // New creates a new router.
 func New() *Router {
-	return &Router{
+	r := &Router{
 		mux:        http.NewServeMux(),
 		liveRoutes: make(map[string]*LiveRoute),
 		middleware: make([]Middleware, 0),
@@ -92,12 +165,21 @@ func New() *Router {
 		codec:          protocol.NewPhoenixCodec(),
 		diffEngine:     diff.NewEngine(),
 		pubsub:         pubsub.NewMemoryPubSub(),
+		quota:          newQuotaState(),
+
+		streamThreshold: DefaultSlotStreamThreshold,
+		streamChunkSize: DefaultSlotChunkSize,
+		diffBudget:      DefaultDiffBudget,
+		fullDelta:       true,
+		clientVersion:   defaultClientVersion(),
 
 		errorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		},
 		notFound: http.NotFoundHandler(),
 	}
+	r.sessionManager.setReaper(r.reap)
+	return r
 }
 
 // Use adds middleware to the router.
@@ -117,6 +199,16 @@ func (r *Router) SetNotFoundHandler(handler http.Handler) {
 	r.notFound = handler
 }
 
+// SetAllowedOrigins lets pages on other origins open live sockets, e.g.
+// sites embedding routes with <golive-view>. Origins are full URLs
+// ("https://shop.example.com"); "*" allows any origin. By default only
+// same-origin sockets are accepted.
+func (r *Router) SetAllowedOrigins(origins ...string) {
+	r.mu.Lock()
+	r.allowedOrigins = origins
+	r.mu.Unlock()
+}
+
 // Registry returns the component registry.
 func (r *Router) Registry() *core.ComponentRegistry {
 	return r.registry
@@ -142,6 +234,16 @@ func (r *Router) SetPubSub(ps pubsub.PubSub) {
 	r.pubsub = ps
 }
 
+// subscribe is the core.Subscriber installed on every socket, so
+// Socket.Subscribe delivers the router's pubsub messages to HandleInfo.
+func (r *Router) subscribe(topic string, deliver func([]byte)) (func(), error) {
+	sub, err := r.PubSub().Subscribe(topic, deliver)
+	if err != nil {
+		return nil, err
+	}
+	return func() { sub.Unsubscribe() }, nil
+}
+
 // Live registers a LiveView route.
 func (r *Router) Live(path string, component func() core.Component, opts ...RouteOption) {
 	route := &LiveRoute{
@@ -154,6 +256,7 @@ func (r *Router) Live(path string, component func() core.Component, opts ...Rout
 	for _, opt := range opts {
 		opt(route)
 	}
+	r.checkParent(route)
 
 	r.mu.Lock()
 	r.liveRoutes[path] = route
@@ -235,69 +338,158 @@ func (r *Router) handleLive(route *LiveRoute) http.HandlerFunc {
 
 // renderLive renders a LiveView component.
 func (r *Router) renderLive(w http.ResponseWriter, req *http.Request, route *LiveRoute) {
-	// If this is a WebSocket upgrade request, handle separately
-	if isWebSocketRequest(req) {
-		component := route.Component()
-		r.handleWebSocket(w, req, component)
+	// Messages POSTed by SSE clients
+	if isSSEPost(req) {
+		r.handleSSEPost(w, req)
+		return
+	}
+
+	// If this is a WebSocket upgrade or SSE stream request, handle separately
+	if isWebSocketRequest(req) || isSSERequest(req) {
+		connect := r.handleWebSocket
+		if isSSERequest(req) {
+			connect = r.handleSSE
+		}
+		// A draining instance sends new sockets back to the load balancer
+		if r.refuseDraining(w) {
+			return
+		}
+		// Queued visitors connect to the waiting room instead
+		if wr := r.waitingRoomFor(req, route); wr != nil {
+			connect(w, req, nil, wr)
+			return
+		}
+		component := r.newComponent(route)
+		connect(w, req, route, component)
+		return
+	}
+
+	// Enforce session caps before mounting
+	if !r.checkQuota(w, req, route) {
 		return
 	}
 
 	// Create component instance for initial HTTP render
-	component := route.Component()
+	component := r.newComponent(route)
 
 	// Extract params from URL
-	params := extractParams(req)
+	params := r.routeParams(route, req.URL)
 
 	// Get session data
 	session := r.extractSession(req)
 
-	// Create context
-	ctx := req.Context()
+	// Create context. Templates read the session from it (CSRF tokens).
+	ctx := r.withValueSigner(core.WithSession(req.Context(), session))
 
-	// Mount the component
+	// Run the route's on-mount hooks, then mount the component
+	if err := runOnMount(ctx, route, params, session); err != nil {
+		r.mountError(w, req, err)
+		return
+	}
 	if err := component.Mount(ctx, params, session); err != nil {
+		r.mountError(w, req, err)
+		return
+	}
+
+	document, err := renderDocument(ctx, component)
+	if err != nil {
 		r.errorHandler(w, req, err)
 		return
 	}
 
+	// Set content type
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, document)
+}
+
+// renderDocument renders a mounted component as a page, with its co-located
+// styles and document head injected.
+func renderDocument(ctx context.Context, component core.Component) (string, error) {
+	// Collect co-located component styles during the render
+	styles := core.NewStyleCollector()
+	if sp, ok := component.(core.StyleProvider); ok {
+		styles.Add(sp.Styles()...)
+	}
+	ctx = core.WithStyleCollector(ctx, styles)
+
 	// Render the component
 	renderer := component.Render(ctx)
 	if renderer == nil {
-		r.errorHandler(w, req, ErrNilRenderer)
-		return
+		return "", ErrNilRenderer
 	}
 
-	// Set content type
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf := pool.GetBuffer()
+	defer pool.PutBuffer(buf)
 
 	// Render HTML
-	if err := renderer.Render(ctx, w); err != nil {
-		r.errorHandler(w, req, err)
-		return
+	if err := renderer.Render(ctx, buf); err != nil {
+		return "", err
 	}
+
+	document := styles.Inject(buf.String())
+	if hp, ok := component.(core.HeadProvider); ok {
+		document = hp.Head().Inject(document)
+	}
+	return document, nil
 }
 
-// handleWebSocket handles WebSocket upgrade for LiveView.
-func (r *Router) handleWebSocket(w http.ResponseWriter, req *http.Request, component core.Component) {
-	// 1. Create WebSocket transport
-	wsTransport := transport.NewWebSocketTransport(transport.DefaultTransportConfig())
+// handleWebSocket handles WebSocket upgrade for LiveView. A nil route
+// connects the component without taking a session slot (waiting rooms).
+func (r *Router) handleWebSocket(w http.ResponseWriter, req *http.Request, route *LiveRoute, component core.Component) {
+	// 1. Generate socket ID and take a session slot
+	socketID := generateSocketID()
+	if !r.acquireSlot(w, route, socketID) {
+		return
+	}
+
+	// 2. Create WebSocket transport
+	loop := r.loop()
+	config := transport.DefaultTransportConfig()
+	if loop != nil {
+		loop.configure(config)
+	}
+	r.configureQueue(config, socketID)
+	r.mu.RLock()
+	wsConfig := &transport.WebSocketConfig{AllowedOrigins: r.allowedOrigins}
+	r.mu.RUnlock()
+	wsTransport := transport.NewWebSocketTransportWithConfig(config, wsConfig)
 
-	// 2. Upgrade connection
+	// 3. Upgrade connection
 	if err := wsTransport.Upgrade(w, req); err != nil {
+		r.quota.release(socketID)
 		r.errorHandler(w, req, fmt.Errorf("websocket upgrade failed: %w", err))
 		return
 	}
 
-	// 3. Generate socket ID
-	socketID := generateSocketID()
+	r.startSession(req, route, component, socketID, wsTransport, loop)
+}
+
+// acquireSlot takes a session slot on route for socketID, answering 503
+// when the route is full. Waiting rooms (nil route) are not capped.
+func (r *Router) acquireSlot(w http.ResponseWriter, route *LiveRoute, socketID string) bool {
+	if route == nil {
+		return true
+	}
+	if info, ok := r.quota.acquire(route, socketID); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(info.RetryAfter.Seconds())))
+		http.Error(w, "server full", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
 
+// startSession wires a connected stream to a new LiveView session and
+// starts processing its messages.
+func (r *Router) startSession(req *http.Request, route *LiveRoute, component core.Component, socketID string, stream transport.Stream, loop *eventLoop) {
 	// 4. Create adapter and socket
-	adapter := NewTransportAdapter(wsTransport, r.codec)
+	adapter := NewTransportAdapter(stream, r.codec)
 	socket := core.NewSocket(socketID, adapter)
+	socket.SetSubscriber(r.subscribe)
+	socket.SetConnectInfo(core.ConnectInfo{IP: limits.GetClientIP(req), UserAgent: req.UserAgent()})
 
 	// 5. Extract session/params
 	session := r.extractSession(req)
-	params := extractParams(req)
+	params := r.routeParams(route, req.URL)
 
 	// 6. Wire component to socket if it supports it
 	if bc, ok := component.(interface{ SetSocket(*core.Socket) }); ok {
@@ -306,69 +498,186 @@ func (r *Router) handleWebSocket(w http.ResponseWriter, req *http.Request, compo
 
 	// 7. Create LiveView session
 	lvSession := r.sessionManager.Create(socketID, component, params, session)
-	lvSession.Transport = wsTransport
+	lvSession.Transport = stream
+	lvSession.Route = route
 	lvSession.Socket = socket
 	lvSession.DiffEngine = r.diffEngine
 	lvSession.Codec = r.codec
+	lvSession.rateKey = r.eventLimitKey(req, socketID)
 
 	// 8. Add socket to manager
 	r.socketManager.Add(socket)
+	r.startReaper()
 
 	// 9. Start message loop in a goroutine
 	// NOTE: Use context.Background() instead of req.Context() because
 	// the WebSocket connection outlives the HTTP request. req.Context()
 	// is canceled when the HTTP handler returns, but the WebSocket
-	// connection should stay alive.
-	ctx := core.BuildContext(context.Background(), socket, component, session, params)
-	go r.messageLoop(ctx, lvSession)
+	// connection should stay alive. (SSE streams end with the request,
+	// and their CloseChan with it.)
+	base := context.Background()
+	if id := tracing.TraceID(req.Context()); id != "" {
+		// Renders of the session link to the trace of its upgrade request
+		base = tracing.WithTraceID(base, id)
+	}
+	lvSession.base, lvSession.cancel = context.WithCancel(base)
+	lvSession.ctx = r.withValueSigner(core.BuildContext(lvSession.base, socket, component, session, params))
+	if loop != nil {
+		// Shared workers process the session when it has work
+		loop.attach(lvSession)
+		return
+	}
+	go r.messageLoop(lvSession)
 
 	// 10. Cleanup on disconnect
 	go func() {
-		<-wsTransport.CloseChan()
+		<-stream.CloseChan()
 		r.handleDisconnect(lvSession)
 	}()
 }
 
-// messageLoop processes incoming WebSocket messages.
-func (r *Router) messageLoop(ctx context.Context, session *LiveViewSession) {
+// messageLoop processes incoming WebSocket messages until the session is
+// closed. Each one is handled with the session's context, which live
+// navigation replaces.
+func (r *Router) messageLoop(session *LiveViewSession) {
 	recvCh := session.Transport.Receive()
+	infoCh := session.Socket.Info()
+	closed := session.base.Done()
 
 	for {
 		select {
+		case <-closed:
+			return
+
+		case info := <-infoCh:
+			r.handleInfo(session.ctx, session, info)
+
 		case msg, ok := <-recvCh:
 			if !ok {
 				// Channel closed, connection ended
 				return
 			}
+			if !r.handleMessage(session.ctx, session, msg) {
+				return
+			}
+		}
+	}
+}
 
-			// Update activity
-			session.UpdateActivity()
+// handleInfo delivers a server-side message (ticker, pubsub, etc.) to the
+// component and sends the resulting diff.
+func (r *Router) handleInfo(ctx context.Context, session *LiveViewSession, info any) {
+	if notice, ok := info.(drainNotice); ok {
+		r.sendReconnect(ctx, session, notice)
+		return
+	}
+	if notice, ok := info.(takeoverNotice); ok {
+		r.handOff(session, notice.reason)
+		return
+	}
+	if _, ok := info.(reapNotice); ok {
+		r.reapIdle(session)
+		return
+	}
+	if !session.IsMounted() {
+		return
+	}
+	if req, ok := info.(core.RenderRequest); ok {
+		req.Done()
+		r.renderUnlessHidden(ctx, session)
+		return
+	}
+	if req, ok := info.(core.UpdateRequest); ok {
+		req.Run()
+		r.renderUnlessHidden(ctx, session)
+		return
+	}
+	if b, ok := info.(core.Broadcast); ok {
+		// Continue the chain of the event that published the message
+		if b.CorrelationID != "" {
+			ctx = tracing.WithCorrelationID(ctx, b.CorrelationID)
+		}
+		if b.TraceID != "" {
+			ctx = tracing.WithTraceID(ctx, b.TraceID)
+		}
+	}
+	start := time.Now()
+	err := session.Component.HandleInfo(ctx, info)
+	r.recordEvent(session, start)
+	if err != nil {
+		r.reportInfoError(ctx, session, err)
+		return
+	}
+	r.renderUnlessHidden(ctx, session)
+	r.flushCookieSession(session)
+}
 
-			// Handle message based on event
-			switch msg.Event {
-			case "heartbeat", "phx_heartbeat":
-				r.handleHeartbeat(session, msg)
+// flushCookieSession sends the client the cookie session changed by the
+// component, for it to post to the store's flush path: a socket cannot set
+// cookies.
+func (r *Router) flushCookieSession(session *LiveViewSession) {
+	cs, ok := session.Session[security.CookieSessionKey].(*security.CookieSession)
+	if !ok {
+		return
+	}
+	payload, err := cs.FlushPayload()
+	if err != nil || payload == nil {
+		return
+	}
+	session.Socket.Push(security.CookieSessionEvent, payload)
+}
 
-			case "phx_join":
-				r.handleJoin(ctx, session, msg)
+// handleMessage handles one client message. It returns false when the
+// client left.
+func (r *Router) handleMessage(ctx context.Context, session *LiveViewSession, msg transport.Message) bool {
+	// Update activity
+	session.UpdateActivity()
 
-			case "phx_leave":
-				r.handleLeave(session, msg)
-				return
+	// Handle message based on event
+	switch msg.Event {
+	case "heartbeat", "phx_heartbeat":
+		r.handleHeartbeat(session, msg)
 
-			default:
-				// User event (click, change, submit, etc.)
-				if err := r.dispatchEvent(ctx, session, msg); err != nil {
-					r.sendError(session, msg.Ref, msg.Topic, err)
-					continue
-				}
-				r.renderAndSendDiff(ctx, session)
-			}
+	case "phx_join":
+		r.handleJoin(ctx, session, msg)
 
-		case <-ctx.Done():
-			return
+	case "phx_leave":
+		r.handleLeave(session, msg)
+		return false
+
+	case core.ParamsEvent:
+		r.handleParams(ctx, session, msg)
+
+	case core.VisibilityEvent:
+		r.handleVisibility(ctx, session, msg)
+
+	case BatchEvent:
+		r.handleBatch(ctx, session, msg)
+
+	default:
+		// User event (click, change, submit, etc.). Events for a
+		// LiveComponent re-render that child only.
+		if !r.allowEvent(ctx, session) {
+			r.sendError(session, msg.Ref, msg.Topic, limits.ErrRateLimitExceeded)
+			return true
+		}
+		child := eventTarget(session, msg)
+		if err := r.dispatchEvent(ctx, session, child, msg); err != nil {
+			r.sendEventError(ctx, session, msg, err)
+			return true
+		}
+		reply := session.Socket.TakeReply()
+		if child != nil {
+			r.renderLiveComponent(ctx, session, child)
+		} else {
+			r.renderAndSendDiff(ctx, session)
+		}
+		r.flushCookieSession(session)
+		if reply != nil {
+			r.sendEventReply(session, msg, reply)
 		}
 	}
+	return true
 }
 
 // handleJoin handles the phx_join event.
@@ -384,13 +693,63 @@ func (r *Router) handleJoin(ctx context.Context, session *LiveViewSession, msg t
 		session.SetJoinRef(joinRef)
 	}
 
+	// Capture the client timezone so Mount can render local times
+	tz, _ := msg.Payload["timezone"].(string)
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		tz = ""
+	}
+	if tz != "" && session.Session != nil {
+		session.Session.SetLocation(loc)
+	}
+	setJoinInfo(session.Socket, tz, msg.Payload)
+	r.joinTabGroup(session, msg.Payload)
+
+	// Old clients reload instead of joining after a deploy
+	if !r.checkClientVersion(session, msg.Payload) {
+		return
+	}
+	// Clients joining an instance that is going away are sent elsewhere
+	if !session.IsMounted() && r.refuseJoin(session) {
+		return
+	}
+
 	// Mount component if not already mounted
 	if !session.IsMounted() {
+		if err := runOnMount(ctx, session.Route, session.Params, session.Session); err != nil {
+			r.sendMountError(session, msg, err)
+			return
+		}
 		if err := component.Mount(ctx, session.Params, session.Session); err != nil {
-			r.sendError(session, msg.Ref, msg.Topic, err)
+			r.sendMountError(session, msg, err)
 			return
 		}
+		// Restore the state of the connection this client lost
+		recovered := false
+		if token, ok := msg.Payload["session"].(string); ok && token != "" {
+			recovered = r.sessionManager.Recover(token, session)
+		}
+		// Or take the session over from the user's previous tab
+		r.sessionManager.supersede(session, !recovered)
+		// Restore the state carried over from a draining instance
+		if token, ok := msg.Payload["recovery"].(string); ok && token != "" {
+			r.restoreSession(ctx, session, token)
+		}
+		// Props from the host page are applied before the first render
+		if props, ok := msg.Payload["props"].(map[string]any); ok {
+			if err := component.HandleEvent(ctx, core.PropsEvent, props); err != nil {
+				r.sendEventError(ctx, session, msg, err)
+				return
+			}
+		}
 		session.SetMounted(true)
+		r.claimSession(session)
+		r.flushCookieSession(session)
+	}
+
+	// Slots marked while mounting do not restrict the first diff
+	if dt, ok := component.(core.DirtyTracker); ok {
+		dt.TakeDirty()
 	}
 
 	// Initial render
@@ -409,12 +768,38 @@ func (r *Router) handleJoin(ctx context.Context, session *LiveViewSession, msg t
 		return
 	}
 
-	// Send join reply with rendered HTML
+	// Send join reply with rendered HTML, and the token the client sends
+	// back to recover the session if the connection drops. The client
+	// keeps the HTML as the base of the next full render's delta.
+	r.trackFullBase(session, buf.String())
 	r.sendReply(session, msg.Ref, msg.Topic, map[string]any{
 		"rendered": map[string]any{
 			"s": []string{buf.String()},
 		},
+		"session": session.Token,
 	})
+
+	r.syncHead(session)
+}
+
+// syncHead pushes the component's document head to the client when it
+// changed since the last push.
+func (r *Router) syncHead(session *LiveViewSession) {
+	hp, ok := session.Component.(core.HeadProvider)
+	if !ok {
+		return
+	}
+	head := hp.Head()
+	key := head.Key()
+
+	session.mu.Lock()
+	changed := key != session.headKey
+	session.headKey = key
+	session.mu.Unlock()
+
+	if changed {
+		session.Socket.SetHead(head)
+	}
 }
 
 // handleHeartbeat handles heartbeat messages.
@@ -425,13 +810,14 @@ func (r *Router) handleHeartbeat(session *LiveViewSession, msg transport.Message
 
 // handleLeave handles the phx_leave event.
 func (r *Router) handleLeave(session *LiveViewSession, msg transport.Message) {
+	session.left.Store(true)
 	ctx := context.Background()
 	session.Component.Terminate(ctx, core.TerminateNormal)
 	r.handleDisconnect(session)
 }
 
 // dispatchEvent dispatches a user event to the component.
-func (r *Router) dispatchEvent(ctx context.Context, session *LiveViewSession, msg transport.Message) error {
+func (r *Router) dispatchEvent(ctx context.Context, session *LiveViewSession, child core.LiveComponent, msg transport.Message) error {
 	event := msg.Event
 
 	// Extract value from payload if present
@@ -439,13 +825,31 @@ func (r *Router) dispatchEvent(ctx context.Context, session *LiveViewSession, ms
 	if payload == nil {
 		payload = make(map[string]any)
 	}
+	if len(msg.Binary) > 0 {
+		payload[core.BinaryKey] = msg.Binary
+	}
+	delete(payload, core.TargetKey)
+	if err := r.verifyValues(ctx, event, payload); err != nil {
+		return err
+	}
 
+	// Drop a reply left by a handler that failed or ran outside an event
+	session.Socket.TakeReply()
+	defer r.recordEvent(session, time.Now())
+
+	// Broadcasts from the handler carry the event's correlation ID
+	ctx = tracing.WithCorrelationID(ctx, tracing.NewCorrelationID())
+	if child != nil {
+		return child.HandleEvent(ctx, event, payload)
+	}
 	return session.Component.HandleEvent(ctx, event, payload)
 }
 
 // renderAndSendDiff renders the component and sends an optimized diff.
 // Uses buffer pool to reduce GC pressure.
 func (r *Router) renderAndSendDiff(ctx context.Context, session *LiveViewSession) {
+	start := time.Now()
+	defer r.recordRender(session, start)
 	component := session.Component
 
 	// 1. Try to get assigns and check for changes
@@ -456,39 +860,79 @@ func (r *Router) renderAndSendDiff(ctx context.Context, session *LiveViewSession
 	// - The actual diff will be computed by comparing rendered output
 	// - If nothing changed, the diff will be empty and won't be sent
 
-	// 2. Render the component
-	renderer := component.Render(ctx)
-	if renderer == nil {
-		return
-	}
+	// 2. Render the component. Precompiled components report their slots
+	// directly, so the HTML is not scanned.
+	var html string
+	var slots map[string]string
+	var trees map[string]*diff.Rendered
+	if sr, ok := component.(core.SlotRenderer); ok {
+		html, slots = sr.RenderSlots(ctx)
+	} else {
+		renderer := component.Render(ctx)
+		if renderer == nil {
+			return
+		}
 
-	// Use buffer from pool to reduce GC pressure
-	buf := pool.GetBuffer()
-	defer pool.PutBuffer(buf)
+		// Use buffer from pool to reduce GC pressure
+		buf := pool.GetBuffer()
+		defer pool.PutBuffer(buf)
 
-	if err := renderer.Render(ctx, buf); err != nil {
-		return
+		// Templates split into statics and dynamics send slot changes
+		// as the dynamics that changed
+		if tr, ok := renderer.(diff.TreeRenderer); ok {
+			var err error
+			if trees, err = tr.RenderTrees(ctx, buf); err != nil {
+				return
+			}
+		} else if err := renderer.Render(ctx, buf); err != nil {
+			return
+		}
+
+		html = buf.String()
 	}
 
-	html := buf.String()
+	// 3. Restrict the diff to the slots the component marked as dirty
+	partial := false
+	if dt, ok := component.(core.DirtyTracker); ok {
+		if dirty := dt.TakeDirty(); len(dirty) > 0 {
+			slots, partial = dirtySlots(html, slots, dirty)
+		}
+	}
 
 	// 4. Build optimized diff payload
-	payload := r.buildDiffPayload(ctx, session, component, html, assigns)
+	payload := r.buildDiffPayload(ctx, session, component, html, slots, trees, partial, assigns)
+
+	// Client commands queued by the handler (js.Exec) run after this diff
+	payload.JS = session.Socket.TakeJS()
+
+	// Very large HTML slots are streamed in chunks after the diff
+	var streams []slotStream
+	if session.Transport != nil {
+		streams = r.splitLargeSlots(payload)
+	}
 
 	// 5. Send diff (only if there's something to send)
-	if !payload.IsEmpty() {
+	if !payload.IsEmpty() || len(streams) > 0 {
+		metrics.RecordRenderContext(ctx, time.Since(start), estimateDiffSize(payload)+len(payload.Full))
 		session.Socket.SendOptimizedDiff(payload)
+		r.sendSlotStreams(session, payload.Version, streams)
 
 		// 6. Reset change tracker after successful send
 		if assigns != nil && assigns.Tracker().HasChanges() {
 			assigns.Tracker().Reset()
 		}
 	}
+
+	// 7. Keep the document head in sync (title, meta, links)
+	r.syncHead(session)
 }
 
 // buildDiffPayload constructs the optimized diff payload.
 // Uses hash-based comparison O(1) and per-socket state (no global lock contention).
-func (r *Router) buildDiffPayload(ctx context.Context, session *LiveViewSession, component core.Component, html string, assigns *core.Assigns) *core.DiffPayload {
+// slots holds precompiled slot contents; when nil they are extracted from html.
+// trees holds the statics and dynamics of the slots that have them.
+// A partial diff covers only the given slots and keeps the others' hashes.
+func (r *Router) buildDiffPayload(ctx context.Context, session *LiveViewSession, component core.Component, html string, slots map[string]string, trees map[string]*diff.Rendered, partial bool, assigns *core.Assigns) *core.DiffPayload {
 	// Get or increment version
 	session.mu.Lock()
 	session.Version++
@@ -502,12 +946,90 @@ func (r *Router) buildDiffPayload(ctx context.Context, session *LiveViewSession,
 	}
 
 	// Extract slots from rendered HTML using optimized O(n) parser
-	textSlots, htmlSlots := extractSlotsOptimized(html)
+	var textSlots, htmlSlots map[string]string
+	if slots != nil {
+		textSlots, htmlSlots = classifySlots(slots)
+	} else {
+		textSlots, htmlSlots = extractSlotsOptimized(html)
+	}
+
+	r.diffSlots(session, payload, textSlots, htmlSlots, partial)
+	r.diffTrees(session, payload, trees)
+	r.spliceTextSlots(session, payload)
+
+	// If no slots found, fallback to full render
+	if len(payload.Slots) == 0 && len(payload.HTMLSlots) == 0 && len(textSlots) == 0 && len(htmlSlots) == 0 {
+		payload.Full = html
+	}
+
+	// Handle list operations if component implements ListProvider
+	if lp, ok := component.(core.ListProvider); ok {
+		listOps := r.computeListOps(session.SocketID, lp)
+		if len(listOps) > 0 {
+			payload.ListOps = listOps
+		}
+	}
+
+	// Send the full render instead if it is smaller
+	r.applyDiffBudget(payload, html)
+
+	// A full render replaces the slots the client holds trees of
+	if payload.Full != "" {
+		session.setSlotTrees(nil)
+		session.setSlotTexts(nil)
+		r.deltaFull(session, payload)
+	}
+
+	return payload
+}
+
+// diffTrees moves the changed HTML slots that have a tree from payload's
+// HTML slots to its trees, as the dynamics the client's copy lacks. Slots
+// sent another way drop their tree, so the next change is sent whole.
+func (r *Router) diffTrees(session *LiveViewSession, payload *core.DiffPayload, trees map[string]*diff.Rendered) {
+	prev := session.getSlotTrees()
+	if len(trees) == 0 && len(prev) == 0 {
+		return
+	}
+
+	next := make(map[string]*diff.Rendered, len(prev))
+	for id, tree := range prev {
+		next[id] = tree
+	}
+	for id := range payload.Slots {
+		delete(next, id)
+	}
+	for id := range payload.HTMLSlots {
+		tree, ok := trees[id]
+		if !ok {
+			delete(next, id)
+			continue
+		}
+		if change, changed := diff.DiffRendered(prev[id], tree); changed {
+			if payload.Trees == nil {
+				payload.Trees = make(map[string]any)
+			}
+			payload.Trees[id] = change
+		}
+		delete(payload.HTMLSlots, id)
+		next[id] = tree
+	}
+	session.setSlotTrees(next)
+}
 
+// diffSlots adds the slots whose content changed since the last diff to
+// payload and records their hashes. A partial diff keeps the hashes of
+// the slots it does not cover.
+func (r *Router) diffSlots(session *LiveViewSession, payload *core.DiffPayload, textSlots, htmlSlots map[string]string, partial bool) {
 	// Get previous hashes from per-socket state (no global lock!)
 	prevHashes := session.GetSlotHashes()
 
 	newHashes := make(map[string]uint64, len(textSlots)+len(htmlSlots))
+	if partial {
+		for id, hash := range prevHashes {
+			newHashes[id] = hash
+		}
+	}
 
 	// Compare with hash O(1) instead of string O(n)
 	for id, content := range textSlots {
@@ -528,21 +1050,6 @@ func (r *Router) buildDiffPayload(ctx context.Context, session *LiveViewSession,
 
 	// Store new hashes in per-socket state (no global lock!)
 	session.SetSlotHashes(newHashes)
-
-	// If no slots found, fallback to full render
-	if len(payload.Slots) == 0 && len(payload.HTMLSlots) == 0 && len(textSlots) == 0 && len(htmlSlots) == 0 {
-		payload.Full = html
-	}
-
-	// Handle list operations if component implements ListProvider
-	if lp, ok := component.(core.ListProvider); ok {
-		listOps := r.computeListOps(session.SocketID, lp)
-		if len(listOps) > 0 {
-			payload.ListOps = listOps
-		}
-	}
-
-	return payload
 }
 
 // extractSlotsOptimized extracts data-slot content using O(n) single-pass parsing.
@@ -551,110 +1058,172 @@ func extractSlotsOptimized(html string) (textSlots, htmlSlots map[string]string)
 	textSlots = make(map[string]string)
 	htmlSlots = make(map[string]string)
 
-	const marker = `data-slot="`
-	markerLen := len(marker)
-	htmlLen := len(html)
 	pos := 0
-
-	for pos < htmlLen {
+	for pos < len(html) {
 		// Find next data-slot
-		idx := strings.Index(html[pos:], marker)
+		idx := strings.Index(html[pos:], slotMarker)
 		if idx == -1 {
 			break
 		}
 
-		slotStart := pos + idx + markerLen
+		slotID, content, next, ok := parseSlotAt(html, pos+idx)
+		if ok {
+			// Classify: simple text vs HTML content
+			if strings.ContainsAny(content, "<>") {
+				htmlSlots[slotID] = content
+			} else {
+				textSlots[slotID] = content
+			}
+		}
+		pos = next
+	}
+
+	return textSlots, htmlSlots
+}
 
-		// Extract slot ID (until next ")
-		slotEnd := strings.IndexByte(html[slotStart:], '"')
-		if slotEnd == -1 {
-			pos = slotStart
+// extractNamedSlots extracts the content of the given slots only, skipping
+// the rest of the HTML. Slots that are not found are left out.
+func extractNamedSlots(html string, ids []string) map[string]string {
+	slots := make(map[string]string, len(ids))
+	for _, id := range ids {
+		idx := strings.Index(html, slotMarker+id+`"`)
+		if idx == -1 {
 			continue
 		}
+		if _, content, _, ok := parseSlotAt(html, idx); ok {
+			slots[id] = content
+		}
+	}
+	return slots
+}
 
-		slotID := html[slotStart : slotStart+slotEnd]
+const slotMarker = `data-slot="`
 
-		// Find the tag start (search backwards for <)
-		tagStart := pos + idx
-		for tagStart > 0 && html[tagStart] != '<' {
-			tagStart--
-		}
+// parseSlotAt parses the slot whose data-slot attribute starts at markerIdx.
+// It returns the slot ID, its trimmed content and the position after the
+// slot's close tag (or after the attribute when the slot is malformed).
+func parseSlotAt(html string, markerIdx int) (slotID, content string, next int, ok bool) {
+	markerLen := len(slotMarker)
+	htmlLen := len(html)
+	slotStart := markerIdx + markerLen
 
-		// Extract tag name
-		tagNameEnd := tagStart + 1
-		for tagNameEnd < htmlLen && html[tagNameEnd] != ' ' && html[tagNameEnd] != '>' && html[tagNameEnd] != '/' {
-			tagNameEnd++
-		}
-		tagName := html[tagStart+1 : tagNameEnd]
+	// Extract slot ID (until next ")
+	slotEnd := strings.IndexByte(html[slotStart:], '"')
+	if slotEnd == -1 {
+		return "", "", slotStart, false
+	}
 
-		// Find the > of the opening tag
-		closeAngle := strings.IndexByte(html[slotStart+slotEnd:], '>')
-		if closeAngle == -1 {
-			pos = slotStart + slotEnd
-			continue
-		}
+	slotID = html[slotStart : slotStart+slotEnd]
 
-		contentStart := slotStart + slotEnd + closeAngle + 1
+	// Find the tag start (search backwards for <)
+	tagStart := markerIdx
+	for tagStart > 0 && html[tagStart] != '<' {
+		tagStart--
+	}
 
-		// Find matching close tag using depth counter (O(n) for this slot)
-		openTag := "<" + tagName
-		closeTag := "</" + tagName
-		openTagLen := len(openTag)
-		closeTagLen := len(closeTag)
+	// Extract tag name
+	tagNameEnd := tagStart + 1
+	for tagNameEnd < htmlLen && html[tagNameEnd] != ' ' && html[tagNameEnd] != '>' && html[tagNameEnd] != '/' {
+		tagNameEnd++
+	}
+	tagName := html[tagStart+1 : tagNameEnd]
 
-		depth := 1
-		searchPos := contentStart
-		contentEnd := -1
+	// Find the > of the opening tag
+	closeAngle := strings.IndexByte(html[slotStart+slotEnd:], '>')
+	if closeAngle == -1 {
+		return "", "", slotStart + slotEnd, false
+	}
 
-		for depth > 0 && searchPos < htmlLen {
-			nextOpen := strings.Index(html[searchPos:], openTag)
-			nextClose := strings.Index(html[searchPos:], closeTag)
+	contentStart := slotStart + slotEnd + closeAngle + 1
 
-			if nextClose == -1 {
-				break
-			}
+	// Find matching close tag using depth counter (O(n) for this slot)
+	openTag := "<" + tagName
+	closeTag := "</" + tagName
+	openTagLen := len(openTag)
+	closeTagLen := len(closeTag)
 
-			// Adjust relative indices
-			if nextOpen != -1 {
-				nextOpen += searchPos
-			} else {
-				nextOpen = htmlLen // No more open tags
-			}
-			nextClose += searchPos
-
-			if nextOpen < nextClose {
-				// Check if it's actually a tag (not part of text like "<span")
-				afterOpen := nextOpen + openTagLen
-				if afterOpen < htmlLen {
-					nextChar := html[afterOpen]
-					if nextChar == ' ' || nextChar == '>' || nextChar == '/' || nextChar == '\t' || nextChar == '\n' {
-						depth++
-					}
-				}
-				searchPos = nextOpen + openTagLen
-			} else {
-				depth--
-				if depth == 0 {
-					contentEnd = nextClose
+	depth := 1
+	searchPos := contentStart
+	contentEnd := -1
+
+	for depth > 0 && searchPos < htmlLen {
+		nextOpen := strings.Index(html[searchPos:], openTag)
+		nextClose := strings.Index(html[searchPos:], closeTag)
+
+		if nextClose == -1 {
+			break
+		}
+
+		// Adjust relative indices
+		if nextOpen != -1 {
+			nextOpen += searchPos
+		} else {
+			nextOpen = htmlLen // No more open tags
+		}
+		nextClose += searchPos
+
+		if nextOpen < nextClose {
+			// Check if it's actually a tag (not part of text like "<span")
+			afterOpen := nextOpen + openTagLen
+			if afterOpen < htmlLen {
+				nextChar := html[afterOpen]
+				if nextChar == ' ' || nextChar == '>' || nextChar == '/' || nextChar == '\t' || nextChar == '\n' {
+					depth++
 				}
-				searchPos = nextClose + closeTagLen
 			}
+			searchPos = nextOpen + openTagLen
+		} else {
+			depth--
+			if depth == 0 {
+				contentEnd = nextClose
+			}
+			searchPos = nextClose + closeTagLen
 		}
+	}
 
-		if contentEnd != -1 {
-			content := strings.TrimSpace(html[contentStart:contentEnd])
+	if contentEnd == -1 {
+		return "", "", searchPos, false
+	}
+	return slotID, strings.TrimSpace(html[contentStart:contentEnd]), searchPos, true
+}
 
-			// Classify: simple text vs HTML content
-			if strings.ContainsAny(content, "<>") {
-				htmlSlots[slotID] = content
-			} else {
-				textSlots[slotID] = content
+// dirtySlots narrows slots (or, when nil, the slots of html) to the dirty
+// ones. partial is false when none of them is found, so the diff falls back
+// to full extraction.
+func dirtySlots(html string, slots map[string]string, dirty []string) (map[string]string, bool) {
+	if slots == nil {
+		slots = extractNamedSlots(html, dirty)
+	} else {
+		named := make(map[string]string, len(dirty))
+		for _, id := range dirty {
+			if content, ok := slots[id]; ok {
+				named[id] = content
 			}
 		}
-
-		pos = searchPos
+		if len(named) == 0 {
+			return slots, false
+		}
+		slots = named
+	}
+	if len(slots) == 0 {
+		return nil, false
 	}
+	return slots, true
+}
 
+// classifySlots splits precompiled slot contents into text and HTML slots
+// the same way extractSlotsOptimized does.
+func classifySlots(slots map[string]string) (textSlots, htmlSlots map[string]string) {
+	textSlots = make(map[string]string)
+	htmlSlots = make(map[string]string)
+	for id, content := range slots {
+		content = strings.TrimSpace(content)
+		if strings.ContainsAny(content, "<>") {
+			htmlSlots[id] = content
+		} else {
+			textSlots[id] = content
+		}
+	}
 	return textSlots, htmlSlots
 }
 
@@ -869,13 +1438,29 @@ func (r *Router) clearListState(socketID string) {
 
 // handleDisconnect handles client disconnection.
 func (r *Router) handleDisconnect(session *LiveViewSession) {
+	r.disconnect(context.Background(), session)
+}
+
+// disconnect ends a session, terminating its component with ctx.
+func (r *Router) disconnect(ctx context.Context, session *LiveViewSession) {
+	if !session.disconnected.CompareAndSwap(false, true) {
+		return // closing the transport below ends the session twice
+	}
+
+	// Keep the state for the client to recover if it comes back
+	if !session.left.Load() {
+		r.sessionManager.Park(session)
+	}
+	r.releaseSession(session)
+	r.leaveTabGroup(session)
+
 	// Terminate component
-	ctx := context.Background()
-	session.Component.Terminate(ctx, core.TerminateShutdown)
+	session.Component.Terminate(ctx, terminateReason(session))
 
 	// Remove from managers
 	r.sessionManager.Remove(session.ID)
 	r.socketManager.Remove(session.SocketID)
+	r.quota.release(session.SocketID)
 
 	// Invalidate diff cache
 	r.diffEngine.InvalidateSocket(session.SocketID)
@@ -887,10 +1472,20 @@ func (r *Router) handleDisconnect(session *LiveViewSession) {
 	// Clear hash cache (new optimization)
 	r.clearSlotHashCache(session.SocketID)
 
+	// Leave pubsub topics
+	if session.Socket != nil {
+		session.Socket.Close()
+	}
+
 	// Close transport
 	if session.Transport != nil {
 		session.Transport.Close()
 	}
+
+	// End the message loop
+	if session.cancel != nil {
+		session.cancel()
+	}
 }
 
 // clearSlotHashCache removes hash cache for a socket (called on disconnect).
@@ -900,6 +1495,13 @@ func (r *Router) clearSlotHashCache(socketID string) {
 	delete(slotHashCache, socketID)
 }
 
+// sendEventReply sends the value a handler set with core.Reply in the
+// reply to its event. It follows the event's diff, so the client resolves
+// pushEvent once the diff is applied.
+func (r *Router) sendEventReply(session *LiveViewSession, msg transport.Message, reply any) {
+	r.sendReply(session, msg.Ref, msg.Topic, map[string]any{"reply": reply})
+}
+
 // sendReply sends a reply message to the client.
 func (r *Router) sendReply(session *LiveViewSession, ref, topic string, response map[string]any) {
 	payload := map[string]any{
@@ -948,6 +1550,15 @@ func (r *Router) sendError(session *LiveViewSession, ref, topic string, err erro
 func (r *Router) extractSession(req *http.Request) core.Session {
 	session := make(core.Session)
 
+	// Verified values of the cookie session, and the session itself so
+	// writes made over the socket reach the browser
+	if cs := security.CookieSessionFromContext(req.Context()); cs != nil {
+		for key, value := range cs.Values() {
+			session[key] = value
+		}
+		session[security.CookieSessionKey] = cs
+	}
+
 	// Extract auth context if available
 	if auth := security.AuthFromContext(req.Context()); auth != nil {
 		session["user_id"] = auth.UserID
@@ -989,6 +1600,7 @@ type RouteGroup struct {
 	router     *Router
 	prefix     string
 	middleware []Middleware
+	onMount    []OnMountHook
 }
 
 // Use adds middleware to the group.
@@ -996,6 +1608,24 @@ func (g *RouteGroup) Use(mw Middleware) {
 	g.middleware = append(g.middleware, mw)
 }
 
+// OnMount adds hooks run before the component of every Live route
+// registered afterwards in the group, or its nested groups, mounts.
+func (g *RouteGroup) OnMount(hooks ...OnMountHook) {
+	g.onMount = append(g.onMount, hooks...)
+}
+
+// Group creates a nested group. It inherits the prefix, middleware and
+// on-mount hooks added so far; its own run after them.
+func (g *RouteGroup) Group(prefix string, fn func(*RouteGroup)) {
+	nested := &RouteGroup{
+		router:     g.router,
+		prefix:     g.prefix + prefix,
+		middleware: append([]Middleware(nil), g.middleware...),
+		onMount:    append([]OnMountHook(nil), g.onMount...),
+	}
+	fn(nested)
+}
+
 // Live registers a LiveView route in the group.
 func (g *RouteGroup) Live(path string, component func() core.Component, opts ...RouteOption) {
 	fullPath := g.prefix + path
@@ -1004,12 +1634,14 @@ func (g *RouteGroup) Live(path string, component func() core.Component, opts ...
 		Component:  component,
 		Middleware: make([]Middleware, len(g.middleware)),
 		Meta:       make(map[string]any),
+		OnMount:    append([]OnMountHook(nil), g.onMount...),
 	}
 	copy(route.Middleware, g.middleware)
 
 	for _, opt := range opts {
 		opt(route)
 	}
+	g.router.checkParent(route)
 
 	g.router.mu.Lock()
 	g.router.liveRoutes[fullPath] = route
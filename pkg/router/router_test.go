@@ -8,8 +8,15 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
+	lvtesting "github.com/gabrielmiguelok/golivekit/pkg/testing"
 )
 
 // MockComponent implements core.Component for testing.
@@ -490,3 +497,397 @@ func BenchmarkLiveViewSessionManager_GetBySocket(b *testing.B) {
 		sm.GetBySocket(fmt.Sprintf("socket-%d", i%1000))
 	}
 }
+
+var mockStyles = core.NewStylesheet("mock", ".content { color: red }")
+
+// StyledComponent co-locates its CSS through core.StyleProvider.
+type StyledComponent struct {
+	MockComponent
+}
+
+func (c *StyledComponent) Styles() []*core.Stylesheet {
+	return []*core.Stylesheet{mockStyles, mockStyles}
+}
+
+func (c *StyledComponent) Render(ctx context.Context) core.Renderer {
+	return &MockRenderer{content: `<html><head><title>x</title></head><body class="` + mockStyles.Scope() + `"></body></html>`}
+}
+
+func TestRouter_Live_InjectsComponentStyles(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component {
+		return &StyledComponent{MockComponent: *NewMockComponent()}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if n := strings.Count(body, `data-lv-style="`+mockStyles.Scope()+`"`); n != 1 {
+		t.Fatalf("expected styles injected once, got %d in %s", n, body)
+	}
+	if strings.Index(body, "data-lv-style") > strings.Index(body, "</head>") {
+		t.Errorf("expected styles in head, got %s", body)
+	}
+}
+
+func TestRouter_PrecompiledSlots(t *testing.T) {
+	r := New()
+	html := `<div><span data-slot="n">5</span><ul data-slot="items"><li>a</li></ul></div>`
+	slots := map[string]string{"n": " 5 ", "items": "<li>a</li>"}
+
+	want := r.buildDiffPayload(context.Background(), NewLiveViewSession("a", NewMockComponent(), nil, nil), NewMockComponent(), html, nil, nil, false, nil)
+	got := r.buildDiffPayload(context.Background(), NewLiveViewSession("b", NewMockComponent(), nil, nil), NewMockComponent(), html, slots, nil, false, nil)
+	if fmt.Sprint(got.Slots) != fmt.Sprint(want.Slots) || fmt.Sprint(got.HTMLSlots) != fmt.Sprint(want.HTMLSlots) {
+		t.Errorf("Expected precompiled slots to match extraction: %+v vs %+v", got, want)
+	}
+}
+
+func TestRouter_DirtySlots(t *testing.T) {
+	r := New()
+	session := NewLiveViewSession("s", NewMockComponent(), nil, nil)
+	page := func(a, b string) string {
+		return `<div><span data-slot="a">` + a + `</span><p data-slot="b">` + b + `</p></div>`
+	}
+	r.buildDiffPayload(context.Background(), session, session.Component, page("1", "x"), nil, nil, false, nil)
+
+	// Only the dirty slot is compared, even though b changed too.
+	slots, partial := dirtySlots(page("2", "y"), nil, []string{"a"})
+	if !partial || len(slots) != 1 || slots["a"] != "2" {
+		t.Fatalf("Expected only slot a, got %v", slots)
+	}
+	payload := r.buildDiffPayload(context.Background(), session, session.Component, page("2", "y"), slots, nil, partial, nil)
+	if len(payload.Slots) != 1 || payload.Slots["a"] != "2" {
+		t.Errorf("Expected a partial diff, got %+v", payload.Slots)
+	}
+
+	// Unmarked slots keep their hashes for the next full extraction.
+	payload = r.buildDiffPayload(context.Background(), session, session.Component, page("2", "y"), nil, nil, false, nil)
+	if len(payload.Slots) != 1 || payload.Slots["b"] != "y" {
+		t.Errorf("Expected b to be sent by the next full diff, got %+v", payload.Slots)
+	}
+
+	if _, partial := dirtySlots(page("2", "y"), nil, []string{"missing"}); partial {
+		t.Error("Expected unknown dirty slots to fall back to full extraction")
+	}
+}
+
+func TestRouter_AllocBudget(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, `<li data-slot="s%d"><b>%d</b></li>`, i, i)
+	}
+	html := b.String()
+	r := New()
+	session := NewLiveViewSession("s", NewMockComponent(), nil, nil)
+
+	lvtesting.AllocBudget(t, func() { extractSlotsOptimized(html) }, 12)
+	lvtesting.AllocBudget(t, func() {
+		r.buildDiffPayload(context.Background(), session, session.Component, html, nil, nil, false, nil)
+	}, 20)
+}
+
+func TestRouter_AllowedOrigins(t *testing.T) {
+	r := New()
+	r.Live("/widget", func() core.Component { return &loopCounter{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := func() error {
+		header := http.Header{"Origin": {"https://shop.example.com"}}
+		ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/widget", &websocket.DialOptions{HTTPHeader: header})
+		if err == nil {
+			ws.CloseNow()
+		}
+		return err
+	}
+
+	if err := dial(); err == nil {
+		t.Error("Expected a cross-origin socket to be refused by default")
+	}
+
+	r.SetAllowedOrigins("https://shop.example.com")
+	if err := dial(); err != nil {
+		t.Errorf("Expected an allowed origin to connect, got %v", err)
+	}
+}
+
+// propsView renders the title it receives from the host page.
+type propsView struct {
+	core.BaseComponent
+	title string
+}
+
+func (c *propsView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	if event == core.PropsEvent {
+		c.title, _ = payload["title"].(string)
+	}
+	return nil
+}
+
+func (c *propsView) Render(ctx context.Context) core.Renderer {
+	return &MockRenderer{content: "<h1>" + c.title + "</h1>"}
+}
+
+func TestRouter_JoinProps(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &propsView{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.CloseNow()
+
+	join := map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{
+		"props": map[string]any{"title": "Invoice 7"},
+	}}
+	if err := wsjson.Write(ctx, ws, join); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	var reply map[string]any
+	if err := wsjson.Read(ctx, ws, &reply); err != nil {
+		t.Fatalf("join reply: %v", err)
+	}
+	if got := fmt.Sprint(reply["payload"]); !strings.Contains(got, "<h1>Invoice 7</h1>") {
+		t.Errorf("Expected the first render to use the props, got %s", got)
+	}
+}
+
+// connectInfoView renders the connection metadata it mounted with.
+type connectInfoView struct {
+	core.BaseComponent
+	info core.ConnectInfo
+}
+
+func (c *connectInfoView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	c.info = c.Socket().ConnectInfo()
+	return nil
+}
+
+func (c *connectInfoView) Render(ctx context.Context) core.Renderer {
+	i := c.info
+	return &MockRenderer{content: fmt.Sprintf("<p>%s|%s|%s|%v|%v|%dx%d|%s|%v|%s|%s</p>",
+		i.IP, i.UserAgent, i.Timezone, i.Features["touch"], !i.JoinedAt.IsZero(),
+		i.ViewportWidth, i.ViewportHeight, i.ColorScheme, i.ReducedMotion, i.Locale, i.Connection)}
+}
+
+func TestRouter_ConnectInfo(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &connectInfoView{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	header := http.Header{"User-Agent": {"test-agent"}, "X-Forwarded-For": {"203.0.113.7"}}
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/", &websocket.DialOptions{HTTPHeader: header})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.CloseNow()
+
+	join := map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{
+		"timezone": "Europe/Madrid",
+		"features": []any{"touch", 7},
+		"client": map[string]any{
+			"viewport_width": 390, "viewport_height": -5, "color_scheme": "dark",
+			"reduced_motion": true, "locale": "es-AR", "connection": "3g",
+		},
+	}}
+	if err := wsjson.Write(ctx, ws, join); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	var reply map[string]any
+	if err := wsjson.Read(ctx, ws, &reply); err != nil {
+		t.Fatalf("join reply: %v", err)
+	}
+	want := "<p>203.0.113.7|test-agent|Europe/Madrid|true|true|390x0|dark|true|es-AR|3g</p>"
+	if got := fmt.Sprint(reply["payload"]); !strings.Contains(got, want) {
+		t.Errorf("Expected %s in the first render, got %s", want, got)
+	}
+}
+
+// roomView counts the messages broadcast to its room.
+type roomView struct {
+	loopCounter
+}
+
+func (c *roomView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	return c.Socket().Subscribe("room:lobby")
+}
+
+func (c *roomView) HandleInfo(ctx context.Context, msg any) error {
+	if b, ok := msg.(core.Broadcast); ok && b.Event == "message" {
+		c.count++
+	}
+	return nil
+}
+
+func TestRouter_BroadcastToHandleInfo(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &roomView{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+
+	broadcaster := pubsub.NewBroadcaster(r.PubSub())
+	if err := broadcaster.Broadcast("room:lobby", "message", map[string]any{"text": "hi"}); err != nil {
+		t.Fatalf("Broadcast: %v", err)
+	}
+
+	var msg map[string]any
+	if err := wsjson.Read(ctx, ws, &msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	payload, _ := msg["payload"].(map[string]any)
+	slots, _ := payload["s"].(map[string]any)
+	if slots["n"] != "1" {
+		t.Fatalf("Expected a diff with n=1 after the broadcast, got %v", msg)
+	}
+
+	// Leaving unsubscribes
+	ws.Close(websocket.StatusNormalClosure, "")
+	mps := r.PubSub().(*pubsub.MemoryPubSub)
+	deadline := time.Now().Add(2 * time.Second)
+	for mps.SubscriberCount("room:lobby") > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := mps.SubscriberCount("room:lobby"); n != 0 {
+		t.Errorf("Expected the subscription to end with the socket, %d left", n)
+	}
+}
+
+// relayRoom broadcasts the "say" event to its room and reports the
+// correlation IDs it sees.
+type relayRoom struct {
+	loopCounter
+	broadcaster *pubsub.Broadcaster
+	sent, got   chan string
+}
+
+func (c *relayRoom) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	return c.Socket().Subscribe("room:relay")
+}
+
+func (c *relayRoom) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	c.sent <- tracing.CorrelationID(ctx)
+	return c.broadcaster.BroadcastContext(ctx, "room:relay", "said", nil)
+}
+
+func (c *relayRoom) HandleInfo(ctx context.Context, msg any) error {
+	if b, ok := msg.(core.Broadcast); ok && b.CorrelationID == tracing.CorrelationID(ctx) {
+		c.got <- b.CorrelationID
+	}
+	return nil
+}
+
+func TestRouter_BroadcastCorrelationID(t *testing.T) {
+	r := New()
+	room := &relayRoom{broadcaster: pubsub.NewBroadcaster(r.PubSub()), sent: make(chan string, 1), got: make(chan string, 1)}
+	r.Live("/", func() core.Component { return room })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "say", "payload": map[string]any{}})
+
+	var sent, got string
+	select {
+	case sent = <-room.sent:
+	case <-ctx.Done():
+		t.Fatal("event not handled")
+	}
+	select {
+	case got = <-room.got:
+	case <-ctx.Done():
+		t.Fatal("broadcast not handled with its correlation ID")
+	}
+	if sent == "" || got != sent {
+		t.Errorf("Expected HandleInfo in the event's correlation %q, got %q", sent, got)
+	}
+}
+
+func TestRouter_PushRender(t *testing.T) {
+	r := New()
+	var comp *loopCounter
+	r.Live("/", func() core.Component {
+		comp = &loopCounter{}
+		return comp
+	})
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+
+	// A background goroutine changes state and asks for a render
+	go func() {
+		comp.count = 5
+		comp.Socket().PushRender()
+	}()
+
+	var msg map[string]any
+	if err := wsjson.Read(ctx, ws, &msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	payload, _ := msg["payload"].(map[string]any)
+	slots, _ := payload["s"].(map[string]any)
+	if msg["event"] != "diff" || slots["n"] != "5" {
+		t.Errorf("Expected a diff with n=5, got %v", msg)
+	}
+}
+
+func TestRouter_SlotTrees(t *testing.T) {
+	r := New()
+	r.SetDiffBudget(0)
+	session := NewLiveViewSession("s", NewMockComponent(), nil, nil)
+	page := func(rows ...string) (string, map[string]*diff.Rendered) {
+		marked := diff.MarkListStart
+		for _, row := range rows {
+			marked += diff.MarkRowStart + "<li>" + diff.MarkDynamicStart + row + diff.MarkDynamicEnd + "</li>" + diff.MarkRowEnd
+		}
+		marked += diff.MarkListEnd
+		tree, err := diff.ParseMarked(marked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return `<div><ul data-slot="rows">` + diff.StripMarkers(marked) + `</ul></div>`, map[string]*diff.Rendered{"rows": tree}
+	}
+
+	html, trees := page("a", "b")
+	payload := r.buildDiffPayload(context.Background(), session, session.Component, html, nil, trees, false, nil)
+	first, _ := payload.Trees["rows"].(map[string]any)
+	if len(payload.HTMLSlots) != 0 || first["s"] == nil {
+		t.Fatalf("Expected the whole tree first, got %+v", payload)
+	}
+
+	html, trees = page("a", "c")
+	payload = r.buildDiffPayload(context.Background(), session, session.Component, html, nil, trees, false, nil)
+	change, _ := payload.Trees["rows"].(map[string]any)
+	if rows, _ := change["0"].(map[string]any); rows == nil || rows["s"] != nil {
+		t.Errorf("Expected only the rows of the comprehension, got %v", change)
+	}
+
+	// A slot sent as HTML drops its tree
+	payload = r.buildDiffPayload(context.Background(), session, session.Component, `<ul data-slot="rows"><li>x</li></ul>`, nil, nil, false, nil)
+	if payload.HTMLSlots["rows"] == "" || len(session.getSlotTrees()) != 0 {
+		t.Errorf("Expected an HTML slot and no tree, got %+v", payload)
+	}
+}
@@ -2,9 +2,12 @@
 package router
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gabrielmiguelok/golivekit/pkg/core"
@@ -21,18 +24,26 @@ type LiveViewSession struct {
 	// SocketID es el ID del socket asociado
 	SocketID string
 
+	// Token identifica la sesión ante su cliente, que lo envía al volver a
+	// unirse tras perder la conexión para recuperar el estado (ver
+	// LiveViewSessionManager.Recover). Es secreto: solo viaja por el socket.
+	Token string
+
 	// Component es la instancia del componente LiveView
 	Component core.Component
 
 	// Socket es la conexión WebSocket
 	Socket *core.Socket
 
-	// Transport es el transporte WebSocket subyacente
-	Transport *transport.WebSocketTransport
+	// Transport es el transporte subyacente (WebSocket o SSE)
+	Transport transport.Stream
 
 	// Params son los parámetros de la URL
 	Params core.Params
 
+	// Route es la ruta Live de la sesión (nil en salas de espera)
+	Route *LiveRoute
+
 	// Session contiene datos de sesión del usuario
 	Session core.Session
 
@@ -60,10 +71,57 @@ type LiveViewSession struct {
 	// Version es la versión de diff para ordenamiento en el cliente
 	Version uint64
 
+	// headKey identifica el último core.Head enviado al cliente
+	headKey string
+
+	// stale indica que se omitieron renders mientras la página del cliente
+	// estaba oculta (ver renderUnlessHidden).
+	stale bool
+
 	// Per-socket slot state (avoids global lock contention)
 	slotHashes map[string]uint64
+	slotTrees  map[string]*diff.Rendered // last tree sent per slot (see diffTrees)
+	fullBase   string                    // last full render sent (see deltaFull)
+	slotTexts  map[string]string         // last text of spliced slots (see spliceTextSlots)
 	slotMu     sync.RWMutex
 
+	// ctx es el contexto con que se manejan los mensajes (socket,
+	// componente, params); la navegación en vivo lo reemplaza.
+	ctx context.Context
+
+	// base es el contexto de toda la conexión, del que deriva ctx en cada
+	// navegación; cancel lo cancela al cerrarse la sesión, lo que termina
+	// su message loop.
+	base   context.Context
+	cancel context.CancelFunc
+
+	// Estado en el event loop compartido (ver SetEventLoop). done solo lo
+	// toca el worker que tiene scheduled.
+	scheduled atomic.Bool
+	done      bool
+
+	// left indica que el cliente salió con phx_leave: su estado no se
+	// guarda para recuperarlo.
+	left atomic.Bool
+
+	// takenOver indica que otra conexión del cliente tomó la sesión (ver
+	// Cluster); reaped, que se cerró por inactividad (ver RunReaper);
+	// disconnected, que handleDisconnect ya la cerró.
+	takenOver    atomic.Bool
+	reaped       atomic.Bool
+	disconnected atomic.Bool
+
+	// rateKey es la clave del bucket que limita sus eventos (ver
+	// SetEventRateLimit).
+	rateKey string
+
+	// tabGroup es la clave del grupo de pestañas de su navegador (ver
+	// joinTabGroup).
+	tabGroup string
+
+	// usage acumula el costo de la sesión (ver Usage).
+	usage sessionUsage
+
 	mu sync.RWMutex
 }
 
@@ -81,12 +139,51 @@ func (s *LiveViewSession) SetSlotHashes(hashes map[string]uint64) {
 	s.slotHashes = hashes
 }
 
+// getSlotTrees returns the slot trees the client holds.
+func (s *LiveViewSession) getSlotTrees() map[string]*diff.Rendered {
+	s.slotMu.RLock()
+	defer s.slotMu.RUnlock()
+	return s.slotTrees
+}
+
+// setSlotTrees stores the slot trees the client holds.
+func (s *LiveViewSession) setSlotTrees(trees map[string]*diff.Rendered) {
+	s.slotMu.Lock()
+	defer s.slotMu.Unlock()
+	s.slotTrees = trees
+}
+
+// getSlotTexts returns the texts of the slots sent as splices.
+func (s *LiveViewSession) getSlotTexts() map[string]string {
+	s.slotMu.RLock()
+	defer s.slotMu.RUnlock()
+	return s.slotTexts
+}
+
+// setSlotTexts stores the texts of the slots sent as splices.
+func (s *LiveViewSession) setSlotTexts(texts map[string]string) {
+	s.slotMu.Lock()
+	defer s.slotMu.Unlock()
+	s.slotTexts = texts
+}
+
+// swapFullBase stores the last full render sent to the client and returns
+// the one before it.
+func (s *LiveViewSession) swapFullBase(html string) string {
+	s.slotMu.Lock()
+	defer s.slotMu.Unlock()
+	prev := s.fullBase
+	s.fullBase = html
+	return prev
+}
+
 // NewLiveViewSession crea una nueva sesión LiveView.
 func NewLiveViewSession(socketID string, comp core.Component, params core.Params, session core.Session) *LiveViewSession {
 	now := time.Now()
 	return &LiveViewSession{
 		ID:           generateSessionID(),
 		SocketID:     socketID,
+		Token:        generateSessionID(),
 		Component:    comp,
 		Params:       params,
 		Session:      session,
@@ -152,20 +249,55 @@ type LiveViewSessionManager struct {
 	// sessionTTL es el tiempo de vida de sesiones inactivas
 	sessionTTL time.Duration
 
+	// parked guarda por token el estado de las sesiones desconectadas
+	// durante recoveryTTL (0 = sin recuperación)
+	parked      map[string]parkedSession
+	recoveryTTL time.Duration
+
+	// cluster guarda los estados en su store en vez de en parked, para
+	// que cualquier nodo los recupere (ver Router.SetCluster)
+	cluster *Cluster
+
+	// singles guarda el token de la sesión viva de cada usuario en las
+	// rutas con WithSingleSession
+	singles map[string]string
+
+	// reap cierra una sesión inactiva, terminando su componente (lo
+	// configura el Router); sin él Cleanup solo la olvida
+	reap func(*LiveViewSession)
+
 	mu sync.RWMutex
 }
 
+// parkedSession es el estado de una sesión que perdió la conexión, a la
+// espera de que su cliente vuelva.
+type parkedSession struct {
+	component string
+	path      string
+	owner     string
+	state     []byte
+	expires   time.Time
+}
+
 // LiveViewSessionManagerConfig configura el session manager.
 type LiveViewSessionManagerConfig struct {
 	MaxSessions int
 	SessionTTL  time.Duration
+
+	// RecoveryTTL es cuánto se guarda el estado de una sesión desconectada
+	// para su cliente (0 desactiva la recuperación).
+	RecoveryTTL time.Duration
 }
 
+// DefaultRecoveryTTL cubre los reintentos de reconexión del cliente.
+const DefaultRecoveryTTL = time.Minute
+
 // DefaultSessionManagerConfig retorna la configuración por defecto.
 func DefaultSessionManagerConfig() *LiveViewSessionManagerConfig {
 	return &LiveViewSessionManagerConfig{
 		MaxSessions: 10000,
 		SessionTTL:  30 * time.Minute,
+		RecoveryTTL: DefaultRecoveryTTL,
 	}
 }
 
@@ -184,6 +316,228 @@ func NewLiveViewSessionManagerWithConfig(config *LiveViewSessionManagerConfig) *
 		bySocket:    make(map[string]*LiveViewSession),
 		maxSessions: config.MaxSessions,
 		sessionTTL:  config.SessionTTL,
+		parked:      make(map[string]parkedSession),
+		recoveryTTL: config.RecoveryTTL,
+		singles:     make(map[string]string),
+	}
+}
+
+// SetRecoveryTTL cambia cuánto se guarda el estado de las sesiones
+// desconectadas (0 desactiva la recuperación).
+func (m *LiveViewSessionManager) SetRecoveryTTL(ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recoveryTTL = ttl
+}
+
+// SetSessionTTL cambia tras cuánto tiempo sin mensajes del cliente (ni
+// siquiera heartbeats) una sesión se considera abandonada y Cleanup la
+// cierra.
+func (m *LiveViewSessionManager) SetSessionTTL(ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessionTTL = ttl
+}
+
+// ttl retorna el SessionTTL actual.
+func (m *LiveViewSessionManager) ttl() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sessionTTL
+}
+
+// setReaper hace que Cleanup cierre las sesiones inactivas con reap.
+func (m *LiveViewSessionManager) setReaper(reap func(*LiveViewSession)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reap = reap
+}
+
+// setCluster hace que Park y Recover usen el store del cluster.
+func (m *LiveViewSessionManager) setCluster(c *Cluster) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cluster = c
+}
+
+// Park guarda el estado de una sesión que perdió la conexión, si su
+// componente implementa core.Snapshotter, para que Recover lo devuelva
+// cuando el cliente vuelva a unirse con el token de la sesión. El estado
+// se serializa como JSON al desconectarse y caduca tras RecoveryTTL. En un
+// cluster se guarda en el store compartido.
+func (m *LiveViewSessionManager) Park(s *LiveViewSession) bool {
+	snap, ok := s.Component.(core.Snapshotter)
+	if !ok || s.Route == nil || !s.IsMounted() {
+		return false
+	}
+	state, err := json.Marshal(snap.Snapshot())
+	if err != nil {
+		return false
+	}
+
+	m.mu.Lock()
+	ttl, cluster := m.recoveryTTL, m.cluster
+	m.mu.Unlock()
+	if ttl <= 0 {
+		return false
+	}
+	now := time.Now()
+	parked := parkedSession{
+		component: s.Component.Name(),
+		path:      s.Route.Path,
+		owner:     sessionOwner(s.Session),
+		state:     state,
+		expires:   now.Add(ttl),
+	}
+	if cluster != nil {
+		return cluster.park(s.Token, parked, ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.maxSessions > 0 && len(m.parked) >= m.maxSessions {
+		m.sweepParkedLocked(now)
+		if len(m.parked) >= m.maxSessions {
+			return false
+		}
+	}
+	m.parked[s.Token] = parked
+	return true
+}
+
+// Recover aplica a una sesión recién montada el estado guardado con Park
+// bajo token. Un token solo sirve una vez, antes de caducar, para el mismo
+// componente en la misma ruta y la misma sesión HTTP; si no, la sesión
+// conserva el estado de Mount. Si la conexión vieja sigue abierta, como
+// cuando un cambio de red deja un socket medio abierto, se le pide que
+// entregue la sesión. En un cluster, el estado puede venir de otro nodo
+// (ver Cluster).
+func (m *LiveViewSessionManager) Recover(token string, s *LiveViewSession) bool {
+	snap, ok := s.Component.(core.Snapshotter)
+	if !ok || s.Route == nil {
+		return false
+	}
+	parked, found := m.take(token, TakeoverReconnect, true)
+	return found && restoreParked(parked, s, snap)
+}
+
+// take quita y devuelve el estado guardado bajo token. Si la sesión sigue
+// conectada, le pide que se entregue por reason y, con wait, espera su
+// estado hasta DefaultHandoffTimeout.
+func (m *LiveViewSessionManager) take(token, reason string, wait bool) (parkedSession, bool) {
+	m.mu.Lock()
+	parked, found := m.parked[token]
+	delete(m.parked, token)
+	cluster := m.cluster
+	m.mu.Unlock()
+	if found {
+		return parked, true
+	}
+	if cluster != nil {
+		return cluster.adopt(token, reason, wait)
+	}
+	if !m.takeOver(token, reason) || !wait {
+		return parkedSession{}, false
+	}
+
+	deadline := time.Now().Add(DefaultHandoffTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		m.mu.Lock()
+		parked, found = m.parked[token]
+		delete(m.parked, token)
+		m.mu.Unlock()
+		if found {
+			return parked, true
+		}
+	}
+	return parkedSession{}, false
+}
+
+// takeOver pide a la sesión conectada con token que se entregue por
+// reason (ver Router.handOff). Devuelve false si no está en este nodo.
+func (m *LiveViewSessionManager) takeOver(token, reason string) bool {
+	for _, session := range m.All() {
+		if session.Token == token && session.Socket != nil {
+			return session.Socket.SendInfo(takeoverNotice{reason: reason}) == nil
+		}
+	}
+	return false
+}
+
+// restoreParked aplica a s el estado guardado, si es de su componente, su
+// ruta y su sesión HTTP y no caducó.
+func restoreParked(parked parkedSession, s *LiveViewSession, snap core.Snapshotter) bool {
+	if time.Now().After(parked.expires) ||
+		parked.component != s.Component.Name() ||
+		parked.path != s.Route.Path ||
+		parked.owner != sessionOwner(s.Session) {
+		return false
+	}
+	var state map[string]any
+	if err := json.Unmarshal(parked.state, &state); err != nil {
+		return false
+	}
+	return snap.Restore(state) == nil
+}
+
+// supersede registra s como la sesión viva de su usuario en una ruta con
+// WithSingleSession y pide a la anterior, la de otra pestaña, que se
+// entregue. Con restore, s recibe su estado. Devuelve si lo recibió.
+func (m *LiveViewSessionManager) supersede(s *LiveViewSession, restore bool) bool {
+	key := singleKey(s)
+	if key == "" {
+		return false
+	}
+
+	m.mu.Lock()
+	prev := m.singles[key]
+	m.singles[key] = s.Token
+	cluster := m.cluster
+	m.mu.Unlock()
+	if cluster != nil {
+		prev = cluster.claimSingle(key, s.Token)
+	}
+	if prev == "" || prev == s.Token {
+		return false
+	}
+
+	snap, ok := s.Component.(core.Snapshotter)
+	restore = restore && ok
+	parked, found := m.take(prev, TakeoverTab, restore)
+	return restore && found && restoreParked(parked, s, snap)
+}
+
+// releaseSingle olvida s como la sesión viva de su usuario, si lo sigue
+// siendo.
+func (m *LiveViewSessionManager) releaseSingle(s *LiveViewSession) {
+	key := singleKey(s)
+	if key == "" {
+		return
+	}
+
+	m.mu.Lock()
+	if m.singles[key] == s.Token {
+		delete(m.singles, key)
+	}
+	cluster := m.cluster
+	m.mu.Unlock()
+	if cluster != nil {
+		cluster.releaseSingle(key, s.Token)
+	}
+}
+
+// sessionOwner identifica la sesión HTTP del usuario, si la hay.
+func sessionOwner(session core.Session) string {
+	return session.GetString("session_id")
+}
+
+// sweepParkedLocked elimina los estados caducados (debe llamarse con lock).
+func (m *LiveViewSessionManager) sweepParkedLocked(now time.Time) {
+	for token, p := range m.parked {
+		if now.After(p.expires) {
+			delete(m.parked, token)
+		}
 	}
 }
 
@@ -262,23 +616,45 @@ func (m *LiveViewSessionManager) All() []*LiveViewSession {
 	return result
 }
 
-// Cleanup elimina sesiones inactivas.
+// Cleanup cierra las sesiones inactivas durante más de SessionTTL, cuyo
+// cliente desapareció sin cerrar la conexión, y descarta los estados
+// guardados caducados. En un Router el componente de cada sesión se termina
+// con core.TerminateTimeout y se liberan sus cachés; si no, la sesión solo
+// se elimina. Retorna cuántas sesiones cerró.
 func (m *LiveViewSessionManager) Cleanup() int {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	now := time.Now()
-	removed := 0
+	var idle []*LiveViewSession
 
 	for id, s := range m.sessions {
-		if now.Sub(s.GetLastActivity()) > m.sessionTTL {
-			delete(m.bySocket, s.SocketID)
-			delete(m.sessions, id)
-			removed++
+		if m.sessionTTL > 0 && now.Sub(s.GetLastActivity()) > m.sessionTTL {
+			idle = append(idle, s)
+			if m.reap == nil {
+				delete(m.bySocket, s.SocketID)
+				delete(m.sessions, id)
+			}
+		}
+	}
+	m.sweepParkedLocked(now)
+	reap := m.reap
+	m.mu.Unlock()
+
+	// Sin el lock: cerrar la sesión la elimina del manager
+	if reap != nil {
+		for _, s := range idle {
+			reap(s)
 		}
 	}
+	return len(idle)
+}
 
-	return removed
+// idle indica si la sesión sigue inactiva durante más de SessionTTL.
+func (m *LiveViewSessionManager) idle(s *LiveViewSession) bool {
+	m.mu.RLock()
+	ttl := m.sessionTTL
+	m.mu.RUnlock()
+	return ttl > 0 && time.Since(s.GetLastActivity()) > ttl
 }
 
 // evictOldestLocked elimina las sesiones más antiguas (debe llamarse con lock).
@@ -299,6 +675,25 @@ func (m *LiveViewSessionManager) evictOldestLocked() {
 	}
 }
 
+// RunReaper ejecuta Cleanup cada interval hasta que ctx termine, para que
+// las sesiones abandonadas no se acumulen. Un Router lo inicia con su
+// primera sesión y lo detiene en Shutdown; llamarlo solo hace falta con un
+// manager propio:
+//
+//	go m.RunReaper(ctx, time.Minute)
+func (m *LiveViewSessionManager) RunReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Cleanup()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // StartCleanupRoutine inicia una rutina de limpieza periódica.
 func (m *LiveViewSessionManager) StartCleanupRoutine(interval time.Duration, stopCh <-chan struct{}) {
 	ticker := time.NewTicker(interval)
@@ -0,0 +1,81 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/shutdown"
+)
+
+// Delays before the clients of a router that shut down reconnect, giving
+// the load balancer time to stop sending them here.
+const (
+	DefaultShutdownMinDelay = time.Second
+	DefaultShutdownMaxDelay = DefaultDrainMaxDelay
+)
+
+// Shutdown closes the live sessions for good, when the process stops:
+//
+//  1. New sockets and joins are refused, as while draining.
+//  2. Every client is told to reconnect later, after a random delay between
+//     DefaultShutdownMinDelay and DefaultShutdownMaxDelay. With SetRecovery,
+//     core.Snapshotter components are saved first, as in Drain.
+//  3. Each component is terminated with core.TerminateShutdown and a
+//     context bounded by ctx, and its connection is closed.
+//  4. The idle session reaper stops.
+//
+// Sessions are closed on their message loops, after the event they are
+// handling. Those still open when ctx is done are terminated at once, and
+// Shutdown returns ctx's error.
+func (r *Router) Shutdown(ctx context.Context) error {
+	notice := drainNotice{
+		config:  DrainConfig{MinDelay: DefaultShutdownMinDelay, MaxDelay: DefaultShutdownMaxDelay},
+		reason:  "shutdown",
+		closing: ctx,
+	}
+	r.mu.Lock()
+	r.draining = true
+	r.drainNotice = notice
+	stopReaper := r.stopReaper
+	r.mu.Unlock()
+	if stopReaper != nil {
+		defer stopReaper()
+	}
+
+	for _, session := range r.sessionManager.All() {
+		if session.Socket == nil || session.Socket.SendInfo(notice) != nil {
+			r.disconnect(ctx, session)
+		}
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for r.sessionManager.Count() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			// Stuck in an event: terminate without waiting for it
+			for _, session := range r.sessionManager.All() {
+				go r.disconnect(ctx, session)
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// ShutdownHook returns a shutdown hook that runs Shutdown before metrics
+// are flushed and the HTTP server stops:
+//
+//	h := shutdown.NewHandler(nil)
+//	h.Register(r.ShutdownHook())
+//	h.Register(shutdown.HTTPServerHook("http", srv.Shutdown))
+//	go srv.ListenAndServe()
+//	h.Wait()
+func (r *Router) ShutdownHook() shutdown.Hook {
+	return shutdown.Hook{
+		Name:     "router-shutdown",
+		Priority: shutdown.PriorityFirst,
+		Fn:       r.Shutdown,
+	}
+}
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/recovery"
+)
+
+func TestRouter_ShutdownClosesLiveSessions(t *testing.T) {
+	reasons := make(chan core.TerminateReason, 4)
+	r := New()
+	r.SetRecovery(recovery.NewRecoveryManager(nil))
+	r.Live("/", func() core.Component { return &clusterCounter{reasons: reasons} })
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, server.URL)
+	defer ws.CloseNow()
+
+	if err := r.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := r.SessionManager().Count(); got != 0 {
+		t.Errorf("Expected no live sessions after Shutdown, got %d", got)
+	}
+	select {
+	case reason := <-reasons:
+		if reason != core.TerminateShutdown {
+			t.Errorf("Expected the component terminated with shutdown, got %v", reason)
+		}
+	default:
+		t.Error("Expected the component terminated")
+	}
+
+	// The client is told to reconnect later, with its session saved
+	var msg struct {
+		Event   string         `json:"event"`
+		Payload map[string]any `json:"payload"`
+	}
+	if err := wsjson.Read(ctx, ws, &msg); err != nil || msg.Event != ReconnectEvent {
+		t.Fatalf("Expected %s, got %+v (%v)", ReconnectEvent, msg, err)
+	}
+	if msg.Payload["reason"] != "shutdown" || msg.Payload["min"] != float64(DefaultShutdownMinDelay.Milliseconds()) || msg.Payload["token"] == nil {
+		t.Errorf("Unexpected reconnect payload: %+v", msg.Payload)
+	}
+
+	if _, resp, err := websocket.Dial(ctx, "ws"+server.URL[4:]+"/", nil); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Error("Expected new sockets to be refused after Shutdown")
+	}
+}
+
+// stuckCounter blocks in its event handler until released.
+type stuckCounter struct {
+	clusterCounter
+	release chan struct{}
+}
+
+func (c *stuckCounter) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	<-c.release
+	return nil
+}
+
+func TestRouter_ShutdownDeadline(t *testing.T) {
+	reasons := make(chan core.TerminateReason, 4)
+	release := make(chan struct{})
+	defer close(release)
+	r := New()
+	r.Live("/", func() core.Component {
+		return &stuckCounter{clusterCounter: clusterCounter{reasons: reasons}, release: release}
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, server.URL)
+	defer ws.CloseNow()
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+	time.Sleep(50 * time.Millisecond) // in the handler
+
+	deadline, stop := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer stop()
+	if err := r.Shutdown(deadline); err != context.DeadlineExceeded {
+		t.Fatalf("Expected the deadline error, got %v", err)
+	}
+	select {
+	case reason := <-reasons:
+		if reason != core.TerminateShutdown {
+			t.Errorf("Expected the component terminated with shutdown, got %v", reason)
+		}
+	case <-ctx.Done():
+		t.Fatal("Expected the stuck component terminated at the deadline")
+	}
+}
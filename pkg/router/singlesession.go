@@ -0,0 +1,56 @@
+package router
+
+import (
+	"context"
+	"time"
+)
+
+// WithSingleSession keeps one live session per user on a route. By default
+// every tab a user opens gets its own independent session. With this
+// option, when the user opens the route in another tab, the newest tab
+// takes the live connection over: the previous tab's component is
+// terminated with core.TerminateTakeover, its state (see core.Snapshotter)
+// moves to the new tab unless that tab recovered state of its own, and its
+// client gets a TakeoverEvent with reason TakeoverTab and shows a notice
+// instead of reconnecting.
+//
+// Users are told apart by the login session of security.SessionManager.
+// Visitors who are not logged in keep independent sessions. In a cluster
+// the rule holds across nodes.
+//
+//	r.Live("/editor/:doc", NewEditor, router.WithSingleSession())
+func WithSingleSession() RouteOption {
+	return func(r *LiveRoute) {
+		r.SingleSession = true
+	}
+}
+
+// singleKey identifies the user's session on a single-session route, or is
+// "" when the session is not limited.
+func singleKey(s *LiveViewSession) string {
+	owner := sessionOwner(s.Session)
+	if s.Route == nil || !s.Route.SingleSession || owner == "" {
+		return ""
+	}
+	return s.Route.Path + " " + owner
+}
+
+// claimSingle records token as the live session under key, returning the
+// previous one.
+func (c *Cluster) claimSingle(key, token string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.HandoffTimeout)
+	defer cancel()
+	prev, _ := c.store.Get(ctx, c.key("single", key))
+	c.store.Set(ctx, c.key("single", key), []byte(token), ownerTTL)
+	return string(prev)
+}
+
+// releaseSingle forgets token as the live session under key, unless
+// another one took its place.
+func (c *Cluster) releaseSingle(key, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if current, err := c.store.Get(ctx, c.key("single", key)); err == nil && string(current) == token {
+		c.store.Delete(ctx, c.key("single", key))
+	}
+}
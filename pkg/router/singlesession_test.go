@@ -0,0 +1,108 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// loginFromQuery logs the request in as the session in ?login.
+func loginFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		auth := &security.AuthContext{SessionID: req.URL.Query().Get("login")}
+		next.ServeHTTP(w, req.WithContext(security.WithAuthContext(req.Context(), auth)))
+	})
+}
+
+// openTab joins the single-session route as login and increments the
+// counter, returning the socket and the count.
+func openTab(t *testing.T, ctx context.Context, url, login string) (*websocket.Conn, any) {
+	t.Helper()
+	ws, _, err := websocket.Dial(ctx, "ws"+url[4:]+"/?login="+login, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{}})
+	var reply map[string]any
+	if err := wsjson.Read(ctx, ws, &reply); err != nil {
+		t.Fatal(err)
+	}
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+	var diff struct {
+		Payload struct {
+			S map[string]any `json:"s"`
+		} `json:"payload"`
+	}
+	wsjson.Read(ctx, ws, &diff)
+	return ws, diff.Payload.S["n"]
+}
+
+func TestRouter_SingleSessionMovesToNewestTab(t *testing.T) {
+	reasons := make(chan core.TerminateReason, 4)
+	r := New()
+	r.Live("/", func() core.Component { return &clusterCounter{reasons: reasons} },
+		WithSingleSession(), WithRouteMiddleware(loginFromQuery))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, _ := openTab(t, ctx, ts.URL, "alice")
+	defer first.CloseNow()
+
+	second, n := openTab(t, ctx, ts.URL, "alice")
+	defer second.CloseNow()
+	if n != "2" {
+		t.Errorf("Expected the new tab to continue the count at 2, got %v", n)
+	}
+
+	var msg struct {
+		Event   string         `json:"event"`
+		Payload map[string]any `json:"payload"`
+	}
+	if err := wsjson.Read(ctx, first, &msg); err != nil || msg.Event != TakeoverEvent || msg.Payload["reason"] != TakeoverTab {
+		t.Errorf("Expected %s for a tab on the old tab, got %+v (%v)", TakeoverEvent, msg, err)
+	}
+	if reason := <-reasons; reason != core.TerminateTakeover {
+		t.Errorf("Expected the old tab terminated with takeover, got %v", reason)
+	}
+
+	// Other users keep their own sessions
+	other, n := openTab(t, ctx, ts.URL, "bob")
+	defer other.CloseNow()
+	if n != "1" {
+		t.Errorf("Expected another user to start at 1, got %v", n)
+	}
+	if got := r.SessionManager().Count(); got != 2 {
+		t.Errorf("Expected 2 live sessions, got %d", got)
+	}
+}
+
+func TestRouter_TabsAreIndependentByDefault(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &snapCounter{} }, WithRouteMiddleware(loginFromQuery))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, _ := openTab(t, ctx, ts.URL, "alice")
+	defer first.CloseNow()
+	second, n := openTab(t, ctx, ts.URL, "alice")
+	defer second.CloseNow()
+	if n != "1" {
+		t.Errorf("Expected the second tab to start at 1, got %v", n)
+	}
+	if got := r.SessionManager().Count(); got != 2 {
+		t.Errorf("Expected 2 live sessions, got %d", got)
+	}
+}
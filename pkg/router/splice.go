@@ -0,0 +1,70 @@
+package router
+
+import (
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+)
+
+// SetTextSlotSplicing sends changes to text slots of at least minSize
+// bytes (documentation pages, editor previews) as splices of the words
+// that changed instead of the whole text. Each session then keeps the last
+// text of those slots. 0, the default, turns splicing off.
+func (r *Router) SetTextSlotSplicing(minSize int) {
+	r.mu.Lock()
+	r.spliceMinSize = minSize
+	r.mu.Unlock()
+}
+
+// spliceTextSlots replaces payload's large text slots with splices of the
+// text the client holds, when they are smaller. Slots sent as HTML or
+// trees forget their text.
+func (r *Router) spliceTextSlots(session *LiveViewSession, payload *core.DiffPayload) {
+	r.mu.RLock()
+	minSize := r.spliceMinSize
+	r.mu.RUnlock()
+	prev := session.getSlotTexts()
+	if minSize <= 0 && len(prev) == 0 {
+		return
+	}
+
+	next := make(map[string]string, len(prev))
+	for id, text := range prev {
+		next[id] = text
+	}
+	for id := range payload.HTMLSlots {
+		delete(next, id)
+	}
+	for id := range payload.Trees {
+		delete(next, id)
+	}
+	for id, content := range payload.Slots {
+		if minSize <= 0 || len(content) < minSize {
+			delete(next, id)
+			continue
+		}
+		next[id] = content
+		base, ok := prev[id]
+		if !ok {
+			continue
+		}
+		splices, ok := diff.SpliceText(base, content)
+		if !ok || spliceSize(splices) >= len(content) {
+			continue
+		}
+		if payload.Splices == nil {
+			payload.Splices = make(map[string][]core.TextSplice)
+		}
+		payload.Splices[id] = splices
+		delete(payload.Slots, id)
+	}
+	session.setSlotTexts(next)
+}
+
+// spliceSize estimates the encoded size of splices.
+func spliceSize(splices []core.TextSplice) int {
+	size := 0
+	for _, sp := range splices {
+		size += len(sp.Text) + 24
+	}
+	return size
+}
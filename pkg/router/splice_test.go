@@ -0,0 +1,56 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// applySplices applies splices like the client, on UTF-16 code units.
+func applySplices(text string, splices []core.TextSplice) string {
+	units := utf16.Encode([]rune(text))
+	for i := len(splices) - 1; i >= 0; i-- {
+		sp := splices[i]
+		ins := utf16.Encode([]rune(sp.Text))
+		units = append(units[:sp.Pos], append(ins, units[sp.Pos+sp.Del:]...)...)
+	}
+	return string(utf16.Decode(units))
+}
+
+func TestRouter_TextSlotSplicing(t *testing.T) {
+	r := New()
+	r.SetDiffBudget(0)
+	r.SetTextSlotSplicing(64)
+	session := NewLiveViewSession("s", NewMockComponent(), nil, nil)
+	doc := strings.Repeat("Lorem ipsum dolor sit amet, ", 9) + "Lorem ipsum dolor sit amet."
+	page := func(text string) string {
+		return `<article data-slot="doc">` + text + `</article><span data-slot="n">1</span>`
+	}
+
+	first := r.buildDiffPayload(context.Background(), session, session.Component, page(doc), nil, nil, false, nil)
+	if first.Slots["doc"] != doc || len(first.Splices) != 0 {
+		t.Fatalf("Expected the text whole first, got %+v", first)
+	}
+
+	// Two edits far apart, one with a character outside the BMP
+	edited := strings.Replace(doc, "dolor", "dolör 😀", 1)
+	edited = strings.TrimSuffix(edited, "amet.") + "finis."
+	payload := r.buildDiffPayload(context.Background(), session, session.Component, page(edited), nil, nil, false, nil)
+	splices := payload.Splices["doc"]
+	if len(splices) == 0 || payload.Slots["doc"] != "" || spliceSize(splices) > len(edited)/2 {
+		t.Fatalf("Expected the edited words only, got %+v", payload)
+	}
+	if got := applySplices(doc, splices); got != edited {
+		t.Errorf("Splices give %q, want %q", got, edited)
+	}
+
+	// Small slots and rewritten texts are sent whole
+	rewritten := strings.Repeat("Completely different words here. ", 9) + "The end."
+	payload = r.buildDiffPayload(context.Background(), session, session.Component, `<article data-slot="doc">`+rewritten+`</article><span data-slot="n">2</span>`, nil, nil, false, nil)
+	if payload.Slots["doc"] != rewritten || payload.Slots["n"] != "2" || len(payload.Splices) != 0 {
+		t.Errorf("Expected whole slots, got %+v", payload)
+	}
+}
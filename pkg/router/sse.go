@@ -0,0 +1,122 @@
+package router
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// SSEStreamParam names the query parameter that routes a POST on a live
+// path to the SSE stream it belongs to. Its value is the client_id of the
+// stream's "connected" event.
+const SSEStreamParam = "lv_sse"
+
+// isSSERequest reports whether req opens an SSE stream: the fallback for
+// clients whose WebSockets are blocked (corporate proxies, some CDNs).
+func isSSERequest(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+}
+
+// isSSEPost reports whether req carries client messages for an SSE stream.
+func isSSEPost(req *http.Request) bool {
+	return req.Method == http.MethodPost && req.URL.Query().Has(SSEStreamParam)
+}
+
+// handleSSE serves a live session over Server-Sent Events. Server messages
+// are streamed on the response; the client POSTs its messages to the same
+// path with ?lv_sse=<client_id>. The session ends with the request.
+func (r *Router) handleSSE(w http.ResponseWriter, req *http.Request, route *LiveRoute, component core.Component) {
+	if !r.originAllowed(req) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	socketID := generateSocketID()
+	if !r.acquireSlot(w, route, socketID) {
+		return
+	}
+
+	loop := r.loop()
+	config := transport.DefaultTransportConfig()
+	if loop != nil {
+		loop.configure(config)
+	}
+	r.configureQueue(config, socketID)
+	r.mu.RLock()
+	sseConfig := &transport.SSEConfig{
+		AllowedOrigins:   r.allowedOrigins,
+		AllowCredentials: len(r.allowedOrigins) > 0,
+	}
+	r.mu.RUnlock()
+	stream := transport.NewSSETransportWithConfig(config, sseConfig)
+	stream.SetClientID(socketID)
+
+	r.sseStreams.Store(socketID, stream)
+	defer r.sseStreams.Delete(socketID)
+
+	r.startSession(req, route, component, socketID, stream, loop)
+	if err := stream.ServeHTTP(w, req); err != nil {
+		stream.Close()
+		r.errorHandler(w, req, err)
+	}
+}
+
+// handleSSEPost queues the messages in a POST body, one JSON message per
+// line, on their SSE stream.
+func (r *Router) handleSSEPost(w http.ResponseWriter, req *http.Request) {
+	if !r.originAllowed(req) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	value, ok := r.sseStreams.Load(req.URL.Query().Get(SSEStreamParam))
+	if !ok {
+		http.Error(w, "unknown stream", http.StatusNotFound)
+		return
+	}
+	stream := value.(*transport.SSETransport)
+
+	// A batch of messages, binary attachments base64-encoded
+	maxBody := 4 * transport.DefaultTransportConfig().MaxMessageSize
+	req.Body = http.MaxBytesReader(w, req.Body, maxBody)
+	if err := stream.ReceiveFromPost(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if origin := req.Header.Get("Origin"); origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// originAllowed applies the WebSocket origin rules to SSE requests:
+// same-origin, or listed in SetAllowedOrigins.
+func (r *Router) originAllowed(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if originURL.Host == req.Host {
+		return true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, allowed := range r.allowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if allowedURL, err := url.Parse(allowed); err == nil && allowedURL.Host == originURL.Host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// sseClient reads the events of a live SSE stream and POSTs messages back.
+type sseClient struct {
+	t      *testing.T
+	url    string
+	id     string
+	events chan map[string]any
+}
+
+func openSSE(t *testing.T, ctx context.Context, url string) *sseClient {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %d %q", resp.StatusCode, ct)
+	}
+
+	c := &sseClient{t: t, url: url, events: make(chan map[string]any, 16)}
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var msg map[string]any
+			if json.Unmarshal([]byte(data), &msg) == nil {
+				c.events <- msg
+			}
+		}
+	}()
+
+	connected := c.next("connected")
+	c.id, _ = connected["payload"].(map[string]any)["client_id"].(string)
+	if c.id == "" {
+		t.Fatalf("Expected a client_id, got %v", connected)
+	}
+	return c
+}
+
+// next returns the next message with the given event.
+func (c *sseClient) next(event string) map[string]any {
+	c.t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case msg := <-c.events:
+			if msg["event"] == event {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatalf("timed out waiting for %s", event)
+			return nil
+		}
+	}
+}
+
+func (c *sseClient) post(msgs ...map[string]any) int {
+	c.t.Helper()
+	var body strings.Builder
+	for _, msg := range msgs {
+		line, _ := json.Marshal(msg)
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+	resp, err := http.Post(c.url+"?"+SSEStreamParam+"="+c.id, "text/plain", strings.NewReader(body.String()))
+	if err != nil {
+		c.t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRouter_SSE(t *testing.T) {
+	for _, loop := range []bool{false, true} {
+		t.Run(map[bool]string{false: "goroutine", true: "eventloop"}[loop], func(t *testing.T) {
+			r := New()
+			if loop {
+				r.SetEventLoop(EventLoopConfig{Workers: 1})
+			}
+			r.Live("/counter", func() core.Component { return &loopCounter{} })
+			ts := httptest.NewServer(r)
+			defer ts.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			c := openSSE(t, ctx, ts.URL+"/counter")
+
+			status := c.post(
+				map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{}},
+				map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}},
+			)
+			if status != http.StatusNoContent {
+				t.Fatalf("Expected 204, got %d", status)
+			}
+
+			reply := c.next("phx_reply")
+			if reply["ref"] != "1" {
+				t.Errorf("Expected the join reply first, got %v", reply)
+			}
+			diff, _ := json.Marshal(c.next("diff"))
+			if !strings.Contains(string(diff), `"1"`) {
+				t.Errorf("Expected the count in the diff, got %s", diff)
+			}
+		})
+	}
+}
+
+func TestRouter_SSEPostUnknownStream(t *testing.T) {
+	r := New()
+	r.Live("/counter", func() core.Component { return &loopCounter{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/counter?"+SSEStreamParam+"=nope", "text/plain", strings.NewReader("{}\n"))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,158 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// WithParent stacks the route over parent, a Live path registered before
+// it, for modals and drawers with their own URL ("/users/{id}/edit" over
+// "/users"). The route is served by the parent's component, which renders
+// its page with the modal on top: params[core.RouteKey] holds the route's
+// path and the path parameters ({id}) are added to the query ones. Moving
+// between the parent and its stacked routes with push_patch or back/forward
+// keeps the component mounted and calls HandleParams. Pass a nil component:
+//
+//	r.Live("/users", NewUsers)
+//	r.Live("/users/{id}/edit", nil, router.WithParent("/users"))
+func WithParent(parent string) RouteOption {
+	return func(r *LiveRoute) {
+		r.Parent = parent
+	}
+}
+
+// checkParent panics when a stacked route's parent is not registered, as
+// http.ServeMux does for conflicting patterns.
+func (r *Router) checkParent(route *LiveRoute) {
+	if route.Parent == "" {
+		return
+	}
+	r.mu.RLock()
+	_, ok := r.liveRoutes[route.Parent]
+	r.mu.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("router: parent route %q of %q is not registered", route.Parent, route.Path))
+	}
+}
+
+// stackRoot returns the route at the bottom of route's stack (route itself
+// when it is not stacked).
+func (r *Router) stackRoot(route *LiveRoute) *LiveRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for route != nil && route.Parent != "" {
+		parent, ok := r.liveRoutes[route.Parent]
+		if !ok {
+			break
+		}
+		route = parent
+	}
+	return route
+}
+
+// stacked reports whether route stacks over another or has routes stacked
+// over it.
+func (r *Router) stacked(route *LiveRoute) bool {
+	if route.Parent != "" {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, other := range r.liveRoutes {
+		if other.Parent == route.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// newComponent creates the component serving route.
+func (r *Router) newComponent(route *LiveRoute) core.Component {
+	if root := r.stackRoot(route); root != nil && root.Component != nil {
+		return root.Component()
+	}
+	return route.Component()
+}
+
+// matchRoute returns the Live route serving u, or nil.
+func (r *Router) matchRoute(u *url.URL) *LiveRoute {
+	_, pattern := r.mux.Handler(&http.Request{Method: http.MethodGet, URL: u})
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.liveRoutes[pattern]
+}
+
+// routeParams returns the params for u on route: the query and the path
+// parameters ("/users/{id}" sets "id"), which win over query ones with the
+// same name. Stacked routes also get their path in params[core.RouteKey].
+func (r *Router) routeParams(route *LiveRoute, u *url.URL) core.Params {
+	params := make(core.Params)
+	for key, values := range u.Query() {
+		if len(values) > 0 {
+			params[key] = values[0]
+		}
+	}
+	if route == nil {
+		return params
+	}
+	for key, value := range pathParams(route.Path, u.EscapedPath()) {
+		params[key] = value
+	}
+	if r.stacked(route) {
+		params[core.RouteKey] = route.Path
+	}
+	return params
+}
+
+// pathParams matches an escaped path against a pattern with {name} and
+// {name...} wildcards, returning the unescaped wildcard values (nil when it
+// does not match). The pattern's method and host, if any, are ignored.
+func pathParams(pattern, path string) map[string]string {
+	pattern = patternPath(pattern)
+	pats := strings.Split(strings.Trim(pattern, "/"), "/")
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	values := make(map[string]string)
+	for i, pat := range pats {
+		if pat == "{$}" {
+			break
+		}
+		if strings.HasPrefix(pat, "{") && strings.HasSuffix(pat, "...}") {
+			values[pat[1:len(pat)-4]] = unescapeSegment(strings.Join(segs[min(i, len(segs)):], "/"))
+			return values
+		}
+		if i >= len(segs) {
+			return nil
+		}
+		if strings.HasPrefix(pat, "{") && strings.HasSuffix(pat, "}") {
+			values[pat[1:len(pat)-1]] = unescapeSegment(segs[i])
+		} else if pat != unescapeSegment(segs[i]) {
+			return nil
+		}
+	}
+	return values
+}
+
+// patternPath strips the method and host from an http.ServeMux pattern
+// ("GET example.com/users/{id}" is "/users/{id}").
+func patternPath(pattern string) string {
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i+1:], " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// unescapeSegment decodes a path segment as http.Request.PathValue does,
+// keeping malformed escapes as they are.
+func unescapeSegment(s string) string {
+	if v, err := url.PathUnescape(s); err == nil {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,183 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// userList shows an edit modal over the list on /users/{id}/edit.
+type userList struct {
+	core.BaseComponent
+	mounts  int
+	editing string
+}
+
+func (c *userList) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	c.mounts++
+	return c.HandleParams(ctx, params, core.Navigation{})
+}
+
+func (c *userList) HandleParams(ctx context.Context, params core.Params, nav core.Navigation) error {
+	c.editing = ""
+	if params.Get(core.RouteKey) == "/users/{id}/edit" {
+		c.editing = params.Get("id")
+	}
+	return nil
+}
+
+func (c *userList) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<div data-live-view="c"><ul>users</ul><span data-slot="modal">%s</span></div>`, c.editing)
+		return err
+	})
+}
+
+func TestRouter_StackedRoutes(t *testing.T) {
+	r := New()
+	var comp *userList
+	r.Live("/users", func() core.Component {
+		comp = &userList{}
+		return comp
+	})
+	r.Live("/users/{id}/edit", nil, WithParent("/users"))
+	r.Live("/other", func() core.Component { return &loopCounter{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// A deep link renders the parent with the modal open
+	resp, err := ts.Client().Get(ts.URL + "/users/3/edit")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `<ul>users</ul><span data-slot="modal">3</span>`) {
+		t.Fatalf("Expected the list with the modal for user 3, got %s", body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/users/3/edit", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.CloseNow()
+	var reply map[string]any
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{}})
+	wsjson.Read(ctx, ws, &reply)
+
+	navigate := func(url string) map[string]any {
+		params := map[string]any{"url": url, "kind": "pop"}
+		wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": core.ParamsEvent, "payload": params})
+		var msg map[string]any
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return msg
+	}
+
+	// Back to the list closes the modal on the same component
+	msg := navigate("/users")
+	payload, _ := msg["payload"].(map[string]any)
+	slots, _ := payload["s"].(map[string]any)
+	if msg["event"] != "diff" || slots["modal"] != "" || comp.mounts != 1 {
+		t.Errorf("Expected the modal closed without a remount, got %v (%d mounts)", msg, comp.mounts)
+	}
+	wsjson.Read(ctx, ws, &msg) // reply
+
+	msg = navigate("/users/7/edit")
+	payload, _ = msg["payload"].(map[string]any)
+	slots, _ = payload["s"].(map[string]any)
+	if slots["modal"] != "7" {
+		t.Errorf("Expected the modal for user 7, got %v", msg)
+	}
+	wsjson.Read(ctx, ws, &msg)
+
+	// Leaving the stack mounts the other route's component
+	msg = navigate("/other")
+	payload, _ = msg["payload"].(map[string]any)
+	response, _ := payload["response"].(map[string]any)
+	if _, ok := response["document"].(string); msg["event"] != "phx_reply" || !ok {
+		t.Errorf("Expected the other route's document out of the stack, got %v", msg)
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          map[string]string
+	}{
+		{"/users/{id}/edit", "/users/3/edit", map[string]string{"id": "3"}},
+		{"/users/{id}/edit", "/users/3", nil},
+		{"/files/{path...}", "/files/a/b.txt", map[string]string{"path": "a/b.txt"}},
+		{"/users", "/users", map[string]string{}},
+		{"GET example.com/posts/{slug}", "/posts/a%2Fb", map[string]string{"slug": "a/b"}},
+	}
+	for _, tt := range tests {
+		got := pathParams(tt.pattern, tt.path)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("pathParams(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+// paramsView records the params it was mounted with.
+type paramsView struct {
+	core.BaseComponent
+	params chan core.Params
+}
+
+func (c *paramsView) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	c.params <- params
+	return nil
+}
+
+func (c *paramsView) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<div data-live-view="c"></div>`)
+		return err
+	})
+}
+
+func TestRouter_PathParams(t *testing.T) {
+	r := New()
+	mounted := make(chan core.Params, 2)
+	r.Live("/posts/{slug}", func() core.Component { return &paramsView{params: mounted} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/posts/hello%20world?slug=query&page=2")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if params := <-mounted; params.Get("slug") != "hello world" || params.Get("page") != "2" || params.Get(core.RouteKey) != "" {
+		t.Errorf("Expected the path param over the query one on render, got %v", params)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/posts/go-1.23", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.CloseNow()
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{}})
+	select {
+	case params := <-mounted:
+		if params.Get("slug") != "go-1.23" {
+			t.Errorf("Expected the path param on join, got %v", params)
+		}
+	case <-ctx.Done():
+		t.Fatal("Expected a mount on join")
+	}
+}
@@ -0,0 +1,108 @@
+package router
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// Slot streaming defaults.
+const (
+	// DefaultSlotStreamThreshold is the HTML slot size above which the slot
+	// is streamed in chunks instead of sent in the diff.
+	DefaultSlotStreamThreshold = 64 * 1024
+	// DefaultSlotChunkSize is the size of each streamed chunk.
+	DefaultSlotChunkSize = 16 * 1024
+	// ChunkEvent carries one chunk of a streamed slot:
+	// {"v": version, "id": slot, "i": index, "n": total, "c": content}.
+	ChunkEvent = "diff_chunk"
+)
+
+// SetSlotStreaming configures slot streaming: HTML slots larger than
+// threshold bytes are sent as chunkSize chunks on the socket's low-priority
+// queue, after the rest of the diff, so a huge table does not hold up
+// heartbeats or other slots. The client reassembles them and drops a stream
+// superseded by a newer diff. A threshold of 0 disables streaming.
+func (r *Router) SetSlotStreaming(threshold, chunkSize int) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultSlotChunkSize
+	}
+	r.mu.Lock()
+	r.streamThreshold = threshold
+	r.streamChunkSize = chunkSize
+	r.mu.Unlock()
+}
+
+// slotStream is an HTML slot to be streamed.
+type slotStream struct {
+	id      string
+	content string
+}
+
+// splitLargeSlots removes HTML slots over the threshold from payload and
+// returns them, in slot order.
+func (r *Router) splitLargeSlots(payload *core.DiffPayload) []slotStream {
+	r.mu.RLock()
+	threshold := r.streamThreshold
+	r.mu.RUnlock()
+	if threshold <= 0 {
+		return nil
+	}
+
+	var streams []slotStream
+	for id, content := range payload.HTMLSlots {
+		if len(content) > threshold {
+			streams = append(streams, slotStream{id: id, content: content})
+			delete(payload.HTMLSlots, id)
+		}
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].id < streams[j].id })
+	return streams
+}
+
+// sendSlotStreams queues the chunks of each stream as bulk messages.
+func (r *Router) sendSlotStreams(session *LiveViewSession, version uint64, streams []slotStream) {
+	r.mu.RLock()
+	size := r.streamChunkSize
+	r.mu.RUnlock()
+
+	for _, s := range streams {
+		chunks := chunkString(s.content, size)
+		for i, c := range chunks {
+			err := session.Transport.SendBulk(transport.Message{
+				Topic: session.Topic,
+				Event: ChunkEvent,
+				Payload: map[string]any{
+					"v":  version,
+					"id": s.id,
+					"i":  i,
+					"n":  len(chunks),
+					"c":  c,
+				},
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// chunkString splits s into pieces of at most size bytes without splitting
+// UTF-8 sequences.
+func chunkString(s string, size int) []string {
+	chunks := make([]string, 0, len(s)/size+1)
+	for len(s) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = size
+		}
+		chunks = append(chunks, s[:cut])
+		s = s[cut:]
+	}
+	return append(chunks, s)
+}
@@ -0,0 +1,50 @@
+package router
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestChunkString(t *testing.T) {
+	s := strings.Repeat("añb€", 1000)
+	chunks := chunkString(s, 7)
+
+	if strings.Join(chunks, "") != s {
+		t.Fatal("Expected chunks to reassemble to the original")
+	}
+	for _, c := range chunks {
+		if len(c) > 7 || !utf8.ValidString(c) {
+			t.Fatalf("Invalid chunk %q", c)
+		}
+	}
+	if got := chunkString("abc", 16); len(got) != 1 || got[0] != "abc" {
+		t.Errorf("Expected a single chunk, got %q", got)
+	}
+}
+
+func TestRouter_SplitLargeSlots(t *testing.T) {
+	r := New()
+	r.SetSlotStreaming(10, 4)
+
+	payload := &core.DiffPayload{HTMLSlots: map[string]string{
+		"small": "<b>x</b>",
+		"table": strings.Repeat("<tr></tr>", 5),
+	}}
+	streams := r.splitLargeSlots(payload)
+
+	if len(streams) != 1 || streams[0].id != "table" {
+		t.Fatalf("Expected the table slot to be streamed, got %+v", streams)
+	}
+	if _, ok := payload.HTMLSlots["table"]; ok || payload.HTMLSlots["small"] == "" {
+		t.Errorf("Expected only the large slot to leave the diff: %v", payload.HTMLSlots)
+	}
+
+	r.SetSlotStreaming(0, 0)
+	payload.HTMLSlots["table"] = streams[0].content
+	if len(r.splitLargeSlots(payload)) != 0 {
+		t.Error("Expected streaming to be disabled")
+	}
+}
@@ -0,0 +1,71 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// tabGroups tracks the core.TabGroup of each browser whose client has the
+// tabSync option on, with the number of its sockets.
+type tabGroups struct {
+	mu     sync.Mutex
+	groups map[string]*tabGroupRef
+}
+
+type tabGroupRef struct {
+	group   *core.TabGroup
+	sockets int
+}
+
+// joinTabGroup puts the session's socket in the group of the tabs of its
+// browser, named by the random "tab_group" ID the client keeps in
+// localStorage. The group is scoped to the login, so a guessed ID does not
+// reach other users' tabs.
+func (r *Router) joinTabGroup(session *LiveViewSession, payload map[string]any) {
+	id := joinString(payload["tab_group"])
+	session.mu.Lock()
+	joined := session.tabGroup != ""
+	session.mu.Unlock()
+	if id == "" || joined {
+		return
+	}
+	key := sessionOwner(session.Session) + " " + id
+
+	g := &r.tabGroups
+	g.mu.Lock()
+	if g.groups == nil {
+		g.groups = make(map[string]*tabGroupRef)
+	}
+	ref := g.groups[key]
+	if ref == nil {
+		ref = &tabGroupRef{group: core.NewTabGroup()}
+		g.groups[key] = ref
+	}
+	ref.sockets++
+	g.mu.Unlock()
+
+	session.mu.Lock()
+	session.tabGroup = key
+	session.mu.Unlock()
+	session.Socket.SetTabGroup(ref.group)
+}
+
+// leaveTabGroup takes the session out of its tab group, dropping the group
+// with its last tab: a tab opened later gets every value again.
+func (r *Router) leaveTabGroup(session *LiveViewSession) {
+	session.mu.Lock()
+	key := session.tabGroup
+	session.mu.Unlock()
+	if key == "" {
+		return
+	}
+	g := &r.tabGroups
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if ref := g.groups[key]; ref != nil {
+		if ref.sockets--; ref.sockets <= 0 {
+			delete(g.groups, key)
+		}
+	}
+}
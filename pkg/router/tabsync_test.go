@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// inbox pushes the unread counts it receives as shared state.
+type inbox struct {
+	loopCounter
+}
+
+func (c *inbox) HandleInfo(ctx context.Context, msg any) error {
+	return c.Socket().PushShared(map[string]any{"unread": msg})
+}
+
+// joinTab joins as a tab of the browser with the tab group id, returning
+// the socket and its session.
+func joinTab(t *testing.T, ctx context.Context, r *Router, url, group string) (*websocket.Conn, *LiveViewSession) {
+	t.Helper()
+	before := map[string]bool{}
+	for _, s := range r.SessionManager().All() {
+		before[s.ID] = true
+	}
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(url, "http")+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsjson.Write(ctx, ws, map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join",
+		"payload": map[string]any{"tab_group": group}})
+	var reply map[string]any
+	if err := wsjson.Read(ctx, ws, &reply); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range r.SessionManager().All() {
+		if !before[s.ID] {
+			return ws, s
+		}
+	}
+	t.Fatal("session not found")
+	return nil, nil
+}
+
+// readShared returns the next shared values pushed to ws.
+func readShared(t *testing.T, ctx context.Context, ws *websocket.Conn) map[string]any {
+	t.Helper()
+	for {
+		var msg struct {
+			Event   string         `json:"event"`
+			Payload map[string]any `json:"payload"`
+		}
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Event == core.SharedEvent {
+			return msg.Payload
+		}
+	}
+}
+
+func TestRouter_SharedStatePushedOncePerBrowser(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &inbox{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, a := joinTab(t, ctx, r, ts.URL, "browser-1")
+	defer first.CloseNow()
+	second, b := joinTab(t, ctx, r, ts.URL, "browser-1")
+	defer second.CloseNow()
+	other, c := joinTab(t, ctx, r, ts.URL, "browser-2")
+	defer other.CloseNow()
+
+	// Every tab's session learns of the same count
+	a.Socket.SendInfo(3)
+	if got := readShared(t, ctx, first); got["unread"] != float64(3) {
+		t.Fatalf("Expected unread 3 on the first tab, got %v", got)
+	}
+	b.Socket.SendInfo(3)
+	b.Socket.SendInfo(4)
+	if got := readShared(t, ctx, second); got["unread"] != float64(4) {
+		t.Errorf("Expected the second tab to skip the count its browser has, got %v", got)
+	}
+	c.Socket.SendInfo(3)
+	if got := readShared(t, ctx, other); got["unread"] != float64(3) {
+		t.Errorf("Expected another browser to get the count, got %v", got)
+	}
+}
@@ -7,15 +7,15 @@ import (
 	"github.com/gabrielmiguelok/golivekit/pkg/transport"
 )
 
-// TransportAdapter adapta transport.WebSocketTransport a core.Transport.
-// Permite que el Socket del core use el transport de WebSocket.
+// TransportAdapter adapta un transport.Stream (WebSocket o SSE) a
+// core.Transport. Permite que el Socket del core use el transporte.
 type TransportAdapter struct {
-	ws    *transport.WebSocketTransport
+	ws    transport.Stream
 	codec protocol.Codec
 }
 
 // NewTransportAdapter crea un nuevo adaptador de transporte.
-func NewTransportAdapter(ws *transport.WebSocketTransport, codec protocol.Codec) *TransportAdapter {
+func NewTransportAdapter(ws transport.Stream, codec protocol.Codec) *TransportAdapter {
 	if codec == nil {
 		codec = protocol.NewPhoenixCodec()
 	}
@@ -34,6 +34,7 @@ func (a *TransportAdapter) Send(msg core.Message) error {
 		Topic:   msg.Topic,
 		Event:   msg.Event,
 		Payload: msg.Payload,
+		Binary:  msg.Binary,
 	}
 
 	return a.ws.Send(transportMsg)
@@ -51,8 +52,15 @@ func (a *TransportAdapter) IsConnected() bool {
 	return a.ws.IsConnected()
 }
 
-// WebSocket retorna el transporte WebSocket subyacente.
+// WebSocket retorna el transporte WebSocket subyacente, o nil si la
+// sesión usa SSE.
 func (a *TransportAdapter) WebSocket() *transport.WebSocketTransport {
+	ws, _ := a.ws.(*transport.WebSocketTransport)
+	return ws
+}
+
+// Stream retorna el transporte subyacente.
+func (a *TransportAdapter) Stream() transport.Stream {
 	return a.ws
 }
 
@@ -68,7 +76,7 @@ type ProtocolTransportAdapter struct {
 }
 
 // NewProtocolTransportAdapter crea un adaptador con soporte completo de protocolo.
-func NewProtocolTransportAdapter(ws *transport.WebSocketTransport, codec protocol.Codec) *ProtocolTransportAdapter {
+func NewProtocolTransportAdapter(ws transport.Stream, codec protocol.Codec) *ProtocolTransportAdapter {
 	return &ProtocolTransportAdapter{
 		TransportAdapter: NewTransportAdapter(ws, codec),
 	}
@@ -0,0 +1,173 @@
+package router
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// SessionUsage is what a live session has cost the server since it
+// connected.
+type SessionUsage struct {
+	SessionID string
+	Component string
+	Path      string
+	Since     time.Time
+
+	// Events counts the HandleEvent and HandleInfo calls, and EventTime is
+	// the time spent in them.
+	Events    int64
+	EventTime time.Duration
+
+	// Renders counts the renders, and RenderTime is the time spent
+	// rendering and diffing them.
+	Renders    int64
+	RenderTime time.Duration
+
+	// BytesSent is what the transport wrote to the client (0 for
+	// transports that do not count).
+	BytesSent int64
+}
+
+// UsageOrder ranks sessions in LiveViewSessionManager.Top.
+type UsageOrder int
+
+const (
+	ByEventTime UsageOrder = iota
+	ByRenderTime
+	ByBytesSent
+)
+
+// UsageLimits are per-session thresholds for SetUsageAlert. Zero fields
+// are not checked.
+type UsageLimits struct {
+	EventTime  time.Duration
+	RenderTime time.Duration
+	BytesSent  int64
+}
+
+// exceeded reports whether u is over any of the limits.
+func (l UsageLimits) exceeded(u SessionUsage) bool {
+	return (l.EventTime > 0 && u.EventTime > l.EventTime) ||
+		(l.RenderTime > 0 && u.RenderTime > l.RenderTime) ||
+		(l.BytesSent > 0 && u.BytesSent > l.BytesSent)
+}
+
+// usageAlert calls fn for sessions over limits (see SetUsageAlert).
+type usageAlert struct {
+	limits UsageLimits
+	fn     func(SessionUsage)
+}
+
+// SetUsageAlert calls alert the first time a session goes over one of
+// limits, to log it or disconnect a runaway client:
+//
+//	r.SetUsageAlert(router.UsageLimits{EventTime: 30 * time.Second}, func(u router.SessionUsage) {
+//		slog.Warn("expensive session", "component", u.Component, "event_time", u.EventTime)
+//	})
+//
+// alert runs on the session's message loop, once per session.
+func (r *Router) SetUsageAlert(limits UsageLimits, alert func(SessionUsage)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usageAlert = &usageAlert{limits: limits, fn: alert}
+}
+
+// sessionUsage accumulates the usage of a session.
+type sessionUsage struct {
+	events      atomic.Int64
+	eventNanos  atomic.Int64
+	renders     atomic.Int64
+	renderNanos atomic.Int64
+	alerted     atomic.Bool
+}
+
+// bytesCounter is implemented by transports that count what they write.
+type bytesCounter interface {
+	BytesSent() int64
+}
+
+// Usage returns what the session has cost so far.
+func (s *LiveViewSession) Usage() SessionUsage {
+	u := SessionUsage{
+		SessionID:  s.ID,
+		Since:      s.CreatedAt,
+		Events:     s.usage.events.Load(),
+		EventTime:  time.Duration(s.usage.eventNanos.Load()),
+		Renders:    s.usage.renders.Load(),
+		RenderTime: time.Duration(s.usage.renderNanos.Load()),
+	}
+	if s.Component != nil {
+		u.Component = s.Component.Name()
+	}
+	if s.Route != nil {
+		u.Path = s.Route.Path
+	}
+	if counter, ok := s.Transport.(bytesCounter); ok {
+		u.BytesSent = counter.BytesSent()
+	}
+	return u
+}
+
+// recordEvent adds a handler call that started at start.
+func (r *Router) recordEvent(session *LiveViewSession, start time.Time) {
+	session.usage.events.Add(1)
+	session.usage.eventNanos.Add(int64(time.Since(start)))
+	r.checkUsage(session)
+}
+
+// recordRender adds a render that started at start.
+func (r *Router) recordRender(session *LiveViewSession, start time.Time) {
+	session.usage.renders.Add(1)
+	session.usage.renderNanos.Add(int64(time.Since(start)))
+	r.checkUsage(session)
+}
+
+// checkUsage calls the usage alert if session just went over its limits.
+func (r *Router) checkUsage(session *LiveViewSession) {
+	r.mu.RLock()
+	alert := r.usageAlert
+	r.mu.RUnlock()
+	if alert == nil || session.usage.alerted.Load() {
+		return
+	}
+	if u := session.Usage(); alert.limits.exceeded(u) && session.usage.alerted.CompareAndSwap(false, true) {
+		alert.fn(u)
+	}
+}
+
+// Usage returns the usage of every session.
+func (m *LiveViewSessionManager) Usage() []SessionUsage {
+	sessions := m.All()
+	usage := make([]SessionUsage, len(sessions))
+	for i, s := range sessions {
+		usage[i] = s.Usage()
+	}
+	return usage
+}
+
+// Top returns the n sessions that cost the most by order, most expensive
+// first (all of them for n <= 0).
+func (m *LiveViewSessionManager) Top(n int, order UsageOrder) []SessionUsage {
+	usage := m.Usage()
+	key := func(u SessionUsage) int64 {
+		switch order {
+		case ByRenderTime:
+			return int64(u.RenderTime)
+		case ByBytesSent:
+			return u.BytesSent
+		default:
+			return int64(u.EventTime)
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if ki, kj := key(usage[i]), key(usage[j]); ki != kj {
+			return ki > kj
+		}
+		return usage[i].SessionID < usage[j].SessionID
+	})
+	if n > 0 && len(usage) > n {
+		usage = usage[:n]
+	}
+	return usage
+}
@@ -0,0 +1,69 @@
+package router
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// slowView takes a few milliseconds per event.
+type slowView struct {
+	loopCounter
+}
+
+func (v *slowView) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	time.Sleep(5 * time.Millisecond)
+	return v.loopCounter.HandleEvent(ctx, event, payload)
+}
+
+func TestRouter_SessionUsage(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &slowView{} })
+	alerts := make(chan SessionUsage, 4)
+	r.SetUsageAlert(UsageLimits{EventTime: 12 * time.Millisecond}, func(u SessionUsage) { alerts <- u })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	busy := dialLive(t, ctx, ts.URL)
+	defer busy.CloseNow()
+	idle := dialLive(t, ctx, ts.URL)
+	defer idle.CloseNow()
+
+	for i := 0; i < 4; i++ {
+		wsjson.Write(ctx, busy, map[string]any{"ref": "2", "topic": "lv:c", "event": "inc", "payload": map[string]any{}})
+	}
+
+	var alert SessionUsage
+	select {
+	case alert = <-alerts:
+	case <-ctx.Done():
+		t.Fatal("no usage alert")
+	}
+	if alert.EventTime <= 12*time.Millisecond || alert.Path != "/" {
+		t.Errorf("alert = %+v", alert)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for r.SessionManager().Top(1, ByEventTime)[0].Events < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	top := r.SessionManager().Top(0, ByEventTime)
+	if len(top) != 2 {
+		t.Fatalf("Top returned %d sessions, want 2", len(top))
+	}
+	if top[0].SessionID != alert.SessionID || top[0].Events < 4 || top[0].Renders == 0 {
+		t.Errorf("top session = %+v, want the busy one", top[0])
+	}
+	if top[0].BytesSent == 0 || top[1].BytesSent == 0 {
+		t.Errorf("bytes sent = %d, %d, want both counted", top[0].BytesSent, top[1].BytesSent)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("alerted %d more times, want once per session", len(alerts))
+	}
+}
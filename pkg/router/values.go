@@ -0,0 +1,55 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// SetValueSigning signs the lv-value-* attributes rendered by template
+// helpers (templates.LiveTemplate's lvClick, lvSubmit, lvChange) with keys,
+// and rejects events whose signed values were changed in the browser.
+// Handlers read verified values with security.SignedValue.
+func (r *Router) SetValueSigning(keys *secrets.Keyring) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if keys == nil {
+		r.valueSigner = nil
+		return
+	}
+	r.valueSigner = security.NewValueSigner(keys)
+}
+
+// withValueSigner adds the value signer to a render or event context.
+func (r *Router) withValueSigner(ctx context.Context) context.Context {
+	r.mu.RLock()
+	signer := r.valueSigner
+	r.mu.RUnlock()
+	if signer == nil {
+		return ctx
+	}
+	return security.WithValueSigner(ctx, signer)
+}
+
+// verifyValues checks the signature of an event's values and replaces it
+// with the names of the values it covers (security.SignedKey). Names sent
+// by the client are dropped, so only the router marks values as signed.
+func (r *Router) verifyValues(ctx context.Context, event string, payload map[string]any) error {
+	delete(payload, security.SignedKey)
+	if _, ok := payload[security.SignatureKey]; !ok {
+		return nil
+	}
+	defer delete(payload, security.SignatureKey)
+
+	signer := security.ValueSignerFromContext(ctx)
+	if signer == nil {
+		return nil
+	}
+	names, err := signer.Verify(security.ValueScope(ctx), event, payload)
+	if err != nil {
+		return err
+	}
+	payload[security.SignedKey] = names
+	return nil
+}
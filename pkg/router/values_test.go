@@ -0,0 +1,47 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+func TestRouter_ValueSigning(t *testing.T) {
+	key, _ := secrets.Generate("k1")
+	keys, _ := secrets.NewKeyring(key)
+	r := New()
+	r.SetValueSigning(keys)
+	ctx := r.withValueSigner(core.WithSession(context.Background(), core.Session{"user_id": "u1"}))
+
+	sig, err := security.ValueSignerFromContext(ctx).Sign("u1", "delete", map[string]string{"id": "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := map[string]any{"id": "42", security.SignatureKey: sig}
+	if err := r.verifyValues(ctx, "delete", payload); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := security.SignedValue(payload, "id"); !ok || id != "42" {
+		t.Errorf("SignedValue = %q, %v", id, ok)
+	}
+	if _, ok := payload[security.SignatureKey]; ok {
+		t.Error("Expected the signature to be removed")
+	}
+
+	tampered := map[string]any{"id": "43", security.SignatureKey: sig}
+	if err := r.verifyValues(ctx, "delete", tampered); err != security.ErrInvalidValueSignature {
+		t.Errorf("tampered: err = %v", err)
+	}
+
+	// Clients cannot mark values as signed themselves
+	forged := map[string]any{"id": "43", security.SignedKey: []string{"id"}}
+	if err := r.verifyValues(ctx, "delete", forged); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := security.SignedValue(forged, "id"); ok {
+		t.Error("Expected a client-sent signed list to be dropped")
+	}
+}
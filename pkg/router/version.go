@@ -0,0 +1,37 @@
+package router
+
+import "github.com/gabrielmiguelok/golivekit/client"
+
+// ReloadEvent tells a client running a different build than the server to
+// refetch its assets and reload: {"reason": "version", "version": "..."}.
+const ReloadEvent = "lv:reload"
+
+// SetClientVersion sets the client build the server expects on join,
+// client.Version() by default. Clients that report another version (an old
+// tab after a deploy) are told to reload instead of joining with a protocol
+// they may not speak. An empty version disables the check.
+func (r *Router) SetClientVersion(version string) {
+	r.mu.Lock()
+	r.clientVersion = version
+	r.mu.Unlock()
+}
+
+// checkClientVersion reports whether a joining client may proceed. Clients
+// that do not report a version are let through.
+func (r *Router) checkClientVersion(session *LiveViewSession, payload map[string]any) bool {
+	r.mu.RLock()
+	expected := r.clientVersion
+	r.mu.RUnlock()
+
+	vsn, _ := payload["vsn"].(string)
+	if expected == "" || vsn == "" || vsn == expected {
+		return true
+	}
+	session.Socket.Push(ReloadEvent, map[string]any{"reason": "version", "version": expected})
+	return false
+}
+
+// defaultClientVersion is the version of the embedded client.
+func defaultClientVersion() string {
+	return client.Version()
+}
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/client"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestRouter_ClientVersionSkew(t *testing.T) {
+	r := New()
+	r.Live("/", func() core.Component { return &loopCounter{} })
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	join := func(vsn string) map[string]any {
+		ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/", nil)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer ws.CloseNow()
+		msg := map[string]any{"ref": "1", "topic": "lv:c", "event": "phx_join", "payload": map[string]any{"vsn": vsn}}
+		if err := wsjson.Write(ctx, ws, msg); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+		var reply map[string]any
+		if err := wsjson.Read(ctx, ws, &reply); err != nil {
+			t.Fatalf("join reply: %v", err)
+		}
+		return reply
+	}
+
+	reply := join("stale1")
+	if reply["event"] != ReloadEvent {
+		t.Fatalf("Expected %s for a stale client, got %v", ReloadEvent, reply["event"])
+	}
+	payload, _ := reply["payload"].(map[string]any)
+	if payload["version"] != client.Version() {
+		t.Errorf("Expected version %q, got %v", client.Version(), payload["version"])
+	}
+
+	if reply := join(client.Version()); reply["event"] != "phx_reply" {
+		t.Errorf("Expected phx_reply for a current client, got %v", reply["event"])
+	}
+
+	r.SetClientVersion("")
+	if reply := join("stale1"); reply["event"] != "phx_reply" {
+		t.Errorf("Expected phx_reply with the check disabled, got %v", reply["event"])
+	}
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/transport"
+)
+
+// handleVisibility records whether the client's page is hidden. A page
+// shown again gets the render it missed meanwhile.
+func (r *Router) handleVisibility(ctx context.Context, session *LiveViewSession, msg transport.Message) {
+	hidden, _ := msg.Payload["hidden"].(bool)
+	session.Socket.SetHidden(hidden)
+	if !hidden && session.stale && session.IsMounted() {
+		session.stale = false
+		r.renderAndSendDiff(ctx, session)
+	}
+}
+
+// renderUnlessHidden renders after an info message, unless the client's
+// page is hidden: nobody would see the diff, so the render waits for the
+// page to be shown (see handleVisibility).
+func (r *Router) renderUnlessHidden(ctx context.Context, session *LiveViewSession) {
+	if session.Socket.Hidden() {
+		session.stale = true
+		return
+	}
+	r.renderAndSendDiff(ctx, session)
+}
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestRouter_HiddenPageSkipsInfoRenders(t *testing.T) {
+	r := New()
+	var comp *loopCounter
+	r.Live("/", func() core.Component {
+		comp = &loopCounter{}
+		return comp
+	})
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ws := dialLive(t, ctx, ts.URL)
+	defer ws.CloseNow()
+
+	read := func() map[string]any {
+		t.Helper()
+		var msg map[string]any
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return msg
+	}
+	heartbeat := func() {
+		t.Helper()
+		wsjson.Write(ctx, ws, map[string]any{"ref": "hb", "topic": "phoenix", "event": "heartbeat", "payload": map[string]any{}})
+		if msg := read(); msg["ref"] != "hb" {
+			t.Fatalf("Expected no diff while hidden, got %v", msg)
+		}
+	}
+
+	wsjson.Write(ctx, ws, map[string]any{"ref": "2", "topic": "lv:c", "event": core.VisibilityEvent, "payload": map[string]any{"hidden": true}})
+	heartbeat()
+	if !comp.Socket().Hidden() {
+		t.Fatal("Expected the socket to be hidden")
+	}
+
+	// Info messages are handled, but not rendered
+	comp.Socket().SendInfo(3)
+	comp.Socket().SendInfo(4)
+	time.Sleep(50 * time.Millisecond)
+	heartbeat()
+
+	// Showing the page catches up with one render
+	wsjson.Write(ctx, ws, map[string]any{"ref": "3", "topic": "lv:c", "event": core.VisibilityEvent, "payload": map[string]any{"hidden": false}})
+	msg := read()
+	payload, _ := msg["payload"].(map[string]any)
+	slots, _ := payload["s"].(map[string]any)
+	if msg["event"] != "diff" || slots["n"] != "7" {
+		t.Errorf("Expected a catch-up diff with n=7, got %v", msg)
+	}
+}
@@ -0,0 +1,249 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// RedirectEvent tells the client to load another page: {"to": "/path"}.
+const RedirectEvent = "lv:redirect"
+
+// waitingUpdate is delivered to a waiting room when its position changes or
+// the visitor is admitted.
+type waitingUpdate struct {
+	info     OverflowInfo
+	admitted bool
+}
+
+// waitingRoom is the LiveView served to queued visitors in
+// OverflowWaitingRoom mode. Its socket does not count against any cap.
+type waitingRoom struct {
+	core.BaseComponent
+
+	quota    *quotaState
+	ticketID string
+	target   string
+	render   func(OverflowInfo) string
+	info     OverflowInfo
+}
+
+func (w *waitingRoom) Name() string {
+	return "waiting-room"
+}
+
+func (w *waitingRoom) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	info, ok := w.quota.watch(w.ticketID, w.Socket())
+	if !ok {
+		// Admitted meanwhile, or the ticket expired: reload to find out.
+		w.redirect()
+		return nil
+	}
+	w.info = info
+	return nil
+}
+
+func (w *waitingRoom) HandleInfo(ctx context.Context, msg any) error {
+	update, ok := msg.(waitingUpdate)
+	if !ok {
+		return nil
+	}
+	if update.admitted {
+		w.redirect()
+		return nil
+	}
+	w.info = update.info
+	return nil
+}
+
+func (w *waitingRoom) Terminate(ctx context.Context, reason core.TerminateReason) error {
+	w.quota.unwatch(w.ticketID, w.Socket())
+	return nil
+}
+
+func (w *waitingRoom) Render(ctx context.Context) core.Renderer {
+	body := w.render(w.info)
+	return core.RendererFunc(func(ctx context.Context, out io.Writer) error {
+		_, err := io.WriteString(out, body)
+		return err
+	})
+}
+
+func (w *waitingRoom) redirect() {
+	if s := w.Socket(); s != nil {
+		s.Push(RedirectEvent, map[string]any{"to": w.target})
+	}
+}
+
+// waitingRoomFor returns the waiting room for a socket request whose visitor
+// is queued for route, or nil.
+func (r *Router) waitingRoomFor(req *http.Request, route *LiveRoute) *waitingRoom {
+	c, err := req.Cookie(QueueCookie)
+	if err != nil {
+		return nil
+	}
+
+	r.quota.mu.Lock()
+	defer r.quota.mu.Unlock()
+	if r.quota.config.Mode != OverflowWaitingRoom {
+		return nil
+	}
+	tk := r.quota.tickets[c.Value]
+	if tk == nil || tk.route != route.Path || tk.admitted {
+		return nil
+	}
+	target := req.URL.Path
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+	return &waitingRoom{
+		quota:    r.quota,
+		ticketID: tk.id,
+		target:   target,
+		render:   r.quota.config.WaitingRoom,
+	}
+}
+
+// watch attaches a waiting room socket to a queued ticket and returns the
+// current position. ok is false if the ticket is no longer queued.
+func (q *quotaState) watch(id string, socket *core.Socket) (info OverflowInfo, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tk := q.tickets[id]
+	if tk == nil || tk.admitted {
+		return OverflowInfo{}, false
+	}
+	if tk.watcher == nil {
+		q.watchers++
+	}
+	tk.watcher = socket
+	tk.lastSeen = time.Now()
+
+	queue := q.queues[tk.route]
+	tk.position = indexOf(queue, tk) + 1
+	info = OverflowInfo{Route: tk.route, Position: tk.position, Waiting: len(queue), RetryAfter: q.config.RetryAfter}
+
+	if !q.ticking {
+		q.ticking = true
+		go q.promoteLoop()
+	}
+	return info, true
+}
+
+// unwatch detaches a waiting room socket. The ticket stays queued for
+// TicketTTL so a page reload keeps the visitor's place.
+func (q *quotaState) unwatch(id string, socket *core.Socket) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if tk := q.tickets[id]; tk != nil && tk.watcher == socket && socket != nil {
+		tk.watcher = nil
+		tk.lastSeen = time.Now()
+		q.watchers--
+	}
+}
+
+// promoteLoop promotes waiting rooms periodically, so slots freed by
+// expiring reservations are handed out too. It stops when no waiting room
+// is connected.
+func (q *quotaState) promoteLoop() {
+	for {
+		q.mu.Lock()
+		interval := q.config.RetryAfter
+		q.mu.Unlock()
+		time.Sleep(interval)
+
+		q.mu.Lock()
+		if q.watchers <= 0 {
+			q.ticking = false
+			q.mu.Unlock()
+			return
+		}
+		q.promoteAll()
+		q.mu.Unlock()
+	}
+}
+
+// promoteAll admits connected waiting rooms at the head of their queues and
+// sends position updates to the others. Must be called with q.mu held.
+func (q *quotaState) promoteAll() {
+	if q.watchers == 0 {
+		return
+	}
+	now := time.Now()
+	defer func() {
+		// Retry admissions whose notification did not get through.
+		for _, tk := range q.tickets {
+			if tk.admitted && !tk.notified && tk.watcher != nil {
+				tk.notified = tk.watcher.SendInfo(waitingUpdate{admitted: true}) == nil
+			}
+		}
+	}()
+	for path, route := range q.routes {
+		q.pruneTickets(path, now)
+		free, _ := q.free(route, now)
+
+		var admitted []*ticket
+		queue := q.queues[path]
+		for i, tk := range queue {
+			if i < free && tk.watcher != nil {
+				admitted = append(admitted, tk)
+			}
+		}
+		for _, tk := range admitted {
+			q.dequeue(tk)
+			tk.admitted, tk.admittedAt = true, now
+			q.tickets[tk.id] = tk
+			q.reserve(path, now)
+		}
+
+		queue = q.queues[path]
+		for i, tk := range queue {
+			if tk.watcher == nil || tk.position == i+1 {
+				continue
+			}
+			info := OverflowInfo{Route: path, Position: i + 1, Waiting: len(queue), RetryAfter: q.config.RetryAfter}
+			if tk.watcher.SendInfo(waitingUpdate{info: info}) == nil {
+				tk.position = i + 1
+			}
+		}
+	}
+}
+
+// serveWaitingRoom writes the initial waiting room page. The client then
+// connects a socket on the same URL, which the router routes to the waiting
+// room instead of the route's component.
+func serveWaitingRoom(w http.ResponseWriter, config QuotaConfig, info OverflowInfo) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(info.RetryAfter.Seconds())))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	// Without JavaScript the page falls back to refreshing itself.
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>You're in line</title>
+<noscript><meta http-equiv="refresh" content="%d"></noscript>
+<style>%s</style>
+</head><body><div data-live-view="waiting-room">%s</div><script src="%s"></script></body></html>`,
+		max(1, int(info.RetryAfter.Seconds())), waitingRoomCSS, config.WaitingRoom(info), html.EscapeString(config.ClientScript))
+}
+
+// DefaultWaitingRoom renders the default waiting room body.
+func DefaultWaitingRoom(info OverflowInfo) string {
+	var b strings.Builder
+	b.WriteString(`<main class="lv-waiting"><h1>You're in line</h1>`)
+	fmt.Fprintf(&b, `<p>You are number <strong data-slot="waiting-position">%d</strong> of <span data-slot="waiting-total">%d</span>.</p>`,
+		info.Position, info.Waiting)
+	b.WriteString(`<p class="lv-waiting-note">Keep this page open: you'll be taken in automatically as soon as there's room.</p></main>`)
+	return b.String()
+}
+
+const waitingRoomCSS = `body{font-family:system-ui,sans-serif;display:flex;min-height:100vh;align-items:center;justify-content:center;margin:0;color:#334155;background:#f8fafc}` +
+	`.lv-waiting{text-align:center;max-width:28rem;padding:2rem}.lv-waiting h1{font-size:1.5rem;margin:0 0 .5rem}` +
+	`.lv-waiting strong{font-size:1.25rem;color:#0f172a}.lv-waiting-note{font-size:.875rem;color:#64748b}`
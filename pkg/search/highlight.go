@@ -0,0 +1,113 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Highlight HTML-escapes text and wraps words matching any of terms (as a
+// prefix, case-insensitively) in <mark>.
+func Highlight(text string, terms []string) string {
+	var sb strings.Builder
+	sb.Grow(len(text) + 16)
+
+	forEachWord(text, func(segment string, isWord bool) {
+		if isWord && matchesAny(segment, terms) {
+			sb.WriteString("<mark>")
+			sb.WriteString(html.EscapeString(segment))
+			sb.WriteString("</mark>")
+			return
+		}
+		sb.WriteString(html.EscapeString(segment))
+	})
+	return sb.String()
+}
+
+// Snippet returns a highlighted excerpt of about maxLen bytes centered on
+// the first match, with ellipses where text was cut.
+func Snippet(text string, terms []string, maxLen int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= maxLen {
+		return Highlight(text, terms)
+	}
+
+	first := -1
+	offset := 0
+	forEachWord(text, func(segment string, isWord bool) {
+		if first < 0 && isWord && matchesAny(segment, terms) {
+			first = offset
+		}
+		offset += len(segment)
+	})
+
+	start := 0
+	if first > maxLen/3 {
+		start = first - maxLen/3
+	}
+	end := start + maxLen
+	if end > len(text) {
+		end = len(text)
+		start = max(0, end-maxLen)
+	}
+	start, end = wordBoundary(text, start, true), wordBoundary(text, end, false)
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	sb.WriteString(Highlight(text[start:end], terms))
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// forEachWord splits text into alternating word and non-word segments.
+func forEachWord(text string, fn func(segment string, isWord bool)) {
+	start := 0
+	inWord := false
+	for i, r := range text {
+		w := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if i > 0 && w != inWord {
+			fn(text[start:i], inWord)
+			start = i
+		}
+		inWord = w
+	}
+	if start < len(text) {
+		fn(text[start:], inWord)
+	}
+}
+
+func matchesAny(word string, terms []string) bool {
+	lower := strings.ToLower(word)
+	for _, t := range terms {
+		if t != "" && strings.HasPrefix(lower, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// wordBoundary moves i to a space (forward for the start of a snippet,
+// backward for the end) so words are not cut in half.
+func wordBoundary(text string, i int, forward bool) int {
+	if i <= 0 || i >= len(text) {
+		return i
+	}
+	for !utf8.RuneStart(text[i]) {
+		i--
+	}
+	if forward {
+		if j := strings.IndexByte(text[i:], ' '); j >= 0 && j < 20 {
+			return i + j + 1
+		}
+		return i
+	}
+	if j := strings.LastIndexByte(text[:i], ' '); j >= 0 && i-j < 20 {
+		return j
+	}
+	return i
+}
@@ -0,0 +1,158 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Field weights used by MemoryIndex scoring.
+const (
+	titleWeight = 3.0
+	bodyWeight  = 1.0
+)
+
+// MemoryIndex is an inverted index held in memory, suited to documentation,
+// settings pages and other small corpora. All query terms must match; the
+// last term also matches as a prefix so results update while typing.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]Document
+	postings map[string]map[string]float64 // term -> doc ID -> weight
+	docTerms map[string][]string           // doc ID -> terms, for removal
+}
+
+// NewMemoryIndex creates an empty index.
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[string]Document),
+		postings: make(map[string]map[string]float64),
+		docTerms: make(map[string][]string),
+	}
+}
+
+// Index adds documents, replacing any with the same ID.
+func (m *MemoryIndex) Index(docs ...Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, doc := range docs {
+		m.remove(doc.ID)
+
+		weights := make(map[string]float64)
+		for _, t := range Terms(doc.Title) {
+			weights[t] += titleWeight
+		}
+		for _, t := range Terms(doc.Body) {
+			weights[t] += bodyWeight
+		}
+
+		terms := make([]string, 0, len(weights))
+		for t, w := range weights {
+			if m.postings[t] == nil {
+				m.postings[t] = make(map[string]float64)
+			}
+			m.postings[t][doc.ID] = w
+			terms = append(terms, t)
+		}
+		m.docs[doc.ID] = doc
+		m.docTerms[doc.ID] = terms
+	}
+}
+
+// Remove deletes a document from the index.
+func (m *MemoryIndex) Remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(id)
+}
+
+// Len returns the number of indexed documents.
+func (m *MemoryIndex) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.docs)
+}
+
+// remove deletes a document. Caller holds m.mu.
+func (m *MemoryIndex) remove(id string) {
+	for _, t := range m.docTerms[id] {
+		delete(m.postings[t], id)
+		if len(m.postings[t]) == 0 {
+			delete(m.postings, t)
+		}
+	}
+	delete(m.docTerms, id)
+	delete(m.docs, id)
+}
+
+// Search implements Backend. Scores are term weight times inverse document
+// frequency, with prefix matches counting half.
+func (m *MemoryIndex) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := float64(len(m.docs))
+	var scores map[string]float64
+	for i, term := range terms {
+		matches := make(map[string]float64)
+		add := func(t string, factor float64) {
+			idf := math.Log(1 + total/float64(len(m.postings[t])))
+			for id, w := range m.postings[t] {
+				matches[id] += w * idf * factor
+			}
+		}
+
+		add(term, 1)
+		if i == len(terms)-1 {
+			for t := range m.postings {
+				if t != term && strings.HasPrefix(t, term) {
+					add(t, 0.5)
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for id := range scores {
+			if _, ok := matches[id]; !ok {
+				delete(scores, id)
+			} else {
+				scores[id] += matches[id]
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Document: m.docs[id], Score: score})
+	}
+	sortHits(hits)
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// sortHits orders by score, then title for stable output.
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Title < hits[j].Title
+	})
+}
@@ -0,0 +1,91 @@
+// Package search provides full-text search for live components: pluggable
+// backends (an in-memory index, SQL LIKE queries, or any engine wrapped in a
+// BackendFunc), result highlighting, and a SearchBox component with debounced
+// input and keyboard navigation.
+//
+// Usage:
+//
+//	idx := search.NewMemoryIndex()
+//	idx.Index(search.Document{ID: "1", Title: "Routing", Body: "...", URL: "/docs/routing"})
+//
+//	// Mount
+//	c.search = search.NewSearchBox("docs", idx)
+//
+//	// HandleEvent
+//	if handled, err := c.search.HandleEvent(ctx, event, payload); handled {
+//		return err
+//	}
+//
+//	// Render
+//	c.search.Render()
+package search
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Document is a searchable item.
+type Document struct {
+	ID    string
+	Title string
+	Body  string
+	URL   string
+	// Meta carries backend-independent extras (section, icon, ...).
+	Meta map[string]string
+}
+
+// Hit is a search result.
+type Hit struct {
+	Document
+	Score float64
+}
+
+// Backend runs queries. Implementations must be safe for concurrent use.
+type Backend interface {
+	Search(ctx context.Context, query string, limit int) ([]Hit, error)
+}
+
+// BackendFunc adapts a function to Backend. Use it to plug in engines such
+// as Bleve or Elasticsearch without this package depending on them:
+//
+//	search.BackendFunc(func(ctx context.Context, q string, limit int) ([]search.Hit, error) {
+//		req := bleve.NewSearchRequestOptions(bleve.NewMatchQuery(q), limit, 0, false)
+//		req.Fields = []string{"title", "body", "url"}
+//		res, err := index.SearchInContext(ctx, req)
+//		...
+//	})
+type BackendFunc func(ctx context.Context, query string, limit int) ([]Hit, error)
+
+// Search implements Backend.
+func (f BackendFunc) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	return f(ctx, query, limit)
+}
+
+// TextFromHTML extracts indexable text from rendered HTML: tags are dropped,
+// entities decoded and whitespace collapsed.
+func TextFromHTML(s string) string {
+	var sb strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+			sb.WriteByte(' ')
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(sb.String())), " ")
+}
+
+// Terms splits a query or text into lowercase search terms.
+func Terms(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+func testIndex() *MemoryIndex {
+	idx := NewMemoryIndex()
+	idx.Index(
+		Document{ID: "routing", Title: "Routing", Body: "Register live routes with r.Live and path params."},
+		Document{ID: "forms", Title: "Forms", Body: "Changesets validate params before they reach the router."},
+		Document{ID: "state", Title: "State", Body: "Session state is kept in memory."},
+	)
+	return idx
+}
+
+func TestMemoryIndex_Search(t *testing.T) {
+	idx := testIndex()
+	ctx := context.Background()
+
+	hits, err := idx.Search(ctx, "params", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits for params, got %+v", hits)
+	}
+
+	// Title matches outrank body matches; the last term matches as a prefix.
+	hits, _ = idx.Search(ctx, "rout", 10)
+	if len(hits) != 2 || hits[0].ID != "routing" {
+		t.Errorf("Expected routing first for prefix query, got %+v", hits)
+	}
+
+	// All terms must match.
+	hits, _ = idx.Search(ctx, "session memory", 10)
+	if len(hits) != 1 || hits[0].ID != "state" {
+		t.Errorf("Expected only state, got %+v", hits)
+	}
+
+	idx.Remove("state")
+	if hits, _ = idx.Search(ctx, "session", 10); len(hits) != 0 || idx.Len() != 2 {
+		t.Errorf("Expected removed document to disappear, got %+v", hits)
+	}
+}
+
+func TestHighlightAndSnippet(t *testing.T) {
+	got := Highlight(`Live <b>routes</b> & Routing`, Terms("rout"))
+	want := `Live &lt;b&gt;<mark>routes</mark>&lt;/b&gt; &amp; <mark>Routing</mark>`
+	if got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+
+	text := strings.Repeat("filler words here ", 20) + "the needle is here " + strings.Repeat("more text ", 20)
+	snippet := Snippet(text, []string{"needle"}, 60)
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") || !strings.Contains(snippet, "<mark>needle</mark>") {
+		t.Errorf("Unexpected snippet: %q", snippet)
+	}
+}
+
+func TestTextFromHTML(t *testing.T) {
+	got := TextFromHTML("<h1>Getting&nbsp;Started</h1>\n<p>Run <code>golive new</code></p>")
+	if got != "Getting Started Run golive new" {
+		t.Errorf("TextFromHTML = %q", got)
+	}
+}
+
+func TestSQLBackend_BuildQuery(t *testing.T) {
+	b := NewSQLBackend(nil, "docs")
+	b.Dialect = migrate.Postgres
+
+	stmt, args := b.buildQuery([]string{"50%", "off"}, 5)
+	want := `SELECT id, title, body FROM docs WHERE (LOWER(title) LIKE $1 ESCAPE '\' OR LOWER(body) LIKE $2 ESCAPE '\') AND (LOWER(title) LIKE $3 ESCAPE '\' OR LOWER(body) LIKE $4 ESCAPE '\') LIMIT $5`
+	if stmt != want {
+		t.Errorf("Unexpected query:\n%s\nwant:\n%s", stmt, want)
+	}
+	if len(args) != 5 || args[0] != `%50\%%` || args[4] != 5 {
+		t.Errorf("Unexpected args: %v", args)
+	}
+}
+
+func TestSearchBox(t *testing.T) {
+	box := NewSearchBox("docs", testIndex())
+	box.SelectEvent = EventSelect
+	ctx := context.Background()
+
+	if handled, _ := box.HandleEvent(ctx, EventQuery, map[string]any{"box": "other", "value": "x"}); handled {
+		t.Error("Expected events for another box to be ignored")
+	}
+	if handled, err := box.HandleEvent(ctx, EventQuery, map[string]any{"box": "docs", "value": "forms"}); !handled || err != nil {
+		t.Fatalf("Expected query to be handled, got %v %v", handled, err)
+	}
+
+	out := box.ResultsHTML()
+	for _, want := range []string{`data-slot="search-docs"`, `role="option"`, `<mark>Forms</mark>`, `lv-value-id="forms"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %s", want, out)
+		}
+	}
+
+	doc, ok := box.Selected(EventSelect, map[string]any{"box": "docs", "id": "forms"})
+	if !ok || doc.ID != "forms" {
+		t.Errorf("Expected forms to be selected, got %+v %v", doc, ok)
+	}
+
+	box.Search(ctx, "zzz")
+	if out := box.ResultsHTML(); !strings.Contains(out, "No results") {
+		t.Errorf("Expected empty state, got %s", out)
+	}
+
+	failing := NewSearchBox("f", BackendFunc(func(context.Context, string, int) ([]Hit, error) {
+		return nil, errors.New("down")
+	}))
+	if err := failing.Search(ctx, "x"); err == nil || !strings.Contains(failing.ResultsHTML(), "Search failed") {
+		t.Error("Expected backend errors to be reported")
+	}
+}
@@ -0,0 +1,194 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+)
+
+// SearchBox events. Payloads carry "box" so several boxes can share a
+// component.
+const (
+	EventQuery  = "search:query"  // {"box": id, "value": query}
+	EventSelect = "search:select" // {"box": id, "id": document ID}
+)
+
+// SearchBox is a search input with live results. The input is debounced on
+// the client; ArrowUp/ArrowDown/Enter/Escape navigate results without a
+// round trip. Render the input and results with Render, or InputHTML and
+// ResultsHTML separately when they live in different parts of the page.
+type SearchBox struct {
+	ID          string
+	Placeholder string
+	// Limit caps the number of results (default 8).
+	Limit int
+	// Debounce is the input debounce in milliseconds (default 200).
+	Debounce int
+	// SnippetLength is the excerpt length in bytes (default 140).
+	SnippetLength int
+	// SelectEvent, if set, makes results buttons that push this event with
+	// the document ID ("id") instead of links to Document.URL. EventSelect
+	// is handled by Selected.
+	SelectEvent string
+
+	backend Backend
+
+	mu    sync.RWMutex
+	query string
+	hits  []Hit
+	err   error
+}
+
+// NewSearchBox creates a search box over backend.
+func NewSearchBox(id string, backend Backend) *SearchBox {
+	return &SearchBox{
+		ID:            id,
+		Placeholder:   "Search…",
+		Limit:         8,
+		Debounce:      200,
+		SnippetLength: 140,
+		backend:       backend,
+	}
+}
+
+// HandleEvent runs queries for EventQuery and reports whether the event
+// belonged to this box.
+func (b *SearchBox) HandleEvent(ctx context.Context, event string, payload map[string]any) (bool, error) {
+	if event != EventQuery || !b.owns(payload) {
+		return false, nil
+	}
+	query, _ := payload["value"].(string)
+	return true, b.Search(ctx, query)
+}
+
+// Selected returns the document chosen with EventSelect.
+func (b *SearchBox) Selected(event string, payload map[string]any) (Document, bool) {
+	if event != EventSelect || !b.owns(payload) {
+		return Document{}, false
+	}
+	id, _ := payload["id"].(string)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, h := range b.hits {
+		if h.ID == id {
+			return h.Document, true
+		}
+	}
+	return Document{}, false
+}
+
+// Search runs query and stores the results for rendering.
+func (b *SearchBox) Search(ctx context.Context, query string) error {
+	query = strings.TrimSpace(query)
+
+	var hits []Hit
+	var err error
+	if query != "" {
+		hits, err = b.backend.Search(ctx, query, b.Limit)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.query, b.hits, b.err = query, hits, err
+	return err
+}
+
+// Clear resets the query and results.
+func (b *SearchBox) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.query, b.hits, b.err = "", nil, nil
+}
+
+// Query returns the current query.
+func (b *SearchBox) Query() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.query
+}
+
+// Hits returns the current results.
+func (b *SearchBox) Hits() []Hit {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]Hit(nil), b.hits...)
+}
+
+// Render returns the input followed by the results.
+func (b *SearchBox) Render() string {
+	return `<div class="lv-search" role="search">` + b.InputHTML() + b.ResultsHTML() + `</div>`
+}
+
+// InputHTML renders the search input. It is not a data-slot, so typing is
+// never interrupted by re-renders.
+func (b *SearchBox) InputHTML() string {
+	b.mu.RLock()
+	query := b.query
+	b.mu.RUnlock()
+
+	id := html.EscapeString(b.ID)
+	return fmt.Sprintf(`<input type="search" class="lv-search-input" role="combobox" aria-autocomplete="list" aria-controls="%s-results" aria-label="%s" placeholder="%s" autocomplete="off" value="%s" lv-input="%s" lv-debounce="%d" lv-value-box="%s" lv-search="%s-results">`,
+		id, html.EscapeString(b.Placeholder), html.EscapeString(b.Placeholder), html.EscapeString(query),
+		EventQuery, b.Debounce, id, id)
+}
+
+// ResultsHTML renders the results list as a data-slot.
+func (b *SearchBox) ResultsHTML() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	id := html.EscapeString(b.ID)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<div class="lv-search-results" data-slot="search-%s">`, id)
+
+	switch {
+	case b.err != nil:
+		sb.WriteString(`<p class="lv-search-empty" role="alert">Search failed. Please try again.</p>`)
+	case b.query != "" && len(b.hits) == 0:
+		fmt.Fprintf(&sb, `<p class="lv-search-empty" role="status">No results for “%s”</p>`, html.EscapeString(b.query))
+	case len(b.hits) > 0:
+		terms := Terms(b.query)
+		fmt.Fprintf(&sb, `<ul id="%s-results" role="listbox" aria-label="Search results">`, id)
+		for i, h := range b.hits {
+			fmt.Fprintf(&sb, `<li id="%s-opt-%d" role="option" class="lv-search-option">`, id, i)
+			if b.SelectEvent != "" {
+				fmt.Fprintf(&sb, `<button type="button" class="lv-search-hit" lv-click="%s" lv-value-box="%s" lv-value-id="%s">`,
+					html.EscapeString(b.SelectEvent), id, html.EscapeString(h.ID))
+			} else {
+				fmt.Fprintf(&sb, `<a class="lv-search-hit" href="%s">`, html.EscapeString(h.URL))
+			}
+			fmt.Fprintf(&sb, `<span class="lv-search-title">%s</span>`, Highlight(h.Title, terms))
+			if h.Body != "" {
+				fmt.Fprintf(&sb, `<span class="lv-search-snippet">%s</span>`, Snippet(h.Body, terms, b.SnippetLength))
+			}
+			if b.SelectEvent != "" {
+				sb.WriteString(`</button></li>`)
+			} else {
+				sb.WriteString(`</a></li>`)
+			}
+		}
+		sb.WriteString(`</ul>`)
+	}
+
+	sb.WriteString(`</div>`)
+	return sb.String()
+}
+
+func (b *SearchBox) owns(payload map[string]any) bool {
+	box, _ := payload["box"].(string)
+	return box == "" || box == b.ID
+}
+
+// CSS is a default style for the search box.
+const CSS = `.lv-search{position:relative}
+.lv-search-input{width:100%;padding:0.5rem 0.75rem;border:1px solid var(--color-border,#CBD5E1);border-radius:0.5rem;font:inherit;background:var(--color-bg,#fff);color:inherit}
+.lv-search-results ul{list-style:none;margin:0.25rem 0 0;padding:0.25rem;border:1px solid var(--color-border,#CBD5E1);border-radius:0.5rem;background:var(--color-bg,#fff)}
+.lv-search-hit{display:block;width:100%;padding:0.5rem;border:0;border-radius:0.375rem;background:none;color:inherit;font:inherit;text-align:start;text-decoration:none;cursor:pointer}
+.lv-search-option.lv-search-active .lv-search-hit,.lv-search-hit:hover{background:var(--color-bgAlt,#F1F5F9)}
+.lv-search-title{display:block;font-weight:600}
+.lv-search-snippet{display:block;font-size:0.8rem;color:var(--color-textMuted,#64748B)}
+.lv-search mark{background:rgba(250,204,21,0.4);color:inherit;border-radius:0.125rem}
+.lv-search-empty{padding:0.5rem;font-size:0.875rem;color:var(--color-textMuted,#64748B)}`
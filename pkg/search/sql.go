@@ -0,0 +1,126 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/internal/sqlutil"
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+// SQLBackend searches a table with LIKE queries. It needs no full-text
+// extension, so it works on SQLite, PostgreSQL and MySQL alike; use a
+// BackendFunc over the database's native full-text search for large tables.
+//
+// Table and column names are interpolated into the query and must come from
+// trusted configuration, never from user input.
+type SQLBackend struct {
+	DB    *sql.DB
+	Table string
+
+	// Column names. URLColumn is optional.
+	IDColumn    string
+	TitleColumn string
+	BodyColumn  string
+	URLColumn   string
+
+	// Dialect selects the bind parameters: $1, $2, ... for
+	// migrate.Postgres, "?" otherwise.
+	Dialect migrate.Dialect
+}
+
+// NewSQLBackend creates a backend over table using the conventional
+// id/title/body columns.
+func NewSQLBackend(db *sql.DB, table string) *SQLBackend {
+	return &SQLBackend{
+		DB:          db,
+		Table:       table,
+		IDColumn:    "id",
+		TitleColumn: "title",
+		BodyColumn:  "body",
+	}
+}
+
+// Search implements Backend. Every term must appear in the title or body;
+// rows are ranked in Go by how often the terms occur.
+func (b *SQLBackend) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	stmt, args := b.buildQuery(terms, limit)
+	rows, err := b.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search: query: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []Hit
+	for rows.Next() {
+		var doc Document
+		dest := []any{&doc.ID, &doc.Title, &doc.Body}
+		if b.URLColumn != "" {
+			dest = append(dest, &doc.URL)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("search: scan: %w", err)
+		}
+		hits = append(hits, Hit{Document: doc, Score: score(doc, terms)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search: rows: %w", err)
+	}
+
+	sortHits(hits)
+	return hits, nil
+}
+
+func (b *SQLBackend) buildQuery(terms []string, limit int) (string, []any) {
+	placeholder := func(int) string { return "?" }
+	if b.Dialect == migrate.Postgres {
+		placeholder = sqlutil.PostgresPlaceholder
+	}
+
+	cols := []string{b.IDColumn, b.TitleColumn, b.BodyColumn}
+	if b.URLColumn != "" {
+		cols = append(cols, b.URLColumn)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT %s FROM %s WHERE ", strings.Join(cols, ", "), b.Table)
+
+	args := make([]any, 0, len(terms)*2+1)
+	for i, term := range terms {
+		if i > 0 {
+			sb.WriteString(" AND ")
+		}
+		pattern := "%" + escapeLike(term) + "%"
+		args = append(args, pattern, pattern)
+		fmt.Fprintf(&sb, `(LOWER(%s) LIKE %s ESCAPE '\' OR LOWER(%s) LIKE %s ESCAPE '\')`,
+			b.TitleColumn, placeholder(len(args)-1), b.BodyColumn, placeholder(len(args)))
+	}
+
+	if limit > 0 {
+		args = append(args, limit)
+		fmt.Fprintf(&sb, " LIMIT %s", placeholder(len(args)))
+	}
+	return sb.String(), args
+}
+
+// escapeLike escapes LIKE wildcards so terms match literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// score counts term occurrences, weighting the title like MemoryIndex.
+func score(doc Document, terms []string) float64 {
+	title, body := strings.ToLower(doc.Title), strings.ToLower(doc.Body)
+	var s float64
+	for _, t := range terms {
+		s += titleWeight*float64(strings.Count(title, t)) + bodyWeight*float64(strings.Count(body, t))
+	}
+	return s
+}
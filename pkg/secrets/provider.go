@@ -0,0 +1,160 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultEnvVar is read by EnvProvider when Var is empty.
+const DefaultEnvVar = "GOLIVEKIT_SECRET_KEYS"
+
+// Provider loads keys, primary first.
+type Provider interface {
+	Load(ctx context.Context) ([]Key, error)
+}
+
+// ProviderFunc adapts a function to Provider.
+type ProviderFunc func(ctx context.Context) ([]Key, error)
+
+// Load calls f.
+func (f ProviderFunc) Load(ctx context.Context) ([]Key, error) {
+	return f(ctx)
+}
+
+// EnvProvider reads keys from an environment variable in the ParseKeys
+// format.
+type EnvProvider struct {
+	Var string
+}
+
+// Load implements Provider.
+func (p EnvProvider) Load(ctx context.Context) ([]Key, error) {
+	name := p.Var
+	if name == "" {
+		name = DefaultEnvVar
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("secrets: %s is not set", name)
+	}
+	return ParseKeys(value)
+}
+
+// FileProvider reads keys from a file in the ParseKeys format, typically a
+// mounted secret that the orchestrator updates in place on rotation.
+type FileProvider struct {
+	Path string
+}
+
+// Load implements Provider.
+func (p FileProvider) Load(ctx context.Context) ([]Key, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+	return ParseKeys(string(data))
+}
+
+// KMSProvider unwraps keys encrypted by a key management service. Source
+// returns the wrapped keys (from env or a file, say) and Unwrap calls the
+// KMS, e.g. AWS KMS Decrypt or GCP Cloud KMS Decrypt.
+type KMSProvider struct {
+	Source Provider
+	Unwrap func(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Load implements Provider.
+func (p KMSProvider) Load(ctx context.Context) ([]Key, error) {
+	keys, err := p.Source.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		material, err := p.Unwrap(ctx, key.ID, key.Material)
+		if err != nil {
+			return nil, fmt.Errorf("secrets: failed to unwrap %q: %w", key.ID, err)
+		}
+		keys[i].Material = material
+	}
+	return keys, nil
+}
+
+// ParseKeys parses "id:base64" entries separated by commas or newlines,
+// primary first. Blank lines and lines starting with # are ignored.
+// Wrapped KMS keys are often shorter than MinKeyLen, so lengths are checked
+// when the keys are added to a Keyring rather than here.
+func ParseKeys(s string) ([]Key, error) {
+	var keys []Key
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			id, encoded, ok := strings.Cut(entry, ":")
+			if !ok {
+				return nil, fmt.Errorf("%w: entry must be id:base64", ErrInvalidKeyID)
+			}
+			material, err := decodeMaterial(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("secrets: key %q: %w", id, err)
+			}
+			keys = append(keys, Key{ID: id, Material: material})
+		}
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return keys, nil
+}
+
+func decodeMaterial(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid base64")
+}
+
+// Load creates a keyring from p.
+func Load(ctx context.Context, p Provider) (*Keyring, error) {
+	keys, err := p.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring(keys...)
+}
+
+// Reload replaces the keys with the ones from p. On error the current keys
+// are kept.
+func (k *Keyring) Reload(ctx context.Context, p Provider) error {
+	keys, err := p.Load(ctx)
+	if err != nil {
+		return err
+	}
+	return k.Set(keys...)
+}
+
+// Watch reloads the keys from p every interval until ctx is done, so a
+// rotation takes effect without a restart. Errors go to onError, if set.
+func (k *Keyring) Watch(ctx context.Context, p Provider, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Reload(ctx, p); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
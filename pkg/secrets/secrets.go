@@ -0,0 +1,382 @@
+// Package secrets provides the signing and encryption keys used by sessions,
+// CSRF tokens, recovery tokens, download links and cookies.
+//
+// Keys live in a Keyring. The first key is the primary: it signs and
+// encrypts. Every key verifies and decrypts, so rotating is a matter of
+// putting a new key first and removing the old one once the tokens it signed
+// have expired:
+//
+//	GOLIVEKIT_SECRET_KEYS="k2:<base64>,k1:<base64>"
+//
+//	keys, err := secrets.Load(ctx, secrets.EnvProvider{})
+//	csrf := security.NewCSRFProtection(security.CSRFConfig{Keys: keys.Derive("csrf")})
+//	go keys.Watch(ctx, secrets.EnvProvider{}, time.Minute, nil)
+//
+// Derive gives each consumer its own subkeys, so a token signed for one
+// purpose is never accepted for another.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Common errors.
+var (
+	ErrNoKeys       = errors.New("secrets: keyring is empty")
+	ErrWeakKey      = errors.New("secrets: key material is too short")
+	ErrInvalidKeyID = errors.New("secrets: invalid key id")
+	ErrDuplicateKey = errors.New("secrets: duplicate key id")
+	ErrInvalid      = errors.New("secrets: invalid signature or ciphertext")
+	ErrExpired      = errors.New("secrets: token expired")
+)
+
+// MinKeyLen is the minimum key material length in bytes.
+const MinKeyLen = 16
+
+// Key is a named secret.
+type Key struct {
+	ID       string
+	Material []byte
+	// NotAfter, if set, is when the key stops verifying. Rotate sets it on
+	// the previous primary so it is dropped after a grace period.
+	NotAfter time.Time
+}
+
+// Generate returns a key with 32 random bytes.
+func Generate(id string) (Key, error) {
+	material := make([]byte, 32)
+	if _, err := rand.Read(material); err != nil {
+		return Key{}, fmt.Errorf("secrets: failed to generate key: %w", err)
+	}
+	return Key{ID: id, Material: material}, nil
+}
+
+func (k Key) active(now time.Time) bool {
+	return k.NotAfter.IsZero() || now.Before(k.NotAfter)
+}
+
+// Keyring holds the active keys, primary first. It is safe for concurrent
+// use.
+type Keyring struct {
+	mu   sync.RWMutex
+	keys []Key
+
+	// Derived keyrings read their keys from parent on every call, so
+	// rotations of the root apply to them immediately.
+	parent  *Keyring
+	purpose string
+}
+
+// NewKeyring creates a keyring. The first key is the primary.
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	k := &Keyring{}
+	if err := k.Set(keys...); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Set replaces all keys. Mutating a derived keyring mutates its root.
+func (k *Keyring) Set(keys ...Key) error {
+	if err := validate(keys); err != nil {
+		return err
+	}
+	root := k.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.keys = copyKeys(keys)
+	return nil
+}
+
+// Rotate makes next the primary. The previous keys keep verifying for grace
+// (forever if grace is zero, until removed with Retire or Set).
+func (k *Keyring) Rotate(next Key, grace time.Duration) error {
+	root := k.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	keys := append([]Key{next}, copyKeys(root.keys)...)
+	if err := validate(keys); err != nil {
+		return err
+	}
+	if grace > 0 {
+		notAfter := time.Now().Add(grace)
+		for i := 1; i < len(keys); i++ {
+			if keys[i].NotAfter.IsZero() || keys[i].NotAfter.After(notAfter) {
+				keys[i].NotAfter = notAfter
+			}
+		}
+	}
+	root.keys = keys
+	return nil
+}
+
+// Retire removes a key. The primary cannot be retired; rotate first.
+func (k *Keyring) Retire(id string) bool {
+	root := k.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	for i := 1; i < len(root.keys); i++ {
+		if root.keys[i].ID == id {
+			root.keys = append(root.keys[:i:i], root.keys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Derive returns a view of the keyring whose keys are derived for purpose
+// ("csrf", "session", "download"...). Derived keys keep their IDs.
+func (k *Keyring) Derive(purpose string) *Keyring {
+	return &Keyring{parent: k, purpose: purpose}
+}
+
+// Keys returns the active keys, primary first.
+func (k *Keyring) Keys() []Key {
+	now := time.Now()
+	var keys []Key
+	if k.parent != nil {
+		keys = k.parent.Keys()
+		for i := range keys {
+			keys[i].Material = derive(keys[i].Material, k.purpose)
+		}
+	} else {
+		k.mu.RLock()
+		keys = copyKeys(k.keys)
+		k.mu.RUnlock()
+	}
+
+	active := keys[:0]
+	for i, key := range keys {
+		// The primary never expires: it is the only key that can sign.
+		if i == 0 || key.active(now) {
+			active = append(active, key)
+		}
+	}
+	return active
+}
+
+// Primary returns the signing key.
+func (k *Keyring) Primary() (Key, error) {
+	keys := k.Keys()
+	if len(keys) == 0 {
+		return Key{}, ErrNoKeys
+	}
+	return keys[0], nil
+}
+
+// Lookup returns the active key with the given ID.
+func (k *Keyring) Lookup(id string) (Key, bool) {
+	for _, key := range k.Keys() {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Sign returns the HMAC-SHA256 of data under the primary key.
+func (k *Keyring) Sign(data []byte) ([]byte, error) {
+	key, err := k.Primary()
+	if err != nil {
+		return nil, err
+	}
+	return mac(key.Material, data), nil
+}
+
+// Verify reports whether sig is a valid signature of data under any active
+// key.
+func (k *Keyring) Verify(data, sig []byte) bool {
+	for _, key := range k.Keys() {
+		if hmac.Equal(sig, mac(key.Material, data)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignValue returns value with a signature appended, for use in cookies and
+// URLs: "<value>.<key id>.<base64 signature>".
+func (k *Keyring) SignValue(value string) (string, error) {
+	key, err := k.Primary()
+	if err != nil {
+		return "", err
+	}
+	return signValue(key, value), nil
+}
+
+// VerifyValue checks a value produced by SignValue and returns the original
+// value.
+func (k *Keyring) VerifyValue(signed string) (string, error) {
+	rest, sig, ok := cutLast(signed)
+	if !ok {
+		return "", ErrInvalid
+	}
+	value, id, ok := cutLast(rest)
+	if !ok {
+		return "", ErrInvalid
+	}
+	key, ok := k.Lookup(id)
+	if !ok {
+		return "", ErrInvalid
+	}
+	expected := base64.RawURLEncoding.EncodeToString(mac(key.Material, []byte(id+"."+value)))
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return "", ErrInvalid
+	}
+	return value, nil
+}
+
+// NewToken signs value with an expiry, for links such as downloads:
+// "<value>.<unix expiry>.<key id>.<base64 signature>".
+func (k *Keyring) NewToken(value string, ttl time.Duration) (string, error) {
+	return k.SignValue(fmt.Sprintf("%s.%d", value, time.Now().Add(ttl).Unix()))
+}
+
+// ParseToken verifies a token from NewToken and returns its value.
+func (k *Keyring) ParseToken(token string) (string, error) {
+	signed, err := k.VerifyValue(token)
+	if err != nil {
+		return "", err
+	}
+	value, expiry, ok := cutLast(signed)
+	if !ok {
+		return "", ErrInvalid
+	}
+	var unix int64
+	if _, err := fmt.Sscanf(expiry, "%d", &unix); err != nil {
+		return "", ErrInvalid
+	}
+	if time.Now().Unix() > unix {
+		return "", ErrExpired
+	}
+	return value, nil
+}
+
+// Encrypt seals plaintext with AES-256-GCM under the primary key. The
+// output names the key, so Decrypt keeps working after a rotation.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	key, err := k.Primary()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(key.ID)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out = append(out, byte(len(key.ID)))
+	out = append(out, key.ID...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("secrets: failed to generate nonce: %w", err)
+	}
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, []byte(key.ID)), nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt with any active key.
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 1 || len(ciphertext) < 1+int(ciphertext[0]) {
+		return nil, ErrInvalid
+	}
+	idLen := int(ciphertext[0])
+	id := string(ciphertext[1 : 1+idLen])
+	key, ok := k.Lookup(id)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := ciphertext[1+idLen:]
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrInvalid
+	}
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	return plaintext, nil
+}
+
+func (k *Keyring) root() *Keyring {
+	for k.parent != nil {
+		k = k.parent
+	}
+	return k
+}
+
+func validate(keys []Key) error {
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.ID == "" || len(key.ID) > 255 || strings.ContainsAny(key.ID, ".:, \t\n") {
+			return fmt.Errorf("%w: %q", ErrInvalidKeyID, key.ID)
+		}
+		if seen[key.ID] {
+			return fmt.Errorf("%w: %q", ErrDuplicateKey, key.ID)
+		}
+		seen[key.ID] = true
+		if len(key.Material) < MinKeyLen {
+			return fmt.Errorf("%w: %q has %d bytes, need %d", ErrWeakKey, key.ID, len(key.Material), MinKeyLen)
+		}
+	}
+	return nil
+}
+
+func copyKeys(keys []Key) []Key {
+	out := make([]Key, len(keys))
+	for i, key := range keys {
+		out[i] = key
+		out[i].Material = append([]byte(nil), key.Material...)
+	}
+	return out
+}
+
+func mac(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// derive computes a subkey as HMAC(material, "golivekit/"+purpose).
+func derive(material []byte, purpose string) []byte {
+	return mac(material, []byte("golivekit/"+purpose))
+}
+
+func newAEAD(key Key) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(derive(key.Material, "encrypt"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func signValue(key Key, value string) string {
+	sig := mac(key.Material, []byte(key.ID+"."+value))
+	return value + "." + key.ID + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func cutLast(s string) (before, after string, ok bool) {
+	i := strings.LastIndexByte(s, '.')
+	if i < 0 {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
+}
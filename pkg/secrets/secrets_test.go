@@ -0,0 +1,182 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustKey(t *testing.T, id string) Key {
+	t.Helper()
+	k, err := Generate(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	k1 := mustKey(t, "k1")
+	ring, err := NewKeyring(k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("payload")
+	oldSig, _ := ring.Sign(data)
+	oldValue, _ := ring.SignValue("user-42")
+	oldCipher, _ := ring.Encrypt([]byte("secret"))
+
+	if err := ring.Rotate(mustKey(t, "k2"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ring.Primary(); p.ID != "k2" {
+		t.Errorf("Expected k2 to be primary, got %s", p.ID)
+	}
+
+	if !ring.Verify(data, oldSig) {
+		t.Error("Expected signatures from the old key to verify during the grace period")
+	}
+	if v, err := ring.VerifyValue(oldValue); err != nil || v != "user-42" {
+		t.Errorf("VerifyValue = %q, %v", v, err)
+	}
+	if pt, err := ring.Decrypt(oldCipher); err != nil || string(pt) != "secret" {
+		t.Errorf("Decrypt = %q, %v", pt, err)
+	}
+
+	newSig, _ := ring.Sign(data)
+	if bytes.Equal(newSig, oldSig) {
+		t.Error("Expected the new primary to sign")
+	}
+
+	if !ring.Retire("k1") || ring.Verify(data, oldSig) {
+		t.Error("Expected retired key to stop verifying")
+	}
+	if ring.Retire("k2") {
+		t.Error("Expected the primary to be protected from Retire")
+	}
+}
+
+func TestKeyring_GraceExpiry(t *testing.T) {
+	old := mustKey(t, "old")
+	old.NotAfter = time.Now().Add(-time.Second)
+	ring, _ := NewKeyring(mustKey(t, "new"), old)
+
+	if _, ok := ring.Lookup("old"); ok {
+		t.Error("Expected expired key to be inactive")
+	}
+	if len(ring.Keys()) != 1 {
+		t.Errorf("Expected one active key, got %d", len(ring.Keys()))
+	}
+}
+
+func TestKeyring_Derive(t *testing.T) {
+	ring, _ := NewKeyring(mustKey(t, "k1"))
+	csrf, session := ring.Derive("csrf"), ring.Derive("session")
+
+	sig, _ := csrf.Sign([]byte("x"))
+	if session.Verify([]byte("x"), sig) || ring.Verify([]byte("x"), sig) {
+		t.Error("Expected derived keys to be isolated by purpose")
+	}
+
+	// Rotating the root applies to derived keyrings.
+	ring.Rotate(mustKey(t, "k2"), 0)
+	if p, _ := csrf.Primary(); p.ID != "k2" {
+		t.Errorf("Expected derived primary to follow the root, got %s", p.ID)
+	}
+	if !csrf.Verify([]byte("x"), sig) {
+		t.Error("Expected derived signature from the old key to verify")
+	}
+}
+
+func TestKeyring_Tokens(t *testing.T) {
+	ring, _ := NewKeyring(mustKey(t, "k1"))
+
+	token, _ := ring.NewToken("files/report.pdf", time.Minute)
+	if v, err := ring.ParseToken(token); err != nil || v != "files/report.pdf" {
+		t.Errorf("ParseToken = %q, %v", v, err)
+	}
+
+	expired, _ := ring.NewToken("x", -time.Minute)
+	if _, err := ring.ParseToken(expired); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+
+	tampered := strings.Replace(token, "report", "secret", 1)
+	if _, err := ring.ParseToken(tampered); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid, got %v", err)
+	}
+	if _, err := ring.Decrypt([]byte{5, 'a'}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid for short ciphertext, got %v", err)
+	}
+}
+
+func TestKeyring_Validation(t *testing.T) {
+	cases := []struct {
+		keys []Key
+		want error
+	}{
+		{nil, ErrNoKeys},
+		{[]Key{{ID: "a", Material: []byte("short")}}, ErrWeakKey},
+		{[]Key{{ID: "a.b", Material: make([]byte, 32)}}, ErrInvalidKeyID},
+		{[]Key{{ID: "a", Material: make([]byte, 32)}, {ID: "a", Material: make([]byte, 32)}}, ErrDuplicateKey},
+	}
+	for _, tc := range cases {
+		if _, err := NewKeyring(tc.keys...); !errors.Is(err, tc.want) {
+			t.Errorf("NewKeyring(%v) = %v, want %v", tc.keys, err, tc.want)
+		}
+	}
+}
+
+func TestProviders(t *testing.T) {
+	ctx := context.Background()
+	m1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	m2 := base64.RawURLEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+
+	t.Setenv("TEST_SECRET_KEYS", "k2:"+m2+", k1:"+m1)
+	ring, err := Load(ctx, EnvProvider{Var: "TEST_SECRET_KEYS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ring.Primary(); p.ID != "k2" || len(ring.Keys()) != 2 {
+		t.Errorf("Unexpected env keys: %v", ring.Keys())
+	}
+
+	path := filepath.Join(t.TempDir(), "keys")
+	os.WriteFile(path, []byte("# rotated 2026-01\nk3:"+m1+"\n\nk2:"+m2+"\n"), 0o600)
+	if err := ring.Reload(ctx, FileProvider{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ring.Primary(); p.ID != "k3" {
+		t.Errorf("Expected reload to switch primary, got %s", p.ID)
+	}
+
+	if err := ring.Reload(ctx, FileProvider{Path: path + ".missing"}); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if p, _ := ring.Primary(); p.ID != "k3" {
+		t.Error("Expected a failed reload to keep the current keys")
+	}
+
+	kms := KMSProvider{
+		Source: ProviderFunc(func(context.Context) ([]Key, error) {
+			return []Key{{ID: "wrapped", Material: []byte("ciphertext")}}, nil
+		}),
+		Unwrap: func(_ context.Context, id string, wrapped []byte) ([]byte, error) {
+			return bytes.Repeat(wrapped[:1], 32), nil
+		},
+	}
+	if _, err := Load(ctx, kms); err != nil {
+		t.Errorf("KMS load failed: %v", err)
+	}
+
+	if _, err := ParseKeys("nocolon"); err == nil {
+		t.Error("Expected parse error")
+	}
+}
@@ -0,0 +1,435 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+)
+
+// Cookie session errors.
+var (
+	ErrNoSessionKeys   = errors.New("security: cookie sessions need a keyring")
+	ErrSessionTooLarge = errors.New("security: session does not fit in a cookie")
+)
+
+// CookieSessionKey is the core.Session key holding the *CookieSession of a
+// Live view, so writes made while handling socket events reach the
+// browser.
+const CookieSessionKey = "cookie_session"
+
+// CookieSessionEvent carries a session written over a socket to the client,
+// which posts it to the store's flush path to get the new cookie:
+// {"url": flush path, "token": signed cookie}.
+const CookieSessionEvent = "lv:session"
+
+// maxCookieSize is the largest cookie value browsers are guaranteed to keep.
+const maxCookieSize = 4000
+
+// CookieSessionConfig configures cookie sessions.
+type CookieSessionConfig struct {
+	// Keys signs the cookie (required). Use a derived keyring, e.g.
+	// keys.Derive("session"), and rotate it like any other.
+	Keys *secrets.Keyring
+
+	// Encrypt seals the values with AES-GCM so the browser cannot read
+	// them, not only change them.
+	Encrypt bool
+
+	// CookieName (default "_golive_session")
+	CookieName string
+
+	// Path (default "/") and Domain of the cookie
+	Path   string
+	Domain string
+
+	// Secure cookie attribute
+	Secure bool
+
+	// SameSite cookie attribute (default Lax)
+	SameSite http.SameSite
+
+	// MaxAge is how long the browser keeps the cookie (default 30 days)
+	MaxAge time.Duration
+
+	// FlushPath receives the sessions written over sockets (default
+	// "/_live/session")
+	FlushPath string
+
+	// FlushTTL is how long the client has to post a written session
+	// (default one minute)
+	FlushTTL time.Duration
+}
+
+// CookieSessionStore keeps session values in a signed, optionally
+// encrypted, cookie, so no server-side storage is needed. Its middleware
+// loads the session of each request and writes it back on the response
+// when it changed.
+//
+//	sessions, err := security.NewCookieSessionStore(security.CookieSessionConfig{
+//	    Keys:    keys.Derive("session"),
+//	    Encrypt: true,
+//	})
+//	r.Use(sessions.Middleware())
+//
+//	func (c *Cart) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//	    security.CookieSessionFromContext(ctx).Set("cart", c.Items)
+//	    return nil
+//	}
+//
+// Writes made over a socket cannot set cookies: the router pushes the
+// session to the client, which posts it to FlushPath.
+type CookieSessionStore struct {
+	keys       *secrets.Keyring
+	flushKeys  *secrets.Keyring
+	encrypt    bool
+	cookieName string
+	path       string
+	domain     string
+	secure     bool
+	sameSite   http.SameSite
+	maxAge     time.Duration
+	flushPath  string
+	flushTTL   time.Duration
+}
+
+// NewCookieSessionStore creates a cookie session store.
+func NewCookieSessionStore(config CookieSessionConfig) (*CookieSessionStore, error) {
+	if config.Keys == nil {
+		return nil, ErrNoSessionKeys
+	}
+	if config.CookieName == "" {
+		config.CookieName = "_golive_session"
+	}
+	if config.Path == "" {
+		config.Path = "/"
+	}
+	if config.SameSite == 0 {
+		config.SameSite = http.SameSiteLaxMode
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = 30 * 24 * time.Hour
+	}
+	if config.FlushPath == "" {
+		config.FlushPath = "/_live/session"
+	}
+	if config.FlushTTL == 0 {
+		config.FlushTTL = time.Minute
+	}
+
+	return &CookieSessionStore{
+		keys:       config.Keys,
+		flushKeys:  config.Keys.Derive("session-flush"),
+		encrypt:    config.Encrypt,
+		cookieName: config.CookieName,
+		path:       config.Path,
+		domain:     config.Domain,
+		secure:     config.Secure,
+		sameSite:   config.SameSite,
+		maxAge:     config.MaxAge,
+		flushPath:  config.FlushPath,
+		flushTTL:   config.FlushTTL,
+	}, nil
+}
+
+// CookieSession is the session of one browser. It is safe for concurrent
+// use by the page request and the sockets of the same browser. Values go
+// through JSON: numbers read back as float64.
+type CookieSession struct {
+	store   *CookieSessionStore
+	mu      sync.Mutex
+	id      string
+	values  map[string]any
+	changed bool
+}
+
+// cookiePayload is the JSON inside the cookie. ID identifies the browser's
+// session, so a session flushed over a socket only replaces its own.
+type cookiePayload struct {
+	ID     string         `json:"id"`
+	Values map[string]any `json:"v,omitempty"`
+}
+
+// Get returns the value of key, or nil.
+func (s *CookieSession) Get(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// Set stores value under key.
+func (s *CookieSession) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.changed = true
+}
+
+// Delete removes key.
+func (s *CookieSession) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.changed = true
+	}
+}
+
+// Clear removes every value, e.g. on logout.
+func (s *CookieSession) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.values) > 0 {
+		s.values = make(map[string]any)
+		s.changed = true
+	}
+}
+
+// Values returns a copy of the session values.
+func (s *CookieSession) Values() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := make(map[string]any, len(s.values))
+	for k, v := range s.values {
+		values[k] = v
+	}
+	return values
+}
+
+// take returns the encoded cookie when the session changed since the last
+// call, and "" otherwise.
+func (s *CookieSession) take() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.changed {
+		return "", nil
+	}
+	value, err := s.store.encode(cookiePayload{ID: s.id, Values: s.values})
+	if err != nil {
+		return "", err
+	}
+	s.changed = false
+	return value, nil
+}
+
+// FlushPayload returns the CookieSessionEvent payload carrying the changes
+// made since the last flush, or nil when there are none. The router sends
+// it after socket events.
+func (s *CookieSession) FlushPayload() (map[string]any, error) {
+	value, err := s.take()
+	if err != nil || value == "" {
+		return nil, err
+	}
+	token, err := s.store.flushKeys.NewToken(value, s.store.flushTTL)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"url": s.store.flushPath, "token": token}, nil
+}
+
+// Load returns the session of r, or a new one when r has no valid session
+// cookie. A new session is written on the response, so the sockets the
+// page opens share it.
+func (st *CookieSessionStore) Load(r *http.Request) *CookieSession {
+	if cookie, err := r.Cookie(st.cookieName); err == nil {
+		if p, err := st.decode(cookie.Value); err == nil {
+			if p.Values == nil {
+				p.Values = make(map[string]any)
+			}
+			return &CookieSession{store: st, id: p.ID, values: p.Values}
+		}
+	}
+	return &CookieSession{store: st, id: generateSessionID(), values: make(map[string]any), changed: true}
+}
+
+// encode signs, and optionally encrypts, a session for its cookie.
+func (st *CookieSessionStore) encode(p cookiePayload) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	if st.encrypt {
+		if data, err = st.keys.Encrypt(data); err != nil {
+			return "", err
+		}
+	}
+	value, err := st.keys.SignValue(base64.RawURLEncoding.EncodeToString(data))
+	if err != nil {
+		return "", err
+	}
+	if len(value) > maxCookieSize {
+		return "", ErrSessionTooLarge
+	}
+	return value, nil
+}
+
+// decode verifies a cookie written by encode.
+func (st *CookieSessionStore) decode(value string) (cookiePayload, error) {
+	var p cookiePayload
+	encoded, err := st.keys.VerifyValue(value)
+	if err != nil {
+		return p, err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return p, ErrInvalidSession
+	}
+	if st.encrypt {
+		if data, err = st.keys.Decrypt(data); err != nil {
+			return p, err
+		}
+	}
+	if err := json.Unmarshal(data, &p); err != nil || p.ID == "" {
+		return p, ErrInvalidSession
+	}
+	return p, nil
+}
+
+// setCookie adds the session cookie to the response headers.
+func (st *CookieSessionStore) setCookie(w http.ResponseWriter, value string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     st.cookieName,
+		Value:    value,
+		Path:     st.path,
+		Domain:   st.domain,
+		MaxAge:   int(st.maxAge.Seconds()),
+		Secure:   st.secure,
+		HttpOnly: true,
+		SameSite: st.sameSite,
+	})
+}
+
+// Middleware loads the session of each request into its context and sets
+// the cookie on the response when the handler changed the session. It
+// also serves FlushPath.
+func (st *CookieSessionStore) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session := st.Load(r)
+			if r.URL.Path == st.flushPath && r.Method == http.MethodPost {
+				st.serveFlush(w, r, session)
+				return
+			}
+
+			sw := &sessionWriter{ResponseWriter: w, store: st, session: session}
+			next.ServeHTTP(sw, r.WithContext(WithCookieSession(r.Context(), session)))
+			// Handlers that wrote nothing still get their headers sent
+			sw.flush()
+		})
+	}
+}
+
+// FlushHandler serves FlushPath on its own, for routers where no route
+// covering it runs the middleware:
+//
+//	r.Handle("/_live/session", sessions.FlushHandler())
+func (st *CookieSessionStore) FlushHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		st.serveFlush(w, r, st.Load(r))
+	})
+}
+
+// serveFlush sets the cookie posted by the client after a socket changed
+// the session. The token must come from a socket of the same session.
+func (st *CookieSessionStore) serveFlush(w http.ResponseWriter, r *http.Request, current *CookieSession) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 2*maxCookieSize))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	value, err := st.flushKeys.ParseToken(string(body))
+	if err != nil {
+		http.Error(w, "invalid session token", http.StatusForbidden)
+		return
+	}
+	p, err := st.decode(value)
+	if err != nil || p.ID != current.id {
+		http.Error(w, "invalid session token", http.StatusForbidden)
+		return
+	}
+	st.setCookie(w, value)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionWriter sets the session cookie before the response headers are
+// written.
+type sessionWriter struct {
+	http.ResponseWriter
+	store   *CookieSessionStore
+	session *CookieSession
+	flushed bool
+}
+
+// flush adds the cookie of a changed session, once.
+func (w *sessionWriter) flush() {
+	if w.flushed {
+		return
+	}
+	w.flushed = true
+	if value, err := w.session.take(); err == nil && value != "" {
+		w.store.setCookie(w.ResponseWriter, value)
+	}
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	w.flush()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.flush()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush supports streamed responses (SSE).
+func (w *sessionWriter) Flush() {
+	w.flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack supports WebSocket upgrades.
+func (w *sessionWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type cookieSessionKey struct{}
+
+// WithCookieSession adds a cookie session to the context.
+func WithCookieSession(ctx context.Context, session *CookieSession) context.Context {
+	return context.WithValue(ctx, cookieSessionKey{}, session)
+}
+
+// CookieSessionFromContext returns the cookie session of a request, or of
+// the Live view handling an event (under CookieSessionKey). It is nil
+// when no CookieSessionStore middleware ran.
+func CookieSessionFromContext(ctx context.Context) *CookieSession {
+	if s, ok := ctx.Value(cookieSessionKey{}).(*CookieSession); ok {
+		return s
+	}
+	s, _ := core.SessionFromContext(ctx)[CookieSessionKey].(*CookieSession)
+	return s
+}
@@ -0,0 +1,145 @@
+package security
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+)
+
+func newCookieSessions(t *testing.T, encrypt bool) *CookieSessionStore {
+	t.Helper()
+	key, err := secrets.Generate("k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := secrets.NewKeyring(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := NewCookieSessionStore(CookieSessionConfig{Keys: keys, Encrypt: encrypt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+// serve runs h behind the store's middleware with the given cookies and
+// returns the session cookie it set, if any.
+func serve(st *CookieSessionStore, req *http.Request, h http.HandlerFunc) (*httptest.ResponseRecorder, *http.Cookie) {
+	rec := httptest.NewRecorder()
+	st.Middleware()(h).ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "_golive_session" {
+			return rec, c
+		}
+	}
+	return rec, nil
+}
+
+func TestCookieSession_RoundTrip(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		st := newCookieSessions(t, encrypt)
+
+		_, cookie := serve(st, httptest.NewRequest("GET", "/", nil), func(w http.ResponseWriter, r *http.Request) {
+			CookieSessionFromContext(r.Context()).Set("cart", []string{"apple"})
+			w.Write([]byte("ok"))
+		})
+		if cookie == nil || !cookie.HttpOnly {
+			t.Fatalf("encrypt=%v: cookie = %v, want an HttpOnly session cookie", encrypt, cookie)
+		}
+		encoded, _, _ := strings.Cut(cookie.Value, ".")
+		data, _ := base64.RawURLEncoding.DecodeString(encoded)
+		if readable := strings.Contains(string(data), "apple"); readable == encrypt {
+			t.Errorf("encrypt=%v: value readable = %v", encrypt, readable)
+		}
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(cookie)
+		var cart any
+		_, again := serve(st, req, func(w http.ResponseWriter, r *http.Request) {
+			cart = CookieSessionFromContext(r.Context()).Get("cart")
+		})
+		if items, _ := cart.([]any); len(items) != 1 || items[0] != "apple" {
+			t.Errorf("encrypt=%v: cart = %#v", encrypt, cart)
+		}
+		if again != nil {
+			t.Errorf("encrypt=%v: unchanged session was written again", encrypt)
+		}
+	}
+}
+
+func TestCookieSession_RejectsTamperedCookie(t *testing.T) {
+	st := newCookieSessions(t, false)
+	_, cookie := serve(st, httptest.NewRequest("GET", "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		CookieSessionFromContext(r.Context()).Set("role", "user")
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: cookie.Name, Value: "x" + cookie.Value})
+	serve(st, req, func(w http.ResponseWriter, r *http.Request) {
+		if role := CookieSessionFromContext(r.Context()).Get("role"); role != nil {
+			t.Errorf("tampered cookie loaded role %v", role)
+		}
+	})
+}
+
+func TestCookieSession_FlushFromSocket(t *testing.T) {
+	st := newCookieSessions(t, true)
+	_, cookie := serve(st, httptest.NewRequest("GET", "/", nil), func(w http.ResponseWriter, r *http.Request) {})
+	if cookie == nil {
+		t.Fatal("new session was not written")
+	}
+
+	// A Live view gets the session through core.Session
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(cookie)
+	var session *CookieSession
+	serve(st, req, func(w http.ResponseWriter, r *http.Request) {
+		session = CookieSessionFromContext(r.Context())
+	})
+	ctx := core.WithSession(req.Context(), core.Session{CookieSessionKey: session})
+	if payload, _ := session.FlushPayload(); payload != nil {
+		t.Errorf("unchanged session flushed %v", payload)
+	}
+	CookieSessionFromContext(ctx).Set("theme", "dark")
+	payload, err := session.FlushPayload()
+	if err != nil || payload["url"] != "/_live/session" {
+		t.Fatalf("FlushPayload = %v, %v", payload, err)
+	}
+	token, _ := payload["token"].(string)
+
+	flush := func(cookie *http.Cookie, token string) (*httptest.ResponseRecorder, *http.Cookie) {
+		req := httptest.NewRequest("POST", "/_live/session", strings.NewReader(token))
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		return serve(st, req, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("flush reached the application")
+		})
+	}
+
+	// Another browser cannot plant the session
+	if rec, _ := flush(nil, token); rec.Code != http.StatusForbidden {
+		t.Errorf("flush without the session cookie: status %d, want 403", rec.Code)
+	}
+	if rec, _ := flush(cookie, token+"x"); rec.Code != http.StatusForbidden {
+		t.Errorf("flush with a bad token: status %d, want 403", rec.Code)
+	}
+
+	rec, updated := flush(cookie, token)
+	if rec.Code != http.StatusNoContent || updated == nil {
+		t.Fatalf("flush: status %d, cookie %v", rec.Code, updated)
+	}
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(updated)
+	serve(st, req, func(w http.ResponseWriter, r *http.Request) {
+		if theme := CookieSessionFromContext(r.Context()).Get("theme"); theme != "dark" {
+			t.Errorf("theme = %v after the flush", theme)
+		}
+	})
+}
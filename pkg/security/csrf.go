@@ -9,10 +9,13 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
 )
 
 // Common security errors.
@@ -26,6 +29,7 @@ var (
 // CSRFProtection provides CSRF protection for forms.
 type CSRFProtection struct {
 	secret    []byte
+	keys      *secrets.Keyring
 	tokenLen  int
 	maxAge    time.Duration
 	sameSite  http.SameSite
@@ -42,6 +46,11 @@ type CSRFConfig struct {
 	// Secret key for signing tokens (required)
 	Secret []byte
 
+	// Keys, if set, replaces Secret: tokens are signed with the primary key
+	// and accepted under any active key, so keys can be rotated without
+	// invalidating outstanding tokens.
+	Keys *secrets.Keyring
+
 	// TokenLen is the length of random bytes in the token (default 32)
 	TokenLen int
 
@@ -86,7 +95,7 @@ func DefaultCSRFConfig() CSRFConfig {
 
 // NewCSRFProtection creates a new CSRF protection instance.
 func NewCSRFProtection(config CSRFConfig) *CSRFProtection {
-	if len(config.Secret) == 0 {
+	if len(config.Secret) == 0 && config.Keys == nil {
 		config.Secret = make([]byte, 32)
 		rand.Read(config.Secret)
 	}
@@ -108,6 +117,7 @@ func NewCSRFProtection(config CSRFConfig) *CSRFProtection {
 
 	return &CSRFProtection{
 		secret:     config.Secret,
+		keys:       config.Keys,
 		tokenLen:   config.TokenLen,
 		maxAge:     config.MaxAge,
 		sameSite:   config.SameSite,
@@ -138,7 +148,10 @@ func (c *CSRFProtection) GenerateToken(sessionID string) (string, error) {
 	)
 
 	// Sign the payload
-	signature := c.sign([]byte(payload))
+	signature, err := c.sign([]byte(payload))
+	if err != nil {
+		return "", err
+	}
 
 	// Combine: payload.signature
 	token := payload + "." + base64.StdEncoding.EncodeToString(signature)
@@ -168,8 +181,7 @@ func (c *CSRFProtection) ValidateToken(token, sessionID string) error {
 	}
 
 	// Verify signature
-	expectedSig := c.sign([]byte(payload))
-	if subtle.ConstantTimeCompare(signature, expectedSig) != 1 {
+	if !c.verify([]byte(payload), signature) {
 		return ErrInvalidSignature
 	}
 
@@ -199,10 +211,23 @@ func (c *CSRFProtection) ValidateToken(token, sessionID string) error {
 }
 
 // sign creates an HMAC signature.
-func (c *CSRFProtection) sign(data []byte) []byte {
+func (c *CSRFProtection) sign(data []byte) ([]byte, error) {
+	if c.keys != nil {
+		return c.keys.Sign(data)
+	}
 	mac := hmac.New(sha256.New, c.secret)
 	mac.Write(data)
-	return mac.Sum(nil)
+	return mac.Sum(nil), nil
+}
+
+// verify checks a signature created by sign.
+func (c *CSRFProtection) verify(data, signature []byte) bool {
+	if c.keys != nil {
+		return c.keys.Verify(data, signature)
+	}
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(data)
+	return subtle.ConstantTimeCompare(signature, mac.Sum(nil)) == 1
 }
 
 // Middleware returns HTTP middleware for CSRF protection.
@@ -231,19 +256,26 @@ func (c *CSRFProtection) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
-// ensureToken ensures a CSRF token exists in the cookie.
+// ensureToken ensures the cookie holds a CSRF token valid for the session.
+// Tokens of another session, such as the anonymous one before a login, are
+// replaced. The new token is also added to the request, so the page being
+// rendered embeds it (Hidden, GetToken).
 func (c *CSRFProtection) ensureToken(w http.ResponseWriter, r *http.Request) {
+	sessionID := c.getSessionID(r)
+
 	// Check if cookie exists
 	if cookie, err := r.Cookie(c.cookieName); err == nil && cookie.Value != "" {
-		return
+		if c.ValidateToken(cookie.Value, sessionID) == nil {
+			return
+		}
 	}
 
 	// Generate new token
-	sessionID := c.getSessionID(r)
 	token, err := c.GenerateToken(sessionID)
 	if err != nil {
 		return
 	}
+	c.setRequestToken(r, token)
 
 	// Set cookie
 	http.SetCookie(w, &http.Cookie{
@@ -258,6 +290,18 @@ func (c *CSRFProtection) ensureToken(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// setRequestToken replaces the token cookie in r's Cookie header.
+func (c *CSRFProtection) setRequestToken(r *http.Request, token string) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != c.cookieName {
+			r.AddCookie(cookie)
+		}
+	}
+	r.AddCookie(&http.Cookie{Name: c.cookieName, Value: token})
+}
+
 // getToken extracts the CSRF token from the request.
 func (c *CSRFProtection) getToken(r *http.Request) string {
 	// Check header first
@@ -280,8 +324,13 @@ func (c *CSRFProtection) getSessionID(r *http.Request) string {
 		return cookie.Value
 	}
 
-	// Fall back to remote addr + user agent
-	return hashString(r.RemoteAddr + r.UserAgent())
+	// Fall back to the client address + user agent. The port changes with
+	// each connection, so it is left out.
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return hashString(host + r.UserAgent())
 }
 
 // isSafeMethod returns true for safe HTTP methods.
@@ -320,6 +369,6 @@ func (c *CSRFProtection) TemplateFunc() func(*http.Request) string {
 
 // Hidden returns an HTML hidden input with the CSRF token.
 func (c *CSRFProtection) Hidden(r *http.Request) string {
-	token := GetToken(r)
+	token := c.TemplateFunc()(r)
 	return fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, c.formField, token)
 }
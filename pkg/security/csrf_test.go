@@ -0,0 +1,72 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRF_FirstPageEmbedsToken(t *testing.T) {
+	c := NewCSRFProtection(CSRFConfig{})
+	var hidden string
+	h := c.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hidden = c.Hidden(r)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/login", nil))
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "_csrf" {
+		t.Fatalf("cookies = %v, want _csrf", cookies)
+	}
+	if !strings.Contains(hidden, `value="`+cookies[0].Value+`"`) {
+		t.Errorf("Hidden = %s, want the new token", hidden)
+	}
+}
+
+func TestCSRF_AnonymousTokenSurvivesNewConnection(t *testing.T) {
+	c := NewCSRFProtection(CSRFConfig{})
+	get := httptest.NewRequest("GET", "/login", nil)
+	get.RemoteAddr = "10.0.0.1:50000"
+	token, err := c.GenerateToken(c.getSessionID(get))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post := httptest.NewRequest("POST", "/login", strings.NewReader(url.Values{"_csrf": {token}}.Encode()))
+	post.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	post.RemoteAddr = "10.0.0.1:50001"
+	rec := httptest.NewRecorder()
+	c.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rec, post)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestCSRF_RenewsTokenAfterLogin(t *testing.T) {
+	c := NewCSRFProtection(CSRFConfig{})
+	anonymous, _ := c.GenerateToken("someone-else")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	req.AddCookie(&http.Cookie{Name: "_csrf", Value: anonymous})
+	rec := httptest.NewRecorder()
+	var seen string
+	c.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = GetToken(r)
+	})).ServeHTTP(rec, req)
+
+	if seen == anonymous {
+		t.Fatal("token bound to another session was kept")
+	}
+	if err := c.ValidateToken(seen, "s1"); err != nil {
+		t.Errorf("renewed token invalid for the session: %v", err)
+	}
+	if cookies := rec.Result().Cookies(); len(cookies) != 1 || cookies[0].Value != seen {
+		t.Errorf("cookies = %v, want the renewed token", cookies)
+	}
+}
@@ -0,0 +1,269 @@
+// Package security provides security utilities for GoliveKit.
+// This file adds a restricted Markdown renderer for user-generated content.
+package security
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MarkdownConfig configures the restricted Markdown renderer.
+type MarkdownConfig struct {
+	// AllowLinks enables [text](url) links.
+	AllowLinks bool
+
+	// AllowedSchemes lists the URL schemes permitted in links.
+	// Relative URLs are never allowed.
+	AllowedSchemes []string
+
+	// LinkTarget is the target attribute for links ("" omits it).
+	LinkTarget string
+
+	// LineBreaks converts newlines to <br>.
+	LineBreaks bool
+
+	// MaxLength truncates input before rendering (0 = unlimited).
+	MaxLength int
+}
+
+// DefaultMarkdownConfig returns a safe default configuration for chat messages.
+func DefaultMarkdownConfig() MarkdownConfig {
+	return MarkdownConfig{
+		AllowLinks:     true,
+		AllowedSchemes: []string{"http", "https", "mailto"},
+		LinkTarget:     "_blank",
+		LineBreaks:     true,
+		MaxLength:      4096,
+	}
+}
+
+// maxMarkdownDepth limits nesting of emphasis to keep rendering linear.
+const maxMarkdownDepth = 8
+
+// linkRel is always applied to rendered links.
+const linkRel = "nofollow noopener noreferrer"
+
+// MarkdownRenderer renders a safe subset of Markdown to HTML.
+// Only **bold**, *italic*, `code` and [links](url) are supported; every other
+// character is HTML-escaped, so the output can be embedded without sanitizing.
+type MarkdownRenderer struct {
+	config  MarkdownConfig
+	schemes map[string]bool
+}
+
+// NewMarkdownRenderer creates a new restricted Markdown renderer.
+func NewMarkdownRenderer(config MarkdownConfig) *MarkdownRenderer {
+	schemes := make(map[string]bool, len(config.AllowedSchemes))
+	for _, s := range config.AllowedSchemes {
+		schemes[strings.ToLower(s)] = true
+	}
+	return &MarkdownRenderer{
+		config:  config,
+		schemes: schemes,
+	}
+}
+
+var defaultMarkdown = NewMarkdownRenderer(DefaultMarkdownConfig())
+
+// RenderMarkdown renders input with the default configuration.
+func RenderMarkdown(input string) string {
+	return defaultMarkdown.Render(input)
+}
+
+// Render converts input to HTML.
+func (m *MarkdownRenderer) Render(input string) string {
+	if m.config.MaxLength > 0 && len(input) > m.config.MaxLength {
+		input = truncateUTF8(input, m.config.MaxLength)
+	}
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+
+	var sb strings.Builder
+	sb.Grow(len(input) + len(input)/4)
+	m.renderInline(&sb, input, 0, m.config.AllowLinks)
+	return sb.String()
+}
+
+// renderInline renders a span of inline Markdown into sb.
+func (m *MarkdownRenderer) renderInline(sb *strings.Builder, s string, depth int, links bool) {
+	if depth > maxMarkdownDepth {
+		writeEscaped(sb, s)
+		return
+	}
+
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isMarkdownPunct(s[i+1]):
+			writeEscaped(sb, s[i+1:i+2])
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+				sb.WriteString("<code>")
+				writeEscaped(sb, s[i+1:i+1+end])
+				sb.WriteString("</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if n := m.renderEmphasis(sb, s, i, depth, links); n > 0 {
+				i += n
+				continue
+			}
+
+		case c == '[' && links:
+			if n := m.renderLink(sb, s, i, depth); n > 0 {
+				i += n
+				continue
+			}
+
+		case c == '\n' && m.config.LineBreaks:
+			sb.WriteString("<br>")
+			i++
+			continue
+		}
+
+		writeEscaped(sb, s[i:i+1])
+		i++
+	}
+}
+
+// renderEmphasis renders **strong** or *em* starting at s[i].
+// Returns the number of bytes consumed, or 0 if no emphasis was found.
+func (m *MarkdownRenderer) renderEmphasis(sb *strings.Builder, s string, i, depth int, links bool) int {
+	c := s[i]
+
+	// Underscores inside words (snake_case) are literal.
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return 0
+	}
+
+	delim := string(c)
+	tag := "em"
+	if i+1 < len(s) && s[i+1] == c {
+		delim = string([]byte{c, c})
+		tag = "strong"
+	}
+
+	start := i + len(delim)
+	if start >= len(s) || s[start] == ' ' || s[start] == '\n' {
+		return 0
+	}
+
+	end := strings.Index(s[start:], delim)
+	if end <= 0 || s[start+end-1] == ' ' {
+		return 0
+	}
+	closeAt := start + end
+	if c == '_' && closeAt+len(delim) < len(s) && isWordByte(s[closeAt+len(delim)]) {
+		return 0
+	}
+
+	sb.WriteString("<" + tag + ">")
+	m.renderInline(sb, s[start:closeAt], depth+1, links)
+	sb.WriteString("</" + tag + ">")
+	return closeAt + len(delim) - i
+}
+
+// renderLink renders [text](url) starting at s[i].
+// Returns the number of bytes consumed, or 0 if no link was found.
+func (m *MarkdownRenderer) renderLink(sb *strings.Builder, s string, i, depth int) int {
+	textEnd := strings.IndexByte(s[i+1:], ']')
+	if textEnd <= 0 {
+		return 0
+	}
+	textEnd += i + 1
+	if textEnd+1 >= len(s) || s[textEnd+1] != '(' {
+		return 0
+	}
+	urlEnd := strings.IndexByte(s[textEnd+2:], ')')
+	if urlEnd <= 0 {
+		return 0
+	}
+	urlEnd += textEnd + 2
+
+	text := s[i+1 : textEnd]
+	href, ok := m.safeURL(s[textEnd+2 : urlEnd])
+	if !ok {
+		// Unsafe target: keep the label, drop the link.
+		m.renderInline(sb, text, depth+1, false)
+		return urlEnd + 1 - i
+	}
+
+	sb.WriteString(`<a href="`)
+	writeEscaped(sb, href)
+	sb.WriteString(`" rel="` + linkRel + `"`)
+	if m.config.LinkTarget != "" {
+		sb.WriteString(` target="`)
+		writeEscaped(sb, m.config.LinkTarget)
+		sb.WriteString(`"`)
+	}
+	sb.WriteString(">")
+	m.renderInline(sb, text, depth+1, false)
+	sb.WriteString("</a>")
+	return urlEnd + 1 - i
+}
+
+// safeURL validates a link target against the allowed schemes.
+func (m *MarkdownRenderer) safeURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+	for _, r := range raw {
+		if r < 0x20 || r == 0x7f || r == ' ' {
+			return "", false
+		}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return "", false
+	}
+	if !m.schemes[strings.ToLower(u.Scheme)] {
+		return "", false
+	}
+	if u.Scheme != "mailto" && u.Host == "" {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// writeEscaped writes s to sb with HTML special characters escaped.
+func writeEscaped(sb *strings.Builder, s string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '<':
+			sb.WriteString("&lt;")
+		case '>':
+			sb.WriteString("&gt;")
+		case '&':
+			sb.WriteString("&amp;")
+		case '"':
+			sb.WriteString("&#34;")
+		case '\'':
+			sb.WriteString("&#39;")
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+}
+
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("\\`*_[]()#+-.!", c) >= 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// truncateUTF8 truncates s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
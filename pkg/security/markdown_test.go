@@ -0,0 +1,95 @@
+package security
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown_Formatting(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "hello", "hello"},
+		{"bold", "**hi** there", "<strong>hi</strong> there"},
+		{"italic", "*hi*", "<em>hi</em>"},
+		{"underscore italic", "_hi_", "<em>hi</em>"},
+		{"snake_case literal", "use snake_case_names", "use snake_case_names"},
+		{"code", "run `go test`", "run <code>go test</code>"},
+		{"code not formatted", "`**x**`", "<code>**x**</code>"},
+		{"nested", "**bold *and* italic**", "<strong>bold <em>and</em> italic</strong>"},
+		{"escaped asterisk", `\*not italic\*`, "*not italic*"},
+		{"unclosed", "**open", "**open"},
+		{"newline", "a\nb", "a<br>b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.input); got != tt.want {
+				t.Errorf("RenderMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_Links(t *testing.T) {
+	got := RenderMarkdown("[docs](https://example.com/a?b=1&c=2)")
+	want := `<a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer" target="_blank">docs</a>`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	unsafe := []string{
+		"[x](javascript:alert(1))",
+		"[x](data:text/html,<script>)",
+		"[x](/relative)",
+		"[x](//evil.com)",
+		"[x](vbscript:msgbox)",
+	}
+	for _, input := range unsafe {
+		out := RenderMarkdown(input)
+		if strings.Contains(out, "<a") {
+			t.Errorf("RenderMarkdown(%q) produced a link: %q", input, out)
+		}
+	}
+}
+
+func TestRenderMarkdown_EscapesHTML(t *testing.T) {
+	inputs := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`**<b>bold</b>**`,
+		"`<script>`",
+		`[<img src=x>](https://example.com)`,
+		`[x](https://example.com/"onmouseover="alert(1))`,
+	}
+
+	for _, input := range inputs {
+		out := RenderMarkdown(input)
+		if strings.Contains(out, "<script") || strings.Contains(out, "<img") || strings.Contains(out, "<b>") {
+			t.Errorf("RenderMarkdown(%q) leaked HTML: %q", input, out)
+		}
+		if strings.Contains(out, `"onmouseover`) {
+			t.Errorf("RenderMarkdown(%q) broke out of attribute: %q", input, out)
+		}
+	}
+}
+
+func TestRenderMarkdown_NoNestedLinks(t *testing.T) {
+	out := RenderMarkdown("[a [b](https://b.com)](https://a.com)")
+	if strings.Count(out, "<a ") > 1 {
+		t.Errorf("nested links rendered: %q", out)
+	}
+}
+
+func TestMarkdownRenderer_MaxLength(t *testing.T) {
+	r := NewMarkdownRenderer(MarkdownConfig{MaxLength: 5})
+	if got := r.Render("hello world"); got != "hello" {
+		t.Errorf("got %q, want %q", got, "hello")
+	}
+	// Must not split multi-byte runes.
+	if got := r.Render("héllo"); got != "héll" {
+		t.Errorf("got %q, want %q", got, "héll")
+	}
+}
@@ -0,0 +1,139 @@
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+)
+
+// Payload keys of signed event values. The signature is rendered as the
+// lv-value-_sig attribute, so the client sends it with the values; the
+// router replaces it with the names of the values it covers.
+const (
+	SignatureKey = "_sig"
+	SignedKey    = "_signed"
+)
+
+// ErrInvalidValueSignature is returned for event values that do not match
+// the signature rendered with them: they were changed in the browser.
+var ErrInvalidValueSignature = errors.New("security: event values do not match their signature")
+
+// ValueSigner signs the lv-value-* attributes the server renders (item
+// IDs, prices), so values tampered with in the browser are rejected when
+// their event arrives. Signatures are scoped to the event and to the user
+// (see ValueScope): a value rendered for one user is not valid for another.
+type ValueSigner struct {
+	keys *secrets.Keyring
+}
+
+// NewValueSigner creates a signer whose keys are derived from keys.
+func NewValueSigner(keys *secrets.Keyring) *ValueSigner {
+	return &ValueSigner{keys: keys.Derive("lv-values")}
+}
+
+// Sign returns the signature of the values of event, for the
+// lv-value-_sig attribute: the signed names and a MAC, "id,qty.<mac>".
+func (s *ValueSigner) Sign(scope, event string, values map[string]string) (string, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	mac, err := s.keys.Sign(signedValues(scope, event, names, values))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(names, ",") + "." + base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+// Verify checks the signature in payload against the values of payload it
+// names and returns those names.
+func (s *ValueSigner) Verify(scope, event string, payload map[string]any) ([]string, error) {
+	sig, _ := payload[SignatureKey].(string)
+	list, encoded, ok := strings.Cut(sig, ".")
+	if !ok {
+		return nil, ErrInvalidValueSignature
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidValueSignature
+	}
+
+	var names []string
+	if list != "" {
+		names = strings.Split(list, ",")
+	}
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		v, ok := payload[name]
+		if !ok {
+			return nil, ErrInvalidValueSignature
+		}
+		values[name] = core.RawValue(v)
+	}
+	if !slices.IsSorted(names) || !s.keys.Verify(signedValues(scope, event, names, values), mac) {
+		return nil, ErrInvalidValueSignature
+	}
+	return names, nil
+}
+
+// signedValues is the message signed for values: each part is length
+// prefixed, so no two sets of values produce the same message.
+func signedValues(scope, event string, names []string, values map[string]string) []byte {
+	var sb strings.Builder
+	write := func(s string) {
+		sb.WriteString(strconv.Itoa(len(s)))
+		sb.WriteByte(':')
+		sb.WriteString(s)
+	}
+	write(scope)
+	write(event)
+	for _, name := range names {
+		write(name)
+		write(values[name])
+	}
+	return []byte(sb.String())
+}
+
+// SignedValue returns the value of key in an event payload if the router
+// verified it was rendered by the server, so a handler can trust it as an
+// ID it showed this user:
+//
+//	id, ok := security.SignedValue(payload, "id")
+//	if !ok {
+//	    return security.ErrInvalidValueSignature
+//	}
+func SignedValue(payload map[string]any, key string) (string, bool) {
+	signed, _ := payload[SignedKey].([]string)
+	if !slices.Contains(signed, key) {
+		return "", false
+	}
+	return core.RawValue(payload[key]), true
+}
+
+// ValueScope returns the scope of the value signatures of a render or
+// event: the ID of the signed-in user, or "" for anonymous visitors.
+func ValueScope(ctx context.Context) string {
+	return core.SessionFromContext(ctx).GetString("user_id")
+}
+
+type valueSignerKey struct{}
+
+// WithValueSigner returns a context carrying s, which template helpers use
+// to sign the values they render.
+func WithValueSigner(ctx context.Context, s *ValueSigner) context.Context {
+	return context.WithValue(ctx, valueSignerKey{}, s)
+}
+
+// ValueSignerFromContext returns the signer of ctx, or nil.
+func ValueSignerFromContext(ctx context.Context) *ValueSigner {
+	s, _ := ctx.Value(valueSignerKey{}).(*ValueSigner)
+	return s
+}
@@ -0,0 +1,48 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
+)
+
+func TestValueSigner(t *testing.T) {
+	key, err := secrets.Generate("k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := secrets.NewKeyring(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewValueSigner(keys)
+
+	sig, err := s.Sign("user-1", "delete", map[string]string{"id": "42", "list": "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := map[string]any{"id": "42", "list": "7", "note": "typed", SignatureKey: sig}
+	names, err := s.Verify("user-1", "delete", payload)
+	if err != nil || len(names) != 2 || names[0] != "id" || names[1] != "list" {
+		t.Fatalf("Verify = %v, %v", names, err)
+	}
+
+	payload[SignedKey] = names
+	if id, ok := SignedValue(payload, "id"); !ok || id != "42" {
+		t.Errorf("SignedValue(id) = %q, %v", id, ok)
+	}
+	if _, ok := SignedValue(payload, "note"); ok {
+		t.Error("Expected an unsigned value not to be trusted")
+	}
+
+	tampered := map[string]any{"id": "43", "list": "7", SignatureKey: sig}
+	if _, err := s.Verify("user-1", "delete", tampered); err != ErrInvalidValueSignature {
+		t.Errorf("tampered value: err = %v", err)
+	}
+	if _, err := s.Verify("user-2", "delete", payload); err != ErrInvalidValueSignature {
+		t.Errorf("other user: err = %v", err)
+	}
+	if _, err := s.Verify("user-1", "archive", payload); err != ErrInvalidValueSignature {
+		t.Errorf("other event: err = %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+)
+
+// Fixtures are records by repository name, as written in fixture files:
+//
+//	{
+//		"posts": [
+//			{"title": "Hello", "published": true},
+//			{"title": "Draft"}
+//		]
+//	}
+type Fixtures map[string][]map[string]any
+
+// ReadFixtures reads the JSON fixture files of fsys matching patterns
+// (default "*.json") in name order, appending the records of each name.
+func ReadFixtures(fsys fs.FS, patterns ...string) (Fixtures, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"*.json"}
+	}
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	fixtures := make(Fixtures)
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+		var f Fixtures
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("seed: %s: %w", file, err)
+		}
+		for name, records := range f {
+			fixtures[name] = append(fixtures[name], records...)
+		}
+	}
+	return fixtures, nil
+}
+
+// Repo receives fixture records. forms.MemoryRepo implements it, as do the
+// admin package's repositories.
+type Repo interface {
+	Insert(cs *forms.Changeset) (string, error)
+}
+
+// Target is a repository fixtures are inserted into. Changeset casts and
+// validates each record as the application does for user input; the
+// changeset functions of admin models fit. Without it records are inserted
+// as they are.
+type Target struct {
+	Repo      Repo
+	Changeset func(data, params map[string]any) *forms.Changeset
+}
+
+// Insert inserts the records of each name into its target, names in order,
+// and returns the new ids by name. Names without a target are an error, so
+// that a typo in a fixture file does not go unnoticed.
+func (f Fixtures) Insert(targets map[string]Target) (map[string][]string, error) {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		if _, ok := targets[name]; !ok {
+			return nil, fmt.Errorf("seed: no target for fixtures %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ids := make(map[string][]string, len(names))
+	for _, name := range names {
+		target := targets[name]
+		for i, record := range f[name] {
+			var cs *forms.Changeset
+			if target.Changeset != nil {
+				cs = target.Changeset(map[string]any{}, record)
+			} else {
+				cs = forms.NewChangeset(nil)
+				for k, v := range record {
+					cs.Change(k, v)
+				}
+			}
+			if !cs.Valid {
+				return ids, fmt.Errorf("seed: %s[%d]: %s", name, i, cs.ErrorMessages())
+			}
+			id, err := target.Repo.Insert(cs)
+			if err != nil {
+				return ids, fmt.Errorf("seed: %s[%d]: %w", name, i, err)
+			}
+			ids[name] = append(ids[name], id)
+		}
+	}
+	return ids, nil
+}
+
+// Decode decodes the records of name into v, usually a slice of the
+// structs an in-memory store keeps:
+//
+//	var todos []Todo
+//	err := fixtures.Decode("todos", &todos)
+func (f Fixtures) Decode(name string, v any) error {
+	data, err := json.Marshal(f[name])
+	if err != nil {
+		return fmt.Errorf("seed: %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("seed: %s: %w", name, err)
+	}
+	return nil
+}
+
+// RegisterFixtures registers a seed inserting the fixture files of fsys
+// into targets.
+func RegisterFixtures(name string, fsys fs.FS, targets map[string]Target, patterns ...string) {
+	Register(name, func(ctx context.Context) error {
+		fixtures, err := ReadFixtures(fsys, patterns...)
+		if err != nil {
+			return err
+		}
+		_, err = fixtures.Insert(targets)
+		return err
+	})
+}
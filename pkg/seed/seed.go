@@ -0,0 +1,157 @@
+// Package seed loads known data for development: seed functions registered
+// by the application and run with "golive seed", and fixture files inserted
+// into record repositories or decoded into in-memory state.
+//
+// Seeds live in the application's cmd/seed:
+//
+//	func main() {
+//		seed.Register("users", func(ctx context.Context) error {
+//			return db.CreateUser(ctx, "admin@example.com")
+//		})
+//		seed.RegisterFixtures("posts", fixtures.FS, map[string]seed.Target{
+//			"posts": {Repo: posts, Changeset: models.PostChangeset},
+//		})
+//		seed.Main()
+//	}
+//
+// In-memory stores live in the server process, so applications seed them at
+// startup with Run instead.
+package seed
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Func inserts seed data.
+type Func func(ctx context.Context) error
+
+type entry struct {
+	name string
+	fn   Func
+}
+
+var (
+	mu    sync.Mutex
+	seeds []entry
+)
+
+// Register adds a seed. It panics when the name is taken, as sql.Register
+// does for drivers.
+func Register(name string, fn Func) {
+	mu.Lock()
+	defer mu.Unlock()
+	if fn == nil {
+		panic("seed: Register " + name + " with a nil func")
+	}
+	for _, e := range seeds {
+		if e.name == name {
+			panic("seed: Register called twice for " + name)
+		}
+	}
+	seeds = append(seeds, entry{name, fn})
+}
+
+// Names returns the registered seeds in registration order.
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, len(seeds))
+	for i, e := range seeds {
+		names[i] = e.name
+	}
+	return names
+}
+
+// Run runs the named seeds, or all of them in registration order, stopping
+// at the first failure.
+func Run(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		names = Names()
+	}
+	for _, name := range names {
+		fn, ok := lookup(name)
+		if !ok {
+			return fmt.Errorf("seed: unknown seed %q", name)
+		}
+		if err := fn(ctx); err != nil {
+			return fmt.Errorf("seed: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func lookup(name string) (Func, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, e := range seeds {
+		if e.name == name {
+			return e.fn, true
+		}
+	}
+	return nil, false
+}
+
+// ErrProduction is returned by Command when GOLIVE_ENV is production and
+// -force is not given.
+var ErrProduction = errors.New("seed: refusing to seed production (use -force)")
+
+// Main runs the seed command given in os.Args and exits. Applications call
+// it from cmd/seed after registering their seeds.
+func Main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := Command(ctx, os.Args[1:], os.Stdout)
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Command runs the named seeds, or all of them:
+//
+//	seed [-list] [-force] [names...]
+//
+// -list prints the registered seeds. Seeding refuses to run when GOLIVE_ENV
+// is production, unless -force is given.
+func Command(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(out)
+	list := flags.Bool("list", false, "list the registered seeds")
+	force := flags.Bool("force", false, "seed even in production")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *list {
+		for _, name := range Names() {
+			fmt.Fprintln(out, name)
+		}
+		return nil
+	}
+	if os.Getenv("GOLIVE_ENV") == "production" && !*force {
+		return ErrProduction
+	}
+
+	names := flags.Args()
+	if len(names) == 0 {
+		names = Names()
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(out, "no seeds registered")
+		return nil
+	}
+	for _, name := range names {
+		if err := Run(ctx, name); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "seeded %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+)
+
+var testFixtures = fstest.MapFS{
+	"1_users.json": {Data: []byte(`{"users": [{"name": "Ada", "email": "ada@example.com"}]}`)},
+	"2_posts.json": {Data: []byte(`{"posts": [{"title": "Hello", "tags": ["a", "b"]}], "users": [{"name": "Linus"}]}`)},
+	"README.md":    {Data: []byte("ignored")},
+}
+
+func userChangeset(data, params map[string]any) *forms.Changeset {
+	return forms.Cast(data, params, []string{"name", "email"}).ValidateRequired("name", "email")
+}
+
+func TestFixtures_Insert(t *testing.T) {
+	fixtures, err := ReadFixtures(testFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures["users"]) != 2 {
+		t.Fatalf("Expected the users of both files, got %v", fixtures["users"])
+	}
+
+	users, posts := forms.NewMemoryRepo(), forms.NewMemoryRepo()
+	_, err = fixtures.Insert(map[string]Target{
+		"users": {Repo: users, Changeset: userChangeset},
+		"posts": {Repo: posts},
+	})
+	if err == nil || !strings.Contains(err.Error(), "users[1]") {
+		t.Errorf("Expected the user without email rejected, got %v", err)
+	}
+	if len(posts.List()) != 1 || len(users.List()) != 1 {
+		t.Errorf("Expected the records before the failure inserted, got %v and %v", posts.List(), users.List())
+	}
+
+	_, err = fixtures.Insert(map[string]Target{"users": {Repo: users}})
+	if err == nil || !strings.Contains(err.Error(), `"posts"`) {
+		t.Errorf("Expected an error for fixtures without a target, got %v", err)
+	}
+}
+
+func TestFixtures_Decode(t *testing.T) {
+	fixtures := Fixtures{"todos": {{"title": "Ship it", "done": true, "due": "2025-01-02T00:00:00Z"}}}
+	var todos []struct {
+		Title string
+		Done  bool
+		Due   time.Time
+	}
+	if err := fixtures.Decode("todos", &todos); err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 1 || todos[0].Title != "Ship it" || !todos[0].Done || todos[0].Due.Year() != 2025 {
+		t.Errorf("Unexpected todos %+v", todos)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	var ran []string
+	Register("test/a", func(ctx context.Context) error { ran = append(ran, "a"); return nil })
+	Register("test/b", func(ctx context.Context) error { return errors.New("boom") })
+
+	var out bytes.Buffer
+	if err := Command(context.Background(), []string{"test/a"}, &out); err != nil || len(ran) != 1 {
+		t.Fatalf("Expected test/a to run, got %v, %v", ran, err)
+	}
+	if err := Command(context.Background(), []string{"test/b"}, &out); err == nil || !strings.Contains(err.Error(), "test/b: boom") {
+		t.Errorf("Expected the seed's error, got %v", err)
+	}
+	if err := Run(context.Background(), "test/missing"); err == nil {
+		t.Error("Expected an error for an unknown seed")
+	}
+
+	t.Setenv("GOLIVE_ENV", "production")
+	if err := Command(context.Background(), []string{"test/a"}, &out); !errors.Is(err, ErrProduction) {
+		t.Errorf("Expected ErrProduction, got %v", err)
+	}
+	if err := Command(context.Background(), []string{"-force", "test/a"}, &out); err != nil || len(ran) != 2 {
+		t.Errorf("Expected -force to seed production, got %v, %v", ran, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a duplicate name")
+		}
+	}()
+	Register("test/a", func(ctx context.Context) error { return nil })
+}
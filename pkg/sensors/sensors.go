@@ -0,0 +1,162 @@
+// Package sensors decodes the payloads of the client-side sensor bindings
+// (lv-geolocation, lv-visibility, lv-resize) into typed values for use in
+// HandleEvent.
+//
+//	<div lv-geolocation="location" lv-throttle="2000"></div>
+//
+//	case "location":
+//		pos, err := sensors.ParseGeolocation(payload)
+//		if err != nil {
+//			c.LocationDenied = errors.Is(err, sensors.ErrPermissionDenied)
+//			return nil
+//		}
+//		c.Lat, c.Lng = pos.Lat, pos.Lng
+package sensors
+
+import "errors"
+
+// Permission states reported by the client.
+const (
+	PermissionGranted     = "granted"
+	PermissionDenied      = "denied"
+	PermissionPrompt      = "prompt"
+	PermissionUnsupported = "unsupported"
+)
+
+// Sensor errors.
+var (
+	// ErrNoReading means the payload only reports a permission state change.
+	ErrNoReading = errors.New("sensor payload has no reading")
+
+	// ErrPermissionDenied means the user denied access to the sensor.
+	ErrPermissionDenied = errors.New("sensor permission denied")
+
+	// ErrUnavailable means the sensor is unsupported or failed to produce a reading.
+	ErrUnavailable = errors.New("sensor unavailable")
+)
+
+// Position is a geolocation reading.
+type Position struct {
+	Lat       float64
+	Lng       float64
+	Accuracy  float64 // meters
+	Altitude  *float64
+	Heading   *float64 // degrees from north
+	Speed     *float64 // meters per second
+	Timestamp int64    // Unix milliseconds
+}
+
+// Visibility describes whether an element is visible to the user.
+type Visibility struct {
+	// Visible is true when the element is in the viewport and the page is shown.
+	Visible bool
+
+	// InViewport is true when any part of the element intersects the viewport.
+	InViewport bool
+
+	// PageVisible is false when the tab is hidden or minimized.
+	PageVisible bool
+
+	// Ratio is the visible fraction of the element (0-1).
+	Ratio float64
+}
+
+// Size describes an element and the viewport dimensions in CSS pixels.
+type Size struct {
+	Width          int
+	Height         int
+	ViewportWidth  int
+	ViewportHeight int
+	DPR            float64
+	Orientation    string // "landscape" or "portrait"
+}
+
+// Permission returns the permission state reported in a sensor payload,
+// or "" if the payload carries none.
+func Permission(payload map[string]any) string {
+	p, _ := payload["permission"].(string)
+	return p
+}
+
+// ParseGeolocation decodes an lv-geolocation payload.
+// It returns ErrPermissionDenied or ErrUnavailable for error payloads and
+// ErrNoReading for permission-state notifications.
+func ParseGeolocation(payload map[string]any) (Position, error) {
+	switch Permission(payload) {
+	case PermissionDenied:
+		return Position{}, ErrPermissionDenied
+	case PermissionUnsupported:
+		return Position{}, ErrUnavailable
+	}
+	if _, ok := payload["error"]; ok {
+		return Position{}, ErrUnavailable
+	}
+
+	lat, okLat := payload["lat"].(float64)
+	lng, okLng := payload["lng"].(float64)
+	if !okLat || !okLng {
+		return Position{}, ErrNoReading
+	}
+
+	pos := Position{
+		Lat:      lat,
+		Lng:      lng,
+		Accuracy: number(payload["accuracy"]),
+		Altitude: optional(payload["altitude"]),
+		Heading:  optional(payload["heading"]),
+		Speed:    optional(payload["speed"]),
+	}
+	pos.Timestamp = int64(number(payload["timestamp"]))
+	return pos, nil
+}
+
+// ParseVisibility decodes an lv-visibility payload.
+func ParseVisibility(payload map[string]any) Visibility {
+	v := Visibility{Ratio: number(payload["ratio"])}
+	v.Visible, _ = payload["visible"].(bool)
+	v.InViewport, _ = payload["in_viewport"].(bool)
+	v.PageVisible, _ = payload["page_visible"].(bool)
+	return v
+}
+
+// ParseSize decodes an lv-resize payload.
+func ParseSize(payload map[string]any) Size {
+	s := Size{
+		Width:          int(number(payload["width"])),
+		Height:         int(number(payload["height"])),
+		ViewportWidth:  int(number(payload["viewport_width"])),
+		ViewportHeight: int(number(payload["viewport_height"])),
+		DPR:            number(payload["dpr"]),
+	}
+	s.Orientation, _ = payload["orientation"].(string)
+	if s.DPR == 0 {
+		s.DPR = 1
+	}
+	return s
+}
+
+// Breakpoint returns the first name whose minimum width is satisfied by the
+// viewport, scanning from the largest. breakpoints maps names to minimum
+// widths, e.g. {"sm": 0, "md": 768, "lg": 1024}.
+func (s Size) Breakpoint(breakpoints map[string]int) string {
+	best, bestMin := "", -1
+	for name, min := range breakpoints {
+		if s.ViewportWidth >= min && min > bestMin {
+			best, bestMin = name, min
+		}
+	}
+	return best
+}
+
+func number(v any) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+func optional(v any) *float64 {
+	f, ok := v.(float64)
+	if !ok {
+		return nil
+	}
+	return &f
+}
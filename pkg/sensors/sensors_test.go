@@ -0,0 +1,157 @@
+package sensors
+
+import (
+	"errors"
+	"testing"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+func TestParseGeolocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]any
+		want    Position
+		err     error
+	}{
+		{"denied", map[string]any{"permission": "denied"}, Position{}, ErrPermissionDenied},
+		{"unsupported", map[string]any{"permission": "unsupported"}, Position{}, ErrUnavailable},
+		{"error", map[string]any{"error": "timeout", "lat": 1.0, "lng": 2.0}, Position{}, ErrUnavailable},
+		{"permission only", map[string]any{"permission": "granted"}, Position{}, ErrNoReading},
+		{"prompt", map[string]any{"permission": "prompt"}, Position{}, ErrNoReading},
+		{"missing lng", map[string]any{"lat": 1.0}, Position{}, ErrNoReading},
+		{"wrong type", map[string]any{"lat": "1", "lng": "2"}, Position{}, ErrNoReading},
+		{
+			"minimal",
+			map[string]any{"lat": -34.6, "lng": -58.4},
+			Position{Lat: -34.6, Lng: -58.4},
+			nil,
+		},
+		{
+			"full",
+			map[string]any{
+				"permission": "granted",
+				"lat":        48.85, "lng": 2.35,
+				"accuracy": 12.5, "altitude": 35.0, "heading": 90.0, "speed": 0.0,
+				"timestamp": 1700000000000.0,
+			},
+			Position{
+				Lat: 48.85, Lng: 2.35, Accuracy: 12.5,
+				Altitude: ptr(35), Heading: ptr(90), Speed: ptr(0),
+				Timestamp: 1700000000000,
+			},
+			nil,
+		},
+		{
+			"null optionals",
+			map[string]any{"lat": 1.0, "lng": 2.0, "altitude": nil, "heading": nil},
+			Position{Lat: 1, Lng: 2},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseGeolocation(tt.payload)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if !equalPosition(got, tt.want) {
+			t.Errorf("%s: ParseGeolocation = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func equalPosition(a, b Position) bool {
+	eq := func(x, y *float64) bool {
+		return (x == nil) == (y == nil) && (x == nil || *x == *y)
+	}
+	return a.Lat == b.Lat && a.Lng == b.Lng && a.Accuracy == b.Accuracy && a.Timestamp == b.Timestamp &&
+		eq(a.Altitude, b.Altitude) && eq(a.Heading, b.Heading) && eq(a.Speed, b.Speed)
+}
+
+func TestPermission(t *testing.T) {
+	if got := Permission(map[string]any{"permission": "granted"}); got != PermissionGranted {
+		t.Errorf("Permission = %q, want %q", got, PermissionGranted)
+	}
+	if got := Permission(map[string]any{"lat": 1.0}); got != "" {
+		t.Errorf("Permission without state = %q, want empty", got)
+	}
+}
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		payload map[string]any
+		want    Visibility
+	}{
+		{
+			map[string]any{"visible": true, "in_viewport": true, "page_visible": true, "ratio": 0.5},
+			Visibility{Visible: true, InViewport: true, PageVisible: true, Ratio: 0.5},
+		},
+		{
+			map[string]any{"visible": false, "in_viewport": true, "page_visible": false, "ratio": 1.0},
+			Visibility{InViewport: true, Ratio: 1},
+		},
+		{map[string]any{}, Visibility{}},
+		{map[string]any{"visible": "yes", "ratio": "1"}, Visibility{}},
+	}
+	for _, tt := range tests {
+		if got := ParseVisibility(tt.payload); got != tt.want {
+			t.Errorf("ParseVisibility(%v) = %+v, want %+v", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		payload map[string]any
+		want    Size
+	}{
+		{
+			map[string]any{
+				"width": 320.0, "height": 200.0,
+				"viewport_width": 1280.0, "viewport_height": 720.0,
+				"dpr": 2.0, "orientation": "landscape",
+			},
+			Size{Width: 320, Height: 200, ViewportWidth: 1280, ViewportHeight: 720, DPR: 2, Orientation: "landscape"},
+		},
+		{
+			map[string]any{"width": 100.0, "viewport_width": 390.0, "orientation": "portrait"},
+			Size{Width: 100, ViewportWidth: 390, DPR: 1, Orientation: "portrait"},
+		},
+		{map[string]any{"dpr": 0.0}, Size{DPR: 1}},
+		{map[string]any{}, Size{DPR: 1}},
+	}
+	for _, tt := range tests {
+		if got := ParseSize(tt.payload); got != tt.want {
+			t.Errorf("ParseSize(%v) = %+v, want %+v", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestSize_Breakpoint(t *testing.T) {
+	breakpoints := map[string]int{"sm": 0, "md": 768, "lg": 1024}
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, "sm"},
+		{767, "sm"},
+		{768, "md"},
+		{1023, "md"},
+		{1024, "lg"},
+		{2560, "lg"},
+	}
+	for _, tt := range tests {
+		if got := (Size{ViewportWidth: tt.width}).Breakpoint(breakpoints); got != tt.want {
+			t.Errorf("Breakpoint at %dpx = %q, want %q", tt.width, got, tt.want)
+		}
+	}
+
+	// Without a zero minimum, narrow viewports match nothing.
+	if got := (Size{ViewportWidth: 500}).Breakpoint(map[string]int{"md": 768}); got != "" {
+		t.Errorf("Breakpoint below every minimum = %q, want empty", got)
+	}
+	if got := (Size{ViewportWidth: 500}).Breakpoint(nil); got != "" {
+		t.Errorf("Breakpoint with no breakpoints = %q, want empty", got)
+	}
+}
@@ -4,6 +4,7 @@ package shutdown
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"sort"
@@ -58,11 +59,12 @@ func DefaultConfig() *Config {
 
 // Handler manages graceful shutdown.
 type Handler struct {
-	config *Config
-	hooks  []Hook
-	done   chan struct{}
-	closed bool
-	mu     sync.Mutex
+	config  *Config
+	hooks   []Hook
+	onStart []func()
+	done    chan struct{}
+	closed  bool
+	mu      sync.Mutex
 }
 
 // NewHandler creates a new shutdown handler.
@@ -85,6 +87,14 @@ func (h *Handler) Register(hook Hook) {
 	h.hooks = append(h.hooks, hook)
 }
 
+// OnShutdown adds a function called when shutdown begins, before the
+// hooks run, such as marking the instance not ready.
+func (h *Handler) OnShutdown(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onStart = append(h.onStart, fn)
+}
+
 // RegisterFunc is a convenience method to register a function as a hook.
 func (h *Handler) RegisterFunc(name string, priority int, fn func(ctx context.Context) error) {
 	h.Register(Hook{
@@ -125,18 +135,22 @@ func (h *Handler) Shutdown() error {
 	h.closed = true
 	close(h.done)
 
-	// Sort hooks by priority
+	// Sort hooks by priority, keeping the registration order of equal ones
 	hooks := make([]Hook, len(h.hooks))
 	copy(hooks, h.hooks)
+	onStart := h.onStart
 	h.mu.Unlock()
 
-	sort.Slice(hooks, func(i, j int) bool {
+	sort.SliceStable(hooks, func(i, j int) bool {
 		return hooks[i].Priority < hooks[j].Priority
 	})
 
 	if h.config.OnShutdown != nil {
 		h.config.OnShutdown()
 	}
+	for _, fn := range onStart {
+		fn()
+	}
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
@@ -146,7 +160,7 @@ func (h *Handler) Shutdown() error {
 	var errs []error
 	for _, hook := range hooks {
 		start := time.Now()
-		err := hook.Fn(ctx)
+		finished, err := runHook(ctx, hook)
 		duration := time.Since(start)
 
 		if h.config.OnHookComplete != nil {
@@ -158,10 +172,8 @@ func (h *Handler) Shutdown() error {
 		}
 
 		// Check if context is done (timeout)
-		select {
-		case <-ctx.Done():
+		if !finished || ctx.Err() != nil {
 			return errors.Join(append(errs, ErrShutdownTimeout)...)
-		default:
 		}
 	}
 
@@ -172,6 +184,22 @@ func (h *Handler) Shutdown() error {
 	return nil
 }
 
+// runHook runs hook, giving up when ctx is done: a hook that ignores its
+// context cannot hold the process past the timeout. finished is false if
+// it was still running.
+func runHook(ctx context.Context, hook Hook) (finished bool, err error) {
+	result := make(chan error, 1)
+	go func() {
+		result <- hook.Fn(ctx)
+	}()
+	select {
+	case err := <-result:
+		return true, err
+	case <-ctx.Done():
+		return false, fmt.Errorf("shutdown: hook %s: %w", hook.Name, ctx.Err())
+	}
+}
+
 // Done returns a channel that's closed when shutdown is complete.
 func (h *Handler) Done() <-chan struct{} {
 	return h.done
@@ -204,6 +232,12 @@ func Register(hook Hook) {
 	Global().Register(hook)
 }
 
+// OnShutdown adds a function called when the global handler begins
+// shutting down.
+func OnShutdown(fn func()) {
+	Global().OnShutdown(fn)
+}
+
 // RegisterFunc adds a function hook to the global handler.
 func RegisterFunc(name string, priority int, fn func(ctx context.Context) error) {
 	Global().RegisterFunc(name, priority, fn)
@@ -227,9 +261,14 @@ func Done() <-chan struct{} {
 // Common hook priorities
 
 const (
-	// PriorityFirst runs earliest
+	// PriorityFirst runs earliest, such as closing live sockets
+	// (router.ShutdownHook)
 	PriorityFirst = 0
 
+	// PriorityMetrics for flushing metrics, traces and logs, after the
+	// live sockets closed and before the HTTP server stops
+	PriorityMetrics = 50
+
 	// PriorityHTTP for HTTP server shutdown
 	PriorityHTTP = 100
 
@@ -257,6 +296,16 @@ func HTTPServerHook(name string, shutdownFn func(ctx context.Context) error) Hoo
 	}
 }
 
+// FlushHook creates a hook flushing buffered telemetry, such as a metrics
+// exporter.
+func FlushHook(name string, flush func(ctx context.Context) error) Hook {
+	return Hook{
+		Name:     name,
+		Priority: PriorityMetrics,
+		Fn:       flush,
+	}
+}
+
 // CloseableHook creates a hook for anything with a Close() method.
 func CloseableHook(name string, priority int, closer interface{ Close() error }) Hook {
 	return Hook{
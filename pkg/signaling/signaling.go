@@ -0,0 +1,265 @@
+// Package signaling relays WebRTC signaling messages (SDP offers/answers and
+// ICE candidates) between sessions in a room, so voice and video features can
+// piggyback on existing GoliveKit connections instead of a separate server.
+//
+// Usage in a component:
+//
+//	peer, _ := hub.Join(room, c.Socket(), presence.PresenceInfo{Username: name})
+//
+//	func (c *Call) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//		if event == signaling.ClientEvent {
+//			return c.peer.Relay(payload)
+//		}
+//		...
+//	}
+//
+// On the client, signals arrive as "webrtc:signal" events:
+//
+//	liveView.on('webrtc:signal', (sig) => { ... });
+//	liveView.pushEvent('webrtc:signal', { type: 'offer', to: peerId, sdp });
+package signaling
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/presence"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+// ClientEvent is the event name used in both directions between the client
+// and the server for signaling messages.
+const ClientEvent = "webrtc:signal"
+
+// Signal types.
+const (
+	TypeOffer     = "offer"
+	TypeAnswer    = "answer"
+	TypeCandidate = "candidate"
+	TypeJoin      = "join"
+	TypeLeave     = "leave"
+)
+
+// MaxSDPSize limits the size of relayed session descriptions.
+const MaxSDPSize = 64 * 1024
+
+// Common signaling errors.
+var (
+	ErrInvalidSignal = errors.New("invalid signal")
+	ErrPeerClosed    = errors.New("peer has left the room")
+)
+
+// Signal is a message relayed between peers in a room.
+type Signal struct {
+	// Type is one of the Type* constants.
+	Type string `json:"type"`
+
+	// From is the sender's socket ID (always set by the server).
+	From string `json:"from"`
+
+	// To is the recipient's socket ID; empty means every peer in the room.
+	To string `json:"to,omitempty"`
+
+	// SDP carries the session description for offers and answers.
+	SDP string `json:"sdp,omitempty"`
+
+	// Candidate carries an ICE candidate (candidate, sdpMid, sdpMLineIndex).
+	Candidate map[string]any `json:"candidate,omitempty"`
+
+	// Info describes the sender on join signals.
+	Info *presence.PresenceInfo `json:"info,omitempty"`
+}
+
+// ToMap converts the signal to a push payload.
+func (s Signal) ToMap() map[string]any {
+	m := map[string]any{
+		"type": s.Type,
+		"from": s.From,
+	}
+	if s.To != "" {
+		m["to"] = s.To
+	}
+	if s.SDP != "" {
+		m["sdp"] = s.SDP
+	}
+	if s.Candidate != nil {
+		m["candidate"] = s.Candidate
+	}
+	if s.Info != nil {
+		m["info"] = s.Info
+	}
+	return m
+}
+
+// Hub relays signals between peers over PubSub, so peers connected to
+// different nodes can reach each other.
+type Hub struct {
+	pubsub    pubsub.PubSub
+	presences *presence.PresenceManager
+}
+
+// NewHub creates a signaling hub. Room membership is tracked with presence
+// under the same PubSub.
+func NewHub(ps pubsub.PubSub) *Hub {
+	return &Hub{
+		pubsub:    ps,
+		presences: presence.NewPresenceManager(ps),
+	}
+}
+
+// topic returns the PubSub topic for a room.
+func topic(room string) string {
+	return "signal:" + room
+}
+
+// Join adds a socket to a room. Existing peers receive a join signal so they
+// can start negotiating with the newcomer.
+func (h *Hub) Join(room string, socket *core.Socket, info presence.PresenceInfo) (*Peer, error) {
+	p := &Peer{
+		hub:    h,
+		room:   room,
+		socket: socket,
+	}
+
+	sub, err := h.pubsub.Subscribe(topic(room), p.receive)
+	if err != nil {
+		return nil, err
+	}
+	p.sub = sub
+
+	pres := h.presences.GetOrCreate(topic(room))
+	if err := pres.Track(socket, info); err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+	p.presence = pres
+
+	info.Key = socket.ID()
+	if err := p.publish(Signal{Type: TypeJoin, Info: &info}); err != nil {
+		p.Leave()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Peers returns the presence list of a room.
+func (h *Hub) Peers(room string) []presence.PresenceInfo {
+	return h.presences.GetOrCreate(topic(room)).List()
+}
+
+// Peer is a socket's membership in a signaling room.
+type Peer struct {
+	hub      *Hub
+	room     string
+	socket   *core.Socket
+	sub      pubsub.Subscription
+	presence *presence.Presence
+	closed   bool
+	mu       sync.Mutex
+}
+
+// ID returns the peer's socket ID.
+func (p *Peer) ID() string {
+	return p.socket.ID()
+}
+
+// Room returns the room name.
+func (p *Peer) Room() string {
+	return p.room
+}
+
+// Relay validates a signal received from the client (a ClientEvent payload)
+// and forwards it to its recipient.
+func (p *Peer) Relay(payload map[string]any) error {
+	sig, err := parseSignal(payload)
+	if err != nil {
+		return err
+	}
+	return p.publish(sig)
+}
+
+// Leave removes the peer from the room and notifies the other peers.
+func (p *Peer) Leave() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.publishRaw(Signal{Type: TypeLeave, From: p.ID()})
+	if p.presence != nil {
+		p.presence.Untrack(p.socket)
+	}
+	if p.sub != nil {
+		return p.sub.Unsubscribe()
+	}
+	return nil
+}
+
+// publish sends a signal from this peer to the room.
+func (p *Peer) publish(sig Signal) error {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return ErrPeerClosed
+	}
+
+	sig.From = p.ID()
+	return p.publishRaw(sig)
+}
+
+func (p *Peer) publishRaw(sig Signal) error {
+	data, err := json.Marshal(sig)
+	if err != nil {
+		return err
+	}
+	return p.hub.pubsub.Publish(topic(p.room), data)
+}
+
+// receive delivers room signals addressed to this peer to its client.
+func (p *Peer) receive(data []byte) {
+	var sig Signal
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return
+	}
+	if sig.From == p.ID() {
+		return
+	}
+	if sig.To != "" && sig.To != p.ID() {
+		return
+	}
+	p.socket.Push(ClientEvent, sig.ToMap())
+}
+
+// parseSignal validates a client payload. Only offers, answers and ICE
+// candidates may be sent by clients; join/leave are server-generated.
+func parseSignal(payload map[string]any) (Signal, error) {
+	var sig Signal
+	sig.Type, _ = payload["type"].(string)
+	sig.To, _ = payload["to"].(string)
+
+	switch sig.Type {
+	case TypeOffer, TypeAnswer:
+		sdp, _ := payload["sdp"].(string)
+		if sdp == "" || len(sdp) > MaxSDPSize || sig.To == "" {
+			return Signal{}, ErrInvalidSignal
+		}
+		sig.SDP = sdp
+
+	case TypeCandidate:
+		cand, ok := payload["candidate"].(map[string]any)
+		if !ok || sig.To == "" {
+			return Signal{}, ErrInvalidSignal
+		}
+		sig.Candidate = cand
+
+	default:
+		return Signal{}, ErrInvalidSignal
+	}
+	return sig, nil
+}
@@ -0,0 +1,92 @@
+package signaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/presence"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+	lvtesting "github.com/gabrielmiguelok/golivekit/pkg/testing"
+)
+
+func signalsTo(mock *lvtesting.MockSocket) []map[string]any {
+	var out []map[string]any
+	for _, msg := range mock.SentMessages() {
+		if msg.Event == ClientEvent {
+			out = append(out, msg.Payload)
+		}
+	}
+	return out
+}
+
+func TestHub_RelayOfferToPeer(t *testing.T) {
+	ps := pubsub.NewMemoryPubSub()
+	defer ps.Close()
+	hub := NewHub(ps)
+
+	mockA, mockB := lvtesting.NewMockSocket(), lvtesting.NewMockSocket()
+	a := core.NewSocket("a", mockA)
+	b := core.NewSocket("b", mockB)
+
+	peerA, err := hub.Join("room1", a, presence.PresenceInfo{Username: "alice"})
+	if err != nil {
+		t.Fatalf("Join error: %v", err)
+	}
+	peerB, err := hub.Join("room1", b, presence.PresenceInfo{Username: "bob"})
+	if err != nil {
+		t.Fatalf("Join error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// A learns about B joining; B does not receive its own join.
+	gotA := signalsTo(mockA)
+	if len(gotA) != 1 || gotA[0]["type"] != TypeJoin || gotA[0]["from"] != "b" {
+		t.Fatalf("Expected join signal from b, got %v", gotA)
+	}
+	if len(signalsTo(mockB)) != 0 {
+		t.Fatalf("Expected no signals for b yet, got %v", signalsTo(mockB))
+	}
+
+	if err := peerA.Relay(map[string]any{"type": "offer", "to": "b", "sdp": "v=0", "from": "spoofed"}); err != nil {
+		t.Fatalf("Relay error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	gotB := signalsTo(mockB)
+	if len(gotB) != 1 || gotB[0]["type"] != TypeOffer || gotB[0]["sdp"] != "v=0" {
+		t.Fatalf("Expected offer for b, got %v", gotB)
+	}
+	if gotB[0]["from"] != "a" {
+		t.Errorf("Expected server-set sender a, got %v", gotB[0]["from"])
+	}
+
+	if len(hub.Peers("room1")) != 2 {
+		t.Errorf("Expected 2 peers, got %d", len(hub.Peers("room1")))
+	}
+
+	peerB.Leave()
+	time.Sleep(20 * time.Millisecond)
+	gotA = signalsTo(mockA)
+	if last := gotA[len(gotA)-1]; last["type"] != TypeLeave || last["from"] != "b" {
+		t.Errorf("Expected leave signal from b, got %v", last)
+	}
+	if err := peerB.Relay(map[string]any{"type": "answer", "to": "a", "sdp": "x"}); err != ErrPeerClosed {
+		t.Errorf("Expected ErrPeerClosed, got %v", err)
+	}
+}
+
+func TestParseSignal_Invalid(t *testing.T) {
+	invalid := []map[string]any{
+		{"type": "join"},
+		{"type": "offer", "to": "b"},
+		{"type": "offer", "sdp": "v=0"},
+		{"type": "candidate", "to": "b", "candidate": "nope"},
+		{"type": "unknown", "to": "b"},
+	}
+	for _, p := range invalid {
+		if _, err := parseSignal(p); err != ErrInvalidSignal {
+			t.Errorf("parseSignal(%v) = %v, want ErrInvalidSignal", p, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package skeleton renders loading placeholders for data that arrives after
+// the page: assigns loaded with core.BaseComponent.AssignAsync.
+//
+// Slot.Render wraps the placeholder in a data-slot element. When the load
+// resolves, the component re-renders and the slot's new content reaches
+// the client as one slot diff, so the rest of the page is untouched:
+//
+//	func (v *Dashboard) Mount(ctx context.Context, params core.Params, session core.Session) error {
+//	    v.AssignAsync("stats", func(ctx context.Context) (any, error) {
+//	        return v.db.Stats(ctx)
+//	    })
+//	    return nil
+//	}
+//
+//	skeleton.Slot{ID: "stats", Placeholder: skeleton.Card()}.Render(
+//	    v.Assigns().GetAsync("stats"), func(stats any) string { ... })
+//
+// The shimmer comes from CSS, which uses the theme package's color and
+// spacing variables and stops animating for users who prefer reduced
+// motion.
+package skeleton
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Slot is the region of the page showing an async assign.
+type Slot struct {
+	// ID is the data-slot ID of the region.
+	ID string
+
+	// Placeholder is shown while the assign loads. Default Lines(3).
+	Placeholder string
+
+	// Label is read by screen readers while loading. Default "Loading".
+	Label string
+
+	// Failed renders the region when the load failed. Default: a generic
+	// message; the error is not shown to the user.
+	Failed func(err error) string
+}
+
+// Render returns the region for result: the placeholder while it loads,
+// render(value) once it resolved.
+func (s Slot) Render(result core.AsyncResult, render func(value any) string) string {
+	var content string
+	switch {
+	case result.Loading:
+		label := s.Label
+		if label == "" {
+			label = "Loading"
+		}
+		placeholder := s.Placeholder
+		if placeholder == "" {
+			placeholder = Lines(3)
+		}
+		content = fmt.Sprintf(`<div class="lv-skeleton" role="status" aria-busy="true" aria-label="%s">%s</div>`,
+			html.EscapeString(label), placeholder)
+	case result.Err != nil:
+		if s.Failed != nil {
+			content = s.Failed(result.Err)
+		} else {
+			content = `<p class="lv-skeleton-failed" role="alert">Could not load this content.</p>`
+		}
+	default:
+		content = render(result.Value)
+	}
+	return fmt.Sprintf(`<div data-slot="%s">%s</div>`, html.EscapeString(s.ID), content)
+}
+
+// Line returns a bar the height of a line of text. width is a CSS length;
+// empty fills the container.
+func Line(width string) string {
+	if width == "" {
+		return `<span class="lv-skeleton-line" aria-hidden="true"></span>`
+	}
+	return fmt.Sprintf(`<span class="lv-skeleton-line" style="width:%s" aria-hidden="true"></span>`, html.EscapeString(width))
+}
+
+// Lines returns n lines of text, the last one shorter like the end of a
+// paragraph.
+func Lines(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		if i == n-1 && n > 1 {
+			sb.WriteString(Line("60%"))
+		} else {
+			sb.WriteString(Line(""))
+		}
+	}
+	return sb.String()
+}
+
+// Box returns a block of the given CSS width and height, for images,
+// charts and buttons. Empty width fills the container.
+func Box(width, height string) string {
+	style := "height:" + height
+	if width != "" {
+		style = "width:" + width + ";" + style
+	}
+	return fmt.Sprintf(`<span class="lv-skeleton-box" style="%s" aria-hidden="true"></span>`, html.EscapeString(style))
+}
+
+// Circle returns a circle of the given CSS size, for avatars.
+func Circle(size string) string {
+	return fmt.Sprintf(`<span class="lv-skeleton-circle" style="width:%s;height:%s" aria-hidden="true"></span>`,
+		html.EscapeString(size), html.EscapeString(size))
+}
+
+// Card returns an avatar beside a title and two lines of text.
+func Card() string {
+	return `<span class="lv-skeleton-card">` + Circle("2.5rem") +
+		`<span class="lv-skeleton-card-body">` + Line("40%") + Lines(2) + `</span></span>`
+}
+
+// Rows returns n rows of a list or table.
+func Rows(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteString(`<span class="lv-skeleton-row">` + Line("30%") + Line("") + `</span>`)
+	}
+	return sb.String()
+}
+
+// CSS is the placeholder style. It uses the theme variables (see
+// theme.Tag).
+const CSS = `.lv-skeleton{display:flex;flex-direction:column;gap:var(--space-sm)}
+.lv-skeleton-line,.lv-skeleton-box,.lv-skeleton-circle{display:block;border-radius:var(--space-xs);background:linear-gradient(90deg,var(--color-bgHover) 25%,var(--color-bgAlt) 50%,var(--color-bgHover) 75%);background-size:200% 100%;animation:lv-shimmer 1.5s ease-in-out infinite}
+.lv-skeleton-line{height:1em;width:100%}
+.lv-skeleton-circle{flex:none;border-radius:50%}
+.lv-skeleton-card{display:flex;gap:var(--space-md);align-items:flex-start}
+.lv-skeleton-card-body{display:flex;flex-direction:column;gap:var(--space-sm);flex:1}
+.lv-skeleton-row{display:flex;gap:var(--space-md);padding-block:var(--space-sm);border-bottom:1px solid var(--color-border)}
+.lv-skeleton-failed{color:var(--color-danger)}
+@keyframes lv-shimmer{0%{background-position:200% 0}100%{background-position:-200% 0}}
+@media (prefers-reduced-motion:reduce){.lv-skeleton-line,.lv-skeleton-box,.lv-skeleton-circle{animation:none}}`
@@ -0,0 +1,41 @@
+package skeleton
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func TestSlot_Render(t *testing.T) {
+	slot := Slot{ID: "stats", Placeholder: Box("", "4rem")}
+	render := func(v any) string { return fmt.Sprintf("<b>%v</b>", v) }
+
+	loading := slot.Render(core.AsyncResult{Loading: true}, render)
+	want := `<div data-slot="stats"><div class="lv-skeleton" role="status" aria-busy="true" aria-label="Loading">` +
+		`<span class="lv-skeleton-box" style="height:4rem" aria-hidden="true"></span></div></div>`
+	if loading != want {
+		t.Errorf("loading =\n%s\nwant\n%s", loading, want)
+	}
+
+	if got := slot.Render(core.AsyncResult{Value: 7}, render); got != `<div data-slot="stats"><b>7</b></div>` {
+		t.Errorf("resolved = %s", got)
+	}
+
+	failed := slot.Render(core.AsyncResult{Err: errors.New("db: timeout")}, render)
+	if !strings.Contains(failed, `role="alert"`) || strings.Contains(failed, "timeout") {
+		t.Errorf("failed = %s", failed)
+	}
+}
+
+func TestLines(t *testing.T) {
+	got := Lines(3)
+	if n := strings.Count(got, "lv-skeleton-line"); n != 3 {
+		t.Errorf("%d lines in %s", n, got)
+	}
+	if !strings.HasSuffix(got, `style="width:60%" aria-hidden="true"></span>`) {
+		t.Errorf("last line is not shorter: %s", got)
+	}
+}
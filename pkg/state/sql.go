@@ -0,0 +1,316 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/internal/sqlutil"
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+// SQLStore is a Store over a database/sql table, for applications that
+// keep their state in PostgreSQL or SQLite instead of Redis. Each key has a
+// version, bumped on every write, for optimistic locking (see
+// VersionedStore). The version column is the source of truth: Set bumps it
+// and StateManager.Load reads it, so plain and versioned writes can be
+// mixed. Expiry times are stored as Unix milliseconds; expired
+// rows are ignored by reads and removed by Cleanup.
+//
+// The table name, which may be schema-qualified ("app.live_state"), is
+// interpolated into the queries and must come from trusted configuration,
+// never from user input.
+type SQLStore struct {
+	DB      *sql.DB
+	Dialect migrate.Dialect
+	Table   string
+
+	closed atomic.Bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewSQLStore creates a store over the golivekit_state table. dialect is
+// migrate.Postgres or migrate.SQLite (3.24 or later); NewSQLStore panics on
+// others.
+//
+//	store := state.NewSQLStore(db, migrate.Postgres)
+//	if err := store.CreateTables(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//	go store.RunCleanup(ctx, time.Minute)
+func NewSQLStore(db *sql.DB, dialect migrate.Dialect) *SQLStore {
+	if dialect != migrate.Postgres && dialect != migrate.SQLite {
+		panic(fmt.Sprintf("state: unsupported SQL dialect %q", dialect))
+	}
+	return &SQLStore{
+		DB:      db,
+		Dialect: dialect,
+		Table:   "golivekit_state",
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *SQLStore) ph(n int) string {
+	if s.Dialect == migrate.Postgres {
+		return sqlutil.PostgresPlaceholder(n)
+	}
+	return "?"
+}
+
+// Schema returns the statements creating the table.
+func (s *SQLStore) Schema() []string {
+	blob := "BLOB"
+	if s.Dialect == migrate.Postgres {
+		blob = "BYTEA"
+	}
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	state_key VARCHAR(255) PRIMARY KEY,
+	value %s NOT NULL,
+	version BIGINT NOT NULL,
+	expires_at BIGINT NOT NULL DEFAULT 0
+)`, s.Table, blob),
+		// Indexes live in the schema of their table
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_expires_at ON %s (expires_at)`,
+			strings.ReplaceAll(s.Table, ".", "_"), s.Table),
+	}
+}
+
+// Migration returns the contents of the up and down files of a migration
+// creating the table, for applications that manage their schema with
+// package migrate.
+func (s *SQLStore) Migration() (up, down string) {
+	return strings.Join(s.Schema(), ";\n") + ";\n", fmt.Sprintf("DROP TABLE %s;\n", s.Table)
+}
+
+// CreateTables runs Schema. Use migrations instead in applications that
+// have them.
+func (s *SQLStore) CreateTables(ctx context.Context) error {
+	for _, stmt := range s.Schema() {
+		if _, err := s.DB.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("state: create tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// expiresAt returns the stored expiry of a ttl (0 for none).
+func expiresAt(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixMilli()
+}
+
+// live is the condition of rows that have not expired, comparing with the
+// bind parameter n.
+func (s *SQLStore) live(n int) string {
+	return fmt.Sprintf("(expires_at = 0 OR expires_at > %s)", s.ph(n))
+}
+
+// Get implements Store.
+func (s *SQLStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, _, err := s.GetVersion(ctx, key)
+	return value, err
+}
+
+// GetVersion implements VersionedStore.
+func (s *SQLStore) GetVersion(ctx context.Context, key string) ([]byte, uint64, error) {
+	if s.closed.Load() {
+		return nil, 0, ErrStoreClosed
+	}
+	query := fmt.Sprintf("SELECT value, version FROM %s WHERE state_key = %s AND %s",
+		s.Table, s.ph(1), s.live(2))
+	var (
+		value   []byte
+		version int64
+	)
+	err := s.DB.QueryRowContext(ctx, query, key, time.Now().UnixMilli()).Scan(&value, &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("state: get %s: %w", key, err)
+	}
+	return value, uint64(version), nil
+}
+
+// Set implements Store, bumping the version of the key.
+func (s *SQLStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if s.closed.Load() {
+		return ErrStoreClosed
+	}
+	query := fmt.Sprintf(`INSERT INTO %s AS cur (state_key, value, version, expires_at) VALUES (%s, %s, 1, %s)
+ON CONFLICT (state_key) DO UPDATE SET value = excluded.value, version = cur.version + 1, expires_at = excluded.expires_at`,
+		s.Table, s.ph(1), s.ph(2), s.ph(3))
+	if _, err := s.DB.ExecContext(ctx, query, key, value, expiresAt(ttl)); err != nil {
+		return fmt.Errorf("state: set %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetVersion implements VersionedStore.
+func (s *SQLStore) SetVersion(ctx context.Context, key string, value []byte, version uint64, ttl time.Duration) error {
+	if s.closed.Load() {
+		return ErrStoreClosed
+	}
+	now := time.Now().UnixMilli()
+
+	// Replace the previous version, or an expired row
+	update := fmt.Sprintf(`UPDATE %s SET value = %s, version = %s, expires_at = %s
+WHERE state_key = %s AND (version = %s OR (expires_at <> 0 AND expires_at <= %s))`,
+		s.Table, s.ph(1), s.ph(2), s.ph(3), s.ph(4), s.ph(5), s.ph(6))
+	res, err := s.DB.ExecContext(ctx, update, value, int64(version), expiresAt(ttl), key, int64(version)-1, now)
+	if err != nil {
+		return fmt.Errorf("state: set %s: %w", key, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	// Or create the key, unless another writer got there first
+	insert := fmt.Sprintf(`INSERT INTO %s (state_key, value, version, expires_at) VALUES (%s, %s, %s, %s)
+ON CONFLICT (state_key) DO NOTHING`,
+		s.Table, s.ph(1), s.ph(2), s.ph(3), s.ph(4))
+	res, err = s.DB.ExecContext(ctx, insert, key, value, int64(version), expiresAt(ttl))
+	if err != nil {
+		return fmt.Errorf("state: set %s: %w", key, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("state: set %s: %w", key, ErrVersionConflict)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *SQLStore) Delete(ctx context.Context, key string) error {
+	if s.closed.Load() {
+		return ErrStoreClosed
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE state_key = %s", s.Table, s.ph(1))
+	if _, err := s.DB.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("state: delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Exists implements Store.
+func (s *SQLStore) Exists(ctx context.Context, key string) (bool, error) {
+	if s.closed.Load() {
+		return false, ErrStoreClosed
+	}
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE state_key = %s AND %s", s.Table, s.ph(1), s.live(2))
+	var one int
+	err := s.DB.QueryRowContext(ctx, query, key, time.Now().UnixMilli()).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("state: exists %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Keys implements Store, with the glob patterns of MemoryStore.
+func (s *SQLStore) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if s.closed.Load() {
+		return nil, ErrStoreClosed
+	}
+	query := fmt.Sprintf(`SELECT state_key FROM %s WHERE state_key LIKE %s ESCAPE '\' AND %s`,
+		s.Table, s.ph(1), s.live(2))
+	rows, err := s.DB.QueryContext(ctx, query, globToLike(pattern), time.Now().UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("state: keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("state: keys: %w", err)
+		}
+		// LIKE is a superset of the glob (and case-insensitive in SQLite)
+		if matched, _ := filepath.Match(pattern, key); matched {
+			keys = append(keys, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("state: keys: %w", err)
+	}
+	return keys, nil
+}
+
+// globToLike converts a glob pattern to a LIKE pattern matching at least
+// the same keys.
+func globToLike(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '[':
+			// A character class: match anything here, filepath.Match
+			// checks it
+			b.WriteByte('%')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Cleanup deletes the expired rows, returning how many were removed.
+func (s *SQLStore) Cleanup(ctx context.Context) (int64, error) {
+	if s.closed.Load() {
+		return 0, ErrStoreClosed
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at <> 0 AND expires_at <= %s", s.Table, s.ph(1))
+	res, err := s.DB.ExecContext(ctx, query, time.Now().UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("state: cleanup: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// RunCleanup runs Cleanup every interval until ctx is done or the store is
+// closed. Run it on one instance, or on all of them: the deletes do not
+// conflict.
+func (s *SQLStore) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Cleanup(ctx)
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Close implements Store. It stops RunCleanup but leaves the database
+// open, since it belongs to the application.
+func (s *SQLStore) Close() error {
+	s.once.Do(func() {
+		s.closed.Store(true)
+		close(s.done)
+	})
+	return nil
+}
@@ -0,0 +1,313 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+// fakeDB is the state of a database opened with the "statetest" driver: it
+// keeps the state table in memory, interpreting the queries of SQLStore.
+type fakeDB struct {
+	mu      sync.Mutex
+	queries []string
+	rows    map[string]fakeRow
+}
+
+type fakeRow struct {
+	value   []byte
+	version int64
+	expires int64
+}
+
+// live reports whether the row has not expired at now.
+func (r fakeRow) live(now int64) bool {
+	return r.expires == 0 || r.expires > now
+}
+
+var (
+	fakeMu  sync.Mutex
+	fakeDBs = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("statetest", fakeDriver{})
+}
+
+// openStore opens a store over an empty fake database named after the test.
+func openStore(t *testing.T, dialect migrate.Dialect) (*SQLStore, *fakeDB) {
+	t.Helper()
+	fakeMu.Lock()
+	fake := &fakeDB{rows: make(map[string]fakeRow)}
+	fakeDBs[t.Name()] = fake
+	fakeMu.Unlock()
+
+	db, err := sql.Open("statetest", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewSQLStore(db, dialect), fake
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	return &fakeConn{db: fakeDBs[dsn]}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func arg(args []driver.NamedValue, i int) driver.Value { return args[i].Value }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	db := c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, query)
+
+	now := time.Now().UnixMilli()
+	switch {
+	case strings.HasPrefix(query, "INSERT") && strings.Contains(query, "DO UPDATE"):
+		// Set
+		key := arg(args, 0).(string)
+		row, ok := db.rows[key]
+		row.version++
+		if !ok {
+			row.version = 1
+		}
+		row.value, row.expires = arg(args, 1).([]byte), arg(args, 2).(int64)
+		db.rows[key] = row
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "UPDATE"):
+		// SetVersion replacing the previous version or an expired row
+		key := arg(args, 3).(string)
+		row, ok := db.rows[key]
+		if !ok || (row.version != arg(args, 4).(int64) && row.live(now)) {
+			return driver.RowsAffected(0), nil
+		}
+		db.rows[key] = fakeRow{value: arg(args, 0).([]byte), version: arg(args, 1).(int64), expires: arg(args, 2).(int64)}
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "INSERT") && strings.Contains(query, "DO NOTHING"):
+		key := arg(args, 0).(string)
+		if _, ok := db.rows[key]; ok {
+			return driver.RowsAffected(0), nil
+		}
+		db.rows[key] = fakeRow{value: arg(args, 1).([]byte), version: arg(args, 2).(int64), expires: arg(args, 3).(int64)}
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "DELETE") && strings.Contains(query, "state_key ="):
+		delete(db.rows, arg(args, 0).(string))
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "DELETE"):
+		// Cleanup
+		var n int64
+		for key, row := range db.rows {
+			if !row.live(arg(args, 0).(int64)) {
+				delete(db.rows, key)
+				n++
+			}
+		}
+		return driver.RowsAffected(n), nil
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	db := c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, query)
+
+	switch {
+	case strings.HasPrefix(query, "SELECT state_key"):
+		// Keys: every live key, SQLStore filters them with the glob
+		rows := &fakeRows{columns: []string{"state_key"}}
+		for key, row := range db.rows {
+			if row.live(arg(args, 1).(int64)) {
+				rows.data = append(rows.data, []driver.Value{key})
+			}
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "SELECT 1"):
+		rows := &fakeRows{columns: []string{"1"}}
+		if row, ok := db.rows[arg(args, 0).(string)]; ok && row.live(arg(args, 1).(int64)) {
+			rows.data = append(rows.data, []driver.Value{int64(1)})
+		}
+		return rows, nil
+	default:
+		rows := &fakeRows{columns: []string{"value", "version"}}
+		if row, ok := db.rows[arg(args, 0).(string)]; ok && row.live(arg(args, 1).(int64)) {
+			rows.data = append(rows.data, []driver.Value{row.value, row.version})
+		}
+		return rows, nil
+	}
+}
+
+type fakeRows struct {
+	columns []string
+	data    [][]driver.Value
+	i       int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.i])
+	r.i++
+	return nil
+}
+
+func TestSQLStore_GetSet(t *testing.T) {
+	ctx := context.Background()
+	store, _ := openStore(t, migrate.Postgres)
+
+	if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Expected ErrKeyNotFound, got %v", err)
+	}
+	store.Set(ctx, "a", []byte("1"), 0)
+	store.Set(ctx, "a", []byte("2"), 0)
+	value, version, err := store.GetVersion(ctx, "a")
+	if err != nil || string(value) != "2" || version != 2 {
+		t.Errorf("Expected 2 at version 2, got %q at %d (%v)", value, version, err)
+	}
+	if ok, _ := store.Exists(ctx, "a"); !ok {
+		t.Error("Expected a to exist")
+	}
+
+	store.Set(ctx, "user:1", []byte("x"), 0)
+	store.Set(ctx, "user:2", []byte("y"), 0)
+	keys, _ := store.Keys(ctx, "user:*")
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "user:1,user:2" {
+		t.Errorf("Keys(user:*) = %v", keys)
+	}
+
+	store.Delete(ctx, "a")
+	if ok, _ := store.Exists(ctx, "a"); ok {
+		t.Error("Expected a deleted")
+	}
+
+	store.Close()
+	if err := store.Set(ctx, "a", nil, 0); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Expected ErrStoreClosed, got %v", err)
+	}
+}
+
+func TestSQLStore_SetVersion(t *testing.T) {
+	ctx := context.Background()
+	store, _ := openStore(t, migrate.SQLite)
+
+	// A missing key accepts any version
+	if err := store.SetVersion(ctx, "a", []byte("1"), 1, 0); err != nil {
+		t.Fatalf("SetVersion(1): %v", err)
+	}
+	if err := store.SetVersion(ctx, "a", []byte("2"), 2, 0); err != nil {
+		t.Fatalf("SetVersion(2): %v", err)
+	}
+
+	// Another writer saved version 2 first
+	if err := store.SetVersion(ctx, "a", []byte("x"), 2, 0); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+	if value, version, _ := store.GetVersion(ctx, "a"); string(value) != "2" || version != 2 {
+		t.Errorf("Expected the conflicting write discarded, got %q at %d", value, version)
+	}
+
+	// Set bumps the column, which versioned writers must follow
+	store.Set(ctx, "a", []byte("3"), 0)
+	if err := store.SetVersion(ctx, "a", []byte("x"), 3, 0); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected a conflict after Set, got %v", err)
+	}
+	if err := store.SetVersion(ctx, "a", []byte("4"), 4, 0); err != nil {
+		t.Errorf("SetVersion(4): %v", err)
+	}
+}
+
+func TestSQLStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store, _ := openStore(t, migrate.Postgres)
+
+	store.Set(ctx, "old", []byte("1"), time.Millisecond)
+	store.Set(ctx, "new", []byte("1"), time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get(ctx, "old"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected an expired key to be missing, got %v", err)
+	}
+	if keys, _ := store.Keys(ctx, "*"); len(keys) != 1 || keys[0] != "new" {
+		t.Errorf("Expected only the live key, got %v", keys)
+	}
+
+	// An expired key accepts any version
+	store.Set(ctx, "stale", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if err := store.SetVersion(ctx, "stale", []byte("2"), 7, 0); err != nil {
+		t.Errorf("Expected an expired key replaced, got %v", err)
+	}
+
+	if n, err := store.Cleanup(ctx); err != nil || n != 1 {
+		t.Errorf("Cleanup() = %d, %v; want 1", n, err)
+	}
+}
+
+func TestSQLStore_SchemaQualifiedTable(t *testing.T) {
+	ctx := context.Background()
+	store, fake := openStore(t, migrate.Postgres)
+	store.Table = "app.live_state"
+
+	store.Set(ctx, "a", []byte("1"), 0)
+	for _, query := range append(fake.queries, store.Schema()...) {
+		if strings.Contains(query, "app.live_state.") || strings.Contains(query, "app.live_state_") {
+			t.Errorf("Expected no qualified names built from the table, got %q", query)
+		}
+	}
+}
+
+func TestStateManager_LoadsStoredVersion(t *testing.T) {
+	ctx := context.Background()
+	store, _ := openStore(t, migrate.Postgres)
+	sm := NewStateManager(store)
+
+	state := NewComponentState("s1", "Counter")
+	state.SetExpiry(time.Hour)
+	if err := sm.Save(ctx, state); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A plain write bumps the column past the serialized version
+	data, _ := store.Get(ctx, "golivekit:state:s1")
+	store.Set(ctx, "golivekit:state:s1", data, time.Hour)
+
+	loaded, err := sm.Load(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Version != state.Version+1 {
+		t.Errorf("Expected the stored version %d, got %d", state.Version+1, loaded.Version)
+	}
+	if err := sm.Save(ctx, loaded); err != nil {
+		t.Errorf("Expected the loaded state saved, got %v", err)
+	}
+}
@@ -14,6 +14,10 @@ var (
 	ErrStoreClosed    = errors.New("store is closed")
 	ErrInvalidData    = errors.New("invalid data format")
 	ErrStoreTimeout   = errors.New("store operation timeout")
+
+	// ErrVersionConflict is returned by VersionedStore.SetVersion when the
+	// key was written by someone else since it was read.
+	ErrVersionConflict = errors.New("version conflict")
 )
 
 // Store is the interface for state storage backends.
@@ -37,6 +41,21 @@ type Store interface {
 	Close() error
 }
 
+// VersionedStore is a Store that versions its keys, so concurrent writers
+// do not overwrite each other. StateManager.Save uses it when the store
+// implements it.
+type VersionedStore interface {
+	Store
+
+	// GetVersion retrieves a value and its version.
+	GetVersion(ctx context.Context, key string) ([]byte, uint64, error)
+
+	// SetVersion stores value at version, which must follow the stored
+	// version (version-1), or ErrVersionConflict is returned. A missing
+	// or expired key accepts any version.
+	SetVersion(ctx context.Context, key string, value []byte, version uint64, ttl time.Duration) error
+}
+
 // TypedStore provides type-safe access to the store.
 type TypedStore[T any] struct {
 	store      Store
@@ -179,26 +198,49 @@ func WithDefaultTTL(ttl time.Duration) StateManagerOption {
 	}
 }
 
-// Save persists component state.
+// Save persists component state. With a VersionedStore, it fails with
+// ErrVersionConflict when the state was saved elsewhere since it was
+// loaded, leaving state unchanged: load it again and reapply the change.
 func (sm *StateManager) Save(ctx context.Context, state *ComponentState) error {
+	prev := *state
 	state.Update()
 
 	data, err := sm.serializer.Marshal(state)
 	if err != nil {
+		*state = prev
 		return err
 	}
 
 	key := sm.keyPrefix + state.SocketID
 	ttl := time.Until(state.ExpiresAt)
 
-	return sm.store.Set(ctx, key, data, ttl)
+	if vs, ok := sm.store.(VersionedStore); ok {
+		err = vs.SetVersion(ctx, key, data, state.Version, ttl)
+	} else {
+		err = sm.store.Set(ctx, key, data, ttl)
+	}
+	if err != nil {
+		*state = prev
+	}
+	return err
 }
 
-// Load retrieves component state.
+// Load retrieves component state. With a VersionedStore, the state's
+// Version is the stored one, which plain Set calls may have bumped.
 func (sm *StateManager) Load(ctx context.Context, socketID string) (*ComponentState, error) {
 	key := sm.keyPrefix + socketID
 
-	data, err := sm.store.Get(ctx, key)
+	var (
+		data    []byte
+		version uint64
+		err     error
+	)
+	vs, versioned := sm.store.(VersionedStore)
+	if versioned {
+		data, version, err = vs.GetVersion(ctx, key)
+	} else {
+		data, err = sm.store.Get(ctx, key)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +249,9 @@ func (sm *StateManager) Load(ctx context.Context, socketID string) (*ComponentSt
 	if err := sm.serializer.Unmarshal(data, &state); err != nil {
 		return nil, err
 	}
+	if versioned {
+		state.Version = version
+	}
 
 	if state.IsExpired() {
 		sm.Delete(ctx, socketID)
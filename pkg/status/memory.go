@@ -0,0 +1,85 @@
+package status
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryHistory keeps the history in memory: for tests and single
+// instances that can lose it on restart.
+type MemoryHistory struct {
+	mu        sync.RWMutex
+	samples   []Sample
+	incidents []Incident
+	nextID    int64
+}
+
+// NewMemoryHistory creates an empty history.
+func NewMemoryHistory() *MemoryHistory {
+	return &MemoryHistory{}
+}
+
+// Record implements History.
+func (h *MemoryHistory) Record(ctx context.Context, samples ...Sample) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples = append(h.samples, samples...)
+	sort.SliceStable(h.samples, func(i, j int) bool { return h.samples[i].At.Before(h.samples[j].At) })
+	return nil
+}
+
+// Samples implements History.
+func (h *MemoryHistory) Samples(ctx context.Context, since time.Time) ([]Sample, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	i := sort.Search(len(h.samples), func(i int) bool { return !h.samples[i].At.Before(since) })
+	return append([]Sample(nil), h.samples[i:]...), nil
+}
+
+// Prune implements History.
+func (h *MemoryHistory) Prune(ctx context.Context, before time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := sort.Search(len(h.samples), func(i int) bool { return !h.samples[i].At.Before(before) })
+	h.samples = append([]Sample(nil), h.samples[i:]...)
+	return nil
+}
+
+// AddIncident implements History.
+func (h *MemoryHistory) AddIncident(ctx context.Context, inc *Incident) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	inc.ID = h.nextID
+	h.incidents = append(h.incidents, *inc)
+	return nil
+}
+
+// ResolveIncident implements History.
+func (h *MemoryHistory) ResolveIncident(ctx context.Context, id int64, at time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.incidents {
+		if h.incidents[i].ID == id {
+			h.incidents[i].Resolved = at
+			return nil
+		}
+	}
+	return ErrIncidentNotFound
+}
+
+// Incidents implements History.
+func (h *MemoryHistory) Incidents(ctx context.Context, since time.Time) ([]Incident, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var result []Incident
+	for _, inc := range h.incidents {
+		if inc.Open() || !inc.Started.Before(since) {
+			result = append(result, inc)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Started.After(result[j].Started) })
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package status
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/health"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+// Topic is the pubsub topic on which the Monitor announces new results and
+// incident changes; Page subscribes to it.
+const Topic = "status:updates"
+
+// EventUpdated is the event broadcast on Topic.
+const EventUpdated = "status:updated"
+
+// Report is what the status page shows.
+type Report struct {
+	Status    health.Status
+	Checked   time.Time
+	Checks    []CheckUptime
+	Incidents []Incident
+}
+
+// Monitor runs the health checks on an interval, records the results and
+// announces them.
+type Monitor struct {
+	// Interval between checks (default 1 minute).
+	Interval time.Duration
+
+	// Days of history shown and kept (default 90).
+	Days int
+
+	checker     *health.Checker
+	history     History
+	broadcaster *pubsub.Broadcaster
+
+	mu        sync.RWMutex
+	current   health.HealthStatus
+	lastPrune time.Time
+}
+
+// NewMonitor creates a monitor for checker. ps may be nil, in which case
+// pages only refresh on reload.
+func NewMonitor(checker *health.Checker, history History, ps pubsub.PubSub) *Monitor {
+	m := &Monitor{
+		Interval: time.Minute,
+		Days:     90,
+		checker:  checker,
+		history:  history,
+	}
+	if ps != nil {
+		m.broadcaster = pubsub.NewBroadcaster(ps)
+	}
+	return m
+}
+
+// History returns the monitor's history.
+func (m *Monitor) History() History {
+	return m.history
+}
+
+// Run checks immediately and then every Interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are transient (the history's database being down, say);
+		// the next tick retries.
+		_ = m.CheckNow(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow runs the checks once, records the results and announces them.
+func (m *Monitor) CheckNow(ctx context.Context) error {
+	result := m.checker.Check(ctx)
+
+	m.mu.Lock()
+	m.current = result
+	prune := result.Timestamp.Sub(m.lastPrune) >= time.Hour
+	if prune {
+		m.lastPrune = result.Timestamp
+	}
+	m.mu.Unlock()
+
+	samples := make([]Sample, 0, len(result.Checks))
+	for name, check := range result.Checks {
+		samples = append(samples, Sample{
+			Check:     name,
+			Status:    check.Status,
+			At:        result.Timestamp,
+			LatencyMS: int64(check.Duration), // CheckResult.Duration holds milliseconds
+			Error:     check.Error,
+		})
+	}
+	if err := m.history.Record(ctx, samples...); err != nil {
+		return err
+	}
+	if prune {
+		if err := m.history.Prune(ctx, result.Timestamp.AddDate(0, 0, -m.Days)); err != nil {
+			return err
+		}
+	}
+
+	m.announce()
+	return nil
+}
+
+// Current returns the result of the last check.
+func (m *Monitor) Current() health.HealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Report loads the history for the status page.
+func (m *Monitor) Report(ctx context.Context) (Report, error) {
+	now := time.Now()
+	since := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(m.Days - 1))
+
+	samples, err := m.history.Samples(ctx, since)
+	if err != nil {
+		return Report{}, err
+	}
+	incidents, err := m.history.Incidents(ctx, since)
+	if err != nil {
+		return Report{}, err
+	}
+
+	current := m.Current()
+	return Report{
+		Status:    current.Status,
+		Checked:   current.Timestamp,
+		Checks:    Summarize(samples, incidents, m.Days, now),
+		Incidents: incidents,
+	}, nil
+}
+
+// OpenIncident records an incident starting now and announces it.
+func (m *Monitor) OpenIncident(ctx context.Context, title, body string, impact health.Status) (Incident, error) {
+	inc := Incident{Title: title, Body: body, Impact: impact, Started: time.Now()}
+	if err := m.history.AddIncident(ctx, &inc); err != nil {
+		return Incident{}, err
+	}
+	m.announce()
+	return inc, nil
+}
+
+// ResolveIncident resolves an incident now and announces it.
+func (m *Monitor) ResolveIncident(ctx context.Context, id int64) error {
+	if err := m.history.ResolveIncident(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	m.announce()
+	return nil
+}
+
+func (m *Monitor) announce() {
+	if m.broadcaster != nil {
+		_ = m.broadcaster.Broadcast(Topic, EventUpdated, nil)
+	}
+}
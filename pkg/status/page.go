@@ -0,0 +1,214 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/health"
+)
+
+// Page is the status page live view: an overall banner, a bar per check
+// with one segment per day, and the incidents. Connected pages refresh
+// whenever the monitor records results or an incident changes.
+type Page struct {
+	core.BaseComponent
+
+	Title string
+
+	monitor *Monitor
+	report  Report
+	err     error
+}
+
+// NewPage returns a component factory for r.Live.
+func NewPage(monitor *Monitor, title string) func() core.Component {
+	return func() core.Component {
+		return &Page{Title: title, monitor: monitor}
+	}
+}
+
+// Name returns the component name.
+func (p *Page) Name() string {
+	return "status-page"
+}
+
+// Mount loads the report and subscribes to updates.
+func (p *Page) Mount(ctx context.Context, params core.Params, session core.Session) error {
+	if socket := p.Socket(); socket != nil {
+		if err := socket.Subscribe(Topic); err != nil && !errors.Is(err, core.ErrNoSubscriber) {
+			return fmt.Errorf("status: subscribe: %w", err)
+		}
+	}
+	p.load(ctx)
+	return nil
+}
+
+// HandleInfo reloads the report when the monitor announces an update.
+func (p *Page) HandleInfo(ctx context.Context, msg any) error {
+	if b, ok := msg.(core.Broadcast); ok && b.Topic == Topic {
+		p.load(ctx)
+	}
+	return nil
+}
+
+// load keeps the last report when loading fails, so a database hiccup
+// shows a notice instead of an empty page.
+func (p *Page) load(ctx context.Context) {
+	report, err := p.monitor.Report(ctx)
+	p.err = err
+	if err == nil {
+		p.report = report
+	}
+}
+
+// Render renders the page.
+func (p *Page) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		var sb strings.Builder
+		sb.WriteString(`<div class="lv-status">`)
+		fmt.Fprintf(&sb, `<h1>%s</h1>`, html.EscapeString(p.Title))
+		sb.WriteString(`<div data-slot="status-banner">`)
+		p.renderBanner(&sb)
+		sb.WriteString(`</div><div class="lv-status-checks" data-slot="status-checks">`)
+		p.renderChecks(&sb)
+		sb.WriteString(`</div><div class="lv-status-incidents" data-slot="status-incidents">`)
+		p.renderIncidents(&sb)
+		sb.WriteString(`</div></div>`)
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+func (p *Page) renderBanner(sb *strings.Builder) {
+	status := p.report.Status
+	label := statusLabel(status)
+	for _, inc := range p.report.Incidents {
+		if inc.Open() {
+			label = inc.Title
+			if severity(inc.Impact) > severity(status) {
+				status = inc.Impact
+			}
+			break
+		}
+	}
+	fmt.Fprintf(sb, `<div class="lv-status-banner lv-status-%s">%s`, statusClass(status), html.EscapeString(label))
+	if !p.report.Checked.IsZero() {
+		fmt.Fprintf(sb, ` <time datetime="%s">checked %s</time>`,
+			p.report.Checked.UTC().Format(time.RFC3339), p.report.Checked.UTC().Format("15:04 UTC"))
+	}
+	sb.WriteString(`</div>`)
+	if p.err != nil {
+		sb.WriteString(`<p class="lv-status-error">Status history is temporarily unavailable.</p>`)
+	}
+}
+
+func (p *Page) renderChecks(sb *strings.Builder) {
+	for _, c := range p.report.Checks {
+		fmt.Fprintf(sb, `<section class="lv-status-check"><h2>%s <span class="lv-status-%s">%s</span></h2>`,
+			html.EscapeString(c.Name), statusClass(c.Current), statusLabel(c.Current))
+		sb.WriteString(`<div class="lv-status-bar">`)
+		for _, d := range c.Days {
+			title := d.Date.Format("Jan 2")
+			if up := d.Uptime(); up >= 0 {
+				title += fmt.Sprintf(": %s uptime", percent(up))
+			} else {
+				title += ": no data"
+			}
+			class := "lv-status-day lv-status-" + statusClass(d.Status())
+			if len(d.Incidents) > 0 {
+				class += " lv-status-annotated"
+				title += "\n" + strings.Join(d.Incidents, "\n")
+			}
+			fmt.Fprintf(sb, `<span class="%s" title="%s"></span>`, class, html.EscapeString(title))
+		}
+		sb.WriteString(`</div>`)
+		if c.Uptime >= 0 {
+			fmt.Fprintf(sb, `<p class="lv-status-uptime">%s uptime over %d days</p>`, percent(c.Uptime), len(c.Days))
+		}
+		sb.WriteString(`</section>`)
+	}
+}
+
+func (p *Page) renderIncidents(sb *strings.Builder) {
+	if len(p.report.Incidents) == 0 {
+		sb.WriteString(`<p class="lv-status-none">No incidents reported.</p>`)
+		return
+	}
+	sb.WriteString(`<h2>Incidents</h2>`)
+	for _, inc := range p.report.Incidents {
+		state := "Resolved " + inc.Resolved.UTC().Format("Jan 2 15:04 UTC")
+		if inc.Open() {
+			state = "Ongoing"
+		}
+		fmt.Fprintf(sb, `<article class="lv-status-incident lv-status-%s"><h3>%s</h3><p class="lv-status-incident-time">%s · %s</p>`,
+			statusClass(inc.Impact), html.EscapeString(inc.Title), inc.Started.UTC().Format("Jan 2 15:04 UTC"), state)
+		if inc.Body != "" {
+			fmt.Fprintf(sb, `<p>%s</p>`, html.EscapeString(inc.Body))
+		}
+		sb.WriteString(`</article>`)
+	}
+}
+
+func statusClass(s health.Status) string {
+	if s == "" {
+		return "unknown"
+	}
+	return string(s)
+}
+
+func statusLabel(s health.Status) string {
+	switch s {
+	case health.StatusHealthy:
+		return "All systems operational"
+	case health.StatusDegraded:
+		return "Degraded performance"
+	case health.StatusUnhealthy:
+		return "Major outage"
+	default:
+		return "Status unknown"
+	}
+}
+
+func severity(s health.Status) int {
+	switch s {
+	case health.StatusDegraded:
+		return 1
+	case health.StatusUnhealthy:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func percent(f float64) string {
+	return fmt.Sprintf("%.2f%%", f*100)
+}
+
+// CSS is a default style for the status page.
+const CSS = `.lv-status{max-width:48rem;margin:0 auto;font-family:system-ui,sans-serif;color:#1e293b}
+.lv-status-banner{padding:1rem 1.25rem;border-radius:0.5rem;font-weight:600;color:#fff;background:#64748b}
+.lv-status-banner time{float:right;font-weight:400;opacity:0.85}
+.lv-status-banner.lv-status-healthy{background:#16a34a}
+.lv-status-banner.lv-status-degraded{background:#d97706}
+.lv-status-banner.lv-status-unhealthy{background:#dc2626}
+.lv-status-error{color:#b91c1c}
+.lv-status-check{margin:1.5rem 0}
+.lv-status-check h2{display:flex;justify-content:space-between;font-size:1rem}
+.lv-status-check h2 span.lv-status-healthy{color:#16a34a}
+.lv-status-check h2 span.lv-status-unhealthy{color:#dc2626}
+.lv-status-bar{display:flex;gap:2px;height:2rem}
+.lv-status-day{flex:1;border-radius:2px;background:#cbd5e1}
+.lv-status-day.lv-status-healthy{background:#22c55e}
+.lv-status-day.lv-status-degraded{background:#f59e0b}
+.lv-status-day.lv-status-unhealthy{background:#ef4444}
+.lv-status-day.lv-status-annotated{box-shadow:inset 0 -4px 0 #1e293b}
+.lv-status-uptime,.lv-status-incident-time{font-size:0.875rem;color:#64748b}
+.lv-status-incident{border-left:4px solid #cbd5e1;padding-left:1rem;margin:1rem 0}
+.lv-status-incident.lv-status-degraded{border-color:#f59e0b}
+.lv-status-incident.lv-status-unhealthy{border-color:#ef4444}`
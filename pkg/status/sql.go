@@ -0,0 +1,206 @@
+package status
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/internal/sqlutil"
+	"github.com/gabrielmiguelok/golivekit/pkg/health"
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+)
+
+// SQLHistory keeps the history in two tables. Times are stored as Unix
+// milliseconds and incident IDs are assigned in Go, so the schema works on
+// SQLite, PostgreSQL and MySQL alike.
+//
+// Table names are interpolated into the queries and must come from trusted
+// configuration, never from user input.
+type SQLHistory struct {
+	DB             *sql.DB
+	SamplesTable   string
+	IncidentsTable string
+
+	// Dialect selects the bind parameters: $1, $2, ... for
+	// migrate.Postgres, "?" otherwise.
+	Dialect migrate.Dialect
+}
+
+// NewSQLHistory creates a history over the status_samples and
+// status_incidents tables.
+func NewSQLHistory(db *sql.DB) *SQLHistory {
+	return &SQLHistory{
+		DB:             db,
+		SamplesTable:   "status_samples",
+		IncidentsTable: "status_incidents",
+	}
+}
+
+func (h *SQLHistory) ph(n int) string {
+	if h.Dialect == migrate.Postgres {
+		return sqlutil.PostgresPlaceholder(n)
+	}
+	return "?"
+}
+
+// Schema returns the statements creating the tables.
+func (h *SQLHistory) Schema() []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	check_name VARCHAR(255) NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	checked_at BIGINT NOT NULL,
+	latency_ms BIGINT NOT NULL,
+	error TEXT
+)`, h.SamplesTable),
+		fmt.Sprintf(`CREATE INDEX %s_checked_at ON %s (checked_at)`, h.SamplesTable, h.SamplesTable),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGINT PRIMARY KEY,
+	title VARCHAR(255) NOT NULL,
+	body TEXT,
+	impact VARCHAR(16) NOT NULL,
+	started_at BIGINT NOT NULL,
+	resolved_at BIGINT NOT NULL DEFAULT 0
+)`, h.IncidentsTable),
+	}
+}
+
+// CreateTables runs Schema, ignoring the error of an index that already
+// exists. Use migrations instead in applications that have them.
+func (h *SQLHistory) CreateTables(ctx context.Context) error {
+	for i, stmt := range h.Schema() {
+		if _, err := h.DB.ExecContext(ctx, stmt); err != nil && i != 1 {
+			return fmt.Errorf("status: create tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// Record implements History.
+func (h *SQLHistory) Record(ctx context.Context, samples ...Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	stmt := fmt.Sprintf("INSERT INTO %s (check_name, status, checked_at, latency_ms, error) VALUES (%s, %s, %s, %s, %s)",
+		h.SamplesTable, h.ph(1), h.ph(2), h.ph(3), h.ph(4), h.ph(5))
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("status: record: %w", err)
+	}
+	defer tx.Rollback()
+	for _, s := range samples {
+		if _, err := tx.ExecContext(ctx, stmt, s.Check, string(s.Status), s.At.UnixMilli(), s.LatencyMS, s.Error); err != nil {
+			return fmt.Errorf("status: record: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("status: record: %w", err)
+	}
+	return nil
+}
+
+// Samples implements History.
+func (h *SQLHistory) Samples(ctx context.Context, since time.Time) ([]Sample, error) {
+	stmt := fmt.Sprintf("SELECT check_name, status, checked_at, latency_ms, error FROM %s WHERE checked_at >= %s ORDER BY checked_at",
+		h.SamplesTable, h.ph(1))
+	rows, err := h.DB.QueryContext(ctx, stmt, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("status: samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []Sample
+	for rows.Next() {
+		var (
+			s         Sample
+			status    string
+			checkedAt int64
+			errText   sql.NullString
+		)
+		if err := rows.Scan(&s.Check, &status, &checkedAt, &s.LatencyMS, &errText); err != nil {
+			return nil, fmt.Errorf("status: scan: %w", err)
+		}
+		s.Status, s.At, s.Error = health.Status(status), time.UnixMilli(checkedAt), errText.String
+		samples = append(samples, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("status: rows: %w", err)
+	}
+	return samples, nil
+}
+
+// Prune implements History.
+func (h *SQLHistory) Prune(ctx context.Context, before time.Time) error {
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE checked_at < %s", h.SamplesTable, h.ph(1))
+	if _, err := h.DB.ExecContext(ctx, stmt, before.UnixMilli()); err != nil {
+		return fmt.Errorf("status: prune: %w", err)
+	}
+	return nil
+}
+
+// AddIncident implements History. The ID is the start time in
+// nanoseconds.
+func (h *SQLHistory) AddIncident(ctx context.Context, inc *Incident) error {
+	inc.ID = inc.Started.UnixNano()
+	stmt := fmt.Sprintf("INSERT INTO %s (id, title, body, impact, started_at, resolved_at) VALUES (%s, %s, %s, %s, %s, %s)",
+		h.IncidentsTable, h.ph(1), h.ph(2), h.ph(3), h.ph(4), h.ph(5), h.ph(6))
+	if _, err := h.DB.ExecContext(ctx, stmt, inc.ID, inc.Title, inc.Body, string(inc.Impact), inc.Started.UnixMilli(), unixMilli(inc.Resolved)); err != nil {
+		return fmt.Errorf("status: add incident: %w", err)
+	}
+	return nil
+}
+
+// ResolveIncident implements History.
+func (h *SQLHistory) ResolveIncident(ctx context.Context, id int64, at time.Time) error {
+	stmt := fmt.Sprintf("UPDATE %s SET resolved_at = %s WHERE id = %s", h.IncidentsTable, h.ph(1), h.ph(2))
+	res, err := h.DB.ExecContext(ctx, stmt, at.UnixMilli(), id)
+	if err != nil {
+		return fmt.Errorf("status: resolve incident: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrIncidentNotFound
+	}
+	return nil
+}
+
+// Incidents implements History.
+func (h *SQLHistory) Incidents(ctx context.Context, since time.Time) ([]Incident, error) {
+	stmt := fmt.Sprintf("SELECT id, title, body, impact, started_at, resolved_at FROM %s WHERE resolved_at = 0 OR started_at >= %s ORDER BY started_at DESC",
+		h.IncidentsTable, h.ph(1))
+	rows, err := h.DB.QueryContext(ctx, stmt, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("status: incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		var (
+			inc                 Incident
+			body                sql.NullString
+			impact              string
+			started, resolvedAt int64
+		)
+		if err := rows.Scan(&inc.ID, &inc.Title, &body, &impact, &started, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("status: scan: %w", err)
+		}
+		inc.Body, inc.Impact, inc.Started = body.String, health.Status(impact), time.UnixMilli(started)
+		if resolvedAt != 0 {
+			inc.Resolved = time.UnixMilli(resolvedAt)
+		}
+		incidents = append(incidents, inc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("status: rows: %w", err)
+	}
+	return incidents, nil
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
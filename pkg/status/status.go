@@ -0,0 +1,180 @@
+// Package status provides a public status page. A Monitor runs the health
+// checks on an interval and keeps their results in a History (in memory or
+// in a SQL database); Page is a live view of the current status, the
+// uptime of each check over the last days and the incidents, refreshed as
+// new results come in.
+//
+// Usage:
+//
+//	checker := health.DefaultChecker(version)
+//	checker.AddCriticalCheck("database", health.DatabaseCheck(db.PingContext), 2*time.Second)
+//
+//	history := status.NewSQLHistory(db)
+//	history.CreateTables(ctx)
+//
+//	monitor := status.NewMonitor(checker, history, r.PubSub())
+//	go monitor.Run(ctx)
+//
+//	r.Live("/status", status.NewPage(monitor, "Acme Status"))
+package status
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/health"
+)
+
+// ErrIncidentNotFound is returned when resolving an unknown incident.
+var ErrIncidentNotFound = errors.New("status: incident not found")
+
+// Sample is the result of one check at one time.
+type Sample struct {
+	Check     string
+	Status    health.Status
+	At        time.Time
+	LatencyMS int64
+	Error     string
+}
+
+// Up reports whether the check passed.
+func (s Sample) Up() bool {
+	return s.Status != health.StatusUnhealthy
+}
+
+// Incident is an annotation on the status page: an outage or maintenance
+// with its impact, open until resolved.
+type Incident struct {
+	ID       int64
+	Title    string
+	Body     string
+	Impact   health.Status
+	Started  time.Time
+	Resolved time.Time // zero while open
+}
+
+// Open reports whether the incident is unresolved.
+func (i Incident) Open() bool {
+	return i.Resolved.IsZero()
+}
+
+// History stores samples and incidents. Implementations must be safe for
+// concurrent use.
+type History interface {
+	// Record stores samples.
+	Record(ctx context.Context, samples ...Sample) error
+
+	// Samples returns the samples taken at or after since, oldest first.
+	Samples(ctx context.Context, since time.Time) ([]Sample, error)
+
+	// Prune deletes the samples taken before before.
+	Prune(ctx context.Context, before time.Time) error
+
+	// AddIncident stores an incident, setting its ID.
+	AddIncident(ctx context.Context, inc *Incident) error
+
+	// ResolveIncident marks an incident resolved at at.
+	ResolveIncident(ctx context.Context, id int64, at time.Time) error
+
+	// Incidents returns the incidents open at or started after since,
+	// newest first.
+	Incidents(ctx context.Context, since time.Time) ([]Incident, error)
+}
+
+// Day is one day (UTC) of a check's history.
+type Day struct {
+	Date  time.Time
+	Total int
+	Down  int
+
+	// Incidents are the titles of the incidents open during the day.
+	Incidents []string
+}
+
+// Uptime returns the fraction of the day's samples that passed, or -1
+// without samples.
+func (d Day) Uptime() float64 {
+	if d.Total == 0 {
+		return -1
+	}
+	return float64(d.Total-d.Down) / float64(d.Total)
+}
+
+// Status summarizes the day: healthy without failures, unhealthy when the
+// check failed for at least half the samples, degraded in between, and
+// empty without samples.
+func (d Day) Status() health.Status {
+	switch {
+	case d.Total == 0:
+		return ""
+	case d.Down == 0:
+		return health.StatusHealthy
+	case d.Down*2 >= d.Total:
+		return health.StatusUnhealthy
+	default:
+		return health.StatusDegraded
+	}
+}
+
+// CheckUptime is the history of one check.
+type CheckUptime struct {
+	Name    string
+	Current health.Status
+	Uptime  float64 // over Days, -1 without samples
+	Days    []Day   // oldest first
+}
+
+// Summarize groups samples by check into the given number of days ending
+// with now's, and annotates the days with incidents. Checks are sorted by
+// name.
+func Summarize(samples []Sample, incidents []Incident, days int, now time.Time) []CheckUptime {
+	today := now.UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -(days - 1))
+
+	byCheck := make(map[string]*CheckUptime)
+	for _, s := range samples {
+		c, ok := byCheck[s.Check]
+		if !ok {
+			c = &CheckUptime{Name: s.Check, Days: make([]Day, days)}
+			for i := range c.Days {
+				c.Days[i].Date = first.AddDate(0, 0, i)
+			}
+			byCheck[s.Check] = c
+		}
+		c.Current = s.Status
+
+		i := int(s.At.UTC().Sub(first) / (24 * time.Hour))
+		if i < 0 || i >= days {
+			continue
+		}
+		c.Days[i].Total++
+		if !s.Up() {
+			c.Days[i].Down++
+		}
+	}
+
+	result := make([]CheckUptime, 0, len(byCheck))
+	for _, c := range byCheck {
+		total, down := 0, 0
+		for i := range c.Days {
+			d := &c.Days[i]
+			total += d.Total
+			down += d.Down
+			end := d.Date.Add(24 * time.Hour)
+			for _, inc := range incidents {
+				if inc.Started.Before(end) && (inc.Open() || !inc.Resolved.Before(d.Date)) {
+					d.Incidents = append(d.Incidents, inc.Title)
+				}
+			}
+		}
+		c.Uptime = -1
+		if total > 0 {
+			c.Uptime = float64(total-down) / float64(total)
+		}
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
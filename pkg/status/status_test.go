@@ -0,0 +1,139 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/health"
+	"github.com/gabrielmiguelok/golivekit/pkg/migrate"
+	"github.com/gabrielmiguelok/golivekit/pkg/pubsub"
+)
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	samples := []Sample{
+		{Check: "db", Status: health.StatusHealthy, At: yesterday},
+		{Check: "db", Status: health.StatusUnhealthy, At: yesterday.Add(time.Minute)},
+		{Check: "db", Status: health.StatusHealthy, At: yesterday.Add(2 * time.Minute)},
+		{Check: "db", Status: health.StatusHealthy, At: now},
+		{Check: "api", Status: health.StatusHealthy, At: now},
+		{Check: "api", Status: health.StatusHealthy, At: now.AddDate(0, 0, -30)}, // out of range
+	}
+	incidents := []Incident{{Title: "DB failover", Started: yesterday, Resolved: yesterday.Add(time.Hour)}}
+
+	checks := Summarize(samples, incidents, 3, now)
+	if len(checks) != 2 || checks[0].Name != "api" || checks[1].Name != "db" {
+		t.Fatalf("Expected api and db sorted, got %+v", checks)
+	}
+
+	db := checks[1]
+	if db.Current != health.StatusHealthy || db.Uptime != 0.75 {
+		t.Errorf("Expected current healthy with 75%% uptime, got %s %v", db.Current, db.Uptime)
+	}
+	if len(db.Days) != 3 || db.Days[0].Status() != "" || db.Days[1].Status() != health.StatusDegraded || db.Days[2].Status() != health.StatusHealthy {
+		t.Errorf("Unexpected days %+v", db.Days)
+	}
+	if len(db.Days[1].Incidents) != 1 || len(db.Days[2].Incidents) != 0 {
+		t.Errorf("Expected the incident on yesterday only, got %+v", db.Days)
+	}
+}
+
+func TestMemoryHistory(t *testing.T) {
+	ctx := context.Background()
+	h := NewMemoryHistory()
+	now := time.Now()
+
+	h.Record(ctx, Sample{Check: "db", At: now}, Sample{Check: "db", At: now.Add(-48 * time.Hour)})
+	h.Prune(ctx, now.Add(-24*time.Hour))
+	if samples, _ := h.Samples(ctx, time.Time{}); len(samples) != 1 {
+		t.Errorf("Expected prune to keep 1 sample, got %d", len(samples))
+	}
+
+	old := Incident{Title: "old", Started: now.Add(-72 * time.Hour), Resolved: now.Add(-71 * time.Hour)}
+	open := Incident{Title: "open", Started: now.Add(-96 * time.Hour)}
+	h.AddIncident(ctx, &old)
+	h.AddIncident(ctx, &open)
+
+	incidents, _ := h.Incidents(ctx, now.Add(-24*time.Hour))
+	if len(incidents) != 1 || incidents[0].Title != "open" {
+		t.Errorf("Expected only the open incident, got %+v", incidents)
+	}
+	if err := h.ResolveIncident(ctx, 99, now); !errors.Is(err, ErrIncidentNotFound) {
+		t.Errorf("Expected ErrIncidentNotFound, got %v", err)
+	}
+}
+
+func TestSQLHistory_Placeholders(t *testing.T) {
+	h := NewSQLHistory(nil)
+	h.Dialect = migrate.Postgres
+	if got := h.ph(2); got != "$2" {
+		t.Errorf("Expected $2, got %s", got)
+	}
+	for _, stmt := range h.Schema() {
+		if !strings.Contains(stmt, "status_") {
+			t.Errorf("Expected default table names in %q", stmt)
+		}
+	}
+}
+
+func TestMonitor_Page(t *testing.T) {
+	ctx := context.Background()
+	checker := health.NewChecker()
+	checker.AddCheck("cache", func(context.Context) error { return errors.New("timeout") }, time.Second)
+
+	ps := pubsub.NewMemoryPubSub()
+	defer ps.Close()
+	updates := make(chan struct{}, 4)
+	ps.Subscribe(Topic, func([]byte) { updates <- struct{}{} })
+
+	monitor := NewMonitor(checker, NewMemoryHistory(), ps)
+	if err := monitor.CheckNow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := monitor.OpenIncident(ctx, "Cache outage", "Investigating", health.StatusUnhealthy); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-updates:
+		case <-time.After(time.Second):
+			t.Fatal("Expected an update per check and incident")
+		}
+	}
+
+	page := NewPage(monitor, "Acme Status")().(*Page)
+	if err := page.Mount(ctx, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	page.Render(ctx).Render(ctx, &buf)
+	out := buf.String()
+	for _, want := range []string{"Acme Status", `lv-status-banner lv-status-unhealthy">Cache outage`, "cache", "0.00% uptime over 90 days", "Ongoing"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in page:\n%s", want, out)
+		}
+	}
+
+	// Broadcasts reload the report
+	monitor.ResolveIncident(ctx, openIncidentID(t, monitor))
+	page.HandleInfo(ctx, core.Broadcast{Topic: Topic})
+	buf.Reset()
+	page.Render(ctx).Render(ctx, &buf)
+	if strings.Contains(buf.String(), "Ongoing") {
+		t.Error("Expected the resolved incident to no longer be ongoing")
+	}
+}
+
+func openIncidentID(t *testing.T, m *Monitor) int64 {
+	incidents, _ := m.history.Incidents(context.Background(), time.Now())
+	if len(incidents) == 0 {
+		t.Fatal("Expected an open incident")
+	}
+	return incidents[0].ID
+}
@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/logging"
+	"github.com/gabrielmiguelok/golivekit/pkg/metrics"
+	"github.com/gabrielmiguelok/golivekit/pkg/plugin"
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
+)
+
+// MetricsSink counts events in metrics.GlobalMetrics
+// (golivekit_telemetry_events_total{event="..."}), scaled up for sampling.
+func MetricsSink() Sink {
+	return SinkFunc(func(ctx context.Context, e Event) {
+		n := int64(1)
+		if e.SampleRate > 0 && e.SampleRate < 1 {
+			n = int64(math.Round(1 / e.SampleRate))
+		}
+		metrics.RecordTelemetryEvent(e.Name, n)
+	})
+}
+
+// LogSink logs events at info level with the context's logger.
+func LogSink() Sink {
+	return SinkFunc(func(ctx context.Context, e Event) {
+		fields := make([]logging.Field, 0, len(e.Fields)+2)
+		if e.SocketID != "" {
+			fields = append(fields, logging.String("socket_id", e.SocketID))
+		}
+		if e.Component != "" {
+			fields = append(fields, logging.String("component", e.Component))
+		}
+		for k, v := range e.Fields {
+			fields = append(fields, logging.Any(k, v))
+		}
+		logging.L(ctx).Info(e.Name, fields...)
+	})
+}
+
+// TraceSink adds events to the context's span, if any.
+func TraceSink() Sink {
+	return SinkFunc(func(ctx context.Context, e Event) {
+		span := tracing.SpanFromContext(ctx)
+		if span == nil {
+			return
+		}
+		attrs := make(map[string]string, len(e.Fields))
+		for k, v := range e.Fields {
+			attrs[k] = fmt.Sprint(v)
+		}
+		span.AddEvent(e.Name, attrs)
+	})
+}
+
+// PluginSink runs the plugin.HookOnTelemetry hooks of registry, where
+// analytics plugins receive the events. The hook context's Metadata holds
+// "event" (the name), "fields" and "sample_rate".
+//
+//	telemetry.Default.AddSink(telemetry.PluginSink(app.Hooks()))
+func PluginSink(registry *plugin.HookRegistry) Sink {
+	return SinkFunc(func(ctx context.Context, e Event) {
+		if !registry.HasHooks(plugin.HookOnTelemetry) {
+			return
+		}
+		hc := plugin.NewHookContext(ctx)
+		hc.Metadata["event"] = e.Name
+		hc.Metadata["fields"] = map[string]any(e.Fields)
+		hc.Metadata["sample_rate"] = e.SampleRate
+		registry.Execute(plugin.HookOnTelemetry, hc)
+	})
+}
@@ -0,0 +1,140 @@
+// Package telemetry lets components report business events once and have
+// them reach every sink: metrics, logs, traces and analytics plugins.
+//
+//	telemetry.Emit(ctx, "checkout.step_completed", telemetry.Fields{
+//		"step": 2,
+//		"cart_total": c.Total,
+//	})
+//
+// The Default emitter counts events in metrics.GlobalMetrics, logs them
+// with the context's logger and adds them to the context's span. Other
+// sinks, such as an analytics plugin's hook, are added with AddSink.
+package telemetry
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Fields are the properties of an event.
+type Fields map[string]any
+
+// Event is an emitted event, enriched with the session it came from.
+type Event struct {
+	Name   string
+	Fields Fields
+	Time   time.Time
+
+	// SampleRate is the fraction of these events that are kept (1 when
+	// not sampled): each kept event stands for 1/SampleRate.
+	SampleRate float64
+
+	// SocketID and Component identify the session, when ctx is a
+	// component's context.
+	SocketID  string
+	Component string
+}
+
+// Sink receives the events that pass sampling. Sinks are called
+// synchronously and must not block.
+type Sink interface {
+	Handle(ctx context.Context, e Event)
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, e Event)
+
+// Handle calls f.
+func (f SinkFunc) Handle(ctx context.Context, e Event) {
+	f(ctx, e)
+}
+
+// Emitter fans events out to its sinks.
+type Emitter struct {
+	mu    sync.RWMutex
+	sinks []Sink
+	rates map[string]float64
+}
+
+// New creates an emitter without sinks.
+func New(sinks ...Sink) *Emitter {
+	return &Emitter{
+		sinks: sinks,
+		rates: make(map[string]float64),
+	}
+}
+
+// Default is the emitter used by Emit.
+var Default = New(MetricsSink(), LogSink(), TraceSink())
+
+// Emit reports an event through the Default emitter.
+func Emit(ctx context.Context, name string, fields Fields) {
+	Default.Emit(ctx, name, fields)
+}
+
+// AddSink adds a sink.
+func (e *Emitter) AddSink(s Sink) {
+	e.mu.Lock()
+	e.sinks = append(e.sinks, s)
+	e.mu.Unlock()
+}
+
+// SetSampleRate keeps only a fraction (0 to 1) of the events matching
+// pattern: an event name, a prefix ending in ".*" ("checkout.*"), or "*"
+// for every event. The most specific pattern applies.
+func (e *Emitter) SetSampleRate(pattern string, rate float64) {
+	rate = min(max(rate, 0), 1)
+	e.mu.Lock()
+	e.rates[pattern] = rate
+	e.mu.Unlock()
+}
+
+// sampleRate returns the rate of the most specific pattern matching name.
+func (e *Emitter) sampleRate(name string) float64 {
+	if rate, ok := e.rates[name]; ok {
+		return rate
+	}
+	for prefix := name; ; {
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+		if rate, ok := e.rates[prefix+".*"]; ok {
+			return rate
+		}
+	}
+	if rate, ok := e.rates["*"]; ok {
+		return rate
+	}
+	return 1
+}
+
+// Emit reports an event to every sink, unless sampling drops it.
+func (e *Emitter) Emit(ctx context.Context, name string, fields Fields) {
+	e.mu.RLock()
+	rate := e.sampleRate(name)
+	sinks := e.sinks
+	e.mu.RUnlock()
+
+	if rate < 1 && rand.Float64() >= rate {
+		return
+	}
+
+	event := Event{Name: name, Fields: fields, Time: time.Now(), SampleRate: rate}
+	if socket := core.SocketFromContext(ctx); socket != nil {
+		event.SocketID = socket.ID()
+	}
+	if comp := core.ComponentFromContext(ctx); comp != nil {
+		event.Component = comp.Name()
+	}
+
+	for _, s := range sinks {
+		s.Handle(ctx, event)
+	}
+}
@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/plugin"
+	"github.com/gabrielmiguelok/golivekit/pkg/tracing"
+)
+
+func collect(e *Emitter) *[]Event {
+	var got []Event
+	e.AddSink(SinkFunc(func(ctx context.Context, ev Event) { got = append(got, ev) }))
+	return &got
+}
+
+func TestEmit_Enriched(t *testing.T) {
+	e := New()
+	got := collect(e)
+
+	socket := core.NewSocket("sock-1", nil)
+	ctx := core.WithSocket(context.Background(), socket)
+	e.Emit(ctx, "checkout.step_completed", Fields{"step": 2})
+
+	if len(*got) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(*got))
+	}
+	ev := (*got)[0]
+	if ev.Name != "checkout.step_completed" || ev.Fields["step"] != 2 || ev.SocketID != "sock-1" || ev.SampleRate != 1 {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+}
+
+func TestEmit_Sampling(t *testing.T) {
+	e := New()
+	got := collect(e)
+	e.SetSampleRate("*", 0)
+	e.SetSampleRate("checkout.*", 1)
+	e.SetSampleRate("checkout.viewed", 0)
+
+	e.Emit(context.Background(), "search.typed", nil)
+	e.Emit(context.Background(), "checkout.paid", nil)
+	e.Emit(context.Background(), "checkout.viewed", nil)
+
+	if len(*got) != 1 || (*got)[0].Name != "checkout.paid" {
+		t.Errorf("Expected only checkout.paid, got %+v", *got)
+	}
+	if rate := e.sampleRate("checkout.cart.updated"); rate != 1 {
+		t.Errorf("Expected nested names to match checkout.*, got %v", rate)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	hooks := plugin.NewHookRegistry()
+	var analytics []string
+	hooks.Register(plugin.HookOnTelemetry, "analytics", func(hc *plugin.HookContext) error {
+		analytics = append(analytics, hc.Metadata["event"].(string))
+		return nil
+	})
+
+	e := New(TraceSink(), PluginSink(hooks))
+	ctx, span := tracing.NewTracer("test").StartSpan(context.Background(), "event")
+	e.Emit(ctx, "signup.completed", Fields{"plan": "pro"})
+	span.End()
+
+	if len(analytics) != 1 || analytics[0] != "signup.completed" {
+		t.Errorf("Expected the plugin hook to get the event, got %v", analytics)
+	}
+	if len(span.Events) != 1 || span.Events[0].Attrs["plan"] != "pro" {
+		t.Errorf("Expected a span event with the fields, got %+v", span.Events)
+	}
+}
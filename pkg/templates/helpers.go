@@ -0,0 +1,125 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/security"
+)
+
+// CSRF cookie and form field of security.DefaultCSRFConfig, read by
+// csrfToken and csrfField.
+const (
+	CSRFCookie = "_csrf"
+	CSRFField  = "_csrf"
+)
+
+// renderState is what the helpers of one template instance know about the
+// render in progress.
+type renderState struct {
+	ctx context.Context
+}
+
+// helpers returns the template functions:
+//
+//	lvClick "event" ["key" value ...]   lv-click="event" lv-value-key="value"
+//	lvSubmit "event" ["key" value ...]  lv-submit on a form
+//	lvChange "event" ["key" value ...]  lv-change on a form or input
+//	lvTarget "component-id"             lv-target, for LiveComponent events
+//	slot "id" value                     <span data-slot="id">value</span>
+//	csrfToken                           the session's CSRF token
+//	csrfField                           a hidden input with the token
+//
+// The lv* helpers go in a tag: <button {{lvClick "delete" "id" .ID}}>.
+// When the router signs values (Router.SetValueSigning), they also write
+// the signature of the values as lv-value-_sig.
+func helpers(state *renderState) template.FuncMap {
+	return template.FuncMap{
+		"lvClick":  eventAttr(state, "lv-click"),
+		"lvSubmit": eventAttr(state, "lv-submit"),
+		"lvChange": eventAttr(state, "lv-change"),
+		"lvTarget": func(id string) template.HTMLAttr {
+			return template.HTMLAttr(fmt.Sprintf(`lv-target="%s"`, html.EscapeString(id)))
+		},
+		"slot": slot,
+		"csrfToken": func() string {
+			return state.csrfToken()
+		},
+		"csrfField": func() template.HTML {
+			return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+				CSRFField, html.EscapeString(state.csrfToken())))
+		},
+	}
+}
+
+// eventAttr returns a helper writing attr and lv-value-* attributes from
+// key/value pairs.
+func eventAttr(state *renderState, attr string) func(event string, pairs ...any) (template.HTMLAttr, error) {
+	return func(event string, pairs ...any) (template.HTMLAttr, error) {
+		if len(pairs)%2 != 0 {
+			return "", fmt.Errorf("%s: odd number of value arguments", attr)
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, `%s="%s"`, attr, html.EscapeString(event))
+		values := make(map[string]string, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok || !validAttrName(key) || key == security.SignatureKey {
+				return "", fmt.Errorf("%s: invalid value name %v", attr, pairs[i])
+			}
+			values[key] = core.RawValue(pairs[i+1])
+			fmt.Fprintf(&sb, ` lv-value-%s="%s"`, key, html.EscapeString(values[key]))
+		}
+
+		if signer := state.valueSigner(); signer != nil && len(values) > 0 {
+			sig, err := signer.Sign(security.ValueScope(state.ctx), event, values)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", attr, err)
+			}
+			fmt.Fprintf(&sb, ` lv-value-%s="%s"`, security.SignatureKey, sig)
+		}
+		return template.HTMLAttr(sb.String()), nil
+	}
+}
+
+// slot wraps value in a data-slot element. template.HTML values are
+// written as is; others are escaped.
+func slot(id string, value any) template.HTML {
+	content, ok := value.(template.HTML)
+	if !ok {
+		content = template.HTML(html.EscapeString(core.RawValue(value)))
+	}
+	return template.HTML(fmt.Sprintf(`<span data-slot="%s">%s</span>`, html.EscapeString(id), content))
+}
+
+func (s *renderState) valueSigner() *security.ValueSigner {
+	if s.ctx == nil {
+		return nil
+	}
+	return security.ValueSignerFromContext(s.ctx)
+}
+
+func (s *renderState) csrfToken() string {
+	if s.ctx == nil {
+		return ""
+	}
+	return core.SessionFromContext(s.ctx).GetString("cookie:" + CSRFCookie)
+}
+
+// validAttrName reports whether name can follow lv-value- in an attribute
+// name without escaping.
+func validAttrName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+)
+
+// slotNamer hands out data-slot IDs, unique within a LiveTemplate.
+type slotNamer struct {
+	used map[string]int
+}
+
+func newSlotNamer() *slotNamer {
+	return &slotNamer{used: make(map[string]int)}
+}
+
+// name returns the ID for a field path: the path itself, then path-2,
+// path-3... when it is used again.
+func (n *slotNamer) name(field string) string {
+	n.used[field]++
+	if count := n.used[field]; count > 1 {
+		return fmt.Sprintf("%s-%d", field, count)
+	}
+	return field
+}
+
+// rawElements hold text that is not HTML, where a slot element cannot go.
+var rawElements = []string{"script", "style", "textarea", "title"}
+
+// wrapSlots wraps the field expressions ({{.Count}}, {{.User.Name}}) in
+// the text of template source in <span data-slot="Count">...</span>.
+// Only expressions at the top level of the template are wrapped: inside
+// {{range}} or {{define}} they would repeat the slot ID. Expressions
+// inside tags or raw text elements, and those already alone in a data-slot
+// element, are left as they are.
+func wrapSlots(source, text string, names *slotNamer) (string, error) {
+	tree := parse.New(source)
+	tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+	if _, err := tree.Parse(text, "", "", make(map[string]*parse.Tree)); err != nil {
+		return "", err
+	}
+	if tree.Root == nil {
+		return text, nil
+	}
+
+	var sb strings.Builder
+	var scan htmlScanner
+	last := 0
+	for _, node := range tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			scan.feed(string(n.Text))
+
+		case *parse.ActionNode:
+			field, ok := fieldPath(n)
+			if ok && scan.inText() {
+				start := strings.LastIndex(text[:n.Pos], "{{")
+				end := strings.Index(text[n.Pos:], "}}")
+				if start >= last && end >= 0 {
+					end += int(n.Pos) + 2
+					sb.WriteString(text[last:start])
+					fmt.Fprintf(&sb, `<span data-slot="%s">%s</span>`, names.name(field), text[start:end])
+					last = end
+				}
+			}
+			scan.output()
+
+		case *parse.CommentNode:
+
+		default:
+			scan.output()
+		}
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), nil
+}
+
+// fieldPath returns the path of an action printing a field of dot, such as
+// "User.Name" for {{.User.Name}}.
+func fieldPath(n *parse.ActionNode) (string, bool) {
+	if n.Pipe == nil || len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) != 1 {
+		return "", false
+	}
+	args := n.Pipe.Cmds[0].Args
+	if len(args) != 1 {
+		return "", false
+	}
+	field, ok := args[0].(*parse.FieldNode)
+	if !ok {
+		return "", false
+	}
+	return strings.Join(field.Ident, "."), true
+}
+
+// htmlScanner follows where the text of a template leaves off: inside a
+// tag, inside a raw text element, or in text right after a data-slot tag.
+type htmlScanner struct {
+	inTag    bool
+	quote    byte
+	tag      strings.Builder
+	raw      string // open raw text element
+	slotTag  bool   // the last tag has a data-slot attribute
+	afterTag bool   // only whitespace since the last tag
+}
+
+func (s *htmlScanner) feed(text string) {
+	for i := 0; i < len(text); i++ {
+		s.step(text, i)
+	}
+}
+
+// step scans the byte of text at i.
+func (s *htmlScanner) step(text string, i int) {
+	c := text[i]
+	switch {
+	case s.raw != "":
+		if strings.HasPrefix(strings.ToLower(text[i:]), "</"+s.raw) {
+			s.raw = ""
+			s.inTag = true
+			s.tag.Reset()
+		}
+	case s.inTag:
+		switch {
+		case s.quote != 0:
+			if c == s.quote {
+				s.quote = 0
+			}
+		case c == '"' || c == '\'':
+			s.quote = c
+		case c == '>':
+			s.closeTag()
+			return
+		}
+		s.tag.WriteByte(c)
+	case c == '<':
+		s.inTag = true
+		s.tag.Reset()
+	case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+		s.afterTag = false
+	}
+}
+
+// closeTag ends the tag being scanned.
+func (s *htmlScanner) closeTag() {
+	s.inTag = false
+	tag := s.tag.String()
+	s.slotTag = strings.Contains(tag, "data-slot")
+	s.afterTag = true
+	fields := strings.Fields(tag)
+	if len(fields) == 0 {
+		return
+	}
+	name := strings.ToLower(strings.TrimRight(fields[0], "/"))
+	for _, raw := range rawElements {
+		if name == raw {
+			s.raw = raw
+		}
+	}
+}
+
+// output records that a template expression wrote something at the
+// current position.
+func (s *htmlScanner) output() {
+	if !s.inTag && s.raw == "" {
+		s.afterTag = false
+	}
+}
+
+// inText reports whether an expression here is in HTML text that a slot
+// element can wrap.
+func (s *htmlScanner) inText() bool {
+	return !s.inTag && s.raw == "" && !(s.afterTag && s.slotTag)
+}
@@ -0,0 +1,304 @@
+// Package templates renders LiveView components with html/template, so
+// they can return a parsed template instead of building HTML strings:
+//
+//	var counterTmpl = templates.Must(templates.New("counter").Parse(`
+//	    <h1>Count: {{.Count}}</h1>
+//	    <button {{lvClick "inc" "by" 1}}>+1</button>`))
+//
+//	func (c *Counter) Render(ctx context.Context) core.Renderer {
+//	    return counterTmpl.Render(c)
+//	}
+//
+// Field expressions in text, such as {{.Count}} above, are wrapped in a
+// data-slot element named after the field, so a change to the field
+// reaches the client as a slot diff. Templates are parsed and escaped once
+// and reused by every render.
+package templates
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+)
+
+// LiveTemplate is an html/template with the lv-* helpers (see Funcs) and
+// automatic data-slot wrapping. It is safe for concurrent renders.
+type LiveTemplate struct {
+	name      string
+	base      *template.Template // parsed, never executed: cloned per instance
+	marked    *template.Template // base with diff markers (see markDynamics)
+	autoSlots bool
+	slots     *slotNamer
+
+	mu   sync.Mutex
+	free []*instance
+}
+
+// instance is an executable clone of the base templates whose
+// context-dependent helpers read state.
+type instance struct {
+	tmpl   *template.Template
+	marked *template.Template
+	state  *renderState
+}
+
+// New creates an empty template. Field expressions are wrapped in slots
+// unless AutoSlots(false) is called before parsing.
+func New(name string) *LiveTemplate {
+	return &LiveTemplate{
+		name:      name,
+		base:      template.New(name).Funcs(helpers(&renderState{})),
+		marked:    template.New(name).Funcs(helpers(&renderState{})),
+		autoSlots: true,
+		slots:     newSlotNamer(),
+	}
+}
+
+// Must panics if err is non-nil, for templates parsed at package level.
+func Must(t *LiveTemplate, err error) *LiveTemplate {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Name returns the name of the template.
+func (t *LiveTemplate) Name() string {
+	return t.name
+}
+
+// AutoSlots turns the data-slot wrapping of field expressions on or off.
+// It applies to templates parsed afterwards.
+func (t *LiveTemplate) AutoSlots(on bool) *LiveTemplate {
+	t.autoSlots = on
+	return t
+}
+
+// Funcs adds functions to the template. They must be added before parsing.
+func (t *LiveTemplate) Funcs(funcs template.FuncMap) *LiveTemplate {
+	t.base.Funcs(funcs)
+	t.marked.Funcs(funcs)
+	return t
+}
+
+// Parse parses text as the body of the template. It may define other
+// templates with {{define}}.
+func (t *LiveTemplate) Parse(text string) (*LiveTemplate, error) {
+	return t, t.parse(t.name, t.name, text)
+}
+
+// ParseFS parses the files of fsys matching patterns. Each file becomes a
+// template named after its base name; the one named like t is rendered,
+// the others are available to it with {{template}}.
+func (t *LiveTemplate) ParseFS(fsys fs.FS, patterns ...string) (*LiveTemplate, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("templates: pattern matches no files: %q", pattern)
+		}
+		files = append(files, matches...)
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+		if err := t.parse(path.Base(file), file, string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// parse parses text as the template name of both sets.
+func (t *LiveTemplate) parse(name, source, text string) error {
+	if t.autoSlots {
+		wrapped, err := wrapSlots(source, text, t.slots)
+		if err != nil {
+			return fmt.Errorf("templates: %w", err)
+		}
+		text = wrapped
+	}
+	if _, err := lookup(t.base, name).Parse(text); err != nil {
+		return fmt.Errorf("templates: %w", err)
+	}
+
+	// Without markers the template still renders, as slots of HTML
+	marked, err := markDynamics(source, text)
+	if err != nil {
+		marked = text
+	}
+	if _, err := lookup(t.marked, name).Parse(marked); err != nil {
+		return fmt.Errorf("templates: %w", err)
+	}
+	return nil
+}
+
+// lookup returns the template name of set, created if needed.
+func lookup(set *template.Template, name string) *template.Template {
+	if name == set.Name() {
+		return set
+	}
+	return set.New(name)
+}
+
+// Render returns a renderer executing the template with data, for a
+// component's Render method. It implements diff.TreeRenderer, so the
+// router sends changes to its slots as the dynamics that changed.
+func (t *LiveTemplate) Render(data any) core.Renderer {
+	return &renderer{t: t, data: data}
+}
+
+// renderer is a LiveTemplate bound to the data of one render.
+type renderer struct {
+	t    *LiveTemplate
+	data any
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer) error {
+	return r.t.Execute(ctx, w, r.data)
+}
+
+// RenderTrees writes the HTML like Render and returns the statics and
+// dynamics of its data-slot elements.
+func (r *renderer) RenderTrees(ctx context.Context, w io.Writer) (map[string]*diff.Rendered, error) {
+	inst, err := r.t.get()
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	inst.state.ctx = ctx
+	err = inst.marked.ExecuteTemplate(&sb, r.t.name, r.data)
+	inst.state.ctx = nil
+	r.t.put(inst)
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+
+	marked := sb.String()
+	if _, err := io.WriteString(w, diff.StripMarkers(marked)); err != nil {
+		return nil, err
+	}
+	return slotTrees(marked), nil
+}
+
+// Execute writes the template executed with data to w. Helpers such as
+// csrfToken read the session from ctx.
+func (t *LiveTemplate) Execute(ctx context.Context, w io.Writer, data any) error {
+	inst, err := t.get()
+	if err != nil {
+		return err
+	}
+	inst.state.ctx = ctx
+	err = inst.tmpl.ExecuteTemplate(w, t.name, data)
+	inst.state.ctx = nil
+	t.put(inst)
+	if err != nil {
+		return fmt.Errorf("templates: %w", err)
+	}
+	return nil
+}
+
+// get takes an idle instance, cloning the base template when all of them
+// are rendering. Each clone is escaped on its first execution only.
+func (t *LiveTemplate) get() (*instance, error) {
+	t.mu.Lock()
+	if n := len(t.free); n > 0 {
+		inst := t.free[n-1]
+		t.free = t.free[:n-1]
+		t.mu.Unlock()
+		return inst, nil
+	}
+	defer t.mu.Unlock()
+
+	clone, err := t.base.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	marked, err := t.marked.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	state := &renderState{}
+	clone.Funcs(helpers(state))
+	marked.Funcs(helpers(state))
+	return &instance{tmpl: clone, marked: marked, state: state}, nil
+}
+
+func (t *LiveTemplate) put(inst *instance) {
+	t.mu.Lock()
+	t.free = append(t.free, inst)
+	t.mu.Unlock()
+}
+
+// Cache parses the templates of a file system on first use, for apps
+// keeping one template file per view.
+type Cache struct {
+	fsys   fs.FS
+	shared []string
+	funcs  template.FuncMap
+
+	mu        sync.Mutex
+	templates map[string]*LiveTemplate
+}
+
+// NewCache creates a cache of the templates of fsys. Files matching the
+// shared patterns (layouts, partials) are parsed with each template.
+func NewCache(fsys fs.FS, shared ...string) *Cache {
+	return &Cache{
+		fsys:      fsys,
+		shared:    shared,
+		templates: make(map[string]*LiveTemplate),
+	}
+}
+
+// Funcs adds functions to the templates parsed afterwards.
+func (c *Cache) Funcs(funcs template.FuncMap) *Cache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.funcs == nil {
+		c.funcs = make(template.FuncMap)
+	}
+	for name, fn := range funcs {
+		c.funcs[name] = fn
+	}
+	return c
+}
+
+// Get returns the template of file, parsing it with the shared files the
+// first time.
+func (c *Cache) Get(file string) (*LiveTemplate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t, ok := c.templates[file]; ok {
+		return t, nil
+	}
+	t := New(path.Base(file))
+	if c.funcs != nil {
+		t.Funcs(c.funcs)
+	}
+	if _, err := t.ParseFS(c.fsys, append([]string{file}, c.shared...)...); err != nil {
+		return nil, err
+	}
+	c.templates[file] = t
+	return t, nil
+}
+
+// MustGet is like Get but panics if the template cannot be parsed.
+func (c *Cache) MustGet(file string) *LiveTemplate {
+	return Must(c.Get(file))
+}
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+)
+
+func render(t *testing.T, tmpl *LiveTemplate, ctx context.Context, data any) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := tmpl.Render(data).Render(ctx, &sb); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+func TestLiveTemplate_AutoSlots(t *testing.T) {
+	tmpl := Must(New("page").Parse(`<h1 title="{{.Title}}">{{.Title}}</h1>` +
+		`<p>{{.User.Name}} <b>{{.Title}}</b></p>` +
+		`<span data-slot="mine">{{.Count}}</span>` +
+		`<script>var n = {{.Count}};</script>` +
+		`{{range .Items}}<i>{{.}}</i>{{end}}`))
+
+	data := map[string]any{
+		"Title": "<Hi>",
+		"User":  map[string]string{"Name": "Ana"},
+		"Count": 3,
+		"Items": []string{"a"},
+	}
+	got := render(t, tmpl, context.Background(), data)
+	want := `<h1 title="&lt;Hi&gt;"><span data-slot="Title">&lt;Hi&gt;</span></h1>` +
+		`<p><span data-slot="User.Name">Ana</span> <b><span data-slot="Title-2">&lt;Hi&gt;</span></b></p>` +
+		`<span data-slot="mine">3</span>` +
+		`<script>var n =  3 ;</script>` +
+		`<i>a</i>`
+	if got != want {
+		t.Errorf("render =\n%s\nwant\n%s", got, want)
+	}
+
+	plain := Must(New("plain").AutoSlots(false).Parse(`<p>{{.}}</p><p>{{.Len}}</p>`))
+	if got := render(t, plain, context.Background(), struct{ Len int }{2}); strings.Contains(got, "data-slot") {
+		t.Errorf("AutoSlots(false) rendered %s", got)
+	}
+}
+
+func TestLiveTemplate_Helpers(t *testing.T) {
+	tmpl := Must(New("form").Parse(`<form {{lvSubmit "save"}}>{{csrfField}}` +
+		`<button {{lvClick "delete" "id" .ID "kind" "a\"b"}} {{lvTarget "row-1"}}>x</button>` +
+		`{{slot "total" .Total}}</form>`))
+
+	ctx := core.WithSession(context.Background(), core.Session{"cookie:_csrf": "tok"})
+	got := render(t, tmpl, ctx, map[string]any{"ID": 7, "Total": "<1>"})
+	want := `<form lv-submit="save"><input type="hidden" name="_csrf" value="tok">` +
+		`<button lv-click="delete" lv-value-id="7" lv-value-kind="a&#34;b" lv-target="row-1">x</button>` +
+		`<span data-slot="total">&lt;1&gt;</span></form>`
+	if got != want {
+		t.Errorf("render =\n%s\nwant\n%s", got, want)
+	}
+
+	bad := Must(New("bad").Parse(`<a {{lvClick "x" "id"}}>`))
+	if err := bad.Render(nil).Render(context.Background(), &strings.Builder{}); err == nil {
+		t.Error("odd value arguments accepted")
+	}
+}
+
+func TestLiveTemplate_ConcurrentRenders(t *testing.T) {
+	tmpl := Must(New("token").Parse(`{{csrfToken}}`))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			ctx := core.WithSession(context.Background(), core.Session{"cookie:_csrf": token})
+			for j := 0; j < 50; j++ {
+				if got := render(t, tmpl, ctx, nil); got != token {
+					t.Errorf("token %q rendered %q", token, got)
+					return
+				}
+			}
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+}
+
+func TestCache(t *testing.T) {
+	fsys := fstest.MapFS{
+		"views/counter.html":   {Data: []byte(`{{template "header" .}}<p>{{.Count}}</p>`)},
+		"partials/header.html": {Data: []byte(`{{define "header"}}<h1>{{.Title}}</h1>{{end}}`)},
+	}
+	cache := NewCache(fsys, "partials/*.html")
+
+	tmpl := cache.MustGet("views/counter.html")
+	if again := cache.MustGet("views/counter.html"); again != tmpl {
+		t.Error("template parsed twice")
+	}
+	got := render(t, tmpl, context.Background(), map[string]any{"Title": "C", "Count": 1})
+	if got != `<h1>C</h1><p><span data-slot="Count">1</span></p>` {
+		t.Errorf("render = %s", got)
+	}
+
+	if _, err := cache.Get("views/missing.html"); err == nil {
+		t.Error("missing file parsed")
+	}
+}
+
+func TestLiveTemplate_Trees(t *testing.T) {
+	tmpl := Must(New("list").Parse(`<h1>{{.Title}}</h1>` +
+		`<ul data-slot="items">{{range .Items}}<li class="{{.}}">{{.}}</li>{{end}}</ul>`))
+	renderTrees := func(items ...string) (string, map[string]*diff.Rendered) {
+		var sb strings.Builder
+		data := map[string]any{"Title": "Todo", "Items": items}
+		trees, err := tmpl.Render(data).(diff.TreeRenderer).RenderTrees(context.Background(), &sb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb.String(), trees
+	}
+
+	html, trees := renderTrees("a", "b")
+	if want := render(t, tmpl, context.Background(), map[string]any{"Title": "Todo", "Items": []string{"a", "b"}}); html != want {
+		t.Errorf("RenderTrees wrote %q, Render %q", html, want)
+	}
+	items := trees["items"]
+	if items == nil || items.HTML() != `<li class="a">a</li><li class="b">b</li>` {
+		t.Fatalf("items tree = %+v", items)
+	}
+	if _, ok := trees["Title"]; ok {
+		t.Error("a slot holding one expression should be sent as text")
+	}
+
+	// A tag holding an expression is one dynamic; only rows change
+	_, next := renderTrees("a", "c")
+	change, ok := diff.DiffRendered(items, next["items"])
+	rows, _ := change["0"].(map[string]any)
+	if !ok || rows == nil || rows["s"] != nil || rows["d"] == nil {
+		t.Errorf("change = %v, want the rows only", change)
+	}
+	if _, ok := diff.DiffRendered(next["items"], next["items"]); ok {
+		t.Error("an unchanged tree should have no change")
+	}
+}
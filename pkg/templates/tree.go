@@ -0,0 +1,219 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template/parse"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/diff"
+)
+
+// errUnclosedTag stops marking a list of nodes that ends inside a tag,
+// whose statics would depend on the data.
+var errUnclosedTag = errors.New("tag left open across template actions")
+
+// markDynamics returns template source whose output carries the diff
+// markers around its dynamic parts. Expressions in text are marked alone;
+// a tag or raw text element (<script>, <style>...) holding an expression
+// is marked whole, so markers never land where html/template escapes by
+// context. A {{range}} without {{else}} is marked as a list of rows.
+//
+// The source is rebuilt from the parse tree, so it is equivalent to text
+// rather than equal to it.
+func markDynamics(source, text string) (string, error) {
+	trees := make(map[string]*parse.Tree)
+	tree := parse.New(source)
+	tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+	if _, err := tree.Parse(text, "", "", trees); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if tree.Root != nil {
+		marked, err := markList(tree.Root.Nodes)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(marked)
+	}
+
+	// Templates defined in text are not marked: they run wherever
+	// {{template}} calls them, as part of one dynamic
+	names := make([]string, 0, len(trees))
+	for name := range trees {
+		if name != source {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "{{define %q}}%s{{end}}", name, trees[name].Root.String())
+	}
+	return sb.String(), nil
+}
+
+// markList marks the nodes of one list, which starts in HTML text.
+func markList(nodes []parse.Node) (string, error) {
+	var out []byte
+	var scan htmlScanner
+	regionStart := -1 // start of the tag or raw element being scanned
+	regionDynamic := false
+
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			text := string(n.Text)
+			for i := 0; i < len(text); i++ {
+				if !scan.inTag && scan.raw == "" && text[i] == '<' {
+					regionStart = len(out)
+					regionDynamic = false
+				}
+				scan.step(text, i)
+				out = append(out, text[i])
+				if !scan.inTag && scan.raw == "" && regionStart >= 0 {
+					if regionDynamic {
+						out = slices.Insert(out, regionStart, []byte(diff.MarkDynamicStart)...)
+						out = append(out, diff.MarkDynamicEnd...)
+					}
+					regionStart = -1
+				}
+			}
+
+		case *parse.CommentNode:
+			out = append(out, n.String()...)
+
+		default:
+			src := node.String()
+			switch {
+			case scan.inTag || scan.raw != "":
+				regionDynamic = true
+				out = append(out, src...)
+			case isRowLoop(node):
+				out = append(out, markLoop(node.(*parse.RangeNode))...)
+			default:
+				out = append(out, diff.MarkDynamicStart...)
+				out = append(out, src...)
+				out = append(out, diff.MarkDynamicEnd...)
+			}
+		}
+	}
+	if scan.inTag || scan.raw != "" {
+		return "", errUnclosedTag
+	}
+	return string(out), nil
+}
+
+// isRowLoop reports whether node is a {{range}} whose iterations can be
+// marked as rows: without {{else}}, {{break}} or {{continue}}, which would
+// leave rows unclosed.
+func isRowLoop(node parse.Node) bool {
+	n, ok := node.(*parse.RangeNode)
+	if !ok || n.ElseList != nil {
+		return false
+	}
+	body := n.List.String()
+	return !strings.Contains(body, "{{break}}") && !strings.Contains(body, "{{continue}}")
+}
+
+// markLoop marks a range as a list of rows. A body that cannot be marked
+// makes the whole range one dynamic.
+func markLoop(n *parse.RangeNode) string {
+	body, err := markList(n.List.Nodes)
+	if err != nil {
+		return diff.MarkDynamicStart + n.String() + diff.MarkDynamicEnd
+	}
+	return diff.MarkListStart + "{{range " + n.Pipe.String() + "}}" +
+		diff.MarkRowStart + body + diff.MarkRowEnd + "{{end}}" + diff.MarkListEnd
+}
+
+// slotTrees splits the top-level data-slot elements of a marked render
+// into statics and dynamics. Slots without dynamics, or whose markers do
+// not nest, are left out.
+func slotTrees(marked string) map[string]*diff.Rendered {
+	trees := make(map[string]*diff.Rendered)
+	pos := 0
+	for {
+		idx := strings.Index(marked[pos:], `data-slot="`)
+		if idx < 0 {
+			return trees
+		}
+		idx += pos
+		open := strings.LastIndexByte(marked[:idx], '<')
+		idStart := idx + len(`data-slot="`)
+		idEnd := strings.IndexByte(marked[idStart:], '"')
+		if open < 0 || idEnd < 0 {
+			return trees
+		}
+		id := marked[idStart : idStart+idEnd]
+		tagEnd := strings.IndexByte(marked[idStart+idEnd:], '>')
+		if tagEnd < 0 {
+			return trees
+		}
+		contentStart := idStart + idEnd + tagEnd + 1
+		name := tagName(marked[open+1:])
+
+		contentEnd, next := closingTag(marked, contentStart, name)
+		if contentEnd < 0 {
+			pos = contentStart
+			continue
+		}
+		if tree, err := diff.ParseMarked(marked[contentStart:contentEnd]); err == nil && worthSplitting(tree) {
+			trees[id] = tree
+		}
+		pos = next
+	}
+}
+
+// worthSplitting reports whether a slot is better sent as a tree than as
+// its content: a slot holding one expression and nothing else is not.
+func worthSplitting(tree *diff.Rendered) bool {
+	if len(tree.Dynamics) == 0 {
+		return false
+	}
+	if len(tree.Dynamics) > 1 {
+		return true
+	}
+	_, single := tree.Dynamics[0].(string)
+	return !single || strings.TrimSpace(tree.Statics[0]+tree.Statics[1]) != ""
+}
+
+// tagName returns the name of the tag starting s.
+func tagName(s string) string {
+	end := strings.IndexAny(s, " \t\n\r/>")
+	if end < 0 {
+		return s
+	}
+	return strings.ToLower(s[:end])
+}
+
+// closingTag finds the tag closing an element named name whose content
+// starts at from, counting nested elements of the same name. It returns
+// where the content ends and where the closing tag ends, or -1.
+func closingTag(s string, from int, name string) (end, next int) {
+	depth := 1
+	pos := from
+	for {
+		idx := strings.IndexByte(s[pos:], '<')
+		if idx < 0 {
+			return -1, -1
+		}
+		idx += pos
+		rest := s[idx+1:]
+		switch {
+		case strings.HasPrefix(rest, "/") && tagName(rest[1:]) == name:
+			depth--
+			if depth == 0 {
+				close := strings.IndexByte(s[idx:], '>')
+				if close < 0 {
+					return -1, -1
+				}
+				return idx, idx + close + 1
+			}
+		case tagName(rest) == name:
+			depth++
+		}
+		pos = idx + 1
+	}
+}
@@ -0,0 +1,87 @@
+package testing
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// DefaultAllocRuns is the number of runs AllocBudget averages over.
+const DefaultAllocRuns = 100
+
+// AllocBudget fails the test when fn allocates more than maxAllocs times per
+// call on average. Use it to lock in per-event allocation ceilings:
+//
+//	func TestCounter_AllocBudget(t *testing.T) {
+//	    c := NewCounter()
+//	    lvtesting.AllocBudget(t, lvtesting.EventFunc(c, "increment", nil), 12)
+//	}
+//
+// The race detector allocates on its own, so in -race builds fn only runs
+// once and the budget is not checked; run budgets in a non-race CI step.
+func AllocBudget(t testing.TB, fn func(), maxAllocs float64) {
+	t.Helper()
+	if raceEnabled {
+		fn()
+		t.Log("allocation budget not checked: race detector enabled")
+		return
+	}
+
+	allocs := testing.AllocsPerRun(DefaultAllocRuns, fn)
+	if allocs > maxAllocs {
+		t.Errorf("allocation budget exceeded: %.1f allocs/op, budget %.1f", allocs, maxAllocs)
+	}
+}
+
+// EventFunc returns a function that dispatches event to comp and renders it,
+// like the router does for each client event. Render errors panic.
+func EventFunc(comp core.Component, event string, payload map[string]any) func() {
+	ctx := context.Background()
+	return func() {
+		if err := comp.HandleEvent(ctx, event, payload); err != nil {
+			panic(err)
+		}
+		renderTo(ctx, comp, io.Discard)
+	}
+}
+
+// RenderFunc returns a function that renders comp. Render errors panic.
+func RenderFunc(comp core.Component) func() {
+	ctx := context.Background()
+	return func() {
+		renderTo(ctx, comp, io.Discard)
+	}
+}
+
+// BenchmarkEvent benchmarks handling event and re-rendering comp, reporting
+// allocations.
+func BenchmarkEvent(b *testing.B, comp core.Component, event string, payload map[string]any) {
+	b.Helper()
+	runBenchmark(b, EventFunc(comp, event, payload))
+}
+
+// BenchmarkRender benchmarks rendering comp, reporting allocations.
+func BenchmarkRender(b *testing.B, comp core.Component) {
+	b.Helper()
+	runBenchmark(b, RenderFunc(comp))
+}
+
+func runBenchmark(b *testing.B, fn func()) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn()
+	}
+}
+
+func renderTo(ctx context.Context, comp core.Component, w io.Writer) {
+	renderer := comp.Render(ctx)
+	if renderer == nil {
+		return
+	}
+	if err := renderer.Render(ctx, w); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,62 @@
+package testing
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+type counter struct {
+	core.BaseComponent
+	count int
+	out   []byte
+}
+
+func (c *counter) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+	c.count++
+	return nil
+}
+
+func (c *counter) Render(ctx context.Context) core.Renderer {
+	return core.RendererFunc(func(ctx context.Context, w io.Writer) error {
+		c.out = strconv.AppendInt(c.out[:0], int64(c.count), 10)
+		_, err := w.Write(c.out)
+		return err
+	})
+}
+
+var sink []byte
+
+// budgetTB records failures instead of failing the test.
+type budgetTB struct {
+	testing.TB
+	failed bool
+}
+
+func (tb *budgetTB) Helper()               {}
+func (tb *budgetTB) Log(args ...any)       {}
+func (tb *budgetTB) Errorf(string, ...any) { tb.failed = true }
+
+func TestAllocBudget(t *testing.T) {
+	c := &counter{out: make([]byte, 0, 16)}
+	AllocBudget(t, EventFunc(c, "inc", nil), 4)
+	if c.count == 0 {
+		t.Fatal("Expected the event to be dispatched")
+	}
+
+	if raceEnabled {
+		return
+	}
+	tb := &budgetTB{TB: t}
+	AllocBudget(tb, func() { sink = make([]byte, 64) }, 0)
+	if !tb.failed {
+		t.Error("Expected an allocating function to exceed a zero budget")
+	}
+}
+
+func BenchmarkCounterEvent(b *testing.B) {
+	BenchmarkEvent(b, &counter{}, "inc", nil)
+}
@@ -0,0 +1,35 @@
+package testing
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/seed"
+)
+
+// LoadFixtures inserts the JSON fixture files of fsys matching patterns
+// (default "*.json") into targets and returns the new ids by name. Errors
+// fail the test, so a test starts from known state in one line:
+//
+//	//go:embed testdata/*.json
+//	var fixtureFiles embed.FS
+//
+//	func TestPostList(t *testing.T) {
+//	    posts := forms.NewMemoryRepo()
+//	    lvtesting.LoadFixtures(t, fixtureFiles, map[string]seed.Target{
+//	        "posts": {Repo: posts, Changeset: models.PostChangeset},
+//	    }, "testdata/*.json")
+//	    ...
+//	}
+func LoadFixtures(t testing.TB, fsys fs.FS, targets map[string]seed.Target, patterns ...string) map[string][]string {
+	t.Helper()
+	fixtures, err := seed.ReadFixtures(fsys, patterns...)
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	ids, err := fixtures.Insert(targets)
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	return ids
+}
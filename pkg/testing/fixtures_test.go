@@ -0,0 +1,22 @@
+package testing
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/forms"
+	"github.com/gabrielmiguelok/golivekit/pkg/seed"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	files := fstest.MapFS{"testdata/posts.json": {Data: []byte(`{"posts": [{"title": "One"}, {"title": "Two"}]}`)}}
+	posts := forms.NewMemoryRepo()
+
+	ids := LoadFixtures(t, files, map[string]seed.Target{"posts": {Repo: posts}}, "testdata/*.json")
+	if len(ids["posts"]) != 2 {
+		t.Fatalf("Expected 2 ids, got %v", ids)
+	}
+	if post, _ := posts.Get(ids["posts"][1]); post["title"] != "Two" {
+		t.Errorf("Unexpected record %v", post)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package testing
+
+const raceEnabled = false
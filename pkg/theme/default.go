@@ -0,0 +1,94 @@
+package theme
+
+// Default returns the GoliveKit theme. Text colors meet WCAG 2.1 AA
+// (4.5:1) on the backgrounds of their mode.
+func Default() Theme {
+	return Theme{
+		Light: Tokens{
+			Colors: map[string]string{
+				// Backgrounds
+				"bg":      "#FFFFFF",
+				"bgAlt":   "#F8FAFC",
+				"bgHover": "#F1F5F9",
+				"bgCode":  "#F1F5F9",
+
+				// Text
+				"text":      "#0F172A", // 17.9:1 on bg
+				"textMuted": "#475569", // 7.6:1 on bg
+				"textDim":   "#64748B", // 4.8:1 on bg
+
+				// Brand
+				"primary":       "#6D28D9", // 7.1:1 on bg
+				"primaryBright": "#7C3AED",
+				"secondary":     "#0E7490", // 5.4:1 on bg
+				"accent":        "#155E75",
+
+				// Status
+				"success": "#047857",
+				"warning": "#B45309",
+				"danger":  "#B91C1C",
+				"info":    "#1D4ED8",
+
+				// Borders
+				"border":      "#E2E8F0",
+				"borderLight": "#CBD5E1",
+			},
+			// 8px grid
+			Spacing: map[string]string{
+				"xs":  "0.25rem",
+				"sm":  "0.5rem",
+				"md":  "1rem",
+				"lg":  "1.5rem",
+				"xl":  "2rem",
+				"2xl": "3rem",
+				"3xl": "4rem",
+				"4xl": "6rem",
+			},
+			// System font stacks: nothing to download
+			Fonts: map[string]string{
+				"sans": `system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif`,
+				"mono": `'SF Mono', SFMono-Regular, ui-monospace, 'DejaVu Sans Mono', Menlo, Consolas, monospace`,
+			},
+			FontSizes: map[string]string{
+				"xs":   "0.75rem",
+				"sm":   "0.875rem",
+				"base": "1rem",
+				"lg":   "1.125rem",
+				"xl":   "1.25rem",
+				"2xl":  "1.5rem",
+				"3xl":  "2rem",
+				"4xl":  "3rem",
+			},
+		},
+		Dark: Tokens{
+			Colors: map[string]string{
+				// Backgrounds
+				"bg":      "#0F172A",
+				"bgAlt":   "#1E293B",
+				"bgHover": "#334155",
+				"bgCode":  "#0D1117",
+
+				// Text
+				"text":      "#F8FAFC", // 15.5:1 on bg
+				"textMuted": "#CBD5E1", // 8.5:1 on bg, 6.5:1 on bgAlt
+				"textDim":   "#94A3B8", // 5.2:1 on bg, 4.5:1 on bgAlt
+
+				// Brand
+				"primary":       "#A78BFA", // 7:1 on bg
+				"primaryBright": "#C4B5FD",
+				"secondary":     "#22D3EE", // 8:1 on bg
+				"accent":        "#67E8F9", // 10:1 on bg
+
+				// Status
+				"success": "#34D399",
+				"warning": "#FBBF24",
+				"danger":  "#F87171",
+				"info":    "#60A5FA",
+
+				// Borders
+				"border":      "#334155",
+				"borderLight": "#475569",
+			},
+		},
+	}
+}
@@ -0,0 +1,179 @@
+// Package theme provides the design tokens (colors, spacing, typography)
+// shared by GoliveKit pages and components, published as CSS custom
+// properties:
+//
+//	--color-<name>   Tokens.Colors     (--color-primary, --color-bgAlt, ...)
+//	--space-<name>   Tokens.Spacing    (--space-sm, --space-md, ...)
+//	--font-<name>    Tokens.Fonts      (--font-sans, --font-mono)
+//	--text-<name>    Tokens.FontSizes  (--text-sm, --text-lg, ...)
+//
+// Component CSS refers to the variables, never to raw values, so apps
+// restyle every component by overriding tokens once:
+//
+//	theme.Override(theme.Tokens{Colors: map[string]string{"primary": "#0EA5E9"}})
+//
+// and putting theme.Tag() in the document head.
+package theme
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Tokens is a set of design tokens. Names are the CSS variable names
+// without their prefix.
+type Tokens struct {
+	Colors    map[string]string
+	Spacing   map[string]string
+	Fonts     map[string]string
+	FontSizes map[string]string
+}
+
+// With returns a copy of t with the tokens of o replacing or adding to its
+// own. Empty values in o are ignored.
+func (t Tokens) With(o Tokens) Tokens {
+	return Tokens{
+		Colors:    merge(t.Colors, o.Colors),
+		Spacing:   merge(t.Spacing, o.Spacing),
+		Fonts:     merge(t.Fonts, o.Fonts),
+		FontSizes: merge(t.FontSizes, o.FontSizes),
+	}
+}
+
+// Vars returns the tokens as CSS declarations ("--color-bg:#fff;..."),
+// sorted by name so the output is stable.
+func (t Tokens) Vars() string {
+	var sb strings.Builder
+	writeVars(&sb, "color", t.Colors)
+	writeVars(&sb, "space", t.Spacing)
+	writeVars(&sb, "font", t.Fonts)
+	writeVars(&sb, "text", t.FontSizes)
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+// CSS returns the tokens as a rule for selector.
+func (t Tokens) CSS(selector string) string {
+	return selector + "{" + t.Vars() + "}"
+}
+
+// changed returns the tokens of t that are missing from or different in
+// base.
+func (t Tokens) changed(base Tokens) Tokens {
+	return Tokens{
+		Colors:    diff(t.Colors, base.Colors),
+		Spacing:   diff(t.Spacing, base.Spacing),
+		Fonts:     diff(t.Fonts, base.Fonts),
+		FontSizes: diff(t.FontSizes, base.FontSizes),
+	}
+}
+
+func (t Tokens) clone() Tokens {
+	return t.With(Tokens{})
+}
+
+func merge(base, over map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(over))
+	maps.Copy(out, base)
+	for name, value := range over {
+		if value != "" {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+func diff(t, base map[string]string) map[string]string {
+	out := make(map[string]string)
+	for name, value := range t {
+		if base[name] != value {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+func writeVars(sb *strings.Builder, prefix string, vars map[string]string) {
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		fmt.Fprintf(sb, "--%s-%s:%s;", prefix, name, vars[name])
+	}
+}
+
+// Theme is a light and a dark set of tokens. Dark only needs the tokens
+// that differ from Light.
+type Theme struct {
+	Light Tokens
+	Dark  Tokens
+}
+
+// With returns a copy of t with o applied to both modes.
+func (t Theme) With(o Tokens) Theme {
+	return Theme{Light: t.Light.With(o), Dark: t.Dark.With(o)}
+}
+
+// CSS returns the theme's variables. The light tokens go on :root, and the
+// dark ones apply when the system prefers a dark scheme or the document
+// has data-theme="dark". data-theme="light" forces the light tokens.
+func (t Theme) CSS() string {
+	dark := t.Light.With(t.Dark).changed(t.Light).Vars()
+	if dark != "" {
+		dark += ";"
+	}
+	return ":root{color-scheme:light;" + t.Light.Vars() + "}" +
+		"@media(prefers-color-scheme:dark){:root:not([data-theme=light]){color-scheme:dark;" + dark + "}}" +
+		"[data-theme=dark]{color-scheme:dark;" + dark + "}"
+}
+
+// Resolve returns the full token set of a mode: the light tokens, with the
+// dark ones applied on top when dark is true.
+func (t Theme) Resolve(dark bool) Tokens {
+	if dark {
+		return t.Light.With(t.Dark)
+	}
+	return t.Light.clone()
+}
+
+func (t Theme) clone() Theme {
+	return Theme{Light: t.Light.clone(), Dark: t.Dark.clone()}
+}
+
+var (
+	mu      sync.RWMutex
+	current = Default()
+)
+
+// Current returns the theme in use: Default unless the app called Set or
+// Override. The returned maps are copies.
+func Current() Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.clone()
+}
+
+// Set replaces the theme in use. Apps call it at startup, before rendering.
+func Set(t Theme) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = t.clone()
+}
+
+// Override changes tokens of the theme in use in both modes, keeping the
+// others.
+func Override(o Tokens) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = current.With(o)
+}
+
+// CSS returns the variables of the theme in use.
+func CSS() string {
+	return Current().CSS()
+}
+
+// Tag returns the variables of the theme in use as a <style> element for
+// the document head.
+func Tag() string {
+	return `<style data-lv-theme>` + CSS() + `</style>`
+}
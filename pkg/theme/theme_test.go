@@ -0,0 +1,55 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTheme_CSS(t *testing.T) {
+	css := Default().CSS()
+
+	root, dark, ok := strings.Cut(css, "@media(prefers-color-scheme:dark)")
+	if !ok {
+		t.Fatalf("no dark scheme rule in %s", css)
+	}
+	for _, want := range []string{"--color-bg:#FFFFFF", "--space-md:1rem", "--font-mono:'SF Mono'", "--text-sm:0.875rem"} {
+		if !strings.Contains(root, want) {
+			t.Errorf("light tokens missing %q", want)
+		}
+	}
+	if !strings.Contains(dark, "--color-bg:#0F172A") || !strings.Contains(dark, "[data-theme=dark]{") {
+		t.Errorf("dark tokens = %s", dark)
+	}
+	// Dark only repeats what it changes
+	if strings.Contains(dark, "--space-") {
+		t.Errorf("dark rule repeats unchanged tokens: %s", dark)
+	}
+}
+
+func TestOverride_AppliesGlobally(t *testing.T) {
+	defer Set(Default())
+
+	Override(Tokens{
+		Colors:  map[string]string{"primary": "#0EA5E9"},
+		Spacing: map[string]string{"md": "1.25rem"},
+	})
+
+	got := Current()
+	for _, tokens := range []Tokens{got.Resolve(false), got.Resolve(true)} {
+		if tokens.Colors["primary"] != "#0EA5E9" || tokens.Spacing["md"] != "1.25rem" {
+			t.Errorf("override not applied: primary %s, md %s", tokens.Colors["primary"], tokens.Spacing["md"])
+		}
+		if tokens.Colors["danger"] == "" {
+			t.Error("override dropped the other tokens")
+		}
+	}
+	if !strings.Contains(Tag(), "--color-primary:#0EA5E9") {
+		t.Error("Tag does not use the theme in use")
+	}
+
+	// Callers cannot change the theme through the returned maps
+	got.Light.Colors["primary"] = "red"
+	if Current().Light.Colors["primary"] != "#0EA5E9" {
+		t.Error("Current returned the theme's own maps")
+	}
+}
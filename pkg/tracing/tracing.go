@@ -7,21 +7,33 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/redact"
 )
 
 // Tracer provides distributed tracing functionality.
 type Tracer struct {
 	serviceName string
 	spans       sync.Map
+	policy      *redact.Policy
 }
 
-// NewTracer creates a new tracer.
+// NewTracer creates a new tracer. Span tags and event attributes are
+// redacted with redact.Default.
 func NewTracer(serviceName string) *Tracer {
 	return &Tracer{
 		serviceName: serviceName,
+		policy:      redact.Default,
 	}
 }
 
+// SetRedaction sets the policy applied to tags and event attributes of new
+// spans. nil disables redaction.
+func (t *Tracer) SetRedaction(p *redact.Policy) *Tracer {
+	t.policy = p
+	return t
+}
+
 // StartSpan starts a new span.
 func (t *Tracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
 	config := &spanConfig{}
@@ -38,11 +50,12 @@ func (t *Tracer) StartSpan(ctx context.Context, name string, opts ...SpanOption)
 		StartTime: time.Now(),
 		Tags:      make(map[string]string),
 		Events:    make([]SpanEvent, 0),
+		policy:    t.policy,
 	}
 
 	// Add tags from config
 	for k, v := range config.tags {
-		span.Tags[k] = v
+		span.SetTag(k, v)
 	}
 
 	// Store span
@@ -70,6 +83,7 @@ type Span struct {
 	Tags      map[string]string
 	Events    []SpanEvent
 	mu        sync.Mutex
+	policy    *redact.Policy
 }
 
 // SpanStatus indicates span completion status.
@@ -101,11 +115,16 @@ func (s *Span) End() {
 	}
 }
 
-// SetTag sets a tag on the span.
+// SetTag sets a tag on the span. Sensitive tags are dropped or hashed
+// according to the tracer's redaction policy.
 func (s *Span) SetTag(key, value string) {
+	v, ok := s.policy.Value(key, value)
+	if !ok {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.Tags[key] = value
+	s.Tags[key] = v.(string)
 }
 
 // SetStatus sets the span status.
@@ -125,6 +144,8 @@ func (s *Span) SetError(err error) {
 
 // AddEvent adds an event to the span.
 func (s *Span) AddEvent(name string, attrs map[string]string) {
+	attrs = s.policy.Strings(attrs)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -157,6 +178,7 @@ func WithTag(key, value string) SpanOption {
 type traceIDKey struct{}
 type spanIDKey struct{}
 type spanKey struct{}
+type correlationIDKey struct{}
 
 // getTraceID extracts trace ID from context or generates a new one.
 func getTraceID(ctx context.Context) string {
@@ -174,6 +196,42 @@ func getSpanID(ctx context.Context) string {
 	return ""
 }
 
+// TraceID returns the trace ID of ctx, or "" outside a trace.
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// WithTraceID returns a copy of ctx in the trace id, so spans started from
+// it and exemplars recorded with it link to that trace. Work that outlives
+// a request, such as a live session, uses it to stay in the request's
+// trace.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of ctx: the ID of the request
+// or live event that caused the work, or "". Unlike the trace ID, it
+// follows the cause across pubsub broadcasts to other sessions.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithCorrelationID returns a copy of ctx with the correlation ID id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// NewCorrelationID returns a new correlation ID.
+func NewCorrelationID() string {
+	idMu.Lock()
+	idCounter++
+	id := idCounter
+	idMu.Unlock()
+	return fmt.Sprintf("corr-%d-%d", time.Now().UnixNano(), id)
+}
+
 // SpanFromContext retrieves the current span from context.
 func SpanFromContext(ctx context.Context) *Span {
 	span, _ := ctx.Value(spanKey{}).(*Span)
@@ -209,7 +267,7 @@ func TracingMiddleware(tracer *Tracer) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx, span := tracer.StartSpan(r.Context(), "http.request",
 				WithTag("http.method", r.Method),
-				WithTag("http.url", r.URL.String()),
+				WithTag("http.url", tracer.policy.URL(r.URL.String())),
 				WithTag("http.host", r.Host),
 			)
 			defer span.End()
@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBinaryFrame_RoundTrip(t *testing.T) {
+	data := []byte{0x00, 0xff, 0x10, 0x80}
+	msg := NewMessage("lv:1", "audio_chunk", map[string]any{"seq": float64(3)}).WithRef("7")
+	msg.Binary = data
+
+	frame, err := EncodeBinary(msg)
+	if err != nil {
+		t.Fatalf("EncodeBinary error: %v", err)
+	}
+
+	got, err := DecodeBinary(frame)
+	if err != nil {
+		t.Fatalf("DecodeBinary error: %v", err)
+	}
+	if got.Event != "audio_chunk" || got.Ref != "7" || got.Payload["seq"] != float64(3) {
+		t.Errorf("Header mismatch: %+v", got)
+	}
+	if !bytes.Equal(got.Binary, data) {
+		t.Errorf("Expected binary %v, got %v", data, got.Binary)
+	}
+}
+
+func TestBinaryFrame_Invalid(t *testing.T) {
+	frames := [][]byte{
+		nil,
+		{0x00, 0x00},
+		{0x00, 0x00, 0x00, 0x10, '{', '}'}, // header length exceeds frame
+		{0x00, 0x00, 0x00, 0x01, '{'},      // malformed JSON
+	}
+	for _, f := range frames {
+		if _, err := DecodeBinary(f); err == nil {
+			t.Errorf("Expected error for frame %v", f)
+		}
+	}
+}
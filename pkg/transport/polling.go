@@ -13,6 +13,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/secrets"
 )
 
 // LongPollingConfig configures long-polling security settings.
@@ -28,6 +30,10 @@ type LongPollingConfig struct {
 	// If empty, client IDs will not be signed (less secure).
 	HMACSecret []byte
 
+	// Keys, if set, replaces HMACSecret and allows rotating the signing key
+	// without invalidating connected clients.
+	Keys *secrets.Keyring
+
 	// ClientIDExpiry is how long signed client IDs are valid.
 	ClientIDExpiry time.Duration
 }
@@ -106,6 +112,13 @@ func (t *LongPollingTransport) generateSignedClientID() (string, error) {
 	payload := fmt.Sprintf("%s|%d", base64.URLEncoding.EncodeToString(randomBytes), timestamp)
 
 	// Sign the payload
+	if t.lpConfig != nil && t.lpConfig.Keys != nil {
+		sig, err := t.lpConfig.Keys.Sign([]byte(payload))
+		if err != nil {
+			return "", err
+		}
+		return payload + "." + base64.URLEncoding.EncodeToString(sig), nil
+	}
 	if t.lpConfig != nil && len(t.lpConfig.HMACSecret) > 0 {
 		mac := hmac.New(sha256.New, t.lpConfig.HMACSecret)
 		mac.Write([]byte(payload))
@@ -118,7 +131,7 @@ func (t *LongPollingTransport) generateSignedClientID() (string, error) {
 
 // verifyClientIDSignature verifies a signed client ID.
 func (t *LongPollingTransport) verifyClientIDSignature(clientID string) bool {
-	if t.lpConfig == nil || len(t.lpConfig.HMACSecret) == 0 {
+	if t.lpConfig == nil || (len(t.lpConfig.HMACSecret) == 0 && t.lpConfig.Keys == nil) {
 		return true // No signing configured
 	}
 
@@ -131,12 +144,19 @@ func (t *LongPollingTransport) verifyClientIDSignature(clientID string) bool {
 	providedSig := parts[1]
 
 	// Verify signature
-	mac := hmac.New(sha256.New, t.lpConfig.HMACSecret)
-	mac.Write([]byte(payload))
-	expectedSig := base64.URLEncoding.EncodeToString(mac.Sum(nil))
+	if t.lpConfig.Keys != nil {
+		sig, err := base64.URLEncoding.DecodeString(providedSig)
+		if err != nil || !t.lpConfig.Keys.Verify([]byte(payload), sig) {
+			return false
+		}
+	} else {
+		mac := hmac.New(sha256.New, t.lpConfig.HMACSecret)
+		mac.Write([]byte(payload))
+		expectedSig := base64.URLEncoding.EncodeToString(mac.Sum(nil))
 
-	if !hmac.Equal([]byte(providedSig), []byte(expectedSig)) {
-		return false
+		if !hmac.Equal([]byte(providedSig), []byte(expectedSig)) {
+			return false
+		}
 	}
 
 	// Check timestamp expiry
@@ -0,0 +1,328 @@
+package transport
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrSlowConsumer is returned by Send when the client could not keep up
+// and the transport was closed.
+var ErrSlowConsumer = errors.New("slow consumer disconnected")
+
+// DiffEvent is the event of render diffs, which a full send queue merges.
+const DiffEvent = "diff"
+
+// OverflowAction is what a transport does with a message that finds its
+// send queue full.
+type OverflowAction int
+
+const (
+	// OverflowCoalesce merges queued diffs, keeping the latest content of
+	// each slot, and disconnects when that frees no room.
+	OverflowCoalesce OverflowAction = iota
+
+	// OverflowDisconnect closes the connection. The client reconnects and
+	// rejoins, recovering its state when the router has recovery enabled.
+	OverflowDisconnect
+
+	// OverflowDrop discards the message.
+	OverflowDrop
+
+	// OverflowBlock waits up to WriteTimeout for room, blocking the sender.
+	OverflowBlock
+)
+
+// QueueOverflow describes a message that found the send queue full.
+type QueueOverflow struct {
+	// Depth is the number of messages waiting to be written.
+	Depth int
+
+	// Message is the message being sent.
+	Message Message
+}
+
+// sendQueue holds the messages waiting to be written to a client. Send
+// never blocks on it unless the overflow policy says so.
+type sendQueue struct {
+	mu       sync.Mutex
+	items    []Message
+	max      int
+	policy   func(QueueOverflow) OverflowAction
+	ready    chan struct{} // signaled when a message is queued
+	space    chan struct{} // signaled when a message is taken
+	timeout  time.Duration
+	closeCh  <-chan struct{}
+	shutdown func()
+}
+
+// newSendQueue creates the queue of a transport, closed by shutdown when
+// the policy disconnects.
+func newSendQueue(config *TransportConfig, closeCh <-chan struct{}, shutdown func()) *sendQueue {
+	max := config.MaxQueueDepth
+	if max <= 0 {
+		max = config.SendBufferSize
+	}
+	if max <= 0 {
+		max = DefaultTransportConfig().SendBufferSize
+	}
+	return &sendQueue{
+		max:      max,
+		policy:   config.OnOverflow,
+		ready:    make(chan struct{}, 1),
+		space:    make(chan struct{}, 1),
+		timeout:  config.WriteTimeout,
+		closeCh:  closeCh,
+		shutdown: shutdown,
+	}
+}
+
+// push queues msg, applying the overflow policy when the queue is full.
+// The policy is called without locks held.
+func (q *sendQueue) push(msg Message) error {
+	var deadline <-chan time.Time
+	for {
+		q.mu.Lock()
+		if len(q.items) < q.max {
+			q.items = append(q.items, msg)
+			q.mu.Unlock()
+			signal(q.ready)
+			return nil
+		}
+		depth := len(q.items)
+		q.mu.Unlock()
+
+		action := OverflowCoalesce
+		if q.policy != nil {
+			action = q.policy(QueueOverflow{Depth: depth, Message: msg})
+		}
+		switch action {
+		case OverflowDrop:
+			return nil
+
+		case OverflowBlock:
+			if deadline == nil {
+				deadline = time.After(q.timeout)
+			}
+			select {
+			case <-q.space:
+				continue
+			case <-q.closeCh:
+				return ErrConnectionClosed
+			case <-deadline:
+				return ErrSendTimeout
+			}
+
+		case OverflowCoalesce:
+			q.mu.Lock()
+			q.items = coalesce(append(q.items, msg))
+			if len(q.items) <= q.max {
+				q.mu.Unlock()
+				signal(q.ready)
+				return nil
+			}
+			// Nothing merged: msg is still last
+			q.items[len(q.items)-1] = Message{}
+			q.items = q.items[:len(q.items)-1]
+			q.mu.Unlock()
+		}
+
+		// Disconnect, or coalescing freed no room
+		go q.shutdown()
+		return ErrSlowConsumer
+	}
+}
+
+// pop takes the oldest message.
+func (q *sendQueue) pop() (Message, bool) {
+	q.mu.Lock()
+	if len(q.items) == 0 {
+		q.mu.Unlock()
+		return Message{}, false
+	}
+	msg := q.items[0]
+	q.items[0] = Message{}
+	q.items = q.items[1:]
+	q.mu.Unlock()
+	signal(q.space)
+	return msg, true
+}
+
+// depth returns the number of queued messages.
+func (q *sendQueue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// coalesce merges each run of consecutive diffs of a topic into one diff.
+// Only adjacent diffs are merged, so messages keep their order.
+func coalesce(items []Message) []Message {
+	out := items[:0]
+	for _, msg := range items {
+		if n := len(out); n > 0 {
+			last := &out[n-1]
+			if last.Event == DiffEvent && msg.Event == DiffEvent && last.Topic == msg.Topic && len(last.Binary) == 0 && len(msg.Binary) == 0 {
+				if merged, ok := mergeDiffs(last.Payload, msg.Payload); ok {
+					last.Payload = merged
+					last.Ref = msg.Ref
+					last.Timestamp = msg.Timestamp
+					continue
+				}
+			}
+		}
+		out = append(out, msg)
+	}
+	for i := len(out); i < len(items); i++ {
+		items[i] = Message{}
+	}
+	return out
+}
+
+// mergeDiffs merges two diff payloads ({v, s, h, l, f}) into one with the
+// effect of applying older then newer. It refuses payloads it cannot merge
+// exactly: a full render followed by slots, HTML slots followed by other
+// text slots (which they may contain), list operations followed by HTML
+// slots (which may replace the list), slot trees (t), whose changes apply
+// to the client's copy, and unknown keys.
+func mergeDiffs(older, newer map[string]any) (map[string]any, bool) {
+	trees := false
+	for _, p := range []map[string]any{older, newer} {
+		for key, value := range p {
+			switch key {
+			case "v", "s", "h", "l", "f":
+			case "t":
+				t, ok := value.(map[string]any)
+				if !ok && value != nil {
+					return nil, false
+				}
+				trees = trees || len(t) > 0
+			default:
+				return nil, false
+			}
+		}
+	}
+	if full, _ := newer["f"].(string); full != "" {
+		return newer, true
+	}
+	if full, _ := older["f"].(string); full != "" || trees {
+		return nil, false
+	}
+
+	olderText, ok1 := slotMap(older["s"])
+	olderHTML, ok2 := slotMap(older["h"])
+	newerText, ok3 := slotMap(newer["s"])
+	newerHTML, ok4 := slotMap(newer["h"])
+	olderLists, ok5 := listMap(older["l"])
+	newerLists, ok6 := listMap(newer["l"])
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+		return nil, false
+	}
+	if len(olderLists) > 0 && len(newerHTML) > 0 {
+		return nil, false
+	}
+	// Text slots are applied first: one nested in an older HTML slot
+	// would be overwritten by it
+	for id := range newerText {
+		if _, same := olderHTML[id]; len(olderHTML) > 0 && !same {
+			return nil, false
+		}
+	}
+
+	// The client applies text slots before HTML ones: a slot keeps only
+	// its latest content, in the map of its latest kind
+	text := make(map[string]any, len(olderText)+len(newerText))
+	html := make(map[string]any, len(olderHTML)+len(newerHTML))
+	for id, content := range olderText {
+		text[id] = content
+	}
+	for id, content := range olderHTML {
+		html[id] = content
+	}
+	for id, content := range newerText {
+		text[id] = content
+		delete(html, id)
+	}
+	for id, content := range newerHTML {
+		html[id] = content
+		delete(text, id)
+	}
+
+	// List operations run in order
+	lists := make(map[string]any, len(olderLists)+len(newerLists))
+	for id, ops := range olderLists {
+		lists[id] = ops.Interface()
+	}
+	for id, ops := range newerLists {
+		if prev, ok := olderLists[id]; ok {
+			if prev.Type() != ops.Type() {
+				return nil, false
+			}
+			ops = reflect.AppendSlice(reflect.AppendSlice(reflect.MakeSlice(prev.Type(), 0, prev.Len()+ops.Len()), prev), ops)
+		}
+		lists[id] = ops.Interface()
+	}
+
+	merged := map[string]any{"v": newer["v"]}
+	if len(text) > 0 {
+		merged["s"] = text
+	}
+	if len(html) > 0 {
+		merged["h"] = html
+	}
+	if len(lists) > 0 {
+		merged["l"] = lists
+	}
+	return merged, true
+}
+
+// slotMap reads a slot map, typed (map[string]string) or decoded from JSON.
+func slotMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case nil:
+		return nil, true
+	case map[string]string:
+		out := make(map[string]any, len(m))
+		for id, content := range m {
+			out[id] = content
+		}
+		return out, true
+	case map[string]any:
+		return m, true
+	}
+	return nil, false
+}
+
+// listMap reads a map of list operations, whatever the type of the
+// operations, as slices of a type the same list's operations can be
+// appended to.
+func listMap(v any) (map[string]reflect.Value, bool) {
+	if v == nil {
+		return nil, true
+	}
+	m := reflect.ValueOf(v)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	out := make(map[string]reflect.Value, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		ops := iter.Value()
+		if ops.Kind() == reflect.Interface {
+			ops = ops.Elem()
+		}
+		if ops.Kind() != reflect.Slice {
+			return nil, false
+		}
+		out[iter.Key().String()] = ops
+	}
+	return out, true
+}
@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func diffMessage(v int, text, html map[string]string, lists map[string][]string) Message {
+	return NewMessage("lv:t", DiffEvent, map[string]any{"v": v, "s": text, "h": html, "l": lists, "f": ""})
+}
+
+func TestSendQueue_CoalescesDiffs(t *testing.T) {
+	closed := make(chan struct{})
+	q := newSendQueue(&TransportConfig{MaxQueueDepth: 3}, closed, func() { t.Error("disconnected") })
+
+	q.push(NewMessage("lv:t", "phx_reply", map[string]any{"status": "ok"}))
+	q.push(diffMessage(1, map[string]string{"a": "1", "b": "1"}, nil, map[string][]string{"rows": {"insert 1"}}))
+	q.push(diffMessage(2, map[string]string{"a": "2"}, nil, map[string][]string{"rows": {"delete 0"}}))
+	// HTML after list operations is not merged with them
+	q.push(diffMessage(3, nil, map[string]string{"b": "<i>3</i>"}, nil))
+	if err := q.push(diffMessage(4, map[string]string{"b": "4"}, nil, nil)); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	if q.depth() != 3 {
+		t.Fatalf("depth = %d, want 3", q.depth())
+	}
+	if reply, _ := q.pop(); reply.Event != "phx_reply" {
+		t.Fatalf("first message = %s, want the reply", reply.Event)
+	}
+	merged, _ := q.pop()
+	want := map[string]any{
+		"v": 2,
+		"s": map[string]any{"a": "2", "b": "1"},
+		"l": map[string]any{"rows": []string{"insert 1", "delete 0"}},
+	}
+	if !reflect.DeepEqual(merged.Payload, want) {
+		t.Errorf("merged diff = %v, want %v", merged.Payload, want)
+	}
+	last, _ := q.pop()
+	want = map[string]any{"v": 4, "s": map[string]any{"b": "4"}}
+	if !reflect.DeepEqual(last.Payload, want) {
+		t.Errorf("last diff = %v, want %v", last.Payload, want)
+	}
+}
+
+func TestMergeDiffs_Refuses(t *testing.T) {
+	tests := []struct {
+		name         string
+		older, newer map[string]any
+	}{
+		{"slots after a full render", map[string]any{"f": "<div></div>"}, map[string]any{"s": map[string]string{"a": "1"}}},
+		{"text slot outside older HTML", map[string]any{"h": map[string]string{"a": "<b>1</b>"}}, map[string]any{"s": map[string]string{"b": "2"}}},
+		{"HTML after list operations", map[string]any{"l": map[string][]string{"rows": {"x"}}}, map[string]any{"h": map[string]string{"a": ""}}},
+		{"unknown key", map[string]any{"v": 1, "x": true}, map[string]any{"v": 2}},
+		{"slot trees", map[string]any{"t": map[string]any{"rows": map[string]any{"0": "1"}}}, map[string]any{"s": map[string]string{"a": "2"}}},
+	}
+	for _, tt := range tests {
+		if _, ok := mergeDiffs(tt.older, tt.newer); ok {
+			t.Errorf("%s: merged", tt.name)
+		}
+	}
+
+	// A full render replaces whatever came before it
+	full := map[string]any{"v": 3, "f": "<div></div>"}
+	if merged, ok := mergeDiffs(map[string]any{"h": map[string]string{"a": "x"}}, full); !ok || !reflect.DeepEqual(merged, full) {
+		t.Errorf("full render merged into %v", merged)
+	}
+}
+
+func TestSendQueue_Policies(t *testing.T) {
+	closed := make(chan struct{})
+	shutdown := make(chan struct{}, 1)
+	var action OverflowAction
+	var seen QueueOverflow
+	q := newSendQueue(&TransportConfig{MaxQueueDepth: 1, WriteTimeout: 20 * time.Millisecond, OnOverflow: func(o QueueOverflow) OverflowAction {
+		seen = o
+		return action
+	}}, closed, func() { shutdown <- struct{}{} })
+	q.push(NewMessage("lv:t", "first", nil))
+
+	action = OverflowDrop
+	if err := q.push(NewMessage("lv:t", "dropped", nil)); err != nil || q.depth() != 1 {
+		t.Errorf("drop: err %v, depth %d", err, q.depth())
+	}
+	if seen.Depth != 1 || seen.Message.Event != "dropped" {
+		t.Errorf("overflow = %+v", seen)
+	}
+
+	action = OverflowBlock
+	if err := q.push(NewMessage("lv:t", "blocked", nil)); !errors.Is(err, ErrSendTimeout) {
+		t.Errorf("block without a reader: %v", err)
+	}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		q.pop()
+	}()
+	if err := q.push(NewMessage("lv:t", "unblocked", nil)); err != nil {
+		t.Errorf("block with a reader: %v", err)
+	}
+
+	// Messages other than diffs cannot be coalesced
+	action = OverflowCoalesce
+	if err := q.push(NewMessage("lv:t", "reply", nil)); !errors.Is(err, ErrSlowConsumer) {
+		t.Errorf("coalesce: %v", err)
+	}
+	select {
+	case <-shutdown:
+	case <-time.After(time.Second):
+		t.Fatal("slow consumer not disconnected")
+	}
+	if msg, _ := q.pop(); msg.Event != "unblocked" || q.depth() != 0 {
+		t.Errorf("queue holds %s and %d more", msg.Event, q.depth())
+	}
+}
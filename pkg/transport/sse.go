@@ -3,6 +3,7 @@ package transport
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,6 +11,17 @@ import (
 	"time"
 )
 
+// sseKeepAlive is the heartbeat interval when PingInterval is zero: proxies
+// close streams that stay idle for long.
+const sseKeepAlive = 25 * time.Second
+
+// sseFrame is a Message on the stream and in POST bodies, one JSON object
+// per line. Binary attachments travel base64-encoded in bin.
+type sseFrame struct {
+	Message
+	Bin []byte `json:"bin,omitempty"`
+}
+
 // SSEConfig configures SSE security settings.
 type SSEConfig struct {
 	// AllowedOrigins is a list of allowed origins for CORS.
@@ -32,6 +44,7 @@ func DefaultSSEConfig() *SSEConfig {
 // This is a fallback for environments where WebSocket is not available.
 type SSETransport struct {
 	*BaseTransport
+	queue     *sendQueue
 	writer    http.ResponseWriter
 	flusher   http.Flusher
 	postURL   string
@@ -39,18 +52,21 @@ type SSETransport struct {
 	client    *http.Client
 	eventID   int64
 	sseConfig *SSEConfig
+	notify    func()
 	mu        sync.Mutex
 }
 
 // NewSSETransport creates a new SSE transport.
 func NewSSETransport(config *TransportConfig) *SSETransport {
-	return &SSETransport{
+	t := &SSETransport{
 		BaseTransport: NewBaseTransport(config),
 		client: &http.Client{
 			Timeout: config.WriteTimeout,
 		},
 		sseConfig: DefaultSSEConfig(),
 	}
+	t.queue = newSendQueue(t.config, t.closeCh, func() { t.Close() })
+	return t
 }
 
 // NewSSETransportWithConfig creates an SSE transport with security config.
@@ -58,13 +74,15 @@ func NewSSETransportWithConfig(config *TransportConfig, sseConfig *SSEConfig) *S
 	if sseConfig == nil {
 		sseConfig = DefaultSSEConfig()
 	}
-	return &SSETransport{
+	t := &SSETransport{
 		BaseTransport: NewBaseTransport(config),
 		client: &http.Client{
 			Timeout: config.WriteTimeout,
 		},
 		sseConfig: sseConfig,
 	}
+	t.queue = newSendQueue(t.config, t.closeCh, func() { t.Close() })
+	return t
 }
 
 // SetSSEConfig updates the SSE security configuration.
@@ -118,6 +136,7 @@ func (t *SSETransport) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: stream, don't buffer
 
 	// SECURITY FIX: Only set CORS headers if origin is explicitly allowed
 	// Never use wildcard "*" by default
@@ -144,46 +163,92 @@ func (t *SSETransport) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
 	// Start write loop
 	go t.writeLoop()
 
-	// Keep connection open until closed
-	<-r.Context().Done()
+	// Keep connection open until either side closes it
+	select {
+	case <-r.Context().Done():
+	case <-t.closeCh:
+	}
 
 	t.Close()
 	return nil
 }
 
-// Send queues a message to be sent.
+// Send queues a message to be sent. A full queue is handled by
+// OnOverflow, as on a WebSocket.
 func (t *SSETransport) Send(msg Message) error {
 	if !t.IsConnected() {
 		return ErrNotConnected
 	}
+	return t.queue.push(msg)
+}
 
-	select {
-	case t.sendCh <- msg:
-		return nil
-	case <-t.closeCh:
-		return ErrConnectionClosed
-	default:
-		return ErrTransportFull
+// QueueDepth returns the number of messages waiting to be written.
+func (t *SSETransport) QueueDepth() int {
+	return t.queue.depth() + len(t.sendCh)
+}
+
+// SendBulk queues a message. The stream carries one queue, so bulk
+// messages are not deferred as on a WebSocket.
+func (t *SSETransport) SendBulk(msg Message) error {
+	return t.Send(msg)
+}
+
+// SetNotify sets a function called when a POSTed message arrives or the
+// connection closes (shared event loops).
+func (t *SSETransport) SetNotify(fn func()) {
+	t.mu.Lock()
+	t.notify = fn
+	t.mu.Unlock()
+}
+
+func (t *SSETransport) notifyReady() {
+	t.mu.Lock()
+	fn := t.notify
+	t.mu.Unlock()
+	if fn != nil {
+		fn()
 	}
 }
 
 // Close closes the SSE connection.
 func (t *SSETransport) Close() error {
+	defer t.notifyReady()
 	return t.BaseTransport.Close()
 }
 
 // writeLoop sends messages as SSE events.
 func (t *SSETransport) writeLoop() {
-	ticker := time.NewTicker(t.config.PingInterval)
+	interval := t.config.PingInterval
+	if interval <= 0 {
+		interval = sseKeepAlive
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
+		if msg, ok := t.queue.pop(); ok {
+			if t.writeMessage(msg) != nil {
+				t.Close()
+				return
+			}
+			continue
+		}
+
 		select {
+		case <-t.queue.ready:
+
 		case msg := <-t.sendCh:
-			t.sendEvent(msg.Event, msg.Payload)
+			if t.writeMessage(msg) != nil {
+				t.Close()
+				return
+			}
 
 		case <-ticker.C:
-			// Send heartbeat
+			// Clients POST heartbeats: one silent for too long is gone
+			if t.silent() {
+				t.Close()
+				return
+			}
 			t.sendHeartbeat()
 
 		case <-t.closeCh:
@@ -192,6 +257,28 @@ func (t *SSETransport) writeLoop() {
 	}
 }
 
+// writeMessage sends a message as an unnamed event ("message" in the
+// browser's EventSource) whose data is the JSON frame.
+func (t *SSETransport) writeMessage(msg Message) error {
+	data, err := json.Marshal(sseFrame{Message: msg, Bin: msg.Binary})
+	if err != nil {
+		return nil // skip messages that cannot be encoded
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.writer == nil {
+		return ErrNotConnected
+	}
+	n, err := fmt.Fprintf(t.writer, "data: %s\n\n", data)
+	if err != nil {
+		return err
+	}
+	t.flusher.Flush()
+	t.bytesSent.Add(int64(n))
+	return nil
+}
+
 // sendEvent sends an SSE event.
 func (t *SSETransport) sendEvent(event string, data any) error {
 	t.mu.Lock()
@@ -235,28 +322,36 @@ func (t *SSETransport) sendHeartbeat() {
 }
 
 // ReceiveFromPost processes incoming messages from POST requests.
-// SSE is unidirectional, so clients send messages via POST.
+// SSE is unidirectional, so clients send messages via POST, one JSON
+// message per line.
 func (t *SSETransport) ReceiveFromPost(r *http.Request) error {
 	scanner := bufio.NewScanner(r.Body)
 	defer r.Body.Close()
+	if max := int(t.config.MaxMessageSize); max > 0 {
+		scanner.Buffer(make([]byte, 0, 4096), max*2) // base64 attachments
+	}
 
 	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+		line := scanner.Bytes()
+		if len(line) == 0 {
 			continue
 		}
 
-		msg, err := Unmarshal([]byte(line))
-		if err != nil {
+		var frame sseFrame
+		if err := json.Unmarshal(line, &frame); err != nil {
 			continue
 		}
+		msg := frame.Message
+		msg.Binary = frame.Bin
+		t.touch()
 
 		select {
 		case t.recvCh <- msg:
+			t.notifyReady()
 		case <-t.closeCh:
 			return ErrConnectionClosed
-		default:
-			// Channel full
+		case <-r.Context().Done():
+			return r.Context().Err()
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -41,6 +42,22 @@ type Transport interface {
 	Type() TransportType
 }
 
+// Stream is the server side of a live session's connection: a WebSocket,
+// or an SSE stream with POSTed client messages.
+type Stream interface {
+	Transport
+
+	// SendBulk queues a message behind regular ones (large diffs).
+	SendBulk(msg Message) error
+
+	// CloseChan is closed when the connection ends.
+	CloseChan() <-chan struct{}
+
+	// SetNotify sets a function called when a message arrives or the
+	// connection closes.
+	SetNotify(fn func())
+}
+
 // TransportType identifies the transport mechanism.
 type TransportType string
 
@@ -66,6 +83,10 @@ type Message struct {
 
 	// Timestamp is when the message was created
 	Timestamp time.Time `json:"ts,omitempty"`
+
+	// Binary is an optional raw attachment. Messages carrying one are sent
+	// as binary frames (see EncodeBinary) instead of JSON text.
+	Binary []byte `json:"-"`
 }
 
 // NewMessage creates a new message.
@@ -98,24 +119,43 @@ func Unmarshal(data []byte) (Message, error) {
 
 // TransportConfig holds common transport configuration.
 type TransportConfig struct {
-	// ReadTimeout is the maximum time to wait for a read
+	// ReadTimeout is the maximum time to wait for a read when
+	// HeartbeatTimeout is zero
 	ReadTimeout time.Duration
 
 	// WriteTimeout is the maximum time to wait for a write
 	WriteTimeout time.Duration
 
-	// PingInterval is how often to send heartbeats
+	// PingInterval is how often to send heartbeats (zero disables them)
 	PingInterval time.Duration
 
-	// PongTimeout is how long to wait for a pong response
+	// PongTimeout is how long to wait for a pong response before the
+	// connection is considered dead and closed
 	PongTimeout time.Duration
 
+	// HeartbeatTimeout is how long the client may stay silent (no message
+	// or heartbeat) before the connection is considered dead and closed,
+	// so clients that vanish without a close frame do not keep their
+	// session. It should exceed the client's heartbeat interval (30s by
+	// default). Zero falls back to ReadTimeout on WebSockets and
+	// disables the check on SSE.
+	HeartbeatTimeout time.Duration
+
 	// MaxMessageSize is the maximum message size in bytes
 	MaxMessageSize int64
 
 	// SendBufferSize is the size of the send channel buffer
 	SendBufferSize int
 
+	// MaxQueueDepth is how many messages may wait to be written to the
+	// client before OnOverflow decides (default SendBufferSize)
+	MaxQueueDepth int
+
+	// OnOverflow picks what to do with a message that finds the send queue
+	// full. It is called from the sending goroutine and must not send on
+	// the transport. nil coalesces diffs (OverflowCoalesce).
+	OnOverflow func(QueueOverflow) OverflowAction
+
 	// ReceiveBufferSize is the size of the receive channel buffer
 	ReceiveBufferSize int
 }
@@ -127,6 +167,7 @@ func DefaultTransportConfig() *TransportConfig {
 		WriteTimeout:      10 * time.Second,
 		PingInterval:      30 * time.Second,
 		PongTimeout:       10 * time.Second,
+		HeartbeatTimeout:  60 * time.Second,
 		MaxMessageSize:    512 * 1024, // 512KB
 		SendBufferSize:    256,
 		ReceiveBufferSize: 256,
@@ -141,6 +182,8 @@ type BaseTransport struct {
 	recvCh    chan Message
 	closeCh   chan struct{}
 	closeOnce sync.Once
+	bytesSent atomic.Int64
+	lastSeen  atomic.Int64
 	mu        sync.RWMutex
 }
 
@@ -174,6 +217,27 @@ func (t *BaseTransport) SetConnected(connected bool) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.connected = connected
+	if connected {
+		t.touch()
+	}
+}
+
+// touch records that the client was heard from.
+func (t *BaseTransport) touch() {
+	t.lastSeen.Store(time.Now().UnixNano())
+}
+
+// LastSeen returns when the client was last heard from: when it connected,
+// or its last message or pong.
+func (t *BaseTransport) LastSeen() time.Time {
+	return time.Unix(0, t.lastSeen.Load())
+}
+
+// silent reports whether the client has been silent for longer than
+// HeartbeatTimeout.
+func (t *BaseTransport) silent() bool {
+	timeout := t.config.HeartbeatTimeout
+	return timeout > 0 && time.Since(t.LastSeen()) > timeout
 }
 
 // Receive returns the receive channel.
@@ -191,6 +255,12 @@ func (t *BaseTransport) CloseChan() <-chan struct{} {
 	return t.closeCh
 }
 
+// BytesSent returns how many bytes the transport has written to the
+// client.
+func (t *BaseTransport) BytesSent() int64 {
+	return t.bytesSent.Load()
+}
+
 // Close closes the base transport channels.
 func (t *BaseTransport) Close() error {
 	t.closeOnce.Do(func() {
@@ -1,9 +1,13 @@
 package transport
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/coder/websocket"
 )
 
 func TestWebSocket_OriginValidation(t *testing.T) {
@@ -166,3 +170,28 @@ func TestDefaultWebSocketConfig(t *testing.T) {
 		t.Error("AllowedOrigins should be nil by default (same-origin only)")
 	}
 }
+
+func TestWebSocket_ClosesSilentConnection(t *testing.T) {
+	config := DefaultTransportConfig()
+	config.PingInterval = 0
+	config.HeartbeatTimeout = 100 * time.Millisecond
+	server := httptest.NewServer(NewWebSocketHandler(config, nil))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+server.URL[4:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	// The client vanishes without a close frame: it never sends anything
+	start := time.Now()
+	if _, _, err := conn.Read(ctx); err == nil || ctx.Err() != nil {
+		t.Fatalf("Expected the server to close the silent connection, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the connection closed after the heartbeat timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,421 @@
+package uploads
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPresignExpiry is the longest validity of a presigned URL on S3 and GCS.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// requestExpiry is the validity of the URLs the stores sign for their own
+// Put and Delete requests.
+const requestExpiry = 15 * time.Minute
+
+// S3Store stores uploads in an S3 bucket, or in an S3-compatible service
+// (MinIO, Cloudflare R2) when Endpoint is set. Requests are signed with
+// Signature Version 4; no SDK is needed:
+//
+//	store := uploads.NewS3Store("my-bucket", "eu-west-1", os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"))
+//
+// Browsers uploading directly (AllowOptions.External) need a CORS rule on
+// the bucket allowing PUT from the application's origin.
+type S3Store struct {
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is set for temporary credentials.
+	SessionToken string
+
+	// Endpoint is the URL of an S3-compatible service, addressed
+	// path-style ("https://<account>.r2.cloudflarestorage.com"). Empty uses
+	// AWS virtual-hosted URLs.
+	Endpoint string
+
+	// BaseURL is where the files are served from, e.g. a CDN. Empty uses
+	// the object URLs.
+	BaseURL string
+
+	// Client sends the requests (default http.DefaultClient).
+	Client *http.Client
+}
+
+// NewS3Store creates an S3 store.
+func NewS3Store(bucket, region, accessKeyID, secretAccessKey string) *S3Store {
+	return &S3Store{Bucket: bucket, Region: region, AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey}
+}
+
+// Put uploads the file to the bucket.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	p, err := s.PresignURL(ctx, key, contentType, size, requestExpiry)
+	if err != nil {
+		return "", err
+	}
+	if err := doSigned(ctx, s.Client, p, r, size); err != nil {
+		return "", fmt.Errorf("uploads: s3 put %s: %w", key, err)
+	}
+	return p.FileURL, nil
+}
+
+// PresignURL signs a PUT of the file for the browser. The signature covers
+// the content type and length, so the upload must match them.
+func (s *S3Store) PresignURL(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*PresignedURL, error) {
+	return presignPut(s.signer(), s.objectURL(key), s.fileURL(key), contentType, size, expires)
+}
+
+// Delete removes the file from the bucket.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := deleteSigned(ctx, s.Client, s.signer(), s.objectURL(key)); err != nil {
+		return fmt.Errorf("uploads: s3 delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	if s.Endpoint != "" {
+		u, _ := url.Parse(strings.TrimSuffix(s.Endpoint, "/"))
+		u.Path += "/" + s.Bucket + "/" + key
+		return u
+	}
+	return &url.URL{Scheme: "https", Host: s.Bucket + ".s3." + s.Region + ".amazonaws.com", Path: "/" + key}
+}
+
+func (s *S3Store) fileURL(key string) string {
+	if s.BaseURL != "" {
+		return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+	}
+	u := s.objectURL(key)
+	u.Path = uriEncode(u.Path, false)
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+func (s *S3Store) signer() *v4Signer {
+	v := &v4Signer{
+		algorithm:  "AWS4-HMAC-SHA256",
+		prefix:     "X-Amz",
+		credential: s.AccessKeyID,
+		scope:      func(date string) string { return date + "/" + s.Region + "/s3/aws4_request" },
+		sign: func(date, stringToSign string) (string, error) {
+			key := []byte("AWS4" + s.SecretAccessKey)
+			for _, part := range []string{date, s.Region, "s3", "aws4_request", stringToSign} {
+				key = hmacSHA256(key, part)
+			}
+			return hex.EncodeToString(key), nil
+		},
+	}
+	if s.SessionToken != "" {
+		v.extra = url.Values{"X-Amz-Security-Token": {s.SessionToken}}
+	}
+	return v
+}
+
+// GCSStore stores uploads in a Google Cloud Storage bucket, signing
+// requests with a service account key (V4 signed URLs); no SDK is needed:
+//
+//	creds, _ := os.ReadFile("service-account.json")
+//	store, err := uploads.NewGCSStore("my-bucket", creds)
+//
+// Browsers uploading directly (AllowOptions.External) need a CORS
+// configuration on the bucket allowing PUT from the application's origin.
+type GCSStore struct {
+	Bucket string
+
+	// ClientEmail and PrivateKey identify the service account.
+	ClientEmail string
+	PrivateKey  *rsa.PrivateKey
+
+	// Endpoint is the XML API URL (default https://storage.googleapis.com).
+	Endpoint string
+
+	// BaseURL is where the files are served from, e.g. a CDN. Empty uses
+	// the object URLs.
+	BaseURL string
+
+	// Client sends the requests (default http.DefaultClient).
+	Client *http.Client
+}
+
+// NewGCSStore creates a GCS store from a service account key file (JSON).
+func NewGCSStore(bucket string, credentialsJSON []byte) (*GCSStore, error) {
+	var creds struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal(credentialsJSON, &creds); err != nil {
+		return nil, fmt.Errorf("uploads: gcs credentials: %w", err)
+	}
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil || creds.ClientEmail == "" {
+		return nil, errors.New("uploads: gcs credentials: missing client_email or private_key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("uploads: gcs credentials: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("uploads: gcs credentials: private_key is not RSA")
+	}
+	return &GCSStore{Bucket: bucket, ClientEmail: creds.ClientEmail, PrivateKey: key}, nil
+}
+
+// Put uploads the file to the bucket.
+func (s *GCSStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	p, err := s.PresignURL(ctx, key, contentType, size, requestExpiry)
+	if err != nil {
+		return "", err
+	}
+	if err := doSigned(ctx, s.Client, p, r, size); err != nil {
+		return "", fmt.Errorf("uploads: gcs put %s: %w", key, err)
+	}
+	return p.FileURL, nil
+}
+
+// PresignURL signs a PUT of the file for the browser. The signature covers
+// the content type and length, so the upload must match them.
+func (s *GCSStore) PresignURL(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*PresignedURL, error) {
+	return presignPut(s.signer(), s.objectURL(key), s.fileURL(key), contentType, size, expires)
+}
+
+// Delete removes the file from the bucket.
+func (s *GCSStore) Delete(ctx context.Context, key string) error {
+	if err := deleteSigned(ctx, s.Client, s.signer(), s.objectURL(key)); err != nil {
+		return fmt.Errorf("uploads: gcs delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *GCSStore) objectURL(key string) *url.URL {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	u, _ := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	u.Path += "/" + s.Bucket + "/" + key
+	return u
+}
+
+func (s *GCSStore) fileURL(key string) string {
+	if s.BaseURL != "" {
+		return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+	}
+	u := s.objectURL(key)
+	return u.Scheme + "://" + u.Host + uriEncode(u.Path, false)
+}
+
+func (s *GCSStore) signer() *v4Signer {
+	return &v4Signer{
+		algorithm:  "GOOG4-RSA-SHA256",
+		prefix:     "X-Goog",
+		credential: s.ClientEmail,
+		scope:      func(date string) string { return date + "/auto/storage/goog4_request" },
+		sign: func(date, stringToSign string) (string, error) {
+			if s.PrivateKey == nil {
+				return "", errors.New("uploads: gcs: no private key")
+			}
+			sum := sha256.Sum256([]byte(stringToSign))
+			sig, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, sum[:])
+			return hex.EncodeToString(sig), err
+		},
+	}
+}
+
+// presignPut signs a PUT of size bytes of contentType to u.
+func presignPut(v *v4Signer, u *url.URL, fileURL, contentType string, size int64, expires time.Duration) (*PresignedURL, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if expires <= 0 || expires > MaxPresignExpiry {
+		expires = MaxPresignExpiry
+	}
+	signed, err := v.presign(http.MethodPut, u, map[string]string{
+		"Content-Type":   contentType,
+		"Content-Length": strconv.FormatInt(size, 10),
+	}, time.Now(), expires)
+	if err != nil {
+		return nil, err
+	}
+	return &PresignedURL{
+		URL:     signed,
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+		FileURL: fileURL,
+	}, nil
+}
+
+// doSigned sends the body to a presigned URL.
+func doSigned(ctx context.Context, client *http.Client, p *PresignedURL, body io.Reader, size int64) error {
+	if size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, p.Method, p.URL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+	return send(client, req)
+}
+
+// deleteSigned deletes u through a presigned URL. Missing objects are not
+// an error.
+func deleteSigned(ctx context.Context, client *http.Client, v *v4Signer, u *url.URL) error {
+	signed, err := v.presign(http.MethodDelete, u, nil, time.Now(), requestExpiry)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, signed, nil)
+	if err != nil {
+		return err
+	}
+	err = send(client, req)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// statusError is a non-2xx response of the storage service.
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+func send(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &statusError{code: resp.StatusCode, msg: strings.TrimSpace(resp.Status + ": " + string(body))}
+}
+
+// v4Signer signs URLs with the Signature Version 4 query scheme of S3,
+// which GCS implements under its own names.
+type v4Signer struct {
+	algorithm  string // "AWS4-HMAC-SHA256"
+	prefix     string // query parameter prefix, "X-Amz"
+	credential string
+	scope      func(date string) string
+	sign       func(date, stringToSign string) (string, error)
+	extra      url.Values // signed parameters, e.g. the session token
+}
+
+// presign returns u signed for method and headers, valid for expires from
+// now. The payload is not signed.
+func (v *v4Signer) presign(method string, u *url.URL, headers map[string]string, now time.Time, expires time.Duration) (string, error) {
+	now = now.UTC()
+	date, stamp := now.Format("20060102"), now.Format("20060102T150405Z")
+	scope := v.scope(date)
+
+	values := map[string]string{"host": u.Host}
+	for k, val := range headers {
+		values[strings.ToLower(k)] = strings.TrimSpace(val)
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := u.Query()
+	query.Set(v.prefix+"-Algorithm", v.algorithm)
+	query.Set(v.prefix+"-Credential", v.credential+"/"+scope)
+	query.Set(v.prefix+"-Date", stamp)
+	query.Set(v.prefix+"-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set(v.prefix+"-SignedHeaders", signedHeaders)
+	for k, vals := range v.extra {
+		query[k] = vals
+	}
+	canonicalQuery := encodeQuery(query)
+	path := uriEncode(u.Path, false)
+
+	request := strings.Join([]string{method, path, canonicalQuery, canonicalHeaders.String(), signedHeaders, "UNSIGNED-PAYLOAD"}, "\n")
+	sum := sha256.Sum256([]byte(request))
+	signature, err := v.sign(date, strings.Join([]string{v.algorithm, stamp, scope, hex.EncodeToString(sum[:])}, "\n"))
+	if err != nil {
+		return "", err
+	}
+	return u.Scheme + "://" + u.Host + path + "?" + canonicalQuery + "&" + v.prefix + "-Signature=" + signature, nil
+}
+
+// encodeQuery encodes query sorted by key, as signatures require.
+func encodeQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, val := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(val, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but RFC 3986 unreserved characters,
+// and slashes unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
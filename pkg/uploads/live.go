@@ -0,0 +1,586 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Events sent by the client-side lv-upload binding. External uploads report
+// progress, completion and failure instead of sending chunks.
+const (
+	UploadStartEvent    = "upload_start"
+	UploadChunkEvent    = "upload_chunk"
+	UploadCancelEvent   = "upload_cancel"
+	UploadProgressEvent = "upload_progress"
+	UploadDoneEvent     = "upload_done"
+	UploadErrorEvent    = "upload_error"
+)
+
+// PresignEvent is pushed to the client with the presigned URLs of the
+// accepted files of an external upload.
+const PresignEvent = "lv:upload_presign"
+
+// DefaultChunkSize is the size of the binary chunks lv-upload sends. It
+// stays under the transport's message size limit.
+const DefaultChunkSize = 256 * 1024
+
+// DefaultPresignExpiry is how long the browser has to start an external
+// upload.
+const DefaultPresignExpiry = 15 * time.Minute
+
+// Live upload errors.
+var (
+	ErrUploadNotAllowed = errors.New("upload not allowed")
+	ErrNoPusher         = errors.New("external uploads need Uploader.PushTo")
+)
+
+// Pusher sends events to the client. *core.Socket implements it.
+type Pusher interface {
+	Push(event string, payload map[string]any) error
+}
+
+// IsUploadEvent reports whether event belongs to the lv-upload binding.
+func IsUploadEvent(event string) bool {
+	switch event {
+	case UploadStartEvent, UploadChunkEvent, UploadCancelEvent,
+		UploadProgressEvent, UploadDoneEvent, UploadErrorEvent:
+		return true
+	}
+	return false
+}
+
+// AllowOptions constrains an upload allowed with AllowUpload.
+type AllowOptions struct {
+	// Accept lists the allowed extensions (".jpg") and MIME types
+	// ("image/*", "application/pdf"). Empty accepts any file.
+	Accept []string
+
+	// MaxFileSize is the maximum file size in bytes (default 10MB).
+	MaxFileSize int64
+
+	// MaxEntries is the maximum number of files (default 1). With one
+	// entry, choosing another file replaces the current one.
+	MaxEntries int
+
+	// ChunkSize is the size of the chunks the client sends (default
+	// DefaultChunkSize).
+	ChunkSize int
+
+	// External uploads the files from the browser straight to the storage
+	// with presigned URLs, so large files never transit the server. The
+	// storage must support PresignURL, and the uploader needs PushTo.
+	External bool
+}
+
+// LiveUpload is an upload allowed with AllowUpload: the files chosen in its
+// lv-upload input, received while the user fills in the form.
+type LiveUpload struct {
+	Name    string
+	Options AllowOptions
+
+	entries []*liveEntry
+	mu      sync.Mutex
+}
+
+// liveEntry is an entry being received.
+type liveEntry struct {
+	*UploadEntry
+	ref      string // client reference, used by the chunk events
+	received int64
+	file     *os.File
+	store    Storage // set for external entries, uploaded under Key
+}
+
+// Entries returns the chosen files in order, with their progress and
+// errors.
+func (l *LiveUpload) Entries() []*UploadEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]*UploadEntry, len(l.entries))
+	for i, e := range l.entries {
+		entries[i] = e.UploadEntry
+	}
+	return entries
+}
+
+// Pending reports whether files are still being received.
+func (l *LiveUpload) Pending() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.entries {
+		if !e.Done && len(e.Errors) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Uploader receives the files of lv-upload inputs for a component. Files
+// arrive in binary chunks while the user fills in the form and wait in
+// temporary files; Consume moves the finished ones to the storage, usually
+// when the form is submitted:
+//
+//	func (c *Profile) Mount(ctx context.Context, params core.Params, session core.Session) error {
+//		c.uploads = uploads.NewUploader(store)
+//		c.uploads.AllowUpload("avatar", uploads.AllowOptions{Accept: []string{".jpg", ".png"}, MaxFileSize: 5 << 20})
+//		return nil
+//	}
+//
+//	func (c *Profile) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//		if uploads.IsUploadEvent(event) {
+//			_, err := c.uploads.HandleEvent(event, payload)
+//			return err
+//		}
+//		if event == "save" {
+//			entries, err := c.uploads.Consume(ctx, "avatar")
+//			...
+//		}
+//		return nil
+//	}
+//
+// The form renders the input with Input("avatar"). Call Close in Terminate
+// to remove the files never consumed.
+type Uploader struct {
+	storage Storage
+	tempDir string
+	pusher  Pusher
+	uploads map[string]*LiveUpload
+	mu      sync.Mutex
+}
+
+// NewUploader creates an uploader consuming files into storage.
+func NewUploader(storage Storage) *Uploader {
+	return &Uploader{
+		storage: storage,
+		tempDir: os.TempDir(),
+		uploads: make(map[string]*LiveUpload),
+	}
+}
+
+// TempDir sets the directory files wait in until consumed (default
+// os.TempDir()).
+func (u *Uploader) TempDir(dir string) *Uploader {
+	u.tempDir = dir
+	return u
+}
+
+// PushTo sets where the presigned URLs of external uploads are pushed,
+// usually the component's socket.
+func (u *Uploader) PushTo(p Pusher) *Uploader {
+	u.pusher = p
+	return u
+}
+
+// AllowUpload allows the lv-upload input named name, replacing a previous
+// upload of that name.
+func (u *Uploader) AllowUpload(name string, opts AllowOptions) *LiveUpload {
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = DefaultUploadConfig().MaxFileSize
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 1
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	l := &LiveUpload{Name: name, Options: opts}
+
+	u.mu.Lock()
+	old := u.uploads[name]
+	u.uploads[name] = l
+	u.mu.Unlock()
+	if old != nil {
+		old.discard()
+	}
+	return l
+}
+
+// Upload returns the upload allowed as name.
+func (u *Uploader) Upload(name string) (*LiveUpload, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	l, ok := u.uploads[name]
+	return l, ok
+}
+
+// HandleEvent processes an lv-upload event and returns the affected upload.
+// Binary chunks are read from payload[core.BinaryKey]. Files failing the
+// upload's constraints are kept as entries with errors, for the component
+// to render; chunks of rejected or cancelled files are ignored.
+func (u *Uploader) HandleEvent(event string, payload map[string]any) (*LiveUpload, error) {
+	name, _ := payload["name"].(string)
+	l, ok := u.Upload(name)
+	if !ok {
+		return nil, ErrUploadNotAllowed
+	}
+	ref, _ := payload["ref"].(string)
+
+	switch event {
+	case UploadStartEvent:
+		files, _ := payload["files"].([]any)
+		if l.Options.External {
+			return l, u.startExternal(l, files)
+		}
+		return l, l.start(u.tempDir, files)
+	case UploadChunkEvent:
+		data, _ := payload[core.BinaryKey].([]byte)
+		offset, _ := payload["offset"].(float64)
+		return l, l.chunk(ref, int64(offset), data)
+	case UploadCancelEvent:
+		l.remove(func(e *liveEntry) bool { return e.ref == ref })
+		return l, nil
+	case UploadProgressEvent, UploadDoneEvent, UploadErrorEvent:
+		progress, _ := payload["progress"].(float64)
+		l.report(ref, event, int(progress))
+		return l, nil
+	}
+	return nil, ErrUploadFailed
+}
+
+// Cancel removes the entry with the given UUID from the upload name, e.g.
+// from a remove button next to it.
+func (u *Uploader) Cancel(name, uuid string) {
+	if l, ok := u.Upload(name); ok {
+		l.remove(func(e *liveEntry) bool { return e.UUID == uuid })
+	}
+}
+
+// Consume stores the received files of the upload name and removes them
+// from it. The returned entries carry their storage Key and URL. Files
+// still arriving stay, so check Pending first when all of them are needed.
+func (u *Uploader) Consume(ctx context.Context, name string) ([]*UploadEntry, error) {
+	l, ok := u.Upload(name)
+	if !ok {
+		return nil, ErrUploadNotAllowed
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var consumed []*UploadEntry
+	kept := l.entries[:0]
+	var err error
+	for _, e := range l.entries {
+		if err != nil || !e.Done || len(e.Errors) > 0 {
+			kept = append(kept, e)
+			continue
+		}
+		if e.store != nil {
+			// Already in the storage: uploaded by the browser
+			e.store = nil
+		} else if err = u.store(ctx, name, e); err != nil {
+			kept = append(kept, e)
+			continue
+		}
+		consumed = append(consumed, e.UploadEntry)
+	}
+	l.entries = kept
+	return consumed, err
+}
+
+// store moves a received file to the storage.
+func (u *Uploader) store(ctx context.Context, name string, e *liveEntry) error {
+	f, err := os.Open(e.TempPath)
+	if err != nil {
+		return err
+	}
+	key := name + "/" + e.UUID + strings.ToLower(filepath.Ext(e.FileName))
+	url, err := u.storage.Put(ctx, key, f, e.Size, e.ContentType)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("store %s: %w", e.FileName, err)
+	}
+	os.Remove(e.TempPath)
+	e.TempPath = ""
+	e.Key, e.URL = key, url
+	return nil
+}
+
+// Input renders the file input of the upload name, with the constraints
+// the client checks before sending files.
+func (u *Uploader) Input(name string) string {
+	l, ok := u.Upload(name)
+	if !ok {
+		return ""
+	}
+	opts := l.Options
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<input type="file" lv-upload="%s" data-max-size="%d" data-max-entries="%d" data-chunk-size="%d"`,
+		html.EscapeString(name), opts.MaxFileSize, opts.MaxEntries, opts.ChunkSize)
+	if opts.External {
+		sb.WriteString(" data-external")
+	}
+	if len(opts.Accept) > 0 {
+		fmt.Fprintf(&sb, ` accept="%s"`, html.EscapeString(strings.Join(opts.Accept, ",")))
+	}
+	if opts.MaxEntries > 1 {
+		sb.WriteString(" multiple")
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// Close removes the files of every upload that were never consumed.
+func (u *Uploader) Close() {
+	u.mu.Lock()
+	uploads := u.uploads
+	u.uploads = make(map[string]*LiveUpload)
+	u.mu.Unlock()
+	for _, l := range uploads {
+		l.discard()
+	}
+}
+
+// start registers the files chosen on the client.
+func (l *LiveUpload) start(tempDir string, files []any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Options.MaxEntries == 1 {
+		for _, e := range l.entries {
+			e.close(true)
+		}
+		l.entries = nil
+	}
+
+	for _, raw := range files {
+		file, _ := raw.(map[string]any)
+		ref, _ := file["ref"].(string)
+		filename, _ := file["name"].(string)
+		size, _ := file["size"].(float64)
+		contentType, _ := file["type"].(string)
+
+		e := &liveEntry{
+			UploadEntry: &UploadEntry{
+				UUID:        generateUUID(),
+				FileName:    sanitizeFilename(filename),
+				Size:        int64(size),
+				ContentType: contentType,
+				CreatedAt:   time.Now(),
+			},
+			ref: ref,
+		}
+		if err := l.check(e); err != nil {
+			e.Errors = append(e.Errors, err.Error())
+			l.entries = append(l.entries, e)
+			continue
+		}
+		if l.Options.External {
+			l.entries = append(l.entries, e)
+			continue
+		}
+
+		f, err := os.CreateTemp(tempDir, "upload-*"+filepath.Ext(e.FileName))
+		if err != nil {
+			return err
+		}
+		e.file, e.TempPath = f, f.Name()
+		if e.Size == 0 {
+			e.finish()
+		}
+		l.entries = append(l.entries, e)
+	}
+	return nil
+}
+
+// startExternal registers the files chosen on the client and pushes the
+// presigned URLs of the accepted ones. The client uploads only those.
+func (u *Uploader) startExternal(l *LiveUpload, files []any) error {
+	if u.pusher == nil {
+		return ErrNoPusher
+	}
+	if err := l.start("", files); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	var presigned []map[string]any
+	for _, e := range l.entries {
+		if e.Key != "" || e.Done || len(e.Errors) > 0 {
+			continue
+		}
+		key := l.Name + "/" + e.UUID + strings.ToLower(filepath.Ext(e.FileName))
+		// Presigning signs locally, without a request to the storage
+		p, err := u.storage.PresignURL(context.Background(), key, e.ContentType, e.Size, DefaultPresignExpiry)
+		if err != nil {
+			e.Errors = append(e.Errors, ErrUploadFailed.Error())
+			continue
+		}
+		e.Key, e.URL, e.store = key, p.FileURL, u.storage
+		presigned = append(presigned, map[string]any{
+			"ref": e.ref, "url": p.URL, "method": p.Method, "headers": p.Headers,
+		})
+	}
+	l.mu.Unlock()
+
+	if len(presigned) == 0 {
+		return nil
+	}
+	return u.pusher.Push(PresignEvent, map[string]any{"name": l.Name, "entries": presigned})
+}
+
+// report applies the progress, completion or failure the client reports
+// for an external entry.
+func (l *LiveUpload) report(ref, event string, progress int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.entries {
+		if e.ref != ref || e.store == nil || e.Done || len(e.Errors) > 0 {
+			continue
+		}
+		switch event {
+		case UploadProgressEvent:
+			e.Progress = min(max(progress, e.Progress), 99)
+		case UploadDoneEvent:
+			e.Progress, e.Done = 100, true
+		case UploadErrorEvent:
+			e.fail(ErrUploadFailed)
+		}
+	}
+}
+
+// check validates a new entry against the upload's constraints.
+func (l *LiveUpload) check(e *liveEntry) error {
+	accepted := 0
+	for _, other := range l.entries {
+		if len(other.Errors) == 0 {
+			accepted++
+		}
+	}
+	switch {
+	case accepted >= l.Options.MaxEntries:
+		return ErrMaxFilesReached
+	case e.Size < 0 || e.Size > l.Options.MaxFileSize:
+		return ErrFileTooLarge
+	case !accepts(l.Options.Accept, e.FileName, e.ContentType):
+		return ErrInvalidFileType
+	}
+	return nil
+}
+
+// chunk appends data at offset to the entry ref.
+func (l *LiveUpload) chunk(ref string, offset int64, data []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var e *liveEntry
+	for _, candidate := range l.entries {
+		if candidate.ref == ref {
+			e = candidate
+		}
+	}
+	if e == nil || e.file == nil {
+		return nil
+	}
+	if offset != e.received {
+		e.fail(ErrChunkOutOfOrder)
+		return ErrChunkOutOfOrder
+	}
+	if e.received+int64(len(data)) > e.Size {
+		e.fail(ErrFileTooLarge)
+		return ErrFileTooLarge
+	}
+	if _, err := e.file.Write(data); err != nil {
+		e.fail(ErrUploadFailed)
+		return err
+	}
+	e.received += int64(len(data))
+	e.Progress = int(e.received * 100 / e.Size)
+	if e.received == e.Size {
+		e.finish()
+	}
+	return nil
+}
+
+// remove drops the entries matching match, with their files.
+func (l *LiveUpload) remove(match func(*liveEntry) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kept := l.entries[:0]
+	for _, e := range l.entries {
+		if match(e) {
+			e.close(true)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	l.entries = kept
+}
+
+// discard drops all entries, with their files.
+func (l *LiveUpload) discard() {
+	l.remove(func(*liveEntry) bool { return true })
+}
+
+// finish marks a fully received entry done.
+func (e *liveEntry) finish() {
+	if err := e.file.Close(); err != nil {
+		e.fail(ErrUploadFailed)
+		return
+	}
+	e.file = nil
+	e.Progress = 100
+	e.Done = true
+}
+
+// fail aborts an entry, keeping it with the error.
+func (e *liveEntry) fail(err error) {
+	e.close(true)
+	e.Errors = append(e.Errors, err.Error())
+}
+
+// close closes the entry's file, removing it when remove is set. The
+// object of an external entry is deleted from the storage in the
+// background.
+func (e *liveEntry) close(remove bool) {
+	if e.file != nil {
+		e.file.Close()
+		e.file = nil
+	}
+	if remove && e.TempPath != "" {
+		os.Remove(e.TempPath)
+		e.TempPath = ""
+	}
+	if remove && e.store != nil {
+		store, key := e.store, e.Key
+		e.store = nil
+		go store.Delete(context.Background(), key)
+	}
+}
+
+// Accepts reports whether the file matches accept, listed as in
+// AllowOptions.Accept.
+func (e *UploadEntry) Accepts(accept ...string) bool {
+	return accepts(accept, e.FileName, e.ContentType)
+}
+
+// accepts reports whether a file matches the accept list: extensions
+// (".jpg"), MIME types and MIME wildcards ("image/*").
+func accepts(accept []string, filename, contentType string) bool {
+	if len(accept) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, a := range accept {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch {
+		case strings.HasPrefix(a, "."):
+			if a == ext {
+				return true
+			}
+		case a == "*/*" || a == contentType:
+			return true
+		case strings.HasSuffix(a, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(a, "*")):
+			return true
+		}
+	}
+	return false
+}
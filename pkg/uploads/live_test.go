@@ -0,0 +1,233 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func startPayload(name string, files ...map[string]any) map[string]any {
+	list := make([]any, len(files))
+	for i, f := range files {
+		list[i] = f
+	}
+	return map[string]any{"name": name, "files": list}
+}
+
+func chunkPayload(name, ref string, offset int, data string) map[string]any {
+	return map[string]any{"name": name, "ref": ref, "offset": float64(offset), core.BinaryKey: []byte(data)}
+}
+
+func TestUploader_ConsumeOnSubmit(t *testing.T) {
+	store := NewDiskStore(t.TempDir(), "/uploads/")
+	u := NewUploader(store).TempDir(t.TempDir())
+	defer u.Close()
+	l := u.AllowUpload("docs", AllowOptions{Accept: []string{".txt"}, MaxEntries: 2})
+
+	_, err := u.HandleEvent(UploadStartEvent, startPayload("docs",
+		map[string]any{"ref": "0", "name": "notes.txt", "size": float64(11), "type": "text/plain"},
+		map[string]any{"ref": "1", "name": "photo.jpg", "size": float64(3), "type": "image/jpeg"},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := l.Entries()
+	if len(entries) != 2 || len(entries[1].Errors) == 0 || entries[1].Errors[0] != ErrInvalidFileType.Error() {
+		t.Fatalf("Expected photo.jpg rejected, got %+v", entries)
+	}
+
+	u.HandleEvent(UploadChunkEvent, chunkPayload("docs", "0", 0, "hello "))
+	if entries[0].Progress != 54 || !l.Pending() {
+		t.Errorf("Expected partial progress, got %d", entries[0].Progress)
+	}
+	if consumed, _ := u.Consume(context.Background(), "docs"); len(consumed) != 0 {
+		t.Errorf("Expected nothing consumed while pending, got %v", consumed)
+	}
+
+	u.HandleEvent(UploadChunkEvent, chunkPayload("docs", "0", 6, "world"))
+	u.HandleEvent(UploadChunkEvent, chunkPayload("docs", "1", 0, "jpg")) // rejected: ignored
+	if l.Pending() || !entries[0].Done {
+		t.Fatal("Expected the upload complete")
+	}
+
+	tempPath := entries[0].TempPath
+	consumed, err := u.Consume(context.Background(), "docs")
+	if err != nil || len(consumed) != 1 {
+		t.Fatalf("Expected one file consumed, got %v, %v", consumed, err)
+	}
+	entry := consumed[0]
+	if !strings.HasPrefix(entry.Key, "docs/") || entry.URL != "/uploads/"+entry.Key {
+		t.Errorf("Unexpected key %q and URL %q", entry.Key, entry.URL)
+	}
+	data, _ := os.ReadFile(filepath.Join(store.Dir, entry.Key))
+	if string(data) != "hello world" {
+		t.Errorf("Expected the stored file, got %q", data)
+	}
+	if _, err := os.Stat(tempPath); !os.IsNotExist(err) {
+		t.Error("Expected the temporary file removed")
+	}
+	if len(l.Entries()) != 1 {
+		t.Errorf("Expected only the rejected entry left, got %d", len(l.Entries()))
+	}
+}
+
+func TestUploader_Constraints(t *testing.T) {
+	u := NewUploader(NewDiskStore(t.TempDir(), "/")).TempDir(t.TempDir())
+	defer u.Close()
+	l := u.AllowUpload("avatar", AllowOptions{Accept: []string{"image/*"}, MaxFileSize: 4})
+
+	u.HandleEvent(UploadStartEvent, startPayload("avatar",
+		map[string]any{"ref": "0", "name": "big.png", "size": float64(5), "type": "image/png"}))
+	if e := l.Entries(); len(e) != 1 || len(e[0].Errors) == 0 || e[0].Errors[0] != ErrFileTooLarge.Error() {
+		t.Fatalf("Expected big.png too large, got %+v", e)
+	}
+
+	// A single-entry upload replaces the previous file
+	u.HandleEvent(UploadStartEvent, startPayload("avatar",
+		map[string]any{"ref": "1", "name": "me.png", "size": float64(4), "type": "image/png"}))
+	if e := l.Entries(); len(e) != 1 || e[0].FileName != "me.png" || len(e[0].Errors) > 0 {
+		t.Fatalf("Expected me.png to replace big.png, got %+v", e)
+	}
+
+	if _, err := u.HandleEvent(UploadChunkEvent, chunkPayload("avatar", "1", 2, "ng")); !errors.Is(err, ErrChunkOutOfOrder) {
+		t.Errorf("Expected ErrChunkOutOfOrder, got %v", err)
+	}
+	if _, err := u.HandleEvent(UploadStartEvent, startPayload("other")); !errors.Is(err, ErrUploadNotAllowed) {
+		t.Errorf("Expected ErrUploadNotAllowed, got %v", err)
+	}
+}
+
+func TestUploader_Cancel(t *testing.T) {
+	u := NewUploader(NewDiskStore(t.TempDir(), "/")).TempDir(t.TempDir())
+	l := u.AllowUpload("docs", AllowOptions{MaxEntries: 3})
+	u.HandleEvent(UploadStartEvent, startPayload("docs",
+		map[string]any{"ref": "0", "name": "a.txt", "size": float64(2)},
+		map[string]any{"ref": "1", "name": "b.txt", "size": float64(2)},
+	))
+	entries := l.Entries()
+
+	u.HandleEvent(UploadCancelEvent, map[string]any{"name": "docs", "ref": "0"})
+	u.Cancel("docs", entries[1].UUID)
+	if len(l.Entries()) != 0 {
+		t.Errorf("Expected both entries cancelled, got %d", len(l.Entries()))
+	}
+	for _, e := range entries {
+		if _, err := os.Stat(e.TempPath); e.TempPath != "" && !os.IsNotExist(err) {
+			t.Errorf("Expected %s removed", e.TempPath)
+		}
+	}
+}
+
+func TestUploader_Input(t *testing.T) {
+	u := NewUploader(nil)
+	u.AllowUpload("photos", AllowOptions{Accept: []string{".jpg", "image/png"}, MaxEntries: 3, MaxFileSize: 1024})
+
+	input := u.Input("photos")
+	for _, want := range []string{`lv-upload="photos"`, `accept=".jpg,image/png"`, `data-max-size="1024"`, `data-max-entries="3"`, "multiple"} {
+		if !strings.Contains(input, want) {
+			t.Errorf("Expected %s in %s", want, input)
+		}
+	}
+	if u.Input("missing") != "" {
+		t.Error("Expected no input for an upload not allowed")
+	}
+}
+
+func TestDiskStore_RejectsEscapingKeys(t *testing.T) {
+	store := NewDiskStore(t.TempDir(), "/")
+	if _, err := store.Put(context.Background(), "../x", strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected ErrInvalidKey, got %v", err)
+	}
+}
+
+// presignStore presigns uploads to a fake bucket and records deletions.
+type presignStore struct {
+	*DiskStore
+	deleted chan string
+}
+
+func (s *presignStore) PresignURL(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*PresignedURL, error) {
+	return &PresignedURL{URL: "https://bucket.example.com/" + key + "?sig=1", Method: "PUT", FileURL: "https://cdn.example.com/" + key}, nil
+}
+
+func (s *presignStore) Delete(ctx context.Context, key string) error {
+	s.deleted <- key
+	return nil
+}
+
+type recordingPusher struct {
+	events   []string
+	payloads []map[string]any
+}
+
+func (p *recordingPusher) Push(event string, payload map[string]any) error {
+	p.events = append(p.events, event)
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+func TestUploader_External(t *testing.T) {
+	store := &presignStore{DiskStore: NewDiskStore(t.TempDir(), "/"), deleted: make(chan string, 1)}
+	pusher := &recordingPusher{}
+	u := NewUploader(store)
+	l := u.AllowUpload("videos", AllowOptions{Accept: []string{"video/*"}, MaxEntries: 2, External: true})
+
+	start := startPayload("videos",
+		map[string]any{"ref": "0", "name": "talk.mp4", "size": float64(1 << 20), "type": "video/mp4"},
+		map[string]any{"ref": "1", "name": "notes.txt", "size": float64(10), "type": "text/plain"},
+	)
+	if _, err := u.HandleEvent(UploadStartEvent, start); !errors.Is(err, ErrNoPusher) {
+		t.Fatalf("Expected ErrNoPusher, got %v", err)
+	}
+
+	u.AllowUpload("videos", AllowOptions{Accept: []string{"video/*"}, MaxEntries: 2, External: true})
+	l, _ = u.Upload("videos")
+	u.PushTo(pusher)
+	if _, err := u.HandleEvent(UploadStartEvent, start); err != nil {
+		t.Fatal(err)
+	}
+	if len(pusher.events) != 1 || pusher.events[0] != PresignEvent {
+		t.Fatalf("Expected the presigned URLs pushed, got %v", pusher.events)
+	}
+	presigned := pusher.payloads[0]["entries"].([]map[string]any)
+	if len(presigned) != 1 || presigned[0]["ref"] != "0" || !strings.HasPrefix(presigned[0]["url"].(string), "https://bucket.example.com/videos/") {
+		t.Fatalf("Expected only talk.mp4 presigned, got %v", presigned)
+	}
+	if entries := l.Entries(); entries[0].TempPath != "" {
+		t.Error("Expected no temporary file for an external upload")
+	}
+
+	u.HandleEvent(UploadProgressEvent, map[string]any{"name": "videos", "ref": "0", "progress": float64(100)})
+	if e := l.Entries()[0]; e.Progress != 99 || e.Done {
+		t.Errorf("Expected progress below 100 until done, got %d", e.Progress)
+	}
+	u.HandleEvent(UploadDoneEvent, map[string]any{"name": "videos", "ref": "0"})
+
+	consumed, err := u.Consume(context.Background(), "videos")
+	if err != nil || len(consumed) != 1 {
+		t.Fatalf("Expected talk.mp4 consumed, got %v, %v", consumed, err)
+	}
+	if e := consumed[0]; !strings.HasPrefix(e.Key, "videos/") || e.URL != "https://cdn.example.com/"+e.Key {
+		t.Errorf("Unexpected consumed entry %+v", e)
+	}
+
+	// An external file that is never consumed is deleted
+	u.HandleEvent(UploadStartEvent, startPayload("videos",
+		map[string]any{"ref": "2", "name": "demo.mp4", "size": float64(5), "type": "video/mp4"}))
+	key := l.Entries()[1].Key
+	u.Close()
+	select {
+	case deleted := <-store.deleted:
+		if deleted != key {
+			t.Errorf("Expected %s deleted, got %s", key, deleted)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the unconsumed file deleted")
+	}
+}
@@ -0,0 +1,325 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+// Events sent by the client-side lv-record binding.
+const (
+	RecordStartEvent  = "record_start"
+	RecordChunkEvent  = "record_chunk"
+	RecordStopEvent   = "record_stop"
+	RecordCancelEvent = "record_cancel"
+)
+
+// Recording errors.
+var (
+	ErrRecordingNotFound = errors.New("recording not found")
+	ErrRecordingTooLong  = errors.New("recording exceeds maximum duration")
+	ErrChunkOutOfOrder   = errors.New("chunk out of order")
+)
+
+// recordingExtensions maps recorder MIME types to file extensions.
+var recordingExtensions = map[string]string{
+	"audio/webm": ".webm",
+	"audio/ogg":  ".ogg",
+	"audio/mp4":  ".m4a",
+	"audio/mpeg": ".mp3",
+	"audio/wav":  ".wav",
+	"video/webm": ".webm",
+	"video/mp4":  ".mp4",
+}
+
+// IsRecordEvent reports whether event belongs to the lv-record binding.
+func IsRecordEvent(event string) bool {
+	switch event {
+	case RecordStartEvent, RecordChunkEvent, RecordStopEvent, RecordCancelEvent:
+		return true
+	}
+	return false
+}
+
+// Recording is a media recording streamed from the client in chunks.
+type Recording struct {
+	// ID is the client-generated recording identifier.
+	ID string
+
+	// Name is the lv-record binding name (e.g. "voice_note").
+	Name string
+
+	// Entry is the upload entry backing this recording.
+	Entry *UploadEntry
+
+	// Duration is the recorded duration reported by the client.
+	Duration time.Duration
+
+	// Size is the number of bytes received so far.
+	Size int64
+
+	// Chunks is the number of chunks received so far.
+	Chunks int
+
+	// Done indicates the recording was stopped and finalized.
+	Done bool
+
+	file *os.File
+	path string // temporary file
+	ext  string
+}
+
+// Recorder receives lv-record chunks for a component and writes them through
+// the upload pipeline, enforcing the upload config's size and type limits.
+// Chunks wait in a file under the config's TempDir; a stopped recording is
+// moved to storage, under name/uuid.ext, and its entry gets the storage's
+// Key and URL.
+//
+//	func (c *Chat) HandleEvent(ctx context.Context, event string, payload map[string]any) error {
+//		if uploads.IsRecordEvent(event) {
+//			_, err := c.recorder.HandleEvent(ctx, event, payload)
+//			return err
+//		}
+//		...
+//	}
+type Recorder struct {
+	upload      *Upload
+	storage     Storage
+	maxDuration time.Duration
+	active      map[string]*Recording
+	onComplete  func(rec *Recording)
+	mu          sync.Mutex
+}
+
+// NewRecorder creates a recorder storing finished recordings in storage.
+func NewRecorder(config *UploadConfig, storage Storage) *Recorder {
+	if config == nil {
+		config = DefaultUploadConfig()
+		config.Accept = []string{"audio/*"}
+	}
+	return &Recorder{
+		upload:  NewUpload(config),
+		storage: storage,
+		active:  make(map[string]*Recording),
+	}
+}
+
+// MaxDuration limits how long a recording may run (0 = unlimited).
+func (r *Recorder) MaxDuration(d time.Duration) *Recorder {
+	r.maxDuration = d
+	return r
+}
+
+// OnComplete sets the callback invoked when a recording is finalized.
+func (r *Recorder) OnComplete(fn func(rec *Recording)) *Recorder {
+	r.onComplete = fn
+	return r
+}
+
+// Upload returns the upload manager holding the recording entries.
+func (r *Recorder) Upload() *Upload {
+	return r.upload
+}
+
+// Active returns the recording in progress for a binding name, if any.
+func (r *Recorder) Active(name string) (*Recording, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rec := range r.active {
+		if rec.Name == name {
+			return rec, true
+		}
+	}
+	return nil, false
+}
+
+// HandleEvent processes an lv-record event and returns the affected
+// recording. Binary chunks are read from payload[core.BinaryKey]. ctx
+// bounds the move of a stopped recording to storage.
+func (r *Recorder) HandleEvent(ctx context.Context, event string, payload map[string]any) (*Recording, error) {
+	id, _ := payload["id"].(string)
+	if id == "" {
+		return nil, ErrRecordingNotFound
+	}
+
+	switch event {
+	case RecordStartEvent:
+		name, _ := payload["name"].(string)
+		mime, _ := payload["mime"].(string)
+		return r.start(id, name, mime)
+	case RecordChunkEvent:
+		data, _ := payload[core.BinaryKey].([]byte)
+		seq, _ := payload["seq"].(float64)
+		return r.chunk(id, int(seq), data, durationFrom(payload))
+	case RecordStopEvent:
+		return r.stop(ctx, id, durationFrom(payload))
+	case RecordCancelEvent:
+		return r.cancel(id)
+	}
+	return nil, ErrUploadFailed
+}
+
+func (r *Recorder) start(id, name, mime string) (*Recording, error) {
+	// Strip codec parameters: "audio/webm;codecs=opus" -> "audio/webm".
+	contentType, _, _ := strings.Cut(mime, ";")
+	contentType = strings.TrimSpace(contentType)
+
+	ext, ok := recordingExtensions[contentType]
+	if !ok {
+		ext = ".bin"
+	}
+
+	entry, err := r.upload.AddEntry(name+ext, 0, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir := r.upload.Config.TempDir
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	path := filepath.Join(tempDir, "golivekit-rec-"+entry.UUID+ext)
+	f, err := os.Create(path)
+	if err != nil {
+		r.upload.RemoveEntry(entry.UUID)
+		return nil, err
+	}
+	r.upload.updateEntry(entry.UUID, func(e *UploadEntry) { e.TempPath = path })
+
+	rec := &Recording{ID: id, Name: name, Entry: entry, file: f, path: path, ext: ext}
+
+	r.mu.Lock()
+	if old, ok := r.active[id]; ok {
+		r.discardLocked(old)
+	}
+	r.active[id] = rec
+	r.mu.Unlock()
+
+	return rec, nil
+}
+
+func (r *Recorder) chunk(id string, seq int, data []byte, duration time.Duration) (*Recording, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.active[id]
+	if !ok {
+		return nil, ErrRecordingNotFound
+	}
+	if seq != rec.Chunks {
+		r.failLocked(rec, ErrChunkOutOfOrder)
+		return rec, ErrChunkOutOfOrder
+	}
+	if rec.Size+int64(len(data)) > r.upload.Config.MaxFileSize {
+		r.failLocked(rec, ErrFileTooLarge)
+		return rec, ErrFileTooLarge
+	}
+	if r.maxDuration > 0 && duration > r.maxDuration {
+		r.failLocked(rec, ErrRecordingTooLong)
+		return rec, ErrRecordingTooLong
+	}
+
+	if _, err := rec.file.Write(data); err != nil {
+		r.failLocked(rec, ErrUploadFailed)
+		return rec, err
+	}
+
+	rec.Chunks++
+	rec.Size += int64(len(data))
+	rec.Duration = duration
+	size := rec.Size
+	r.upload.updateEntry(rec.Entry.UUID, func(e *UploadEntry) { e.Size = size })
+	return rec, nil
+}
+
+func (r *Recorder) stop(ctx context.Context, id string, duration time.Duration) (*Recording, error) {
+	r.mu.Lock()
+	rec, ok := r.active[id]
+	if !ok {
+		r.mu.Unlock()
+		return nil, ErrRecordingNotFound
+	}
+	delete(r.active, id)
+	r.mu.Unlock()
+
+	if err := rec.file.Close(); err != nil {
+		os.Remove(rec.path)
+		r.upload.Error(rec.Entry.UUID, "Failed to save recording")
+		return rec, err
+	}
+	if err := r.store(ctx, rec); err != nil {
+		r.upload.Error(rec.Entry.UUID, "Failed to save recording")
+		return rec, err
+	}
+	if duration > 0 {
+		rec.Duration = duration
+	}
+	rec.Done = true
+
+	if r.onComplete != nil {
+		r.onComplete(rec)
+	}
+	return rec, nil
+}
+
+// store moves a stopped recording from its temporary file to storage.
+func (r *Recorder) store(ctx context.Context, rec *Recording) error {
+	defer os.Remove(rec.path)
+	f, err := os.Open(rec.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	key := rec.Name + "/" + rec.Entry.UUID + rec.ext
+	url, err := r.storage.Put(ctx, key, f, rec.Size, rec.Entry.ContentType)
+	if err != nil {
+		return fmt.Errorf("store recording %s: %w", rec.Name, err)
+	}
+	r.upload.updateEntry(rec.Entry.UUID, func(e *UploadEntry) { e.TempPath, e.Key = "", key })
+	r.upload.Complete(rec.Entry.UUID, url)
+	return nil
+}
+
+func (r *Recorder) cancel(id string) (*Recording, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.active[id]
+	if !ok {
+		return nil, ErrRecordingNotFound
+	}
+	r.discardLocked(rec)
+	return rec, nil
+}
+
+// failLocked aborts a recording, keeping the entry with an error so the
+// component can render it.
+func (r *Recorder) failLocked(rec *Recording, err error) {
+	delete(r.active, rec.ID)
+	rec.file.Close()
+	os.Remove(rec.path)
+	r.upload.updateEntry(rec.Entry.UUID, func(e *UploadEntry) { e.TempPath = "" })
+	r.upload.Error(rec.Entry.UUID, err.Error())
+}
+
+// discardLocked aborts a recording and removes its entry and file.
+func (r *Recorder) discardLocked(rec *Recording) {
+	delete(r.active, rec.ID)
+	rec.file.Close()
+	r.upload.RemoveEntry(rec.Entry.UUID)
+}
+
+// durationFrom reads the client-reported duration in milliseconds.
+func durationFrom(payload map[string]any) time.Duration {
+	ms, _ := payload["duration"].(float64)
+	return time.Duration(ms) * time.Millisecond
+}
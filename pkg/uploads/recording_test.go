@@ -0,0 +1,135 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gabrielmiguelok/golivekit/pkg/core"
+)
+
+func recordPayload(id string, fields map[string]any) map[string]any {
+	payload := map[string]any{"id": id}
+	for k, v := range fields {
+		payload[k] = v
+	}
+	return payload
+}
+
+// newTestRecorder returns a recorder storing in a DiskStore, with its
+// storage directory.
+func newTestRecorder(t *testing.T, maxSize int64) (*Recorder, string) {
+	t.Helper()
+	config := DefaultUploadConfig()
+	config.Accept = []string{"audio/*"}
+	config.MaxFileSize = maxSize
+	config.TempDir = t.TempDir()
+	dir := t.TempDir()
+	return NewRecorder(config, NewDiskStore(dir, "https://cdn.example.com/media/")), dir
+}
+
+func TestRecorder_AppendsChunks(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestRecorder(t, 10)
+
+	rec, err := r.HandleEvent(ctx, RecordStartEvent, recordPayload("r1", map[string]any{"name": "voice_note", "mime": "audio/webm;codecs=opus"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Entry.ContentType != "audio/webm" {
+		t.Errorf("Expected codec parameters stripped, got %q", rec.Entry.ContentType)
+	}
+	if active, ok := r.Active("voice_note"); !ok || active != rec {
+		t.Error("Expected the recording active")
+	}
+
+	for seq, data := range []string{"abc", "def"} {
+		chunk := recordPayload("r1", map[string]any{"seq": float64(seq), "duration": float64(500 * (seq + 1)), core.BinaryKey: []byte(data)})
+		if _, err := r.HandleEvent(ctx, RecordChunkEvent, chunk); err != nil {
+			t.Fatalf("chunk %d: %v", seq, err)
+		}
+	}
+	if got, _ := os.ReadFile(rec.path); string(got) != "abcdef" || rec.Chunks != 2 || rec.Entry.Size != 6 {
+		t.Errorf("Expected 2 chunks of abcdef, got %q in %d chunks (size %d)", got, rec.Chunks, rec.Entry.Size)
+	}
+
+	// A skipped chunk fails the recording, keeping its entry with the error
+	chunk := recordPayload("r1", map[string]any{"seq": float64(3), core.BinaryKey: []byte("x")})
+	if _, err := r.HandleEvent(ctx, RecordChunkEvent, chunk); !errors.Is(err, ErrChunkOutOfOrder) {
+		t.Fatalf("Expected ErrChunkOutOfOrder, got %v", err)
+	}
+	if _, err := os.Stat(rec.path); !os.IsNotExist(err) {
+		t.Error("Expected the temporary file removed")
+	}
+	if entry, _ := r.Upload().GetEntry(rec.Entry.UUID); len(entry.Errors) != 1 || entry.TempPath != "" {
+		t.Errorf("Expected the entry kept with an error, got %+v", entry)
+	}
+	if _, ok := r.Active("voice_note"); ok {
+		t.Error("Expected no active recording")
+	}
+
+	// Past MaxFileSize
+	r.HandleEvent(ctx, RecordStartEvent, recordPayload("r2", map[string]any{"name": "memo", "mime": "audio/ogg"}))
+	chunk = recordPayload("r2", map[string]any{"seq": float64(0), core.BinaryKey: []byte("0123456789!")})
+	if _, err := r.HandleEvent(ctx, RecordChunkEvent, chunk); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge, got %v", err)
+	}
+}
+
+func TestRecorder_StopStoresRecording(t *testing.T) {
+	ctx := context.Background()
+	r, dir := newTestRecorder(t, 1<<20)
+	var completed *Recording
+	r.OnComplete(func(rec *Recording) { completed = rec })
+
+	rec, _ := r.HandleEvent(ctx, RecordStartEvent, recordPayload("r1", map[string]any{"name": "voice_note", "mime": "audio/ogg"}))
+	r.HandleEvent(ctx, RecordChunkEvent, recordPayload("r1", map[string]any{"seq": float64(0), core.BinaryKey: []byte("ogg")}))
+	if _, err := r.HandleEvent(ctx, RecordStopEvent, recordPayload("r1", map[string]any{"duration": float64(1500)})); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	if completed != rec || !rec.Done || rec.Duration.Milliseconds() != 1500 {
+		t.Errorf("Expected the completed recording of 1.5s, got %+v", completed)
+	}
+	entry, _ := r.Upload().GetEntry(rec.Entry.UUID)
+	key := "voice_note/" + entry.UUID + ".ogg"
+	if !entry.Done || entry.Key != key || entry.URL != "https://cdn.example.com/media/"+key {
+		t.Errorf("Expected the entry stored under %s, got key %q url %q", key, entry.Key, entry.URL)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(key))); string(got) != "ogg" {
+		t.Errorf("Expected the recording in storage, got %q", got)
+	}
+	if _, err := os.Stat(rec.path); !os.IsNotExist(err) || entry.TempPath != "" {
+		t.Error("Expected the temporary file removed")
+	}
+
+	if _, err := r.HandleEvent(ctx, RecordStopEvent, recordPayload("r1", nil)); !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("Expected a second stop to find nothing, got %v", err)
+	}
+}
+
+func TestRecorder_CancelCleansUp(t *testing.T) {
+	ctx := context.Background()
+	r, dir := newTestRecorder(t, 1<<20)
+
+	rec, _ := r.HandleEvent(ctx, RecordStartEvent, recordPayload("r1", map[string]any{"name": "voice_note", "mime": "audio/webm"}))
+	r.HandleEvent(ctx, RecordChunkEvent, recordPayload("r1", map[string]any{"seq": float64(0), core.BinaryKey: []byte("webm")}))
+	if _, err := r.HandleEvent(ctx, RecordCancelEvent, recordPayload("r1", nil)); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	if _, err := os.Stat(rec.path); !os.IsNotExist(err) {
+		t.Error("Expected the temporary file removed")
+	}
+	if _, ok := r.Upload().GetEntry(rec.Entry.UUID); ok {
+		t.Error("Expected the entry removed")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Expected nothing stored, got %v", files)
+	}
+	if _, err := r.HandleEvent(ctx, RecordChunkEvent, recordPayload("r1", map[string]any{"seq": float64(1)})); !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("Expected chunks after cancel refused, got %v", err)
+	}
+}
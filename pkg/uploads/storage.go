@@ -0,0 +1,98 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Storage errors.
+var (
+	ErrInvalidKey          = errors.New("invalid storage key")
+	ErrPresignNotSupported = errors.New("storage does not support presigned uploads")
+)
+
+// Storage keeps consumed uploads. DiskStore, S3Store and GCSStore implement
+// it.
+type Storage interface {
+	// Put stores size bytes read from r under key and returns their URL.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error)
+
+	// PresignURL returns a URL the browser uploads the file to directly,
+	// valid for expires, so it never transits the server.
+	PresignURL(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*PresignedURL, error)
+
+	// Delete removes the file stored under key.
+	Delete(ctx context.Context, key string) error
+}
+
+// DiskStore stores uploads in a local directory, served by the application
+// under BaseURL:
+//
+//	store := uploads.NewDiskStore("./data/uploads", "/uploads/")
+//	mux.Handle("/uploads/", http.StripPrefix("/uploads/", http.FileServer(http.Dir("./data/uploads"))))
+type DiskStore struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewDiskStore creates a disk store.
+func NewDiskStore(dir, baseURL string) *DiskStore {
+	return &DiskStore{Dir: dir, BaseURL: baseURL}
+}
+
+// Put writes the file to Dir/key.
+func (s *DiskStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(f, io.LimitReader(r, size))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + key, nil
+}
+
+// PresignURL returns ErrPresignNotSupported: files reach the disk through
+// the server.
+func (s *DiskStore) PresignURL(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*PresignedURL, error) {
+	return nil, ErrPresignNotSupported
+}
+
+// Delete removes Dir/key.
+func (s *DiskStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path resolves key under Dir.
+func (s *DiskStore) path(key string) (string, error) {
+	key = filepath.FromSlash(key)
+	if !filepath.IsLocal(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.Dir, key), nil
+}
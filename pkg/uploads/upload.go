@@ -88,6 +88,9 @@ type UploadEntry struct {
 	// URL is the URL where the file is accessible.
 	URL string `json:"url,omitempty"`
 
+	// Key is the storage key of a consumed file.
+	Key string `json:"key,omitempty"`
+
 	// Errors contains any upload errors.
 	Errors []string `json:"errors,omitempty"`
 
@@ -172,6 +175,17 @@ func (u *Upload) RemoveEntry(uuid string) {
 	}
 }
 
+// updateEntry changes an entry under the lock, so readers of the entry do
+// not race with fn.
+func (u *Upload) updateEntry(uuid string, fn func(e *UploadEntry)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if entry, ok := u.Entries[uuid]; ok {
+		fn(entry)
+	}
+}
+
 // UpdateProgress updates the progress of an entry.
 func (u *Upload) UpdateProgress(uuid string, progress int) {
 	u.mu.Lock()
@@ -264,8 +278,12 @@ type ExternalUpload struct {
 // PresignedURL contains information for direct upload.
 type PresignedURL struct {
 	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"` // PUT, or POST for form-based uploads
 	Headers map[string]string `json:"headers"`
 	Fields  map[string]string `json:"fields,omitempty"` // For form-based uploads
+
+	// FileURL is where the file is served once uploaded.
+	FileURL string `json:"-"`
 }
 
 // UploadHandler handles file upload HTTP requests.